@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +18,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"go.codycody31.dev/squad-aegis/internal/audit"
 	"go.codycody31.dev/squad-aegis/internal/ban_enforcer"
 	"go.codycody31.dev/squad-aegis/internal/clickhouse"
 	"go.codycody31.dev/squad-aegis/internal/core"
@@ -25,6 +27,7 @@ import (
 	"go.codycody31.dev/squad-aegis/internal/identity"
 	"go.codycody31.dev/squad-aegis/internal/logwatcher_manager"
 	"go.codycody31.dev/squad-aegis/internal/models"
+	"go.codycody31.dev/squad-aegis/internal/oidc"
 	"go.codycody31.dev/squad-aegis/internal/permissions"
 	"go.codycody31.dev/squad-aegis/internal/player_tracker_manager"
 	"go.codycody31.dev/squad-aegis/internal/plugin_manager"
@@ -36,6 +39,7 @@ import (
 	"go.codycody31.dev/squad-aegis/internal/shared/utils"
 	"go.codycody31.dev/squad-aegis/internal/storage"
 	"go.codycody31.dev/squad-aegis/internal/valkey"
+	"go.codycody31.dev/squad-aegis/internal/webhook_dispatcher"
 	"go.codycody31.dev/squad-aegis/internal/workflow_manager"
 	"golang.org/x/sync/errgroup"
 )
@@ -221,6 +225,33 @@ func run(ctx context.Context) error {
 	// Bans.cfg during startup log replay and plugin initialization.
 	permissionService := permissions.NewService(database)
 	permissionRepo := permissions.NewRepository(database)
+
+	var oidcProvider *oidc.Provider
+	if config.Config.Auth.OIDC.Enabled {
+		oidcProvider = newOIDCProvider()
+		log.Info().Str("issuer", config.Config.Auth.OIDC.IssuerUrl).Msg("SSO login enabled")
+	}
+
+	auditStreamer, err := newAuditStreamer()
+	if err != nil {
+		return fmt.Errorf("failed to configure audit sinks: %w", err)
+	}
+	if auditStreamer != nil {
+		defer auditStreamer.Close()
+	}
+
+	var auditSigner *audit.Signer
+	if config.Config.Audit.CheckpointSigningKey != "" {
+		auditSigner, err = audit.NewSigner(config.Config.Audit.CheckpointSigningKey)
+		if err != nil {
+			return fmt.Errorf("failed to load audit checkpoint signing key: %w", err)
+		}
+		log.Info().Str("key_id", auditSigner.KeyID()).Msg("Audit checkpoint signing enabled")
+	}
+
+	remoteBanSyncService := core.NewRemoteBanSyncService(database, database)
+	remoteBanSyncService.SetEventManager(eventManager)
+
 	deps := &server.Dependencies{
 		DB:                   database,
 		Clickhouse:           clickhouseClient,
@@ -230,10 +261,20 @@ func run(ctx context.Context) error {
 		LogwatcherManager:    logwatcherManager,
 		PluginManager:        pluginManager,
 		WorkflowManager:      workflowManager,
-		RemoteBanSyncService: core.NewRemoteBanSyncService(database, database),
+		RemoteBanSyncService: remoteBanSyncService,
 		PermissionService:    permissionService,
 		PermissionRepo:       permissionRepo,
+		OIDCProvider:         oidcProvider,
+		AuditStreamer:        auditStreamer,
+		AuditSigner:          auditSigner,
+	}
+	webhookDispatcher := webhook_dispatcher.NewDispatcher(ctx, database, eventManager)
+	if err := webhookDispatcher.Start(); err != nil {
+		return fmt.Errorf("failed to start webhook dispatcher: %w", err)
 	}
+	defer webhookDispatcher.Stop()
+	deps.WebhookDispatcher = webhookDispatcher
+
 	appServer := server.New(deps)
 	pluginManager.SetBanSyncFunc(appServer.SyncBansCfgByID)
 	workflowManager.SetBanSyncFunc(appServer.SyncBansCfgByID)
@@ -303,6 +344,31 @@ func run(ctx context.Context) error {
 		}
 	}()
 
+	// Periodically sign the head of every audit chain
+	if auditSigner != nil {
+		go func() {
+			interval := time.Duration(config.Config.Audit.CheckpointIntervalSeconds) * time.Second
+			if interval <= 0 {
+				interval = time.Hour
+			}
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if created, err := core.CreateAuditCheckpoints(ctx, database, auditSigner); err != nil {
+						log.Error().Err(err).Msg("failed to create audit checkpoints")
+					} else if created > 0 {
+						log.Info().Int("created", created).Msg("signed audit checkpoints")
+					}
+				}
+			}
+		}()
+	}
+
 	// Connect to all servers
 	rconManager.ConnectToAllServers(ctx, database)
 	logwatcherManager.ConnectToAllServers(ctx, database)
@@ -322,6 +388,11 @@ func run(ctx context.Context) error {
 
 		// Start remote ban sync service
 		go deps.RemoteBanSyncService.StartPeriodicSync(ctx) // Initialize router
+
+		// Watch managed ServerConfig files for changes made outside Aegis
+		go appServer.StartConfigFileDriftChecks(ctx)
+		go appServer.StartMOTDScheduler(ctx)
+
 		router := server.NewRouter(appServer)
 
 		// Create server with timeout
@@ -407,3 +478,57 @@ func setup(database db.Executor) error {
 
 	return nil
 }
+
+func newOIDCProvider() *oidc.Provider {
+	cfg := config.Config.Auth.OIDC
+
+	redirectURL := cfg.RedirectUrl
+	if redirectURL == "" {
+		redirectURL = strings.TrimRight(config.Config.App.Url, "/") + "/api/auth/oidc/callback"
+	}
+
+	var scopes []string
+	for _, scope := range strings.Split(cfg.Scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return oidc.New(oidc.Config{
+		IssuerURL:     cfg.IssuerUrl,
+		ClientID:      cfg.ClientId,
+		ClientSecret:  cfg.ClientSecret,
+		RedirectURL:   redirectURL,
+		Scopes:        scopes,
+		UsernameClaim: cfg.UsernameClaim,
+		GroupsClaim:   cfg.GroupsClaim,
+		SteamIDClaim:  cfg.SteamIdClaim,
+		HTTPClient:    &http.Client{Timeout: 15 * time.Second},
+	})
+}
+
+// newAuditStreamer builds a streamer for the configured audit sinks, or
+// returns nil when none are configured.
+func newAuditStreamer() (*audit.Streamer, error) {
+	cfg := config.Config.Audit
+
+	var sinks []audit.Sink
+	if cfg.SinkFilePath != "" {
+		sinks = append(sinks, audit.NewFileSink(cfg.SinkFilePath))
+	}
+	if cfg.SinkSyslogAddress != "" {
+		sink, err := audit.NewSyslogSink(cfg.SinkSyslogAddress, cfg.SinkSyslogTag)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	if cfg.SinkWebhookUrl != "" {
+		sinks = append(sinks, audit.NewWebhookSink(cfg.SinkWebhookUrl, cfg.SinkWebhookSecret))
+	}
+
+	if len(sinks) == 0 {
+		return nil, nil
+	}
+	return audit.NewStreamer(sinks...), nil
+}
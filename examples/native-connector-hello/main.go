@@ -5,6 +5,8 @@
 // Package the resulting binary into a connector bundle alongside a
 // manifest.json whose target.library_path points at the binary. The Aegis
 // host will launch it via hashicorp/go-plugin and communicate over net/rpc.
+// The manifest target must declare the api.log and api.event capabilities
+// for the host API calls below to succeed.
 package main
 
 import (
@@ -19,6 +21,7 @@ type helloConnector struct {
 	mu     sync.RWMutex
 	config map[string]interface{}
 	status connectorrpc.ConnectorStatus
+	apis   *connectorrpc.HostAPIs
 }
 
 // definition returns the connector's runtime behavior. Identity (name,
@@ -41,11 +44,21 @@ func (c *helloConnector) GetDefinition() connectorrpc.ConnectorDefinition {
 	return definition()
 }
 
+// SetHostAPIs receives the host APIs before Initialize.
+func (c *helloConnector) SetHostAPIs(apis *connectorrpc.HostAPIs) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.apis = apis
+}
+
 func (c *helloConnector) Initialize(config map[string]interface{}) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.config = config
 	c.status = connectorrpc.ConnectorStatusStopped
+	if c.apis != nil {
+		c.apis.LogAPI.Info("hello connector initialized", nil)
+	}
 	return nil
 }
 
@@ -100,10 +113,27 @@ func (c *helloConnector) Invoke(ctx context.Context, req *connectorrpc.Connector
 		out.Error = "missing data"
 		return out, nil
 	}
-	if req.Data["action"] == "ping" {
+	switch req.Data["action"] {
+	case "ping":
 		out.OK = true
 		out.Data = map[string]interface{}{"message": "pong"}
 		return out, nil
+	case "announce":
+		// Publishes CONNECTOR_<id>_HELLO to plugins on the given server.
+		c.mu.RLock()
+		apis := c.apis
+		c.mu.RUnlock()
+		if apis == nil {
+			out.Error = "host apis are not available"
+			return out, nil
+		}
+		serverID, _ := req.Data["server_id"].(string)
+		if err := apis.EventAPI.PublishEvent(serverID, "HELLO", map[string]interface{}{"message": req.Data["message"]}, ""); err != nil {
+			out.Error = err.Error()
+			return out, nil
+		}
+		out.OK = true
+		return out, nil
 	}
 	out.Error = fmt.Sprintf("unknown action %v", req.Data["action"])
 	return out, nil
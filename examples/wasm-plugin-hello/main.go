@@ -0,0 +1,201 @@
+//go:build wasip1
+
+// WASM plugin example. Build as a WASI reactor module:
+//
+//	GOOS=wasip1 GOARCH=wasm go build -buildmode=c-shared -o hello-plugin.wasm .
+//
+// Package the resulting module into a plugin bundle alongside a manifest.json
+// whose target has target_os "wasip1", target_arch "wasm" and a
+// library_path pointing at the module (e.g. "bin/hello-plugin.wasm"). The
+// Aegis host runs it in an embedded WebAssembly runtime, so the same bundle
+// loads on every host OS and architecture.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	pluginrpc "go.codycody31.dev/squad-aegis/pkg/pluginrpc"
+)
+
+// rconChatMessage is the minimal shape of an RCON chat message event the
+// plugin needs.
+type rconChatMessage struct {
+	SteamID    string `json:"steam_id,omitempty"`
+	EOSID      string `json:"eos_id,omitempty"`
+	PlayerName string `json:"player_name,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+func (r rconChatMessage) preferredPlayerID() string {
+	if strings.TrimSpace(r.EOSID) != "" {
+		return r.EOSID
+	}
+	return r.SteamID
+}
+
+type helloPlugin struct {
+	mu     sync.Mutex
+	config map[string]interface{}
+	apis   *pluginrpc.HostAPIs
+	status pluginrpc.PluginStatus
+}
+
+// definition returns the plugin's runtime behavior. WASM plugins only run
+// while the host calls into them, so they cannot be long running.
+func definition() pluginrpc.PluginDefinition {
+	return pluginrpc.PluginDefinition{
+		PluginID:               "com.squad-aegis.plugins.examples.wasm-hello",
+		AllowMultipleInstances: false,
+		LongRunning:            false,
+		ConfigSchema: pluginrpc.ConfigSchema{
+			Fields: []pluginrpc.ConfigField{
+				{
+					Name:        "trigger",
+					Description: "Chat message that will trigger the response.",
+					Type:        pluginrpc.FieldTypeString,
+					Default:     "!hello",
+				},
+				{
+					Name:        "response",
+					Description: "Private message sent back to the player.",
+					Type:        pluginrpc.FieldTypeString,
+					Default:     "Hello from a WASM Squad Aegis plugin.",
+				},
+			},
+		},
+		Events: []string{"RCON_CHAT_MESSAGE"},
+	}
+}
+
+// Reactor modules never run main, so the plugin is registered from init.
+func init() {
+	pluginrpc.Serve(&helloPlugin{})
+}
+
+func main() {}
+
+func (p *helloPlugin) GetDefinition() pluginrpc.PluginDefinition {
+	return definition()
+}
+
+func (p *helloPlugin) Initialize(config map[string]interface{}, apis *pluginrpc.HostAPIs) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+	p.config = applyHelloDefaults(config)
+	p.apis = apis
+	p.status = pluginrpc.PluginStatusStopped
+	return nil
+}
+
+func applyHelloDefaults(config map[string]interface{}) map[string]interface{} {
+	if _, ok := config["trigger"]; !ok {
+		config["trigger"] = "!hello"
+	}
+	if _, ok := config["response"]; !ok {
+		config["response"] = "Hello from a WASM Squad Aegis plugin."
+	}
+	return config
+}
+
+func (p *helloPlugin) Start(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status = pluginrpc.PluginStatusRunning
+	return nil
+}
+
+func (p *helloPlugin) Stop() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status = pluginrpc.PluginStatusStopped
+	return nil
+}
+
+func (p *helloPlugin) HandleEvent(event *pluginrpc.PluginEvent) error {
+	if event == nil || event.Type != "RCON_CHAT_MESSAGE" {
+		return nil
+	}
+
+	var data rconChatMessage
+	if len(event.Data) > 0 {
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return fmt.Errorf("decode chat event: %w", err)
+		}
+	}
+
+	p.mu.Lock()
+	trigger := strings.TrimSpace(fmt.Sprint(p.config["trigger"]))
+	response := strings.TrimSpace(fmt.Sprint(p.config["response"]))
+	apis := p.apis
+	p.mu.Unlock()
+
+	if trigger == "" || !strings.EqualFold(strings.TrimSpace(data.Message), trigger) {
+		return nil
+	}
+
+	playerID := data.preferredPlayerID()
+	if playerID == "" {
+		return fmt.Errorf("chat event did not include a usable player identifier")
+	}
+
+	if apis != nil && apis.RconAPI != nil {
+		if err := apis.RconAPI.SendWarningToPlayer(playerID, response); err != nil {
+			return fmt.Errorf("failed to respond to player: %w", err)
+		}
+	}
+
+	if apis != nil && apis.LogAPI != nil {
+		apis.LogAPI.Info("Responded to hello command", map[string]interface{}{
+			"player_name": data.PlayerName,
+			"player_id":   playerID,
+		})
+	}
+
+	return nil
+}
+
+func (p *helloPlugin) GetStatus() pluginrpc.PluginStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.status
+}
+
+func (p *helloPlugin) GetConfig() map[string]interface{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cloned := make(map[string]interface{}, len(p.config))
+	for key, value := range p.config {
+		cloned[key] = value
+	}
+	return cloned
+}
+
+func (p *helloPlugin) UpdateConfig(config map[string]interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+	p.config = applyHelloDefaults(config)
+	return nil
+}
+
+func (p *helloPlugin) GetCommands() []pluginrpc.PluginCommand {
+	return nil
+}
+
+func (p *helloPlugin) ExecuteCommand(string, map[string]interface{}) (*pluginrpc.CommandResult, error) {
+	return nil, fmt.Errorf("this plugin does not expose commands")
+}
+
+func (p *helloPlugin) GetCommandExecutionStatus(string) (*pluginrpc.CommandExecutionStatus, error) {
+	return nil, fmt.Errorf("this plugin does not expose commands")
+}
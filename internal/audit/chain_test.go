@@ -0,0 +1,85 @@
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func testEntry() *Entry {
+	actor := uuid.MustParse("5d6c1d2e-8f4c-4a43-9b1e-0c2d9b7f3a10")
+	return &Entry{
+		ID:        uuid.MustParse("2b1f8f0a-6a57-4f53-8d0e-7c0f1b0f6e21"),
+		ChainKey:  GlobalChain,
+		ChainSeq:  3,
+		PrevHash:  "abc123",
+		ActorID:   &actor,
+		Action:    "sudo:user:create",
+		Changes:   json.RawMessage(`{"username": "admin"}`),
+		Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 123456000, time.UTC),
+	}
+}
+
+func TestChainKey(t *testing.T) {
+	if got := ChainKey(nil); got != GlobalChain {
+		t.Fatalf("ChainKey(nil) = %q, want %q", got, GlobalChain)
+	}
+	serverID := uuid.New()
+	if got := ChainKey(&serverID); got != serverID.String() {
+		t.Fatalf("ChainKey(server) = %q, want %q", got, serverID.String())
+	}
+}
+
+func TestComputeHashDeterministic(t *testing.T) {
+	entry := testEntry()
+	hash := ComputeHash(entry)
+	if len(hash) != 64 {
+		t.Fatalf("ComputeHash() = %q, want 64 hex characters", hash)
+	}
+
+	// The same instant in another zone hashes identically.
+	again := testEntry()
+	again.Timestamp = again.Timestamp.In(time.FixedZone("test", 5*60*60))
+	if ComputeHash(again) != hash {
+		t.Fatal("ComputeHash() depends on the timestamp's location")
+	}
+
+	// Fields that may change after the fact are not covered.
+	again.Username = "renamed"
+	again.EntryHash = "ignored"
+	if ComputeHash(again) != hash {
+		t.Fatal("ComputeHash() covers fields outside the hash")
+	}
+}
+
+func TestComputeHashDetectsTampering(t *testing.T) {
+	original := ComputeHash(testEntry())
+	otherActor := uuid.New()
+	apiToken := uuid.New()
+
+	tests := map[string]func(e *Entry){
+		"chain key":   func(e *Entry) { e.ChainKey = uuid.NewString() },
+		"sequence":    func(e *Entry) { e.ChainSeq++ },
+		"prev hash":   func(e *Entry) { e.PrevHash = "def456" },
+		"id":          func(e *Entry) { e.ID = uuid.New() },
+		"actor":       func(e *Entry) { e.ActorID = &otherActor },
+		"no actor":    func(e *Entry) { e.ActorID = nil },
+		"api token":   func(e *Entry) { e.APITokenID = &apiToken },
+		"action":      func(e *Entry) { e.Action = "sudo:user:delete" },
+		"changes":     func(e *Entry) { e.Changes = json.RawMessage(`{"username": "root"}`) },
+		"timestamp":   func(e *Entry) { e.Timestamp = e.Timestamp.Add(time.Microsecond) },
+		"field shift": func(e *Entry) { e.Action, e.Changes = "sudo:user:create{", json.RawMessage(`"username": "admin"}`) },
+	}
+
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			entry := testEntry()
+			mutate(entry)
+			if ComputeHash(entry) == original {
+				t.Fatalf("ComputeHash() did not change after modifying %s", name)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package audit
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidSigningKey = errors.New("audit checkpoint signing key must be a base64 ed25519 seed (32 bytes) or private key (64 bytes)")
+
+// Checkpoint is a signed statement of a chain's head at a point in time.
+// Because the signing key is not stored in the database, a checkpoint lets an
+// operator prove a chain was not rewritten after it was signed, even by
+// someone with write access to the database.
+type Checkpoint struct {
+	ChainKey  string    `json:"chain_key"`
+	ChainSeq  int64     `json:"chain_seq"`
+	EntryHash string    `json:"entry_hash"`
+	SignedAt  time.Time `json:"signed_at"`
+	KeyID     string    `json:"key_id"`
+	PublicKey string    `json:"public_key"`
+	Signature string    `json:"signature"`
+}
+
+func (c *Checkpoint) payload() []byte {
+	return []byte(strings.Join([]string{
+		"squad-aegis-audit-checkpoint/v1",
+		c.ChainKey,
+		strconv.FormatInt(c.ChainSeq, 10),
+		c.EntryHash,
+		c.SignedAt.UTC().Format(TimestampLayout),
+	}, "\n"))
+}
+
+// Signer signs checkpoints with an ed25519 key.
+type Signer struct {
+	key ed25519.PrivateKey
+}
+
+// NewSigner parses a base64 encoded ed25519 seed or private key.
+func NewSigner(encoded string) (*Signer, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, ErrInvalidSigningKey
+	}
+
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return &Signer{key: ed25519.NewKeyFromSeed(raw)}, nil
+	case ed25519.PrivateKeySize:
+		return &Signer{key: ed25519.PrivateKey(raw)}, nil
+	default:
+		return nil, ErrInvalidSigningKey
+	}
+}
+
+// PublicKey returns the base64 encoded public key.
+func (s *Signer) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(s.key.Public().(ed25519.PublicKey))
+}
+
+// KeyID returns a short fingerprint of the public key.
+func (s *Signer) KeyID() string {
+	return keyID(s.key.Public().(ed25519.PublicKey))
+}
+
+// Sign returns a checkpoint for the given chain head.
+func (s *Signer) Sign(chainKey string, chainSeq int64, entryHash string, signedAt time.Time) *Checkpoint {
+	c := &Checkpoint{
+		ChainKey:  chainKey,
+		ChainSeq:  chainSeq,
+		EntryHash: entryHash,
+		SignedAt:  signedAt.UTC().Truncate(time.Microsecond),
+		KeyID:     s.KeyID(),
+		PublicKey: s.PublicKey(),
+	}
+	c.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, c.payload()))
+	return c
+}
+
+// VerifyCheckpoint checks a checkpoint's signature against the public key it
+// carries. Callers decide separately whether that key is trusted.
+func VerifyCheckpoint(c *Checkpoint) error {
+	pub, err := base64.StdEncoding.DecodeString(c.PublicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("checkpoint has an invalid public key")
+	}
+	if keyID(pub) != c.KeyID {
+		return fmt.Errorf("checkpoint key id does not match its public key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(c.Signature)
+	if err != nil {
+		return fmt.Errorf("checkpoint has an invalid signature encoding")
+	}
+	if !ed25519.Verify(pub, c.payload(), sig) {
+		return fmt.Errorf("checkpoint signature is invalid")
+	}
+	return nil
+}
+
+func keyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
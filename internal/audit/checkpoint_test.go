@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testSigner(t *testing.T, seedByte byte) *Signer {
+	t.Helper()
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = seedByte
+	}
+	signer, err := NewSigner(base64.StdEncoding.EncodeToString(seed))
+	if err != nil {
+		t.Fatalf("NewSigner() error = %v", err)
+	}
+	return signer
+}
+
+func TestNewSigner(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	fromSeed, err := NewSigner(base64.StdEncoding.EncodeToString(seed))
+	if err != nil {
+		t.Fatalf("NewSigner(seed) error = %v", err)
+	}
+	fromKey, err := NewSigner(base64.StdEncoding.EncodeToString(ed25519.NewKeyFromSeed(seed)))
+	if err != nil {
+		t.Fatalf("NewSigner(private key) error = %v", err)
+	}
+	if fromSeed.KeyID() != fromKey.KeyID() {
+		t.Fatal("seed and private key forms produced different keys")
+	}
+
+	for _, invalid := range []string{"", "not base64!", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		if _, err := NewSigner(invalid); !errors.Is(err, ErrInvalidSigningKey) {
+			t.Fatalf("NewSigner(%q) error = %v, want ErrInvalidSigningKey", invalid, err)
+		}
+	}
+}
+
+func TestCheckpointSignAndVerify(t *testing.T) {
+	signer := testSigner(t, 1)
+	checkpoint := signer.Sign(GlobalChain, 42, "abc123", time.Now())
+
+	if err := VerifyCheckpoint(checkpoint); err != nil {
+		t.Fatalf("VerifyCheckpoint() error = %v", err)
+	}
+	if checkpoint.KeyID != signer.KeyID() || checkpoint.PublicKey != signer.PublicKey() {
+		t.Fatal("checkpoint does not identify its signer")
+	}
+
+	// A checkpoint survives a round trip through a microsecond-precision store.
+	stored := *checkpoint
+	stored.SignedAt = stored.SignedAt.In(time.Local)
+	if err := VerifyCheckpoint(&stored); err != nil {
+		t.Fatalf("VerifyCheckpoint() after round trip error = %v", err)
+	}
+}
+
+func TestCheckpointVerifyRejectsTampering(t *testing.T) {
+	signer := testSigner(t, 1)
+	other := testSigner(t, 2)
+
+	tests := map[string]func(c *Checkpoint){
+		"chain key":  func(c *Checkpoint) { c.ChainKey = "other" },
+		"sequence":   func(c *Checkpoint) { c.ChainSeq = 41 },
+		"entry hash": func(c *Checkpoint) { c.EntryHash = "def456" },
+		"signed at":  func(c *Checkpoint) { c.SignedAt = c.SignedAt.Add(time.Second) },
+		"key id":     func(c *Checkpoint) { c.KeyID = other.KeyID() },
+		"public key": func(c *Checkpoint) { c.PublicKey = other.PublicKey() },
+		"swapped key": func(c *Checkpoint) {
+			c.PublicKey = other.PublicKey()
+			c.KeyID = other.KeyID()
+		},
+		"signature": func(c *Checkpoint) { c.Signature = "not base64!" },
+	}
+
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			checkpoint := signer.Sign(GlobalChain, 42, "abc123", time.Now())
+			mutate(checkpoint)
+			if err := VerifyCheckpoint(checkpoint); err == nil {
+				t.Fatalf("VerifyCheckpoint() accepted a checkpoint with a modified %s", name)
+			}
+		})
+	}
+}
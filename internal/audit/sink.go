@@ -0,0 +1,231 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Sink receives a copy of every audit entry after it is committed.
+type Sink interface {
+	Name() string
+	Write(ctx context.Context, entry *Entry) error
+}
+
+// FileSink appends entries as JSON lines to a local file.
+type FileSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+func (s *FileSink) Name() string { return "file" }
+
+func (s *FileSink) Write(_ context.Context, entry *Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file: %w", err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// SyslogSink sends RFC 5424 messages over UDP or TCP. The address has the
+// form udp://host:514 or tcp://host:514.
+type SyslogSink struct {
+	network  string
+	address  string
+	tag      string
+	hostname string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func NewSyslogSink(address, tag string) (*SyslogSink, error) {
+	u, err := url.Parse(address)
+	if err != nil || (u.Scheme != "udp" && u.Scheme != "tcp") || u.Host == "" {
+		return nil, fmt.Errorf("syslog address must look like udp://host:514 or tcp://host:514")
+	}
+	if tag == "" {
+		tag = "squad-aegis"
+	}
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+	return &SyslogSink{network: u.Scheme, address: u.Host, tag: tag, hostname: hostname}, nil
+}
+
+func (s *SyslogSink) Name() string { return "syslog" }
+
+func (s *SyslogSink) Write(_ context.Context, entry *Entry) error {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// Facility local0, severity informational.
+	const priority = 16*8 + 6
+	msg := fmt.Sprintf("<%d>1 %s %s %s %d audit - %s",
+		priority, entry.Timestamp.UTC().Format(time.RFC3339Nano), s.hostname, s.tag, os.Getpid(), body)
+	if s.network == "tcp" {
+		// Octet-counting framing (RFC 6587).
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		conn, err := net.DialTimeout(s.network, s.address, 5*time.Second)
+		if err != nil {
+			return fmt.Errorf("failed to connect to syslog: %w", err)
+		}
+		s.conn = conn
+	}
+
+	_ = s.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	if _, err := s.conn.Write([]byte(msg)); err != nil {
+		// Reconnect on the next entry.
+		_ = s.conn.Close()
+		s.conn = nil
+		return fmt.Errorf("failed to write to syslog: %w", err)
+	}
+	return nil
+}
+
+// WebhookSink POSTs each entry as JSON. When a secret is configured the body
+// is signed with HMAC-SHA256 in the X-Aegis-Signature header.
+type WebhookSink struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewWebhookSink(url, secret string) *WebhookSink {
+	return &WebhookSink{url: url, secret: secret, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *WebhookSink) Name() string { return "webhook" }
+
+func (s *WebhookSink) Write(ctx context.Context, entry *Entry) error {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.secret != "" {
+		mac := hmac.New(sha256.New, []byte(s.secret))
+		mac.Write(body)
+		req.Header.Set("X-Aegis-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("audit webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("audit webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Streamer fans entries out to sinks in the background so a slow or
+// unreachable sink never delays the request that produced the entry. Entries
+// are dropped, with a warning, when the buffer is full; the database remains
+// the source of truth.
+type Streamer struct {
+	sinks   []Sink
+	entries chan *Entry
+	done    chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func NewStreamer(sinks ...Sink) *Streamer {
+	s := &Streamer{
+		sinks:   sinks,
+		entries: make(chan *Entry, 1024),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Publish queues an entry for delivery. Entries published after Close are
+// dropped.
+func (s *Streamer) Publish(entry *Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		log.Warn().Str("entry_id", entry.ID.String()).Msg("Audit streamer closed, dropping entry")
+		return
+	}
+	select {
+	case s.entries <- entry:
+	default:
+		log.Warn().Str("entry_id", entry.ID.String()).Msg("Audit sink buffer full, dropping entry")
+	}
+}
+
+// Close stops accepting entries and waits for queued ones to be delivered.
+func (s *Streamer) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		<-s.done
+		return
+	}
+	s.closed = true
+	close(s.entries)
+	s.mu.Unlock()
+	<-s.done
+}
+
+func (s *Streamer) run() {
+	defer close(s.done)
+
+	for entry := range s.entries {
+		for _, sink := range s.sinks {
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			if err := sink.Write(ctx, entry); err != nil {
+				log.Warn().Err(err).Str("sink", sink.Name()).Str("entry_id", entry.ID.String()).Msg("Failed to stream audit entry")
+			}
+			cancel()
+		}
+	}
+}
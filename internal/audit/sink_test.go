@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSinkAppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink := NewFileSink(path)
+
+	first := testEntry()
+	second := testEntry()
+	second.ChainSeq++
+	for _, entry := range []*Entry{first, second} {
+		if err := sink.Write(context.Background(), entry); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open sink file: %v", err)
+	}
+	defer f.Close()
+
+	var seqs []int64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("line is not a JSON entry: %v", err)
+		}
+		seqs = append(seqs, entry.ChainSeq)
+	}
+	if len(seqs) != 2 || seqs[0] != first.ChainSeq || seqs[1] != second.ChainSeq {
+		t.Fatalf("sink file sequences = %v, want [%d %d]", seqs, first.ChainSeq, second.ChainSeq)
+	}
+}
+
+func TestWebhookSinkSignsBody(t *testing.T) {
+	const secret = "s3cret"
+	received := make(chan bool, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		received <- r.Header.Get("X-Aegis-Signature") == "sha256="+hex.EncodeToString(mac.Sum(nil))
+	}))
+	defer srv.Close()
+
+	if err := NewWebhookSink(srv.URL, secret).Write(context.Background(), testEntry()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !<-received {
+		t.Fatal("webhook signature does not match the body")
+	}
+}
+
+func TestWebhookSinkReportsFailureStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	if err := NewWebhookSink(srv.URL, "").Write(context.Background(), testEntry()); err == nil {
+		t.Fatal("Write() succeeded against a failing webhook")
+	}
+}
+
+func TestNewSyslogSinkValidatesAddress(t *testing.T) {
+	for _, address := range []string{"udp://127.0.0.1:514", "tcp://logs.example.com:6514"} {
+		if _, err := NewSyslogSink(address, ""); err != nil {
+			t.Fatalf("NewSyslogSink(%q) error = %v", address, err)
+		}
+	}
+	for _, address := range []string{"", "127.0.0.1:514", "http://logs.example.com"} {
+		if _, err := NewSyslogSink(address, ""); err == nil {
+			t.Fatalf("NewSyslogSink(%q) accepted an invalid address", address)
+		}
+	}
+}
+
+func TestStreamerDropsEntriesAfterClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	streamer := NewStreamer(NewFileSink(path))
+
+	streamer.Publish(testEntry())
+	streamer.Close()
+	streamer.Publish(testEntry())
+	streamer.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read sink file: %v", err)
+	}
+	if lines := bytes.Count(data, []byte("\n")); lines != 1 {
+		t.Fatalf("sink file has %d entries, want only the one published before Close", lines)
+	}
+}
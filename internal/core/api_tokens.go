@@ -0,0 +1,180 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.codycody31.dev/squad-aegis/internal/db"
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+var (
+	ErrAPITokenInvalid  = errors.New("api token is invalid, expired or revoked")
+	ErrAPITokenNotFound = errors.New("api token not found")
+)
+
+const (
+	// APITokenPrefix marks a bearer token as a personal API token rather
+	// than a session token, so the auth middleware knows where to look it up.
+	APITokenPrefix = "sat_"
+	// APITokenMaxLifetime caps how far in the future a token may expire.
+	APITokenMaxLifetime = 365 * 24 * time.Hour
+	// apiTokenDisplayLength is how much of the token is kept as a hint.
+	apiTokenDisplayLength = len(APITokenPrefix) + 6
+)
+
+// GenerateAPIToken returns a new random token string.
+func GenerateAPIToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate api token: %w", err)
+	}
+	return APITokenPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// HashAPIToken returns the value stored for a token. Tokens carry 256 bits of
+// entropy, so a plain SHA-256 is sufficient and keeps lookups indexable.
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIToken reports whether a bearer value looks like a personal API token.
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, APITokenPrefix)
+}
+
+// CreateAPIToken stores a new token for token.UserId and returns the secret,
+// which is only ever shown to the user once.
+func CreateAPIToken(ctx context.Context, database db.Executor, token *models.APIToken) (string, error) {
+	secret, err := GenerateAPIToken()
+	if err != nil {
+		return "", err
+	}
+
+	token.Id = uuid.New()
+	token.TokenPrefix = secret[:apiTokenDisplayLength]
+
+	err = database.QueryRowContext(ctx, `
+		INSERT INTO api_tokens (id, user_id, name, token_hash, token_prefix, server_ids, permissions, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6::uuid[], $7, $8)
+		RETURNING created_at
+	`, token.Id, token.UserId, token.Name, HashAPIToken(secret), token.TokenPrefix,
+		pq.Array(uuidStrings(token.ServerIds)), pq.Array(token.Permissions), token.ExpiresAt).Scan(&token.CreatedAt)
+	if err != nil {
+		return "", fmt.Errorf("failed to create api token: %w", err)
+	}
+
+	return secret, nil
+}
+
+const apiTokenColumns = `id, user_id, name, token_prefix, server_ids::text[], permissions, expires_at, last_used_at, last_used_ip, revoked_at, created_at`
+
+func scanAPIToken(scanner interface{ Scan(...any) error }) (*models.APIToken, error) {
+	var token models.APIToken
+	var serverIds []string
+	err := scanner.Scan(&token.Id, &token.UserId, &token.Name, &token.TokenPrefix, pq.Array(&serverIds), pq.Array(&token.Permissions),
+		&token.ExpiresAt, &token.LastUsedAt, &token.LastUsedIp, &token.RevokedAt, &token.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	token.ServerIds = make([]uuid.UUID, 0, len(serverIds))
+	for _, id := range serverIds {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server id in api token scope: %w", err)
+		}
+		token.ServerIds = append(token.ServerIds, parsed)
+	}
+	return &token, nil
+}
+
+// AuthenticateAPIToken resolves a token secret to its active token record.
+func AuthenticateAPIToken(ctx context.Context, database db.Executor, secret string) (*models.APIToken, error) {
+	row := database.QueryRowContext(ctx, `
+		SELECT `+apiTokenColumns+`
+		FROM api_tokens
+		WHERE token_hash = $1 AND revoked_at IS NULL AND expires_at > NOW()
+	`, HashAPIToken(secret))
+
+	token, err := scanAPIToken(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAPITokenInvalid
+		}
+		return nil, fmt.Errorf("failed to look up api token: %w", err)
+	}
+	return token, nil
+}
+
+// TouchAPIToken records that a token was just used.
+func TouchAPIToken(ctx context.Context, database db.Executor, tokenId uuid.UUID, clientIp string) error {
+	_, err := database.ExecContext(ctx, "UPDATE api_tokens SET last_used_at = NOW(), last_used_ip = $1 WHERE id = $2", clientIp, tokenId)
+	return err
+}
+
+// ListAPITokens returns a user's tokens, or every token when userId is nil.
+func ListAPITokens(ctx context.Context, database db.Executor, userId *uuid.UUID) ([]*models.APIToken, error) {
+	query := `SELECT ` + apiTokenColumns + ` FROM api_tokens`
+	args := []any{}
+	if userId != nil {
+		query += ` WHERE user_id = $1`
+		args = append(args, *userId)
+	}
+	query += ` ORDER BY created_at DESC`
+
+	rows, err := database.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []*models.APIToken{}
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+// RevokeAPIToken revokes a token. When ownerId is set the token must belong
+// to that user; super admins pass nil to revoke any token.
+func RevokeAPIToken(ctx context.Context, database db.Executor, tokenId uuid.UUID, ownerId *uuid.UUID, revokedBy uuid.UUID) error {
+	query := `UPDATE api_tokens SET revoked_at = NOW(), revoked_by = $2 WHERE id = $1 AND revoked_at IS NULL`
+	args := []any{tokenId, revokedBy}
+	if ownerId != nil {
+		query += ` AND user_id = $3`
+		args = append(args, *ownerId)
+	}
+
+	result, err := database.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api token: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrAPITokenNotFound
+	}
+	return nil
+}
+
+func uuidStrings(ids []uuid.UUID) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id.String()
+	}
+	return out
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateAPIToken(t *testing.T) {
+	first, err := GenerateAPIToken()
+	if err != nil {
+		t.Fatalf("GenerateAPIToken() error = %v", err)
+	}
+	second, err := GenerateAPIToken()
+	if err != nil {
+		t.Fatalf("GenerateAPIToken() error = %v", err)
+	}
+
+	if !IsAPIToken(first) {
+		t.Fatalf("GenerateAPIToken() = %q, want %q prefix", first, APITokenPrefix)
+	}
+	if first == second {
+		t.Fatal("GenerateAPIToken() returned the same token twice")
+	}
+	if len(first) != len(APITokenPrefix)+43 {
+		t.Fatalf("len(GenerateAPIToken()) = %d, want %d", len(first), len(APITokenPrefix)+43)
+	}
+}
+
+func TestHashAPIToken(t *testing.T) {
+	hash := HashAPIToken("sat_example")
+	if hash != HashAPIToken("sat_example") {
+		t.Fatal("HashAPIToken() is not deterministic")
+	}
+	if hash == HashAPIToken("sat_example2") {
+		t.Fatal("HashAPIToken() collided for different tokens")
+	}
+	if strings.Contains(hash, "sat_") || len(hash) != 64 {
+		t.Fatalf("HashAPIToken() = %q, want 64 hex characters", hash)
+	}
+}
+
+func TestIsAPIToken(t *testing.T) {
+	if IsAPIToken("0b7a3c1e-0000-4000-8000-000000000000") {
+		t.Fatal("IsAPIToken() accepted a session token")
+	}
+	if IsAPIToken("") {
+		t.Fatal("IsAPIToken() accepted an empty token")
+	}
+}
@@ -0,0 +1,308 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/audit"
+	"go.codycody31.dev/squad-aegis/internal/db"
+)
+
+// maxAuditChainProblems bounds the size of a verification report.
+const maxAuditChainProblems = 100
+
+// AuditChainProblem describes one inconsistency found while verifying a chain.
+type AuditChainProblem struct {
+	ChainSeq int64      `json:"chain_seq"`
+	EntryId  *uuid.UUID `json:"entry_id,omitempty"`
+	Reason   string     `json:"reason"`
+}
+
+// AuditChainReport is the outcome of verifying one audit chain.
+type AuditChainReport struct {
+	ChainKey           string              `json:"chain_key"`
+	Valid              bool                `json:"valid"`
+	EntriesChecked     int64               `json:"entries_checked"`
+	HeadSeq            int64               `json:"head_seq"`
+	HeadHash           string              `json:"head_hash"`
+	UnchainedEntries   int64               `json:"unchained_entries"`
+	Checkpoints        int                 `json:"checkpoints"`
+	TrustedCheckpoints int                 `json:"trusted_checkpoints"`
+	Problems           []AuditChainProblem `json:"problems"`
+}
+
+func (r *AuditChainReport) addProblem(seq int64, id *uuid.UUID, format string, args ...any) {
+	r.Valid = false
+	if len(r.Problems) < maxAuditChainProblems {
+		r.Problems = append(r.Problems, AuditChainProblem{ChainSeq: seq, EntryId: id, Reason: fmt.Sprintf(format, args...)})
+	}
+}
+
+// AppendAuditLog writes entry at the head of its chain. Appends to the same
+// chain are serialized with an advisory lock, and the hash is computed over
+// the changes and timestamp exactly as PostgreSQL stored them so that
+// verification reproduces it byte for byte.
+func AppendAuditLog(ctx context.Context, database *sql.DB, entry *audit.Entry) error {
+	tx, err := database.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	entry.ID = uuid.New()
+	entry.ChainKey = audit.ChainKey(entry.ServerID)
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext('audit_chain:' || $1))", entry.ChainKey); err != nil {
+		return fmt.Errorf("failed to lock audit chain: %w", err)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT chain_seq, entry_hash FROM audit_logs
+		WHERE chain_key = $1 AND chain_seq IS NOT NULL
+		ORDER BY chain_seq DESC
+		LIMIT 1
+	`, entry.ChainKey).Scan(&entry.ChainSeq, &entry.PrevHash)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to read audit chain head: %w", err)
+	}
+	entry.ChainSeq++
+
+	var storedChanges string
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO audit_logs (id, server_id, user_id, actor_id, action, changes, timestamp, username_snapshot, api_token_id, chain_key, chain_seq, prev_hash)
+		VALUES ($1, $2, $3, $3, $4, $5, $6, (SELECT username FROM users WHERE id = $3), $7, $8, $9, $10)
+		RETURNING COALESCE(changes::text, ''), timestamp, COALESCE(username_snapshot, '')
+	`, entry.ID, entry.ServerID, entry.ActorID, entry.Action, []byte(entry.Changes), entry.Timestamp, entry.APITokenID,
+		entry.ChainKey, entry.ChainSeq, entry.PrevHash).Scan(&storedChanges, &entry.Timestamp, &entry.Username)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit log: %w", err)
+	}
+	entry.Changes = json.RawMessage(storedChanges)
+	entry.EntryHash = audit.ComputeHash(entry)
+
+	if _, err := tx.ExecContext(ctx, "UPDATE audit_logs SET entry_hash = $1 WHERE id = $2", entry.EntryHash, entry.ID); err != nil {
+		return fmt.Errorf("failed to store audit log hash: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// ListAuditChains returns the key of every chain that has chained entries.
+func ListAuditChains(ctx context.Context, database db.Executor) ([]string, error) {
+	rows, err := database.QueryContext(ctx, "SELECT DISTINCT chain_key FROM audit_logs WHERE chain_key IS NOT NULL ORDER BY chain_key")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit chains: %w", err)
+	}
+	defer rows.Close()
+
+	var chains []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		chains = append(chains, key)
+	}
+	return chains, rows.Err()
+}
+
+// VerifyAuditChain walks a chain from its first entry, recomputing every hash
+// and checking the links between entries, then checks the chain against its
+// signed checkpoints. trustedKeyId is the key id of the configured checkpoint
+// signer, or empty if none is configured.
+func VerifyAuditChain(ctx context.Context, database db.Executor, chainKey string, trustedKeyId string) (*AuditChainReport, error) {
+	report := &AuditChainReport{ChainKey: chainKey, Valid: true, Problems: []AuditChainProblem{}}
+
+	checkpoints, err := ListAuditCheckpoints(ctx, database, chainKey, 0)
+	if err != nil {
+		return nil, err
+	}
+	checkpointsBySeq := map[int64][]*audit.Checkpoint{}
+	for _, cp := range checkpoints {
+		checkpointsBySeq[cp.ChainSeq] = append(checkpointsBySeq[cp.ChainSeq], cp)
+	}
+	hashesAtCheckpoints := map[int64]string{}
+
+	rows, err := database.QueryContext(ctx, `
+		SELECT id, chain_seq, COALESCE(prev_hash, ''), COALESCE(entry_hash, ''), actor_id, user_id, api_token_id, action, COALESCE(changes::text, ''), timestamp
+		FROM audit_logs
+		WHERE chain_key = $1 AND chain_seq IS NOT NULL
+		ORDER BY chain_seq
+	`, chainKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit chain: %w", err)
+	}
+	defer rows.Close()
+
+	expectedSeq := int64(1)
+	prevHash := ""
+	for rows.Next() {
+		var entry audit.Entry
+		var userId *uuid.UUID
+		var changes string
+		if err := rows.Scan(&entry.ID, &entry.ChainSeq, &entry.PrevHash, &entry.EntryHash, &entry.ActorID, &userId, &entry.APITokenID, &entry.Action, &changes, &entry.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entry.ChainKey = chainKey
+		entry.Changes = json.RawMessage(changes)
+		id := entry.ID
+
+		if entry.ChainSeq != expectedSeq {
+			report.addProblem(entry.ChainSeq, &id, "entries %d to %d are missing", expectedSeq, entry.ChainSeq-1)
+		}
+		if entry.PrevHash != prevHash {
+			report.addProblem(entry.ChainSeq, &id, "previous hash does not match the preceding entry")
+		}
+		if audit.ComputeHash(&entry) != entry.EntryHash {
+			report.addProblem(entry.ChainSeq, &id, "entry hash does not match its contents")
+		}
+		if userId != nil && (entry.ActorID == nil || *userId != *entry.ActorID) {
+			report.addProblem(entry.ChainSeq, &id, "user does not match the recorded actor")
+		}
+
+		if _, ok := checkpointsBySeq[entry.ChainSeq]; ok {
+			hashesAtCheckpoints[entry.ChainSeq] = entry.EntryHash
+		}
+
+		report.EntriesChecked++
+		report.HeadSeq = entry.ChainSeq
+		report.HeadHash = entry.EntryHash
+		prevHash = entry.EntryHash
+		expectedSeq = entry.ChainSeq + 1
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, cp := range checkpoints {
+		report.Checkpoints++
+		if err := audit.VerifyCheckpoint(cp); err != nil {
+			report.addProblem(cp.ChainSeq, nil, "checkpoint signed at %s: %v", cp.SignedAt.Format(time.RFC3339), err)
+			continue
+		}
+		if trustedKeyId != "" {
+			if cp.KeyID == trustedKeyId {
+				report.TrustedCheckpoints++
+			} else {
+				report.addProblem(cp.ChainSeq, nil, "checkpoint signed at %s by untrusted key %s", cp.SignedAt.Format(time.RFC3339), cp.KeyID)
+			}
+		}
+
+		hash, ok := hashesAtCheckpoints[cp.ChainSeq]
+		switch {
+		case !ok && cp.ChainSeq > report.HeadSeq:
+			report.addProblem(cp.ChainSeq, nil, "checkpoint signed at %s covers entries beyond the chain head; entries were removed", cp.SignedAt.Format(time.RFC3339))
+		case !ok:
+			report.addProblem(cp.ChainSeq, nil, "checkpointed entry is missing")
+		case hash != cp.EntryHash:
+			report.addProblem(cp.ChainSeq, nil, "entry hash differs from the checkpoint signed at %s", cp.SignedAt.Format(time.RFC3339))
+		}
+	}
+	if trustedKeyId != "" && report.EntriesChecked > 0 && report.TrustedCheckpoints == 0 {
+		report.addProblem(report.HeadSeq, nil, "no checkpoint signed by the trusted key %s covers the chain", trustedKeyId)
+	}
+
+	err = database.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM audit_logs
+		WHERE chain_seq IS NULL AND COALESCE(server_id::text, $2) = $1
+	`, chainKey, audit.GlobalChain).Scan(&report.UnchainedEntries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unchained audit entries: %w", err)
+	}
+
+	return report, nil
+}
+
+// CreateAuditCheckpoints signs the head of every chain that has advanced since
+// its last checkpoint by this signer. It returns the number of checkpoints
+// written.
+func CreateAuditCheckpoints(ctx context.Context, database db.Executor, signer *audit.Signer) (int, error) {
+	rows, err := database.QueryContext(ctx, `
+		SELECT DISTINCT ON (al.chain_key) al.chain_key, al.chain_seq, al.entry_hash
+		FROM audit_logs al
+		WHERE al.chain_seq IS NOT NULL AND al.entry_hash IS NOT NULL
+		  AND al.chain_seq > COALESCE((
+			SELECT MAX(cp.chain_seq) FROM audit_checkpoints cp
+			WHERE cp.chain_key = al.chain_key AND cp.key_id = $1
+		  ), 0)
+		ORDER BY al.chain_key, al.chain_seq DESC
+	`, signer.KeyID())
+	if err != nil {
+		return 0, fmt.Errorf("failed to read audit chain heads: %w", err)
+	}
+
+	var pending []*audit.Checkpoint
+	now := time.Now()
+	for rows.Next() {
+		var chainKey, entryHash string
+		var chainSeq int64
+		if err := rows.Scan(&chainKey, &chainSeq, &entryHash); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		pending = append(pending, signer.Sign(chainKey, chainSeq, entryHash, now))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, cp := range pending {
+		_, err := database.ExecContext(ctx, `
+			INSERT INTO audit_checkpoints (chain_key, chain_seq, entry_hash, signed_at, key_id, public_key, signature)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, cp.ChainKey, cp.ChainSeq, cp.EntryHash, cp.SignedAt, cp.KeyID, cp.PublicKey, cp.Signature)
+		if err != nil {
+			return 0, fmt.Errorf("failed to store audit checkpoint: %w", err)
+		}
+	}
+
+	return len(pending), nil
+}
+
+// ListAuditCheckpoints returns a chain's checkpoints, oldest first. A limit
+// of zero returns all of them.
+func ListAuditCheckpoints(ctx context.Context, database db.Executor, chainKey string, limit int) ([]*audit.Checkpoint, error) {
+	query := `
+		SELECT chain_key, chain_seq, entry_hash, signed_at, key_id, public_key, signature
+		FROM audit_checkpoints
+		WHERE chain_key = $1
+		ORDER BY chain_seq, signed_at
+	`
+	args := []any{chainKey}
+	if limit > 0 {
+		// Most recent checkpoints, still returned oldest first.
+		query = `
+		SELECT * FROM (
+			SELECT chain_key, chain_seq, entry_hash, signed_at, key_id, public_key, signature
+			FROM audit_checkpoints
+			WHERE chain_key = $1
+			ORDER BY chain_seq DESC, signed_at DESC
+			LIMIT $2
+		) recent
+		ORDER BY chain_seq, signed_at
+	`
+		args = append(args, limit)
+	}
+
+	rows, err := database.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit checkpoints: %w", err)
+	}
+	defer rows.Close()
+
+	checkpoints := []*audit.Checkpoint{}
+	for rows.Next() {
+		var cp audit.Checkpoint
+		if err := rows.Scan(&cp.ChainKey, &cp.ChainSeq, &cp.EntryHash, &cp.SignedAt, &cp.KeyID, &cp.PublicKey, &cp.Signature); err != nil {
+			return nil, fmt.Errorf("failed to scan audit checkpoint: %w", err)
+		}
+		checkpoints = append(checkpoints, &cp)
+	}
+	return checkpoints, rows.Err()
+}
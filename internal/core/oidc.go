@@ -0,0 +1,338 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.codycody31.dev/squad-aegis/internal/db"
+	"go.codycody31.dev/squad-aegis/internal/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrOIDCStateInvalid        = errors.New("sso login request is invalid or expired")
+	ErrOIDCProvisioningBlocked = errors.New("no account is linked to this identity and automatic provisioning is disabled")
+	ErrOIDCMappingNotFound     = errors.New("group mapping not found")
+)
+
+// OIDCStateTTL bounds how long the browser may spend at the IdP before the
+// callback is rejected.
+const OIDCStateTTL = 10 * time.Minute
+
+var usernameInvalidChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// CreateOIDCState records a pending authorization request.
+func CreateOIDCState(ctx context.Context, database db.Executor, state, nonce, codeVerifier string) error {
+	// Opportunistically drop abandoned requests.
+	if _, err := database.ExecContext(ctx, "DELETE FROM auth_oidc_states WHERE expires_at < NOW()"); err != nil {
+		return fmt.Errorf("failed to clean up sso states: %w", err)
+	}
+
+	_, err := database.ExecContext(ctx, `
+		INSERT INTO auth_oidc_states (state, nonce, code_verifier, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`, state, nonce, codeVerifier, time.Now().Add(OIDCStateTTL))
+	if err != nil {
+		return fmt.Errorf("failed to store sso state: %w", err)
+	}
+	return nil
+}
+
+// ConsumeOIDCState deletes a pending authorization request and returns its
+// nonce and PKCE verifier. Each state can be used once.
+func ConsumeOIDCState(ctx context.Context, database db.Executor, state string) (nonce string, codeVerifier string, err error) {
+	var valid bool
+	err = database.QueryRowContext(ctx, `
+		DELETE FROM auth_oidc_states
+		WHERE state = $1
+		RETURNING nonce, code_verifier, expires_at > NOW()
+	`, state).Scan(&nonce, &codeVerifier, &valid)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", ErrOIDCStateInvalid
+		}
+		return "", "", fmt.Errorf("failed to consume sso state: %w", err)
+	}
+	if !valid {
+		return "", "", ErrOIDCStateInvalid
+	}
+	return nonce, codeVerifier, nil
+}
+
+// GetUserByExternalIdentity returns the local user linked to an IdP subject.
+func GetUserByExternalIdentity(ctx context.Context, database db.Executor, provider, subject string) (*models.User, error) {
+	var userId uuid.UUID
+	err := database.QueryRowContext(ctx, `
+		SELECT user_id FROM user_identities WHERE provider = $1 AND subject = $2
+	`, provider, subject).Scan(&userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrorUserNotFound
+		}
+		return nil, fmt.Errorf("failed to look up external identity: %w", err)
+	}
+
+	return GetUserById(ctx, database, userId, nil)
+}
+
+// ResolveExternalUser returns the local user for an SSO login, creating one
+// when autoProvision is set. Accounts are only ever matched through a prior
+// identity link, never by username, so an IdP user cannot take over an
+// existing local account that happens to share a name.
+func ResolveExternalUser(ctx context.Context, database db.Executor, identity *models.ExternalIdentity, autoProvision bool) (*models.User, bool, error) {
+	user, err := GetUserByExternalIdentity(ctx, database, identity.Provider, identity.Subject)
+	created := false
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrorUserNotFound):
+		if !autoProvision {
+			return nil, false, ErrOIDCProvisioningBlocked
+		}
+		user, err = provisionExternalUser(ctx, database, identity)
+		if err != nil {
+			return nil, false, err
+		}
+		created = true
+	default:
+		return nil, false, err
+	}
+
+	_, err = database.ExecContext(ctx, `
+		UPDATE user_identities SET email = $1, last_login_at = NOW()
+		WHERE provider = $2 AND subject = $3
+	`, identity.Email, identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to update external identity: %w", err)
+	}
+
+	// The IdP is authoritative for the Steam ID when it supplies one.
+	if identity.SteamId != 0 && identity.SteamId != user.SteamId {
+		if _, err := database.ExecContext(ctx, "UPDATE users SET steam_id = $1, updated_at = $2 WHERE id = $3", identity.SteamId, time.Now(), user.Id); err != nil {
+			return nil, false, fmt.Errorf("failed to update steam id: %w", err)
+		}
+		user.SteamId = identity.SteamId
+	}
+
+	return user, created, nil
+}
+
+func provisionExternalUser(ctx context.Context, database db.Executor, identity *models.ExternalIdentity) (*models.User, error) {
+	username, err := availableUsername(ctx, database, usernameCandidate(identity))
+	if err != nil {
+		return nil, err
+	}
+
+	name := identity.Name
+	if name == "" {
+		name = username
+	}
+
+	// SSO users never sign in with a password; store a hash of random bytes
+	// nobody knows so the password column stays populated.
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate password: %w", err)
+	}
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(secret)), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	userId := uuid.New()
+	_, err = database.ExecContext(ctx, `
+		INSERT INTO users (id, steam_id, name, username, password, super_admin, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, false, $6, $6)
+	`, userId, identity.SteamId, name, username, string(passwordHash), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	_, err = database.ExecContext(ctx, `
+		INSERT INTO user_identities (user_id, provider, subject, email)
+		VALUES ($1, $2, $3, $4)
+	`, userId, identity.Provider, identity.Subject, identity.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to link external identity: %w", err)
+	}
+
+	return GetUserById(ctx, database, userId, nil)
+}
+
+// usernameCandidate derives a local username from the IdP claims, falling
+// back through the email local part to the subject.
+func usernameCandidate(identity *models.ExternalIdentity) string {
+	candidates := []string{identity.Username}
+	if at := strings.Index(identity.Email, "@"); at > 0 {
+		candidates = append(candidates, identity.Email[:at])
+	}
+	candidates = append(candidates, "sso_"+identity.Subject)
+
+	for _, candidate := range candidates {
+		cleaned := usernameInvalidChars.ReplaceAllString(strings.ToLower(candidate), "_")
+		cleaned = strings.Trim(cleaned, "_")
+		if len(cleaned) > 32 {
+			cleaned = cleaned[:32]
+		}
+		if cleaned != "" {
+			return cleaned
+		}
+	}
+	return "sso_user"
+}
+
+// availableUsername appends a numeric suffix until the name is free.
+func availableUsername(ctx context.Context, database db.Executor, base string) (string, error) {
+	for i := 0; i < 100; i++ {
+		candidate := base
+		if i > 0 {
+			suffix := fmt.Sprintf("_%d", i)
+			if len(base)+len(suffix) > 32 {
+				candidate = base[:32-len(suffix)] + suffix
+			} else {
+				candidate = base + suffix
+			}
+		}
+
+		var exists bool
+		if err := database.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)", candidate).Scan(&exists); err != nil {
+			return "", fmt.Errorf("failed to check username: %w", err)
+		}
+		if !exists {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("could not find a free username for %q", base)
+}
+
+// SyncOIDCGroupMappings replaces the user's SSO-managed server admin rows with
+// the roles granted by their current groups. Super admin is only changed when
+// at least one super admin mapping exists, so deployments that manage super
+// admins by hand are unaffected. It returns the servers whose admin rows were
+// touched so callers can invalidate cached permissions.
+func SyncOIDCGroupMappings(ctx context.Context, database db.Executor, userId uuid.UUID, groups []string) ([]uuid.UUID, error) {
+	affected := map[uuid.UUID]struct{}{}
+
+	rows, err := database.QueryContext(ctx, `
+		DELETE FROM server_admins WHERE user_id = $1 AND managed_by_oidc = true
+		RETURNING server_id
+	`, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clear sso managed roles: %w", err)
+	}
+	for rows.Next() {
+		var serverId uuid.UUID
+		if err := rows.Scan(&serverId); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		affected[serverId] = struct{}{}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if groups == nil {
+		groups = []string{}
+	}
+
+	rows, err = database.QueryContext(ctx, `
+		INSERT INTO server_admins (id, server_id, user_id, server_role_id, notes, created_at, managed_by_oidc)
+		SELECT gen_random_uuid(), m.server_id, $1, m.server_role_id, 'Managed by SSO group mapping', NOW(), true
+		FROM (
+			SELECT DISTINCT server_id, server_role_id
+			FROM oidc_group_mappings
+			WHERE NOT super_admin AND group_name = ANY($2)
+		) m
+		RETURNING server_id
+	`, userId, pq.Array(groups))
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply sso group mappings: %w", err)
+	}
+	for rows.Next() {
+		var serverId uuid.UUID
+		if err := rows.Scan(&serverId); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		affected[serverId] = struct{}{}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	_, err = database.ExecContext(ctx, `
+		UPDATE users
+		SET super_admin = EXISTS(
+			SELECT 1 FROM oidc_group_mappings WHERE super_admin AND group_name = ANY($2)
+		), updated_at = NOW()
+		WHERE id = $1
+		  AND EXISTS(SELECT 1 FROM oidc_group_mappings WHERE super_admin)
+	`, userId, pq.Array(groups))
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply sso super admin mapping: %w", err)
+	}
+
+	serverIds := make([]uuid.UUID, 0, len(affected))
+	for serverId := range affected {
+		serverIds = append(serverIds, serverId)
+	}
+	return serverIds, nil
+}
+
+func ListOIDCGroupMappings(ctx context.Context, database db.Executor) ([]models.OIDCGroupMapping, error) {
+	rows, err := database.QueryContext(ctx, `
+		SELECT id, group_name, server_id, server_role_id, super_admin, created_at
+		FROM oidc_group_mappings
+		ORDER BY group_name, created_at
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list group mappings: %w", err)
+	}
+	defer rows.Close()
+
+	mappings := []models.OIDCGroupMapping{}
+	for rows.Next() {
+		var m models.OIDCGroupMapping
+		if err := rows.Scan(&m.Id, &m.GroupName, &m.ServerId, &m.ServerRoleId, &m.SuperAdmin, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan group mapping: %w", err)
+		}
+		mappings = append(mappings, m)
+	}
+	return mappings, rows.Err()
+}
+
+func CreateOIDCGroupMapping(ctx context.Context, database db.Executor, mapping *models.OIDCGroupMapping) error {
+	mapping.Id = uuid.New()
+	err := database.QueryRowContext(ctx, `
+		INSERT INTO oidc_group_mappings (id, group_name, server_id, server_role_id, super_admin)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`, mapping.Id, mapping.GroupName, mapping.ServerId, mapping.ServerRoleId, mapping.SuperAdmin).Scan(&mapping.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create group mapping: %w", err)
+	}
+	return nil
+}
+
+func DeleteOIDCGroupMapping(ctx context.Context, database db.Executor, id uuid.UUID) error {
+	result, err := database.ExecContext(ctx, "DELETE FROM oidc_group_mappings WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete group mapping: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrOIDCMappingNotFound
+	}
+	return nil
+}
@@ -15,13 +15,15 @@ import (
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"go.codycody31.dev/squad-aegis/internal/db"
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
 	"go.codycody31.dev/squad-aegis/internal/models"
 	"go.codycody31.dev/squad-aegis/internal/shared/utils"
 )
 
 type RemoteBanSyncService struct {
-	database   db.Executor
-	dbInstance *sql.DB // Keep reference to the database instance for transactions
+	database     db.Executor
+	dbInstance   *sql.DB // Keep reference to the database instance for transactions
+	eventManager *event_manager.EventManager
 }
 
 func NewRemoteBanSyncService(database db.Executor, dbInstance *sql.DB) *RemoteBanSyncService {
@@ -31,6 +33,12 @@ func NewRemoteBanSyncService(database db.Executor, dbInstance *sql.DB) *RemoteBa
 	}
 }
 
+// SetEventManager sets the event manager that ban list changes are published
+// to, once per subscribed server.
+func (s *RemoteBanSyncService) SetEventManager(eventManager *event_manager.EventManager) {
+	s.eventManager = eventManager
+}
+
 // SyncAllSources syncs all enabled remote ban sources
 func (s *RemoteBanSyncService) SyncAllSources(ctx context.Context) error {
 	sources, err := GetRemoteBanSources(ctx, s.database)
@@ -277,12 +285,19 @@ func (s *RemoteBanSyncService) updateBanListBans(ctx context.Context, banListID
 	}
 	defer tx.Rollback()
 
+	previous, err := listBanListBans(ctx, tx, banListID)
+	if err != nil {
+		return err
+	}
+
 	// Delete existing bans from this ban list
 	_, err = tx.ExecContext(ctx, "DELETE FROM server_bans WHERE ban_list_id = $1", banListID)
 	if err != nil {
 		return err
 	}
 
+	current := make(map[string]banListBan, len(bans))
+
 	// Insert new bans, filtering out ignored Steam IDs
 	for _, ban := range bans {
 		// Check if this Steam ID is in the ignore list
@@ -296,16 +311,106 @@ func (s *RemoteBanSyncService) updateBanListBans(ctx context.Context, banListID
 		}
 
 		// For remote bans, use NULL for admin_id and server_id since they don't apply to a specific server/admin
+		banID := uuid.New()
 		_, err = tx.ExecContext(ctx, `
 			INSERT INTO server_bans (id, server_id, admin_id, steam_id, reason, expires_at, ban_list_id, created_at, updated_at)
 			VALUES ($1, NULL, NULL, $2, $3, $4, $5, $6, $7)
-		`, uuid.New(), ban.SteamID, ban.Reason, ban.ExpiresAt, banListID, ban.CreatedAt, ban.CreatedAt)
+		`, banID, ban.SteamID, ban.Reason, ban.ExpiresAt, banListID, ban.CreatedAt, ban.CreatedAt)
 		if err != nil {
 			return err
 		}
+		current[ban.SteamID] = banListBan{id: banID, steamID: ban.SteamID, reason: ban.Reason, expiresAt: ban.ExpiresAt}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.publishBanListChanges(ctx, banListID, previous, current)
+	return nil
+}
+
+// banListBan is a ban held by a ban list, keyed by Steam ID when diffing
+// one sync against the next.
+type banListBan struct {
+	id        uuid.UUID
+	steamID   string
+	reason    string
+	expiresAt *time.Time
+}
+
+func listBanListBans(ctx context.Context, tx *sql.Tx, banListID uuid.UUID) (map[string]banListBan, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, steam_id, reason, expires_at
+		FROM server_bans
+		WHERE ban_list_id = $1 AND steam_id IS NOT NULL
+	`, banListID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bans := make(map[string]banListBan)
+	for rows.Next() {
+		var ban banListBan
+		var steamID int64
+		if err := rows.Scan(&ban.id, &steamID, &ban.reason, &ban.expiresAt); err != nil {
+			return nil, err
+		}
+		ban.steamID = strconv.FormatInt(steamID, 10)
+		bans[ban.steamID] = ban
+	}
+	return bans, rows.Err()
+}
+
+// publishBanListChanges publishes a removed event for every ban that left the
+// list and a created event for every ban that joined it, on each server
+// subscribed to the list. Bans present in both syncs are not announced again.
+func (s *RemoteBanSyncService) publishBanListChanges(ctx context.Context, banListID uuid.UUID, previous, current map[string]banListBan) {
+	if s.eventManager == nil {
+		return
 	}
 
-	return tx.Commit()
+	var changes []event_manager.AegisBanData
+	var expiries []*time.Time
+	for steamID, ban := range previous {
+		if _, ok := current[steamID]; ok {
+			continue
+		}
+		changes = append(changes, event_manager.AegisBanData{BanID: ban.id.String(), SteamID: steamID, Reason: ban.reason, Action: "removed", Source: event_manager.AegisBanSourceBanList})
+		expiries = append(expiries, nil)
+	}
+	for steamID, ban := range current {
+		if _, ok := previous[steamID]; ok {
+			continue
+		}
+		changes = append(changes, event_manager.AegisBanData{BanID: ban.id.String(), SteamID: steamID, Reason: ban.reason, Action: "created", Source: event_manager.AegisBanSourceBanList})
+		expiries = append(expiries, ban.expiresAt)
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	rows, err := s.database.QueryContext(ctx, `SELECT server_id FROM server_ban_list_subscriptions WHERE ban_list_id = $1`, banListID)
+	if err != nil {
+		log.Warn().Err(err).Str("banListId", banListID.String()).Msg("Failed to load ban list subscribers; ban events not published")
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var serverID uuid.UUID
+		if err := rows.Scan(&serverID); err != nil {
+			log.Warn().Err(err).Str("banListId", banListID.String()).Msg("Failed to read ban list subscriber")
+			return
+		}
+		for i, ban := range changes {
+			s.eventManager.PublishAegisBan(serverID, ban, expiries[i])
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Warn().Err(err).Str("banListId", banListID.String()).Msg("Failed to read ban list subscribers")
+	}
 }
 
 // StartPeriodicSync starts a background goroutine that periodically syncs remote ban sources
@@ -12,15 +12,18 @@ import (
 	"go.codycody31.dev/squad-aegis/internal/models"
 )
 
-func CreateSession(ctx context.Context, database db.Executor, userId uuid.UUID, userIp string, expiresIn time.Duration) (*models.Session, error) {
+// CreateSession creates a login session. authMethod is one of the
+// models.AuthMethod* constants and is kept for auditing.
+func CreateSession(ctx context.Context, database db.Executor, userId uuid.UUID, userIp string, expiresIn time.Duration, authMethod string) (*models.Session, error) {
 	session := &models.Session{
 		Id:         uuid.New(),
 		UserId:     userId,
 		Token:      uuid.New().String(),
 		LastSeen:   time.Now(),
 		LastSeenIp: userIp,
+		AuthMethod: authMethod,
 	}
-	_, err := database.ExecContext(ctx, "INSERT INTO sessions (id, user_id, token, last_seen, last_seen_ip, created_at) VALUES ($1, $2, $3, $4, $5, $6)", session.Id, session.UserId, session.Token, session.LastSeen, session.LastSeenIp, session.LastSeen)
+	_, err := database.ExecContext(ctx, "INSERT INTO sessions (id, user_id, token, last_seen, last_seen_ip, created_at, auth_method) VALUES ($1, $2, $3, $4, $5, $6, $7)", session.Id, session.UserId, session.Token, session.LastSeen, session.LastSeenIp, session.LastSeen, session.AuthMethod)
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +41,7 @@ func CreateSession(ctx context.Context, database db.Executor, userId uuid.UUID,
 }
 
 func GetSessionsByUserId(ctx context.Context, database db.Executor, userId uuid.UUID) ([]models.Session, error) {
-	rows, err := database.QueryContext(ctx, "SELECT id, user_id, token, expires_at, last_seen, last_seen_ip FROM sessions WHERE user_id = $1", userId)
+	rows, err := database.QueryContext(ctx, "SELECT id, user_id, token, expires_at, last_seen, last_seen_ip, auth_method FROM sessions WHERE user_id = $1", userId)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +50,7 @@ func GetSessionsByUserId(ctx context.Context, database db.Executor, userId uuid.
 	var sessions []models.Session
 	for rows.Next() {
 		var session models.Session
-		if err := rows.Scan(&session.Id, &session.UserId, &session.Token, &session.ExpiresAt, &session.LastSeen, &session.LastSeenIp); err != nil {
+		if err := rows.Scan(&session.Id, &session.UserId, &session.Token, &session.ExpiresAt, &session.LastSeen, &session.LastSeenIp, &session.AuthMethod); err != nil {
 			fmt.Println(err)
 			return nil, err
 		}
@@ -58,9 +61,9 @@ func GetSessionsByUserId(ctx context.Context, database db.Executor, userId uuid.
 }
 
 func GetSessionById(ctx context.Context, database db.Executor, sessionId uuid.UUID) (*models.Session, error) {
-	row := database.QueryRowContext(ctx, "SELECT id, user_id, token, expires_at, last_seen, last_seen_ip FROM sessions WHERE id = $1", sessionId)
+	row := database.QueryRowContext(ctx, "SELECT id, user_id, token, expires_at, last_seen, last_seen_ip, auth_method FROM sessions WHERE id = $1", sessionId)
 	var session models.Session
-	if err := row.Scan(&session.Id, &session.UserId, &session.Token, &session.ExpiresAt, &session.LastSeen, &session.LastSeenIp); err != nil {
+	if err := row.Scan(&session.Id, &session.UserId, &session.Token, &session.ExpiresAt, &session.LastSeen, &session.LastSeenIp, &session.AuthMethod); err != nil {
 		return nil, err
 	}
 
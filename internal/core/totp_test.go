@@ -0,0 +1,105 @@
+package core
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 seed from RFC 6238 Appendix B.
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestGenerateTOTPCodeMatchesRFC6238Vectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+	}
+
+	for _, tt := range tests {
+		got, err := GenerateTOTPCode(rfc6238Secret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("GenerateTOTPCode(%d) error = %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Fatalf("GenerateTOTPCode(%d) = %q, want %q", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTOTPCodeAllowsOneStepOfSkew(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	previous, err := GenerateTOTPCode(rfc6238Secret, now.Add(-TOTPPeriod))
+	if err != nil {
+		t.Fatalf("GenerateTOTPCode() error = %v", err)
+	}
+
+	step, ok := ValidateTOTPCode(rfc6238Secret, previous, now)
+	if !ok {
+		t.Fatal("ValidateTOTPCode() rejected code from the previous step")
+	}
+	if want := totpStep(now) - 1; step != want {
+		t.Fatalf("ValidateTOTPCode() step = %d, want %d", step, want)
+	}
+
+	stale, err := GenerateTOTPCode(rfc6238Secret, now.Add(-3*TOTPPeriod))
+	if err != nil {
+		t.Fatalf("GenerateTOTPCode() error = %v", err)
+	}
+	if _, ok := ValidateTOTPCode(rfc6238Secret, stale, now); ok {
+		t.Fatal("ValidateTOTPCode() accepted a code three steps old")
+	}
+}
+
+func TestValidateTOTPCodeRejectsMalformedInput(t *testing.T) {
+	now := time.Unix(59, 0)
+	for _, code := range []string{"", "28708", "2870821", "abcdef"} {
+		if _, ok := ValidateTOTPCode(rfc6238Secret, code, now); ok {
+			t.Fatalf("ValidateTOTPCode(%q) = ok, want rejection", code)
+		}
+	}
+	if _, ok := ValidateTOTPCode(rfc6238Secret, " 287 082 ", now); !ok {
+		t.Fatal("ValidateTOTPCode() rejected a code with whitespace")
+	}
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(RecoveryCodeCount)
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes() error = %v", err)
+	}
+	if len(codes) != RecoveryCodeCount {
+		t.Fatalf("len(codes) = %d, want %d", len(codes), RecoveryCodeCount)
+	}
+
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if len(code) != recoveryCodeLength+1 || code[recoveryCodeLength/2] != '-' {
+			t.Fatalf("recovery code %q has unexpected format", code)
+		}
+		if seen[code] {
+			t.Fatalf("duplicate recovery code %q", code)
+		}
+		seen[code] = true
+
+		if HashRecoveryCode(code) != HashRecoveryCode(strings.ToLower(strings.ReplaceAll(code, "-", ""))) {
+			t.Fatalf("HashRecoveryCode() is sensitive to case or separators for %q", code)
+		}
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := TOTPProvisioningURI("Squad Aegis", "admin", "JBSWY3DPEHPK3PXP")
+	if !strings.HasPrefix(uri, "otpauth://totp/Squad%20Aegis:admin?") {
+		t.Fatalf("TOTPProvisioningURI() = %q, unexpected label", uri)
+	}
+	if !strings.Contains(uri, "secret=JBSWY3DPEHPK3PXP") {
+		t.Fatalf("TOTPProvisioningURI() = %q, missing secret", uri)
+	}
+}
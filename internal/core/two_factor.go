@@ -0,0 +1,391 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"go.codycody31.dev/squad-aegis/internal/db"
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+var (
+	ErrTwoFactorNotEnrolled      = errors.New("two-factor authentication is not enrolled")
+	ErrTwoFactorAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrInvalidTwoFactorCode      = errors.New("invalid two-factor code")
+	ErrTwoFactorChallengeExpired = errors.New("two-factor challenge is invalid or expired")
+)
+
+const (
+	// TwoFactorChallengeTTL bounds how long a user has to enter their code
+	// after a successful password check.
+	TwoFactorChallengeTTL = 5 * time.Minute
+	// TwoFactorChallengeMaxAttempts limits code guesses per challenge.
+	TwoFactorChallengeMaxAttempts = 5
+)
+
+// privilegedPermissionCodes are the codes, besides every ui:bans:* code,
+// that make a user subject to the require-2FA policy. Alongside console
+// execution they list each wildcard that permissions.EvaluatePermission
+// expands to console execution or the ban permissions.
+var privilegedPermissionCodes = []string{"*", "ui:*", "ui:console:*", "ui:console:execute"}
+
+// privilegedPermissionCondition matches the same permission codes as
+// IsPrivilegedPermission in SQL.
+var privilegedPermissionCondition = "(p.code IN ('" + strings.Join(privilegedPermissionCodes, "', '") + "') OR p.code LIKE 'ui:bans:%')"
+
+// IsPrivilegedPermission reports whether holding code makes a user subject to
+// the require-2FA policy.
+func IsPrivilegedPermission(code string) bool {
+	for _, privileged := range privilegedPermissionCodes {
+		if code == privileged {
+			return true
+		}
+	}
+	return strings.HasPrefix(code, "ui:bans:")
+}
+
+func GetUserTwoFactor(ctx context.Context, database db.Executor, userId uuid.UUID) (*models.UserTwoFactor, error) {
+	row := database.QueryRowContext(ctx, `
+		SELECT user_id, secret, enabled, enabled_at, last_used_step, created_at, updated_at
+		FROM user_two_factor
+		WHERE user_id = $1
+	`, userId)
+
+	var tf models.UserTwoFactor
+	if err := row.Scan(&tf.UserId, &tf.Secret, &tf.Enabled, &tf.EnabledAt, &tf.LastUsedStep, &tf.CreatedAt, &tf.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTwoFactorNotEnrolled
+		}
+		return nil, fmt.Errorf("failed to get two-factor enrollment: %w", err)
+	}
+
+	return &tf, nil
+}
+
+// IsTwoFactorEnabled reports whether the user has a confirmed TOTP enrollment.
+func IsTwoFactorEnabled(ctx context.Context, database db.Executor, userId uuid.UUID) (bool, error) {
+	var enabled bool
+	err := database.QueryRowContext(ctx, "SELECT enabled FROM user_two_factor WHERE user_id = $1", userId).Scan(&enabled)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check two-factor status: %w", err)
+	}
+	return enabled, nil
+}
+
+// BeginTwoFactorEnrollment stores a fresh pending secret for the user,
+// replacing any earlier unconfirmed one.
+func BeginTwoFactorEnrollment(ctx context.Context, database db.Executor, userId uuid.UUID) (string, error) {
+	existing, err := GetUserTwoFactor(ctx, database, userId)
+	if err != nil && !errors.Is(err, ErrTwoFactorNotEnrolled) {
+		return "", err
+	}
+	if existing != nil && existing.Enabled {
+		return "", ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = database.ExecContext(ctx, `
+		INSERT INTO user_two_factor (user_id, secret, enabled, last_used_step, created_at, updated_at)
+		VALUES ($1, $2, false, 0, NOW(), NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, enabled = false, enabled_at = NULL, last_used_step = 0, updated_at = NOW()
+	`, userId, secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to store two-factor secret: %w", err)
+	}
+
+	return secret, nil
+}
+
+// ConfirmTwoFactorEnrollment enables a pending enrollment once the user proves
+// possession of the secret, and issues a fresh set of recovery codes.
+func ConfirmTwoFactorEnrollment(ctx context.Context, database db.Executor, userId uuid.UUID, code string) ([]string, error) {
+	tf, err := GetUserTwoFactor(ctx, database, userId)
+	if err != nil {
+		return nil, err
+	}
+	if tf.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := ValidateTOTPCode(tf.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	_, err = database.ExecContext(ctx, `
+		UPDATE user_two_factor
+		SET enabled = true, enabled_at = NOW(), last_used_step = $1, updated_at = NOW()
+		WHERE user_id = $2
+	`, step, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable two-factor: %w", err)
+	}
+
+	return ReplaceRecoveryCodes(ctx, database, userId)
+}
+
+// DisableTwoFactor removes the user's enrollment and recovery codes.
+func DisableTwoFactor(ctx context.Context, database db.Executor, userId uuid.UUID) error {
+	if _, err := database.ExecContext(ctx, "DELETE FROM user_recovery_codes WHERE user_id = $1", userId); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	if _, err := database.ExecContext(ctx, "DELETE FROM user_two_factor WHERE user_id = $1", userId); err != nil {
+		return fmt.Errorf("failed to delete two-factor enrollment: %w", err)
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes invalidates all existing recovery codes and returns a
+// new plaintext set. The plaintext is never stored.
+func ReplaceRecoveryCodes(ctx context.Context, database db.Executor, userId uuid.UUID) ([]string, error) {
+	codes, err := GenerateRecoveryCodes(RecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := database.ExecContext(ctx, "DELETE FROM user_recovery_codes WHERE user_id = $1", userId); err != nil {
+		return nil, fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	for _, code := range codes {
+		_, err := database.ExecContext(ctx, `
+			INSERT INTO user_recovery_codes (id, user_id, code_hash, created_at)
+			VALUES ($1, $2, $3, NOW())
+		`, uuid.New(), userId, HashRecoveryCode(code))
+		if err != nil {
+			return nil, fmt.Errorf("failed to store recovery code: %w", err)
+		}
+	}
+
+	return codes, nil
+}
+
+// CountUnusedRecoveryCodes returns how many recovery codes the user has left.
+func CountUnusedRecoveryCodes(ctx context.Context, database db.Executor, userId uuid.UUID) (int, error) {
+	var count int
+	err := database.QueryRowContext(ctx, "SELECT COUNT(*) FROM user_recovery_codes WHERE user_id = $1 AND used_at IS NULL", userId).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+	return count, nil
+}
+
+// VerifyTwoFactorCode checks a TOTP code or, failing that, a recovery code.
+// Accepted TOTP steps and recovery codes cannot be reused. The returned method
+// is "totp" or "recovery_code".
+func VerifyTwoFactorCode(ctx context.Context, database db.Executor, userId uuid.UUID, code string) (string, error) {
+	tf, err := GetUserTwoFactor(ctx, database, userId)
+	if err != nil {
+		return "", err
+	}
+	if !tf.Enabled {
+		return "", ErrTwoFactorNotEnrolled
+	}
+
+	if step, ok := ValidateTOTPCode(tf.Secret, code, time.Now()); ok {
+		// The conditional update makes concurrent replays of the same code lose.
+		result, err := database.ExecContext(ctx, `
+			UPDATE user_two_factor SET last_used_step = $1, updated_at = NOW()
+			WHERE user_id = $2 AND last_used_step < $1
+		`, step, userId)
+		if err != nil {
+			return "", fmt.Errorf("failed to record two-factor use: %w", err)
+		}
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			return "", ErrInvalidTwoFactorCode
+		}
+		return "totp", nil
+	}
+
+	result, err := database.ExecContext(ctx, `
+		UPDATE user_recovery_codes SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`, userId, HashRecoveryCode(code))
+	if err != nil {
+		return "", fmt.Errorf("failed to consume recovery code: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return "", ErrInvalidTwoFactorCode
+	}
+
+	return "recovery_code", nil
+}
+
+// CreateTwoFactorChallenge issues a login challenge token for a user whose
+// first factor has already been verified. authMethod is recorded on the
+// session issued once the challenge is completed.
+func CreateTwoFactorChallenge(ctx context.Context, database db.Executor, userId uuid.UUID, clientIp string, authMethod string) (string, time.Time, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate challenge token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+	expiresAt := time.Now().Add(TwoFactorChallengeTTL)
+
+	_, err := database.ExecContext(ctx, `
+		INSERT INTO auth_two_factor_challenges (id, token, user_id, client_ip, attempts, expires_at, created_at, auth_method)
+		VALUES ($1, $2, $3, $4, 0, $5, NOW(), $6)
+	`, uuid.New(), token, userId, clientIp, expiresAt, authMethod)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to create two-factor challenge: %w", err)
+	}
+
+	// Opportunistically clear out stale challenges.
+	_, _ = database.ExecContext(ctx, "DELETE FROM auth_two_factor_challenges WHERE expires_at < NOW()")
+
+	return token, expiresAt, nil
+}
+
+// ClaimTwoFactorChallenge returns the user a challenge belongs to and the auth
+// method to record on the resulting session, and counts the attempt.
+// Challenges that are expired or out of attempts are rejected.
+func ClaimTwoFactorChallenge(ctx context.Context, database db.Executor, token string) (uuid.UUID, string, error) {
+	var userId uuid.UUID
+	var authMethod string
+	err := database.QueryRowContext(ctx, `
+		UPDATE auth_two_factor_challenges
+		SET attempts = attempts + 1
+		WHERE token = $1 AND expires_at > NOW() AND attempts < $2
+		RETURNING user_id, auth_method
+	`, token, TwoFactorChallengeMaxAttempts).Scan(&userId, &authMethod)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, "", ErrTwoFactorChallengeExpired
+		}
+		return uuid.Nil, "", fmt.Errorf("failed to claim two-factor challenge: %w", err)
+	}
+	return userId, authMethod, nil
+}
+
+func DeleteTwoFactorChallenge(ctx context.Context, database db.Executor, token string) error {
+	_, err := database.ExecContext(ctx, "DELETE FROM auth_two_factor_challenges WHERE token = $1", token)
+	return err
+}
+
+// MarkSessionTwoFactorVerified records that the session's owner just passed a
+// second-factor check.
+func MarkSessionTwoFactorVerified(ctx context.Context, database db.Executor, sessionId uuid.UUID) error {
+	_, err := database.ExecContext(ctx, "UPDATE sessions SET two_factor_verified_at = NOW() WHERE id = $1", sessionId)
+	return err
+}
+
+// IsSessionTwoFactorFresh reports whether the session verified its second
+// factor within window. The comparison runs in SQL so it uses the same clock
+// that wrote the timestamp.
+func IsSessionTwoFactorFresh(ctx context.Context, database db.Executor, sessionId uuid.UUID, window time.Duration) (bool, error) {
+	var fresh bool
+	err := database.QueryRowContext(ctx, `
+		SELECT COALESCE(two_factor_verified_at > NOW() - make_interval(secs => $2), false)
+		FROM sessions WHERE id = $1
+	`, sessionId, window.Seconds()).Scan(&fresh)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check session two-factor state: %w", err)
+	}
+	return fresh, nil
+}
+
+func GetSessionTwoFactorVerifiedAt(ctx context.Context, database db.Executor, sessionId uuid.UUID) (null.Time, error) {
+	var verifiedAt null.Time
+	err := database.QueryRowContext(ctx, "SELECT two_factor_verified_at FROM sessions WHERE id = $1", sessionId).Scan(&verifiedAt)
+	return verifiedAt, err
+}
+
+func GetSecurityPolicy(ctx context.Context, database db.Executor) (*models.SecurityPolicy, error) {
+	var policy models.SecurityPolicy
+	var updatedBy uuid.NullUUID
+	err := database.QueryRowContext(ctx, `
+		SELECT require_two_factor_for_privileged, updated_at, updated_by
+		FROM security_policy WHERE id = true
+	`).Scan(&policy.RequireTwoFactorForPrivileged, &policy.UpdatedAt, &updatedBy)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &models.SecurityPolicy{}, nil
+		}
+		return nil, fmt.Errorf("failed to get security policy: %w", err)
+	}
+	if updatedBy.Valid {
+		policy.UpdatedBy = &updatedBy.UUID
+	}
+	return &policy, nil
+}
+
+func UpdateSecurityPolicy(ctx context.Context, database db.Executor, policy *models.SecurityPolicy, updatedBy uuid.UUID) error {
+	_, err := database.ExecContext(ctx, `
+		INSERT INTO security_policy (id, require_two_factor_for_privileged, updated_at, updated_by)
+		VALUES (true, $1, NOW(), $2)
+		ON CONFLICT (id) DO UPDATE
+		SET require_two_factor_for_privileged = EXCLUDED.require_two_factor_for_privileged,
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by
+	`, policy.RequireTwoFactorForPrivileged, updatedBy)
+	if err != nil {
+		return fmt.Errorf("failed to update security policy: %w", err)
+	}
+	return nil
+}
+
+// UserHoldsPrivilegedPermission reports whether the user is a super admin or
+// holds console execution or any ban permission on at least one server.
+func UserHoldsPrivilegedPermission(ctx context.Context, database db.Executor, user *models.User) (bool, error) {
+	if user.SuperAdmin {
+		return true, nil
+	}
+
+	var exists bool
+	err := database.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM server_admins sa
+			JOIN server_role_permissions srp ON sa.server_role_id = srp.server_role_id
+			JOIN permissions p ON srp.permission_id = p.id
+			WHERE sa.user_id = $1
+			AND (sa.expires_at IS NULL OR sa.expires_at > NOW())
+			AND `+privilegedPermissionCondition+`
+		)
+	`, user.Id).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check privileged permissions: %w", err)
+	}
+	return exists, nil
+}
+
+// UserNeedsTwoFactorEnrollment reports whether the security policy requires
+// this user to enroll in 2FA before using privileged permissions.
+func UserNeedsTwoFactorEnrollment(ctx context.Context, database db.Executor, user *models.User) (bool, error) {
+	policy, err := GetSecurityPolicy(ctx, database)
+	if err != nil {
+		return false, err
+	}
+	if !policy.RequireTwoFactorForPrivileged {
+		return false, nil
+	}
+
+	enabled, err := IsTwoFactorEnabled(ctx, database, user.Id)
+	if err != nil {
+		return false, err
+	}
+	if enabled {
+		return false, nil
+	}
+
+	return UserHoldsPrivilegedPermission(ctx, database, user)
+}
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsPrivilegedPermission(t *testing.T) {
+	cases := map[string]bool{
+		"*":                  true,
+		"ui:*":               true,
+		"ui:console:*":       true,
+		"ui:console:execute": true,
+		"ui:bans:*":          true,
+		"ui:bans:create":     true,
+		"ui:bans:view":       true,
+		"ui:players:*":       false,
+		"ui:console:view":    false,
+		"rcon:*":             false,
+	}
+	for code, want := range cases {
+		if got := IsPrivilegedPermission(code); got != want {
+			t.Errorf("IsPrivilegedPermission(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+// A role holding ui:* can run console commands and ban players, so the SQL
+// check behind UserNeedsTwoFactorEnrollment must require it to enroll.
+func TestPrivilegedPermissionConditionCoversWildcards(t *testing.T) {
+	for _, code := range []string{"*", "ui:*", "ui:console:*", "ui:console:execute"} {
+		if !strings.Contains(privilegedPermissionCondition, "'"+code+"'") {
+			t.Errorf("privilegedPermissionCondition = %s, want it to match %q", privilegedPermissionCondition, code)
+		}
+	}
+	if !strings.Contains(privilegedPermissionCondition, "LIKE 'ui:bans:%'") {
+		t.Errorf("privilegedPermissionCondition = %s, want it to match every ui:bans code", privilegedPermissionCondition)
+	}
+}
@@ -0,0 +1,268 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.codycody31.dev/squad-aegis/internal/db"
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook subscription not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+const webhookSubscriptionColumns = `id, name, url, secret, event_types, server_ids::text[], enabled, max_attempts, created_by, created_at, updated_at`
+
+func scanWebhookSubscription(scanner interface{ Scan(...any) error }) (*models.WebhookSubscription, error) {
+	var sub models.WebhookSubscription
+	var serverIds []string
+	err := scanner.Scan(&sub.Id, &sub.Name, &sub.Url, &sub.Secret, pq.Array(&sub.EventTypes), pq.Array(&serverIds),
+		&sub.Enabled, &sub.MaxAttempts, &sub.CreatedBy, &sub.CreatedAt, &sub.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	sub.ServerIds = make([]uuid.UUID, 0, len(serverIds))
+	for _, id := range serverIds {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server id in webhook filter: %w", err)
+		}
+		sub.ServerIds = append(sub.ServerIds, parsed)
+	}
+	if sub.EventTypes == nil {
+		sub.EventTypes = []string{}
+	}
+	return &sub, nil
+}
+
+// ListWebhookSubscriptions returns every webhook subscription.
+func ListWebhookSubscriptions(ctx context.Context, database db.Executor) ([]*models.WebhookSubscription, error) {
+	rows, err := database.QueryContext(ctx, `SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subs := []*models.WebhookSubscription{}
+	for rows.Next() {
+		sub, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+// GetWebhookSubscription returns a single webhook subscription.
+func GetWebhookSubscription(ctx context.Context, database db.Executor, id uuid.UUID) (*models.WebhookSubscription, error) {
+	row := database.QueryRowContext(ctx, `SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions WHERE id = $1`, id)
+	sub, err := scanWebhookSubscription(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	return sub, nil
+}
+
+// CreateWebhookSubscription stores a new subscription.
+func CreateWebhookSubscription(ctx context.Context, database db.Executor, sub *models.WebhookSubscription) error {
+	sub.Id = uuid.New()
+	err := database.QueryRowContext(ctx, `
+		INSERT INTO webhook_subscriptions (id, name, url, secret, event_types, server_ids, enabled, max_attempts, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6::uuid[], $7, $8, $9)
+		RETURNING created_at, updated_at
+	`, sub.Id, sub.Name, sub.Url, sub.Secret, pq.Array(sub.EventTypes), pq.Array(uuidStrings(sub.ServerIds)),
+		sub.Enabled, sub.MaxAttempts, sub.CreatedBy).Scan(&sub.CreatedAt, &sub.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// UpdateWebhookSubscription saves changes to an existing subscription,
+// including its secret.
+func UpdateWebhookSubscription(ctx context.Context, database db.Executor, sub *models.WebhookSubscription) error {
+	err := database.QueryRowContext(ctx, `
+		UPDATE webhook_subscriptions
+		SET name = $2, url = $3, secret = $4, event_types = $5, server_ids = $6::uuid[], enabled = $7, max_attempts = $8, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`, sub.Id, sub.Name, sub.Url, sub.Secret, pq.Array(sub.EventTypes), pq.Array(uuidStrings(sub.ServerIds)),
+		sub.Enabled, sub.MaxAttempts).Scan(&sub.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrWebhookNotFound
+		}
+		return fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// DeleteWebhookSubscription removes a subscription and its delivery log.
+func DeleteWebhookSubscription(ctx context.Context, database db.Executor, id uuid.UUID) error {
+	result, err := database.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+const webhookDeliveryColumns = `id, subscription_id, event_id, event_type, server_id, payload, status, attempts, next_attempt_at, last_attempt_at,
+	response_status, response_body, last_error, duration_ms, created_at, completed_at`
+
+func scanWebhookDelivery(scanner interface{ Scan(...any) error }) (*models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	err := scanner.Scan(&d.Id, &d.SubscriptionId, &d.EventId, &d.EventType, &d.ServerId, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.LastAttemptAt, &d.ResponseStatus, &d.ResponseBody, &d.LastError, &d.DurationMs, &d.CreatedAt, &d.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// EnqueueWebhookDelivery queues a delivery for immediate sending.
+func EnqueueWebhookDelivery(ctx context.Context, database db.Executor, delivery *models.WebhookDelivery) error {
+	delivery.Id = uuid.New()
+	delivery.Status = models.WebhookDeliveryPending
+	err := database.QueryRowContext(ctx, `
+		INSERT INTO webhook_deliveries (id, subscription_id, event_id, event_type, server_id, payload, status, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		RETURNING created_at, next_attempt_at
+	`, delivery.Id, delivery.SubscriptionId, delivery.EventId, delivery.EventType, delivery.ServerId, delivery.Payload,
+		delivery.Status).Scan(&delivery.CreatedAt, &delivery.NextAttemptAt)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// ClaimDueWebhookDeliveries returns up to limit deliveries that are due and
+// pushes their next attempt out by lease, so that another worker does not
+// pick them up while they are in flight. A worker that dies mid-delivery
+// simply lets the lease expire and the delivery is retried. Deliveries of
+// disabled subscriptions are left alone until the subscription is enabled
+// again.
+func ClaimDueWebhookDeliveries(ctx context.Context, database db.Executor, lease time.Duration, limit int) ([]*models.WebhookDelivery, error) {
+	rows, err := database.QueryContext(ctx, `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = NOW() + make_interval(secs => $1)
+		WHERE id IN (
+			SELECT d.id FROM webhook_deliveries d
+			JOIN webhook_subscriptions s ON s.id = d.subscription_id AND s.enabled
+			WHERE d.status = 'pending' AND d.next_attempt_at <= NOW()
+			ORDER BY d.next_attempt_at
+			LIMIT $2
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING `+webhookDeliveryColumns, lease.Seconds(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*models.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// RecordWebhookAttempt stores the outcome of a delivery attempt.
+func RecordWebhookAttempt(ctx context.Context, database db.Executor, delivery *models.WebhookDelivery) error {
+	_, err := database.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_attempt_at = $5, response_status = $6,
+		    response_body = $7, last_error = $8, duration_ms = $9, completed_at = $10
+		WHERE id = $1
+	`, delivery.Id, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastAttemptAt, delivery.ResponseStatus,
+		delivery.ResponseBody, delivery.LastError, delivery.DurationMs, delivery.CompletedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook attempt: %w", err)
+	}
+	return nil
+}
+
+// ListWebhookDeliveries returns a page of the delivery log, newest first,
+// optionally narrowed to one subscription and/or status.
+func ListWebhookDeliveries(ctx context.Context, database db.Executor, subscriptionId *uuid.UUID, status string, limit, offset int) ([]*models.WebhookDelivery, int, error) {
+	where := ` WHERE 1=1`
+	args := []any{}
+	if subscriptionId != nil {
+		args = append(args, *subscriptionId)
+		where += fmt.Sprintf(" AND subscription_id = $%d", len(args))
+	}
+	if status != "" {
+		args = append(args, status)
+		where += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+
+	var total int
+	if err := database.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_deliveries`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	args = append(args, limit, offset)
+	rows, err := database.QueryContext(ctx, `SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries`+where+
+		fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*models.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, total, rows.Err()
+}
+
+// RetryWebhookDelivery puts a finished delivery, typically one from the
+// dead-letter queue, back in the queue with a fresh set of attempts.
+func RetryWebhookDelivery(ctx context.Context, database db.Executor, deliveryId uuid.UUID) error {
+	result, err := database.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW(), completed_at = NULL
+		WHERE id = $1 AND status <> 'pending'
+	`, deliveryId)
+	if err != nil {
+		return fmt.Errorf("failed to retry webhook delivery: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrWebhookDeliveryNotFound
+	}
+	return nil
+}
+
+// PruneWebhookDeliveries deletes finished deliveries older than retention.
+func PruneWebhookDeliveries(ctx context.Context, database db.Executor, retention time.Duration) (int64, error) {
+	result, err := database.ExecContext(ctx, `
+		DELETE FROM webhook_deliveries
+		WHERE status <> 'pending' AND created_at < $1
+	`, time.Now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune webhook deliveries: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,7 @@
+ALTER TABLE public.sessions
+    DROP COLUMN IF EXISTS two_factor_verified_at;
+
+DROP TABLE IF EXISTS public.security_policy;
+DROP TABLE IF EXISTS public.auth_two_factor_challenges;
+DROP TABLE IF EXISTS public.user_recovery_codes;
+DROP TABLE IF EXISTS public.user_two_factor;
@@ -0,0 +1,53 @@
+-- TOTP enrollment per user. enabled stays false until the user confirms the
+-- secret with a valid code.
+CREATE TABLE IF NOT EXISTS public.user_two_factor (
+    user_id uuid PRIMARY KEY REFERENCES public.users(id) ON DELETE CASCADE,
+    secret TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT false,
+    enabled_at TIMESTAMPTZ,
+    -- last accepted TOTP time step, used to reject replayed codes
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Single-use recovery codes, stored as SHA-256 digests.
+CREATE TABLE IF NOT EXISTS public.user_recovery_codes (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(user_id, code_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_recovery_codes_user_id ON public.user_recovery_codes(user_id);
+
+-- Short-lived tokens issued after a correct password for users with 2FA
+-- enabled; exchanged for a session once the second factor is verified.
+CREATE TABLE IF NOT EXISTS public.auth_two_factor_challenges (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    token TEXT NOT NULL UNIQUE,
+    user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    client_ip TEXT NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_two_factor_challenges_expires_at ON public.auth_two_factor_challenges(expires_at);
+
+-- Instance-wide security settings. Single row keyed by id = true.
+CREATE TABLE IF NOT EXISTS public.security_policy (
+    id BOOLEAN PRIMARY KEY DEFAULT true CHECK (id),
+    require_two_factor_for_privileged BOOLEAN NOT NULL DEFAULT false,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_by uuid REFERENCES public.users(id) ON DELETE SET NULL
+);
+
+INSERT INTO public.security_policy (id) VALUES (true) ON CONFLICT (id) DO NOTHING;
+
+-- Time the second factor was last verified within this session. Sensitive
+-- sudo endpoints require this to be recent.
+ALTER TABLE public.sessions
+    ADD COLUMN IF NOT EXISTS two_factor_verified_at timestamp without time zone;
@@ -0,0 +1,14 @@
+ALTER TABLE public.auth_two_factor_challenges
+    DROP COLUMN IF EXISTS auth_method;
+
+ALTER TABLE public.sessions
+    DROP COLUMN IF EXISTS auth_method;
+
+DELETE FROM public.server_admins WHERE managed_by_oidc = true;
+
+ALTER TABLE public.server_admins
+    DROP COLUMN IF EXISTS managed_by_oidc;
+
+DROP TABLE IF EXISTS public.auth_oidc_states;
+DROP TABLE IF EXISTS public.oidc_group_mappings;
+DROP TABLE IF EXISTS public.user_identities;
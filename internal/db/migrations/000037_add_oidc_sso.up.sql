@@ -0,0 +1,51 @@
+-- External identities linked to local users. A user provisioned through SSO
+-- gets one row per (provider, subject).
+CREATE TABLE IF NOT EXISTS public.user_identities (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    last_login_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON public.user_identities(user_id);
+
+-- Maps IdP group claims to a server role or to super admin.
+CREATE TABLE IF NOT EXISTS public.oidc_group_mappings (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    group_name TEXT NOT NULL,
+    server_id uuid REFERENCES public.servers(id) ON DELETE CASCADE,
+    server_role_id uuid REFERENCES public.server_roles(id) ON DELETE CASCADE,
+    super_admin BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_oidc_group_mappings_target CHECK (
+        (super_admin AND server_id IS NULL AND server_role_id IS NULL)
+        OR (NOT super_admin AND server_id IS NOT NULL AND server_role_id IS NOT NULL)
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_oidc_group_mappings_group_name ON public.oidc_group_mappings(group_name);
+
+-- Pending authorization requests; consumed by the callback.
+CREATE TABLE IF NOT EXISTS public.auth_oidc_states (
+    state TEXT PRIMARY KEY,
+    nonce TEXT NOT NULL,
+    code_verifier TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Admin rows created from group mappings are replaced on every SSO login;
+-- manually assigned rows are left alone.
+ALTER TABLE public.server_admins
+    ADD COLUMN IF NOT EXISTS managed_by_oidc BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE public.sessions
+    ADD COLUMN IF NOT EXISTS auth_method TEXT NOT NULL DEFAULT 'password';
+
+-- Session auth method to issue once a pending 2FA challenge is completed.
+ALTER TABLE public.auth_two_factor_challenges
+    ADD COLUMN IF NOT EXISTS auth_method TEXT NOT NULL DEFAULT 'password_2fa';
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS public.idx_audit_logs_api_token_id;
+
+ALTER TABLE public.audit_logs
+    DROP COLUMN IF EXISTS api_token_id;
+
+DROP TABLE IF EXISTS public.api_tokens;
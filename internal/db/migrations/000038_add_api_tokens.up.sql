@@ -0,0 +1,25 @@
+-- Personal API tokens for scripts and bots. Only a SHA-256 hash of the token
+-- is stored; token_prefix keeps a short, non-secret hint for display.
+CREATE TABLE IF NOT EXISTS public.api_tokens (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    token_prefix TEXT NOT NULL,
+    server_ids uuid[] NOT NULL,
+    permissions TEXT[] NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    last_used_ip TEXT,
+    revoked_at TIMESTAMPTZ,
+    revoked_by uuid REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_tokens_user_id ON public.api_tokens(user_id);
+
+-- Audit entries written by a request authenticated with an API token.
+ALTER TABLE public.audit_logs
+    ADD COLUMN IF NOT EXISTS api_token_id uuid REFERENCES public.api_tokens(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_api_token_id ON public.audit_logs(api_token_id) WHERE api_token_id IS NOT NULL;
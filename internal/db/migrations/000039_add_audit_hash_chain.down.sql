@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS public.audit_checkpoints;
+
+DROP INDEX IF EXISTS public.idx_audit_logs_chain;
+
+ALTER TABLE public.audit_logs
+    DROP COLUMN IF EXISTS actor_id,
+    DROP COLUMN IF EXISTS entry_hash,
+    DROP COLUMN IF EXISTS prev_hash,
+    DROP COLUMN IF EXISTS chain_seq,
+    DROP COLUMN IF EXISTS chain_key;
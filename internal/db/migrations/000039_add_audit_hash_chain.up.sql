@@ -0,0 +1,25 @@
+-- Hash chain columns. Entries written before this migration keep NULL chain
+-- columns and are reported as unchained by verification.
+ALTER TABLE public.audit_logs
+    ADD COLUMN IF NOT EXISTS chain_key TEXT,
+    ADD COLUMN IF NOT EXISTS chain_seq BIGINT,
+    ADD COLUMN IF NOT EXISTS prev_hash TEXT,
+    ADD COLUMN IF NOT EXISTS entry_hash TEXT,
+    -- Immutable copy of user_id. user_id is nulled when a user is deleted,
+    -- actor_id is not, so deleting a user does not break the chain.
+    ADD COLUMN IF NOT EXISTS actor_id uuid;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_logs_chain ON public.audit_logs(chain_key, chain_seq) WHERE chain_seq IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS public.audit_checkpoints (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    chain_key TEXT NOT NULL,
+    chain_seq BIGINT NOT NULL,
+    entry_hash TEXT NOT NULL,
+    signed_at TIMESTAMPTZ NOT NULL,
+    key_id TEXT NOT NULL,
+    public_key TEXT NOT NULL,
+    signature TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_checkpoints_chain ON public.audit_checkpoints(chain_key, chain_seq);
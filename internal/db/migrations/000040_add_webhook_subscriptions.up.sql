@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS public.webhook_subscriptions (
+    id uuid PRIMARY KEY,
+    name TEXT NOT NULL,
+    url TEXT NOT NULL,
+    -- Shared secret for the X-Aegis-Signature HMAC. Stored in the clear
+    -- because it is needed to sign every delivery.
+    secret TEXT NOT NULL,
+    -- Empty arrays match every event type / server.
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    server_ids uuid[] NOT NULL DEFAULT '{}',
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    max_attempts INTEGER NOT NULL DEFAULT 8,
+    created_by uuid REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS public.webhook_deliveries (
+    id uuid PRIMARY KEY,
+    subscription_id uuid NOT NULL REFERENCES public.webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id uuid NOT NULL,
+    event_type TEXT NOT NULL,
+    server_id uuid,
+    -- Kept as text, not jsonb, so retries sign and send the exact same bytes.
+    payload TEXT NOT NULL,
+    -- pending, succeeded or dead (gave up after max_attempts)
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ,
+    last_attempt_at TIMESTAMPTZ,
+    response_status INTEGER,
+    response_body TEXT,
+    last_error TEXT,
+    duration_ms INTEGER,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON public.webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON public.webhook_deliveries(subscription_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status ON public.webhook_deliveries(status, created_at DESC);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS public.lua_module_versions;
+DROP TABLE IF EXISTS public.lua_modules;
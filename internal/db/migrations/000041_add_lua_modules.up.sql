@@ -0,0 +1,28 @@
+-- Shared Lua modules that workflow scripts load with require(). A module
+-- with no server_id is global and available to every server; a server's own
+-- module shadows a global one of the same name.
+CREATE TABLE IF NOT EXISTS public.lua_modules (
+    id uuid PRIMARY KEY,
+    server_id uuid REFERENCES public.servers(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT,
+    latest_version INTEGER NOT NULL DEFAULT 1,
+    created_by uuid REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_lua_modules_server_name ON public.lua_modules(server_id, name) WHERE server_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_lua_modules_global_name ON public.lua_modules(name) WHERE server_id IS NULL;
+
+-- Every saved change is a new immutable version so workflows can pin one.
+CREATE TABLE IF NOT EXISTS public.lua_module_versions (
+    id uuid PRIMARY KEY,
+    module_id uuid NOT NULL REFERENCES public.lua_modules(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    source TEXT NOT NULL,
+    test_source TEXT NOT NULL DEFAULT '',
+    created_by uuid REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (module_id, version)
+);
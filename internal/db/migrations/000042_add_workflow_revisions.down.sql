@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_server_workflow_executions_revision_id;
+ALTER TABLE public.server_workflow_executions DROP COLUMN IF EXISTS revision_id;
+ALTER TABLE public.server_workflows DROP COLUMN IF EXISTS current_revision_id;
+DROP TABLE IF EXISTS public.server_workflow_revisions;
@@ -0,0 +1,38 @@
+-- Immutable snapshots of a workflow. Every change to a workflow's name,
+-- description or definition appends a revision; rollbacks append a copy of
+-- an older one, so history is never rewritten.
+CREATE TABLE IF NOT EXISTS public.server_workflow_revisions (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    workflow_id uuid NOT NULL REFERENCES public.server_workflows(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    definition JSONB NOT NULL,
+    change_note TEXT,
+    -- Set when this revision restored an older one
+    restored_from uuid REFERENCES public.server_workflow_revisions(id) ON DELETE SET NULL,
+    created_by uuid REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (workflow_id, revision)
+);
+
+ALTER TABLE public.server_workflows
+    ADD COLUMN IF NOT EXISTS current_revision_id uuid REFERENCES public.server_workflow_revisions(id) ON DELETE SET NULL;
+
+-- The definition that ran. Executions from before revisions existed stay NULL.
+ALTER TABLE public.server_workflow_executions
+    ADD COLUMN IF NOT EXISTS revision_id uuid REFERENCES public.server_workflow_revisions(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_server_workflow_executions_revision_id ON public.server_workflow_executions(revision_id);
+
+-- Existing workflows start with their current definition as revision 1
+INSERT INTO public.server_workflow_revisions (workflow_id, revision, name, description, definition, change_note, created_by, created_at)
+SELECT w.id, 1, w.name, w.description, w.definition, 'Initial revision',
+       (SELECT u.id FROM public.users u WHERE u.id = w.created_by), w.updated_at
+FROM public.server_workflows w
+WHERE NOT EXISTS (SELECT 1 FROM public.server_workflow_revisions r WHERE r.workflow_id = w.id);
+
+UPDATE public.server_workflows w
+SET current_revision_id = r.id
+FROM public.server_workflow_revisions r
+WHERE r.workflow_id = w.id AND r.revision = 1 AND w.current_revision_id IS NULL;
@@ -0,0 +1,10 @@
+UPDATE public.server_workflow_executions
+SET status = 'CANCELLED', completed_at = NOW(), error_message = 'Wait cancelled by migration rollback'
+WHERE status = 'WAITING';
+
+ALTER TABLE public.server_workflow_executions DROP CONSTRAINT IF EXISTS server_workflow_executions_status_check;
+ALTER TABLE public.server_workflow_executions
+    ADD CONSTRAINT server_workflow_executions_status_check
+    CHECK (status IN ('RUNNING', 'COMPLETED', 'FAILED', 'CANCELLED'));
+
+DROP TABLE IF EXISTS public.server_workflow_waits;
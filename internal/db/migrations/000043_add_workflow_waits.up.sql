@@ -0,0 +1,23 @@
+-- Executions suspended at a wait_for_event step. The row holds the execution's
+-- context so it can resume after a restart; it is deleted when the execution
+-- resumes.
+CREATE TABLE IF NOT EXISTS public.server_workflow_waits (
+    execution_id uuid PRIMARY KEY,
+    workflow_id uuid NOT NULL REFERENCES public.server_workflows(id) ON DELETE CASCADE,
+    server_id uuid NOT NULL,
+    step_id VARCHAR(255) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    conditions JSONB NOT NULL DEFAULT '[]',
+    definition JSONB NOT NULL,
+    context JSONB NOT NULL,
+    summary JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_server_workflow_waits_workflow_id ON public.server_workflow_waits(workflow_id);
+
+ALTER TABLE public.server_workflow_executions DROP CONSTRAINT IF EXISTS server_workflow_executions_status_check;
+ALTER TABLE public.server_workflow_executions
+    ADD CONSTRAINT server_workflow_executions_status_check
+    CHECK (status IN ('RUNNING', 'WAITING', 'COMPLETED', 'FAILED', 'CANCELLED'));
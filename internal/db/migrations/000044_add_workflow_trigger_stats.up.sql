@@ -0,0 +1,10 @@
+-- Triggers a workflow's concurrency settings kept from starting an execution.
+-- Counts are flushed from memory every few seconds.
+CREATE TABLE IF NOT EXISTS public.server_workflow_trigger_stats (
+    workflow_id uuid PRIMARY KEY REFERENCES public.server_workflows(id) ON DELETE CASCADE,
+    throttled_count BIGINT NOT NULL DEFAULT 0,
+    deduplicated_count BIGINT NOT NULL DEFAULT 0,
+    concurrency_dropped_count BIGINT NOT NULL DEFAULT 0,
+    debounced_count BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
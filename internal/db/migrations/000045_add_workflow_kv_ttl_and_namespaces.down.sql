@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS public.server_workflow_kv_namespace_entries;
+DROP TABLE IF EXISTS public.server_workflow_kv_namespace_grants;
+DROP TABLE IF EXISTS public.server_workflow_kv_namespaces;
+
+DROP INDEX IF EXISTS public.idx_server_workflow_kv_store_expires_at;
+ALTER TABLE public.server_workflow_kv_store DROP COLUMN IF EXISTS expires_at;
@@ -0,0 +1,40 @@
+-- Optional expiry for workflow KV entries. Expired entries are hidden at once
+-- and deleted in the background.
+ALTER TABLE public.server_workflow_kv_store
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_server_workflow_kv_store_expires_at
+    ON public.server_workflow_kv_store(expires_at) WHERE expires_at IS NOT NULL;
+
+-- Server-scoped KV stores that several workflows can share
+CREATE TABLE IF NOT EXISTS public.server_workflow_kv_namespaces (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    server_id uuid NOT NULL REFERENCES public.servers(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (server_id, name)
+);
+
+-- Workflows must be granted read or write access to a namespace
+CREATE TABLE IF NOT EXISTS public.server_workflow_kv_namespace_grants (
+    namespace_id uuid NOT NULL REFERENCES public.server_workflow_kv_namespaces(id) ON DELETE CASCADE,
+    workflow_id uuid NOT NULL REFERENCES public.server_workflows(id) ON DELETE CASCADE,
+    access VARCHAR(10) NOT NULL CHECK (access IN ('read', 'write')),
+    PRIMARY KEY (namespace_id, workflow_id)
+);
+
+CREATE TABLE IF NOT EXISTS public.server_workflow_kv_namespace_entries (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    namespace_id uuid NOT NULL REFERENCES public.server_workflow_kv_namespaces(id) ON DELETE CASCADE,
+    key VARCHAR(255) NOT NULL,
+    value JSONB NOT NULL,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (namespace_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_server_workflow_kv_namespace_entries_expires_at
+    ON public.server_workflow_kv_namespace_entries(expires_at) WHERE expires_at IS NOT NULL;
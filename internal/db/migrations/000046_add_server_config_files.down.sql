@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS public.server_config_file_revisions;
+DROP TABLE IF EXISTS public.server_config_files;
@@ -0,0 +1,28 @@
+-- Managed ServerConfig files. content_hash is the content Aegis last wrote
+-- or adopted; a different hash on the host means the file drifted.
+CREATE TABLE IF NOT EXISTS public.server_config_files (
+    server_id uuid NOT NULL REFERENCES public.servers(id) ON DELETE CASCADE,
+    file_name VARCHAR(64) NOT NULL,
+    content_hash VARCHAR(64) NOT NULL,
+    synced_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    checked_at TIMESTAMPTZ,
+    drift_hash VARCHAR(64),
+    drift_detected_at TIMESTAMPTZ,
+    last_error TEXT,
+    PRIMARY KEY (server_id, file_name)
+);
+
+CREATE TABLE IF NOT EXISTS public.server_config_file_revisions (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    server_id uuid NOT NULL REFERENCES public.servers(id) ON DELETE CASCADE,
+    file_name VARCHAR(64) NOT NULL,
+    content TEXT NOT NULL,
+    content_hash VARCHAR(64) NOT NULL,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('upload', 'restore', 'adopt', 'external')),
+    comment TEXT,
+    created_by uuid REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_server_config_file_revisions_file
+    ON public.server_config_file_revisions(server_id, file_name, created_at DESC);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS public.server_motd_schedules;
+DROP TABLE IF EXISTS public.server_rule_translations;
+
+ALTER TABLE public.server_motd_config
+    DROP COLUMN IF EXISTS active_variant,
+    DROP COLUMN IF EXISTS refresh_interval_minutes,
+    DROP COLUMN IF EXISTS variables,
+    DROP COLUMN IF EXISTS language,
+    DROP COLUMN IF EXISTS template;
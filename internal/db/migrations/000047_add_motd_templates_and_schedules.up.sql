@@ -0,0 +1,37 @@
+-- MOTD templates. A NULL template keeps the prefix, rules and suffix layout.
+-- active_variant is "default" or the ID of the schedule last uploaded.
+ALTER TABLE public.server_motd_config
+    ADD COLUMN IF NOT EXISTS template TEXT,
+    ADD COLUMN IF NOT EXISTS language VARCHAR(16),
+    ADD COLUMN IF NOT EXISTS variables JSONB NOT NULL DEFAULT '{}'::jsonb,
+    ADD COLUMN IF NOT EXISTS refresh_interval_minutes INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS active_variant VARCHAR(64);
+
+-- Rule text in other languages
+CREATE TABLE IF NOT EXISTS public.server_rule_translations (
+    rule_id uuid NOT NULL REFERENCES public.server_rules(id) ON DELETE CASCADE,
+    language VARCHAR(16) NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (rule_id, language)
+);
+
+-- MOTD variants uploaded automatically while their window is open
+CREATE TABLE IF NOT EXISTS public.server_motd_schedules (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    server_id uuid NOT NULL REFERENCES public.servers(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    template TEXT,
+    language VARCHAR(16),
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    recurrence VARCHAR(10) NOT NULL DEFAULT 'once' CHECK (recurrence IN ('once', 'daily', 'weekly')),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_server_motd_schedules_server_id ON public.server_motd_schedules(server_id);
@@ -0,0 +1,3 @@
+ALTER TABLE plugin_instances
+    DROP COLUMN IF EXISTS capability_grants,
+    DROP COLUMN IF EXISTS rcon_policy;
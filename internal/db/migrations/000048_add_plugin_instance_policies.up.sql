@@ -0,0 +1,19 @@
+-- Per-instance RCON command policy: {"mode": "allow"|"deny", "commands": [...]}.
+-- An empty object leaves RCON commands unrestricted.
+-- capability_grants maps each capability a native plugin requests to the
+-- operator's decision (true = granted, false = denied).
+ALTER TABLE plugin_instances
+    ADD COLUMN IF NOT EXISTS rcon_policy JSONB NOT NULL DEFAULT '{}'::jsonb,
+    ADD COLUMN IF NOT EXISTS capability_grants JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+-- Existing native instances already run with every capability their package
+-- requests, so grant those to keep them working after the upgrade.
+UPDATE plugin_instances pi
+SET capability_grants = (
+    SELECT COALESCE(jsonb_object_agg(capability, true), '{}'::jsonb)
+    FROM jsonb_array_elements_text(pp.required_capabilities) AS capability
+    WHERE capability <> 'entrypoint.get_aegis_plugin'
+)
+FROM plugin_packages pp
+WHERE pp.plugin_id = pi.plugin_id
+  AND pp.source = 'native';
@@ -0,0 +1,4 @@
+ALTER TABLE plugin_instances
+    DROP COLUMN IF EXISTS config_migration,
+    DROP COLUMN IF EXISTS config_backup,
+    DROP COLUMN IF EXISTS config_schema_version;
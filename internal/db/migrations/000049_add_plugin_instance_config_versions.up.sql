@@ -0,0 +1,8 @@
+-- Config schema version each plugin instance's config was written for.
+-- Zero covers every config stored before plugins could version their schema.
+-- config_backup holds {"schema_version", "config", "created_at"} from before
+-- the last migration; config_migration records that migration's outcome.
+ALTER TABLE plugin_instances
+    ADD COLUMN IF NOT EXISTS config_schema_version INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS config_backup JSONB,
+    ADD COLUMN IF NOT EXISTS config_migration JSONB;
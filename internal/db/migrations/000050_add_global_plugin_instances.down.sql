@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS plugin_instance_servers;
+
+DELETE FROM plugin_instances WHERE server_id IS NULL;
+
+ALTER TABLE plugin_instances
+    ALTER COLUMN server_id SET NOT NULL;
@@ -0,0 +1,13 @@
+-- Global plugin instances are not bound to one server: server_id is NULL and
+-- plugin_instance_servers lists the servers the instance receives events
+-- from and may act on.
+ALTER TABLE plugin_instances
+    ALTER COLUMN server_id DROP NOT NULL;
+
+CREATE TABLE IF NOT EXISTS plugin_instance_servers (
+    plugin_instance_id UUID NOT NULL REFERENCES plugin_instances(id) ON DELETE CASCADE,
+    server_id UUID NOT NULL REFERENCES servers(id) ON DELETE CASCADE,
+    PRIMARY KEY (plugin_instance_id, server_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_plugin_instance_servers_server_id ON plugin_instance_servers(server_id);
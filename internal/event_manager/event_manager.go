@@ -52,8 +52,21 @@ const (
 	EventTypePlayerStatsUpdated EventType = "PLAYER_STATS_UPDATED"
 
 	// Plugin Events
-	EventTypePluginCustom EventType = "PLUGIN_CUSTOM"
-	EventTypePluginLog    EventType = "PLUGIN_LOG"
+	EventTypePluginCustom      EventType = "PLUGIN_CUSTOM"
+	EventTypePluginLog         EventType = "PLUGIN_LOG"
+	EventTypePluginQuarantined EventType = "PLUGIN_QUARANTINED"
+
+	// Connector Events
+	EventTypeConnectorCustom EventType = "CONNECTOR_CUSTOM"
+
+	// Aegis Events, raised by actions taken in the panel rather than by the
+	// game server. Audit actions that are not tied to a server carry uuid.Nil
+	// as their server ID.
+	EventTypeAegisBanCreated   EventType = "AEGIS_BAN_CREATED"
+	EventTypeAegisBanRemoved   EventType = "AEGIS_BAN_REMOVED"
+	EventTypeAegisAdminAdded   EventType = "AEGIS_ADMIN_ADDED"
+	EventTypeAegisAdminRemoved EventType = "AEGIS_ADMIN_REMOVED"
+	EventTypeAegisAuditAction  EventType = "AEGIS_AUDIT_ACTION"
 )
 
 // Event represents a unified event from any source
@@ -177,6 +190,20 @@ func (em *EventManager) PublishEvent(serverID uuid.UUID, data EventData, rawData
 	}
 }
 
+// PublishAegisBan publishes AEGIS_BAN_CREATED or AEGIS_BAN_REMOVED. Every
+// path that creates or removes a ban calls it, so webhook subscribers see
+// them all. expiresAt is nil for permanent bans. It does nothing on a nil
+// manager.
+func (em *EventManager) PublishAegisBan(serverID uuid.UUID, ban AegisBanData, expiresAt *time.Time) {
+	if em == nil {
+		return
+	}
+	if expiresAt != nil {
+		ban.ExpiresAt = expiresAt.UTC().Format(time.RFC3339)
+	}
+	em.PublishEvent(serverID, ban, nil)
+}
+
 // processEvents processes events from the queue and distributes to subscribers
 func (em *EventManager) processEvents() {
 	log.Info().Msg("Event processor started")
@@ -221,10 +248,16 @@ func (em *EventManager) eventMatchesFilter(event Event, subscriber *EventSubscri
 		return false
 	}
 
+	return subscriber.Filter.Matches(event)
+}
+
+// Matches reports whether an event passes the filter. Empty server IDs or
+// types match everything.
+func (f EventFilter) Matches(event Event) bool {
 	// Check server IDs in filter
-	if len(subscriber.Filter.ServerIDs) > 0 {
+	if len(f.ServerIDs) > 0 {
 		found := false
-		for _, serverID := range subscriber.Filter.ServerIDs {
+		for _, serverID := range f.ServerIDs {
 			if serverID == event.ServerID {
 				found = true
 				break
@@ -235,25 +268,18 @@ func (em *EventManager) eventMatchesFilter(event Event, subscriber *EventSubscri
 		}
 	}
 
-	if (len(subscriber.Filter.Types) == 1 && subscriber.Filter.Types[0] == EventTypeAll) || len(subscriber.Filter.Types) == 0 {
+	if (len(f.Types) == 1 && f.Types[0] == EventTypeAll) || len(f.Types) == 0 {
 		return true
 	}
 
 	// Check event types in filter
-	if len(subscriber.Filter.Types) > 0 {
-		found := false
-		for _, eventType := range subscriber.Filter.Types {
-			if eventType == event.Type {
-				found = true
-				break
-			}
-		}
-		if !found {
-			return false
+	for _, eventType := range f.Types {
+		if eventType == event.Type {
+			return true
 		}
 	}
 
-	return true
+	return false
 }
 
 // GetEventStats returns statistics about the event system
@@ -317,6 +343,37 @@ func (et EventType) IsLogEvent() bool {
 	return !et.IsRconEvent()
 }
 
+// IsAegisEvent checks if the event type was raised by the panel itself
+func (et EventType) IsAegisEvent() bool {
+	switch et {
+	case EventTypeAegisBanCreated, EventTypeAegisBanRemoved, EventTypeAegisAdminAdded,
+		EventTypeAegisAdminRemoved, EventTypeAegisAuditAction:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsKnown checks if the event type is one Squad Aegis publishes. Custom
+// plugin and connector events travel as PLUGIN_CUSTOM and CONNECTOR_CUSTOM.
+func (et EventType) IsKnown() bool {
+	if et.IsRconEvent() || et.IsPlayerTrackerEvent() || et.IsAegisEvent() {
+		return true
+	}
+	switch et {
+	case EventTypeRconServerInfo,
+		EventTypeLogAdminBroadcast, EventTypeLogDeployableDamaged, EventTypeLogPlayerConnected,
+		EventTypeLogPlayerDamaged, EventTypeLogPlayerDied, EventTypeLogPlayerWounded,
+		EventTypeLogPlayerRevived, EventTypeLogPlayerPossess, EventTypeLogPlayerDisconnected,
+		EventTypeLogJoinSucceeded, EventTypeLogTickRate, EventTypeLogGameEventUnified,
+		EventTypePluginCustom, EventTypePluginLog, EventTypePluginQuarantined,
+		EventTypeConnectorCustom:
+		return true
+	default:
+		return false
+	}
+}
+
 // IsPlayerTrackerEvent checks if the event type is a player tracker event
 func (et EventType) IsPlayerTrackerEvent() bool {
 	switch et {
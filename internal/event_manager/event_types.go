@@ -332,6 +332,15 @@ type PluginCustomEventData struct {
 
 func (d PluginCustomEventData) GetEventType() EventType { return EventTypePluginCustom }
 
+// ConnectorCustomEventData represents custom event data from connectors
+type ConnectorCustomEventData struct {
+	ConnectorID string                 `json:"connector_id"`
+	EventType   string                 `json:"event_type"`
+	Data        map[string]interface{} `json:"data"`
+}
+
+func (d ConnectorCustomEventData) GetEventType() EventType { return EventTypeConnectorCustom }
+
 // PluginLogEventData represents log event data from plugins
 type PluginLogEventData struct {
 	PluginInstanceID string                 `json:"plugin_instance_id"`
@@ -345,3 +354,76 @@ type PluginLogEventData struct {
 }
 
 func (d PluginLogEventData) GetEventType() EventType { return EventTypePluginLog }
+
+// PluginQuarantinedEventData is published when a plugin instance is
+// automatically disabled for exceeding a resource threshold
+type PluginQuarantinedEventData struct {
+	PluginInstanceID string `json:"plugin_instance_id"`
+	PluginID         string `json:"plugin_id"`
+	PluginName       string `json:"plugin_name"`
+	Reason           string `json:"reason"`
+}
+
+func (d PluginQuarantinedEventData) GetEventType() EventType { return EventTypePluginQuarantined }
+
+// Aegis Event Data Types
+
+// Where a ban in AegisBanData came from
+const (
+	AegisBanSourcePanel    = "panel"
+	AegisBanSourceRcon     = "rcon"
+	AegisBanSourceImport   = "import"
+	AegisBanSourcePlugin   = "plugin"
+	AegisBanSourceWorkflow = "workflow"
+	AegisBanSourceBanList  = "ban_list"
+)
+
+// AegisBanData represents a ban created or removed on a server, whichever
+// path made it. Publish it with PublishAegisBan.
+type AegisBanData struct {
+	BanID     string `json:"ban_id"`
+	SteamID   string `json:"steam_id,omitempty"`
+	EosID     string `json:"eos_id,omitempty"`
+	Reason    string `json:"reason"`
+	ExpiresAt string `json:"expires_at,omitempty"` // RFC 3339, empty for permanent bans
+	ActorID   string `json:"actor_id,omitempty"`
+	Action    string `json:"action"` // "created" or "removed"
+	Source    string `json:"source"` // One of the AegisBanSource values
+}
+
+func (d AegisBanData) GetEventType() EventType {
+	if d.Action == "removed" {
+		return EventTypeAegisBanRemoved
+	}
+	return EventTypeAegisBanCreated
+}
+
+// AegisAdminData represents a server admin added or removed through the panel
+type AegisAdminData struct {
+	AdminID  string `json:"admin_id"`
+	UserID   string `json:"user_id,omitempty"`
+	SteamID  string `json:"steam_id,omitempty"`
+	EosID    string `json:"eos_id,omitempty"`
+	RoleID   string `json:"role_id"`
+	RoleName string `json:"role_name"`
+	ActorID  string `json:"actor_id,omitempty"`
+	Action   string `json:"action"` // "added" or "removed"
+}
+
+func (d AegisAdminData) GetEventType() EventType {
+	if d.Action == "removed" {
+		return EventTypeAegisAdminRemoved
+	}
+	return EventTypeAegisAdminAdded
+}
+
+// AegisAuditActionData represents an entry written to the audit log
+type AegisAuditActionData struct {
+	AuditLogID string                 `json:"audit_log_id"`
+	Action     string                 `json:"action"`
+	ActorID    string                 `json:"actor_id,omitempty"`
+	Username   string                 `json:"username,omitempty"`
+	Changes    map[string]interface{} `json:"changes,omitempty"`
+}
+
+func (d AegisAuditActionData) GetEventType() EventType { return EventTypeAegisAuditAction }
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+)
+
+// APIToken is a personal access token limited to a set of servers and
+// permission codes. The token secret itself is never stored.
+type APIToken struct {
+	Id          uuid.UUID   `json:"id"`
+	UserId      uuid.UUID   `json:"user_id"`
+	Name        string      `json:"name"`
+	TokenPrefix string      `json:"token_prefix"`
+	ServerIds   []uuid.UUID `json:"server_ids"`
+	Permissions []string    `json:"permissions"`
+	ExpiresAt   time.Time   `json:"expires_at"`
+	LastUsedAt  null.Time   `json:"last_used_at"`
+	LastUsedIp  null.String `json:"last_used_ip"`
+	RevokedAt   null.Time   `json:"revoked_at"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// AllowsServer reports whether the token is scoped to serverId.
+func (t *APIToken) AllowsServer(serverId uuid.UUID) bool {
+	for _, id := range t.ServerIds {
+		if id == serverId {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsPermission reports whether the token was granted permission code.
+func (t *APIToken) AllowsPermission(code string) bool {
+	for _, p := range t.Permissions {
+		if p == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LuaModule is a named Lua library that workflow scripts load with
+// require(). Modules without a server are global.
+type LuaModule struct {
+	ID            uuid.UUID  `json:"id"`
+	ServerID      *uuid.UUID `json:"server_id,omitempty"`
+	Name          string     `json:"name"`
+	Description   *string    `json:"description,omitempty"`
+	LatestVersion int        `json:"latest_version"`
+	CreatedBy     *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// LuaModuleVersion is an immutable revision of a module's source and tests
+type LuaModuleVersion struct {
+	ID         uuid.UUID  `json:"id"`
+	ModuleID   uuid.UUID  `json:"module_id"`
+	Version    int        `json:"version"`
+	Source     string     `json:"source"`
+	TestSource string     `json:"test_source"`
+	CreatedBy  *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// LuaModuleRequest creates a module or saves a new version of one
+type LuaModuleRequest struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	Source      string  `json:"source"`
+	TestSource  string  `json:"test_source"`
+}
+
+// LuaModuleTestRequest runs a module's tests. Source and TestSource default
+// to the latest saved version, so drafts can be tested before saving.
+type LuaModuleTestRequest struct {
+	Source     *string `json:"source,omitempty"`
+	TestSource *string `json:"test_source,omitempty"`
+}
+
+// LuaModuleTestResult is the outcome of one test_* function
+type LuaModuleTestResult struct {
+	Name       string        `json:"name"`
+	Passed     bool          `json:"passed"`
+	Error      string        `json:"error,omitempty"`
+	DurationMs int64         `json:"duration_ms"`
+	Logs       []interface{} `json:"logs"`
+	RconCalls  []interface{} `json:"rcon_calls"`
+}
+
+// LuaModuleTestReport is the outcome of a module test run
+type LuaModuleTestReport struct {
+	Passed bool                  `json:"passed"`
+	Error  string                `json:"error,omitempty"`
+	Tests  []LuaModuleTestResult `json:"tests"`
+}
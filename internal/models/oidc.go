@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OIDCGroupMapping grants a server role, or super admin, to SSO users whose
+// IdP group claim contains GroupName.
+type OIDCGroupMapping struct {
+	Id           uuid.UUID  `json:"id"`
+	GroupName    string     `json:"group_name"`
+	ServerId     *uuid.UUID `json:"server_id"`
+	ServerRoleId *uuid.UUID `json:"server_role_id"`
+	SuperAdmin   bool       `json:"super_admin"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// ExternalIdentity is the identity asserted by an SSO provider for a login.
+type ExternalIdentity struct {
+	Provider string
+	Subject  string
+	Email    string
+	Name     string
+	Username string
+	Groups   []string
+	SteamId  int64
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sources of a config file revision
+const (
+	ConfigRevisionUpload   = "upload"   // Written by Aegis
+	ConfigRevisionRestore  = "restore"  // An earlier revision written again
+	ConfigRevisionAdopt    = "adopt"    // The host's content accepted as the baseline
+	ConfigRevisionExternal = "external" // A change made on the host outside Aegis
+)
+
+// ServerConfigFileState tracks a managed file against the content Aegis
+// last wrote or adopted.
+type ServerConfigFileState struct {
+	ServerID        uuid.UUID  `json:"server_id"`
+	FileName        string     `json:"file_name"`
+	ContentHash     string     `json:"content_hash"`
+	SyncedAt        time.Time  `json:"synced_at"`
+	CheckedAt       *time.Time `json:"checked_at,omitempty"`
+	DriftHash       *string    `json:"drift_hash,omitempty"`
+	DriftDetectedAt *time.Time `json:"drift_detected_at,omitempty"`
+	LastError       *string    `json:"last_error,omitempty"`
+}
+
+// ServerConfigFileRevision is a snapshot of a managed file. Content is left
+// out of revision lists.
+type ServerConfigFileRevision struct {
+	ID          uuid.UUID  `json:"id"`
+	ServerID    uuid.UUID  `json:"server_id"`
+	FileName    string     `json:"file_name"`
+	Content     string     `json:"content,omitempty"`
+	ContentHash string     `json:"content_hash"`
+	Source      string     `json:"source"`
+	Comment     *string    `json:"comment,omitempty"`
+	CreatedBy   *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ServerConfigFileChangeRequest describes new content for a managed file,
+// given either as raw Content or as edits applied to the host's current
+// file. Settings set keys (null removes one) in settings files; Entries
+// replaces the entries of list files. BaseHash, when set, must match the
+// host's current content so concurrent edits are not overwritten.
+type ServerConfigFileChangeRequest struct {
+	Content  *string                `json:"content,omitempty"`
+	Settings map[string]interface{} `json:"settings,omitempty"`
+	Entries  []string               `json:"entries,omitempty"`
+	BaseHash *string                `json:"base_hash,omitempty"`
+	Comment  *string                `json:"comment,omitempty"`
+}
@@ -18,6 +18,13 @@ type ServerMOTDConfig struct {
 	AutoGenerateFromRules   bool `json:"auto_generate_from_rules"`
 	IncludeRuleDescriptions bool `json:"include_rule_descriptions"`
 
+	// Template replaces the prefix, rules and suffix layout when set. Language
+	// selects rule translations; Variables are exposed to the template as .Vars.
+	Template               *string           `json:"template,omitempty"`
+	Language               *string           `json:"language,omitempty"`
+	Variables              map[string]string `json:"variables"`
+	RefreshIntervalMinutes int               `json:"refresh_interval_minutes"`
+
 	// Upload settings
 	UploadEnabled      bool    `json:"upload_enabled"`
 	AutoUploadOnChange bool    `json:"auto_upload_on_change"`
@@ -32,6 +39,7 @@ type ServerMOTDConfig struct {
 	LastUploadedAt       *time.Time `json:"last_uploaded_at,omitempty"`
 	LastUploadError      *string    `json:"last_upload_error,omitempty"`
 	LastGeneratedContent *string    `json:"last_generated_content,omitempty"`
+	ActiveVariant        *string    `json:"active_variant,omitempty"`
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -42,6 +50,9 @@ type ServerMOTDConfigUpdateRequest struct {
 	SuffixText              *string `json:"suffix_text,omitempty"`
 	AutoGenerateFromRules   *bool   `json:"auto_generate_from_rules,omitempty"`
 	IncludeRuleDescriptions *bool   `json:"include_rule_descriptions,omitempty"`
+	Template                *string `json:"template,omitempty"`
+	Language                *string `json:"language,omitempty"`
+	RefreshIntervalMinutes  *int    `json:"refresh_interval_minutes,omitempty"`
 	UploadEnabled           *bool   `json:"upload_enabled,omitempty"`
 	AutoUploadOnChange      *bool   `json:"auto_upload_on_change,omitempty"`
 	UseLogCredentials       *bool   `json:"use_log_credentials,omitempty"`
@@ -50,11 +61,47 @@ type ServerMOTDConfigUpdateRequest struct {
 	UploadUsername          *string `json:"upload_username,omitempty"`
 	UploadPassword          *string `json:"upload_password,omitempty"`
 	UploadProtocol          *string `json:"upload_protocol,omitempty"`
+
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// MOTD schedule recurrences
+const (
+	MOTDRecurrenceOnce   = "once"
+	MOTDRecurrenceDaily  = "daily"
+	MOTDRecurrenceWeekly = "weekly"
+)
+
+// ServerMOTDSchedule is an MOTD variant uploaded while its window is open.
+// A nil Template or Language falls back to the server's MOTD config.
+type ServerMOTDSchedule struct {
+	ID         uuid.UUID `json:"id"`
+	ServerID   uuid.UUID `json:"server_id"`
+	Name       string    `json:"name"`
+	Template   *string   `json:"template,omitempty"`
+	Language   *string   `json:"language,omitempty"`
+	StartsAt   time.Time `json:"starts_at"`
+	EndsAt     time.Time `json:"ends_at"`
+	Recurrence string    `json:"recurrence"`
+	Enabled    bool      `json:"enabled"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type ServerMOTDScheduleRequest struct {
+	Name       string    `json:"name" binding:"required"`
+	Template   *string   `json:"template,omitempty"`
+	Language   *string   `json:"language,omitempty"`
+	StartsAt   time.Time `json:"starts_at" binding:"required"`
+	EndsAt     time.Time `json:"ends_at" binding:"required"`
+	Recurrence string    `json:"recurrence"`
+	Enabled    *bool     `json:"enabled,omitempty"`
 }
 
 type MOTDPreviewResponse struct {
 	Content     string `json:"content"`
 	RulesCount  int    `json:"rules_count"`
+	Variant     string `json:"variant"`
 	GeneratedAt string `json:"generated_at"`
 }
 
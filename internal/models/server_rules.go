@@ -41,3 +41,18 @@ type PlayerRuleViolation struct {
 	AdminUserID   *uuid.UUID `json:"admin_user_id,omitempty"` // Can be empty if violation was automatically triggered
 	CreatedAt     time.Time  `json:"created_at"`
 }
+
+// ServerRuleTranslation is a rule's title and description in another language.
+type ServerRuleTranslation struct {
+	RuleID      uuid.UUID `json:"rule_id"`
+	Language    string    `json:"language"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ServerRuleTranslationRequest struct {
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+}
@@ -8,6 +8,17 @@ import (
 	"github.com/google/uuid"
 )
 
+// Session auth methods record how the session's owner authenticated.
+const (
+	AuthMethodPassword          = "password"
+	AuthMethodPasswordTwoFactor = "password_2fa"
+	AuthMethodOIDC              = "oidc"
+	AuthMethodOIDCTwoFactor     = "oidc_2fa"
+	// AuthMethodAPIToken marks the synthetic session built for a request
+	// authenticated with a personal API token.
+	AuthMethodAPIToken = "api_token"
+)
+
 type Session struct {
 	Id         uuid.UUID `json:"id"`
 	UserId     uuid.UUID `json:"user_id"`
@@ -16,4 +27,5 @@ type Session struct {
 	ExpiresAt  null.Time `json:"expires_at"`
 	LastSeen   time.Time `json:"last_seen"`
 	LastSeenIp string    `json:"last_seen_ip"`
+	AuthMethod string    `json:"auth_method"`
 }
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+)
+
+// UserTwoFactor holds a user's TOTP enrollment. A row with Enabled=false is a
+// pending enrollment that has not yet been confirmed with a valid code.
+type UserTwoFactor struct {
+	UserId       uuid.UUID `json:"user_id"`
+	Secret       string    `json:"-"`
+	Enabled      bool      `json:"enabled"`
+	EnabledAt    null.Time `json:"enabled_at"`
+	LastUsedStep int64     `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// SecurityPolicy holds instance-wide authentication settings managed by super admins.
+type SecurityPolicy struct {
+	RequireTwoFactorForPrivileged bool       `json:"require_two_factor_for_privileged"`
+	UpdatedAt                     time.Time  `json:"updated_at"`
+	UpdatedBy                     *uuid.UUID `json:"updated_by"`
+}
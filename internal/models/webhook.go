@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryDead      = "dead"
+)
+
+// WebhookSubscription delivers every event matching its filter to URL. Empty
+// EventTypes or ServerIds match everything.
+type WebhookSubscription struct {
+	Id          uuid.UUID   `json:"id"`
+	Name        string      `json:"name"`
+	Url         string      `json:"url"`
+	Secret      string      `json:"-"`
+	EventTypes  []string    `json:"event_types"`
+	ServerIds   []uuid.UUID `json:"server_ids"`
+	Enabled     bool        `json:"enabled"`
+	MaxAttempts int         `json:"max_attempts"`
+	CreatedBy   *uuid.UUID  `json:"created_by,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// Filter returns the event filter the subscription matches with.
+func (w *WebhookSubscription) Filter() event_manager.EventFilter {
+	filter := event_manager.EventFilter{ServerIDs: w.ServerIds}
+	for _, eventType := range w.EventTypes {
+		filter.Types = append(filter.Types, event_manager.EventType(eventType))
+	}
+	return filter
+}
+
+// WebhookDelivery is one event queued for one subscription, along with the
+// outcome of its most recent attempt.
+type WebhookDelivery struct {
+	Id             uuid.UUID   `json:"id"`
+	SubscriptionId uuid.UUID   `json:"subscription_id"`
+	EventId        uuid.UUID   `json:"event_id"`
+	EventType      string      `json:"event_type"`
+	ServerId       *uuid.UUID  `json:"server_id,omitempty"`
+	Payload        string      `json:"payload"`
+	Status         string      `json:"status"`
+	Attempts       int         `json:"attempts"`
+	NextAttemptAt  null.Time   `json:"next_attempt_at"`
+	LastAttemptAt  null.Time   `json:"last_attempt_at"`
+	ResponseStatus null.Int    `json:"response_status"`
+	ResponseBody   null.String `json:"response_body"`
+	LastError      null.String `json:"last_error"`
+	DurationMs     null.Int    `json:"duration_ms"`
+	CreatedAt      time.Time   `json:"created_at"`
+	CompletedAt    null.Time   `json:"completed_at"`
+}
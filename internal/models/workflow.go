@@ -19,6 +19,33 @@ type ServerWorkflow struct {
 	CreatedAt   time.Time                `json:"created_at"`
 	UpdatedAt   time.Time                `json:"updated_at"`
 	Variables   []ServerWorkflowVariable `json:"variables,omitempty"`
+	// CurrentRevisionID is the revision matching Definition
+	CurrentRevisionID *uuid.UUID `json:"current_revision_id,omitempty"`
+	Revision          int        `json:"revision"`
+}
+
+// ServerWorkflowRevision is an immutable snapshot of a workflow
+type ServerWorkflowRevision struct {
+	ID           uuid.UUID          `json:"id"`
+	WorkflowID   uuid.UUID          `json:"workflow_id"`
+	Revision     int                `json:"revision"`
+	Name         string             `json:"name"`
+	Description  *string            `json:"description,omitempty"`
+	Definition   WorkflowDefinition `json:"definition"`
+	ChangeNote   *string            `json:"change_note,omitempty"`
+	RestoredFrom *uuid.UUID         `json:"restored_from,omitempty"` // Revision this one rolled back to
+	CreatedBy    *uuid.UUID         `json:"created_by,omitempty"`
+	CreatedAt    time.Time          `json:"created_at"`
+}
+
+// WorkflowRevisionChange is one difference between two revisions. Path uses
+// dot notation; steps and triggers are addressed by ID, e.g.
+// "definition.steps[id=notify].config.message".
+type WorkflowRevisionChange struct {
+	Path string      `json:"path"`
+	Type string      `json:"type"` // "added", "removed" or "changed"
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
 }
 
 // WorkflowDefinition defines the structure of a workflow
@@ -28,6 +55,30 @@ type WorkflowDefinition struct {
 	Variables     map[string]interface{} `json:"variables"` // Default workflow variables
 	Steps         []WorkflowStep         `json:"steps"`     // Ordered list of steps to execute
 	ErrorHandling WorkflowErrorHandling  `json:"error_handling,omitempty"`
+	LuaLimits     *WorkflowLuaLimits     `json:"lua_limits,omitempty"`  // Optional tighter limits for Lua steps
+	Concurrency   *WorkflowConcurrency   `json:"concurrency,omitempty"` // Optional limits on how triggers start executions
+}
+
+// WorkflowConcurrency limits how triggers start executions of a workflow.
+// Zero values disable the corresponding limit.
+type WorkflowConcurrency struct {
+	MaxConcurrent         int `json:"max_concurrent,omitempty"`          // Executions running at once; further triggers are dropped
+	ThrottleCount         int `json:"throttle_count,omitempty"`          // Executions started per throttle window
+	ThrottleWindowSeconds int `json:"throttle_window_seconds,omitempty"` // Length of the throttle window
+	DebounceMs            int `json:"debounce_ms,omitempty"`             // Start once triggers stop for this long, with the latest trigger
+	// DedupKey is a template such as {{trigger_event.attacker_eos}}. A
+	// trigger whose key matches a running execution, or one started within
+	// DedupWindowSeconds, is dropped. Debouncing is per key when it is set.
+	DedupKey           string `json:"dedup_key,omitempty"`
+	DedupWindowSeconds int    `json:"dedup_window_seconds,omitempty"`
+}
+
+// WorkflowLuaLimits lowers the server-wide sandbox limits for this
+// workflow's Lua steps. Zero values keep the server-wide limit.
+type WorkflowLuaLimits struct {
+	MaxInstructions int64 `json:"max_instructions,omitempty"`
+	MaxMemoryMB     int64 `json:"max_memory_mb,omitempty"`
+	TimeoutSeconds  int   `json:"timeout_seconds,omitempty"`
 }
 
 // WorkflowTrigger defines what events trigger this workflow
@@ -79,11 +130,28 @@ type ServerWorkflowExecution struct {
 	ID           uuid.UUID              `json:"id"`
 	WorkflowID   uuid.UUID              `json:"workflow_id"`
 	ExecutionID  uuid.UUID              `json:"execution_id"` // Links to ClickHouse logs
-	Status       string                 `json:"status"`       // "RUNNING", "COMPLETED", "FAILED", "CANCELLED"
+	Status       string                 `json:"status"`       // "RUNNING", "WAITING", "COMPLETED", "FAILED", "CANCELLED"
 	TriggerData  map[string]interface{} `json:"trigger_data"` // Event data that triggered the workflow
 	StartedAt    time.Time              `json:"started_at"`
 	CompletedAt  *time.Time             `json:"completed_at,omitempty"`
 	ErrorMessage *string                `json:"error_message,omitempty"`
+	RevisionID   *uuid.UUID             `json:"revision_id,omitempty"` // Definition that ran
+}
+
+// ServerWorkflowWait is an execution suspended at a wait_for_event step. It
+// holds everything needed to resume the execution, including after a restart.
+type ServerWorkflowWait struct {
+	ExecutionID uuid.UUID                `json:"execution_id"`
+	WorkflowID  uuid.UUID                `json:"workflow_id"`
+	ServerID    uuid.UUID                `json:"server_id"`
+	StepID      string                   `json:"step_id"`
+	EventType   string                   `json:"event_type"`
+	Conditions  []WorkflowCondition      `json:"conditions"` // Values already resolved against the execution
+	Definition  WorkflowDefinition       `json:"definition"` // Definition the execution started with
+	Context     WorkflowExecutionContext `json:"context"`
+	Summary     WorkflowExecutionSummary `json:"summary"`
+	CreatedAt   time.Time                `json:"created_at"`
+	ExpiresAt   time.Time                `json:"expires_at"`
 }
 
 // ServerWorkflowVariable stores dynamic variables for workflows
@@ -119,6 +187,7 @@ const (
 	StepTypeParallel  = "parallel"
 	StepTypeDelay     = "delay"
 	StepTypeLua       = "lua"
+	StepTypeWait      = "wait_for_event"
 )
 
 // Predefined action types
@@ -136,6 +205,7 @@ const (
 	ActionTypeLogMessage            = "log_message"
 	ActionTypeSetVariable           = "set_variable"
 	ActionTypeLuaScript             = "lua_script"
+	ActionTypeCallWorkflow          = "call_workflow"
 )
 
 // Predefined condition operators
@@ -162,6 +232,7 @@ type ServerWorkflowCreateRequest struct {
 	Description *string            `json:"description,omitempty"`
 	Enabled     bool               `json:"enabled"`
 	Definition  WorkflowDefinition `json:"definition" binding:"required"`
+	ChangeNote  *string            `json:"change_note,omitempty"`
 }
 
 type ServerWorkflowUpdateRequest struct {
@@ -169,6 +240,7 @@ type ServerWorkflowUpdateRequest struct {
 	Description *string             `json:"description,omitempty"`
 	Enabled     *bool               `json:"enabled,omitempty"`
 	Definition  *WorkflowDefinition `json:"definition,omitempty"`
+	ChangeNote  *string             `json:"change_note,omitempty"` // Recorded on the new revision
 }
 
 type ServerWorkflowVariableCreateRequest struct {
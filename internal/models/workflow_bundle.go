@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Workflow bundle format identifiers
+const (
+	WorkflowBundleFormat        = "squad-aegis-workflow"
+	WorkflowBundleFormatVersion = 1
+)
+
+// Workflow bundle parameter types
+const (
+	WorkflowBundleParamString  = "string"
+	WorkflowBundleParamNumber  = "number"
+	WorkflowBundleParamBoolean = "boolean"
+)
+
+// WorkflowBundle is a portable export of a workflow. Server-specific values
+// such as rule IDs and webhook URLs are replaced with {{param:name}}
+// placeholders, declared in Parameters and filled in on import.
+type WorkflowBundle struct {
+	Format        string                    `json:"format"`
+	FormatVersion int                       `json:"format_version"`
+	ExportedAt    time.Time                 `json:"exported_at"`
+	Name          string                    `json:"name"`
+	Description   *string                   `json:"description,omitempty"`
+	Definition    WorkflowDefinition        `json:"definition"`
+	Variables     []WorkflowBundleVariable  `json:"variables,omitempty"`
+	KVSeed        map[string]interface{}    `json:"kv_seed,omitempty"` // Initial KV store contents
+	Parameters    []WorkflowBundleParameter `json:"parameters,omitempty"`
+}
+
+// WorkflowBundleVariable is a workflow variable without its server-side IDs
+type WorkflowBundleVariable struct {
+	Name        string                 `json:"name"`
+	Value       map[string]interface{} `json:"value"`
+	Description *string                `json:"description,omitempty"`
+}
+
+// WorkflowBundleParameter declares a value the importer has to supply
+type WorkflowBundleParameter struct {
+	Name        string      `json:"name"`
+	Label       string      `json:"label,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Type        string      `json:"type"` // "string", "number" or "boolean"
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+}
+
+// WorkflowBundleImportRequest imports a bundle onto the route's server and,
+// optionally, further servers.
+type WorkflowBundleImportRequest struct {
+	Bundle     WorkflowBundle         `json:"bundle"`
+	Name       *string                `json:"name,omitempty"` // Overrides the bundle's name
+	Enabled    bool                   `json:"enabled"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	// ServerParameters overrides Parameters for individual servers
+	ServerParameters map[uuid.UUID]map[string]interface{} `json:"server_parameters,omitempty"`
+	ServerIDs        []uuid.UUID                          `json:"server_ids,omitempty"` // Additional target servers
+	IncludeKVSeed    bool                                 `json:"include_kv_seed"`
+}
+
+// WorkflowBundleImportResult is the outcome of importing onto one server
+type WorkflowBundleImportResult struct {
+	ServerID   uuid.UUID  `json:"server_id"`
+	WorkflowID *uuid.UUID `json:"workflow_id,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Access a workflow can be granted to a shared KV namespace
+const (
+	KVAccessRead  = "read"
+	KVAccessWrite = "write"
+)
+
+// WorkflowKVEntry is a key in a workflow's KV store or a shared namespace
+type WorkflowKVEntry struct {
+	Key       string      `json:"key"`
+	Value     interface{} `json:"value"`
+	ExpiresAt *time.Time  `json:"expires_at,omitempty"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// WorkflowKVNamespace is a KV store on a server that the workflows granted
+// access to can share
+type WorkflowKVNamespace struct {
+	ID          uuid.UUID         `json:"id"`
+	ServerID    uuid.UUID         `json:"server_id"`
+	Name        string            `json:"name"`
+	Description *string           `json:"description,omitempty"`
+	Grants      []WorkflowKVGrant `json:"grants"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// WorkflowKVGrant gives a workflow read or write access to a namespace
+type WorkflowKVGrant struct {
+	WorkflowID uuid.UUID `json:"workflow_id"`
+	Access     string    `json:"access"`
+}
+
+// WorkflowKVNamespaceRequest creates or updates a namespace. The name cannot
+// change once created, since scripts open namespaces by name.
+type WorkflowKVNamespaceRequest struct {
+	Name        string            `json:"name"`
+	Description *string           `json:"description,omitempty"`
+	Grants      []WorkflowKVGrant `json:"grants"`
+}
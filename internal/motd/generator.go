@@ -41,6 +41,14 @@ func (g *Generator) GenerateMOTD(config *models.ServerMOTDConfig, rules []models
 	return builder.String()
 }
 
+// RulesText renders rules as a numbered list, the same layout GenerateMOTD
+// uses for its rules section
+func (g *Generator) RulesText(rules []models.ServerRule, includeDescriptions bool) string {
+	var builder strings.Builder
+	g.writeRules(&builder, rules, includeDescriptions, "", 0)
+	return builder.String()
+}
+
 // writeRules recursively writes rules with proper numbering
 func (g *Generator) writeRules(builder *strings.Builder, rules []models.ServerRule, includeDescriptions bool, prefix string, depth int) {
 	for i, rule := range rules {
@@ -0,0 +1,25 @@
+package motd
+
+import (
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+// LocalizeRules returns a copy of rules with titles and descriptions replaced
+// by their translations. Rules without a translation keep their text.
+func LocalizeRules(rules []models.ServerRule, translations map[uuid.UUID]models.ServerRuleTranslation) []models.ServerRule {
+	if len(translations) == 0 {
+		return rules
+	}
+
+	localized := make([]models.ServerRule, len(rules))
+	for i, rule := range rules {
+		if translation, ok := translations[rule.ID]; ok {
+			rule.Title = translation.Title
+			rule.Description = translation.Description
+		}
+		rule.SubRules = LocalizeRules(rule.SubRules, translations)
+		localized[i] = rule
+	}
+	return localized
+}
@@ -0,0 +1,105 @@
+package motd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	data := &TemplateData{
+		ServerName: "Aegis",
+		Rules:      "1. Be nice\n",
+		Vars:       map[string]string{"discord": "discord.gg/aegis"},
+		Seeding:    SeedingStatus{Known: true, PlayerCount: 12, Threshold: 50, IsSeeding: true, Status: "seeding"},
+		TopSeeders: []Seeder{{Name: "A", Hours: 3.25}, {Name: "B", Hours: 2}, {Name: "C", Hours: 1}},
+		RulesFor: func(language string) string {
+			return "1. Sei nett (" + language + ")\n"
+		},
+	}
+
+	text := "{{.ServerName}} {{.Vars.discord}}{{.Vars.missing}}\n" +
+		"{{if .Seeding.IsSeeding}}Seeding {{.Seeding.PlayerCount}}/{{.Seeding.Threshold}}{{end}}\n" +
+		"{{range take 2 .TopSeeders}}{{.Name}}={{hours .Hours}} {{end}}\n" +
+		"{{rules}}{{rules \"de\"}}"
+	got, err := RenderTemplate(text, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Aegis discord.gg/aegis\nSeeding 12/50\nA=3.2 B=2.0 \n1. Be nice\n1. Sei nett (de)\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := ParseTemplate("{{.Rules"); err == nil {
+		t.Error("expected an unterminated action to fail")
+	}
+	if !TemplateUses(text, "TopSeeders") || TemplateUses(text, "NextEvent") {
+		t.Error("unexpected TemplateUses result")
+	}
+}
+
+func TestLocalizeRules(t *testing.T) {
+	parent, child := uuid.New(), uuid.New()
+	rules := []models.ServerRule{{
+		ID:       parent,
+		Title:    "Be nice",
+		SubRules: []models.ServerRule{{ID: child, Title: "No spam", Description: "Keep chat clean"}},
+	}}
+
+	localized := LocalizeRules(rules, map[uuid.UUID]models.ServerRuleTranslation{
+		child: {Title: "Kein Spam", Description: "Chat sauber halten"},
+	})
+	got := NewGenerator().RulesText(localized, true)
+	if want := "1. Be nice\n    1.1. Kein Spam\n        * Chat sauber halten\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if rules[0].SubRules[0].Title != "No spam" {
+		t.Error("expected the original rules to be left unchanged")
+	}
+}
+
+func TestSchedules(t *testing.T) {
+	start := time.Date(2026, 3, 6, 19, 0, 0, 0, time.UTC) // Friday
+	eventNight := models.ServerMOTDSchedule{
+		Name:       "Event night",
+		StartsAt:   start,
+		EndsAt:     start.Add(4 * time.Hour),
+		Recurrence: models.MOTDRecurrenceWeekly,
+		Enabled:    true,
+	}
+	once := models.ServerMOTDSchedule{
+		Name:       "Launch",
+		StartsAt:   start.Add(time.Hour),
+		EndsAt:     start.Add(2 * time.Hour),
+		Recurrence: models.MOTDRecurrenceOnce,
+		Enabled:    true,
+	}
+	schedules := []models.ServerMOTDSchedule{eventNight, once}
+
+	if active, _ := ActiveSchedule(schedules, start.Add(-time.Minute)); active != nil {
+		t.Errorf("expected no active schedule, got %q", active.Name)
+	}
+	if active, _ := ActiveSchedule(schedules, start.Add(90*time.Minute)); active == nil || active.Name != "Launch" {
+		t.Error("expected the most recently opened schedule to win")
+	}
+
+	nextWeek := start.Add(7*24*time.Hour + time.Hour)
+	active, event := ActiveSchedule(schedules, nextWeek)
+	if active == nil || active.Name != "Event night" || !event.StartsAt.Equal(start.Add(7*24*time.Hour)) {
+		t.Errorf("expected the second weekly occurrence to be active, got %+v", event)
+	}
+
+	next := NextEvent(schedules, start.Add(5*time.Hour))
+	if next == nil || !next.StartsAt.Equal(start.Add(7*24*time.Hour)) {
+		t.Errorf("unexpected next event %+v", next)
+	}
+
+	disabled := eventNight
+	disabled.Enabled = false
+	if active, _ := ActiveSchedule([]models.ServerMOTDSchedule{disabled}, start.Add(time.Hour)); active != nil {
+		t.Error("expected disabled schedules to be ignored")
+	}
+}
@@ -0,0 +1,93 @@
+package motd
+
+import (
+	"time"
+
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+// RecurrencePeriod returns how often a schedule repeats, or zero for once
+func RecurrencePeriod(recurrence string) time.Duration {
+	switch recurrence {
+	case models.MOTDRecurrenceDaily:
+		return 24 * time.Hour
+	case models.MOTDRecurrenceWeekly:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+// Occurrence returns the window of schedule that is open at now, or else
+// the next one to open. ok is false once a one-off schedule has ended.
+// Recurring windows repeat at fixed 24h/168h intervals from StartsAt.
+func Occurrence(schedule *models.ServerMOTDSchedule, now time.Time) (start, end time.Time, active, ok bool) {
+	duration := schedule.EndsAt.Sub(schedule.StartsAt)
+	if now.Before(schedule.StartsAt) {
+		return schedule.StartsAt, schedule.EndsAt, false, true
+	}
+
+	period := RecurrencePeriod(schedule.Recurrence)
+	if period == 0 {
+		if now.Before(schedule.EndsAt) {
+			return schedule.StartsAt, schedule.EndsAt, true, true
+		}
+		return time.Time{}, time.Time{}, false, false
+	}
+
+	start = schedule.StartsAt.Add(now.Sub(schedule.StartsAt) / period * period)
+	end = start.Add(duration)
+	if now.Before(end) {
+		return start, end, true, true
+	}
+	start = start.Add(period)
+	return start, start.Add(duration), false, true
+}
+
+// ActiveSchedule returns the enabled schedule whose window is open at now.
+// When windows overlap, the one that opened most recently wins.
+func ActiveSchedule(schedules []models.ServerMOTDSchedule, now time.Time) (*models.ServerMOTDSchedule, *Event) {
+	var active *models.ServerMOTDSchedule
+	var event *Event
+	for i := range schedules {
+		schedule := &schedules[i]
+		if !schedule.Enabled {
+			continue
+		}
+		start, end, isActive, ok := Occurrence(schedule, now)
+		if !ok || !isActive {
+			continue
+		}
+		if event == nil || start.After(event.StartsAt) {
+			active = schedule
+			event = &Event{Name: schedule.Name, StartsAt: start, EndsAt: end}
+		}
+	}
+	return active, event
+}
+
+// NextEvent returns the enabled schedule window that opens soonest after now
+func NextEvent(schedules []models.ServerMOTDSchedule, now time.Time) *Event {
+	var next *Event
+	for i := range schedules {
+		schedule := &schedules[i]
+		if !schedule.Enabled {
+			continue
+		}
+		start, end, isActive, ok := Occurrence(schedule, now)
+		if !ok {
+			continue
+		}
+		if isActive {
+			// The window after the open one
+			period := RecurrencePeriod(schedule.Recurrence)
+			if period == 0 {
+				continue
+			}
+			start, end = start.Add(period), end.Add(period)
+		}
+		if next == nil || start.Before(next.StartsAt) {
+			next = &Event{Name: schedule.Name, StartsAt: start, EndsAt: end}
+		}
+	}
+	return next
+}
@@ -0,0 +1,117 @@
+package motd
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// SeedingStatus is the server's population against the seeding threshold
+type SeedingStatus struct {
+	Known       bool
+	PlayerCount int
+	MaxPlayers  int
+	Threshold   int
+	IsSeeding   bool
+	// Status is "seeding", "live" or "unknown"
+	Status string
+}
+
+// Seeder is a player ranked by qualified seeding time
+type Seeder struct {
+	Name     string
+	PlayerID string
+	Hours    float64
+}
+
+// Event is an occurrence of an MOTD schedule
+type Event struct {
+	Name     string
+	StartsAt time.Time
+	EndsAt   time.Time
+}
+
+// TemplateData is exposed to MOTD templates
+type TemplateData struct {
+	ServerName string
+	Language   string
+	Rules      string
+	RulesCount int
+	Vars       map[string]string
+	Seeding    SeedingStatus
+	TopSeeders []Seeder
+	// ActiveEvent is the schedule being uploaded, NextEvent the next one to open
+	ActiveEvent *Event
+	NextEvent   *Event
+	Now         time.Time
+
+	// RulesFor renders the rules in another language. Nil falls back to Rules.
+	RulesFor func(language string) string
+}
+
+// templateFuncs returns the functions available to templates. The rules
+// function is bound to data so templates can mix languages.
+func templateFuncs(data *TemplateData) template.FuncMap {
+	return template.FuncMap{
+		"rules": func(language ...string) string {
+			if data == nil {
+				return ""
+			}
+			if len(language) == 0 || data.RulesFor == nil {
+				return data.Rules
+			}
+			return data.RulesFor(language[0])
+		},
+		"take": func(n int, seeders []Seeder) []Seeder {
+			if n < 0 {
+				n = 0
+			}
+			if n < len(seeders) {
+				return seeders[:n]
+			}
+			return seeders
+		},
+		"default": func(fallback, value string) string {
+			if value == "" {
+				return fallback
+			}
+			return value
+		},
+		"formatTime": func(layout string, t time.Time) string {
+			return t.Format(layout)
+		},
+		"hours": func(hours float64) string {
+			return fmt.Sprintf("%.1f", hours)
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"trim":  strings.TrimSpace,
+	}
+}
+
+// ParseTemplate checks that text is a valid MOTD template
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("motd").Option("missingkey=zero").Funcs(templateFuncs(nil)).Parse(text)
+}
+
+// RenderTemplate executes an MOTD template against data. Missing variables
+// render as empty strings.
+func RenderTemplate(text string, data *TemplateData) (string, error) {
+	tmpl, err := template.New("motd").Option("missingkey=zero").Funcs(templateFuncs(data)).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, data); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
+
+// TemplateUses reports whether a template references a top-level field, so
+// callers can skip gathering data that will not be rendered
+func TemplateUses(text, field string) bool {
+	return strings.Contains(text, "."+field)
+}
@@ -0,0 +1,231 @@
+// Package oidc implements the OpenID Connect authorization code flow used for
+// single sign-on against an external identity provider such as Keycloak or
+// Authentik.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+var (
+	ErrMissingIDToken = errors.New("token response did not include an id_token")
+	ErrNonceMismatch  = errors.New("id_token nonce does not match")
+	ErrMissingSubject = errors.New("id_token has no subject")
+)
+
+// Config describes how to talk to the identity provider.
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+
+	// Claim names used to read identity attributes out of the ID token.
+	UsernameClaim string
+	GroupsClaim   string
+	SteamIDClaim  string
+
+	// HTTPClient is used for discovery, JWKS and token requests. Nil uses
+	// http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Claims are the identity attributes extracted from a verified ID token.
+type Claims struct {
+	Subject  string
+	Email    string
+	Name     string
+	Username string
+	Groups   []string
+	SteamID  string
+}
+
+// Provider performs discovery lazily so Aegis can start while the IdP is
+// unreachable; a failed discovery is retried on the next login attempt.
+type Provider struct {
+	cfg Config
+
+	mu       sync.Mutex
+	provider *gooidc.Provider
+	verifier *gooidc.IDTokenVerifier
+	oauth    *oauth2.Config
+}
+
+// New creates a provider. No network calls are made until first use.
+func New(cfg Config) *Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{gooidc.ScopeOpenID, "profile", "email"}
+	}
+	if !containsString(cfg.Scopes, gooidc.ScopeOpenID) {
+		cfg.Scopes = append([]string{gooidc.ScopeOpenID}, cfg.Scopes...)
+	}
+	if cfg.UsernameClaim == "" {
+		cfg.UsernameClaim = "preferred_username"
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+	return &Provider{cfg: cfg}
+}
+
+func (p *Provider) clientContext(ctx context.Context) context.Context {
+	if p.cfg.HTTPClient != nil {
+		return gooidc.ClientContext(ctx, p.cfg.HTTPClient)
+	}
+	return ctx
+}
+
+func (p *Provider) ensureDiscovered(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.provider != nil {
+		return nil
+	}
+
+	provider, err := gooidc.NewProvider(p.clientContext(ctx), p.cfg.IssuerURL)
+	if err != nil {
+		return fmt.Errorf("oidc discovery failed: %w", err)
+	}
+
+	p.provider = provider
+	p.verifier = provider.Verifier(&gooidc.Config{ClientID: p.cfg.ClientID})
+	p.oauth = &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  p.cfg.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       p.cfg.Scopes,
+	}
+	return nil
+}
+
+// AuthCodeURL returns the IdP URL the browser is redirected to. state and
+// nonce bind the callback to this request; codeVerifier is the PKCE secret
+// that must be passed back to Exchange.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	if err := p.ensureDiscovered(ctx); err != nil {
+		return "", err
+	}
+	return p.oauth.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier)), nil
+}
+
+// Exchange redeems an authorization code, verifies the returned ID token and
+// extracts the configured claims.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Claims, error) {
+	if err := p.ensureDiscovered(ctx); err != nil {
+		return nil, err
+	}
+
+	ctx = p.clientContext(ctx)
+	token, err := p.oauth.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("oidc code exchange failed: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, ErrMissingIDToken
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("oidc id_token verification failed: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+	if idToken.Subject == "" {
+		return nil, ErrMissingSubject
+	}
+
+	var raw map[string]any
+	if err := idToken.Claims(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode id_token claims: %w", err)
+	}
+
+	return p.claimsFromMap(idToken.Subject, raw), nil
+}
+
+func (p *Provider) claimsFromMap(subject string, raw map[string]any) *Claims {
+	claims := &Claims{
+		Subject:  subject,
+		Email:    stringClaim(raw, "email"),
+		Name:     stringClaim(raw, "name"),
+		Username: stringClaim(raw, p.cfg.UsernameClaim),
+		Groups:   ParseGroups(raw[p.cfg.GroupsClaim]),
+	}
+	if p.cfg.SteamIDClaim != "" {
+		claims.SteamID = stringClaim(raw, p.cfg.SteamIDClaim)
+	}
+	return claims
+}
+
+// ParseGroups normalizes a groups claim, which IdPs emit either as a JSON
+// array of strings or as a single (sometimes comma separated) string.
+func ParseGroups(raw any) []string {
+	var groups []string
+	switch v := raw.(type) {
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				groups = append(groups, strings.TrimSpace(s))
+			}
+		}
+	case []string:
+		for _, s := range v {
+			if strings.TrimSpace(s) != "" {
+				groups = append(groups, strings.TrimSpace(s))
+			}
+		}
+	case string:
+		for _, s := range strings.Split(v, ",") {
+			if strings.TrimSpace(s) != "" {
+				groups = append(groups, strings.TrimSpace(s))
+			}
+		}
+	}
+	return groups
+}
+
+// RandomToken returns a URL-safe random string suitable for state, nonce and
+// PKCE verifier values.
+func RandomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func stringClaim(raw map[string]any, name string) string {
+	if name == "" {
+		return ""
+	}
+	// Numeric claims are ignored on purpose: a 17-digit Steam ID does not
+	// survive a round trip through float64.
+	if v, ok := raw[name].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,252 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mockIdP is a minimal OpenID provider: discovery, JWKS and a token endpoint
+// that issues an RS256 id_token for a single pre-registered code.
+type mockIdP struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	code          string
+	nonce         string
+	codeChallenge string
+	claims        map[string]any
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+
+	idp := &mockIdP{t: t, key: key, code: "test-code"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.handleDiscovery)
+	mux.HandleFunc("/jwks", idp.handleJWKS)
+	mux.HandleFunc("/token", idp.handleToken)
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+func (m *mockIdP) handleDiscovery(w http.ResponseWriter, _ *http.Request) {
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"issuer":                                m.server.URL,
+		"authorization_endpoint":                m.server.URL + "/authorize",
+		"token_endpoint":                        m.server.URL + "/token",
+		"jwks_uri":                              m.server.URL + "/jwks",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (m *mockIdP) handleJWKS(w http.ResponseWriter, _ *http.Request) {
+	pub := m.key.PublicKey
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"keys": []map[string]any{{
+			"kty": "RSA",
+			"alg": "RS256",
+			"use": "sig",
+			"kid": "test",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func (m *mockIdP) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if r.PostForm.Get("code") != m.code {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != m.codeChallenge {
+		http.Error(w, `{"error":"invalid_grant","error_description":"pkce"}`, http.StatusBadRequest)
+		return
+	}
+
+	claims := map[string]any{
+		"iss":   m.server.URL,
+		"aud":   "aegis",
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"nonce": m.nonce,
+	}
+	for k, v := range m.claims {
+		claims[k] = v
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"expires_in":   60,
+		"id_token":     m.sign(claims),
+	})
+}
+
+func (m *mockIdP) sign(claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": "test"})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, m.key, crypto.SHA256, digest[:])
+	if err != nil {
+		m.t.Fatalf("rsa.SignPKCS1v15() error = %v", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// authorize simulates the browser leg: it reads the PKCE challenge and nonce
+// out of the authorization URL the provider built.
+func (m *mockIdP) authorize(t *testing.T, authURL string) {
+	t.Helper()
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+	q := parsed.Query()
+	if got := q.Get("code_challenge_method"); got != "S256" {
+		t.Fatalf("code_challenge_method = %q, want S256", got)
+	}
+	m.codeChallenge = q.Get("code_challenge")
+	m.nonce = q.Get("nonce")
+}
+
+func newTestProvider(idp *mockIdP) *Provider {
+	return New(Config{
+		IssuerURL:     idp.server.URL,
+		ClientID:      "aegis",
+		ClientSecret:  "secret",
+		RedirectURL:   "http://localhost/api/auth/oidc/callback",
+		Scopes:        []string{"profile", "email", "groups"},
+		SteamIDClaim:  "steam_id",
+		HTTPClient:    idp.server.Client(),
+		UsernameClaim: "preferred_username",
+	})
+}
+
+func TestProviderExchangeExtractsClaims(t *testing.T) {
+	idp := newMockIdP(t)
+	idp.claims = map[string]any{
+		"sub":                "user-123",
+		"email":              "alice@example.com",
+		"name":               "Alice",
+		"preferred_username": "alice",
+		"groups":             []string{"/aegis-admins", "moderators"},
+		"steam_id":           "76561198000000001",
+	}
+
+	provider := newTestProvider(idp)
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-verifier-verifier-verifier-verifier")
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+	if !strings.Contains(authURL, "scope=openid+profile+email+groups") {
+		t.Fatalf("AuthCodeURL() = %q, want openid scope prepended", authURL)
+	}
+	idp.authorize(t, authURL)
+
+	claims, err := provider.Exchange(ctx, "test-code", "verifier-verifier-verifier-verifier-verifier", "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+
+	want := &Claims{
+		Subject:  "user-123",
+		Email:    "alice@example.com",
+		Name:     "Alice",
+		Username: "alice",
+		Groups:   []string{"/aegis-admins", "moderators"},
+		SteamID:  "76561198000000001",
+	}
+	if !reflect.DeepEqual(claims, want) {
+		t.Fatalf("Exchange() claims = %+v, want %+v", claims, want)
+	}
+}
+
+func TestProviderExchangeRejectsNonceMismatch(t *testing.T) {
+	idp := newMockIdP(t)
+	idp.claims = map[string]any{"sub": "user-123"}
+
+	provider := newTestProvider(idp)
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-verifier-verifier-verifier-verifier")
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+	idp.authorize(t, authURL)
+
+	_, err = provider.Exchange(ctx, "test-code", "verifier-verifier-verifier-verifier-verifier", "other-nonce")
+	if err != ErrNonceMismatch {
+		t.Fatalf("Exchange() error = %v, want %v", err, ErrNonceMismatch)
+	}
+}
+
+func TestProviderExchangeRejectsWrongVerifier(t *testing.T) {
+	idp := newMockIdP(t)
+	idp.claims = map[string]any{"sub": "user-123"}
+
+	provider := newTestProvider(idp)
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-verifier-verifier-verifier-verifier")
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+	idp.authorize(t, authURL)
+
+	if _, err := provider.Exchange(ctx, "test-code", "a-different-verifier-a-different-verifier", "nonce-1"); err == nil {
+		t.Fatal("Exchange() succeeded with the wrong PKCE verifier")
+	}
+}
+
+func TestParseGroups(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  any
+		want []string
+	}{
+		{name: "array", raw: []any{"a", " b ", "", 3}, want: []string{"a", "b"}},
+		{name: "comma string", raw: "a, b,,c", want: []string{"a", "b", "c"}},
+		{name: "missing", raw: nil, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseGroups(tt.raw); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseGroups() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
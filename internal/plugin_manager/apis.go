@@ -1148,6 +1148,14 @@ type rconAPI struct {
 	clickhouseClient *clickhouse.Client
 	chWarnOnce       sync.Once
 	banSyncFunc      func(ctx context.Context, serverID uuid.UUID) error
+	eventManager     *event_manager.EventManager
+
+	// pluginID and instanceID identify the calling instance in denial
+	// logs. policy returns its current RCON command policy; nil leaves
+	// commands unrestricted.
+	pluginID   string
+	instanceID uuid.UUID
+	policy     func() RconCommandPolicy
 }
 
 func NewRconAPI(serverID uuid.UUID, db *sql.DB, rconManager *rcon_manager.RconManager, clickhouseClient *clickhouse.Client, banSyncFunc func(ctx context.Context, serverID uuid.UUID) error) RconAPI {
@@ -1160,6 +1168,64 @@ func NewRconAPI(serverID uuid.UUID, db *sql.DB, rconManager *rcon_manager.RconMa
 	}
 }
 
+// newInstanceRconAPI returns an RconAPI that enforces a plugin instance's
+// RCON command policy, read live so policy changes apply immediately.
+func (pm *PluginManager) newInstanceRconAPI(serverID, instanceID uuid.UUID, pluginID string) RconAPI {
+	return &rconAPI{
+		serverID:         serverID,
+		db:               pm.db,
+		rconManager:      pm.rconManager,
+		clickhouseClient: pm.clickhouseClient,
+		banSyncFunc:      pm.banSyncFunc,
+		eventManager:     pm.eventManager,
+		pluginID:         pluginID,
+		instanceID:       instanceID,
+		policy: func() RconCommandPolicy {
+			policy, _, _ := pm.instancePolicy(serverID, instanceID)
+			return policy
+		},
+	}
+}
+
+// checkCommand applies the instance's RCON policy to a plugin-initiated
+// command, logging every denial.
+func (api *rconAPI) checkCommand(command string) error {
+	if api.policy == nil {
+		return nil
+	}
+	policy := api.policy()
+	if policy.Allows(command) {
+		return nil
+	}
+
+	name := rconCommandName(command)
+	log.Warn().
+		Str("serverID", api.serverID.String()).
+		Str("instanceID", api.instanceID.String()).
+		Str("pluginID", api.pluginID).
+		Str("command", name).
+		Str("policyMode", policy.Mode).
+		Msg("Denied plugin RCON command by instance policy")
+	return fmt.Errorf("%w: %s", ErrRconCommandDenied, name)
+}
+
+// execute sends a plugin-initiated command if the instance's policy allows it
+func (api *rconAPI) execute(command string) (string, error) {
+	if err := api.checkCommand(command); err != nil {
+		return "", err
+	}
+	return api.rconManager.ExecuteCommand(api.serverID, command)
+}
+
+// checkBan applies the policy to the commands a ban issues before any ban
+// is recorded. Bans are stored by Aegis and enforced with a kick.
+func (api *rconAPI) checkBan() error {
+	if err := api.checkCommand("AdminBan"); err != nil {
+		return err
+	}
+	return api.checkCommand("AdminKick")
+}
+
 func (api *rconAPI) deleteBanRecord(ctx context.Context, banID uuid.UUID) error {
 	result, err := api.db.ExecContext(ctx, `
 		DELETE FROM server_bans
@@ -1216,6 +1282,10 @@ func (api *rconAPI) SendCommand(command string) (string, error) {
 		return "", fmt.Errorf("empty command")
 	}
 
+	if err := api.checkCommand(command); err != nil {
+		return "", err
+	}
+
 	// Execute command via RCON manager
 	response, err := api.rconManager.ExecuteCommand(api.serverID, command)
 	if err != nil {
@@ -1227,7 +1297,7 @@ func (api *rconAPI) SendCommand(command string) (string, error) {
 
 func (api *rconAPI) Broadcast(message string) error {
 	command := fmt.Sprintf("AdminBroadcast %s", utils.SanitizeRCONParam(message))
-	_, err := api.rconManager.ExecuteCommand(api.serverID, command)
+	_, err := api.execute(command)
 	if err != nil {
 		return fmt.Errorf("failed to send broadcast message: %w", err)
 	}
@@ -1236,7 +1306,7 @@ func (api *rconAPI) Broadcast(message string) error {
 
 func (api *rconAPI) SendWarningToPlayer(playerID string, message string) error {
 	command := fmt.Sprintf("AdminWarn \"%s\" %s", utils.SanitizeRCONParam(playerID), utils.SanitizeRCONParam(message))
-	_, err := api.rconManager.ExecuteCommand(api.serverID, command)
+	_, err := api.execute(command)
 	if err != nil {
 		return fmt.Errorf("failed to send warning to player: %w", err)
 	}
@@ -1245,7 +1315,7 @@ func (api *rconAPI) SendWarningToPlayer(playerID string, message string) error {
 
 func (api *rconAPI) KickPlayer(playerID string, reason string) error {
 	command := fmt.Sprintf("AdminKick \"%s\" %s", utils.SanitizeRCONParam(playerID), utils.SanitizeRCONParam(reason))
-	_, err := api.rconManager.ExecuteCommand(api.serverID, command)
+	_, err := api.execute(command)
 	if err != nil {
 		return fmt.Errorf("failed to kick player: %w", err)
 	}
@@ -1254,7 +1324,7 @@ func (api *rconAPI) KickPlayer(playerID string, reason string) error {
 
 func (api *rconAPI) RemovePlayerFromSquad(playerID string) error {
 	command := fmt.Sprintf("AdminRemovePlayerFromSquad \"%s\"", utils.SanitizeRCONParam(playerID))
-	_, err := api.rconManager.ExecuteCommand(api.serverID, command)
+	_, err := api.execute(command)
 	if err != nil {
 		return fmt.Errorf("failed to remove player from squad: %w", err)
 	}
@@ -1263,7 +1333,7 @@ func (api *rconAPI) RemovePlayerFromSquad(playerID string) error {
 
 func (api *rconAPI) RemovePlayerFromSquadById(playerID string) error {
 	command := fmt.Sprintf("AdminRemovePlayerFromSquadById \"%s\"", utils.SanitizeRCONParam(playerID))
-	_, err := api.rconManager.ExecuteCommand(api.serverID, command)
+	_, err := api.execute(command)
 	if err != nil {
 		return fmt.Errorf("failed to remove player from squad: %w", err)
 	}
@@ -1271,6 +1341,10 @@ func (api *rconAPI) RemovePlayerFromSquadById(playerID string) error {
 }
 
 func (api *rconAPI) BanPlayer(playerID string, reason string, duration time.Duration) error {
+	if err := api.checkBan(); err != nil {
+		return err
+	}
+
 	// Compute expires_at from duration
 	var expiresAt *time.Time
 	if duration > 0 {
@@ -1279,7 +1353,7 @@ func (api *rconAPI) BanPlayer(playerID string, reason string, duration time.Dura
 	}
 
 	// Store ban in database first
-	banID, err := api.storeBanInDatabase(playerID, reason, expiresAt)
+	banID, identifiers, err := api.storeBanInDatabase(playerID, reason, expiresAt)
 	if err != nil {
 		log.Error().Err(err).Str("playerID", playerID).Msg("Failed to store ban in database")
 		return fmt.Errorf("failed to store ban: %w", err)
@@ -1288,10 +1362,11 @@ func (api *rconAPI) BanPlayer(playerID string, reason string, duration time.Dura
 	if err := api.syncBanConfigOrRollback(context.Background(), banID, "plugin ban"); err != nil {
 		return err
 	}
+	api.publishBanCreated(banID, identifiers.SteamID, identifiers.EOSID, reason, expiresAt)
 
 	// Kick player for immediate enforcement
 	command := fmt.Sprintf("AdminKick \"%s\" %s", utils.SanitizeRCONParam(playerID), utils.SanitizeRCONParam(reason))
-	_, err = api.rconManager.ExecuteCommand(api.serverID, command)
+	_, err = api.execute(command)
 	if err != nil {
 		return fmt.Errorf("failed to kick player: %w", err)
 	}
@@ -1320,6 +1395,10 @@ func mapPluginEventTypeToEvidence(eventType string) (evidenceType string, clickh
 // banWithEvidence bans a player and links evidence from an event, optionally
 // persisting an associated server rule on the created ban and extra evidence metadata.
 func (api *rconAPI) banWithEvidence(playerID string, reason string, duration time.Duration, eventID string, eventType string, ruleID *string, extraMetadata map[string]interface{}) (string, error) {
+	if err := api.checkBan(); err != nil {
+		return "", err
+	}
+
 	// Compute expires_at from duration
 	var expiresAt *time.Time
 	if duration > 0 {
@@ -1342,10 +1421,13 @@ func (api *rconAPI) banWithEvidence(playerID string, reason string, duration tim
 	// Detect player ID type (Steam ID or EOS ID)
 	var steamIDVal interface{}
 	var eosIDVal interface{}
+	var bannedSteamID, bannedEOSID string
 	if sid, err := strconv.ParseInt(playerID, 10, 64); err == nil {
 		steamIDVal = sid
+		bannedSteamID = strconv.FormatInt(sid, 10)
 	} else if normalizedEOSID := utils.NormalizeEOSID(playerID); utils.IsEOSID(normalizedEOSID) {
 		eosIDVal = normalizedEOSID
+		bannedEOSID = normalizedEOSID
 	} else {
 		return "", fmt.Errorf("invalid player ID format: must be a numeric Steam ID or 32-char hex EOS ID")
 	}
@@ -1427,10 +1509,11 @@ func (api *rconAPI) banWithEvidence(playerID string, reason string, duration tim
 	if err := api.syncBanConfigOrRollback(context.Background(), banID, "evidence ban"); err != nil {
 		return "", err
 	}
+	api.publishBanCreated(banID, bannedSteamID, bannedEOSID, reason, expiresAt)
 
 	// Kick player for immediate enforcement
 	kickCommand := fmt.Sprintf("AdminKick \"%s\" %s", utils.SanitizeRCONParam(playerID), utils.SanitizeRCONParam(reason))
-	_, _ = api.rconManager.ExecuteCommand(api.serverID, kickCommand)
+	_, _ = api.execute(kickCommand)
 
 	return banID.String(), nil
 }
@@ -1440,16 +1523,30 @@ func (api *rconAPI) BanWithEvidence(playerID string, reason string, duration tim
 	return api.banWithEvidence(playerID, reason, duration, eventID, eventType, nil, nil)
 }
 
-// storeBanInDatabase stores the ban information in the database
-func (api *rconAPI) storeBanInDatabase(playerID string, reason string, expiresAt *time.Time) (uuid.UUID, error) {
+// publishBanCreated announces a plugin-initiated ban once it has been
+// stored and synced to Bans.cfg.
+func (api *rconAPI) publishBanCreated(banID uuid.UUID, steamID, eosID, reason string, expiresAt *time.Time) {
+	api.eventManager.PublishAegisBan(api.serverID, event_manager.AegisBanData{
+		BanID:   banID.String(),
+		SteamID: steamID,
+		EosID:   eosID,
+		Reason:  reason,
+		Action:  "created",
+		Source:  event_manager.AegisBanSourcePlugin,
+	}, expiresAt)
+}
+
+// storeBanInDatabase stores the ban information in the database and returns
+// the identifiers it was stored under.
+func (api *rconAPI) storeBanInDatabase(playerID string, reason string, expiresAt *time.Time) (uuid.UUID, utils.PlayerIdentifiers, error) {
 	identifiers, err := api.resolvePlayerIdentifiers(playerID)
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, identifiers, err
 	}
 
 	steamIDVal, eosIDVal, err := identifiers.DatabaseArgs()
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("failed to parse player identifiers: %w", err)
+		return uuid.Nil, identifiers, fmt.Errorf("failed to parse player identifiers: %w", err)
 	}
 
 	// Insert ban into database
@@ -1472,10 +1569,10 @@ func (api *rconAPI) storeBanInDatabase(playerID string, reason string, expiresAt
 		now,          // updated_at
 	)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("failed to insert ban into database: %w", err)
+		return uuid.Nil, identifiers, fmt.Errorf("failed to insert ban into database: %w", err)
 	}
 
-	return banID, nil
+	return banID, identifiers, nil
 }
 
 // logPluginRuleViolation logs a rule violation to ClickHouse for player history tracking
@@ -1635,6 +1732,11 @@ type eventAPI struct {
 	pluginInstanceID uuid.UUID
 	pluginName       string
 	eventManager     *event_manager.EventManager
+
+	// subscribe and unsubscribe change the instance's runtime event
+	// subscriptions. They are nil when the API is not bound to an instance.
+	subscribe   func(eventTypes []string) ([]string, error)
+	unsubscribe func(eventTypes []string) ([]string, error)
 }
 
 func NewEventAPI(ctx context.Context, serverID uuid.UUID, pluginInstanceID uuid.UUID, pluginName string, eventManager *event_manager.EventManager) EventAPI {
@@ -1711,6 +1813,20 @@ func (api *eventAPI) SubscribeToEvents(eventTypes []string, handler func(*Plugin
 	return nil
 }
 
+func (api *eventAPI) AddEventSubscriptions(eventTypes []string) ([]string, error) {
+	if api.subscribe == nil {
+		return nil, fmt.Errorf("runtime event subscriptions are not available")
+	}
+	return api.subscribe(eventTypes)
+}
+
+func (api *eventAPI) RemoveEventSubscriptions(eventTypes []string) ([]string, error) {
+	if api.unsubscribe == nil {
+		return nil, fmt.Errorf("runtime event subscriptions are not available")
+	}
+	return api.unsubscribe(eventTypes)
+}
+
 // logAPI implements LogAPI interface
 type logAPI struct {
 	serverID         uuid.UUID
@@ -50,6 +50,8 @@ func TestCreatePluginAPIsScopesNativeManifestCapabilities(t *testing.T) {
 	t.Parallel()
 
 	const pluginID = "com.example.scoped"
+	serverID := uuid.New()
+	instanceID := uuid.New()
 	pm := &PluginManager{
 		registry:          NewPluginRegistry(),
 		connectorRegistry: NewConnectorRegistry(),
@@ -60,7 +62,22 @@ func TestCreatePluginAPIsScopesNativeManifestCapabilities(t *testing.T) {
 				Name:                 "Scoped Native Plugin",
 				Source:               PluginSourceNative,
 				InstallState:         PluginInstallStateReady,
-				RequiredCapabilities: []string{NativePluginCapabilityAPILog},
+				RequiredCapabilities: []string{NativePluginCapabilityAPILog, NativePluginCapabilityAPIRCON},
+			},
+		},
+		plugins: map[uuid.UUID]map[uuid.UUID]*PluginInstance{
+			serverID: {
+				instanceID: {
+					ID:       instanceID,
+					ServerID: serverID,
+					PluginID: pluginID,
+					CapabilityGrants: map[string]bool{
+						NativePluginCapabilityAPILog:  true,
+						NativePluginCapabilityAPIRCON: false,
+						// Granted but not requested by the package
+						NativePluginCapabilityAPIServer: true,
+					},
+				},
 			},
 		},
 	}
@@ -72,9 +89,9 @@ func TestCreatePluginAPIsScopesNativeManifestCapabilities(t *testing.T) {
 		t.Fatalf("RegisterPlugin() error = %v", err)
 	}
 
-	apis := pm.createPluginAPIs(context.Background(), uuid.New(), uuid.New(), "Scoped Native Plugin", pluginID, "info")
+	apis := pm.createPluginAPIs(context.Background(), serverID, instanceID, "Scoped Native Plugin", pluginID, "info")
 	if apis.LogAPI == nil {
-		t.Fatal("LogAPI = nil, want exposed api.log capability")
+		t.Fatal("LogAPI = nil, want granted api.log capability")
 	}
 	if apis.ServerAPI != nil ||
 		apis.DatabaseAPI != nil ||
@@ -82,9 +99,16 @@ func TestCreatePluginAPIsScopesNativeManifestCapabilities(t *testing.T) {
 		apis.RconAPI != nil ||
 		apis.AdminAPI != nil ||
 		apis.EventAPI != nil ||
+		apis.HistoryAPI != nil ||
 		apis.DiscordAPI != nil ||
 		apis.ConnectorAPI != nil {
-		t.Fatalf("createPluginAPIs() exposed undeclared native capabilities: %+v", apis)
+		t.Fatalf("createPluginAPIs() exposed undeclared or ungranted native capabilities: %+v", apis)
+	}
+
+	// Without a grant decision nothing is exposed
+	other := pm.createPluginAPIs(context.Background(), serverID, uuid.New(), "Scoped Native Plugin", pluginID, "info")
+	if other.LogAPI != nil {
+		t.Fatal("LogAPI exposed to an instance without capability grants")
 	}
 }
 
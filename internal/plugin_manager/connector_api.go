@@ -96,11 +96,6 @@ func (pm *PluginManager) shouldExposePluginAPI(pluginID string, capability strin
 	return false
 }
 
-// shouldExposeConnectorAPI is true for bundled plugins; native plugins must declare api.connector in their manifest target.
-func (pm *PluginManager) shouldExposeConnectorAPI(pluginID string) bool {
-	return pm.shouldExposePluginAPI(pluginID, NativePluginCapabilityAPIConnector)
-}
-
 // ResolveConnectorInstanceKey maps a canonical or legacy connector ref to
 // the key used in pm.connectors. Registry lookup happens outside
 // connectorMu; probes run under a single connectorMu.RLock so we take one
@@ -2,6 +2,7 @@ package plugin_manager
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -14,7 +15,8 @@ import (
 
 func (pm *PluginManager) loadPluginsFromDatabase() error {
 	query := `
-		SELECT id, server_id, plugin_id, notes, config, enabled, log_level, created_at, updated_at
+		SELECT id, server_id, plugin_id, notes, config, config_schema_version, config_backup, config_migration,
+			enabled, log_level, rcon_policy, capability_grants, created_at, updated_at
 		FROM plugin_instances
 		ORDER BY created_at
 	`
@@ -25,18 +27,29 @@ func (pm *PluginManager) loadPluginsFromDatabase() error {
 	}
 	defer rows.Close()
 
+	// Global instances are hydrated once every row has been read, so their
+	// server scopes can be loaded first.
+	var globals []*PluginInstance
+
 	for rows.Next() {
-		var instance PluginInstance
+		instance := &PluginInstance{}
+		var serverID uuid.NullUUID
 		var configJSON string
+		var policyJSON, grantsJSON, backupJSON, migrationJSON []byte
 
 		err := rows.Scan(
 			&instance.ID,
-			&instance.ServerID,
+			&serverID,
 			&instance.PluginID,
 			&instance.Notes,
 			&configJSON,
+			&instance.ConfigSchemaVersion,
+			&backupJSON,
+			&migrationJSON,
 			&instance.Enabled,
 			&instance.LogLevel,
+			&policyJSON,
+			&grantsJSON,
 			&instance.CreatedAt,
 			&instance.UpdatedAt,
 		)
@@ -44,6 +57,8 @@ func (pm *PluginManager) loadPluginsFromDatabase() error {
 			log.Error().Err(err).Msg("Failed to scan plugin instance row")
 			continue
 		}
+		instance.ServerID = serverID.UUID
+		instance.Global = !serverID.Valid
 
 		// Parse config JSON
 		if err := json.Unmarshal([]byte(configJSON), &instance.Config); err != nil {
@@ -54,7 +69,46 @@ func (pm *PluginManager) loadPluginsFromDatabase() error {
 			continue
 		}
 
-		if err := pm.hydratePluginInstanceFromDatabase(&instance); err != nil {
+		// An unreadable policy or grant set fails closed: the instance
+		// loads with every command denied and no capabilities granted.
+		if err := json.Unmarshal(policyJSON, &instance.RconPolicy); err != nil {
+			log.Error().
+				Str("instanceID", instance.ID.String()).
+				Err(err).
+				Msg("Failed to parse plugin instance RCON policy")
+			instance.RconPolicy = RconCommandPolicy{Mode: RconPolicyModeAllow}
+		}
+		if err := json.Unmarshal(grantsJSON, &instance.CapabilityGrants); err != nil {
+			log.Error().
+				Str("instanceID", instance.ID.String()).
+				Err(err).
+				Msg("Failed to parse plugin instance capability grants")
+			instance.CapabilityGrants = nil
+		}
+		if len(backupJSON) > 0 {
+			if err := json.Unmarshal(backupJSON, &instance.ConfigBackup); err != nil {
+				log.Error().
+					Str("instanceID", instance.ID.String()).
+					Err(err).
+					Msg("Failed to parse plugin instance config backup")
+				instance.ConfigBackup = nil
+			}
+		}
+		if len(migrationJSON) > 0 {
+			if err := json.Unmarshal(migrationJSON, &instance.ConfigMigration); err != nil {
+				log.Error().
+					Str("instanceID", instance.ID.String()).
+					Err(err).
+					Msg("Failed to parse plugin instance config migration")
+				instance.ConfigMigration = nil
+			}
+		}
+
+		if instance.Global {
+			globals = append(globals, instance)
+			continue
+		}
+		if err := pm.hydratePluginInstanceFromDatabase(instance); err != nil {
 			log.Error().
 				Str("instanceID", instance.ID.String()).
 				Str("pluginID", instance.PluginID).
@@ -67,10 +121,83 @@ func (pm *PluginManager) loadPluginsFromDatabase() error {
 	if err := rows.Err(); err != nil {
 		return fmt.Errorf("failed to iterate plugin instances: %w", err)
 	}
+	if len(globals) == 0 {
+		return nil
+	}
+	rows.Close()
+
+	scopes, err := pm.loadPluginInstanceServers()
+	if err != nil {
+		return err
+	}
+	for _, instance := range globals {
+		instance.ServerIDs = scopes[instance.ID]
+		if err := pm.hydratePluginInstanceFromDatabase(instance); err != nil {
+			log.Error().
+				Str("instanceID", instance.ID.String()).
+				Str("pluginID", instance.PluginID).
+				Err(err).
+				Msg("Failed to hydrate global plugin instance from database")
+		}
+	}
 
 	return nil
 }
 
+// loadPluginInstanceServers returns the server scope of every global plugin
+// instance, keyed by instance ID.
+func (pm *PluginManager) loadPluginInstanceServers() (map[uuid.UUID][]uuid.UUID, error) {
+	rows, err := pm.db.Query(`
+		SELECT plugin_instance_id, server_id
+		FROM plugin_instance_servers
+		ORDER BY plugin_instance_id, server_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query plugin instance servers: %w", err)
+	}
+	defer rows.Close()
+
+	scopes := make(map[uuid.UUID][]uuid.UUID)
+	for rows.Next() {
+		var instanceID, serverID uuid.UUID
+		if err := rows.Scan(&instanceID, &serverID); err != nil {
+			return nil, fmt.Errorf("failed to scan plugin instance server: %w", err)
+		}
+		scopes[instanceID] = append(scopes[instanceID], serverID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate plugin instance servers: %w", err)
+	}
+	return scopes, nil
+}
+
+// savePluginInstanceServers replaces the server scope of a global plugin
+// instance.
+func (pm *PluginManager) savePluginInstanceServers(instanceID uuid.UUID, serverIDs []uuid.UUID) error {
+	tx, err := pm.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM plugin_instance_servers WHERE plugin_instance_id = $1", instanceID); err != nil {
+		return fmt.Errorf("failed to clear plugin instance servers: %w", err)
+	}
+	if err := insertPluginInstanceServers(tx, instanceID, serverIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertPluginInstanceServers(tx *sql.Tx, instanceID uuid.UUID, serverIDs []uuid.UUID) error {
+	for _, serverID := range serverIDs {
+		if _, err := tx.Exec("INSERT INTO plugin_instance_servers (plugin_instance_id, server_id) VALUES ($1, $2)", instanceID, serverID); err != nil {
+			return fmt.Errorf("failed to insert plugin instance server: %w", err)
+		}
+	}
+	return nil
+}
+
 func (pm *PluginManager) hydratePluginInstanceFromDatabase(instance *PluginInstance) error {
 	definition, err := pm.registry.GetPlugin(instance.PluginID)
 	if err != nil {
@@ -85,7 +212,8 @@ func (pm *PluginManager) hydratePluginInstanceFromDatabase(instance *PluginInsta
 		return nil
 	}
 
-	pm.applyPluginDefinitionMetadata(instance, pm.enrichPluginDefinition(*definition))
+	enrichedDefinition := pm.enrichPluginDefinition(*definition)
+	pm.applyPluginDefinitionMetadata(instance, enrichedDefinition)
 
 	if err := pm.ensurePluginInstanceRuntime(instance); err != nil {
 		pm.markPluginInstanceUnavailable(instance, err)
@@ -100,6 +228,26 @@ func (pm *PluginManager) hydratePluginInstanceFromDatabase(instance *PluginInsta
 		return nil
 	}
 
+	// A config that cannot be migrated keeps its stored values and the
+	// instance stays stopped until an operator fixes it.
+	if err := pm.migratePluginInstanceConfig(instance, enrichedDefinition); err != nil {
+		pm.ensurePluginInstanceContext(instance)
+		if instance.Enabled {
+			instance.setError(PluginStatusError, err.Error())
+		} else {
+			instance.setError(PluginStatusDisabled, err.Error())
+		}
+		pm.storeLoadedPluginInstance(instance)
+
+		log.Error().
+			Str("serverID", instance.ServerID.String()).
+			Str("instanceID", instance.ID.String()).
+			Str("pluginID", instance.PluginID).
+			Err(err).
+			Msg("Failed to migrate plugin instance config")
+		return nil
+	}
+
 	instance.setStatus(PluginStatusStopped)
 	pm.storeLoadedPluginInstance(instance)
 
@@ -210,20 +358,44 @@ func (pm *PluginManager) savePluginInstanceToDatabase(instance *PluginInstance)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
+	policyJSON, grantsJSON, err := marshalPluginInstancePolicy(instance)
+	if err != nil {
+		return err
+	}
+	backupJSON, migrationJSON, err := marshalPluginInstanceConfigMigration(instance)
+	if err != nil {
+		return err
+	}
 
 	query := `
-		INSERT INTO plugin_instances (id, server_id, plugin_id, notes, config, enabled, log_level, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO plugin_instances (id, server_id, plugin_id, notes, config, config_schema_version, config_backup, config_migration,
+			enabled, log_level, rcon_policy, capability_grants, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
 
-	_, err = pm.db.Exec(query,
+	// Global instances are stored without a server; their scope lives in
+	// plugin_instance_servers.
+	serverID := uuid.NullUUID{UUID: instance.ServerID, Valid: !instance.Global}
+
+	tx, err := pm.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(query,
 		instance.ID,
-		instance.ServerID,
+		serverID,
 		instance.PluginID,
 		instance.Notes,
 		string(configJSON),
+		instance.ConfigSchemaVersion,
+		backupJSON,
+		migrationJSON,
 		instance.Enabled,
 		instance.LogLevel,
+		string(policyJSON),
+		string(grantsJSON),
 		instance.CreatedAt,
 		instance.UpdatedAt,
 	)
@@ -231,8 +403,13 @@ func (pm *PluginManager) savePluginInstanceToDatabase(instance *PluginInstance)
 	if err != nil {
 		return fmt.Errorf("failed to insert plugin instance: %w", err)
 	}
+	if instance.Global {
+		if err := insertPluginInstanceServers(tx, instance.ID, instance.ServerIDs); err != nil {
+			return err
+		}
+	}
 
-	return nil
+	return tx.Commit()
 }
 
 func (pm *PluginManager) updatePluginInstanceInDatabase(instance *PluginInstance) error {
@@ -240,10 +417,19 @@ func (pm *PluginManager) updatePluginInstanceInDatabase(instance *PluginInstance
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
+	policyJSON, grantsJSON, err := marshalPluginInstancePolicy(instance)
+	if err != nil {
+		return err
+	}
+	backupJSON, migrationJSON, err := marshalPluginInstanceConfigMigration(instance)
+	if err != nil {
+		return err
+	}
 
 	query := `
 		UPDATE plugin_instances
-		SET notes = $2, config = $3, enabled = $4, log_level = $5, updated_at = $6
+		SET notes = $2, config = $3, config_schema_version = $4, config_backup = $5, config_migration = $6,
+			enabled = $7, log_level = $8, rcon_policy = $9, capability_grants = $10, updated_at = $11
 		WHERE id = $1
 	`
 
@@ -251,8 +437,13 @@ func (pm *PluginManager) updatePluginInstanceInDatabase(instance *PluginInstance
 		instance.ID,
 		instance.Notes,
 		string(configJSON),
+		instance.ConfigSchemaVersion,
+		backupJSON,
+		migrationJSON,
 		instance.Enabled,
 		instance.LogLevel,
+		string(policyJSON),
+		string(grantsJSON),
 		instance.UpdatedAt,
 	)
 
@@ -373,6 +564,10 @@ func (pm *PluginManager) initializeConnectorInstance(instance *ConnectorInstance
 
 	instance.setStatus(ConnectorStatusStarting)
 
+	if receiver, ok := instance.Connector.(hostAPIConnector); ok {
+		receiver.setHostAPI(pm.newConnectorHostAPI(instance.Connector.GetDefinition()))
+	}
+
 	// Initialize connector (panic-safe so a crashing native connector cannot
 	// take down the manager).
 	if err := safePluginCall(instance.ID, "Connector.Initialize", func() error {
@@ -433,6 +628,13 @@ func (pm *PluginManager) attachConnectorUnexpectedExitReporter(instance *Connect
 	})
 }
 
+// hostAPIConnector is implemented by native connectors, which reach the
+// host through a ConnectorHostAPI server scoped to their declared
+// capabilities.
+type hostAPIConnector interface {
+	setHostAPI(api *connectorHostAPI)
+}
+
 // killableConnector is implemented by subprocess-isolated connectors that can
 // SIGKILL their backing process when a graceful Stop() hangs. In-process
 // connectors do not implement this and are skipped.
@@ -786,3 +988,40 @@ func (pm *PluginManager) DeleteConnectorInstance(connectorID string) error {
 
 	return nil
 }
+
+func marshalPluginInstancePolicy(instance *PluginInstance) ([]byte, []byte, error) {
+	policyJSON, err := json.Marshal(instance.RconPolicy)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal rcon policy: %w", err)
+	}
+	grants := instance.CapabilityGrants
+	if grants == nil {
+		grants = map[string]bool{}
+	}
+	grantsJSON, err := json.Marshal(grants)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal capability grants: %w", err)
+	}
+	return policyJSON, grantsJSON, nil
+}
+
+// marshalPluginInstanceConfigMigration encodes the config backup and
+// migration outcome; either is nil (SQL NULL) when the instance has none.
+func marshalPluginInstanceConfigMigration(instance *PluginInstance) (interface{}, interface{}, error) {
+	var backupJSON, migrationJSON interface{}
+	if instance.ConfigBackup != nil {
+		encoded, err := json.Marshal(instance.ConfigBackup)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal config backup: %w", err)
+		}
+		backupJSON = string(encoded)
+	}
+	if instance.ConfigMigration != nil {
+		encoded, err := json.Marshal(instance.ConfigMigration)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal config migration: %w", err)
+		}
+		migrationJSON = string(encoded)
+	}
+	return backupJSON, migrationJSON, nil
+}
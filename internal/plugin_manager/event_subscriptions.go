@@ -0,0 +1,161 @@
+package plugin_manager
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
+)
+
+// maxPluginEventSubscriptions caps the runtime subscriptions one instance
+// may hold.
+const maxPluginEventSubscriptions = 64
+
+var pluginEventTypePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,63}$`)
+
+// newInstanceEventAPI builds an EventAPI whose runtime subscriptions apply
+// to the given instance.
+func (pm *PluginManager) newInstanceEventAPI(ctx context.Context, serverID, instanceID uuid.UUID, pluginName string) EventAPI {
+	return &eventAPI{
+		ctx:              ctx,
+		serverID:         serverID,
+		pluginInstanceID: instanceID,
+		pluginName:       pluginName,
+		eventManager:     pm.eventManager,
+		subscribe: func(eventTypes []string) ([]string, error) {
+			return pm.addPluginEventSubscriptions(serverID, instanceID, eventTypes)
+		},
+		unsubscribe: func(eventTypes []string) ([]string, error) {
+			return pm.removePluginEventSubscriptions(serverID, instanceID, eventTypes)
+		},
+	}
+}
+
+// addPluginEventSubscriptions validates and adds runtime event
+// subscriptions for an instance. Nothing is added unless every type is
+// accepted. Native plugins may only subscribe to event families they were
+// granted.
+func (pm *PluginManager) addPluginEventSubscriptions(serverID, instanceID uuid.UUID, eventTypes []string) ([]string, error) {
+	types, err := parsePluginEventTypes(eventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	instance, err := pm.lookupPluginInstanceLocked(serverID, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if instance.Source == PluginSourceNative {
+		for _, t := range types {
+			if capability := capabilityForEventType(t); !instance.CapabilityGrants[capability] {
+				return nil, fmt.Errorf("cannot subscribe to %s: capability %s has not been granted", t, capability)
+			}
+		}
+	}
+
+	instance.mu.Lock()
+	defer instance.mu.Unlock()
+	added := 0
+	for _, t := range types {
+		if !instance.eventSubscriptions[t] {
+			added++
+		}
+	}
+	if len(instance.eventSubscriptions)+added > maxPluginEventSubscriptions {
+		return nil, fmt.Errorf("cannot hold more than %d event subscriptions", maxPluginEventSubscriptions)
+	}
+	if instance.eventSubscriptions == nil {
+		instance.eventSubscriptions = make(map[event_manager.EventType]bool, len(types))
+	}
+	for _, t := range types {
+		instance.eventSubscriptions[t] = true
+	}
+	return instance.eventSubscriptionListLocked(), nil
+}
+
+// removePluginEventSubscriptions drops runtime event subscriptions for an
+// instance. Types it was not subscribed to are ignored.
+func (pm *PluginManager) removePluginEventSubscriptions(serverID, instanceID uuid.UUID, eventTypes []string) ([]string, error) {
+	types, err := parsePluginEventTypes(eventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	instance, err := pm.lookupPluginInstanceLocked(serverID, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	instance.mu.Lock()
+	defer instance.mu.Unlock()
+	for _, t := range types {
+		delete(instance.eventSubscriptions, t)
+	}
+	return instance.eventSubscriptionListLocked(), nil
+}
+
+// lookupPluginInstanceLocked returns an instance. The caller holds pm.mu.
+func (pm *PluginManager) lookupPluginInstanceLocked(serverID, instanceID uuid.UUID) (*PluginInstance, error) {
+	instance, ok := pm.plugins[serverID][instanceID]
+	if !ok || instance == nil {
+		return nil, fmt.Errorf("plugin instance %s not found", instanceID)
+	}
+	return instance, nil
+}
+
+// parsePluginEventTypes normalizes requested event types. Wildcards are
+// rejected so a plugin names every event it receives.
+func parsePluginEventTypes(eventTypes []string) ([]event_manager.EventType, error) {
+	if len(eventTypes) == 0 {
+		return nil, fmt.Errorf("at least one event type is required")
+	}
+	if len(eventTypes) > maxPluginEventSubscriptions {
+		return nil, fmt.Errorf("cannot change more than %d event subscriptions at once", maxPluginEventSubscriptions)
+	}
+	types := make([]event_manager.EventType, 0, len(eventTypes))
+	for _, raw := range eventTypes {
+		name := strings.TrimSpace(raw)
+		if name == string(event_manager.EventTypeAll) {
+			return nil, fmt.Errorf("wildcard event subscriptions are not allowed")
+		}
+		if !pluginEventTypePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid event type %q", raw)
+		}
+		types = append(types, event_manager.EventType(name))
+	}
+	return types, nil
+}
+
+// subscribedToEvent reports whether the plugin subscribed to an event type
+// at runtime.
+func (pi *PluginInstance) subscribedToEvent(t event_manager.EventType) bool {
+	pi.mu.Lock()
+	defer pi.mu.Unlock()
+	return pi.eventSubscriptions[t]
+}
+
+// clearEventSubscriptions drops every runtime subscription.
+func (pi *PluginInstance) clearEventSubscriptions() {
+	pi.mu.Lock()
+	pi.eventSubscriptions = nil
+	pi.mu.Unlock()
+}
+
+// eventSubscriptionListLocked returns the runtime subscriptions, sorted.
+// The caller holds pi.mu.
+func (pi *PluginInstance) eventSubscriptionListLocked() []string {
+	list := make([]string, 0, len(pi.eventSubscriptions))
+	for t := range pi.eventSubscriptions {
+		list = append(list, string(t))
+	}
+	sort.Strings(list)
+	return list
+}
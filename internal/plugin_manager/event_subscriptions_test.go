@@ -0,0 +1,102 @@
+package plugin_manager
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
+)
+
+// recordingPlugin reports every event it handles.
+type recordingPlugin struct {
+	noopPlugin
+	events chan string
+}
+
+func (p *recordingPlugin) HandleEvent(event *PluginEvent) error {
+	p.events <- event.Type
+	return nil
+}
+
+func TestPluginEventSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	serverID := uuid.New()
+	instanceID := uuid.New()
+	plugin := &recordingPlugin{events: make(chan string, 4)}
+	instance := &PluginInstance{
+		ID:       instanceID,
+		ServerID: serverID,
+		PluginID: "com.example.watcher",
+		Source:   PluginSourceNative,
+		Status:   PluginStatusRunning,
+		Enabled:  true,
+		Plugin:   plugin,
+		CapabilityGrants: map[string]bool{
+			NativePluginCapabilityEventsLog:  true,
+			NativePluginCapabilityEventsRCON: false,
+		},
+	}
+	pm := &PluginManager{
+		plugins: map[uuid.UUID]map[uuid.UUID]*PluginInstance{serverID: {instanceID: instance}},
+	}
+
+	active, err := pm.addPluginEventSubscriptions(serverID, instanceID, []string{"LOG_PLAYER_DIED", " LOG_PLAYER_WOUNDED "})
+	if err != nil {
+		t.Fatalf("addPluginEventSubscriptions() error = %v", err)
+	}
+	if want := []string{"LOG_PLAYER_DIED", "LOG_PLAYER_WOUNDED"}; !reflect.DeepEqual(active, want) {
+		t.Fatalf("active subscriptions = %v, want %v", active, want)
+	}
+
+	for _, eventTypes := range [][]string{
+		{"RCON_CHAT_MESSAGE"},                 // events.rcon was denied
+		{"LOG_PLAYER_REVIVED", "*"},           // wildcard
+		{"log_player_died"},                   // not an event type
+		{},                                    // nothing requested
+		{"LOG_PLAYER_REVIVED", "PLAYER DIED"}, // one invalid type rejects all
+	} {
+		if _, err := pm.addPluginEventSubscriptions(serverID, instanceID, eventTypes); err == nil {
+			t.Fatalf("addPluginEventSubscriptions(%v) error = nil, want error", eventTypes)
+		}
+	}
+	if instance.subscribedToEvent("LOG_PLAYER_REVIVED") {
+		t.Fatal("a rejected request added a subscription")
+	}
+
+	pm.distributeEventToPlugins(&event_manager.Event{ID: uuid.New(), ServerID: serverID, Type: event_manager.EventTypeLogPlayerDied})
+	select {
+	case got := <-plugin.events:
+		if got != string(event_manager.EventTypeLogPlayerDied) {
+			t.Fatalf("delivered event = %s, want %s", got, event_manager.EventTypeLogPlayerDied)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("subscribed event was not delivered")
+	}
+
+	active, err = pm.removePluginEventSubscriptions(serverID, instanceID, []string{"LOG_PLAYER_DIED"})
+	if err != nil {
+		t.Fatalf("removePluginEventSubscriptions() error = %v", err)
+	}
+	if want := []string{"LOG_PLAYER_WOUNDED"}; !reflect.DeepEqual(active, want) {
+		t.Fatalf("active subscriptions = %v, want %v", active, want)
+	}
+	pm.distributeEventToPlugins(&event_manager.Event{ID: uuid.New(), ServerID: serverID, Type: event_manager.EventTypeLogPlayerDied})
+	select {
+	case got := <-plugin.events:
+		t.Fatalf("unsubscribed event %s was delivered", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Subscriptions end when the instance stops
+	pm.resetPluginInstanceContext(instance)
+	if instance.subscribedToEvent(event_manager.EventTypeLogPlayerWounded) {
+		t.Fatal("subscriptions survived the instance stopping")
+	}
+
+	if _, err := pm.addPluginEventSubscriptions(serverID, uuid.New(), []string{"LOG_PLAYER_DIED"}); err == nil {
+		t.Fatal("addPluginEventSubscriptions() for an unknown instance error = nil, want error")
+	}
+}
@@ -0,0 +1,218 @@
+package plugin_manager
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// GlobalServerID keys global plugin instances in the manager. A global
+// instance is not bound to one server: it receives events from every server
+// in its scope and reaches those servers through ServerScopeAPI.
+var GlobalServerID = uuid.Nil
+
+// maxGlobalInstanceServers caps the servers one global instance may span.
+const maxGlobalInstanceServers = 256
+
+// ErrServerOutOfScope is returned when a global instance asks for a server
+// that is not in its scope.
+var ErrServerOutOfScope = errors.New("server is not in the plugin instance's scope")
+
+// inScope reports whether the instance handles events from serverID.
+func (i *PluginInstance) inScope(serverID uuid.UUID) bool {
+	if !i.Global {
+		return i.ServerID == serverID
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, id := range i.ServerIDs {
+		if id == serverID {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeServerIDs returns a copy of a global instance's server scope.
+func (i *PluginInstance) scopeServerIDs() []uuid.UUID {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return cloneServerIDs(i.ServerIDs)
+}
+
+func cloneServerIDs(serverIDs []uuid.UUID) []uuid.UUID {
+	if serverIDs == nil {
+		return nil
+	}
+	return append([]uuid.UUID(nil), serverIDs...)
+}
+
+// normalizeServerScope de-duplicates and sorts a global instance's scope.
+func normalizeServerScope(serverIDs []uuid.UUID) ([]uuid.UUID, error) {
+	seen := make(map[uuid.UUID]bool, len(serverIDs))
+	scope := make([]uuid.UUID, 0, len(serverIDs))
+	for _, id := range serverIDs {
+		if id == GlobalServerID {
+			return nil, errors.New("server scope contains an empty server ID")
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		scope = append(scope, id)
+	}
+	if len(scope) == 0 {
+		return nil, errors.New("global plugin instances need at least one server")
+	}
+	if len(scope) > maxGlobalInstanceServers {
+		return nil, fmt.Errorf("global plugin instances may span at most %d servers", maxGlobalInstanceServers)
+	}
+	sort.Slice(scope, func(a, b int) bool { return scope[a].String() < scope[b].String() })
+	return scope, nil
+}
+
+// CreateGlobalPluginInstance creates a plugin instance that receives events
+// from every server in serverIDs. The plugin must allow global instances.
+func (pm *PluginManager) CreateGlobalPluginInstance(serverIDs []uuid.UUID, pluginID string, notes string, config map[string]interface{}, capabilityGrants map[string]bool, rconPolicy RconCommandPolicy) (*PluginInstance, error) {
+	scope, err := normalizeServerScope(serverIDs)
+	if err != nil {
+		return nil, err
+	}
+	return pm.createPluginInstance(GlobalServerID, scope, pluginID, notes, config, capabilityGrants, rconPolicy)
+}
+
+// GetGlobalPluginInstances returns every global plugin instance.
+func (pm *PluginManager) GetGlobalPluginInstances() []*PluginInstance {
+	return pm.GetPluginInstances(GlobalServerID)
+}
+
+// UpdateGlobalPluginServers replaces the server scope of a global plugin
+// instance. Events from removed servers stop at once, and handles the
+// instance already holds for them deny every call.
+func (pm *PluginManager) UpdateGlobalPluginServers(instanceID uuid.UUID, serverIDs []uuid.UUID) error {
+	scope, err := normalizeServerScope(serverIDs)
+	if err != nil {
+		return err
+	}
+
+	pm.mu.RLock()
+	instance, err := pm.getPluginInstanceUnsafe(GlobalServerID, instanceID)
+	pm.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if err := pm.savePluginInstanceServers(instanceID, scope); err != nil {
+		return err
+	}
+
+	instance.mu.Lock()
+	instance.ServerIDs = scope
+	instance.mu.Unlock()
+
+	log.Info().
+		Str("instanceID", instanceID.String()).
+		Str("pluginID", instance.PluginID).
+		Int("servers", len(scope)).
+		Msg("Updated global plugin instance servers")
+
+	return nil
+}
+
+// serverScopeAPI implements ServerScopeAPI for one global instance.
+type serverScopeAPI struct {
+	pm         *PluginManager
+	instanceID uuid.UUID
+	pluginID   string
+}
+
+func (pm *PluginManager) newServerScopeAPI(instanceID uuid.UUID, pluginID string) ServerScopeAPI {
+	return &serverScopeAPI{pm: pm, instanceID: instanceID, pluginID: pluginID}
+}
+
+func (api *serverScopeAPI) instance() (*PluginInstance, error) {
+	api.pm.mu.RLock()
+	defer api.pm.mu.RUnlock()
+	return api.pm.getPluginInstanceUnsafe(GlobalServerID, api.instanceID)
+}
+
+func (api *serverScopeAPI) ServerIDs() []uuid.UUID {
+	instance, err := api.instance()
+	if err != nil {
+		return nil
+	}
+	return instance.scopeServerIDs()
+}
+
+// check resolves a server for the instance: it must be in scope and the
+// instance must hold the capability for the API it asks for.
+func (api *serverScopeAPI) check(serverID uuid.UUID, name, capability string) error {
+	instance, err := api.instance()
+	if err != nil {
+		return err
+	}
+	if !instance.inScope(serverID) {
+		return fmt.Errorf("%w: %s", ErrServerOutOfScope, serverID)
+	}
+	if !api.pm.capabilityGranted(GlobalServerID, api.instanceID, api.pluginID, capability) {
+		return fmt.Errorf("%s api is unavailable", name)
+	}
+	return nil
+}
+
+func (api *serverScopeAPI) ServerAPI(serverID uuid.UUID) (ServerAPI, error) {
+	if err := api.check(serverID, "server", NativePluginCapabilityAPIServer); err != nil {
+		return nil, err
+	}
+	return &scopedServerAPI{api: api, serverID: serverID, server: NewServerAPI(serverID, api.pm.db, api.pm.rconManager)}, nil
+}
+
+// scopedServerAPI is a ServerAPI handed to a global instance. Every call
+// re-checks the scope, so the handle stops working once its server leaves it.
+type scopedServerAPI struct {
+	api      *serverScopeAPI
+	serverID uuid.UUID
+	server   ServerAPI
+}
+
+func (s *scopedServerAPI) GetServerID() uuid.UUID {
+	return s.serverID
+}
+
+func (s *scopedServerAPI) GetServerInfo() (*ServerInfo, error) {
+	if err := s.api.check(s.serverID, "server", NativePluginCapabilityAPIServer); err != nil {
+		return nil, err
+	}
+	return s.server.GetServerInfo()
+}
+
+func (s *scopedServerAPI) GetPlayers() ([]*PlayerInfo, error) {
+	if err := s.api.check(s.serverID, "server", NativePluginCapabilityAPIServer); err != nil {
+		return nil, err
+	}
+	return s.server.GetPlayers()
+}
+
+func (s *scopedServerAPI) GetAdmins() ([]*AdminInfo, error) {
+	if err := s.api.check(s.serverID, "server", NativePluginCapabilityAPIServer); err != nil {
+		return nil, err
+	}
+	return s.server.GetAdmins()
+}
+
+func (s *scopedServerAPI) GetSquads() ([]*SquadInfo, error) {
+	if err := s.api.check(s.serverID, "server", NativePluginCapabilityAPIServer); err != nil {
+		return nil, err
+	}
+	return s.server.GetSquads()
+}
+
+func (api *serverScopeAPI) RconAPI(serverID uuid.UUID) (RconAPI, error) {
+	if err := api.check(serverID, "rcon", NativePluginCapabilityAPIRCON); err != nil {
+		return nil, err
+	}
+	return api.pm.newInstanceRconAPI(serverID, api.instanceID, api.pluginID), nil
+}
@@ -0,0 +1,170 @@
+package plugin_manager
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
+)
+
+func TestDistributeEventToGlobalInstances(t *testing.T) {
+	t.Parallel()
+
+	serverA, serverB, serverC := uuid.New(), uuid.New(), uuid.New()
+	newInstance := func(serverID uuid.UUID, plugin Plugin) *PluginInstance {
+		return &PluginInstance{
+			ID:                 uuid.New(),
+			ServerID:           serverID,
+			PluginID:           "com.example.relay",
+			Status:             PluginStatusRunning,
+			Enabled:            true,
+			Plugin:             plugin,
+			eventSubscriptions: map[event_manager.EventType]bool{event_manager.EventTypeRconChatMessage: true},
+		}
+	}
+	local := &recordingPlugin{events: make(chan string, 4)}
+	global := &recordingPlugin{events: make(chan string, 4)}
+	localInstance := newInstance(serverA, local)
+	globalInstance := newInstance(GlobalServerID, global)
+	globalInstance.Global = true
+	globalInstance.ServerIDs = []uuid.UUID{serverA, serverB}
+
+	pm := &PluginManager{
+		plugins: map[uuid.UUID]map[uuid.UUID]*PluginInstance{
+			serverA:        {localInstance.ID: localInstance},
+			GlobalServerID: {globalInstance.ID: globalInstance},
+		},
+	}
+
+	delivered := func(plugin *recordingPlugin) bool {
+		select {
+		case <-plugin.events:
+			return true
+		case <-time.After(50 * time.Millisecond):
+			return false
+		}
+	}
+
+	for _, tc := range []struct {
+		serverID        uuid.UUID
+		wantLocal, want bool
+	}{
+		{serverID: serverA, wantLocal: true, want: true},
+		{serverID: serverB, wantLocal: false, want: true},
+		{serverID: serverC, wantLocal: false, want: false},
+		{serverID: GlobalServerID, wantLocal: false, want: false},
+	} {
+		pm.distributeEventToPlugins(&event_manager.Event{ID: uuid.New(), ServerID: tc.serverID, Type: event_manager.EventTypeRconChatMessage})
+		if got := delivered(local); got != tc.wantLocal {
+			t.Fatalf("event from %s delivered to per-server instance = %v, want %v", tc.serverID, got, tc.wantLocal)
+		}
+		if got := delivered(global); got != tc.want {
+			t.Fatalf("event from %s delivered to global instance = %v, want %v", tc.serverID, got, tc.want)
+		}
+	}
+}
+
+func TestServerScopeAPI(t *testing.T) {
+	t.Parallel()
+
+	serverA, serverB := uuid.New(), uuid.New()
+	instance := &PluginInstance{
+		ID:        uuid.New(),
+		ServerID:  GlobalServerID,
+		ServerIDs: []uuid.UUID{serverA},
+		Global:    true,
+		PluginID:  "com.example.relay",
+		Source:    PluginSourceNative,
+		CapabilityGrants: map[string]bool{
+			NativePluginCapabilityAPIServer: true,
+			NativePluginCapabilityAPIRCON:   false,
+		},
+	}
+	pm := &PluginManager{
+		registry: NewPluginRegistry(),
+		plugins:  map[uuid.UUID]map[uuid.UUID]*PluginInstance{GlobalServerID: {instance.ID: instance}},
+	}
+	if err := pm.registry.RegisterPlugin(PluginDefinition{
+		ID:                   "com.example.relay",
+		Source:               PluginSourceNative,
+		AllowGlobalInstances: true,
+		RequiredCapabilities: []string{NativePluginCapabilityAPIServer, NativePluginCapabilityAPIRCON},
+		CreateInstance:       func() Plugin { return &noopPlugin{} },
+	}); err != nil {
+		t.Fatalf("RegisterPlugin() error = %v", err)
+	}
+
+	apis := pm.createPluginAPIs(t.Context(), GlobalServerID, instance.ID, "Relay", instance.PluginID, "info")
+	if apis.ServerAPI != nil || apis.RconAPI != nil || apis.ServerScopeAPI == nil {
+		t.Fatalf("global instance APIs = %+v, want only ServerScopeAPI for server access", apis)
+	}
+	scope := apis.ServerScopeAPI
+
+	server, err := scope.ServerAPI(serverA)
+	if err != nil {
+		t.Fatalf("ServerAPI(in scope) error = %v", err)
+	}
+	if server.GetServerID() != serverA {
+		t.Fatalf("ServerAPI(in scope).GetServerID() = %s, want %s", server.GetServerID(), serverA)
+	}
+	if _, err := scope.ServerAPI(serverB); !errors.Is(err, ErrServerOutOfScope) {
+		t.Fatalf("ServerAPI(out of scope) error = %v, want ErrServerOutOfScope", err)
+	}
+	if _, err := scope.RconAPI(serverA); err == nil {
+		t.Fatal("RconAPI() without the rcon grant error = nil, want error")
+	}
+
+	instance.CapabilityGrants[NativePluginCapabilityAPIRCON] = true
+	rcon, err := scope.RconAPI(serverA)
+	if err != nil {
+		t.Fatalf("RconAPI(in scope) error = %v", err)
+	}
+
+	// Handles stop working as soon as their server leaves the scope
+	instance.mu.Lock()
+	instance.ServerIDs = []uuid.UUID{serverB}
+	instance.mu.Unlock()
+	if err := rcon.Broadcast("hello"); !errors.Is(err, ErrRconCommandDenied) {
+		t.Fatalf("Broadcast() after scope change error = %v, want ErrRconCommandDenied", err)
+	}
+	if _, err := server.GetPlayers(); !errors.Is(err, ErrServerOutOfScope) {
+		t.Fatalf("GetPlayers() after scope change error = %v, want ErrServerOutOfScope", err)
+	}
+	if server.GetServerID() != serverA {
+		t.Fatalf("GetServerID() after scope change = %s, want %s", server.GetServerID(), serverA)
+	}
+	if got := scope.ServerIDs(); !reflect.DeepEqual(got, []uuid.UUID{serverB}) {
+		t.Fatalf("ServerIDs() = %v, want [%s]", got, serverB)
+	}
+}
+
+func TestNormalizeServerScope(t *testing.T) {
+	t.Parallel()
+
+	serverA, serverB := uuid.New(), uuid.New()
+	scope, err := normalizeServerScope([]uuid.UUID{serverA, serverB, serverA})
+	if err != nil {
+		t.Fatalf("normalizeServerScope() error = %v", err)
+	}
+	if len(scope) != 2 {
+		t.Fatalf("normalizeServerScope() = %v, want two servers", scope)
+	}
+
+	for name, serverIDs := range map[string][]uuid.UUID{
+		"empty":        nil,
+		"nil server":   {serverA, uuid.Nil},
+		"too many ids": make([]uuid.UUID, maxGlobalInstanceServers+1),
+	} {
+		if name == "too many ids" {
+			for i := range serverIDs {
+				serverIDs[i] = uuid.New()
+			}
+		}
+		if _, err := normalizeServerScope(serverIDs); err == nil {
+			t.Fatalf("normalizeServerScope(%s) error = nil, want error", name)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package plugin_manager
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/shared/utils"
+)
+
+// Bounds applied to every HistoryAPI query so a plugin cannot scan the
+// whole event store.
+const (
+	defaultHistoryWindow = time.Hour
+	maxHistoryWindow     = 7 * 24 * time.Hour
+	defaultHistoryLimit  = 100
+	maxHistoryLimit      = 500
+	historyQueryTimeout  = 10 * time.Second
+)
+
+// historyQuerier is the part of the ClickHouse client the history API uses.
+type historyQuerier interface {
+	Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// historyAPI implements HistoryAPI over the tables the ingester writes.
+type historyAPI struct {
+	ctx      context.Context
+	serverID uuid.UUID
+	querier  historyQuerier
+	now      func() time.Time
+}
+
+func newHistoryAPI(ctx context.Context, serverID uuid.UUID, querier historyQuerier) HistoryAPI {
+	return &historyAPI{ctx: ctx, serverID: serverID, querier: querier, now: time.Now}
+}
+
+// historyBounds is a HistoryQuery with its defaults applied.
+type historyBounds struct {
+	since    time.Time
+	until    time.Time
+	limit    int
+	steamID  string
+	eosID    string
+	teamkill bool
+}
+
+func (api *historyAPI) GetChatMessages(query HistoryQuery) ([]*HistoryChatMessage, error) {
+	ctx, cancel := context.WithTimeout(api.context(), historyQueryTimeout)
+	defer cancel()
+
+	bounds, err := api.resolveBounds(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	where := []string{"server_id = ?", "sent_at >= ?", "sent_at <= ?"}
+	args := []interface{}{api.serverID.String(), bounds.since, bounds.until}
+	switch {
+	case bounds.steamID != "":
+		steamID, _ := strconv.ParseUint(bounds.steamID, 10, 64)
+		where = append(where, "steam_id = ?")
+		args = append(args, steamID)
+	case bounds.eosID != "":
+		where = append(where, "eos_id = ?")
+		args = append(args, bounds.eosID)
+	}
+	args = append(args, bounds.limit)
+
+	rows, err := api.querier.Query(ctx, fmt.Sprintf(`
+		SELECT message_id, player_name, steam_id, eos_id, chat_type, message, sent_at
+		FROM squad_aegis.server_player_chat_messages
+		WHERE %s
+		ORDER BY sent_at DESC
+		LIMIT ?
+	`, strings.Join(where, " AND ")), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chat history: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []*HistoryChatMessage{}
+	for rows.Next() {
+		var message HistoryChatMessage
+		var messageID uuid.UUID
+		var steamID uint64
+		if err := rows.Scan(&messageID, &message.PlayerName, &steamID, &message.EOSID, &message.ChatType, &message.Message, &message.SentAt); err != nil {
+			return nil, fmt.Errorf("failed to read chat history: %w", err)
+		}
+		message.MessageID = messageID.String()
+		if steamID != 0 {
+			message.SteamID = strconv.FormatUint(steamID, 10)
+		}
+		messages = append(messages, &message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read chat history: %w", err)
+	}
+	return messages, nil
+}
+
+func (api *historyAPI) GetPlayerDeaths(query HistoryQuery) ([]*HistoryPlayerDeath, error) {
+	ctx, cancel := context.WithTimeout(api.context(), historyQueryTimeout)
+	defer cancel()
+
+	bounds, err := api.resolveBounds(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	where := []string{"server_id = ?", "event_time >= ?", "event_time <= ?"}
+	args := []interface{}{api.serverID.String(), bounds.since, bounds.until}
+	if bounds.teamkill {
+		where = append(where, "teamkill = 1")
+	}
+	switch {
+	case bounds.steamID != "":
+		where = append(where, "(attacker_steam = ? OR victim_steam = ?)")
+		args = append(args, bounds.steamID, bounds.steamID)
+	case bounds.eosID != "":
+		where = append(where, "(attacker_eos = ? OR victim_eos = ?)")
+		args = append(args, bounds.eosID, bounds.eosID)
+	}
+	args = append(args, bounds.limit)
+
+	rows, err := api.querier.Query(ctx, fmt.Sprintf(`
+		SELECT
+			toString(id),
+			event_time,
+			victim_name,
+			ifNull(victim_steam, ''),
+			ifNull(victim_eos, ''),
+			ifNull(victim_team, ''),
+			attacker_name,
+			ifNull(attacker_steam, ''),
+			ifNull(attacker_eos, ''),
+			ifNull(attacker_team, ''),
+			weapon,
+			damage,
+			teamkill
+		FROM squad_aegis.server_player_died_events
+		WHERE %s
+		ORDER BY event_time DESC
+		LIMIT ?
+	`, strings.Join(where, " AND ")), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query death history: %w", err)
+	}
+	defer rows.Close()
+
+	deaths := []*HistoryPlayerDeath{}
+	for rows.Next() {
+		var death HistoryPlayerDeath
+		var damage float32
+		var teamkill uint8
+		if err := rows.Scan(
+			&death.ID, &death.EventTime,
+			&death.VictimName, &death.VictimSteamID, &death.VictimEOSID, &death.VictimTeam,
+			&death.AttackerName, &death.AttackerSteamID, &death.AttackerEOSID, &death.AttackerTeam,
+			&death.Weapon, &damage, &teamkill,
+		); err != nil {
+			return nil, fmt.Errorf("failed to read death history: %w", err)
+		}
+		death.Damage = float64(damage)
+		death.Teamkill = teamkill == 1
+		deaths = append(deaths, &death)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read death history: %w", err)
+	}
+	return deaths, nil
+}
+
+func (api *historyAPI) GetCurrentRoundStart() (*time.Time, error) {
+	ctx, cancel := context.WithTimeout(api.context(), historyQueryTimeout)
+	defer cancel()
+	return api.currentRoundStart(ctx)
+}
+
+func (api *historyAPI) currentRoundStart(ctx context.Context) (*time.Time, error) {
+	rows, err := api.querier.Query(ctx, `
+		SELECT event_time
+		FROM squad_aegis.server_game_events_unified
+		WHERE server_id = ? AND event_type = 'NEW_GAME'
+		ORDER BY event_time DESC
+		LIMIT 1
+	`, api.serverID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query round start: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to query round start: %w", err)
+		}
+		return nil, nil
+	}
+	var startedAt time.Time
+	if err := rows.Scan(&startedAt); err != nil {
+		return nil, fmt.Errorf("failed to read round start: %w", err)
+	}
+	return &startedAt, nil
+}
+
+// resolveBounds applies the query defaults and limits. An explicit window
+// longer than maxHistoryWindow is rejected, while a current round that
+// started earlier is cut to the most recent maxHistoryWindow.
+func (api *historyAPI) resolveBounds(ctx context.Context, query HistoryQuery) (historyBounds, error) {
+	bounds := historyBounds{
+		since:    query.Since,
+		until:    query.Until,
+		limit:    query.Limit,
+		teamkill: query.TeamkillsOnly,
+	}
+
+	if bounds.until.IsZero() {
+		bounds.until = api.now()
+	}
+	if query.CurrentRound {
+		startedAt, err := api.currentRoundStart(ctx)
+		if err != nil {
+			return historyBounds{}, err
+		}
+		if startedAt == nil {
+			return historyBounds{}, fmt.Errorf("no round start has been recorded for this server")
+		}
+		bounds.since = *startedAt
+		if earliest := bounds.until.Add(-maxHistoryWindow); bounds.since.Before(earliest) {
+			bounds.since = earliest
+		}
+	} else if bounds.since.IsZero() {
+		bounds.since = bounds.until.Add(-defaultHistoryWindow)
+	}
+	if bounds.since.After(bounds.until) {
+		return historyBounds{}, fmt.Errorf("history query starts after it ends")
+	}
+	if bounds.until.Sub(bounds.since) > maxHistoryWindow {
+		return historyBounds{}, fmt.Errorf("history query window cannot exceed %s", maxHistoryWindow)
+	}
+	bounds.since = bounds.since.UTC()
+	bounds.until = bounds.until.UTC()
+
+	switch {
+	case bounds.limit < 0:
+		return historyBounds{}, fmt.Errorf("history query limit cannot be negative")
+	case bounds.limit == 0:
+		bounds.limit = defaultHistoryLimit
+	case bounds.limit > maxHistoryLimit:
+		bounds.limit = maxHistoryLimit
+	}
+
+	if playerID := strings.TrimSpace(query.PlayerID); playerID != "" {
+		switch {
+		case utils.IsSteamID(playerID):
+			bounds.steamID = playerID
+		case utils.IsEOSID(strings.ToLower(playerID)):
+			bounds.eosID = strings.ToLower(playerID)
+		default:
+			return historyBounds{}, fmt.Errorf("player ID must be a valid Steam ID or EOS ID")
+		}
+	}
+	return bounds, nil
+}
+
+func (api *historyAPI) context() context.Context {
+	if api.ctx == nil {
+		return context.Background()
+	}
+	return api.ctx
+}
@@ -0,0 +1,105 @@
+package plugin_manager
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// testHistoryQuerier adapts a test database to historyQuerier.
+type testHistoryQuerier struct{ db *sql.DB }
+
+func (q testHistoryQuerier) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return q.db.QueryContext(ctx, query, args...)
+}
+
+func TestHistoryAPIResolveBounds(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	roundStart := now.Add(-20 * time.Minute)
+	db := openTestSQLDB(t, &testSQLDriver{
+		queryContext: func(query string, _ []driver.NamedValue) (driver.Rows, error) {
+			if !strings.Contains(query, "NEW_GAME") {
+				return nil, fmt.Errorf("unexpected query: %s", query)
+			}
+			return &testSQLRows{columns: []string{"event_time"}, values: [][]driver.Value{{roundStart}}}, nil
+		},
+	})
+	api := &historyAPI{ctx: context.Background(), serverID: uuid.New(), querier: testHistoryQuerier{db}, now: func() time.Time { return now }}
+
+	bounds, err := api.resolveBounds(context.Background(), HistoryQuery{})
+	if err != nil {
+		t.Fatalf("resolveBounds() error = %v", err)
+	}
+	if !bounds.since.Equal(now.Add(-defaultHistoryWindow)) || !bounds.until.Equal(now) || bounds.limit != defaultHistoryLimit {
+		t.Fatalf("default bounds = %+v, want the last hour with limit %d", bounds, defaultHistoryLimit)
+	}
+
+	bounds, err = api.resolveBounds(context.Background(), HistoryQuery{CurrentRound: true, Limit: 10000, PlayerID: "ABCDEF0123456789ABCDEF0123456789"})
+	if err != nil {
+		t.Fatalf("resolveBounds(current round) error = %v", err)
+	}
+	if !bounds.since.Equal(roundStart) || bounds.limit != maxHistoryLimit || bounds.eosID != "abcdef0123456789abcdef0123456789" {
+		t.Fatalf("current round bounds = %+v, want the round start, capped limit and normalized EOS ID", bounds)
+	}
+
+	bounds, err = api.resolveBounds(context.Background(), HistoryQuery{PlayerID: "76561198000000000"})
+	if err != nil || bounds.steamID != "76561198000000000" {
+		t.Fatalf("resolveBounds(steam) = %+v, %v, want the Steam ID", bounds, err)
+	}
+
+	for name, query := range map[string]HistoryQuery{
+		"window too long":  {Since: now.Add(-8 * 24 * time.Hour)},
+		"starts after end": {Since: now.Add(time.Hour)},
+		"negative limit":   {Limit: -1},
+		"bad player id":    {PlayerID: "someone"},
+	} {
+		if _, err := api.resolveBounds(context.Background(), query); err == nil {
+			t.Fatalf("resolveBounds(%s) error = nil, want error", name)
+		}
+	}
+}
+
+func TestHistoryAPIGetPlayerDeaths(t *testing.T) {
+	t.Parallel()
+
+	serverID := uuid.New()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	var gotQuery string
+	var gotArgs []driver.NamedValue
+	db := openTestSQLDB(t, &testSQLDriver{
+		queryContext: func(query string, args []driver.NamedValue) (driver.Rows, error) {
+			gotQuery, gotArgs = query, args
+			return &testSQLRows{
+				columns: []string{"id", "event_time", "victim_name", "victim_steam", "victim_eos", "victim_team", "attacker_name", "attacker_steam", "attacker_eos", "attacker_team", "weapon", "damage", "teamkill"},
+				values: [][]driver.Value{{
+					"d1", now.Add(-time.Minute), "Victim", "76561198000000001", "", "1", "Attacker", "76561198000000000", "", "1", "BP_M4", float64(100), int64(1),
+				}},
+			}, nil
+		},
+	})
+	api := &historyAPI{ctx: context.Background(), serverID: serverID, querier: testHistoryQuerier{db}, now: func() time.Time { return now }}
+
+	deaths, err := api.GetPlayerDeaths(HistoryQuery{PlayerID: "76561198000000000", TeamkillsOnly: true, Limit: 5})
+	if err != nil {
+		t.Fatalf("GetPlayerDeaths() error = %v", err)
+	}
+	if len(deaths) != 1 || !deaths[0].Teamkill || deaths[0].AttackerSteamID != "76561198000000000" || deaths[0].Damage != 100 {
+		t.Fatalf("GetPlayerDeaths() = %+v, want the recorded teamkill", deaths)
+	}
+	for _, clause := range []string{"teamkill = 1", "(attacker_steam = ? OR victim_steam = ?)", "server_id = ?"} {
+		if !strings.Contains(gotQuery, clause) {
+			t.Fatalf("query is missing %q:\n%s", clause, gotQuery)
+		}
+	}
+	if len(gotArgs) != 6 || gotArgs[0].Value != serverID.String() || fmt.Sprint(gotArgs[5].Value) != "5" {
+		t.Fatalf("query args = %v, want server, window, player twice and limit", gotArgs)
+	}
+}
@@ -25,6 +25,7 @@ type PluginDistribution string
 const (
 	PluginDistributionBundled  PluginDistribution = "bundled"
 	PluginDistributionSideload PluginDistribution = "sideload"
+	PluginDistributionCatalog  PluginDistribution = "catalog"
 )
 
 type PluginInstallState string
@@ -55,6 +56,7 @@ const (
 	NativePluginCapabilityAPIConnector             = "api.connector"
 	NativePluginCapabilityAPIEvent                 = "api.event"
 	NativePluginCapabilityAPILog                   = "api.log"
+	NativePluginCapabilityAPIHistory               = "api.history"
 	NativePluginCapabilityEventsRCON               = "events.rcon"
 	NativePluginCapabilityEventsLog                = "events.log"
 	NativePluginCapabilityEventsSystem             = "events.system"
@@ -73,6 +75,7 @@ var nativePluginHostCapabilities = []string{
 	NativePluginCapabilityAPIConnector,
 	NativePluginCapabilityAPIEvent,
 	NativePluginCapabilityAPILog,
+	NativePluginCapabilityAPIHistory,
 	NativePluginCapabilityEventsRCON,
 	NativePluginCapabilityEventsLog,
 	NativePluginCapabilityEventsSystem,
@@ -86,6 +89,26 @@ func NativePluginHostCapabilities() []string {
 	return capabilities
 }
 
+// Native connector capabilities. Each grants one ConnectorHostAPI service;
+// the names match the plugin capabilities for the same APIs.
+const (
+	NativeConnectorCapabilityAPIEvent  = "api.event"
+	NativeConnectorCapabilityAPILog    = "api.log"
+	NativeConnectorCapabilityAPIServer = "api.server"
+)
+
+var nativeConnectorHostCapabilities = []string{
+	NativeConnectorCapabilityAPIEvent,
+	NativeConnectorCapabilityAPILog,
+	NativeConnectorCapabilityAPIServer,
+}
+
+func NativeConnectorHostCapabilities() []string {
+	capabilities := make([]string, len(nativeConnectorHostCapabilities))
+	copy(capabilities, nativeConnectorHostCapabilities)
+	return capabilities
+}
+
 type PluginPackageTarget struct {
 	MinHostAPIVersion    int      `json:"min_host_api_version"`
 	RequiredCapabilities []string `json:"required_capabilities,omitempty"`
@@ -206,14 +229,26 @@ type PluginDefinition struct {
 	SignatureVerified      bool                            `json:"signature_verified"`
 	Unsafe                 bool                            `json:"unsafe"`
 	AllowMultipleInstances bool                            `json:"allow_multiple_instances"`
+	AllowGlobalInstances   bool                            `json:"allow_global_instances"`
 	RequiredConnectors     []string                        `json:"required_connectors"`
 	OptionalConnectors     []string                        `json:"optional_connectors,omitempty"`
 	ConfigSchema           plug_config_schema.ConfigSchema `json:"config_schema"`
 	Events                 []event_manager.EventType       `json:"event_handlers"`
 	LongRunning            bool                            `json:"long_running"`
+	HTTPRoutes             []PluginHTTPRoute               `json:"http_routes,omitempty"`
+	Panels                 []PluginPanel                   `json:"panels,omitempty"`
 	CreateInstance         func() Plugin                   `json:"-"`
+
+	// MigrateConfig rewrites a config stored for an older
+	// ConfigSchema.Version. It is optional; without it, a version bump only
+	// fills new defaults before the stored config is re-validated.
+	MigrateConfig ConfigMigrationFunc `json:"-"`
 }
 
+// ConfigMigrationFunc upgrades a stored config from fromVersion to the
+// plugin's current config schema version.
+type ConfigMigrationFunc func(fromVersion int, config map[string]interface{}) (map[string]interface{}, error)
+
 // EventSource represents the source of an event
 type EventSource string
 
@@ -291,31 +326,49 @@ type CommandExecutionStatus struct {
 
 // PluginInstance represents an active plugin instance
 type PluginInstance struct {
-	ID                uuid.UUID              `json:"id"`
-	ServerID          uuid.UUID              `json:"server_id"`
-	PluginID          string                 `json:"plugin_id"`
-	PluginName        string                 `json:"plugin_name"`
-	Source            PluginSource           `json:"source,omitempty"`
-	Official          bool                   `json:"official,omitempty"`
-	Distribution      PluginDistribution     `json:"distribution,omitempty"`
-	InstallState      PluginInstallState     `json:"install_state,omitempty"`
-	MinHostAPIVersion int                    `json:"min_host_api_version,omitempty"`
-	Notes             string                 `json:"notes"`
-	Config            map[string]interface{} `json:"config"`
-	Status            PluginStatus           `json:"status"`
-	Enabled           bool                   `json:"enabled"`
-	LogLevel          string                 `json:"log_level"` // debug, info, warn, error
-	Plugin            Plugin                 `json:"-"`
-	Context           context.Context        `json:"-"`
-	Cancel            context.CancelFunc     `json:"-"`
-	LastError         string                 `json:"last_error,omitempty"`
-	CreatedAt         time.Time              `json:"created_at"`
-	UpdatedAt         time.Time              `json:"updated_at"`
-
-	// mu protects mutable state (Status, LastError) that may be written
-	// from concurrent event-handler goroutines.
+	ID                  uuid.UUID              `json:"id"`
+	ServerID            uuid.UUID              `json:"server_id"`            // GlobalServerID for global instances
+	ServerIDs           []uuid.UUID            `json:"server_ids,omitempty"` // servers a global instance receives events from
+	Global              bool                   `json:"global"`
+	PluginID            string                 `json:"plugin_id"`
+	PluginName          string                 `json:"plugin_name"`
+	Source              PluginSource           `json:"source,omitempty"`
+	Official            bool                   `json:"official,omitempty"`
+	Distribution        PluginDistribution     `json:"distribution,omitempty"`
+	InstallState        PluginInstallState     `json:"install_state,omitempty"`
+	MinHostAPIVersion   int                    `json:"min_host_api_version,omitempty"`
+	Notes               string                 `json:"notes"`
+	Config              map[string]interface{} `json:"config"`
+	ConfigSchemaVersion int                    `json:"config_schema_version"`
+	ConfigMigration     *PluginConfigMigration `json:"config_migration,omitempty"`
+	ConfigBackup        *PluginConfigBackup    `json:"-"`
+	Status              PluginStatus           `json:"status"`
+	Enabled             bool                   `json:"enabled"`
+	LogLevel            string                 `json:"log_level"` // debug, info, warn, error
+	RconPolicy          RconCommandPolicy      `json:"rcon_policy"`
+	CapabilityGrants    map[string]bool        `json:"capability_grants"`
+	PendingCapabilities []string               `json:"pending_capabilities,omitempty"` // requested but not yet granted or denied
+	Plugin              Plugin                 `json:"-"`
+	Context             context.Context        `json:"-"`
+	Cancel              context.CancelFunc     `json:"-"`
+	LastError           string                 `json:"last_error,omitempty"`
+	Quarantine          *PluginQuarantine      `json:"quarantine,omitempty"`
+	CreatedAt           time.Time              `json:"created_at"`
+	UpdatedAt           time.Time              `json:"updated_at"`
+
+	// mu protects mutable state (Status, LastError, ServerIDs,
+	// eventSubscriptions, usage)
+	// that may be written from concurrent event-handler goroutines.
 	mu sync.Mutex `json:"-"`
 
+	// eventSubscriptions holds the event types the plugin subscribed to at
+	// runtime through EventAPI. They are not persisted.
+	eventSubscriptions map[event_manager.EventType]bool
+
+	// usage holds the instance's resource accounting. It is created on
+	// first use and kept in memory only.
+	usage *pluginResourceUsage
+
 	// lifecycleMu serializes Create/Start/Init vs Delete/Stop and
 	// Enable vs Disable on a single instance so a Delete cannot race
 	// with a still-running Create's subprocess spawn.
@@ -545,6 +598,11 @@ type PluginAPIs struct {
 	// Server information
 	ServerAPI ServerAPI
 
+	// ServerScopeAPI hands a global instance server-qualified ServerAPI and
+	// RconAPI handles for the servers in its scope. Nil for per-server
+	// instances; global instances get it instead of ServerAPI and RconAPI.
+	ServerScopeAPI ServerScopeAPI
+
 	// Plugin-scoped key/value storage
 	DatabaseAPI DatabaseAPI
 
@@ -560,6 +618,9 @@ type PluginAPIs struct {
 	// Event system access
 	EventAPI EventAPI
 
+	// Read-only access to recorded match history
+	HistoryAPI HistoryAPI
+
 	// Discord messaging access when the Discord connector is available
 	DiscordAPI DiscordAPI
 
@@ -588,6 +649,21 @@ type ServerAPI interface {
 	GetSquads() ([]*SquadInfo, error)
 }
 
+// ServerScopeAPI gives a global plugin instance server-qualified access to
+// the servers in its scope. The scope is read on every call, so servers an
+// operator removes stop resolving immediately.
+type ServerScopeAPI interface {
+	// ServerIDs returns the servers the instance receives events from
+	ServerIDs() []uuid.UUID
+
+	// ServerAPI returns server information for a server in scope
+	ServerAPI(serverID uuid.UUID) (ServerAPI, error)
+
+	// RconAPI returns RCON access to a server in scope, subject to the
+	// instance's RCON policy
+	RconAPI(serverID uuid.UUID) (RconAPI, error)
+}
+
 // DatabaseAPI provides plugin-scoped key/value storage
 type DatabaseAPI interface {
 	// GetPluginData retrieves plugin-specific data
@@ -612,7 +688,7 @@ type RuleAPI interface {
 
 // RconAPI provides limited RCON access to plugins
 type RconAPI interface {
-	// SendCommand sends an RCON command (restricted list)
+	// SendCommand sends an RCON command, subject to the instance's RCON policy
 	SendCommand(command string) (string, error)
 
 	// Broadcast sends a message to all players
@@ -712,6 +788,32 @@ type EventAPI interface {
 
 	// SubscribeToEvents subscribes to specific event types
 	SubscribeToEvents(eventTypes []string, handler func(*PluginEvent)) error
+
+	// AddEventSubscriptions delivers the given event types to the plugin's
+	// HandleEvent in addition to the events in its definition, and returns
+	// the runtime subscriptions now active. They last until the instance
+	// stops.
+	AddEventSubscriptions(eventTypes []string) ([]string, error)
+
+	// RemoveEventSubscriptions drops runtime subscriptions added with
+	// AddEventSubscriptions and returns the ones still active. Events in
+	// the plugin's definition cannot be removed.
+	RemoveEventSubscriptions(eventTypes []string) ([]string, error)
+}
+
+// HistoryAPI provides bounded, read-only queries over the events recorded
+// for the plugin's server.
+type HistoryAPI interface {
+	// GetChatMessages returns chat messages, newest first.
+	GetChatMessages(query HistoryQuery) ([]*HistoryChatMessage, error)
+
+	// GetPlayerDeaths returns player deaths, newest first. When a player
+	// is given, deaths where they were the attacker or the victim match.
+	GetPlayerDeaths(query HistoryQuery) ([]*HistoryPlayerDeath, error)
+
+	// GetCurrentRoundStart returns when the current round started, or nil
+	// when no round start has been recorded.
+	GetCurrentRoundStart() (*time.Time, error)
 }
 
 // DiscordAPI provides limited Discord messaging functionality to plugins.
@@ -740,6 +842,46 @@ type LogAPI interface {
 
 // Data structures for API responses
 
+// HistoryQuery bounds a HistoryAPI query. Since defaults to one hour ago
+// and Limit to 100; the window may not exceed seven days and Limit is
+// capped at 500.
+type HistoryQuery struct {
+	PlayerID      string    `json:"player_id,omitempty"` // Steam or EOS ID
+	Since         time.Time `json:"since,omitempty"`
+	Until         time.Time `json:"until,omitempty"`
+	CurrentRound  bool      `json:"current_round,omitempty"` // start at the current round instead of Since
+	TeamkillsOnly bool      `json:"teamkills_only,omitempty"`
+	Limit         int       `json:"limit,omitempty"`
+}
+
+// HistoryChatMessage is a recorded chat message.
+type HistoryChatMessage struct {
+	MessageID  string    `json:"message_id"`
+	PlayerName string    `json:"player_name"`
+	SteamID    string    `json:"steam_id,omitempty"`
+	EOSID      string    `json:"eos_id,omitempty"`
+	ChatType   string    `json:"chat_type"`
+	Message    string    `json:"message"`
+	SentAt     time.Time `json:"sent_at"`
+}
+
+// HistoryPlayerDeath is a recorded player death.
+type HistoryPlayerDeath struct {
+	ID              string    `json:"id"`
+	EventTime       time.Time `json:"event_time"`
+	VictimName      string    `json:"victim_name"`
+	VictimSteamID   string    `json:"victim_steam_id,omitempty"`
+	VictimEOSID     string    `json:"victim_eos_id,omitempty"`
+	VictimTeam      string    `json:"victim_team,omitempty"`
+	AttackerName    string    `json:"attacker_name"`
+	AttackerSteamID string    `json:"attacker_steam_id,omitempty"`
+	AttackerEOSID   string    `json:"attacker_eos_id,omitempty"`
+	AttackerTeam    string    `json:"attacker_team,omitempty"`
+	Weapon          string    `json:"weapon"`
+	Damage          float64   `json:"damage"`
+	Teamkill        bool      `json:"teamkill"`
+}
+
 // RuleInfo contains the rule fields plugins are allowed to inspect.
 type RuleInfo struct {
 	ID           string `json:"id"`
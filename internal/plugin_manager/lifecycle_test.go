@@ -135,7 +135,7 @@ func TestPluginManagerRejectsInstanceCreationAfterStop(t *testing.T) {
 		t.Fatalf("Stop() error = %v", err)
 	}
 
-	_, err := pm.CreatePluginInstance(uuid.New(), "com.example.plugin", "", nil)
+	_, err := pm.CreatePluginInstance(uuid.New(), "com.example.plugin", "", nil, nil, RconCommandPolicy{})
 	if err == nil {
 		t.Fatal("CreatePluginInstance() error = nil, want stopped-manager error")
 	}
@@ -352,8 +352,13 @@ func TestLoadPluginsFromDatabaseKeepsUnavailableInstancesVisible(t *testing.T) {
 					"plugin_id",
 					"notes",
 					"config",
+					"config_schema_version",
+					"config_backup",
+					"config_migration",
 					"enabled",
 					"log_level",
+					"rcon_policy",
+					"capability_grants",
 					"created_at",
 					"updated_at",
 				},
@@ -363,8 +368,13 @@ func TestLoadPluginsFromDatabaseKeepsUnavailableInstancesVisible(t *testing.T) {
 					"com.example.missing",
 					"persisted instance",
 					[]byte(`{"token":"super-secret"}`),
+					int64(0),
+					nil,
+					nil,
 					true,
 					"info",
+					[]byte(`{}`),
+					[]byte(`{}`),
 					createdAt,
 					updatedAt,
 				}},
@@ -0,0 +1,225 @@
+package plugin_manager
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/time/rate"
+
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
+	connectorrpcpb "go.codycody31.dev/squad-aegis/pkg/connectorrpc/proto"
+)
+
+// connectorServerDirectory is the server lookup behind the connector host
+// API: ListServers serves api.server, and ServerExists validates the server
+// a published event names.
+type connectorServerDirectory interface {
+	ListServers(ctx context.Context) ([]*connectorrpcpb.ServerSummary, error)
+	ServerExists(ctx context.Context, serverID uuid.UUID) (bool, error)
+}
+
+// dbServerDirectory reads servers from the servers table.
+type dbServerDirectory struct {
+	db *sql.DB
+}
+
+func (d dbServerDirectory) ListServers(ctx context.Context) ([]*connectorrpcpb.ServerSummary, error) {
+	if d.db == nil {
+		return nil, errors.New("server directory is not available")
+	}
+	rows, err := d.db.QueryContext(ctx, `SELECT id, name FROM servers ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list servers: %w", err)
+	}
+	defer rows.Close()
+
+	var servers []*connectorrpcpb.ServerSummary
+	for rows.Next() {
+		var id uuid.UUID
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan server: %w", err)
+		}
+		servers = append(servers, &connectorrpcpb.ServerSummary{Id: id.String(), Name: name})
+	}
+	return servers, rows.Err()
+}
+
+func (d dbServerDirectory) ServerExists(ctx context.Context, serverID uuid.UUID) (bool, error) {
+	if d.db == nil {
+		return false, errors.New("server directory is not available")
+	}
+	var exists bool
+	err := d.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM servers WHERE id = $1)`, serverID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up server: %w", err)
+	}
+	return exists, nil
+}
+
+// connectorHostAPI implements the ConnectorHostAPI gRPC service for one
+// native connector instance. Each method is gated by a capability the
+// connector's manifest target declared, and calls share the plugin host
+// API's per-instance rate limit and concurrency cap.
+type connectorHostAPI struct {
+	connectorrpcpb.UnimplementedConnectorHostAPIServer
+
+	connectorID  string
+	capabilities map[string]bool
+	events       *event_manager.EventManager
+	servers      connectorServerDirectory
+	limiter      *rate.Limiter
+	sem          chan struct{}
+}
+
+// newConnectorHostAPI returns the host API for a connector instance,
+// granting the capabilities its definition carries from the manifest.
+func (pm *PluginManager) newConnectorHostAPI(definition ConnectorDefinition) *connectorHostAPI {
+	return &connectorHostAPI{
+		connectorID:  definition.ID,
+		capabilities: capabilitySet(definition.RequiredCapabilities),
+		events:       pm.eventManager,
+		servers:      dbServerDirectory{db: pm.db},
+		limiter:      buildHostAPIRateLimiter(),
+		sem:          make(chan struct{}, maxConcurrentHostAPICalls),
+	}
+}
+
+// require rejects calls to an API whose capability was not declared.
+func (a *connectorHostAPI) require(name, capability string) error {
+	if a.capabilities[capability] {
+		return nil
+	}
+	log.Warn().
+		Str("connectorID", a.connectorID).
+		Str("capability", capability).
+		Msg("Denied connector host API call without the granted capability")
+	return fmt.Errorf("%s api is unavailable", name)
+}
+
+// PublishEvent publishes a connector event for one server. The type is
+// always namespaced as CONNECTOR_<id>_<type> so connectors cannot
+// impersonate system events, plugins, or each other.
+func (a *connectorHostAPI) PublishEvent(ctx context.Context, req *connectorrpcpb.PublishEventRequest) (*connectorrpcpb.Empty, error) {
+	release, err := admitHostAPICall(a.limiter, a.sem)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if err := a.require("event", NativeConnectorCapabilityAPIEvent); err != nil {
+		return nil, err
+	}
+	if a.events == nil {
+		return nil, errors.New("event manager is not available")
+	}
+	serverID, err := uuid.Parse(strings.TrimSpace(req.GetServerId()))
+	if err != nil || serverID == uuid.Nil {
+		return nil, errors.New("server_id must be a server UUID")
+	}
+	if !pluginEventTypePattern.MatchString(req.GetEventType()) {
+		return nil, fmt.Errorf("invalid event type %q: must match %s", req.GetEventType(), pluginEventTypePattern)
+	}
+	if err := checkPayload(req.GetDataJson()); err != nil {
+		return nil, err
+	}
+	data, err := decodeJSONMap(req.GetDataJson())
+	if err != nil {
+		return nil, fmt.Errorf("decode event data: %w", err)
+	}
+	if len(data) > 0 {
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode event data: %w", err)
+		}
+		if len(encoded) > maxPublishEventDataSize {
+			return nil, fmt.Errorf("event data exceeds maximum size of %d bytes", maxPublishEventDataSize)
+		}
+	}
+	if len(req.GetRaw()) > maxPublishEventRawSize {
+		return nil, fmt.Errorf("event raw exceeds maximum size of %d bytes", maxPublishEventRawSize)
+	}
+
+	exists, err := a.servers.ServerExists(ctx, serverID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("server %s not found", serverID)
+	}
+
+	a.events.PublishEvent(serverID, &event_manager.ConnectorCustomEventData{
+		ConnectorID: a.connectorID,
+		EventType:   "CONNECTOR_" + a.connectorID + "_" + req.GetEventType(),
+		Data:        data,
+	}, req.GetRaw())
+	return &connectorrpcpb.Empty{}, nil
+}
+
+// Log writes a connector log entry to the host logger.
+func (a *connectorHostAPI) Log(_ context.Context, req *connectorrpcpb.LogRequest) (*connectorrpcpb.Empty, error) {
+	release, err := admitHostAPICall(a.limiter, a.sem)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if err := a.require("log", NativeConnectorCapabilityAPILog); err != nil {
+		return nil, err
+	}
+
+	var level zerolog.Level
+	switch req.GetLevel() {
+	case "debug":
+		level = zerolog.DebugLevel
+	case "info":
+		level = zerolog.InfoLevel
+	case "warn":
+		level = zerolog.WarnLevel
+	case "error":
+		level = zerolog.ErrorLevel
+	default:
+		return nil, fmt.Errorf("unknown log level %q", req.GetLevel())
+	}
+	if err := checkPayload(req.GetFieldsJson()); err != nil {
+		return nil, err
+	}
+	fields, err := decodeJSONMap(req.GetFieldsJson())
+	if err != nil {
+		return nil, fmt.Errorf("invalid log fields: %w", err)
+	}
+	if len(fields) > 32 {
+		return nil, fmt.Errorf("log fields exceed maximum count of 32")
+	}
+
+	entry := log.WithLevel(level).
+		Str("connectorID", a.connectorID).
+		Fields(sanitizeLogFields(fields))
+	if e := req.GetError(); e != "" {
+		entry = entry.Str("error", sanitizeLogMessage(e))
+	}
+	entry.Msg(sanitizeLogMessage(req.GetMessage()))
+	return &connectorrpcpb.Empty{}, nil
+}
+
+// ListServers returns every server registered with the host.
+func (a *connectorHostAPI) ListServers(ctx context.Context, _ *connectorrpcpb.Empty) (*connectorrpcpb.ListServersResponse, error) {
+	release, err := admitHostAPICall(a.limiter, a.sem)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if err := a.require("server", NativeConnectorCapabilityAPIServer); err != nil {
+		return nil, err
+	}
+	servers, err := a.servers.ListServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &connectorrpcpb.ListServersResponse{Servers: servers}, nil
+}
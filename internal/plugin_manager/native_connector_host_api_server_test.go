@@ -0,0 +1,124 @@
+package plugin_manager
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
+	connectorrpcpb "go.codycody31.dev/squad-aegis/pkg/connectorrpc/proto"
+)
+
+// fakeServerDirectory serves a fixed server list.
+type fakeServerDirectory struct {
+	servers []*connectorrpcpb.ServerSummary
+}
+
+func (f *fakeServerDirectory) ListServers(context.Context) ([]*connectorrpcpb.ServerSummary, error) {
+	return f.servers, nil
+}
+
+func (f *fakeServerDirectory) ServerExists(_ context.Context, serverID uuid.UUID) (bool, error) {
+	for _, server := range f.servers {
+		if server.GetId() == serverID.String() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newTestConnectorHostAPI(t *testing.T, servers connectorServerDirectory, capabilities ...string) (*connectorHostAPI, *event_manager.EventManager) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	events := event_manager.NewEventManager(ctx, 10)
+	pm := &PluginManager{eventManager: events}
+	api := pm.newConnectorHostAPI(ConnectorDefinition{ID: "com.example.bridge", RequiredCapabilities: capabilities})
+	api.servers = servers
+	return api, events
+}
+
+func TestConnectorHostAPIRequiresDeclaredCapabilities(t *testing.T) {
+	api, _ := newTestConnectorHostAPI(t, &fakeServerDirectory{}, NativeConnectorCapabilityAPILog)
+
+	if _, err := api.Log(context.Background(), &connectorrpcpb.LogRequest{Level: "info", Message: "hi"}); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if _, err := api.Log(context.Background(), &connectorrpcpb.LogRequest{Level: "trace", Message: "hi"}); err == nil {
+		t.Fatal("Log() with an unknown level error = nil, want error")
+	}
+
+	_, err := api.ListServers(context.Background(), &connectorrpcpb.Empty{})
+	if err == nil || !strings.Contains(err.Error(), "server api is unavailable") {
+		t.Fatalf("ListServers() error = %v, want server api unavailable", err)
+	}
+	_, err = api.PublishEvent(context.Background(), &connectorrpcpb.PublishEventRequest{ServerId: uuid.NewString(), EventType: "X"})
+	if err == nil || !strings.Contains(err.Error(), "event api is unavailable") {
+		t.Fatalf("PublishEvent() error = %v, want event api unavailable", err)
+	}
+}
+
+func TestConnectorHostAPIPublishEventNamespacesConnectorEvents(t *testing.T) {
+	serverID := uuid.New()
+	api, events := newTestConnectorHostAPI(t, &fakeServerDirectory{
+		servers: []*connectorrpcpb.ServerSummary{{Id: serverID.String(), Name: "Main"}},
+	}, NativeConnectorCapabilityAPIEvent)
+	subscriber := events.Subscribe(event_manager.EventFilter{Types: []event_manager.EventType{event_manager.EventTypeConnectorCustom}}, &serverID, 1)
+
+	data, _ := json.Marshal(map[string]interface{}{"message": "hi"})
+	if _, err := api.PublishEvent(context.Background(), &connectorrpcpb.PublishEventRequest{
+		ServerId:  serverID.String(),
+		EventType: "GREETING",
+		DataJson:  data,
+	}); err != nil {
+		t.Fatalf("PublishEvent() error = %v", err)
+	}
+
+	select {
+	case event := <-subscriber.Channel:
+		custom, ok := event.Data.(*event_manager.ConnectorCustomEventData)
+		if !ok {
+			t.Fatalf("event data = %T, want *ConnectorCustomEventData", event.Data)
+		}
+		if custom.EventType != "CONNECTOR_com.example.bridge_GREETING" || custom.ConnectorID != "com.example.bridge" {
+			t.Fatalf("event = %+v, want namespaced greeting from com.example.bridge", custom)
+		}
+		if custom.Data["message"] != "hi" {
+			t.Fatalf("event data = %v, want message hi", custom.Data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("connector event was not published")
+	}
+
+	if _, err := api.PublishEvent(context.Background(), &connectorrpcpb.PublishEventRequest{ServerId: uuid.NewString(), EventType: "GREETING"}); err == nil {
+		t.Fatal("PublishEvent() for an unknown server error = nil, want error")
+	}
+	if _, err := api.PublishEvent(context.Background(), &connectorrpcpb.PublishEventRequest{ServerId: "main", EventType: "GREETING"}); err == nil {
+		t.Fatal("PublishEvent() with an invalid server ID error = nil, want error")
+	}
+	for _, eventType := range []string{"", "greeting", "GREETING-1", "_GREETING", strings.Repeat("A", 65)} {
+		if _, err := api.PublishEvent(context.Background(), &connectorrpcpb.PublishEventRequest{ServerId: serverID.String(), EventType: eventType}); err == nil || !strings.Contains(err.Error(), "invalid event type") {
+			t.Fatalf("PublishEvent(%q) error = %v, want an invalid event type error", eventType, err)
+		}
+	}
+	if got := EventSourceForType(event_manager.EventTypeConnectorCustom); got != EventSourceConnector {
+		t.Fatalf("EventSourceForType(CONNECTOR_CUSTOM) = %q, want %q", got, EventSourceConnector)
+	}
+}
+
+func TestConnectorHostAPIListServers(t *testing.T) {
+	servers := []*connectorrpcpb.ServerSummary{{Id: uuid.NewString(), Name: "Main"}}
+	api, _ := newTestConnectorHostAPI(t, &fakeServerDirectory{servers: servers}, NativeConnectorCapabilityAPIServer)
+
+	resp, err := api.ListServers(context.Background(), &connectorrpcpb.Empty{})
+	if err != nil {
+		t.Fatalf("ListServers() error = %v", err)
+	}
+	if len(resp.GetServers()) != 1 || resp.GetServers()[0].GetName() != "Main" {
+		t.Fatalf("ListServers() = %v, want [Main]", resp.GetServers())
+	}
+}
@@ -336,6 +336,11 @@ func (pm *PluginManager) loadNativeConnectorPackage(pkg *InstalledConnectorPacka
 		TargetOS:             pkg.TargetOS,
 		TargetArch:           pkg.TargetArch,
 	}
+	// Enforce capabilities on load as well as install: packages installed
+	// by older hosts were checked against the plugin capability set.
+	if missing := missingConnectorCapabilities(target.RequiredCapabilities); len(missing) > 0 {
+		return fmt.Errorf("connector requires unsupported host capabilities: %s", strings.Join(missing, ", "))
+	}
 
 	definition, err := nativeConnectorVerifiedLoader(safePath, expectedSHA, pkg.Manifest, target)
 	if err != nil {
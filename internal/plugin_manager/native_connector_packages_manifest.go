@@ -9,7 +9,8 @@ import (
 )
 
 func selectedConnectorManifestTarget(manifest ConnectorPackageManifest) (PluginPackageTarget, error) {
-	return selectedManifestTarget(manifest.asPluginManifest())
+	// Connectors are always subprocesses, so there is no WASM fallback.
+	return selectManifestTargetFor(manifest.asPluginManifest(), runtime.GOOS, runtime.GOARCH)
 }
 
 func validateConnectorManifest(manifest *ConnectorPackageManifest) error {
@@ -85,8 +86,18 @@ func validateConnectorCompatibility(manifest ConnectorPackageManifest, target Pl
 	if target.MinHostAPIVersion > NativeConnectorHostAPIVersion {
 		return fmt.Errorf("connector requires host API version >= %d, but host provides %d", target.MinHostAPIVersion, NativeConnectorHostAPIVersion)
 	}
-	if missing := missingRequiredCapabilities(target.RequiredCapabilities); len(missing) > 0 {
+	if missing := missingConnectorCapabilities(target.RequiredCapabilities); len(missing) > 0 {
 		return fmt.Errorf("connector requires unsupported host capabilities: %s", strings.Join(missing, ", "))
 	}
 	return nil
 }
+
+// missingConnectorCapabilities returns the required capabilities the host
+// does not provide to native connectors.
+func missingConnectorCapabilities(required []string) []string {
+	hostCapabilities := make(map[string]struct{}, len(nativeConnectorHostCapabilities))
+	for _, capability := range NativeConnectorHostCapabilities() {
+		hostCapabilities[capability] = struct{}{}
+	}
+	return missingCapabilitiesFrom(required, hostCapabilities)
+}
@@ -312,3 +312,23 @@ func TestInstallConnectorBundleMarksLiveUpdatePendingRestart(t *testing.T) {
 		t.Fatalf("persisted.InstallState = %q, want %q", got, want)
 	}
 }
+
+func TestValidateConnectorCompatibilityUsesConnectorCapabilities(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("native connectors require linux")
+	}
+	manifest := testConnectorManifest("com.example.connector")
+	target := manifest.Targets[0]
+
+	target.RequiredCapabilities = []string{NativeConnectorCapabilityAPIEvent, NativeConnectorCapabilityAPILog, NativeConnectorCapabilityAPIServer}
+	if err := validateConnectorCompatibility(manifest, target); err != nil {
+		t.Fatalf("validateConnectorCompatibility() error = %v", err)
+	}
+
+	// Plugin-only capabilities grant nothing to connectors.
+	target.RequiredCapabilities = []string{NativeConnectorCapabilityAPILog, NativePluginCapabilityAPIRCON}
+	err := validateConnectorCompatibility(manifest, target)
+	if err == nil || !strings.Contains(err.Error(), NativePluginCapabilityAPIRCON) {
+		t.Fatalf("validateConnectorCompatibility() error = %v, want unsupported %s", err, NativePluginCapabilityAPIRCON)
+	}
+}
@@ -13,9 +13,11 @@ import (
 	"github.com/hashicorp/go-hclog"
 	goplugin "github.com/hashicorp/go-plugin"
 	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
 
 	"go.codycody31.dev/squad-aegis/internal/shared/plug_config_schema"
 	"go.codycody31.dev/squad-aegis/pkg/connectorrpc"
+	connectorrpcpb "go.codycody31.dev/squad-aegis/pkg/connectorrpc/proto"
 )
 
 // nativeConnectorSubprocessLauncher is the test-injectable factory that
@@ -101,6 +103,8 @@ type subprocessConnectorShim struct {
 
 	mu              sync.Mutex
 	handle          *connectorSubprocessHandle
+	hostAPI         *connectorHostAPI
+	stopHostAPI     func()
 	status          ConnectorStatus
 	onExit          func(error)
 	stopWatcher     chan struct{}
@@ -117,13 +121,22 @@ func (s *subprocessConnectorShim) OnUnexpectedExit(fn func(error)) {
 	s.onExit = fn
 }
 
+// setHostAPI sets the host API served to the subprocess from the next
+// Initialize.
+func (s *subprocessConnectorShim) setHostAPI(api *connectorHostAPI) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hostAPI = api
+}
+
 // GetDefinition returns the cached definition.
 func (s *subprocessConnectorShim) GetDefinition() ConnectorDefinition {
 	return s.definition
 }
 
-// Initialize spawns the subprocess (if not already running) and runs the
-// connector's Initialize RPC.
+// Initialize spawns the subprocess (if not already running), serves the
+// host API on a new broker ID when one is set, and runs the connector's
+// Initialize RPC.
 func (s *subprocessConnectorShim) Initialize(config map[string]interface{}) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -136,13 +149,29 @@ func (s *subprocessConnectorShim) Initialize(config map[string]interface{}) erro
 	if err != nil {
 		return fmt.Errorf("failed to spawn connector subprocess: %w", err)
 	}
+	args := connectorrpc.InitializeArgs{Config: config}
+	stopHostAPI := func() {}
+	if s.hostAPI != nil {
+		hostAPI := s.hostAPI
+		brokerID, stop, err := handle.rpc.StartHostAPIBroker(func(server *grpc.Server) {
+			connectorrpcpb.RegisterConnectorHostAPIServer(server, hostAPI)
+		})
+		if err != nil {
+			handle.Kill()
+			return fmt.Errorf("failed to start connector host api: %w", err)
+		}
+		args.HostAPIBrokerID = brokerID
+		stopHostAPI = stop
+	}
 	initCtx, initCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer initCancel()
-	if err := handle.rpc.Initialize(initCtx, connectorrpc.InitializeArgs{Config: config}); err != nil {
+	if err := handle.rpc.Initialize(initCtx, args); err != nil {
 		handle.Kill()
+		stopHostAPI()
 		return err
 	}
 	s.handle = handle
+	s.stopHostAPI = stopHostAPI
 	s.intentional = false
 	s.stopWatcher = make(chan struct{})
 	s.stopWatcherOnce = &sync.Once{}
@@ -242,6 +271,7 @@ func (s *subprocessConnectorShim) Stop() error {
 	stopWatcher := s.stopWatcher
 	stopOnce := s.stopWatcherOnce
 	watcherDone := s.watcherDone
+	stopHostAPI := s.stopHostAPI
 	s.intentional = true
 	s.status = ConnectorStatusStopped
 	s.mu.Unlock()
@@ -261,6 +291,9 @@ func (s *subprocessConnectorShim) Stop() error {
 	if handle != nil {
 		handle.Kill()
 	}
+	if stopHostAPI != nil {
+		stopHostAPI()
+	}
 	if watcherDone != nil {
 		<-watcherDone
 	}
@@ -268,6 +301,7 @@ func (s *subprocessConnectorShim) Stop() error {
 	s.mu.Lock()
 	if s.handle == handle {
 		s.handle = nil
+		s.stopHostAPI = nil
 		s.stopWatcher = nil
 		s.stopWatcherOnce = nil
 		s.watcherDone = nil
@@ -288,6 +322,7 @@ func (s *subprocessConnectorShim) Kill() error {
 	stopWatcher := s.stopWatcher
 	stopOnce := s.stopWatcherOnce
 	watcherDone := s.watcherDone
+	stopHostAPI := s.stopHostAPI
 	s.intentional = true
 	s.status = ConnectorStatusStopped
 	s.mu.Unlock()
@@ -299,6 +334,9 @@ func (s *subprocessConnectorShim) Kill() error {
 	if handle != nil {
 		handle.Kill()
 	}
+	if stopHostAPI != nil {
+		stopHostAPI()
+	}
 	if watcherDone != nil {
 		<-watcherDone
 	}
@@ -306,6 +344,7 @@ func (s *subprocessConnectorShim) Kill() error {
 	s.mu.Lock()
 	if s.handle == handle {
 		s.handle = nil
+		s.stopHostAPI = nil
 		s.stopWatcher = nil
 		s.stopWatcherOnce = nil
 		s.watcherDone = nil
@@ -314,9 +353,13 @@ func (s *subprocessConnectorShim) Kill() error {
 	return nil
 }
 
-// Compile-time guard: subprocessConnectorShim satisfies killableConnector so
-// PluginManager.stopConnectorInstance can SIGKILL a wedged connector.
-var _ killableConnector = (*subprocessConnectorShim)(nil)
+// Compile-time guards: subprocessConnectorShim satisfies killableConnector so
+// PluginManager.stopConnectorInstance can SIGKILL a wedged connector, and
+// hostAPIConnector so it is handed a host API before Initialize.
+var (
+	_ killableConnector = (*subprocessConnectorShim)(nil)
+	_ hostAPIConnector  = (*subprocessConnectorShim)(nil)
+)
 
 // GetStatus returns the cached status.
 func (s *subprocessConnectorShim) GetStatus() ConnectorStatus {
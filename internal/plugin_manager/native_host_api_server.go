@@ -7,11 +7,17 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"go.codycody31.dev/squad-aegis/internal/shared/config"
 	"go.codycody31.dev/squad-aegis/internal/shared/utils"
@@ -51,16 +57,12 @@ type hostAPIServer struct {
 // wires up the HostAPI gRPC server on it, and returns the broker ID plus a
 // handle that the caller uses to Close() on shutdown. Each hostAPIServer
 // gets its own rate limiter, so a compromised plugin cannot starve other
-// plugins by burning through a shared token bucket.
-func startHostAPIServer(rpcClient *pluginrpc.PluginGRPCClient, apis *PluginAPIs, pluginID string) (*hostAPIServer, uint32, error) {
-	dispatcher := &hostAPIDispatcher{
-		pluginID: pluginID,
-		apis:     apis,
-		limiter:  buildHostAPIRateLimiter(),
-		sem:      make(chan struct{}, maxConcurrentHostAPICalls),
-	}
+// plugins by burning through a shared token bucket. Calls are counted into
+// usage when it is non-nil.
+func startHostAPIServer(rpcClient *pluginrpc.PluginGRPCClient, apis *PluginAPIs, pluginID string, usage *pluginResourceUsage) (*hostAPIServer, uint32, error) {
+	dispatcher := newHostAPIDispatcher(apis, pluginID, usage)
 	brokerID, stop, err := rpcClient.StartHostAPIBroker(func(s *grpc.Server) {
-		pluginrpcpb.RegisterHostAPIServer(s, dispatcher)
+		s.RegisterService(dispatcher.serviceDesc(), dispatcher)
 	})
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to start host api broker: %w", err)
@@ -72,6 +74,18 @@ func startHostAPIServer(rpcClient *pluginrpc.PluginGRPCClient, apis *PluginAPIs,
 	}, brokerID, nil
 }
 
+// newHostAPIDispatcher returns the HostAPI implementation for one plugin
+// instance, with its own rate limiter and concurrency semaphore.
+func newHostAPIDispatcher(apis *PluginAPIs, pluginID string, usage *pluginResourceUsage) *hostAPIDispatcher {
+	return &hostAPIDispatcher{
+		pluginID: pluginID,
+		apis:     apis,
+		limiter:  buildHostAPIRateLimiter(),
+		sem:      make(chan struct{}, maxConcurrentHostAPICalls),
+		desc:     instrumentHostAPIService(&pluginrpcpb.HostAPI_ServiceDesc, usage),
+	}
+}
+
 // buildHostAPIRateLimiter constructs a per-instance rate.Limiter configured
 // from Plugins.HostAPIRatePerSec / Plugins.HostAPIBurst. A non-positive rate
 // disables rate limiting entirely (nil limiter); callers must check for nil
@@ -102,6 +116,32 @@ func (s *hostAPIServer) Close() {
 	})
 }
 
+// hostAPIRoute serves HostAPI calls for plugins that have no go-plugin
+// broker. Calls go to the dispatcher of the instance that currently owns
+// the plugin, and fail while none does.
+type hostAPIRoute struct {
+	dispatcher atomic.Pointer[hostAPIDispatcher]
+}
+
+// serve routes calls to dispatcher until the returned server is closed.
+func (r *hostAPIRoute) serve(dispatcher *hostAPIDispatcher) *hostAPIServer {
+	r.dispatcher.Store(dispatcher)
+	return &hostAPIServer{
+		apis: dispatcher.apis,
+		stop: func() { r.dispatcher.CompareAndSwap(dispatcher, nil) },
+	}
+}
+
+// invoke runs one HostAPI method and returns its reply envelope. It
+// implements pluginrpc.WasmInvoker.
+func (r *hostAPIRoute) invoke(ctx context.Context, method, serverID string, payload []byte) ([]byte, error) {
+	dispatcher := r.dispatcher.Load()
+	if dispatcher == nil {
+		return pluginrpc.EncodeWasmReply(nil, status.Error(codes.FailedPrecondition, "host apis are not available")), nil
+	}
+	return pluginrpc.DispatchWasmCall(ctx, dispatcher.serviceDesc(), dispatcher, method, serverID, payload), nil
+}
+
 // hostAPIDispatcher implements the HostAPI gRPC service. Each loaded plugin
 // instance has its own dispatcher with its own rate limiter and concurrency
 // semaphore so one misbehaving subprocess cannot starve others.
@@ -111,7 +151,17 @@ type hostAPIDispatcher struct {
 	pluginID string
 	apis     *PluginAPIs
 	limiter  *rate.Limiter
-	sem      chan struct{} // buffered semaphore limiting concurrent calls
+	sem      chan struct{}     // buffered semaphore limiting concurrent calls
+	desc     *grpc.ServiceDesc // HostAPI service, instrumented for resource accounting
+}
+
+// serviceDesc returns the service description the dispatcher is served
+// with.
+func (d *hostAPIDispatcher) serviceDesc() *grpc.ServiceDesc {
+	if d.desc == nil {
+		return &pluginrpcpb.HostAPI_ServiceDesc
+	}
+	return d.desc
 }
 
 // admit applies the rate limiter and concurrency semaphore for an incoming
@@ -121,12 +171,18 @@ func (d *hostAPIDispatcher) admit() (func(), error) {
 	if d == nil || d.apis == nil {
 		return nil, errors.New("host apis are not configured")
 	}
-	if d.limiter != nil && !d.limiter.Allow() {
+	return admitHostAPICall(d.limiter, d.sem)
+}
+
+// admitHostAPICall takes a rate limiter token and a semaphore slot. A nil
+// limiter disables rate limiting.
+func admitHostAPICall(limiter *rate.Limiter, sem chan struct{}) (func(), error) {
+	if limiter != nil && !limiter.Allow() {
 		return nil, errors.New("host api rate limit exceeded")
 	}
 	select {
-	case d.sem <- struct{}{}:
-		return func() { <-d.sem }, nil
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
 	default:
 		return nil, errors.New("too many concurrent host API calls")
 	}
@@ -184,7 +240,7 @@ func sanitizeLogFields(fields map[string]interface{}) map[string]interface{} {
 // decoded fields ready to forward to the host LogAPI.
 func (d *hostAPIDispatcher) prepareLogArgs(req *pluginrpcpb.LogRequest) (string, map[string]interface{}, error) {
 	if d.apis.LogAPI == nil {
-		return "", nil, errors.New("log api is unavailable")
+		return "", nil, d.unavailable("log", NativePluginCapabilityAPILog)
 	}
 	if err := checkPayload(req.GetFieldsJson()); err != nil {
 		return "", nil, err
@@ -292,11 +348,59 @@ func validateSquadOpPlayerID(playerID string) error {
 	return fmt.Errorf("player_id must be a Steam64, 32-char hex EOS, or in-match Squad player ID")
 }
 
-func (d *hostAPIDispatcher) checkRcon() error {
+// unavailable logs a call to a host API the plugin instance was not given,
+// because its package did not request the capability or an operator did
+// not grant it, and returns the error reported to the plugin.
+func (d *hostAPIDispatcher) unavailable(name, capability string) error {
+	log.Warn().
+		Str("pluginID", d.pluginID).
+		Str("capability", capability).
+		Msg("Denied plugin host API call without the granted capability")
+	return fmt.Errorf("%s api is unavailable", name)
+}
+
+// scopedServerID returns the server a call names in its request metadata.
+// Only global instances qualify calls with a server.
+func scopedServerID(ctx context.Context) (uuid.UUID, bool, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return uuid.Nil, false, nil
+	}
+	values := md.Get(pluginrpc.ServerIDMetadataKey)
+	if len(values) == 0 {
+		return uuid.Nil, false, nil
+	}
+	serverID, err := uuid.Parse(values[0])
+	if err != nil {
+		return uuid.Nil, true, fmt.Errorf("invalid %s: %q", pluginrpc.ServerIDMetadataKey, values[0])
+	}
+	return serverID, true, nil
+}
+
+// errServerNotQualified is returned when a global instance calls an Rcon*
+// or Server* method without naming a server.
+var errServerNotQualified = errors.New("global plugin instances must name a server with HostAPIs.ForServer")
+
+// rcon resolves the RconAPI a call targets: the instance's own server, or
+// the server a global instance names.
+func (d *hostAPIDispatcher) rcon(ctx context.Context) (RconAPI, error) {
+	serverID, qualified, err := scopedServerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if d.apis.ServerScopeAPI != nil {
+		if !qualified {
+			return nil, errServerNotQualified
+		}
+		return d.apis.ServerScopeAPI.RconAPI(serverID)
+	}
+	if qualified {
+		return nil, errors.New("server-qualified calls are only available to global plugin instances")
+	}
 	if d.apis.RconAPI == nil {
-		return errors.New("rcon api is unavailable")
+		return nil, d.unavailable("rcon", NativePluginCapabilityAPIRCON)
 	}
-	return nil
+	return d.apis.RconAPI, nil
 }
 
 // ruleIDPtr extracts the optional rule_id from a RconBanRequest; it returns
@@ -310,191 +414,202 @@ func ruleIDPtr(req *pluginrpcpb.RconBanRequest) *string {
 	return &v
 }
 
-func (d *hostAPIDispatcher) RconSendCommand(_ context.Context, req *pluginrpcpb.RconCommandRequest) (*pluginrpcpb.RconCommandResponse, error) {
+func (d *hostAPIDispatcher) RconSendCommand(ctx context.Context, req *pluginrpcpb.RconCommandRequest) (*pluginrpcpb.RconCommandResponse, error) {
 	release, err := d.admit()
 	if err != nil {
 		return nil, err
 	}
 	defer release()
-	if err := d.checkRcon(); err != nil {
+	rcon, err := d.rcon(ctx)
+	if err != nil {
 		return nil, err
 	}
 	log.Warn().Str("plugin_id", d.pluginID).Str("command", req.GetCommand()).Msg("Plugin executing raw RCON command via SendCommand")
-	resp, err := d.apis.RconAPI.SendCommand(req.GetCommand())
+	resp, err := rcon.SendCommand(req.GetCommand())
 	if err != nil {
 		return nil, err
 	}
 	return &pluginrpcpb.RconCommandResponse{Response: resp}, nil
 }
 
-func (d *hostAPIDispatcher) RconBroadcast(_ context.Context, req *pluginrpcpb.RconBroadcastRequest) (*pluginrpcpb.Empty, error) {
+func (d *hostAPIDispatcher) RconBroadcast(ctx context.Context, req *pluginrpcpb.RconBroadcastRequest) (*pluginrpcpb.Empty, error) {
 	release, err := d.admit()
 	if err != nil {
 		return nil, err
 	}
 	defer release()
-	if err := d.checkRcon(); err != nil {
+	rcon, err := d.rcon(ctx)
+	if err != nil {
 		return nil, err
 	}
-	if err := d.apis.RconAPI.Broadcast(req.GetMessage()); err != nil {
+	if err := rcon.Broadcast(req.GetMessage()); err != nil {
 		return nil, err
 	}
 	return &pluginrpcpb.Empty{}, nil
 }
 
-func (d *hostAPIDispatcher) RconSendWarningToPlayer(_ context.Context, req *pluginrpcpb.RconWarnPlayerRequest) (*pluginrpcpb.Empty, error) {
+func (d *hostAPIDispatcher) RconSendWarningToPlayer(ctx context.Context, req *pluginrpcpb.RconWarnPlayerRequest) (*pluginrpcpb.Empty, error) {
 	release, err := d.admit()
 	if err != nil {
 		return nil, err
 	}
 	defer release()
-	if err := d.checkRcon(); err != nil {
+	rcon, err := d.rcon(ctx)
+	if err != nil {
 		return nil, err
 	}
 	if err := validatePlayerID(req.GetPlayerId()); err != nil {
 		return nil, err
 	}
-	if err := d.apis.RconAPI.SendWarningToPlayer(req.GetPlayerId(), req.GetMessage()); err != nil {
+	if err := rcon.SendWarningToPlayer(req.GetPlayerId(), req.GetMessage()); err != nil {
 		return nil, err
 	}
 	return &pluginrpcpb.Empty{}, nil
 }
 
-func (d *hostAPIDispatcher) RconKickPlayer(_ context.Context, req *pluginrpcpb.RconKickRequest) (*pluginrpcpb.Empty, error) {
+func (d *hostAPIDispatcher) RconKickPlayer(ctx context.Context, req *pluginrpcpb.RconKickRequest) (*pluginrpcpb.Empty, error) {
 	release, err := d.admit()
 	if err != nil {
 		return nil, err
 	}
 	defer release()
-	if err := d.checkRcon(); err != nil {
+	rcon, err := d.rcon(ctx)
+	if err != nil {
 		return nil, err
 	}
 	if err := validatePlayerID(req.GetPlayerId()); err != nil {
 		return nil, err
 	}
-	if err := d.apis.RconAPI.KickPlayer(req.GetPlayerId(), req.GetReason()); err != nil {
+	if err := rcon.KickPlayer(req.GetPlayerId(), req.GetReason()); err != nil {
 		return nil, err
 	}
 	return &pluginrpcpb.Empty{}, nil
 }
 
-func (d *hostAPIDispatcher) RconBanPlayer(_ context.Context, req *pluginrpcpb.RconBanRequest) (*pluginrpcpb.Empty, error) {
+func (d *hostAPIDispatcher) RconBanPlayer(ctx context.Context, req *pluginrpcpb.RconBanRequest) (*pluginrpcpb.Empty, error) {
 	release, err := d.admit()
 	if err != nil {
 		return nil, err
 	}
 	defer release()
-	if err := d.checkRcon(); err != nil {
+	rcon, err := d.rcon(ctx)
+	if err != nil {
 		return nil, err
 	}
 	if err := validatePlayerID(req.GetPlayerId()); err != nil {
 		return nil, err
 	}
-	if err := d.apis.RconAPI.BanPlayer(req.GetPlayerId(), req.GetReason(), time.Duration(req.GetDurationNs())); err != nil {
+	if err := rcon.BanPlayer(req.GetPlayerId(), req.GetReason(), time.Duration(req.GetDurationNs())); err != nil {
 		return nil, err
 	}
 	return &pluginrpcpb.Empty{}, nil
 }
 
-func (d *hostAPIDispatcher) RconBanWithEvidence(_ context.Context, req *pluginrpcpb.RconBanRequest) (*pluginrpcpb.BanResultResponse, error) {
+func (d *hostAPIDispatcher) RconBanWithEvidence(ctx context.Context, req *pluginrpcpb.RconBanRequest) (*pluginrpcpb.BanResultResponse, error) {
 	release, err := d.admit()
 	if err != nil {
 		return nil, err
 	}
 	defer release()
-	if err := d.checkRcon(); err != nil {
+	rcon, err := d.rcon(ctx)
+	if err != nil {
 		return nil, err
 	}
 	if err := validatePlayerID(req.GetPlayerId()); err != nil {
 		return nil, err
 	}
-	banID, err := d.apis.RconAPI.BanWithEvidence(req.GetPlayerId(), req.GetReason(), time.Duration(req.GetDurationNs()), req.GetEventId(), req.GetEventType())
+	banID, err := rcon.BanWithEvidence(req.GetPlayerId(), req.GetReason(), time.Duration(req.GetDurationNs()), req.GetEventId(), req.GetEventType())
 	if err != nil {
 		return nil, err
 	}
 	return &pluginrpcpb.BanResultResponse{BanId: banID}, nil
 }
 
-func (d *hostAPIDispatcher) RconWarnPlayerWithRule(_ context.Context, req *pluginrpcpb.RconBanRequest) (*pluginrpcpb.Empty, error) {
+func (d *hostAPIDispatcher) RconWarnPlayerWithRule(ctx context.Context, req *pluginrpcpb.RconBanRequest) (*pluginrpcpb.Empty, error) {
 	release, err := d.admit()
 	if err != nil {
 		return nil, err
 	}
 	defer release()
-	if err := d.checkRcon(); err != nil {
+	rcon, err := d.rcon(ctx)
+	if err != nil {
 		return nil, err
 	}
 	if err := validatePlayerID(req.GetPlayerId()); err != nil {
 		return nil, err
 	}
-	if err := d.apis.RconAPI.WarnPlayerWithRule(req.GetPlayerId(), req.GetReason(), ruleIDPtr(req)); err != nil {
+	if err := rcon.WarnPlayerWithRule(req.GetPlayerId(), req.GetReason(), ruleIDPtr(req)); err != nil {
 		return nil, err
 	}
 	return &pluginrpcpb.Empty{}, nil
 }
 
-func (d *hostAPIDispatcher) RconKickPlayerWithRule(_ context.Context, req *pluginrpcpb.RconBanRequest) (*pluginrpcpb.Empty, error) {
+func (d *hostAPIDispatcher) RconKickPlayerWithRule(ctx context.Context, req *pluginrpcpb.RconBanRequest) (*pluginrpcpb.Empty, error) {
 	release, err := d.admit()
 	if err != nil {
 		return nil, err
 	}
 	defer release()
-	if err := d.checkRcon(); err != nil {
+	rcon, err := d.rcon(ctx)
+	if err != nil {
 		return nil, err
 	}
 	if err := validatePlayerID(req.GetPlayerId()); err != nil {
 		return nil, err
 	}
-	if err := d.apis.RconAPI.KickPlayerWithRule(req.GetPlayerId(), req.GetReason(), ruleIDPtr(req)); err != nil {
+	if err := rcon.KickPlayerWithRule(req.GetPlayerId(), req.GetReason(), ruleIDPtr(req)); err != nil {
 		return nil, err
 	}
 	return &pluginrpcpb.Empty{}, nil
 }
 
-func (d *hostAPIDispatcher) RconBanPlayerWithRule(_ context.Context, req *pluginrpcpb.RconBanRequest) (*pluginrpcpb.Empty, error) {
+func (d *hostAPIDispatcher) RconBanPlayerWithRule(ctx context.Context, req *pluginrpcpb.RconBanRequest) (*pluginrpcpb.Empty, error) {
 	release, err := d.admit()
 	if err != nil {
 		return nil, err
 	}
 	defer release()
-	if err := d.checkRcon(); err != nil {
+	rcon, err := d.rcon(ctx)
+	if err != nil {
 		return nil, err
 	}
 	if err := validatePlayerID(req.GetPlayerId()); err != nil {
 		return nil, err
 	}
-	if err := d.apis.RconAPI.BanPlayerWithRule(req.GetPlayerId(), req.GetReason(), time.Duration(req.GetDurationNs()), ruleIDPtr(req)); err != nil {
+	if err := rcon.BanPlayerWithRule(req.GetPlayerId(), req.GetReason(), time.Duration(req.GetDurationNs()), ruleIDPtr(req)); err != nil {
 		return nil, err
 	}
 	return &pluginrpcpb.Empty{}, nil
 }
 
-func (d *hostAPIDispatcher) RconBanWithEvidenceAndRule(_ context.Context, req *pluginrpcpb.RconBanRequest) (*pluginrpcpb.BanResultResponse, error) {
+func (d *hostAPIDispatcher) RconBanWithEvidenceAndRule(ctx context.Context, req *pluginrpcpb.RconBanRequest) (*pluginrpcpb.BanResultResponse, error) {
 	release, err := d.admit()
 	if err != nil {
 		return nil, err
 	}
 	defer release()
-	if err := d.checkRcon(); err != nil {
+	rcon, err := d.rcon(ctx)
+	if err != nil {
 		return nil, err
 	}
 	if err := validatePlayerID(req.GetPlayerId()); err != nil {
 		return nil, err
 	}
-	banID, err := d.apis.RconAPI.BanWithEvidenceAndRule(req.GetPlayerId(), req.GetReason(), time.Duration(req.GetDurationNs()), req.GetEventId(), req.GetEventType(), ruleIDPtr(req))
+	banID, err := rcon.BanWithEvidenceAndRule(req.GetPlayerId(), req.GetReason(), time.Duration(req.GetDurationNs()), req.GetEventId(), req.GetEventType(), ruleIDPtr(req))
 	if err != nil {
 		return nil, err
 	}
 	return &pluginrpcpb.BanResultResponse{BanId: banID}, nil
 }
 
-func (d *hostAPIDispatcher) RconBanWithEvidenceAndRuleAndMetadata(_ context.Context, req *pluginrpcpb.RconBanRequest) (*pluginrpcpb.BanResultResponse, error) {
+func (d *hostAPIDispatcher) RconBanWithEvidenceAndRuleAndMetadata(ctx context.Context, req *pluginrpcpb.RconBanRequest) (*pluginrpcpb.BanResultResponse, error) {
 	release, err := d.admit()
 	if err != nil {
 		return nil, err
 	}
 	defer release()
-	if err := d.checkRcon(); err != nil {
+	rcon, err := d.rcon(ctx)
+	if err != nil {
 		return nil, err
 	}
 	if err := validatePlayerID(req.GetPlayerId()); err != nil {
@@ -507,38 +622,40 @@ func (d *hostAPIDispatcher) RconBanWithEvidenceAndRuleAndMetadata(_ context.Cont
 	if err != nil {
 		return nil, fmt.Errorf("decode metadata: %w", err)
 	}
-	banID, err := d.apis.RconAPI.BanWithEvidenceAndRuleAndMetadata(req.GetPlayerId(), req.GetReason(), time.Duration(req.GetDurationNs()), req.GetEventId(), req.GetEventType(), ruleIDPtr(req), metadata)
+	banID, err := rcon.BanWithEvidenceAndRuleAndMetadata(req.GetPlayerId(), req.GetReason(), time.Duration(req.GetDurationNs()), req.GetEventId(), req.GetEventType(), ruleIDPtr(req), metadata)
 	if err != nil {
 		return nil, err
 	}
 	return &pluginrpcpb.BanResultResponse{BanId: banID}, nil
 }
 
-func (d *hostAPIDispatcher) RconRemovePlayerFromSquad(_ context.Context, req *pluginrpcpb.RconRemoveSquadRequest) (*pluginrpcpb.Empty, error) {
+func (d *hostAPIDispatcher) RconRemovePlayerFromSquad(ctx context.Context, req *pluginrpcpb.RconRemoveSquadRequest) (*pluginrpcpb.Empty, error) {
 	release, err := d.admit()
 	if err != nil {
 		return nil, err
 	}
 	defer release()
-	if err := d.checkRcon(); err != nil {
+	rcon, err := d.rcon(ctx)
+	if err != nil {
 		return nil, err
 	}
 	if err := validatePlayerID(req.GetPlayerId()); err != nil {
 		return nil, err
 	}
-	if err := d.apis.RconAPI.RemovePlayerFromSquad(req.GetPlayerId()); err != nil {
+	if err := rcon.RemovePlayerFromSquad(req.GetPlayerId()); err != nil {
 		return nil, err
 	}
 	return &pluginrpcpb.Empty{}, nil
 }
 
-func (d *hostAPIDispatcher) RconRemovePlayerFromSquadById(_ context.Context, req *pluginrpcpb.RconRemoveSquadRequest) (*pluginrpcpb.Empty, error) {
+func (d *hostAPIDispatcher) RconRemovePlayerFromSquadById(ctx context.Context, req *pluginrpcpb.RconRemoveSquadRequest) (*pluginrpcpb.Empty, error) {
 	release, err := d.admit()
 	if err != nil {
 		return nil, err
 	}
 	defer release()
-	if err := d.checkRcon(); err != nil {
+	rcon, err := d.rcon(ctx)
+	if err != nil {
 		return nil, err
 	}
 	// AdminRemovePlayerFromSquadById accepts the numeric Squad ID in
@@ -546,7 +663,7 @@ func (d *hostAPIDispatcher) RconRemovePlayerFromSquadById(_ context.Context, req
 	if err := validateSquadOpPlayerID(req.GetPlayerId()); err != nil {
 		return nil, err
 	}
-	if err := d.apis.RconAPI.RemovePlayerFromSquadById(req.GetPlayerId()); err != nil {
+	if err := rcon.RemovePlayerFromSquadById(req.GetPlayerId()); err != nil {
 		return nil, err
 	}
 	return &pluginrpcpb.Empty{}, nil
@@ -554,11 +671,25 @@ func (d *hostAPIDispatcher) RconRemovePlayerFromSquadById(_ context.Context, req
 
 // -- Server -----------------------------------------------------------------
 
-func (d *hostAPIDispatcher) checkServer() error {
+// server resolves the ServerAPI a call targets, like rcon.
+func (d *hostAPIDispatcher) server(ctx context.Context) (ServerAPI, error) {
+	serverID, qualified, err := scopedServerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if d.apis.ServerScopeAPI != nil {
+		if !qualified {
+			return nil, errServerNotQualified
+		}
+		return d.apis.ServerScopeAPI.ServerAPI(serverID)
+	}
+	if qualified {
+		return nil, errors.New("server-qualified calls are only available to global plugin instances")
+	}
 	if d.apis.ServerAPI == nil {
-		return errors.New("server api is unavailable")
+		return nil, d.unavailable("server", NativePluginCapabilityAPIServer)
 	}
-	return nil
+	return d.apis.ServerAPI, nil
 }
 
 func encodeJSONReply(v interface{}) ([]byte, error) {
@@ -568,28 +699,30 @@ func encodeJSONReply(v interface{}) ([]byte, error) {
 	return json.Marshal(v)
 }
 
-func (d *hostAPIDispatcher) ServerGetServerID(_ context.Context, _ *pluginrpcpb.Empty) (*pluginrpcpb.StringResponse, error) {
+func (d *hostAPIDispatcher) ServerGetServerID(ctx context.Context, _ *pluginrpcpb.Empty) (*pluginrpcpb.StringResponse, error) {
 	release, err := d.admit()
 	if err != nil {
 		return nil, err
 	}
 	defer release()
-	if err := d.checkServer(); err != nil {
+	server, err := d.server(ctx)
+	if err != nil {
 		return nil, err
 	}
-	return &pluginrpcpb.StringResponse{Value: d.apis.ServerAPI.GetServerID().String()}, nil
+	return &pluginrpcpb.StringResponse{Value: server.GetServerID().String()}, nil
 }
 
-func (d *hostAPIDispatcher) ServerGetServerInfo(_ context.Context, _ *pluginrpcpb.Empty) (*pluginrpcpb.JSONResponse, error) {
+func (d *hostAPIDispatcher) ServerGetServerInfo(ctx context.Context, _ *pluginrpcpb.Empty) (*pluginrpcpb.JSONResponse, error) {
 	release, err := d.admit()
 	if err != nil {
 		return nil, err
 	}
 	defer release()
-	if err := d.checkServer(); err != nil {
+	server, err := d.server(ctx)
+	if err != nil {
 		return nil, err
 	}
-	info, err := d.apis.ServerAPI.GetServerInfo()
+	info, err := server.GetServerInfo()
 	if err != nil {
 		return nil, err
 	}
@@ -600,16 +733,17 @@ func (d *hostAPIDispatcher) ServerGetServerInfo(_ context.Context, _ *pluginrpcp
 	return &pluginrpcpb.JSONResponse{DataJson: encoded}, nil
 }
 
-func (d *hostAPIDispatcher) ServerGetPlayers(_ context.Context, _ *pluginrpcpb.Empty) (*pluginrpcpb.JSONResponse, error) {
+func (d *hostAPIDispatcher) ServerGetPlayers(ctx context.Context, _ *pluginrpcpb.Empty) (*pluginrpcpb.JSONResponse, error) {
 	release, err := d.admit()
 	if err != nil {
 		return nil, err
 	}
 	defer release()
-	if err := d.checkServer(); err != nil {
+	server, err := d.server(ctx)
+	if err != nil {
 		return nil, err
 	}
-	players, err := d.apis.ServerAPI.GetPlayers()
+	players, err := server.GetPlayers()
 	if err != nil {
 		return nil, err
 	}
@@ -620,16 +754,17 @@ func (d *hostAPIDispatcher) ServerGetPlayers(_ context.Context, _ *pluginrpcpb.E
 	return &pluginrpcpb.JSONResponse{DataJson: encoded}, nil
 }
 
-func (d *hostAPIDispatcher) ServerGetAdmins(_ context.Context, _ *pluginrpcpb.Empty) (*pluginrpcpb.JSONResponse, error) {
+func (d *hostAPIDispatcher) ServerGetAdmins(ctx context.Context, _ *pluginrpcpb.Empty) (*pluginrpcpb.JSONResponse, error) {
 	release, err := d.admit()
 	if err != nil {
 		return nil, err
 	}
 	defer release()
-	if err := d.checkServer(); err != nil {
+	server, err := d.server(ctx)
+	if err != nil {
 		return nil, err
 	}
-	admins, err := d.apis.ServerAPI.GetAdmins()
+	admins, err := server.GetAdmins()
 	if err != nil {
 		return nil, err
 	}
@@ -640,16 +775,17 @@ func (d *hostAPIDispatcher) ServerGetAdmins(_ context.Context, _ *pluginrpcpb.Em
 	return &pluginrpcpb.JSONResponse{DataJson: encoded}, nil
 }
 
-func (d *hostAPIDispatcher) ServerGetSquads(_ context.Context, _ *pluginrpcpb.Empty) (*pluginrpcpb.JSONResponse, error) {
+func (d *hostAPIDispatcher) ServerGetSquads(ctx context.Context, _ *pluginrpcpb.Empty) (*pluginrpcpb.JSONResponse, error) {
 	release, err := d.admit()
 	if err != nil {
 		return nil, err
 	}
 	defer release()
-	if err := d.checkServer(); err != nil {
+	server, err := d.server(ctx)
+	if err != nil {
 		return nil, err
 	}
-	squads, err := d.apis.ServerAPI.GetSquads()
+	squads, err := server.GetSquads()
 	if err != nil {
 		return nil, err
 	}
@@ -660,6 +796,30 @@ func (d *hostAPIDispatcher) ServerGetSquads(_ context.Context, _ *pluginrpcpb.Em
 	return &pluginrpcpb.JSONResponse{DataJson: encoded}, nil
 }
 
+// ServerListScopeServers returns a global instance's scope, or the
+// instance's own server for per-server instances.
+func (d *hostAPIDispatcher) ServerListScopeServers(_ context.Context, _ *pluginrpcpb.Empty) (*pluginrpcpb.JSONResponse, error) {
+	release, err := d.admit()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	var serverIDs []uuid.UUID
+	switch {
+	case d.apis.ServerScopeAPI != nil:
+		serverIDs = d.apis.ServerScopeAPI.ServerIDs()
+	case d.apis.ServerAPI != nil:
+		serverIDs = []uuid.UUID{d.apis.ServerAPI.GetServerID()}
+	default:
+		return nil, d.unavailable("server", NativePluginCapabilityAPIServer)
+	}
+	encoded, err := encodeJSONReply(serverIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &pluginrpcpb.JSONResponse{DataJson: encoded}, nil
+}
+
 // -- Database ---------------------------------------------------------------
 
 func validatePluginDataKey(key string) error {
@@ -674,7 +834,7 @@ func validatePluginDataKey(key string) error {
 
 func (d *hostAPIDispatcher) checkDatabase() error {
 	if d.apis.DatabaseAPI == nil {
-		return errors.New("database api is unavailable")
+		return d.unavailable("database", NativePluginCapabilityAPIDatabase)
 	}
 	return nil
 }
@@ -741,7 +901,7 @@ func (d *hostAPIDispatcher) DatabaseDeletePluginData(_ context.Context, req *plu
 
 func (d *hostAPIDispatcher) checkRule() error {
 	if d.apis.RuleAPI == nil {
-		return errors.New("rule api is unavailable")
+		return d.unavailable("rule", NativePluginCapabilityAPIRule)
 	}
 	return nil
 }
@@ -795,7 +955,7 @@ func (d *hostAPIDispatcher) RuleListServerRuleActions(_ context.Context, req *pl
 
 func (d *hostAPIDispatcher) checkAdmin() error {
 	if d.apis.AdminAPI == nil {
-		return errors.New("admin api is unavailable")
+		return d.unavailable("admin", NativePluginCapabilityAPIAdmin)
 	}
 	return nil
 }
@@ -908,7 +1068,7 @@ const (
 
 func (d *hostAPIDispatcher) checkEvent() error {
 	if d.apis.EventAPI == nil {
-		return errors.New("event api is unavailable")
+		return d.unavailable("event", NativePluginCapabilityAPIEvent)
 	}
 	return nil
 }
@@ -956,6 +1116,125 @@ func (d *hostAPIDispatcher) EventPublishEvent(_ context.Context, req *pluginrpcp
 	return &pluginrpcpb.Empty{}, nil
 }
 
+func (d *hostAPIDispatcher) EventSubscribe(_ context.Context, req *pluginrpcpb.EventSubscriptionRequest) (*pluginrpcpb.EventSubscriptionResponse, error) {
+	release, err := d.admit()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if err := d.checkEvent(); err != nil {
+		return nil, err
+	}
+	active, err := d.apis.EventAPI.AddEventSubscriptions(req.GetEventTypes())
+	if err != nil {
+		return nil, err
+	}
+	return &pluginrpcpb.EventSubscriptionResponse{EventTypes: active}, nil
+}
+
+func (d *hostAPIDispatcher) EventUnsubscribe(_ context.Context, req *pluginrpcpb.EventSubscriptionRequest) (*pluginrpcpb.EventSubscriptionResponse, error) {
+	release, err := d.admit()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if err := d.checkEvent(); err != nil {
+		return nil, err
+	}
+	active, err := d.apis.EventAPI.RemoveEventSubscriptions(req.GetEventTypes())
+	if err != nil {
+		return nil, err
+	}
+	return &pluginrpcpb.EventSubscriptionResponse{EventTypes: active}, nil
+}
+
+// -- History ----------------------------------------------------------------
+
+func (d *hostAPIDispatcher) checkHistory() error {
+	if d.apis.HistoryAPI == nil {
+		return d.unavailable("history", NativePluginCapabilityAPIHistory)
+	}
+	return nil
+}
+
+// historyQueryFromWire converts a wire history query. Unset timestamps stay
+// zero so the host defaults apply.
+func historyQueryFromWire(req *pluginrpcpb.HistoryQueryRequest) HistoryQuery {
+	query := HistoryQuery{
+		PlayerID:      req.GetPlayerId(),
+		CurrentRound:  req.GetCurrentRound(),
+		TeamkillsOnly: req.GetTeamkillsOnly(),
+		Limit:         int(req.GetLimit()),
+	}
+	if req.GetSince() != nil {
+		query.Since = req.GetSince().AsTime()
+	}
+	if req.GetUntil() != nil {
+		query.Until = req.GetUntil().AsTime()
+	}
+	return query
+}
+
+func (d *hostAPIDispatcher) HistoryGetChatMessages(_ context.Context, req *pluginrpcpb.HistoryQueryRequest) (*pluginrpcpb.JSONResponse, error) {
+	release, err := d.admit()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if err := d.checkHistory(); err != nil {
+		return nil, err
+	}
+	messages, err := d.apis.HistoryAPI.GetChatMessages(historyQueryFromWire(req))
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := encodeJSONReply(messages)
+	if err != nil {
+		return nil, err
+	}
+	return &pluginrpcpb.JSONResponse{DataJson: encoded}, nil
+}
+
+func (d *hostAPIDispatcher) HistoryGetPlayerDeaths(_ context.Context, req *pluginrpcpb.HistoryQueryRequest) (*pluginrpcpb.JSONResponse, error) {
+	release, err := d.admit()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if err := d.checkHistory(); err != nil {
+		return nil, err
+	}
+	deaths, err := d.apis.HistoryAPI.GetPlayerDeaths(historyQueryFromWire(req))
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := encodeJSONReply(deaths)
+	if err != nil {
+		return nil, err
+	}
+	return &pluginrpcpb.JSONResponse{DataJson: encoded}, nil
+}
+
+func (d *hostAPIDispatcher) HistoryGetCurrentRoundStart(_ context.Context, _ *pluginrpcpb.Empty) (*pluginrpcpb.RoundStartResponse, error) {
+	release, err := d.admit()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if err := d.checkHistory(); err != nil {
+		return nil, err
+	}
+	startedAt, err := d.apis.HistoryAPI.GetCurrentRoundStart()
+	if err != nil {
+		return nil, err
+	}
+	resp := &pluginrpcpb.RoundStartResponse{}
+	if startedAt != nil {
+		resp.StartedAt = timestamppb.New(*startedAt)
+	}
+	return resp, nil
+}
+
 // -- Discord ----------------------------------------------------------------
 
 // discordChannelAllowlister is implemented by DiscordAPI wrappers that scope
@@ -968,7 +1247,7 @@ type discordChannelAllowlister interface {
 
 func (d *hostAPIDispatcher) checkDiscord() error {
 	if d.apis.DiscordAPI == nil {
-		return errors.New("discord api is unavailable")
+		return d.unavailable("discord", NativePluginCapabilityAPIDiscord)
 	}
 	return nil
 }
@@ -987,6 +1266,10 @@ func (d *hostAPIDispatcher) checkDiscordChannel(channelID string) error {
 			return nil
 		}
 	}
+	log.Warn().
+		Str("pluginID", d.pluginID).
+		Str("channelID", channelID).
+		Msg("Denied plugin Discord call to a channel outside the instance allowlist")
 	return fmt.Errorf("discord channel %s is not in this plugin instance's allowlist", channelID)
 }
 
@@ -1070,7 +1353,7 @@ func (d *hostAPIDispatcher) ConnectorCall(rpcCtx context.Context, req *pluginrpc
 	}
 	defer release()
 	if d.apis.ConnectorAPI == nil {
-		return nil, errors.New("connector api is unavailable")
+		return nil, d.unavailable("connector", NativePluginCapabilityAPIConnector)
 	}
 	if err := checkPayload(req.GetDataJson()); err != nil {
 		return nil, err
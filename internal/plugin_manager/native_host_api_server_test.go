@@ -3,13 +3,17 @@ package plugin_manager
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/time/rate"
+	"google.golang.org/grpc/metadata"
 
 	"go.codycody31.dev/squad-aegis/internal/shared/config"
+	"go.codycody31.dev/squad-aegis/pkg/pluginrpc"
 	pluginrpcpb "go.codycody31.dev/squad-aegis/pkg/pluginrpc/proto"
 )
 
@@ -163,3 +167,75 @@ func TestBuildHostAPIRateLimiterRespectsConfig(t *testing.T) {
 		})
 	}
 }
+
+// fixedServerAPI answers GetServerID; the tests below call nothing else.
+type fixedServerAPI struct {
+	ServerAPI
+	serverID uuid.UUID
+}
+
+func (s *fixedServerAPI) GetServerID() uuid.UUID { return s.serverID }
+
+type fakeServerScopeAPI struct {
+	serverIDs []uuid.UUID
+}
+
+func (f *fakeServerScopeAPI) ServerIDs() []uuid.UUID { return f.serverIDs }
+
+func (f *fakeServerScopeAPI) ServerAPI(serverID uuid.UUID) (ServerAPI, error) {
+	for _, id := range f.serverIDs {
+		if id == serverID {
+			return &fixedServerAPI{serverID: serverID}, nil
+		}
+	}
+	return nil, ErrServerOutOfScope
+}
+
+func (f *fakeServerScopeAPI) RconAPI(uuid.UUID) (RconAPI, error) {
+	return nil, errors.New("not used")
+}
+
+func TestHostAPIDispatcherRoutesServerQualifiedCalls(t *testing.T) {
+	serverA, serverB := uuid.New(), uuid.New()
+	disp := &hostAPIDispatcher{
+		apis: &PluginAPIs{ServerScopeAPI: &fakeServerScopeAPI{serverIDs: []uuid.UUID{serverA}}},
+		sem:  make(chan struct{}, maxConcurrentHostAPICalls),
+	}
+	forServer := func(serverID string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(pluginrpc.ServerIDMetadataKey, serverID))
+	}
+
+	if _, err := disp.ServerGetServerID(context.Background(), &pluginrpcpb.Empty{}); !errors.Is(err, errServerNotQualified) {
+		t.Fatalf("ServerGetServerID(unqualified) error = %v, want errServerNotQualified", err)
+	}
+	resp, err := disp.ServerGetServerID(forServer(serverA.String()), &pluginrpcpb.Empty{})
+	if err != nil {
+		t.Fatalf("ServerGetServerID(in scope) error = %v", err)
+	}
+	if resp.GetValue() != serverA.String() {
+		t.Fatalf("ServerGetServerID(in scope) = %s, want %s", resp.GetValue(), serverA)
+	}
+	if _, err := disp.ServerGetServerID(forServer(serverB.String()), &pluginrpcpb.Empty{}); !errors.Is(err, ErrServerOutOfScope) {
+		t.Fatalf("ServerGetServerID(out of scope) error = %v, want ErrServerOutOfScope", err)
+	}
+	if _, err := disp.ServerGetServerID(forServer("not-a-uuid"), &pluginrpcpb.Empty{}); err == nil {
+		t.Fatal("ServerGetServerID(invalid server) error = nil, want error")
+	}
+
+	scope, err := disp.ServerListScopeServers(context.Background(), &pluginrpcpb.Empty{})
+	if err != nil {
+		t.Fatalf("ServerListScopeServers() error = %v", err)
+	}
+	if want := `["` + serverA.String() + `"]`; string(scope.GetDataJson()) != want {
+		t.Fatalf("ServerListScopeServers() = %s, want %s", scope.GetDataJson(), want)
+	}
+
+	// Per-server instances cannot reach other servers by naming them
+	local := &hostAPIDispatcher{
+		apis: &PluginAPIs{ServerAPI: &fixedServerAPI{serverID: serverA}},
+		sem:  make(chan struct{}, maxConcurrentHostAPICalls),
+	}
+	if _, err := local.ServerGetServerID(forServer(serverB.String()), &pluginrpcpb.Empty{}); err == nil {
+		t.Fatal("per-server ServerGetServerID(qualified) error = nil, want error")
+	}
+}
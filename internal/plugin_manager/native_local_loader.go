@@ -0,0 +1,68 @@
+package plugin_manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.codycody31.dev/squad-aegis/pkg/pluginrpc"
+)
+
+// The loaders in this file build native plugin definitions outside the
+// package install flow, for plugin test kits. They skip signature checks
+// and operator review, so the host never uses them for real plugins.
+
+// LoadInProcessNativePlugin builds the definition of a native plugin that
+// runs in the host's own process. Each instance gets a fresh plugin from
+// newPlugin. Every call still crosses the plugin RPC boundary, so the
+// definition merge, manifest capability checks and HostAPI checks behave
+// as they do for a subprocess.
+func LoadInProcessNativePlugin(newPlugin func() pluginrpc.Plugin, manifest PluginPackageManifest, target PluginPackageTarget) (PluginDefinition, error) {
+	if newPlugin == nil {
+		return PluginDefinition{}, fmt.Errorf("plugin factory is nil")
+	}
+	launch := func(string, string) (*pluginSubprocessHandle, error) {
+		route := &hostAPIRoute{}
+		return &pluginSubprocessHandle{
+			hostRoute: route,
+			rpc:       pluginrpc.NewInProcessPluginClient(newPlugin(), route.invoke),
+		}, nil
+	}
+	return peekPluginDefinition(launch, "", "", manifest, target)
+}
+
+// LoadNativePluginBundle reads a plugin bundle, writes the runtime for this
+// host into runtimeDir and returns the plugin's definition. Instances run
+// as subprocesses, or as WASM modules for WASM targets, exactly as they do
+// once the bundle is installed. The bundle's signature is not checked.
+func LoadNativePluginBundle(bundlePath, runtimeDir string) (PluginDefinition, error) {
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		return PluginDefinition{}, fmt.Errorf("failed to open plugin bundle: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return PluginDefinition{}, fmt.Errorf("failed to stat plugin bundle: %w", err)
+	}
+
+	parts, err := readPluginBundle(file, info.Size())
+	if err != nil {
+		return PluginDefinition{}, err
+	}
+	if err := validatePluginManifest(parts.Manifest); err != nil {
+		return PluginDefinition{}, err
+	}
+	if err := validatePluginCompatibility(parts.Manifest, parts.SelectedTarget); err != nil {
+		return PluginDefinition{}, err
+	}
+
+	if err := os.MkdirAll(runtimeDir, 0o750); err != nil {
+		return PluginDefinition{}, fmt.Errorf("failed to create plugin runtime directory: %w", err)
+	}
+	runtimePath := filepath.Join(runtimeDir, filepath.Base(parts.LibraryName))
+	if err := writeRuntimeLibrary(runtimePath, parts.LibraryBytes); err != nil {
+		return PluginDefinition{}, err
+	}
+	return peekNativePluginDefinition(runtimePath, parts.SelectedTarget.SHA256, parts.Manifest, parts.SelectedTarget)
+}
@@ -453,25 +453,35 @@ func (pm *PluginManager) maskAndEnrichPluginInstance(instance *PluginInstance) *
 	// from the unexpected-exit reporter.
 	instance.mu.Lock()
 	maskedInstance := &PluginInstance{
-		ID:                instance.ID,
-		ServerID:          instance.ServerID,
-		PluginID:          instance.PluginID,
-		PluginName:        instance.PluginName,
-		Source:            instance.Source,
-		Official:          instance.Official,
-		Distribution:      instance.Distribution,
-		InstallState:      instance.InstallState,
-		MinHostAPIVersion: instance.MinHostAPIVersion,
-		Notes:             instance.Notes,
-		Config:            instance.Config,
-		Status:            instance.Status,
-		Enabled:           instance.Enabled,
-		LogLevel:          instance.LogLevel,
-		LastError:         instance.LastError,
-		CreatedAt:         instance.CreatedAt,
-		UpdatedAt:         instance.UpdatedAt,
-	}
+		ID:                  instance.ID,
+		ServerID:            instance.ServerID,
+		ServerIDs:           cloneServerIDs(instance.ServerIDs),
+		Global:              instance.Global,
+		PluginID:            instance.PluginID,
+		PluginName:          instance.PluginName,
+		Source:              instance.Source,
+		Official:            instance.Official,
+		Distribution:        instance.Distribution,
+		InstallState:        instance.InstallState,
+		MinHostAPIVersion:   instance.MinHostAPIVersion,
+		Notes:               instance.Notes,
+		Config:              instance.Config,
+		ConfigSchemaVersion: instance.ConfigSchemaVersion,
+		ConfigMigration:     instance.ConfigMigration.clone(),
+		Status:              instance.Status,
+		Enabled:             instance.Enabled,
+		LogLevel:            instance.LogLevel,
+		RconPolicy:          instance.RconPolicy,
+		CapabilityGrants:    cloneCapabilityGrants(instance.CapabilityGrants),
+		LastError:           instance.LastError,
+		CreatedAt:           instance.CreatedAt,
+		UpdatedAt:           instance.UpdatedAt,
+	}
+	usage := instance.usage
 	instance.mu.Unlock()
+	if usage != nil {
+		maskedInstance.Quarantine = usage.quarantined()
+	}
 	if definition, err := pm.registry.GetPlugin(instance.PluginID); err == nil {
 		enrichedDefinition := pm.enrichPluginDefinition(*definition)
 		maskedInstance.PluginName = enrichedDefinition.Name
@@ -481,6 +491,7 @@ func (pm *PluginManager) maskAndEnrichPluginInstance(instance *PluginInstance) *
 		maskedInstance.InstallState = enrichedDefinition.InstallState
 		maskedInstance.MinHostAPIVersion = enrichedDefinition.MinHostAPIVersion
 		maskedInstance.Config = enrichedDefinition.ConfigSchema.MaskSensitiveFields(maskedInstance.Config)
+		maskedInstance.PendingCapabilities = pendingCapabilities(enrichedDefinition, instance.CapabilityGrants)
 		return maskedInstance
 	}
 
@@ -648,6 +659,12 @@ func (pm *PluginManager) installPluginBundleWithFlags(ctx context.Context, archi
 		return nil, fmt.Errorf("unsigned sideloads are disabled")
 	}
 
+	// Catalog downloads have no unsafe path: the bundle must verify
+	// against a trusted, unexpired and unrevoked key.
+	if distribution == PluginDistributionCatalog && !signatureVerified {
+		return nil, fmt.Errorf("catalog plugin signature rejected: %s", formatVerificationFailure(verification.Payload, parts.PublicKeyBytes))
+	}
+
 	// In unsafe-sideload mode, signed bundles install freely; only unsigned
 	// or unverified ones need confirm_unsafe=true.
 	if distribution == PluginDistributionSideload && !signatureVerified && allowUnsafeSideload() && !confirmUnsafe {
@@ -6,6 +6,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+
+	"go.codycody31.dev/squad-aegis/pkg/pluginrpc"
 )
 
 func clonePluginPackageTargets(targets []PluginPackageTarget) []PluginPackageTarget {
@@ -51,11 +53,16 @@ func hostNativePluginCapabilitiesSet() map[string]struct{} {
 }
 
 func missingRequiredCapabilities(required []string) []string {
+	return missingCapabilitiesFrom(required, hostNativePluginCapabilitiesSet())
+}
+
+// missingCapabilitiesFrom returns the sorted required capabilities that
+// hostCapabilities does not provide.
+func missingCapabilitiesFrom(required []string, hostCapabilities map[string]struct{}) []string {
 	if len(required) == 0 {
 		return nil
 	}
 
-	hostCapabilities := hostNativePluginCapabilitiesSet()
 	missingSet := make(map[string]struct{})
 	for _, capability := range required {
 		capability = strings.TrimSpace(capability)
@@ -134,14 +141,28 @@ func preferredPluginTarget(targets []PluginPackageTarget) PluginPackageTarget {
 	return targets[0]
 }
 
+// selectedManifestTarget picks the plugin target to load on this host. A
+// compatible native target always wins; a WASM target is the fallback for
+// hosts the plugin ships no native build for.
 func selectedManifestTarget(manifest PluginPackageManifest) (PluginPackageTarget, error) {
+	target, err := selectManifestTargetFor(manifest, runtime.GOOS, runtime.GOARCH)
+	if err == nil {
+		return target, nil
+	}
+	if wasmTarget, wasmErr := selectManifestTargetFor(manifest, pluginrpc.WasmTargetOS, pluginrpc.WasmTargetArch); wasmErr == nil {
+		return wasmTarget, nil
+	}
+	return PluginPackageTarget{}, err
+}
+
+// selectManifestTargetFor picks the best compatible target for the given
+// OS and architecture.
+func selectManifestTargetFor(manifest PluginPackageManifest, hostOS, hostArch string) (PluginPackageTarget, error) {
 	targets := clonePluginPackageTargets(manifest.Targets)
 	if len(targets) == 0 {
 		return PluginPackageTarget{}, fmt.Errorf("plugin manifest is missing targets")
 	}
 
-	hostOS := runtime.GOOS
-	hostArch := runtime.GOARCH
 	var osMatches []PluginPackageTarget
 	var archMatches []PluginPackageTarget
 	var compatible []PluginPackageTarget
@@ -289,14 +310,17 @@ func validatePluginManifest(manifest PluginPackageManifest) error {
 }
 
 func validatePluginCompatibility(manifest PluginPackageManifest, target PluginPackageTarget) error {
-	if runtime.GOOS != "linux" {
-		return fmt.Errorf("native plugins are only supported on Linux")
-	}
-	if target.TargetOS != runtime.GOOS {
-		return fmt.Errorf("plugin targets %s but host is %s", target.TargetOS, runtime.GOOS)
-	}
-	if target.TargetArch != runtime.GOARCH {
-		return fmt.Errorf("plugin targets %s but host architecture is %s", target.TargetArch, runtime.GOARCH)
+	// WASM modules run inside the host on every OS and architecture.
+	if !isWasmTarget(target) {
+		if runtime.GOOS != "linux" {
+			return fmt.Errorf("native plugins are only supported on Linux")
+		}
+		if target.TargetOS != runtime.GOOS {
+			return fmt.Errorf("plugin targets %s but host is %s", target.TargetOS, runtime.GOOS)
+		}
+		if target.TargetArch != runtime.GOARCH {
+			return fmt.Errorf("plugin targets %s but host architecture is %s", target.TargetArch, runtime.GOARCH)
+		}
 	}
 	if target.MinHostAPIVersion > NativePluginHostAPIVersion {
 		return fmt.Errorf("plugin requires host API version >= %d, but host provides %d", target.MinHostAPIVersion, NativePluginHostAPIVersion)
@@ -18,6 +18,7 @@ import (
 
 	"go.codycody31.dev/squad-aegis/internal/event_manager"
 	"go.codycody31.dev/squad-aegis/internal/shared/config"
+	connectorrpcpb "go.codycody31.dev/squad-aegis/pkg/connectorrpc/proto"
 )
 
 // buildExampleBinary is a test helper that compiles a Go package to a binary
@@ -96,11 +97,17 @@ func helloConnectorTestManifest() ConnectorPackageManifest {
 	}
 }
 
+// helloConnectorTestTarget mirrors scripts/package-example-native-connector.sh:
+// the example logs and publishes events through the connector host API.
 func helloConnectorTestTarget() PluginPackageTarget {
 	return PluginPackageTarget{
 		MinHostAPIVersion: NativeConnectorHostAPIVersion,
-		TargetOS:          runtime.GOOS,
-		TargetArch:        runtime.GOARCH,
+		RequiredCapabilities: []string{
+			NativeConnectorCapabilityAPILog,
+			NativeConnectorCapabilityAPIEvent,
+		},
+		TargetOS:   runtime.GOOS,
+		TargetArch: runtime.GOARCH,
 	}
 }
 
@@ -173,6 +180,55 @@ func TestPeekNativeConnectorDefinitionFromRealSubprocess(t *testing.T) {
 	}
 }
 
+func TestSubprocessConnectorPublishesEventsThroughHostAPI(t *testing.T) {
+	path, sha := buildExampleBinary(t, "examples/native-connector-hello")
+
+	def, err := peekNativeConnectorDefinition(path, sha, helloConnectorTestManifest(), helloConnectorTestTarget())
+	if err != nil {
+		t.Fatalf("peekNativeConnectorDefinition() error = %v", err)
+	}
+
+	serverID := uuid.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	events := event_manager.NewEventManager(ctx, 10)
+	subscriber := events.Subscribe(event_manager.EventFilter{}, &serverID, 1)
+	pm := &PluginManager{eventManager: events}
+	hostAPI := pm.newConnectorHostAPI(def)
+	hostAPI.servers = &fakeServerDirectory{servers: []*connectorrpcpb.ServerSummary{{Id: serverID.String(), Name: "Main"}}}
+
+	instance := def.CreateInstance()
+	instance.(hostAPIConnector).setHostAPI(hostAPI)
+	t.Cleanup(func() { _ = instance.Stop() })
+	if err := instance.Initialize(map[string]interface{}{}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	invokeCtx, invokeCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer invokeCancel()
+	resp, err := instance.(InvokableConnector).Invoke(invokeCtx, &ConnectorInvokeRequest{V: "1", Data: map[string]interface{}{
+		"action":    "announce",
+		"server_id": serverID.String(),
+		"message":   "hi",
+	}})
+	if err != nil {
+		t.Fatalf("Invoke(announce) error = %v", err)
+	}
+	if resp == nil || !resp.OK {
+		t.Fatalf("Invoke(announce) response = %#v, want OK", resp)
+	}
+
+	select {
+	case event := <-subscriber.Channel:
+		custom, ok := event.Data.(*event_manager.ConnectorCustomEventData)
+		if !ok || custom.EventType != "CONNECTOR_com.squad-aegis.connectors.examples.hello_HELLO" || custom.Data["message"] != "hi" {
+			t.Fatalf("published event = %#v, want the hello connector's HELLO event", event.Data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("connector event was not published")
+	}
+}
+
 // fakeRconAPI records the last SendWarningToPlayer call. Only the minimal
 // surface used by the example plugin is implemented; the rest return an
 // error so the dispatcher can trip on unexpected calls.
@@ -391,11 +447,19 @@ func TestSubprocessPluginLifecycleWithHostAPICallbacks(t *testing.T) {
 		LogAPI:    logAPI,
 	}
 
+	usage := newPluginResourceUsage()
+	instance.(resourceAccountedPlugin).setResourceUsage(usage)
+
 	if err := instance.Initialize(map[string]interface{}{"trigger": "!hello", "response": "hi"}, apis); err != nil {
 		t.Fatalf("Initialize() error = %v", err)
 	}
 	t.Cleanup(func() { _ = instance.Stop() })
 
+	pid := instance.(processReporter).processID()
+	if pid <= 0 {
+		t.Fatalf("processID() = %d, want the subprocess PID", pid)
+	}
+
 	if err := instance.Start(context.Background()); err != nil {
 		t.Fatalf("Start() error = %v", err)
 	}
@@ -429,7 +493,24 @@ func TestSubprocessPluginLifecycleWithHostAPICallbacks(t *testing.T) {
 		t.Fatalf("LogAPI.Info count = %d, want 1", logAPI.infoCount.Load())
 	}
 
+	metrics := usage.snapshot()
+	if got := metrics.HostAPICalls["RconSendWarningToPlayer"]; got != 1 {
+		t.Fatalf("HostAPICalls[RconSendWarningToPlayer] = %d, want 1", got)
+	}
+	if got := metrics.HostAPICalls["LogInfo"]; got != 1 {
+		t.Fatalf("HostAPICalls[LogInfo] = %d, want 1", got)
+	}
+	if metrics.RconCommands != 1 {
+		t.Fatalf("RconCommands = %d, want 1", metrics.RconCommands)
+	}
+	if _, rss, err := readProcessUsage(pid); err == nil && rss == 0 {
+		t.Fatal("readProcessUsage() rss = 0 for a live subprocess")
+	}
+
 	if err := instance.Stop(); err != nil {
 		t.Fatalf("Stop() error = %v", err)
 	}
+	if got := instance.(processReporter).processID(); got != 0 {
+		t.Fatalf("processID() after Stop = %d, want 0", got)
+	}
 }
@@ -11,6 +11,7 @@ import (
 	"os/exec"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
@@ -47,21 +48,86 @@ func commandFromVerifiedRuntimeFile(verifiedFile *os.File) *exec.Cmd {
 }
 
 // pluginSubprocessHandle holds the live subprocess + the gRPC client stub.
-// A handle must be released via Kill() when the plugin is unloaded.
+// For WASM plugins wasm holds the module instance instead of a subprocess.
+// Plugins without a broker (WASM and in-process) reach their host APIs
+// through hostRoute. A handle must be released via Kill() when the plugin
+// is unloaded.
 type pluginSubprocessHandle struct {
-	client *goplugin.Client
-	rpc    *pluginrpc.PluginGRPCClient
+	client    *goplugin.Client
+	wasm      *wasmModule
+	hostRoute *hostAPIRoute
+	rpc       *pluginrpc.PluginGRPCClient
+}
+
+// pluginLauncher starts a verified plugin runtime and returns its handle.
+type pluginLauncher func(runtimePath, expectedSHA256 string) (*pluginSubprocessHandle, error)
+
+// launcherForTarget picks the launcher for a manifest target. The launcher
+// variables are read on each launch so tests can swap them after a load.
+func launcherForTarget(target PluginPackageTarget) pluginLauncher {
+	if isWasmTarget(target) {
+		return func(runtimePath, expectedSHA256 string) (*pluginSubprocessHandle, error) {
+			return wasmPluginModuleLauncher(runtimePath, expectedSHA256)
+		}
+	}
+	return func(runtimePath, expectedSHA256 string) (*pluginSubprocessHandle, error) {
+		return nativePluginSubprocessLauncher(runtimePath, expectedSHA256)
+	}
 }
 
 // Kill terminates the subprocess. Safe to call multiple times.
 func (h *pluginSubprocessHandle) Kill() {
-	if h == nil || h.client == nil {
+	if h == nil {
+		return
+	}
+	if h.wasm != nil {
+		h.wasm.Close()
+	}
+	if h.client == nil {
 		return
 	}
 	killProcessGroup(h.client)
 	h.client.Kill()
 }
 
+// exited reports whether the subprocess or module has stopped. A handle
+// with neither is never considered exited.
+func (h *pluginSubprocessHandle) exited() bool {
+	switch {
+	case h == nil:
+		return false
+	case h.wasm != nil:
+		return h.wasm.exited()
+	case h.client != nil:
+		return h.client.Exited()
+	default:
+		return false
+	}
+}
+
+// startHostAPI serves the host APIs to the plugin. Subprocesses reach them
+// over a go-plugin broker; other plugins through their host route, which
+// needs no broker ID.
+func (h *pluginSubprocessHandle) startHostAPI(apis *PluginAPIs, pluginID string, usage *pluginResourceUsage) (*hostAPIServer, uint32, error) {
+	if h.hostRoute != nil {
+		return h.hostRoute.serve(newHostAPIDispatcher(apis, pluginID, usage)), 0, nil
+	}
+	return startHostAPIServer(h.rpc, apis, pluginID, usage)
+}
+
+// pid returns the subprocess's process ID, or 0 for plugins that do not
+// run in their own process.
+func (h *pluginSubprocessHandle) pid() int {
+	if h == nil || h.client == nil {
+		return 0
+	}
+	rc := h.client.ReattachConfig()
+	if rc == nil {
+		return 0
+	}
+	return rc.Pid
+}
+
 // launchNativePluginSubprocess verifies the runtime binary's checksum and
 // spawns it via hashicorp/go-plugin. On success the returned handle's rpc
 // client can be used to drive the plugin; on failure the subprocess (if any)
@@ -165,10 +231,13 @@ type subprocessPluginShim struct {
 	definition   PluginDefinition
 	runtimePath  string
 	expectedHash string
+	launch       pluginLauncher
 
 	mu              sync.Mutex
 	handle          *pluginSubprocessHandle
 	hostAPISvc      *hostAPIServer
+	usage           *pluginResourceUsage
+	pid             atomic.Int64 // subprocess PID, read by the resource monitor without mu
 	status          PluginStatus
 	onExit          func(error)
 	stopWatcher     chan struct{}
@@ -199,7 +268,13 @@ func (s *subprocessPluginShim) OnUnexpectedExit(fn func(error)) {
 // connectors). Any mismatch between manifest.plugin_id and the PluginID
 // echoed by the subprocess aborts the load.
 func peekNativePluginDefinition(runtimePath, expectedSHA256 string, manifest PluginPackageManifest, target PluginPackageTarget) (PluginDefinition, error) {
-	handle, err := nativePluginSubprocessLauncher(runtimePath, expectedSHA256)
+	return peekPluginDefinition(launcherForTarget(target), runtimePath, expectedSHA256, manifest, target)
+}
+
+// peekPluginDefinition is peekNativePluginDefinition with an explicit
+// launcher.
+func peekPluginDefinition(launch pluginLauncher, runtimePath, expectedSHA256 string, manifest PluginPackageManifest, target PluginPackageTarget) (PluginDefinition, error) {
+	handle, err := launch(runtimePath, expectedSHA256)
 	if err != nil {
 		return PluginDefinition{}, err
 	}
@@ -228,20 +303,46 @@ func peekNativePluginDefinition(runtimePath, expectedSHA256 string, manifest Plu
 	if err != nil {
 		return PluginDefinition{}, err
 	}
+	if isWasmTarget(target) && hostDef.LongRunning {
+		// A WASM module only runs while the host is calling into it, so
+		// there is nothing to keep a background loop going.
+		return PluginDefinition{}, fmt.Errorf("plugin %q: wasm plugins cannot be long running", manifest.PluginID)
+	}
 
 	captured := hostDef
+	if wire.MigratesConfig {
+		captured.MigrateConfig = func(fromVersion int, config map[string]interface{}) (map[string]interface{}, error) {
+			return migrateNativePluginConfig(launch, runtimePath, expectedSHA256, fromVersion, config)
+		}
+	}
 	captured.CreateInstance = func() Plugin {
 		return &subprocessPluginShim{
 			pluginID:     captured.ID,
 			definition:   captured,
 			runtimePath:  runtimePath,
 			expectedHash: expectedSHA256,
+			launch:       launch,
 			status:       PluginStatusStopped,
 		}
 	}
 	return captured, nil
 }
 
+// migrateNativePluginConfig runs the plugin's MigrateConfig RPC in a
+// throwaway subprocess. Migrations happen before Initialize, so there is no
+// instance subprocess to reuse and the plugin gets no host APIs.
+func migrateNativePluginConfig(launch pluginLauncher, runtimePath, expectedSHA256 string, fromVersion int, config map[string]interface{}) (map[string]interface{}, error) {
+	handle, err := launch(runtimePath, expectedSHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to spawn plugin subprocess: %w", err)
+	}
+	defer handle.Kill()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return handle.rpc.MigrateConfig(ctx, fromVersion, config)
+}
+
 // GetDefinition returns the cached definition captured during peek.
 func (s *subprocessPluginShim) GetDefinition() PluginDefinition {
 	return s.definition
@@ -258,12 +359,16 @@ func (s *subprocessPluginShim) Initialize(config map[string]interface{}, apis *P
 		return errors.New("plugin subprocess already initialized")
 	}
 
-	handle, err := nativePluginSubprocessLauncher(s.runtimePath, s.expectedHash)
+	launch := s.launch
+	if launch == nil {
+		launch = nativePluginSubprocessLauncher
+	}
+	handle, err := launch(s.runtimePath, s.expectedHash)
 	if err != nil {
 		return fmt.Errorf("failed to spawn plugin subprocess: %w", err)
 	}
 
-	svc, brokerID, err := startHostAPIServer(handle.rpc, apis, s.pluginID)
+	svc, brokerID, err := handle.startHostAPI(apis, s.pluginID, s.usage)
 	if err != nil {
 		handle.Kill()
 		return fmt.Errorf("failed to start host api server: %w", err)
@@ -291,6 +396,7 @@ func (s *subprocessPluginShim) Initialize(config map[string]interface{}, apis *P
 
 	s.handle = handle
 	s.hostAPISvc = svc
+	s.pid.Store(int64(handle.pid()))
 	s.intentional = false
 	s.stopWatcher = make(chan struct{})
 	s.stopWatcherOnce = &sync.Once{}
@@ -317,10 +423,10 @@ func (s *subprocessPluginShim) watchHealth(handle *pluginSubprocessHandle, stopC
 		case <-stopCh:
 			return
 		case <-ticker.C:
-			if handle == nil || handle.client == nil {
+			if handle == nil || (handle.client == nil && handle.wasm == nil) {
 				return
 			}
-			if handle.client.Exited() {
+			if handle.exited() {
 				s.mu.Lock()
 				cb := s.onExit
 				intentional := s.intentional
@@ -451,6 +557,7 @@ func (s *subprocessPluginShim) Stop() error {
 	if s.handle == handle {
 		s.handle = nil
 		s.hostAPISvc = nil
+		s.pid.Store(0)
 		s.stopWatcher = nil
 		s.stopWatcherOnce = nil
 		s.watcherDone = nil
@@ -493,6 +600,7 @@ func (s *subprocessPluginShim) Kill() error {
 	if s.handle == handle {
 		s.handle = nil
 		s.hostAPISvc = nil
+		s.pid.Store(0)
 		s.stopWatcher = nil
 		s.stopWatcherOnce = nil
 		s.watcherDone = nil
@@ -501,10 +609,29 @@ func (s *subprocessPluginShim) Kill() error {
 	return nil
 }
 
-// Compile-time guard: subprocessPluginShim satisfies killablePlugin so the
+// setResourceUsage sets where the shim's HostAPI server counts calls. It
+// takes effect at the next Initialize.
+func (s *subprocessPluginShim) setResourceUsage(usage *pluginResourceUsage) {
+	s.mu.Lock()
+	s.usage = usage
+	s.mu.Unlock()
+}
+
+// processID returns the subprocess PID, or 0 while none is running or for
+// WASM and in-process runtimes.
+func (s *subprocessPluginShim) processID() int {
+	return int(s.pid.Load())
+}
+
+// Compile-time guards: subprocessPluginShim satisfies killablePlugin so the
 // host-side Stop timeout fallback at plugin_manager.stopPluginInstance can
-// SIGKILL a wedged subprocess.
-var _ killablePlugin = (*subprocessPluginShim)(nil)
+// SIGKILL a wedged subprocess, and records resource usage for the
+// resource monitor.
+var (
+	_ killablePlugin          = (*subprocessPluginShim)(nil)
+	_ resourceAccountedPlugin = (*subprocessPluginShim)(nil)
+	_ processReporter         = (*subprocessPluginShim)(nil)
+)
 
 // HandleEvent forwards an event to the subprocess via RPC. The host caller
 // owns the timeout; we bound the call here so a wedged plugin handler
@@ -621,6 +748,33 @@ func (s *subprocessPluginShim) ExecuteCommand(commandID string, params map[strin
 	}, nil
 }
 
+// HandleHTTP forwards a request for a declared route to the plugin.
+func (s *subprocessPluginShim) HandleHTTP(ctx context.Context, req *PluginHTTPRequest) (*PluginHTTPResponse, error) {
+	s.mu.Lock()
+	handle := s.handle
+	s.mu.Unlock()
+	if handle == nil {
+		return nil, errors.New("plugin subprocess is not initialized")
+	}
+	wire, err := handle.rpc.HandleHTTP(ctx, &pluginrpc.HTTPRequest{
+		Method: req.Method,
+		Path:   req.Path,
+		Route:  req.Route,
+		Params: req.Params,
+		Query:  req.Query,
+		Body:   req.Body,
+		UserID: req.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &PluginHTTPResponse{
+		Status:      wire.Status,
+		ContentType: wire.ContentType,
+		Body:        wire.Body,
+	}, nil
+}
+
 // GetCommandExecutionStatus fetches the status of an async command.
 func (s *subprocessPluginShim) GetCommandExecutionStatus(executionID string) (*CommandExecutionStatus, error) {
 	s.mu.Lock()
@@ -683,6 +837,7 @@ func mergeWirePluginIntoHost(wire pluginrpc.PluginDefinition, manifest PluginPac
 
 		// Behavior comes from the subprocess runtime definition.
 		AllowMultipleInstances: wire.AllowMultipleInstances,
+		AllowGlobalInstances:   wire.AllowGlobalInstances,
 		RequiredConnectors:     append([]string(nil), wire.RequiredConnectors...),
 		OptionalConnectors:     append([]string(nil), wire.OptionalConnectors...),
 		LongRunning:            wire.LongRunning,
@@ -698,6 +853,27 @@ func mergeWirePluginIntoHost(wire pluginrpc.PluginDefinition, manifest PluginPac
 	}
 	hostDef.ConfigSchema = schema
 
+	for _, route := range wire.HTTPRoutes {
+		hostDef.HTTPRoutes = append(hostDef.HTTPRoutes, PluginHTTPRoute{
+			Method:      route.Method,
+			Path:        route.Path,
+			Access:      route.Access,
+			Description: route.Description,
+		})
+	}
+	if len(wire.Panels) > 0 {
+		panelsJSON, err := json.Marshal(wire.Panels)
+		if err != nil {
+			return PluginDefinition{}, fmt.Errorf("failed to marshal plugin panels: %w", err)
+		}
+		if err := json.Unmarshal(panelsJSON, &hostDef.Panels); err != nil {
+			return PluginDefinition{}, fmt.Errorf("failed to parse plugin panels: %w", err)
+		}
+	}
+	if err := validatePluginHTTPSurface(hostDef); err != nil {
+		return PluginDefinition{}, err
+	}
+
 	hostDef.Events = make([]event_manager.EventType, 0, len(wire.Events))
 	declaredCaps := capabilitySet(target.RequiredCapabilities)
 	for _, ev := range wire.Events {
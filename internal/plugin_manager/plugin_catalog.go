@@ -0,0 +1,665 @@
+package plugin_manager
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.codycody31.dev/squad-aegis/internal/plugin_signing"
+	"go.codycody31.dev/squad-aegis/internal/shared/config"
+	"go.codycody31.dev/squad-aegis/pkg/pluginrpc"
+)
+
+const (
+	// maxCatalogFileBytes bounds each index file fetched from a catalog
+	maxCatalogFileBytes = 4 * 1024 * 1024
+	catalogFetchTimeout = 2 * time.Minute
+	// minCatalogRefreshInterval stops a misconfigured interval hammering
+	// catalog hosts
+	minCatalogRefreshInterval = time.Minute
+)
+
+// ErrCatalogPluginNotFound is returned when no configured catalog offers the
+// requested plugin, or the requested version of it.
+var ErrCatalogPluginNotFound = errors.New("plugin not found in any configured catalog")
+
+// catalogHTTPClient fetches catalog indexes and bundles over HTTP. Redirects
+// must stay on https, so a remote catalog cannot bounce a fetch onto another
+// scheme.
+var catalogHTTPClient = &http.Client{
+	Timeout:       catalogFetchTimeout,
+	CheckRedirect: checkCatalogRedirect,
+}
+
+// catalogFileClient serves file:// catalogs from the local filesystem so a
+// catalog can be a static directory. It is kept apart from catalogHTTPClient
+// so nothing fetched over the network can reach local files.
+var catalogFileClient = &http.Client{
+	Transport: http.NewFileTransport(http.Dir("/")),
+	Timeout:   catalogFetchTimeout,
+}
+
+// checkCatalogRedirect rejects redirects that change scheme or leave https.
+func checkCatalogRedirect(request *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	if request.URL.Scheme != via[0].URL.Scheme || request.URL.Scheme != "https" {
+		return fmt.Errorf("refusing to follow catalog redirect from %s to %s", via[len(via)-1].URL.Redacted(), request.URL.Redacted())
+	}
+	return nil
+}
+
+// PluginCatalogIndex is the index.json document a plugin catalog publishes.
+// It is signed the same way as a bundle manifest: index.signed.json wraps it
+// and index.sig / index.pub carry the signature and public key.
+type PluginCatalogIndex struct {
+	Name    string               `json:"name"`
+	Plugins []PluginCatalogEntry `json:"plugins"`
+}
+
+// PluginCatalogEntry is a plugin offered by a catalog
+type PluginCatalogEntry struct {
+	PluginID    string                 `json:"plugin_id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Author      string                 `json:"author,omitempty"`
+	Homepage    string                 `json:"homepage,omitempty"`
+	Versions    []PluginCatalogVersion `json:"versions"`
+}
+
+// PluginCatalogVersion is a published version of a catalog plugin
+type PluginCatalogVersion struct {
+	Version           string                  `json:"version"`
+	MinHostAPIVersion int                     `json:"min_host_api_version,omitempty"`
+	ReleasedAt        *time.Time              `json:"released_at,omitempty"`
+	Changelog         string                  `json:"changelog,omitempty"`
+	Downloads         []PluginCatalogDownload `json:"downloads"`
+}
+
+// PluginCatalogDownload is the bundle for one target. URL may be relative to
+// the index.
+type PluginCatalogDownload struct {
+	TargetOS   string `json:"target_os"`
+	TargetArch string `json:"target_arch"`
+	URL        string `json:"url"`
+	SHA256     string `json:"sha256"`
+	Size       int64  `json:"size,omitempty"`
+}
+
+// PluginCatalogStatus reports the state of a configured catalog
+type PluginCatalogStatus struct {
+	URL         string     `json:"url"`
+	Name        string     `json:"name,omitempty"`
+	KeyID       string     `json:"key_id,omitempty"`
+	PluginCount int        `json:"plugin_count"`
+	FetchedAt   *time.Time `json:"fetched_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+}
+
+// PluginCatalogListing is a catalog plugin with its install and update state
+// on this host. LatestVersion and Versions only include versions with a
+// download for this host.
+type PluginCatalogListing struct {
+	PluginID         string                 `json:"plugin_id"`
+	Name             string                 `json:"name"`
+	Description      string                 `json:"description,omitempty"`
+	Author           string                 `json:"author,omitempty"`
+	Homepage         string                 `json:"homepage,omitempty"`
+	CatalogURL       string                 `json:"catalog_url"`
+	CatalogName      string                 `json:"catalog_name,omitempty"`
+	Compatible       bool                   `json:"compatible"`
+	LatestVersion    string                 `json:"latest_version,omitempty"`
+	Versions         []PluginCatalogVersion `json:"versions"`
+	InstalledVersion string                 `json:"installed_version,omitempty"`
+	InstallState     PluginInstallState     `json:"install_state,omitempty"`
+	UpdateAvailable  bool                   `json:"update_available"`
+}
+
+// pluginCatalog is a fetched catalog. A catalog that fails to refresh keeps
+// serving the last index that verified.
+type pluginCatalog struct {
+	url    *url.URL
+	index  *PluginCatalogIndex
+	status PluginCatalogStatus
+}
+
+// configuredCatalogURLs returns the catalog index URLs from config
+func configuredCatalogURLs() []string {
+	if config.Config == nil {
+		return nil
+	}
+	var urls []string
+	for _, entry := range strings.Split(config.Config.Plugins.CatalogUrls, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			urls = append(urls, entry)
+		}
+	}
+	return urls
+}
+
+func catalogRefreshInterval() time.Duration {
+	if config.Config == nil || config.Config.Plugins.CatalogRefreshSeconds <= 0 {
+		return 0
+	}
+	interval := time.Duration(config.Config.Plugins.CatalogRefreshSeconds) * time.Second
+	if interval < minCatalogRefreshInterval {
+		interval = minCatalogRefreshInterval
+	}
+	return interval
+}
+
+// parseCatalogURL validates a configured catalog URL. A URL ending in a
+// slash points at the catalog directory and gets index.json appended.
+func parseCatalogURL(raw string) (*url.URL, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid catalog URL: %w", err)
+	}
+	switch parsed.Scheme {
+	case "http", "https", "file":
+	default:
+		return nil, fmt.Errorf("catalog URL scheme must be http, https or file")
+	}
+	if strings.HasSuffix(parsed.Path, "/") {
+		parsed.Path += plugin_signing.CatalogIndexFile
+	}
+	return parsed, nil
+}
+
+// startPluginCatalogRefresher fetches the configured catalogs in the
+// background and re-fetches them on the configured interval until the
+// manager stops.
+func (pm *PluginManager) startPluginCatalogRefresher() {
+	if len(configuredCatalogURLs()) == 0 {
+		return
+	}
+	interval := catalogRefreshInterval()
+
+	go func() {
+		pm.RefreshPluginCatalogs(pm.ctx)
+		if interval <= 0 {
+			return
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-pm.ctx.Done():
+				return
+			case <-ticker.C:
+				pm.RefreshPluginCatalogs(pm.ctx)
+			}
+		}
+	}()
+}
+
+// RefreshPluginCatalogs re-fetches every configured catalog and returns
+// their status. Update availability for installed packages is logged once
+// per new version.
+func (pm *PluginManager) RefreshPluginCatalogs(ctx context.Context) []PluginCatalogStatus {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	pm.catalogRefreshMu.Lock()
+	defer pm.catalogRefreshMu.Unlock()
+
+	pm.catalogMu.RLock()
+	previous := make(map[string]*pluginCatalog, len(pm.catalogs))
+	for _, catalog := range pm.catalogs {
+		previous[catalog.status.URL] = catalog
+	}
+	pm.catalogMu.RUnlock()
+
+	catalogs := make([]*pluginCatalog, 0, len(previous))
+	for _, raw := range configuredCatalogURLs() {
+		catalog := &pluginCatalog{status: PluginCatalogStatus{URL: raw}}
+		if prior, ok := previous[raw]; ok {
+			copied := *prior
+			catalog = &copied
+		}
+
+		index, payload, indexURL, err := fetchPluginCatalog(ctx, raw)
+		if err != nil {
+			log.Warn().Err(err).Str("catalog", raw).Msg("Failed to refresh plugin catalog")
+			catalog.status.LastError = err.Error()
+		} else {
+			fetchedAt := time.Now()
+			expiresAt := payload.ExpiresAt
+			catalog.url = indexURL
+			catalog.index = index
+			catalog.status = PluginCatalogStatus{
+				URL:         raw,
+				Name:        index.Name,
+				KeyID:       payload.KeyID,
+				PluginCount: len(index.Plugins),
+				FetchedAt:   &fetchedAt,
+				ExpiresAt:   &expiresAt,
+			}
+		}
+		catalogs = append(catalogs, catalog)
+	}
+
+	pm.catalogMu.Lock()
+	pm.catalogs = catalogs
+	pm.catalogMu.Unlock()
+
+	pm.logPluginCatalogUpdates()
+
+	return pm.PluginCatalogStatuses()
+}
+
+// PluginCatalogStatuses returns the status of each configured catalog as of
+// its last refresh.
+func (pm *PluginManager) PluginCatalogStatuses() []PluginCatalogStatus {
+	pm.catalogMu.RLock()
+	defer pm.catalogMu.RUnlock()
+
+	statuses := make([]PluginCatalogStatus, 0, len(pm.catalogs))
+	for _, catalog := range pm.catalogs {
+		statuses = append(statuses, catalog.status)
+	}
+	return statuses
+}
+
+// fetchPluginCatalog downloads a catalog index and its signature files and
+// returns the index only when the signature verifies.
+func fetchPluginCatalog(ctx context.Context, raw string) (*PluginCatalogIndex, plugin_signing.SignedManifestPayload, *url.URL, error) {
+	var payload plugin_signing.SignedManifestPayload
+
+	indexURL, err := parseCatalogURL(raw)
+	if err != nil {
+		return nil, payload, nil, err
+	}
+
+	files := make(map[string][]byte, 4)
+	for _, name := range []string{
+		plugin_signing.CatalogIndexFile,
+		plugin_signing.CatalogSignedPayloadFile,
+		plugin_signing.CatalogSignatureFile,
+		plugin_signing.CatalogPublicKeyFile,
+	} {
+		fileURL := indexURL
+		if name != plugin_signing.CatalogIndexFile {
+			fileURL = indexURL.ResolveReference(&url.URL{Path: name})
+		}
+		data, err := fetchCatalogFile(ctx, fileURL, maxCatalogFileBytes)
+		if err != nil {
+			return nil, payload, nil, err
+		}
+		files[name] = data
+	}
+	indexBytes := files[plugin_signing.CatalogIndexFile]
+
+	verification, err := verifyManifestSignature(
+		files[plugin_signing.CatalogSignedPayloadFile],
+		indexBytes,
+		files[plugin_signing.CatalogSignatureFile],
+		files[plugin_signing.CatalogPublicKeyFile],
+	)
+	if err != nil {
+		return nil, payload, nil, fmt.Errorf("failed to verify catalog index: %w", err)
+	}
+	if !verification.Verified {
+		return nil, payload, nil, fmt.Errorf("catalog index signature rejected: %s", formatVerificationFailure(verification.Payload, files[plugin_signing.CatalogPublicKeyFile]))
+	}
+
+	var index PluginCatalogIndex
+	if err := json.Unmarshal(indexBytes, &index); err != nil {
+		return nil, payload, nil, fmt.Errorf("invalid catalog index: %w", err)
+	}
+	if err := validatePluginCatalogIndex(&index); err != nil {
+		return nil, payload, nil, err
+	}
+
+	return &index, verification.Payload, indexURL, nil
+}
+
+// fetchCatalogFile GETs a catalog file, refusing bodies over limit bytes
+func fetchCatalogFile(ctx context.Context, fileURL *url.URL, limit int64) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	client := catalogHTTPClient
+	if fileURL.Scheme == "file" {
+		client = catalogFileClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", fileURL.Redacted(), err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", fileURL.Redacted(), response.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(response.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", fileURL.Redacted(), err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s is larger than %d bytes", fileURL.Redacted(), limit)
+	}
+	return data, nil
+}
+
+// validatePluginCatalogIndex rejects indexes with missing identifiers,
+// duplicate plugins or versions, or downloads without a checksum.
+func validatePluginCatalogIndex(index *PluginCatalogIndex) error {
+	plugins := make(map[string]bool, len(index.Plugins))
+	for _, entry := range index.Plugins {
+		if strings.TrimSpace(entry.PluginID) == "" {
+			return fmt.Errorf("catalog index has a plugin without a plugin_id")
+		}
+		if plugins[entry.PluginID] {
+			return fmt.Errorf("catalog index lists plugin %s more than once", entry.PluginID)
+		}
+		plugins[entry.PluginID] = true
+
+		versions := make(map[string]bool, len(entry.Versions))
+		for _, version := range entry.Versions {
+			if strings.TrimSpace(version.Version) == "" {
+				return fmt.Errorf("catalog plugin %s has a version without a version string", entry.PluginID)
+			}
+			if versions[version.Version] {
+				return fmt.Errorf("catalog plugin %s lists version %s more than once", entry.PluginID, version.Version)
+			}
+			versions[version.Version] = true
+
+			for _, download := range version.Downloads {
+				if download.TargetOS == "" || download.TargetArch == "" || download.URL == "" {
+					return fmt.Errorf("catalog plugin %s %s has a download without a target or URL", entry.PluginID, version.Version)
+				}
+				if len(download.SHA256) != sha256.Size*2 {
+					return fmt.Errorf("catalog plugin %s %s download for %s/%s has an invalid sha256", entry.PluginID, version.Version, download.TargetOS, download.TargetArch)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// hostDownload returns the version's download for this host, if any. A
+// native download is preferred over a WASM one, matching the target
+// selection at install time.
+func (v PluginCatalogVersion) hostDownload() (PluginCatalogDownload, bool) {
+	if v.MinHostAPIVersion > NativePluginHostAPIVersion {
+		return PluginCatalogDownload{}, false
+	}
+	var wasm *PluginCatalogDownload
+	for _, download := range v.Downloads {
+		if download.TargetOS == runtime.GOOS && download.TargetArch == runtime.GOARCH {
+			return download, true
+		}
+		if download.TargetOS == pluginrpc.WasmTargetOS && download.TargetArch == pluginrpc.WasmTargetArch && wasm == nil {
+			downloadCopy := download
+			wasm = &downloadCopy
+		}
+	}
+	if wasm != nil {
+		return *wasm, true
+	}
+	return PluginCatalogDownload{}, false
+}
+
+// compatibleVersions returns the entry's versions with a download for this
+// host, newest first.
+func (e PluginCatalogEntry) compatibleVersions() []PluginCatalogVersion {
+	versions := make([]PluginCatalogVersion, 0, len(e.Versions))
+	for _, version := range e.Versions {
+		if _, ok := version.hostDownload(); ok {
+			versions = append(versions, version)
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return compareCatalogVersions(versions[i].Version, versions[j].Version) > 0
+	})
+	return versions
+}
+
+// ListPluginCatalog returns every plugin offered by the configured catalogs.
+// When several catalogs offer the same plugin, the first configured wins.
+func (pm *PluginManager) ListPluginCatalog() []PluginCatalogListing {
+	pm.catalogMu.RLock()
+	catalogs := append([]*pluginCatalog(nil), pm.catalogs...)
+	pm.catalogMu.RUnlock()
+
+	seen := make(map[string]bool)
+	listings := []PluginCatalogListing{}
+	for _, catalog := range catalogs {
+		if catalog.index == nil {
+			continue
+		}
+		for _, entry := range catalog.index.Plugins {
+			if seen[entry.PluginID] {
+				continue
+			}
+			seen[entry.PluginID] = true
+			listings = append(listings, pm.catalogListing(catalog, entry))
+		}
+	}
+
+	sort.Slice(listings, func(i, j int) bool {
+		return listings[i].Name < listings[j].Name
+	})
+	return listings
+}
+
+func (pm *PluginManager) catalogListing(catalog *pluginCatalog, entry PluginCatalogEntry) PluginCatalogListing {
+	listing := PluginCatalogListing{
+		PluginID:    entry.PluginID,
+		Name:        entry.Name,
+		Description: entry.Description,
+		Author:      entry.Author,
+		Homepage:    entry.Homepage,
+		CatalogURL:  catalog.status.URL,
+		CatalogName: catalog.status.Name,
+		Versions:    entry.compatibleVersions(),
+	}
+	if listing.Name == "" {
+		listing.Name = entry.PluginID
+	}
+	if len(listing.Versions) > 0 {
+		listing.Compatible = true
+		listing.LatestVersion = listing.Versions[0].Version
+	}
+
+	if pkg := pm.getNativePackage(entry.PluginID); pkg != nil {
+		listing.InstalledVersion = pkg.Version
+		listing.InstallState = pkg.InstallState
+		listing.UpdateAvailable = listing.Compatible && compareCatalogVersions(listing.LatestVersion, pkg.Version) > 0
+	}
+	return listing
+}
+
+// logPluginCatalogUpdates logs each installed package with a newer catalog
+// version, once per version.
+func (pm *PluginManager) logPluginCatalogUpdates() {
+	for _, listing := range pm.ListPluginCatalog() {
+		if !listing.UpdateAvailable {
+			continue
+		}
+		pm.catalogMu.Lock()
+		notified := pm.notifiedCatalogUpdates[listing.PluginID] == listing.LatestVersion
+		if pm.notifiedCatalogUpdates == nil {
+			pm.notifiedCatalogUpdates = make(map[string]string)
+		}
+		pm.notifiedCatalogUpdates[listing.PluginID] = listing.LatestVersion
+		pm.catalogMu.Unlock()
+		if notified {
+			continue
+		}
+		log.Info().
+			Str("plugin_id", listing.PluginID).
+			Str("installed_version", listing.InstalledVersion).
+			Str("available_version", listing.LatestVersion).
+			Str("catalog", listing.CatalogURL).
+			Msg("Plugin update available from catalog")
+	}
+}
+
+// InstallPluginFromCatalog downloads a plugin bundle from the catalog that
+// offers it and installs it. An empty version selects the newest version
+// with a download for this host. Installing over an existing package is an
+// in-place upgrade, with the same pending-restart handling as an upload.
+func (pm *PluginManager) InstallPluginFromCatalog(ctx context.Context, pluginID, version string) (*InstalledPluginPackage, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	catalog, entry, ok := pm.findCatalogEntry(pluginID)
+	if !ok {
+		return nil, ErrCatalogPluginNotFound
+	}
+
+	var selected *PluginCatalogVersion
+	for _, candidate := range entry.compatibleVersions() {
+		if version == "" || candidate.Version == version {
+			selected = &candidate
+			break
+		}
+	}
+	if selected == nil {
+		if version == "" {
+			return nil, fmt.Errorf("%w: no version of %s is available for %s/%s", ErrCatalogPluginNotFound, pluginID, runtime.GOOS, runtime.GOARCH)
+		}
+		return nil, fmt.Errorf("%w: %s %s is not available for %s/%s", ErrCatalogPluginNotFound, pluginID, version, runtime.GOOS, runtime.GOARCH)
+	}
+	download, _ := selected.hostDownload()
+
+	downloadURL, err := catalog.url.Parse(download.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid catalog download URL: %w", err)
+	}
+	// A remote catalog must not point the host at its own filesystem
+	if downloadURL.Scheme == "file" && catalog.url.Scheme != "file" {
+		return nil, fmt.Errorf("catalog download URL for %s %s must use http or https", pluginID, selected.Version)
+	}
+	if downloadURL.Scheme != "http" && downloadURL.Scheme != "https" && downloadURL.Scheme != "file" {
+		return nil, fmt.Errorf("catalog download URL for %s %s has an unsupported scheme", pluginID, selected.Version)
+	}
+
+	archive, err := fetchCatalogFile(ctx, downloadURL, pluginMaxUploadSize())
+	if err != nil {
+		return nil, err
+	}
+	archiveHash := fmt.Sprintf("%x", sha256.Sum256(archive))
+	if !strings.EqualFold(archiveHash, download.SHA256) {
+		return nil, fmt.Errorf("catalog download for %s %s does not match the catalog sha256 (catalog=%s, download=%s)", pluginID, selected.Version, download.SHA256, archiveHash)
+	}
+
+	// The bundle must be the plugin and version the catalog advertised
+	parts, err := readPluginBundle(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, err
+	}
+	if parts.Manifest.PluginID != pluginID || parts.Manifest.Version != selected.Version {
+		return nil, fmt.Errorf("catalog download for %s %s contains %s %s", pluginID, selected.Version, parts.Manifest.PluginID, parts.Manifest.Version)
+	}
+
+	pkg, err := pm.installPluginBundleWithFlags(ctx, bytes.NewReader(archive), int64(len(archive)), path.Base(downloadURL.Path), PluginDistributionCatalog, false)
+	if err != nil {
+		return nil, err
+	}
+
+	pm.catalogMu.Lock()
+	delete(pm.notifiedCatalogUpdates, pluginID)
+	pm.catalogMu.Unlock()
+
+	return pkg, nil
+}
+
+// findCatalogEntry returns the first configured catalog offering pluginID
+func (pm *PluginManager) findCatalogEntry(pluginID string) (*pluginCatalog, PluginCatalogEntry, bool) {
+	pm.catalogMu.RLock()
+	defer pm.catalogMu.RUnlock()
+
+	for _, catalog := range pm.catalogs {
+		if catalog.index == nil {
+			continue
+		}
+		for _, entry := range catalog.index.Plugins {
+			if entry.PluginID == pluginID {
+				return catalog, entry, true
+			}
+		}
+	}
+	return nil, PluginCatalogEntry{}, false
+}
+
+// compareCatalogVersions orders versions by their dot-separated segments,
+// numerically where both segments are numbers. A leading "v" and build
+// metadata are ignored, and a pre-release sorts before its release.
+func compareCatalogVersions(a, b string) int {
+	normalize := func(version string) (string, string) {
+		version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+		version, _, _ = strings.Cut(version, "+")
+		core, prerelease, _ := strings.Cut(version, "-")
+		return core, prerelease
+	}
+	aCore, aPre := normalize(a)
+	bCore, bPre := normalize(b)
+
+	if result := compareVersionSegments(strings.Split(aCore, "."), strings.Split(bCore, ".")); result != 0 {
+		return result
+	}
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	return compareVersionSegments(strings.Split(aPre, "."), strings.Split(bPre, "."))
+}
+
+func compareVersionSegments(a, b []string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		left, right := "0", "0"
+		if i < len(a) {
+			left = a[i]
+		}
+		if i < len(b) {
+			right = b[i]
+		}
+
+		leftNumber, leftErr := strconv.Atoi(left)
+		rightNumber, rightErr := strconv.Atoi(right)
+		switch {
+		case leftErr == nil && rightErr == nil:
+			if leftNumber != rightNumber {
+				if leftNumber < rightNumber {
+					return -1
+				}
+				return 1
+			}
+		case leftErr == nil:
+			// Numeric segments sort before alphanumeric ones
+			return -1
+		case rightErr == nil:
+			return 1
+		default:
+			if result := strings.Compare(left, right); result != 0 {
+				return result
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,362 @@
+package plugin_manager
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/plugin_signing"
+	"go.codycody31.dev/squad-aegis/internal/shared/config"
+)
+
+// testCatalog is a static-file catalog in a temporary directory
+type testCatalog struct {
+	dir        string
+	privateKey ed25519.PrivateKey
+	index      PluginCatalogIndex
+}
+
+func newTestCatalog(t *testing.T, privateKey ed25519.PrivateKey) *testCatalog {
+	t.Helper()
+
+	return &testCatalog{
+		dir:        t.TempDir(),
+		privateKey: privateKey,
+		index:      PluginCatalogIndex{Name: "Test Catalog"},
+	}
+}
+
+// addVersion writes a signed bundle for pluginID at version and lists it
+func (c *testCatalog) addVersion(t *testing.T, pluginID, version string) {
+	t.Helper()
+
+	libraryBytes := []byte("fake-so-contents-" + version)
+	manifest := testManifest(pluginID)
+	manifest.Version = version
+	manifest.Targets[0].SHA256 = fmt.Sprintf("%x", sha256.Sum256(libraryBytes))
+
+	manifestRaw, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	signedPayload, signatureRaw, publicKeyRaw := signTestPayload(t, manifestRaw, c.privateKey)
+	archive := buildSignedPluginArchive(t, manifest, primaryManifestLibraryPath(manifest), libraryBytes, signedPayload, signatureRaw, publicKeyRaw)
+
+	name := fmt.Sprintf("%s-%s.zip", pluginID, version)
+	if err := os.MkdirAll(filepath.Join(c.dir, "bundles"), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(c.dir, "bundles", name), archive, 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	catalogVersion := PluginCatalogVersion{
+		Version:           version,
+		MinHostAPIVersion: NativePluginHostAPIVersion,
+		Downloads: []PluginCatalogDownload{{
+			TargetOS:   runtime.GOOS,
+			TargetArch: runtime.GOARCH,
+			URL:        "bundles/" + name,
+			SHA256:     fmt.Sprintf("%x", sha256.Sum256(archive)),
+		}},
+	}
+	for i := range c.index.Plugins {
+		if c.index.Plugins[i].PluginID == pluginID {
+			c.index.Plugins[i].Versions = append(c.index.Plugins[i].Versions, catalogVersion)
+			return
+		}
+	}
+	c.index.Plugins = append(c.index.Plugins, PluginCatalogEntry{
+		PluginID: pluginID,
+		Name:     "Test Plugin",
+		Versions: []PluginCatalogVersion{catalogVersion},
+	})
+}
+
+// publish writes the index and its signature files
+func (c *testCatalog) publish(t *testing.T) {
+	t.Helper()
+
+	indexRaw, err := json.MarshalIndent(c.index, "", "  ")
+	if err != nil {
+		t.Fatalf("json.MarshalIndent() error = %v", err)
+	}
+	signedPayload, signatureRaw, publicKeyRaw := signTestPayload(t, indexRaw, c.privateKey)
+	for name, data := range map[string][]byte{
+		plugin_signing.CatalogIndexFile:         indexRaw,
+		plugin_signing.CatalogSignedPayloadFile: signedPayload,
+		plugin_signing.CatalogSignatureFile:     signatureRaw,
+		plugin_signing.CatalogPublicKeyFile:     publicKeyRaw,
+	} {
+		if err := os.WriteFile(filepath.Join(c.dir, name), data, 0o644); err != nil {
+			t.Fatalf("WriteFile(%s) error = %v", name, err)
+		}
+	}
+}
+
+func (c *testCatalog) fileURL() string {
+	return "file://" + filepath.ToSlash(c.dir) + "/"
+}
+
+func signTestPayload(t *testing.T, payload []byte, privateKey ed25519.PrivateKey) ([]byte, []byte, []byte) {
+	t.Helper()
+
+	signedAt := time.Now().UTC()
+	signedPayload, err := plugin_signing.BuildSignedPayload(payload, "catalog-key", signedAt, signedAt.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("BuildSignedPayload() error = %v", err)
+	}
+	signatureRaw, publicKeyRaw, err := plugin_signing.SignSignedPayload(signedPayload, privateKey)
+	if err != nil {
+		t.Fatalf("SignSignedPayload() error = %v", err)
+	}
+	return signedPayload, signatureRaw, publicKeyRaw
+}
+
+func generateTestSigningKey(t *testing.T) (string, ed25519.PrivateKey) {
+	t.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey() error = %v", err)
+	}
+	encoded, err := plugin_signing.EncodePublicKeyString(publicKey)
+	if err != nil {
+		t.Fatalf("EncodePublicKeyString() error = %v", err)
+	}
+	return encoded, privateKey
+}
+
+// newCatalogTestManager returns a manager whose package saves succeed and
+// that has no plugin instances
+func newCatalogTestManager(t *testing.T) *PluginManager {
+	t.Helper()
+
+	previousLoader := nativePluginVerifiedLoader
+	nativePluginVerifiedLoader = func(_ string, _ string, manifest PluginPackageManifest, _ PluginPackageTarget) (PluginDefinition, error) {
+		return PluginDefinition{
+			ID:             manifest.PluginID,
+			Name:           manifest.Name,
+			Version:        manifest.Version,
+			Source:         PluginSourceNative,
+			CreateInstance: func() Plugin { return &noopPlugin{} },
+		}, nil
+	}
+	t.Cleanup(func() { nativePluginVerifiedLoader = previousLoader })
+
+	return &PluginManager{
+		registry:            NewPluginRegistry(),
+		plugins:             make(map[uuid.UUID]map[uuid.UUID]*PluginInstance),
+		nativePackages:      make(map[string]*InstalledPluginPackage),
+		loadedNativePlugins: make(map[string]string),
+		db: openTestSQLDB(t, &testSQLDriver{
+			queryContext: func(query string, args []driver.NamedValue) (driver.Rows, error) {
+				if !strings.Contains(query, "SELECT EXISTS") {
+					return nil, fmt.Errorf("unexpected query: %s", query)
+				}
+				return &testSQLRows{columns: []string{"exists"}, values: [][]driver.Value{{false}}}, nil
+			},
+			execContext: func(string, []driver.NamedValue) (driver.Result, error) {
+				return driver.RowsAffected(1), nil
+			},
+		}),
+	}
+}
+
+func TestCompareCatalogVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"v1.2.0", "1.2", 0},
+		{"1.10.0", "1.9.0", 1},
+		{"1.0.0", "1.0.1", -1},
+		{"2.0.0-rc.1", "2.0.0", -1},
+		{"2.0.0-rc.2", "2.0.0-rc.10", -1},
+		{"1.0.0+build.5", "1.0.0", 0},
+	}
+	for _, tt := range tests {
+		if got := compareCatalogVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareCatalogVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestInstallPluginFromCatalogUpgradesInPlace(t *testing.T) {
+	requireLinuxNativePlugins(t)
+
+	trustedKey, privateKey := generateTestSigningKey(t)
+	catalog := newTestCatalog(t, privateKey)
+	catalog.addVersion(t, "com.example.catalog", "1.0.0")
+	catalog.publish(t)
+
+	setPluginTestConfig(t, func(cfg *config.Struct) {
+		cfg.Plugins.NativeEnabled = true
+		cfg.Plugins.RuntimeDir = t.TempDir()
+		cfg.Plugins.TrustedSigningKeys = trustedKey
+		cfg.Plugins.CatalogUrls = catalog.fileURL()
+	})
+	resetRevokedKeyIDsForTest()
+
+	pm := newCatalogTestManager(t)
+	statuses := pm.RefreshPluginCatalogs(context.Background())
+	if len(statuses) != 1 || statuses[0].LastError != "" || statuses[0].PluginCount != 1 {
+		t.Fatalf("RefreshPluginCatalogs() = %+v, want one healthy catalog", statuses)
+	}
+
+	pkg, err := pm.InstallPluginFromCatalog(context.Background(), "com.example.catalog", "")
+	if err != nil {
+		t.Fatalf("InstallPluginFromCatalog() error = %v", err)
+	}
+	if pkg.Version != "1.0.0" || pkg.Distribution != PluginDistributionCatalog || !pkg.SignatureVerified {
+		t.Fatalf("InstallPluginFromCatalog() = %s %s verified=%v, want 1.0.0 catalog verified", pkg.Version, pkg.Distribution, pkg.SignatureVerified)
+	}
+
+	// Publishing a new version surfaces an update on the next refresh
+	catalog.addVersion(t, "com.example.catalog", "1.1.0")
+	catalog.publish(t)
+	pm.RefreshPluginCatalogs(context.Background())
+
+	listings := pm.ListPluginCatalog()
+	if len(listings) != 1 {
+		t.Fatalf("ListPluginCatalog() returned %d plugins, want 1", len(listings))
+	}
+	if !listings[0].UpdateAvailable || listings[0].LatestVersion != "1.1.0" || listings[0].InstalledVersion != "1.0.0" {
+		t.Fatalf("ListPluginCatalog()[0] = %+v, want update from 1.0.0 to 1.1.0", listings[0])
+	}
+
+	pkg, err = pm.InstallPluginFromCatalog(context.Background(), "com.example.catalog", "")
+	if err != nil {
+		t.Fatalf("InstallPluginFromCatalog(upgrade) error = %v", err)
+	}
+	if pkg.Version != "1.1.0" {
+		t.Fatalf("upgraded version = %s, want 1.1.0", pkg.Version)
+	}
+	if listing := pm.ListPluginCatalog()[0]; listing.UpdateAvailable {
+		t.Fatalf("ListPluginCatalog()[0].UpdateAvailable = true after upgrade")
+	}
+}
+
+func TestRefreshPluginCatalogsRejectsUntrustedIndex(t *testing.T) {
+	trustedKey, _ := generateTestSigningKey(t)
+	_, untrustedKey := generateTestSigningKey(t)
+	catalog := newTestCatalog(t, untrustedKey)
+	catalog.addVersion(t, "com.example.catalog", "1.0.0")
+	catalog.publish(t)
+
+	server := httptest.NewServer(http.FileServer(http.Dir(catalog.dir)))
+	t.Cleanup(server.Close)
+
+	setPluginTestConfig(t, func(cfg *config.Struct) {
+		cfg.Plugins.TrustedSigningKeys = trustedKey
+		cfg.Plugins.CatalogUrls = server.URL + "/index.json"
+	})
+	resetRevokedKeyIDsForTest()
+
+	pm := newCatalogTestManager(t)
+	statuses := pm.RefreshPluginCatalogs(context.Background())
+	if len(statuses) != 1 || !strings.Contains(statuses[0].LastError, "signature rejected") {
+		t.Fatalf("RefreshPluginCatalogs() = %+v, want signature rejection", statuses)
+	}
+	if listings := pm.ListPluginCatalog(); len(listings) != 0 {
+		t.Fatalf("ListPluginCatalog() = %+v, want nothing from an untrusted catalog", listings)
+	}
+	if _, err := pm.InstallPluginFromCatalog(context.Background(), "com.example.catalog", ""); !errors.Is(err, ErrCatalogPluginNotFound) {
+		t.Fatalf("InstallPluginFromCatalog() error = %v, want ErrCatalogPluginNotFound", err)
+	}
+}
+
+func TestInstallPluginFromCatalogRejectsChecksumMismatch(t *testing.T) {
+	requireLinuxNativePlugins(t)
+
+	trustedKey, privateKey := generateTestSigningKey(t)
+	catalog := newTestCatalog(t, privateKey)
+	catalog.addVersion(t, "com.example.catalog", "1.0.0")
+	catalog.index.Plugins[0].Versions[0].Downloads[0].SHA256 = strings.Repeat("0", sha256.Size*2)
+	catalog.publish(t)
+
+	setPluginTestConfig(t, func(cfg *config.Struct) {
+		cfg.Plugins.NativeEnabled = true
+		cfg.Plugins.RuntimeDir = t.TempDir()
+		cfg.Plugins.TrustedSigningKeys = trustedKey
+		cfg.Plugins.CatalogUrls = catalog.fileURL()
+	})
+	resetRevokedKeyIDsForTest()
+
+	pm := newCatalogTestManager(t)
+	pm.RefreshPluginCatalogs(context.Background())
+
+	_, err := pm.InstallPluginFromCatalog(context.Background(), "com.example.catalog", "1.0.0")
+	if err == nil || !strings.Contains(err.Error(), "does not match the catalog sha256") {
+		t.Fatalf("InstallPluginFromCatalog() error = %v, want checksum mismatch", err)
+	}
+	if pkg := pm.getNativePackage("com.example.catalog"); pkg != nil {
+		t.Fatalf("package installed despite checksum mismatch: %+v", pkg)
+	}
+}
+
+func TestInstallPluginFromCatalogRequiresTrustedBundleSignature(t *testing.T) {
+	requireLinuxNativePlugins(t)
+
+	// The index is signed by a trusted key but the bundle is not, and
+	// unsafe sideloads do not apply to catalog installs.
+	trustedKey, privateKey := generateTestSigningKey(t)
+	_, bundleKey := generateTestSigningKey(t)
+	catalog := newTestCatalog(t, bundleKey)
+	catalog.addVersion(t, "com.example.catalog", "1.0.0")
+	catalog.privateKey = privateKey
+	catalog.publish(t)
+
+	setPluginTestConfig(t, func(cfg *config.Struct) {
+		cfg.Plugins.NativeEnabled = true
+		cfg.Plugins.RuntimeDir = t.TempDir()
+		cfg.Plugins.AllowUnsafeSideload = true
+		cfg.Plugins.TrustedSigningKeys = trustedKey
+		cfg.Plugins.CatalogUrls = catalog.fileURL()
+	})
+	resetRevokedKeyIDsForTest()
+
+	pm := newCatalogTestManager(t)
+	pm.RefreshPluginCatalogs(context.Background())
+
+	_, err := pm.InstallPluginFromCatalog(context.Background(), "com.example.catalog", "1.0.0")
+	if err == nil || !strings.Contains(err.Error(), "catalog plugin signature rejected") {
+		t.Fatalf("InstallPluginFromCatalog() error = %v, want signature rejection", err)
+	}
+}
+
+func TestFetchCatalogFileRefusesRedirectToFile(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "secret.txt")
+	if err := os.WriteFile(secret, []byte("local secret"), 0o600); err != nil {
+		t.Fatalf("failed to write local file: %v", err)
+	}
+	server := httptest.NewServer(http.RedirectHandler("file://"+filepath.ToSlash(secret), http.StatusFound))
+	defer server.Close()
+
+	indexURL, err := url.Parse(server.URL + "/index.json")
+	if err != nil {
+		t.Fatalf("failed to parse server url: %v", err)
+	}
+	data, err := fetchCatalogFile(context.Background(), indexURL, maxCatalogFileBytes)
+	if err == nil || !strings.Contains(err.Error(), "refusing to follow catalog redirect") {
+		t.Fatalf("fetchCatalogFile() = %q, %v, want the redirect refused", data, err)
+	}
+}
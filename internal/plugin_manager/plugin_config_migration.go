@@ -0,0 +1,160 @@
+package plugin_manager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Outcomes recorded on a plugin instance after its stored config was moved
+// to a different config schema version.
+const (
+	PluginConfigMigrationMigrated = "migrated"
+	PluginConfigMigrationRestored = "restored"
+	PluginConfigMigrationFailed   = "failed"
+)
+
+// PluginConfigMigration records the last config schema migration attempted
+// for a plugin instance.
+type PluginConfigMigration struct {
+	Status          string    `json:"status"`
+	FromVersion     int       `json:"from_version"`
+	ToVersion       int       `json:"to_version"`
+	Error           string    `json:"error,omitempty"`
+	BackupAvailable bool      `json:"backup_available"`
+	At              time.Time `json:"at"`
+}
+
+func (m *PluginConfigMigration) clone() *PluginConfigMigration {
+	if m == nil {
+		return nil
+	}
+	cloned := *m
+	return &cloned
+}
+
+// PluginConfigBackup is the config an instance held before its last
+// successful migration. It may contain sensitive values and is never
+// returned by the API.
+type PluginConfigBackup struct {
+	SchemaVersion int                    `json:"schema_version"`
+	Config        map[string]interface{} `json:"config"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+// migratePluginInstanceConfig brings a loaded instance's config up to the
+// plugin's current config schema version. The previous config is kept as a
+// backup and the outcome is recorded on the instance. When the plugin was
+// downgraded to the version the backup was taken from, the backup is
+// restored instead. On failure the stored config is left untouched and an
+// error is returned so the caller does not start the instance.
+func (pm *PluginManager) migratePluginInstanceConfig(instance *PluginInstance, definition PluginDefinition) error {
+	from := instance.ConfigSchemaVersion
+	to := definition.ConfigSchema.Version
+	if from == to {
+		return nil
+	}
+
+	now := time.Now()
+	outcome := &PluginConfigMigration{FromVersion: from, ToVersion: to, At: now}
+	backup := &PluginConfigBackup{SchemaVersion: from, CreatedAt: now}
+
+	config, err := runPluginConfigMigration(definition, instance.ConfigBackup, from, instance.Config)
+	if err == nil {
+		backup.Config, err = clonePluginConfig(instance.Config)
+	}
+	if err != nil {
+		outcome.Status = PluginConfigMigrationFailed
+		outcome.Error = err.Error()
+		outcome.BackupAvailable = instance.ConfigBackup != nil
+		instance.ConfigMigration = outcome
+		if dbErr := pm.updatePluginInstanceInDatabase(instance); dbErr != nil {
+			log.Error().
+				Str("instanceID", instance.ID.String()).
+				Err(dbErr).
+				Msg("Failed to record plugin config migration failure")
+		}
+		return fmt.Errorf("config migration from schema version %d to %d failed: %w", from, to, err)
+	}
+
+	outcome.Status = PluginConfigMigrationMigrated
+	if from > to {
+		outcome.Status = PluginConfigMigrationRestored
+	}
+	outcome.BackupAvailable = true
+
+	instance.Config = config
+	instance.ConfigSchemaVersion = to
+	instance.ConfigBackup = backup
+	instance.ConfigMigration = outcome
+	instance.UpdatedAt = now
+	if err := pm.updatePluginInstanceInDatabase(instance); err != nil {
+		return fmt.Errorf("failed to save migrated config: %w", err)
+	}
+
+	log.Info().
+		Str("instanceID", instance.ID.String()).
+		Str("pluginID", instance.PluginID).
+		Int("fromVersion", from).
+		Int("toVersion", to).
+		Str("outcome", outcome.Status).
+		Msg("Migrated plugin instance config")
+	return nil
+}
+
+// runPluginConfigMigration returns config rewritten for the definition's
+// current schema version without modifying the input.
+func runPluginConfigMigration(definition PluginDefinition, backup *PluginConfigBackup, from int, config map[string]interface{}) (map[string]interface{}, error) {
+	to := definition.ConfigSchema.Version
+
+	var migrated map[string]interface{}
+	var err error
+	switch {
+	case from > to:
+		// Plugins only migrate forward. A downgrade can only go back to
+		// the config saved before the upgrade.
+		if backup == nil || backup.SchemaVersion != to {
+			return nil, fmt.Errorf("stored config uses schema version %d, which is newer than the plugin's version %d, and no backup for version %d exists", from, to, to)
+		}
+		migrated, err = clonePluginConfig(backup.Config)
+	case definition.MigrateConfig != nil:
+		migrated, err = clonePluginConfig(config)
+		if err == nil {
+			migrated, err = definition.MigrateConfig(from, migrated)
+		}
+	default:
+		migrated, err = clonePluginConfig(config)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if migrated == nil {
+		return nil, errors.New("migration returned no config")
+	}
+
+	migrated = definition.ConfigSchema.FillDefaults(migrated)
+	if err := definition.ConfigSchema.Validate(migrated); err != nil {
+		return nil, fmt.Errorf("migrated config is invalid: %w", err)
+	}
+	return migrated, nil
+}
+
+// clonePluginConfig deep-copies a config through JSON so a migration cannot
+// modify the caller's map.
+func clonePluginConfig(config map[string]interface{}) (map[string]interface{}, error) {
+	if config == nil {
+		return map[string]interface{}{}, nil
+	}
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy config: %w", err)
+	}
+	var cloned map[string]interface{}
+	if err := json.Unmarshal(encoded, &cloned); err != nil {
+		return nil, fmt.Errorf("failed to copy config: %w", err)
+	}
+	return cloned, nil
+}
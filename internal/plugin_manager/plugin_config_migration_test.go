@@ -0,0 +1,180 @@
+package plugin_manager
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/shared/plug_config_schema"
+)
+
+func versionedTestDefinition(migrate ConfigMigrationFunc) PluginDefinition {
+	return PluginDefinition{
+		ID:     "com.example.versioned",
+		Source: PluginSourceBundled,
+		ConfigSchema: plug_config_schema.ConfigSchema{
+			Version: 2,
+			Fields: []plug_config_schema.ConfigField{
+				plug_config_schema.NewStringField("channel_id", "Channel", true, ""),
+				plug_config_schema.NewIntField("cooldown", "Cooldown", false, 30),
+			},
+		},
+		MigrateConfig:  migrate,
+		CreateInstance: func() Plugin { return &noopPlugin{} },
+	}
+}
+
+// renameChannelField is the v1 -> v2 migration used by the tests.
+func renameChannelField(fromVersion int, config map[string]interface{}) (map[string]interface{}, error) {
+	if fromVersion < 2 {
+		config["channel_id"] = config["channel"]
+		delete(config, "channel")
+	}
+	return config, nil
+}
+
+func TestRunPluginConfigMigration(t *testing.T) {
+	t.Parallel()
+
+	stored := map[string]interface{}{"channel": "123"}
+	migrated, err := runPluginConfigMigration(versionedTestDefinition(renameChannelField), nil, 1, stored)
+	if err != nil {
+		t.Fatalf("runPluginConfigMigration() error = %v", err)
+	}
+	want := map[string]interface{}{"channel_id": "123", "cooldown": 30}
+	if !reflect.DeepEqual(migrated, want) {
+		t.Fatalf("migrated config = %v, want %v", migrated, want)
+	}
+	if !reflect.DeepEqual(stored, map[string]interface{}{"channel": "123"}) {
+		t.Fatalf("stored config was modified: %v", stored)
+	}
+
+	// Without a migration function a version bump only fills defaults
+	// before the stored config is re-validated.
+	if _, err := runPluginConfigMigration(versionedTestDefinition(nil), nil, 1, map[string]interface{}{"channel_id": "123", "cooldown": "soon"}); err == nil {
+		t.Fatal("runPluginConfigMigration() without migrator error = nil, want validation error")
+	}
+
+	broken := func(int, map[string]interface{}) (map[string]interface{}, error) {
+		return nil, fmt.Errorf("cannot migrate")
+	}
+	if _, err := runPluginConfigMigration(versionedTestDefinition(broken), nil, 1, stored); err == nil || !strings.Contains(err.Error(), "cannot migrate") {
+		t.Fatalf("runPluginConfigMigration() error = %v, want migrator error", err)
+	}
+
+	// A downgrade restores the backup taken before the upgrade.
+	backup := &PluginConfigBackup{SchemaVersion: 2, Config: map[string]interface{}{"channel_id": "456", "cooldown": float64(5)}}
+	restored, err := runPluginConfigMigration(versionedTestDefinition(nil), backup, 3, map[string]interface{}{"channels": []interface{}{"456"}})
+	if err != nil {
+		t.Fatalf("runPluginConfigMigration() downgrade error = %v", err)
+	}
+	if !reflect.DeepEqual(restored, backup.Config) {
+		t.Fatalf("restored config = %v, want %v", restored, backup.Config)
+	}
+	if _, err := runPluginConfigMigration(versionedTestDefinition(nil), nil, 3, stored); err == nil {
+		t.Fatal("runPluginConfigMigration() downgrade without backup error = nil, want error")
+	}
+}
+
+func TestLoadPluginsFromDatabaseMigratesInstanceConfigs(t *testing.T) {
+	serverID := uuid.New()
+	migratedID := uuid.New()
+	failedID := uuid.New()
+	createdAt := time.Now().UTC().Add(-time.Minute)
+
+	updates := make(map[string][]driver.NamedValue)
+	db := openTestSQLDB(t, &testSQLDriver{
+		queryContext: func(query string, _ []driver.NamedValue) (driver.Rows, error) {
+			if !strings.Contains(query, "FROM plugin_instances") {
+				return nil, fmt.Errorf("unexpected query: %s", query)
+			}
+			row := func(id uuid.UUID, config string, version int64, offset time.Duration) []driver.Value {
+				return []driver.Value{
+					id.String(), serverID.String(), "com.example.versioned", "", []byte(config),
+					version, nil, nil,
+					false, "info", []byte(`{}`), []byte(`{}`), createdAt.Add(offset), createdAt.Add(offset),
+				}
+			}
+			return &testSQLRows{
+				columns: []string{
+					"id", "server_id", "plugin_id", "notes", "config",
+					"config_schema_version", "config_backup", "config_migration",
+					"enabled", "log_level", "rcon_policy", "capability_grants", "created_at", "updated_at",
+				},
+				values: [][]driver.Value{
+					row(migratedID, `{"channel":"123"}`, 1, 0),
+					row(failedID, `{"channels":["123"]}`, 3, time.Second),
+				},
+			}, nil
+		},
+		execContext: func(query string, args []driver.NamedValue) (driver.Result, error) {
+			if !strings.Contains(query, "UPDATE plugin_instances") {
+				return nil, fmt.Errorf("unexpected exec: %s", query)
+			}
+			updates[fmt.Sprint(args[0].Value)] = args
+			return driver.RowsAffected(1), nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	pm := &PluginManager{
+		db:       db,
+		registry: NewPluginRegistry(),
+		plugins:  make(map[uuid.UUID]map[uuid.UUID]*PluginInstance),
+		ctx:      ctx,
+	}
+	if err := pm.registry.RegisterPlugin(versionedTestDefinition(renameChannelField)); err != nil {
+		t.Fatalf("RegisterPlugin() error = %v", err)
+	}
+
+	if err := pm.loadPluginsFromDatabase(); err != nil {
+		t.Fatalf("loadPluginsFromDatabase() error = %v", err)
+	}
+
+	migrated, err := pm.GetPluginInstance(serverID, migratedID)
+	if err != nil {
+		t.Fatalf("GetPluginInstance(migrated) error = %v", err)
+	}
+	if migrated.ConfigSchemaVersion != 2 || migrated.Config["channel_id"] != "123" {
+		t.Fatalf("migrated instance = version %d config %v, want version 2 with channel_id", migrated.ConfigSchemaVersion, migrated.Config)
+	}
+	if migrated.ConfigMigration == nil || migrated.ConfigMigration.Status != PluginConfigMigrationMigrated || !migrated.ConfigMigration.BackupAvailable {
+		t.Fatalf("migrated.ConfigMigration = %+v, want migrated with backup", migrated.ConfigMigration)
+	}
+	args := updates[migratedID.String()]
+	if args == nil {
+		t.Fatal("migrated instance was not saved")
+	}
+	var backup PluginConfigBackup
+	if err := json.Unmarshal([]byte(fmt.Sprint(args[4].Value)), &backup); err != nil {
+		t.Fatalf("saved config backup is not JSON: %v", err)
+	}
+	if backup.SchemaVersion != 1 || backup.Config["channel"] != "123" {
+		t.Fatalf("saved config backup = %+v, want the version 1 config", backup)
+	}
+
+	failed, err := pm.GetPluginInstance(serverID, failedID)
+	if err != nil {
+		t.Fatalf("GetPluginInstance(failed) error = %v", err)
+	}
+	if failed.ConfigSchemaVersion != 3 || failed.Config["channels"] == nil {
+		t.Fatalf("failed instance = version %d config %v, want the stored config untouched", failed.ConfigSchemaVersion, failed.Config)
+	}
+	if failed.ConfigMigration == nil || failed.ConfigMigration.Status != PluginConfigMigrationFailed {
+		t.Fatalf("failed.ConfigMigration = %+v, want failed", failed.ConfigMigration)
+	}
+	if failed.Status != PluginStatusDisabled || failed.LastError == "" {
+		t.Fatalf("failed instance status = %q error = %q, want disabled with an error", failed.Status, failed.LastError)
+	}
+	if err := pm.EnablePluginInstance(serverID, failedID, nil); err == nil {
+		t.Fatal("EnablePluginInstance() error = nil, want failed migration error")
+	}
+}
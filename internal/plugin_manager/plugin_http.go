@@ -0,0 +1,389 @@
+package plugin_manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/shared/plug_config_schema"
+)
+
+// Access levels for plugin HTTP routes. View routes are served to anyone
+// who can see the server's plugins; manage routes also need plugin manage
+// permission.
+const (
+	PluginHTTPAccessView   = "view"
+	PluginHTTPAccessManage = "manage"
+)
+
+// Panel types the web UI knows how to render.
+const (
+	PluginPanelTypeTable = "table"
+	PluginPanelTypeChart = "chart"
+	PluginPanelTypeStats = "stats"
+	PluginPanelTypeForm  = "form"
+)
+
+// Limits applied to plugin HTTP traffic in both directions.
+const (
+	maxPluginHTTPRoutes       = 32
+	maxPluginPanels           = 16
+	MaxPluginHTTPBodySize     = 1 << 20
+	pluginHTTPRequestTimeout  = 30 * time.Second
+	minPluginPanelRefreshSecs = 5
+)
+
+var (
+	// ErrPluginHTTPRouteNotFound is returned when no declared route matches
+	// a request.
+	ErrPluginHTTPRouteNotFound = errors.New("plugin HTTP route not found")
+	// ErrPluginHTTPNotRunning is returned when the instance is not running.
+	ErrPluginHTTPNotRunning = errors.New("plugin instance is not running")
+)
+
+var pluginHTTPSegmentPattern = regexp.MustCompile(`^(:[a-z][a-z0-9_]{0,31}|[A-Za-z0-9._~-]{1,64})$`)
+
+// allowedPluginHTTPContentTypes lists what plugins may respond with. The
+// host never serves plugin-authored HTML or scripts.
+var allowedPluginHTTPContentTypes = map[string]bool{
+	"application/json": true,
+	"text/plain":       true,
+	"text/csv":         true,
+}
+
+// PluginHTTPRoute declares an HTTP endpoint a plugin serves under
+// /servers/:serverId/plugins/:pluginId/http. Path segments starting with
+// ":" are parameters.
+type PluginHTTPRoute struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Access      string `json:"access"`
+	Description string `json:"description,omitempty"`
+}
+
+// PluginPanel describes a dashboard panel the web UI renders from a
+// plugin route. Table and chart panels read {"rows": [...]} from a GET
+// route, stats panels read {"stats": [{"label", "value"}]}, and form panels
+// POST the submitted values to their route.
+type PluginPanel struct {
+	ID             string                           `json:"id"`
+	Title          string                           `json:"title"`
+	Description    string                           `json:"description,omitempty"`
+	Type           string                           `json:"type"`
+	Route          string                           `json:"route"`
+	Columns        []PluginPanelColumn              `json:"columns,omitempty"`
+	Chart          *PluginPanelChart                `json:"chart,omitempty"`
+	Form           *plug_config_schema.ConfigSchema `json:"form,omitempty"`
+	SubmitLabel    string                           `json:"submit_label,omitempty"`
+	RefreshSeconds int                              `json:"refresh_seconds,omitempty"`
+
+	// Access is filled in by the host from the panel's route.
+	Access string `json:"access,omitempty"`
+}
+
+// PluginPanelColumn is one column of a table panel.
+type PluginPanelColumn struct {
+	Key    string `json:"key"`
+	Label  string `json:"label"`
+	Format string `json:"format,omitempty"` // text, number, datetime, duration
+}
+
+// PluginPanelChart configures a chart panel.
+type PluginPanelChart struct {
+	Kind   string                   `json:"kind"` // line, bar
+	XKey   string                   `json:"x_key"`
+	Series []PluginPanelChartSeries `json:"series"`
+}
+
+// PluginPanelChartSeries is one plotted value of a chart panel.
+type PluginPanelChartSeries struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+}
+
+// PluginHTTPRequest is an HTTP request forwarded to a plugin.
+type PluginHTTPRequest struct {
+	Method string              `json:"method"`
+	Path   string              `json:"path"`
+	Route  string              `json:"route"`
+	Params map[string]string   `json:"params,omitempty"`
+	Query  map[string][]string `json:"query,omitempty"`
+	Body   []byte              `json:"body,omitempty"`
+	UserID string              `json:"user_id,omitempty"`
+}
+
+// PluginHTTPResponse is a plugin's reply to a PluginHTTPRequest.
+type PluginHTTPResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// PluginHTTPHandler is implemented by plugins that declare HTTPRoutes. The
+// host only forwards requests that match a declared route.
+type PluginHTTPHandler interface {
+	HandleHTTP(ctx context.Context, req *PluginHTTPRequest) (*PluginHTTPResponse, error)
+}
+
+// NewPluginHTTPJSONResponse encodes v as a JSON response.
+func NewPluginHTTPJSONResponse(status int, v interface{}) (*PluginHTTPResponse, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode response: %w", err)
+	}
+	return &PluginHTTPResponse{Status: status, ContentType: "application/json", Body: body}, nil
+}
+
+// validatePluginHTTPSurface checks the routes and panels a plugin declares.
+func validatePluginHTTPSurface(definition PluginDefinition) error {
+	if len(definition.HTTPRoutes) > maxPluginHTTPRoutes {
+		return fmt.Errorf("plugin %s declares more than %d HTTP routes", definition.ID, maxPluginHTTPRoutes)
+	}
+	seen := make(map[string]bool, len(definition.HTTPRoutes))
+	for _, route := range definition.HTTPRoutes {
+		if err := route.validate(); err != nil {
+			return fmt.Errorf("plugin %s: %w", definition.ID, err)
+		}
+		key := route.Method + " " + route.Path
+		if seen[key] {
+			return fmt.Errorf("plugin %s declares HTTP route %s twice", definition.ID, key)
+		}
+		seen[key] = true
+	}
+
+	if len(definition.Panels) > maxPluginPanels {
+		return fmt.Errorf("plugin %s declares more than %d panels", definition.ID, maxPluginPanels)
+	}
+	panelIDs := make(map[string]bool, len(definition.Panels))
+	for _, panel := range definition.Panels {
+		if panel.ID == "" || panelIDs[panel.ID] {
+			return fmt.Errorf("plugin %s: panel IDs must be unique and non-empty", definition.ID)
+		}
+		panelIDs[panel.ID] = true
+		if err := panel.validate(definition.HTTPRoutes); err != nil {
+			return fmt.Errorf("plugin %s panel %s: %w", definition.ID, panel.ID, err)
+		}
+	}
+	return nil
+}
+
+func (r PluginHTTPRoute) validate() error {
+	switch r.Method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return fmt.Errorf("HTTP route %s %s: unsupported method", r.Method, r.Path)
+	}
+	switch r.Access {
+	case PluginHTTPAccessView, PluginHTTPAccessManage:
+	default:
+		return fmt.Errorf("HTTP route %s %s: access must be %q or %q", r.Method, r.Path, PluginHTTPAccessView, PluginHTTPAccessManage)
+	}
+	if r.Path == "/" {
+		return nil
+	}
+	if !strings.HasPrefix(r.Path, "/") || strings.HasSuffix(r.Path, "/") {
+		return fmt.Errorf("HTTP route %s %s: path must start and not end with /", r.Method, r.Path)
+	}
+	for _, segment := range strings.Split(r.Path[1:], "/") {
+		if segment == "." || segment == ".." || !pluginHTTPSegmentPattern.MatchString(segment) {
+			return fmt.Errorf("HTTP route %s %s: invalid path segment %q", r.Method, r.Path, segment)
+		}
+	}
+	return nil
+}
+
+func (p PluginPanel) validate(routes []PluginHTTPRoute) error {
+	if strings.TrimSpace(p.Title) == "" {
+		return fmt.Errorf("title is required")
+	}
+	method := http.MethodGet
+	switch p.Type {
+	case PluginPanelTypeTable:
+		if len(p.Columns) == 0 {
+			return fmt.Errorf("table panels need at least one column")
+		}
+	case PluginPanelTypeChart:
+		if p.Chart == nil || p.Chart.XKey == "" || len(p.Chart.Series) == 0 {
+			return fmt.Errorf("chart panels need an x key and at least one series")
+		}
+		if p.Chart.Kind != "line" && p.Chart.Kind != "bar" {
+			return fmt.Errorf("chart kind must be line or bar")
+		}
+	case PluginPanelTypeStats:
+	case PluginPanelTypeForm:
+		if p.Form == nil || len(p.Form.Fields) == 0 {
+			return fmt.Errorf("form panels need at least one field")
+		}
+		method = http.MethodPost
+	default:
+		return fmt.Errorf("unsupported panel type %q", p.Type)
+	}
+	if p.RefreshSeconds != 0 && p.RefreshSeconds < minPluginPanelRefreshSecs {
+		return fmt.Errorf("refresh interval must be at least %d seconds", minPluginPanelRefreshSecs)
+	}
+	for _, route := range routes {
+		if route.Method == method && route.Path == p.Route {
+			return nil
+		}
+	}
+	return fmt.Errorf("route %s %s is not declared", method, p.Route)
+}
+
+// matchPluginHTTPRoute finds the declared route for a request and extracts
+// its path parameters.
+func matchPluginHTTPRoute(routes []PluginHTTPRoute, method, path string) (PluginHTTPRoute, map[string]string, bool) {
+	if path == "" {
+		path = "/"
+	}
+	requested := strings.Split(strings.Trim(path, "/"), "/")
+	for _, route := range routes {
+		if route.Method != method {
+			continue
+		}
+		declared := strings.Split(strings.Trim(route.Path, "/"), "/")
+		if len(declared) != len(requested) {
+			continue
+		}
+		params := map[string]string{}
+		matched := true
+		for i, segment := range declared {
+			if strings.HasPrefix(segment, ":") {
+				value, err := url.PathUnescape(requested[i])
+				if err != nil || value == "" {
+					matched = false
+					break
+				}
+				params[segment[1:]] = value
+				continue
+			}
+			if segment != requested[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return route, params, true
+		}
+	}
+	return PluginHTTPRoute{}, nil, false
+}
+
+// pluginHTTPDefinition returns the registered definition of a running
+// instance.
+func (pm *PluginManager) pluginHTTPDefinition(serverID, instanceID uuid.UUID) (*PluginInstance, *PluginDefinition, error) {
+	pm.mu.RLock()
+	instance, err := pm.lookupPluginInstanceLocked(serverID, instanceID)
+	pm.mu.RUnlock()
+	if err != nil {
+		return nil, nil, err
+	}
+	definition, err := pm.registry.GetPlugin(instance.PluginID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return instance, definition, nil
+}
+
+// GetPluginPanels returns the panels an instance declares, each tagged
+// with the access level of its route.
+func (pm *PluginManager) GetPluginPanels(serverID, instanceID uuid.UUID) ([]PluginPanel, error) {
+	_, definition, err := pm.pluginHTTPDefinition(serverID, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	panels := make([]PluginPanel, 0, len(definition.Panels))
+	for _, panel := range definition.Panels {
+		method := http.MethodGet
+		if panel.Type == PluginPanelTypeForm {
+			method = http.MethodPost
+		}
+		route, _, ok := matchPluginHTTPRoute(definition.HTTPRoutes, method, panel.Route)
+		if !ok {
+			continue
+		}
+		panel.Access = route.Access
+		panels = append(panels, panel)
+	}
+	return panels, nil
+}
+
+// ServePluginHTTP forwards a request to the plugin route it matches.
+// authorize is called with the matched route before the plugin sees the
+// request, so callers can enforce the route's access level.
+func (pm *PluginManager) ServePluginHTTP(ctx context.Context, serverID, instanceID uuid.UUID, req *PluginHTTPRequest, authorize func(PluginHTTPRoute) error) (*PluginHTTPResponse, error) {
+	instance, definition, err := pm.pluginHTTPDefinition(serverID, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	route, params, ok := matchPluginHTTPRoute(definition.HTTPRoutes, req.Method, req.Path)
+	if !ok {
+		return nil, ErrPluginHTTPRouteNotFound
+	}
+	if authorize != nil {
+		if err := authorize(route); err != nil {
+			return nil, err
+		}
+	}
+	if len(req.Body) > MaxPluginHTTPBodySize {
+		return nil, fmt.Errorf("request body exceeds %d bytes", MaxPluginHTTPBodySize)
+	}
+
+	instance.mu.Lock()
+	status := instance.Status
+	plugin := instance.Plugin
+	instance.mu.Unlock()
+	if status != PluginStatusRunning || plugin == nil {
+		return nil, ErrPluginHTTPNotRunning
+	}
+	handler, ok := plugin.(PluginHTTPHandler)
+	if !ok {
+		return nil, ErrPluginHTTPRouteNotFound
+	}
+
+	forwarded := *req
+	forwarded.Route = route.Path
+	forwarded.Params = params
+
+	ctx, cancel := context.WithTimeout(ctx, pluginHTTPRequestTimeout)
+	defer cancel()
+	resp, err := handler.HandleHTTP(ctx, &forwarded)
+	if err != nil {
+		return nil, err
+	}
+	return sanitizePluginHTTPResponse(resp)
+}
+
+// sanitizePluginHTTPResponse enforces the response limits.
+func sanitizePluginHTTPResponse(resp *PluginHTTPResponse) (*PluginHTTPResponse, error) {
+	if resp == nil {
+		return &PluginHTTPResponse{Status: http.StatusNoContent}, nil
+	}
+	if resp.Status == 0 {
+		resp.Status = http.StatusOK
+	}
+	if resp.Status < 200 || resp.Status > 599 || (resp.Status >= 300 && resp.Status < 400) {
+		return nil, fmt.Errorf("plugin returned unsupported status %d", resp.Status)
+	}
+	if len(resp.Body) > MaxPluginHTTPBodySize {
+		return nil, fmt.Errorf("plugin response exceeds %d bytes", MaxPluginHTTPBodySize)
+	}
+	if len(resp.Body) == 0 {
+		return resp, nil
+	}
+	if resp.ContentType == "" {
+		resp.ContentType = "application/json"
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.ContentType)
+	if err != nil || !allowedPluginHTTPContentTypes[mediaType] {
+		return nil, fmt.Errorf("plugin returned unsupported content type %q", resp.ContentType)
+	}
+	return resp, nil
+}
@@ -0,0 +1,141 @@
+package plugin_manager
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/shared/plug_config_schema"
+)
+
+// httpPlugin answers every request with the route and params it was given.
+type httpPlugin struct {
+	noopPlugin
+	contentType string
+}
+
+func (p *httpPlugin) HandleHTTP(_ context.Context, req *PluginHTTPRequest) (*PluginHTTPResponse, error) {
+	resp, err := NewPluginHTTPJSONResponse(http.StatusOK, map[string]interface{}{"route": req.Route, "params": req.Params})
+	if err != nil {
+		return nil, err
+	}
+	if p.contentType != "" {
+		resp.ContentType = p.contentType
+	}
+	return resp, nil
+}
+
+func testHTTPDefinition() PluginDefinition {
+	return PluginDefinition{
+		ID:             "com.example.dashboard",
+		CreateInstance: func() Plugin { return &httpPlugin{} },
+		HTTPRoutes: []PluginHTTPRoute{
+			{Method: http.MethodGet, Path: "/players/:player_id", Access: PluginHTTPAccessView},
+			{Method: http.MethodGet, Path: "/leaderboard", Access: PluginHTTPAccessView},
+			{Method: http.MethodPost, Path: "/reset", Access: PluginHTTPAccessManage},
+		},
+		Panels: []PluginPanel{
+			{ID: "top", Title: "Leaderboard", Type: PluginPanelTypeTable, Route: "/leaderboard", Columns: []PluginPanelColumn{{Key: "name", Label: "Name"}}},
+			{ID: "reset", Title: "Reset", Type: PluginPanelTypeForm, Route: "/reset", Form: &plug_config_schema.ConfigSchema{
+				Fields: []plug_config_schema.ConfigField{{Name: "confirm", Type: plug_config_schema.FieldTypeBool}},
+			}},
+		},
+	}
+}
+
+func TestValidatePluginHTTPSurface(t *testing.T) {
+	t.Parallel()
+
+	if err := validatePluginHTTPSurface(testHTTPDefinition()); err != nil {
+		t.Fatalf("validatePluginHTTPSurface() error = %v", err)
+	}
+
+	for name, mutate := range map[string]func(*PluginDefinition){
+		"unsupported method":  func(d *PluginDefinition) { d.HTTPRoutes[0].Method = "TRACE" },
+		"missing access":      func(d *PluginDefinition) { d.HTTPRoutes[0].Access = "" },
+		"relative path":       func(d *PluginDefinition) { d.HTTPRoutes[0].Path = "players" },
+		"path traversal":      func(d *PluginDefinition) { d.HTTPRoutes[0].Path = "/players/../admin" },
+		"duplicate route":     func(d *PluginDefinition) { d.HTTPRoutes[1] = d.HTTPRoutes[0] },
+		"undeclared route":    func(d *PluginDefinition) { d.Panels[0].Route = "/missing" },
+		"form on a GET route": func(d *PluginDefinition) { d.Panels[1].Route = "/leaderboard" },
+		"unknown panel type":  func(d *PluginDefinition) { d.Panels[0].Type = "html" },
+		"duplicate panel":     func(d *PluginDefinition) { d.Panels[1].ID = "top" },
+		"fast refresh":        func(d *PluginDefinition) { d.Panels[0].RefreshSeconds = 1 },
+	} {
+		definition := testHTTPDefinition()
+		mutate(&definition)
+		if err := validatePluginHTTPSurface(definition); err == nil {
+			t.Fatalf("validatePluginHTTPSurface(%s) error = nil, want error", name)
+		}
+	}
+
+	definition := testHTTPDefinition()
+	definition.HTTPRoutes[0].Path = "/players/../admin"
+	if err := NewPluginRegistry().RegisterPlugin(definition); err == nil {
+		t.Fatal("RegisterPlugin() accepted an invalid HTTP route")
+	}
+}
+
+func TestServePluginHTTP(t *testing.T) {
+	t.Parallel()
+
+	serverID := uuid.New()
+	instanceID := uuid.New()
+	plugin := &httpPlugin{}
+	instance := &PluginInstance{
+		ID:       instanceID,
+		ServerID: serverID,
+		PluginID: "com.example.dashboard",
+		Status:   PluginStatusRunning,
+		Plugin:   plugin,
+	}
+	pm := &PluginManager{
+		registry: NewPluginRegistry(),
+		plugins:  map[uuid.UUID]map[uuid.UUID]*PluginInstance{serverID: {instanceID: instance}},
+	}
+	if err := pm.registry.RegisterPlugin(testHTTPDefinition()); err != nil {
+		t.Fatalf("RegisterPlugin() error = %v", err)
+	}
+
+	resp, err := pm.ServePluginHTTP(context.Background(), serverID, instanceID, &PluginHTTPRequest{Method: http.MethodGet, Path: "/players/76561198000000000"}, nil)
+	if err != nil {
+		t.Fatalf("ServePluginHTTP() error = %v", err)
+	}
+	if want := `{"params":{"player_id":"76561198000000000"},"route":"/players/:player_id"}`; string(resp.Body) != want {
+		t.Fatalf("response body = %s, want %s", resp.Body, want)
+	}
+
+	if _, err := pm.ServePluginHTTP(context.Background(), serverID, instanceID, &PluginHTTPRequest{Method: http.MethodDelete, Path: "/leaderboard"}, nil); !errors.Is(err, ErrPluginHTTPRouteNotFound) {
+		t.Fatalf("ServePluginHTTP(undeclared method) error = %v, want ErrPluginHTTPRouteNotFound", err)
+	}
+
+	denied := errors.New("denied")
+	var authorized PluginHTTPRoute
+	_, err = pm.ServePluginHTTP(context.Background(), serverID, instanceID, &PluginHTTPRequest{Method: http.MethodPost, Path: "/reset"}, func(route PluginHTTPRoute) error {
+		authorized = route
+		return denied
+	})
+	if !errors.Is(err, denied) || authorized.Access != PluginHTTPAccessManage {
+		t.Fatalf("ServePluginHTTP(manage route) error = %v, authorized %+v, want the authorizer's error", err, authorized)
+	}
+
+	plugin.contentType = "text/html"
+	if _, err := pm.ServePluginHTTP(context.Background(), serverID, instanceID, &PluginHTTPRequest{Method: http.MethodGet, Path: "/leaderboard"}, nil); err == nil {
+		t.Fatal("ServePluginHTTP() served an HTML response")
+	}
+
+	instance.Status = PluginStatusStopped
+	if _, err := pm.ServePluginHTTP(context.Background(), serverID, instanceID, &PluginHTTPRequest{Method: http.MethodGet, Path: "/leaderboard"}, nil); !errors.Is(err, ErrPluginHTTPNotRunning) {
+		t.Fatalf("ServePluginHTTP(stopped) error = %v, want ErrPluginHTTPNotRunning", err)
+	}
+
+	panels, err := pm.GetPluginPanels(serverID, instanceID)
+	if err != nil {
+		t.Fatalf("GetPluginPanels() error = %v", err)
+	}
+	if len(panels) != 2 || panels[0].Access != PluginHTTPAccessView || panels[1].Access != PluginHTTPAccessManage {
+		t.Fatalf("GetPluginPanels() = %+v, want each panel tagged with its route access", panels)
+	}
+}
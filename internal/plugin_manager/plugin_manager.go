@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"runtime"
 	"sort"
@@ -53,6 +54,13 @@ type PluginManager struct {
 	// those flows span multiple separate critical sections.
 	installMu sync.Mutex
 
+	// Plugin catalogs. catalogRefreshMu serializes refreshes so a manual
+	// refresh cannot interleave with the background one.
+	catalogs               []*pluginCatalog
+	notifiedCatalogUpdates map[string]string
+	catalogMu              sync.RWMutex
+	catalogRefreshMu       sync.Mutex
+
 	// Event subscription
 	eventSubscriber *event_manager.EventSubscriber
 
@@ -125,6 +133,12 @@ func (pm *PluginManager) Start() error {
 		return fmt.Errorf("failed to load plugins from database: %w", err)
 	}
 
+	if nativePluginsEnabled() {
+		pm.startPluginCatalogRefresher()
+	}
+
+	pm.startPluginResourceMonitor()
+
 	// Start event distribution goroutine
 	go pm.eventDistributionLoop()
 
@@ -254,7 +268,18 @@ func (pm *PluginManager) RegisterConnector(definition ConnectorDefinition) error
 }
 
 // CreatePluginInstance creates and starts a new plugin instance
-func (pm *PluginManager) CreatePluginInstance(serverID uuid.UUID, pluginID string, notes string, config map[string]interface{}) (*PluginInstance, error) {
+func (pm *PluginManager) CreatePluginInstance(serverID uuid.UUID, pluginID string, notes string, config map[string]interface{}, capabilityGrants map[string]bool, rconPolicy RconCommandPolicy) (*PluginInstance, error) {
+	if serverID == GlobalServerID {
+		return nil, errors.New("global plugin instances must be created with CreateGlobalPluginInstance")
+	}
+	return pm.createPluginInstance(serverID, nil, pluginID, notes, config, capabilityGrants, rconPolicy)
+}
+
+// createPluginInstance creates a plugin instance for a server, or a global
+// instance scoped to serverIDs when serverID is GlobalServerID.
+func (pm *PluginManager) createPluginInstance(serverID uuid.UUID, serverIDs []uuid.UUID, pluginID string, notes string, config map[string]interface{}, capabilityGrants map[string]bool, rconPolicy RconCommandPolicy) (*PluginInstance, error) {
+	global := serverID == GlobalServerID
+
 	pm.installMu.Lock()
 	defer pm.installMu.Unlock()
 
@@ -272,6 +297,9 @@ func (pm *PluginManager) CreatePluginInstance(serverID uuid.UUID, pluginID strin
 		enrichedDefinition.InstallState != PluginInstallStateReady {
 		return nil, fmt.Errorf("plugin %s is not ready to be enabled (state=%s)", pluginID, enrichedDefinition.InstallState)
 	}
+	if global && !enrichedDefinition.AllowGlobalInstances {
+		return nil, fmt.Errorf("plugin %s does not support global instances", pluginID)
+	}
 
 	// Validate config for creation (ensures sensitive required fields are provided)
 	if err := enrichedDefinition.ConfigSchema.ValidateForCreation(config); err != nil {
@@ -281,6 +309,17 @@ func (pm *PluginManager) CreatePluginInstance(serverID uuid.UUID, pluginID strin
 	// Fill defaults
 	config = enrichedDefinition.ConfigSchema.FillDefaults(config)
 
+	// Native plugins start only once an operator has granted or denied
+	// every capability the package requests.
+	capabilityGrants = mergeCapabilityGrants(enrichedDefinition, nil, capabilityGrants)
+	if pending := pendingCapabilities(enrichedDefinition, capabilityGrants); len(pending) > 0 {
+		return nil, &CapabilityApprovalError{PluginID: pluginID, Pending: pending}
+	}
+	rconPolicy, err = rconPolicy.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
 	// Validate required connectors are running. ResolveConnectorInstanceKey
 	// and the snapshot read take the connectorMu briefly and release it before
 	// any RPC happens.
@@ -311,25 +350,30 @@ func (pm *PluginManager) CreatePluginInstance(serverID uuid.UUID, pluginID strin
 	ctx, cancel := context.WithCancel(pm.ctx)
 
 	instance := &PluginInstance{
-		ID:                instanceID,
-		ServerID:          serverID,
-		PluginID:          pluginID,
-		PluginName:        enrichedDefinition.Name,
-		Source:            enrichedDefinition.Source,
-		Official:          enrichedDefinition.Official,
-		Distribution:      enrichedDefinition.Distribution,
-		InstallState:      enrichedDefinition.InstallState,
-		MinHostAPIVersion: enrichedDefinition.MinHostAPIVersion,
-		Notes:             notes,
-		Config:            config,
-		Status:            PluginStatusStopped,
-		Enabled:           true,
-		LogLevel:          "info", // Default log level
-		Plugin:            plugin,
-		Context:           ctx,
-		Cancel:            cancel,
-		CreatedAt:         time.Now(),
-		UpdatedAt:         time.Now(),
+		ID:                  instanceID,
+		ServerID:            serverID,
+		ServerIDs:           serverIDs,
+		Global:              global,
+		PluginID:            pluginID,
+		PluginName:          enrichedDefinition.Name,
+		Source:              enrichedDefinition.Source,
+		Official:            enrichedDefinition.Official,
+		Distribution:        enrichedDefinition.Distribution,
+		InstallState:        enrichedDefinition.InstallState,
+		MinHostAPIVersion:   enrichedDefinition.MinHostAPIVersion,
+		Notes:               notes,
+		Config:              config,
+		ConfigSchemaVersion: enrichedDefinition.ConfigSchema.Version,
+		Status:              PluginStatusStopped,
+		Enabled:             true,
+		LogLevel:            "info", // Default log level
+		RconPolicy:          rconPolicy,
+		CapabilityGrants:    capabilityGrants,
+		Plugin:              plugin,
+		Context:             ctx,
+		Cancel:              cancel,
+		CreatedAt:           time.Now(),
+		UpdatedAt:           time.Now(),
 	}
 
 	pm.mu.Lock()
@@ -339,6 +383,9 @@ func (pm *PluginManager) CreatePluginInstance(serverID uuid.UUID, pluginID strin
 				if existing.PluginID == pluginID {
 					pm.mu.Unlock()
 					cancel()
+					if global {
+						return nil, fmt.Errorf("plugin %s does not allow multiple global instances", pluginID)
+					}
 					return nil, fmt.Errorf("plugin %s does not allow multiple instances on server %s", pluginID, serverID.String())
 				}
 			}
@@ -564,7 +611,13 @@ func (pm *PluginManager) UpdatePluginConfig(serverID, instanceID uuid.UUID, conf
 		return fmt.Errorf("plugin instance %s was replaced concurrently; config update discarded", instanceID.String())
 	}
 
+	// A config saved against the current schema supersedes a failed
+	// migration.
 	instance.Config = mergedConfig
+	instance.ConfigSchemaVersion = definition.ConfigSchema.Version
+	if instance.ConfigMigration != nil && instance.ConfigMigration.Status == PluginConfigMigrationFailed {
+		instance.ConfigMigration = nil
+	}
 	instance.UpdatedAt = time.Now()
 
 	if err := pm.updatePluginInstanceInDatabase(instance); err != nil {
@@ -650,8 +703,10 @@ func (pm *PluginManager) UpdatePluginLogLevel(serverID, instanceID uuid.UUID, lo
 	return nil
 }
 
-// EnablePluginInstance enables a plugin instance
-func (pm *PluginManager) EnablePluginInstance(serverID, instanceID uuid.UUID) error {
+// EnablePluginInstance enables a plugin instance, first recording any
+// capability grant decisions. A native plugin cannot be enabled while a
+// capability its package requests is still pending review.
+func (pm *PluginManager) EnablePluginInstance(serverID, instanceID uuid.UUID, capabilityGrants map[string]bool) error {
 	if err := pm.ensureRunning(); err != nil {
 		return err
 	}
@@ -672,13 +727,41 @@ func (pm *PluginManager) EnablePluginInstance(serverID, instanceID uuid.UUID) er
 	instance.lifecycleMu.Lock()
 	defer instance.lifecycleMu.Unlock()
 
+	// Registry lookups take their own locks, so resolve the definition
+	// before pm.mu.
+	var definition PluginDefinition
+	if registered, err := pm.registry.GetPlugin(instance.PluginID); err == nil {
+		definition = pm.enrichPluginDefinition(*registered)
+	}
+
 	pm.mu.Lock()
+	previousGrants := instance.CapabilityGrants
+	if len(capabilityGrants) > 0 {
+		// Host APIs are built when the plugin starts, so grants only
+		// change while it is stopped.
+		if instance.Enabled {
+			pm.mu.Unlock()
+			return errors.New("disable the plugin instance before changing its capability grants")
+		}
+		instance.CapabilityGrants = mergeCapabilityGrants(definition, instance.CapabilityGrants, capabilityGrants)
+	}
 	if instance.Enabled {
 		pm.mu.Unlock()
 		return nil // Already enabled
 	}
+	if migration := instance.ConfigMigration; migration != nil && migration.Status == PluginConfigMigrationFailed {
+		instance.CapabilityGrants = previousGrants
+		pm.mu.Unlock()
+		return fmt.Errorf("config migration failed (%s); update the plugin config before enabling", migration.Error)
+	}
+	if pending := pendingCapabilities(definition, instance.CapabilityGrants); len(pending) > 0 {
+		instance.CapabilityGrants = previousGrants
+		pm.mu.Unlock()
+		return &CapabilityApprovalError{PluginID: instance.PluginID, Pending: pending}
+	}
 	instance.Enabled = true
 	instance.UpdatedAt = time.Now()
+	instance.resourceUsage().clearQuarantine()
 	needsInit := instance.getStatus() == PluginStatusDisabled
 	pm.mu.Unlock()
 
@@ -688,6 +771,7 @@ func (pm *PluginManager) EnablePluginInstance(serverID, instanceID uuid.UUID) er
 			// clean enable rather than an enable-of-already-enabled no-op.
 			pm.mu.Lock()
 			instance.Enabled = false
+			instance.CapabilityGrants = previousGrants
 			pm.mu.Unlock()
 			return fmt.Errorf("failed to initialize plugin instance: %w", err)
 		}
@@ -1159,6 +1243,12 @@ func (pm *PluginManager) initializePluginInstance(instance *PluginInstance) erro
 
 	instance.setStatus(PluginStatusStarting)
 
+	// Subprocess plugins count their HostAPI calls into the instance's
+	// resource accounting.
+	if accounted, ok := instance.Plugin.(resourceAccountedPlugin); ok {
+		accounted.setResourceUsage(instance.resourceUsage())
+	}
+
 	// Create plugin APIs
 	apis := pm.createPluginAPIs(instance.Context, instance.ServerID, instance.ID, instance.PluginName, instance.PluginID, instance.LogLevel)
 
@@ -1323,11 +1413,13 @@ func (pm *PluginManager) stopPluginInstance(instance *PluginInstance) error {
 
 // resetPluginInstanceContext releases the per-instance context after Stop
 // so the next Enable allocates a fresh one. Without this,
-// ensurePluginInstanceContext would reuse the cancelled context.
+// ensurePluginInstanceContext would reuse the cancelled context. Runtime
+// event subscriptions end with the context.
 func (pm *PluginManager) resetPluginInstanceContext(instance *PluginInstance) {
 	if instance == nil {
 		return
 	}
+	instance.clearEventSubscriptions()
 	if instance.Cancel != nil {
 		instance.Cancel()
 	}
@@ -1335,33 +1427,42 @@ func (pm *PluginManager) resetPluginInstanceContext(instance *PluginInstance) {
 	instance.Cancel = nil
 }
 
+// createPluginAPIs builds the host APIs for an instance. Native plugins
+// only receive APIs for capabilities an operator has granted. Global
+// instances reach their servers through ServerScopeAPI instead of the
+// single-server APIs.
 func (pm *PluginManager) createPluginAPIs(ctx context.Context, serverID, instanceID uuid.UUID, pluginName, pluginID, logLevel string) *PluginAPIs {
 	apis := &PluginAPIs{}
-	if pm.shouldExposePluginAPI(pluginID, NativePluginCapabilityAPIServer) {
+	if serverID == GlobalServerID {
+		apis.ServerScopeAPI = pm.newServerScopeAPI(instanceID, pluginID)
+	} else if pm.capabilityGranted(serverID, instanceID, pluginID, NativePluginCapabilityAPIServer) {
 		apis.ServerAPI = NewServerAPI(serverID, pm.db, pm.rconManager)
 	}
-	if pm.shouldExposePluginAPI(pluginID, NativePluginCapabilityAPIDatabase) {
+	if pm.capabilityGranted(serverID, instanceID, pluginID, NativePluginCapabilityAPIDatabase) {
 		apis.DatabaseAPI = NewDatabaseAPI(instanceID, pm.db)
 	}
-	if pm.shouldExposePluginAPI(pluginID, NativePluginCapabilityAPIRule) {
+	if serverID != GlobalServerID && pm.capabilityGranted(serverID, instanceID, pluginID, NativePluginCapabilityAPIRule) {
 		apis.RuleAPI = NewRuleAPI(serverID, pm.db)
 	}
-	if pm.shouldExposePluginAPI(pluginID, NativePluginCapabilityAPIRCON) {
-		apis.RconAPI = NewRconAPI(serverID, pm.db, pm.rconManager, pm.clickhouseClient, pm.banSyncFunc)
+	if serverID != GlobalServerID && pm.capabilityGranted(serverID, instanceID, pluginID, NativePluginCapabilityAPIRCON) {
+		apis.RconAPI = pm.newInstanceRconAPI(serverID, instanceID, pluginID)
 	}
-	if pm.shouldExposePluginAPI(pluginID, NativePluginCapabilityAPIAdmin) {
+	if serverID != GlobalServerID && pm.capabilityGranted(serverID, instanceID, pluginID, NativePluginCapabilityAPIAdmin) {
 		apis.AdminAPI = NewAdminAPI(serverID, pm.db, pm.rconManager, instanceID, pluginID)
 	}
-	if pm.shouldExposePluginAPI(pluginID, NativePluginCapabilityAPIEvent) {
-		apis.EventAPI = NewEventAPI(ctx, serverID, instanceID, pluginName, pm.eventManager)
+	if pm.capabilityGranted(serverID, instanceID, pluginID, NativePluginCapabilityAPIEvent) {
+		apis.EventAPI = pm.newInstanceEventAPI(ctx, serverID, instanceID, pluginName)
+	}
+	if serverID != GlobalServerID && pm.capabilityGranted(serverID, instanceID, pluginID, NativePluginCapabilityAPIHistory) && pm.clickhouseClient != nil {
+		apis.HistoryAPI = newHistoryAPI(ctx, serverID, pm.clickhouseClient)
 	}
-	if pm.shouldExposePluginAPI(pluginID, NativePluginCapabilityAPIDiscord) {
+	if pm.capabilityGranted(serverID, instanceID, pluginID, NativePluginCapabilityAPIDiscord) {
 		apis.DiscordAPI = pm.wrapDiscordAPIWithAllowlist(pm.getDiscordAPI(), serverID, instanceID)
 	}
-	if pm.shouldExposePluginAPI(pluginID, NativePluginCapabilityAPILog) {
+	if pm.capabilityGranted(serverID, instanceID, pluginID, NativePluginCapabilityAPILog) {
 		apis.LogAPI = NewLogAPI(serverID, instanceID, pluginName, pluginID, logLevel, pm.clickhouseClient, pm.db, pm.eventManager)
 	}
-	if pm.shouldExposeConnectorAPI(pluginID) {
+	if pm.capabilityGranted(serverID, instanceID, pluginID, NativePluginCapabilityAPIConnector) {
 		apis.ConnectorAPI = newConnectorAPI(pm, pluginID)
 	}
 	return apis
@@ -1407,26 +1508,19 @@ func (pm *PluginManager) distributeEventToPlugins(event *event_manager.Event) {
 	var targets []dispatch
 
 	pm.mu.RLock()
-	if serverPlugins, exists := pm.plugins[event.ServerID]; exists {
-		for _, instance := range serverPlugins {
-			if instance.getStatus() != PluginStatusRunning || !instance.Enabled || instance.Plugin == nil {
-				continue
-			}
-
-			definition := instance.Plugin.GetDefinition()
-			handles := false
-			for _, e := range definition.Events {
-				if e == event.Type || e == event_manager.EventTypeAll {
-					handles = true
-					break
-				}
-			}
-
-			if handles {
+	if event.ServerID != GlobalServerID {
+		for _, instance := range pm.plugins[event.ServerID] {
+			if instanceHandlesEvent(instance, event.Type) {
 				targets = append(targets, dispatch{instance: instance})
 			}
 		}
 	}
+	// Global instances receive events from every server in their scope.
+	for _, instance := range pm.plugins[GlobalServerID] {
+		if instance.inScope(event.ServerID) && instanceHandlesEvent(instance, event.Type) {
+			targets = append(targets, dispatch{instance: instance})
+		}
+	}
 	pm.mu.RUnlock()
 
 	for _, t := range targets {
@@ -1434,9 +1528,45 @@ func (pm *PluginManager) distributeEventToPlugins(event *event_manager.Event) {
 	}
 }
 
+// instanceHandlesEvent reports whether a running instance declared or
+// subscribed to an event type. Native plugins only receive event families
+// they were granted.
+func instanceHandlesEvent(instance *PluginInstance, eventType event_manager.EventType) bool {
+	if instance.getStatus() != PluginStatusRunning || !instance.Enabled || instance.Plugin == nil {
+		return false
+	}
+
+	definition := instance.Plugin.GetDefinition()
+	handles := false
+	for _, e := range definition.Events {
+		if e == eventType || e == event_manager.EventTypeAll {
+			handles = true
+			break
+		}
+	}
+	if !handles {
+		handles = instance.subscribedToEvent(eventType)
+	}
+
+	if handles && instance.Source == PluginSourceNative {
+		if capability := capabilityForEventType(eventType); !instance.CapabilityGrants[capability] {
+			log.Debug().
+				Str("instanceID", instance.ID.String()).
+				Str("pluginID", instance.PluginID).
+				Str("capability", capability).
+				Str("eventType", string(eventType)).
+				Msg("Withheld event from plugin without the granted capability")
+			return false
+		}
+	}
+	return handles
+}
+
 func (pm *PluginManager) handlePluginEvent(instance *PluginInstance, event *PluginEvent) {
+	usage := instance.resourceUsage()
 	defer func() {
 		if r := recover(); r != nil {
+			usage.recordPanic()
 			log.Error().
 				Str("serverID", instance.ServerID.String()).
 				Str("instanceID", instance.ID.String()).
@@ -1448,7 +1578,10 @@ func (pm *PluginManager) handlePluginEvent(instance *PluginInstance, event *Plug
 		}
 	}()
 
-	if err := instance.Plugin.HandleEvent(event); err != nil {
+	started := time.Now()
+	err := instance.Plugin.HandleEvent(event)
+	usage.recordEvent(time.Since(started), err)
+	if err != nil {
 		log.Error().
 			Str("serverID", instance.ServerID.String()).
 			Str("instanceID", instance.ID.String()).
@@ -1466,12 +1599,19 @@ func (pm *PluginManager) handlePluginEvent(instance *PluginInstance, event *Plug
 }
 
 func (pm *PluginManager) convertEventSource(eventType event_manager.EventType) EventSource {
+	return EventSourceForType(eventType)
+}
+
+// EventSourceForType returns the source plugins see for an event type.
+func EventSourceForType(eventType event_manager.EventType) EventSource {
 	eventTypeStr := string(eventType)
 	switch {
 	case strings.HasPrefix(eventTypeStr, "RCON"):
 		return EventSourceRCON
 	case strings.HasPrefix(eventTypeStr, "LOG"):
 		return EventSourceLog
+	case strings.HasPrefix(eventTypeStr, "CONNECTOR"):
+		return EventSourceConnector
 	default:
 		return EventSourceSystem
 	}
@@ -1854,6 +1994,7 @@ func (pm *PluginManager) ExecutePluginCommand(serverID, instanceID uuid.UUID, co
 	func() {
 		defer func() {
 			if r := recover(); r != nil {
+				instance.resourceUsage().recordPanic()
 				log.Error().
 					Str("serverID", serverID.String()).
 					Str("instanceID", instanceID.String()).
@@ -0,0 +1,252 @@
+package plugin_manager
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// RCON command policy modes. An empty mode places no restriction on the
+// commands an instance may send.
+const (
+	RconPolicyModeAllow = "allow"
+	RconPolicyModeDeny  = "deny"
+)
+
+// maxRconPolicyCommands bounds the number of commands in a policy
+const maxRconPolicyCommands = 200
+
+// ErrRconCommandDenied is returned when a plugin instance's RCON policy
+// rejects a command.
+var ErrRconCommandDenied = errors.New("rcon command denied by plugin instance policy")
+
+// RconCommandPolicy restricts the RCON commands a plugin instance may send.
+// In allow mode only the listed commands may be sent; in deny mode the
+// listed commands are refused. Commands are matched on their name, the
+// first word of the command line, ignoring case.
+type RconCommandPolicy struct {
+	Mode     string   `json:"mode,omitempty"`
+	Commands []string `json:"commands,omitempty"`
+}
+
+// Normalize validates a policy and returns it with trimmed, de-duplicated
+// command names.
+func (p RconCommandPolicy) Normalize() (RconCommandPolicy, error) {
+	mode := strings.ToLower(strings.TrimSpace(p.Mode))
+	switch mode {
+	case "", RconPolicyModeAllow, RconPolicyModeDeny:
+	default:
+		return RconCommandPolicy{}, fmt.Errorf("rcon policy mode must be %q, %q or empty", RconPolicyModeAllow, RconPolicyModeDeny)
+	}
+
+	commands := make([]string, 0, len(p.Commands))
+	seen := make(map[string]bool, len(p.Commands))
+	for _, command := range p.Commands {
+		command = strings.TrimSpace(command)
+		if command == "" {
+			continue
+		}
+		if strings.ContainsAny(command, " \t\r\n") {
+			return RconCommandPolicy{}, fmt.Errorf("rcon policy entry %q must be a single command name", command)
+		}
+		key := strings.ToLower(command)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		commands = append(commands, command)
+	}
+	if len(commands) > maxRconPolicyCommands {
+		return RconCommandPolicy{}, fmt.Errorf("rcon policy may list at most %d commands", maxRconPolicyCommands)
+	}
+	if mode == "" && len(commands) > 0 {
+		return RconCommandPolicy{}, errors.New("rcon policy commands require an allow or deny mode")
+	}
+
+	return RconCommandPolicy{Mode: mode, Commands: commands}, nil
+}
+
+// Allows reports whether the policy permits sending command
+func (p RconCommandPolicy) Allows(command string) bool {
+	name := rconCommandName(command)
+	listed := false
+	for _, entry := range p.Commands {
+		if strings.EqualFold(entry, name) {
+			listed = true
+			break
+		}
+	}
+
+	switch p.Mode {
+	case RconPolicyModeAllow:
+		return listed
+	case RconPolicyModeDeny:
+		return !listed
+	default:
+		return true
+	}
+}
+
+// rconCommandName returns the command name of an RCON command line
+func rconCommandName(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// CapabilityApprovalError is returned when a native plugin instance is
+// enabled while some of the capabilities its package requests have not
+// been granted or denied by an operator.
+type CapabilityApprovalError struct {
+	PluginID string
+	Pending  []string
+}
+
+func (e *CapabilityApprovalError) Error() string {
+	return fmt.Sprintf("plugin %s requests capabilities that need operator approval: %s", e.PluginID, strings.Join(e.Pending, ", "))
+}
+
+// reviewableCapabilities returns the capabilities an operator grants or
+// denies for a definition. Bundled plugins are trusted and have none; the
+// entrypoint capability is required to load a native plugin at all.
+func reviewableCapabilities(definition PluginDefinition) []string {
+	if definition.Source != PluginSourceNative {
+		return nil
+	}
+
+	capabilities := make([]string, 0, len(definition.RequiredCapabilities))
+	seen := make(map[string]bool, len(definition.RequiredCapabilities))
+	for _, capability := range definition.RequiredCapabilities {
+		capability = strings.TrimSpace(capability)
+		if capability == "" || capability == NativePluginCapabilityEntrypointGetAegisPlugin || seen[capability] {
+			continue
+		}
+		seen[capability] = true
+		capabilities = append(capabilities, capability)
+	}
+	sort.Strings(capabilities)
+	return capabilities
+}
+
+// pendingCapabilities returns the reviewable capabilities with no grant
+// decision recorded.
+func pendingCapabilities(definition PluginDefinition, grants map[string]bool) []string {
+	var pending []string
+	for _, capability := range reviewableCapabilities(definition) {
+		if _, reviewed := grants[capability]; !reviewed {
+			pending = append(pending, capability)
+		}
+	}
+	return pending
+}
+
+// mergeCapabilityGrants records grant decisions for the capabilities a
+// definition requests, ignoring any it does not request.
+func mergeCapabilityGrants(definition PluginDefinition, current, decisions map[string]bool) map[string]bool {
+	merged := make(map[string]bool, len(current)+len(decisions))
+	for capability, granted := range current {
+		merged[capability] = granted
+	}
+	for _, capability := range reviewableCapabilities(definition) {
+		if granted, ok := decisions[capability]; ok {
+			merged[capability] = granted
+		}
+	}
+	return merged
+}
+
+func cloneCapabilityGrants(grants map[string]bool) map[string]bool {
+	cloned := make(map[string]bool, len(grants))
+	for capability, granted := range grants {
+		cloned[capability] = granted
+	}
+	return cloned
+}
+
+// instancePolicy returns a snapshot of an instance's RCON policy and
+// capability grants, read live so operator changes apply without a restart.
+func (pm *PluginManager) instancePolicy(serverID, instanceID uuid.UUID) (RconCommandPolicy, map[string]bool, bool) {
+	if pm == nil {
+		return RconCommandPolicy{}, nil, false
+	}
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	if instance, ok := pm.plugins[GlobalServerID][instanceID]; ok && instance != nil && serverID != GlobalServerID {
+		// A global instance acting on one of its servers. Servers outside
+		// its scope get a policy that denies every command and no grants.
+		if !instance.inScope(serverID) {
+			return RconCommandPolicy{Mode: RconPolicyModeAllow}, nil, false
+		}
+		return instance.RconPolicy, instance.CapabilityGrants, true
+	}
+	serverPlugins, ok := pm.plugins[serverID]
+	if !ok {
+		return RconCommandPolicy{}, nil, false
+	}
+	instance, ok := serverPlugins[instanceID]
+	if !ok || instance == nil {
+		return RconCommandPolicy{}, nil, false
+	}
+	return instance.RconPolicy, instance.CapabilityGrants, true
+}
+
+// capabilityGranted reports whether a native plugin instance may use a
+// capability: the package must request it and an operator must grant it.
+// Bundled plugins are always allowed.
+func (pm *PluginManager) capabilityGranted(serverID, instanceID uuid.UUID, pluginID, capability string) bool {
+	if !pm.shouldExposePluginAPI(pluginID, capability) {
+		return false
+	}
+	definition, err := pm.registry.GetPlugin(pluginID)
+	if err != nil {
+		return false
+	}
+	if pm.enrichPluginDefinition(*definition).Source != PluginSourceNative {
+		return true
+	}
+	_, grants, ok := pm.instancePolicy(serverID, instanceID)
+	if !ok || !grants[capability] {
+		log.Warn().
+			Str("serverID", serverID.String()).
+			Str("instanceID", instanceID.String()).
+			Str("pluginID", pluginID).
+			Str("capability", capability).
+			Msg("Withholding plugin capability that has not been granted")
+		return false
+	}
+	return true
+}
+
+// UpdatePluginRconPolicy replaces a plugin instance's RCON command policy.
+// The policy applies to the next command the instance sends.
+func (pm *PluginManager) UpdatePluginRconPolicy(serverID, instanceID uuid.UUID, policy RconCommandPolicy) error {
+	policy, err := policy.Normalize()
+	if err != nil {
+		return err
+	}
+
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	instance, err := pm.getPluginInstanceUnsafe(serverID, instanceID)
+	if err != nil {
+		return err
+	}
+
+	previous := instance.RconPolicy
+	instance.RconPolicy = policy
+	instance.UpdatedAt = time.Now()
+	if err := pm.updatePluginInstanceInDatabase(instance); err != nil {
+		instance.RconPolicy = previous
+		return fmt.Errorf("failed to update plugin instance in database: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package plugin_manager
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRconCommandPolicyNormalize(t *testing.T) {
+	t.Parallel()
+
+	policy, err := RconCommandPolicy{Mode: " Allow ", Commands: []string{"AdminBroadcast", " adminbroadcast", "", "AdminWarn"}}.Normalize()
+	if err != nil {
+		t.Fatalf("Normalize() error = %v", err)
+	}
+	want := RconCommandPolicy{Mode: RconPolicyModeAllow, Commands: []string{"AdminBroadcast", "AdminWarn"}}
+	if !reflect.DeepEqual(policy, want) {
+		t.Fatalf("Normalize() = %+v, want %+v", policy, want)
+	}
+
+	for name, invalid := range map[string]RconCommandPolicy{
+		"unknown mode":          {Mode: "block"},
+		"commands without mode": {Commands: []string{"AdminBroadcast"}},
+		"command with args":     {Mode: RconPolicyModeDeny, Commands: []string{"AdminKick player"}},
+	} {
+		if _, err := invalid.Normalize(); err == nil {
+			t.Errorf("Normalize(%s) error = nil, want error", name)
+		}
+	}
+}
+
+func TestRconCommandPolicyAllows(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		policy  RconCommandPolicy
+		command string
+		want    bool
+	}{
+		{"no policy", RconCommandPolicy{}, "AdminEndMatch", true},
+		{"allow listed", RconCommandPolicy{Mode: RconPolicyModeAllow, Commands: []string{"AdminBroadcast"}}, "adminbroadcast Hello there", true},
+		{"allow unlisted", RconCommandPolicy{Mode: RconPolicyModeAllow, Commands: []string{"AdminBroadcast"}}, "AdminEndMatch", false},
+		{"allow empty list", RconCommandPolicy{Mode: RconPolicyModeAllow}, "AdminBroadcast hi", false},
+		{"deny listed", RconCommandPolicy{Mode: RconPolicyModeDeny, Commands: []string{"AdminEndMatch"}}, "AdminEndMatch", false},
+		{"deny unlisted", RconCommandPolicy{Mode: RconPolicyModeDeny, Commands: []string{"AdminEndMatch"}}, "AdminBroadcast hi", true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.policy.Allows(tt.command); got != tt.want {
+			t.Errorf("%s: Allows(%q) = %v, want %v", tt.name, tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestPendingCapabilities(t *testing.T) {
+	t.Parallel()
+
+	definition := PluginDefinition{
+		Source: PluginSourceNative,
+		RequiredCapabilities: []string{
+			NativePluginCapabilityEntrypointGetAegisPlugin,
+			NativePluginCapabilityAPIRCON,
+			NativePluginCapabilityAPILog,
+			NativePluginCapabilityEventsRCON,
+		},
+	}
+
+	pending := pendingCapabilities(definition, map[string]bool{NativePluginCapabilityAPIRCON: false})
+	want := []string{NativePluginCapabilityAPILog, NativePluginCapabilityEventsRCON}
+	if !reflect.DeepEqual(pending, want) {
+		t.Fatalf("pendingCapabilities() = %v, want %v", pending, want)
+	}
+
+	grants := mergeCapabilityGrants(definition, nil, map[string]bool{
+		NativePluginCapabilityAPILog:      true,
+		NativePluginCapabilityEventsRCON:  true,
+		NativePluginCapabilityAPIDatabase: true, // not requested
+	})
+	if _, ok := grants[NativePluginCapabilityAPIDatabase]; ok {
+		t.Fatal("mergeCapabilityGrants() recorded a capability the package does not request")
+	}
+
+	bundled := PluginDefinition{Source: PluginSourceBundled, RequiredCapabilities: definition.RequiredCapabilities}
+	if pending := pendingCapabilities(bundled, nil); len(pending) != 0 {
+		t.Fatalf("pendingCapabilities(bundled) = %v, want none", pending)
+	}
+}
+
+func TestRconAPIDeniesCommandsOutsidePolicy(t *testing.T) {
+	t.Parallel()
+
+	// A denied command must never reach the RCON manager, which is nil here
+	api := &rconAPI{
+		serverID:   uuid.New(),
+		pluginID:   "com.example.policy",
+		instanceID: uuid.New(),
+		policy: func() RconCommandPolicy {
+			return RconCommandPolicy{Mode: RconPolicyModeAllow, Commands: []string{"AdminBroadcast"}}
+		},
+	}
+
+	if _, err := api.SendCommand("AdminEndMatch"); !errors.Is(err, ErrRconCommandDenied) {
+		t.Fatalf("SendCommand() error = %v, want ErrRconCommandDenied", err)
+	}
+	if err := api.KickPlayer("76561198000000000", "bye"); !errors.Is(err, ErrRconCommandDenied) {
+		t.Fatalf("KickPlayer() error = %v, want ErrRconCommandDenied", err)
+	}
+	// Bans are refused before anything is stored
+	if err := api.BanPlayer("76561198000000000", "bye", time.Hour); !errors.Is(err, ErrRconCommandDenied) {
+		t.Fatalf("BanPlayer() error = %v, want ErrRconCommandDenied", err)
+	}
+}
@@ -0,0 +1,66 @@
+//go:build linux
+
+package plugin_manager
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// procClockTicks is USER_HZ, the unit of the CPU times in /proc/<pid>/stat.
+// It is 100 on every Linux architecture Aegis supports.
+const procClockTicks = 100
+
+// readProcessUsage returns a process's total CPU time and resident set size
+// from /proc.
+func readProcessUsage(pid int) (time.Duration, uint64, error) {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, 0, err
+	}
+	cpuTime, err := parseProcStatCPUTime(string(stat))
+	if err != nil {
+		return 0, 0, err
+	}
+	statm, err := os.ReadFile(fmt.Sprintf("/proc/%d/statm", pid))
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(string(statm))
+	if len(fields) < 2 {
+		return 0, 0, fmt.Errorf("malformed /proc/%d/statm", pid)
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed /proc/%d/statm: %w", pid, err)
+	}
+	return cpuTime, pages * uint64(os.Getpagesize()), nil
+}
+
+// parseProcStatCPUTime returns utime+stime from a /proc/<pid>/stat line.
+// The command name can contain spaces and parentheses, so fields are
+// counted from the last closing parenthesis.
+func parseProcStatCPUTime(stat string) (time.Duration, error) {
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return 0, fmt.Errorf("malformed /proc stat line")
+	}
+	// Fields after the command start at field 3 (state); utime and stime
+	// are fields 14 and 15.
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 13 {
+		return 0, fmt.Errorf("malformed /proc stat line")
+	}
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed utime: %w", err)
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed stime: %w", err)
+	}
+	return time.Duration(utime+stime) * time.Second / procClockTicks, nil
+}
@@ -0,0 +1,35 @@
+//go:build linux
+
+package plugin_manager
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseProcStatCPUTime(t *testing.T) {
+	// The command name contains spaces and a closing parenthesis.
+	stat := "1234 (my plugin) x) S 1 1234 1234 0 -1 4194560 500 0 0 0 250 50 0 0 20 0 8 0 100 1000000 300 18446744073709551615"
+	got, err := parseProcStatCPUTime(stat)
+	if err != nil {
+		t.Fatalf("parseProcStatCPUTime() error = %v", err)
+	}
+	if want := 3 * time.Second; got != want {
+		t.Fatalf("parseProcStatCPUTime() = %s, want %s", got, want)
+	}
+
+	if _, err := parseProcStatCPUTime("1234 (truncated"); err == nil {
+		t.Fatal("parseProcStatCPUTime(malformed) error = nil, want error")
+	}
+}
+
+func TestReadProcessUsageOfSelf(t *testing.T) {
+	_, rss, err := readProcessUsage(os.Getpid())
+	if err != nil {
+		t.Fatalf("readProcessUsage() error = %v", err)
+	}
+	if rss == 0 {
+		t.Fatal("readProcessUsage() rss = 0, want the test process's resident set")
+	}
+}
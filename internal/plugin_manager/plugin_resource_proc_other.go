@@ -0,0 +1,14 @@
+//go:build !linux
+
+package plugin_manager
+
+import (
+	"errors"
+	"time"
+)
+
+// readProcessUsage is unsupported without /proc; process metrics are
+// omitted and the CPU and RSS thresholds never trigger.
+func readProcessUsage(_ int) (time.Duration, uint64, error) {
+	return 0, 0, errors.New("process accounting requires /proc")
+}
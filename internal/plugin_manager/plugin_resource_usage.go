@@ -0,0 +1,519 @@
+package plugin_manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
+	"go.codycody31.dev/squad-aegis/internal/shared/config"
+)
+
+// PluginResourceMetrics is the resource accounting for one plugin instance,
+// served by the plugin metrics route. Counters cover the instance since
+// Aegis started, across restarts of its runtime.
+type PluginResourceMetrics struct {
+	// Process is the latest /proc sample of the instance's subprocess. It
+	// is nil for in-process and WASM plugins, before the first sample, and
+	// on hosts without /proc.
+	Process *PluginProcessMetrics `json:"process,omitempty"`
+
+	// HostAPICalls counts HostAPI calls by method, including rejected ones.
+	HostAPICalls      map[string]uint64 `json:"host_api_calls"`
+	HostAPICallsTotal uint64            `json:"host_api_calls_total"`
+	HostAPIErrors     uint64            `json:"host_api_errors"`
+
+	// RconCommands counts RCON commands sent through the HostAPI.
+	RconCommands uint64 `json:"rcon_commands"`
+
+	Events PluginEventMetrics `json:"events"`
+
+	// Panics counts panics recovered while the host called into the plugin.
+	Panics uint64 `json:"panics"`
+
+	// Window holds the rates over the last sample interval, which the
+	// quarantine thresholds are checked against.
+	Window *PluginUsageWindow `json:"window,omitempty"`
+
+	// Quarantine is set when the instance was disabled for exceeding a
+	// threshold, until an operator enables it again.
+	Quarantine *PluginQuarantine `json:"quarantine,omitempty"`
+}
+
+// PluginProcessMetrics is one sample of a plugin subprocess.
+type PluginProcessMetrics struct {
+	PID        int       `json:"pid"`
+	CPUPercent float64   `json:"cpu_percent"`
+	RSSBytes   uint64    `json:"rss_bytes"`
+	SampledAt  time.Time `json:"sampled_at"`
+}
+
+// PluginEventMetrics describes how an instance handled events.
+type PluginEventMetrics struct {
+	Handled      uint64  `json:"handled"`
+	Errors       uint64  `json:"errors"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+	MaxLatencyMs float64 `json:"max_latency_ms"`
+}
+
+// PluginUsageWindow holds the rates between the last two samples.
+type PluginUsageWindow struct {
+	Seconds               float64 `json:"seconds"`
+	HostAPICallsPerMinute float64 `json:"host_api_calls_per_minute"`
+	RconCommandsPerMinute float64 `json:"rcon_commands_per_minute"`
+	AvgEventLatencyMs     float64 `json:"avg_event_latency_ms"`
+}
+
+// PluginQuarantine records why an instance was automatically disabled.
+type PluginQuarantine struct {
+	Reason string    `json:"reason"`
+	At     time.Time `json:"at"`
+}
+
+// quarantineThresholds are the limits an instance is disabled for
+// exceeding. A zero limit is not checked.
+type quarantineThresholds struct {
+	maxCPUPercent            float64
+	maxRSSBytes              uint64
+	maxHostAPICallsPerMinute float64
+	maxRconCommandsPerMinute float64
+	maxEventLatency          time.Duration
+	maxPanics                uint64
+	strikes                  int
+}
+
+// quarantineThresholdsFromConfig reads the thresholds from
+// config.Config.Plugins. Without a config nothing is quarantined.
+func quarantineThresholdsFromConfig() quarantineThresholds {
+	if config.Config == nil {
+		return quarantineThresholds{}
+	}
+	cfg := config.Config.Plugins
+	t := quarantineThresholds{strikes: cfg.QuarantineStrikes}
+	if cfg.QuarantineMaxCPUPercent > 0 {
+		t.maxCPUPercent = cfg.QuarantineMaxCPUPercent
+	}
+	if cfg.QuarantineMaxRSSMB > 0 {
+		t.maxRSSBytes = uint64(cfg.QuarantineMaxRSSMB) << 20
+	}
+	if cfg.QuarantineMaxHostAPICallsPerMinute > 0 {
+		t.maxHostAPICallsPerMinute = float64(cfg.QuarantineMaxHostAPICallsPerMinute)
+	}
+	if cfg.QuarantineMaxRconCommandsPerMinute > 0 {
+		t.maxRconCommandsPerMinute = float64(cfg.QuarantineMaxRconCommandsPerMinute)
+	}
+	if cfg.QuarantineMaxEventLatencyMs > 0 {
+		t.maxEventLatency = time.Duration(cfg.QuarantineMaxEventLatencyMs) * time.Millisecond
+	}
+	if cfg.QuarantineMaxPanics > 0 {
+		t.maxPanics = uint64(cfg.QuarantineMaxPanics)
+	}
+	if t.strikes < 1 {
+		t.strikes = 1
+	}
+	return t
+}
+
+// resourceSampleInterval reads the sampling interval from config.Config.
+// Zero disables the resource monitor.
+func resourceSampleInterval() time.Duration {
+	seconds := 15
+	if config.Config != nil {
+		seconds = config.Config.Plugins.ResourceSampleIntervalSeconds
+	}
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// pluginResourceUsage accumulates the accounting for one plugin instance.
+// The HostAPI dispatcher and event handling record into it as calls happen;
+// the resource monitor samples the process and checks thresholds.
+type pluginResourceUsage struct {
+	mu sync.Mutex
+
+	hostAPICalls    map[string]uint64
+	hostAPITotal    uint64
+	hostAPIErrors   uint64
+	rconCommands    uint64
+	eventsHandled   uint64
+	eventErrors     uint64
+	eventLatency    time.Duration
+	eventLatencyMax time.Duration
+	panics          uint64
+
+	process *PluginProcessMetrics
+	window  *PluginUsageWindow
+
+	// Counters as of the previous sample, for the window rates.
+	lastSample       time.Time
+	lastPID          int
+	lastCPUTime      time.Duration
+	lastHostAPITotal uint64
+	lastRcon         uint64
+	lastEvents       uint64
+	lastLatency      time.Duration
+
+	// panicBaseline is the panic count when the instance was last enabled,
+	// so a re-enabled instance is not quarantined again for old panics.
+	panicBaseline uint64
+	strikes       int
+	quarantine    *PluginQuarantine
+}
+
+func newPluginResourceUsage() *pluginResourceUsage {
+	return &pluginResourceUsage{hostAPICalls: make(map[string]uint64)}
+}
+
+// recordHostAPICall counts a HostAPI call. Successful Rcon* calls each send
+// one RCON command.
+func (u *pluginResourceUsage) recordHostAPICall(method string, err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.hostAPICalls[method]++
+	u.hostAPITotal++
+	if err != nil {
+		u.hostAPIErrors++
+	} else if strings.HasPrefix(method, "Rcon") {
+		u.rconCommands++
+	}
+}
+
+// recordEvent records how long the plugin took to handle one event.
+func (u *pluginResourceUsage) recordEvent(latency time.Duration, err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.eventsHandled++
+	if err != nil {
+		u.eventErrors++
+	}
+	u.eventLatency += latency
+	if latency > u.eventLatencyMax {
+		u.eventLatencyMax = latency
+	}
+}
+
+// recordPanic counts a recovered panic.
+func (u *pluginResourceUsage) recordPanic() {
+	u.mu.Lock()
+	u.panics++
+	u.mu.Unlock()
+}
+
+// clearQuarantine resets the quarantine state when an operator enables the
+// instance again.
+func (u *pluginResourceUsage) clearQuarantine() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.quarantine = nil
+	u.strikes = 0
+	u.panicBaseline = u.panics
+}
+
+// quarantined returns a copy of the quarantine record, or nil.
+func (u *pluginResourceUsage) quarantined() *PluginQuarantine {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.quarantine == nil {
+		return nil
+	}
+	q := *u.quarantine
+	return &q
+}
+
+// sample records a process sample (when sampled is set), updates the window
+// rates, and checks the thresholds. It returns the quarantine reason when
+// the instance must be disabled; the quarantine is recorded before it
+// returns, so an instance is only ever quarantined once per enable.
+func (u *pluginResourceUsage) sample(now time.Time, pid int, cpuTime time.Duration, rss uint64, sampled bool, t quarantineThresholds) string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.quarantine != nil {
+		return ""
+	}
+
+	var exceeded []string
+	elapsed := now.Sub(u.lastSample)
+	hasWindow := !u.lastSample.IsZero() && elapsed > 0
+
+	if sampled {
+		process := &PluginProcessMetrics{PID: pid, RSSBytes: rss, SampledAt: now}
+		if hasWindow && pid == u.lastPID && cpuTime >= u.lastCPUTime {
+			process.CPUPercent = float64(cpuTime-u.lastCPUTime) / float64(elapsed) * 100
+		}
+		u.process = process
+		u.lastPID = pid
+		u.lastCPUTime = cpuTime
+
+		if t.maxCPUPercent > 0 && process.CPUPercent > t.maxCPUPercent {
+			exceeded = append(exceeded, fmt.Sprintf("cpu %.1f%% exceeds %.1f%%", process.CPUPercent, t.maxCPUPercent))
+		}
+		if t.maxRSSBytes > 0 && rss > t.maxRSSBytes {
+			exceeded = append(exceeded, fmt.Sprintf("rss %d MiB exceeds %d MiB", rss>>20, t.maxRSSBytes>>20))
+		}
+	} else {
+		u.process = nil
+		u.lastPID = 0
+	}
+
+	if hasWindow {
+		minutes := elapsed.Minutes()
+		window := &PluginUsageWindow{
+			Seconds:               elapsed.Seconds(),
+			HostAPICallsPerMinute: float64(u.hostAPITotal-u.lastHostAPITotal) / minutes,
+			RconCommandsPerMinute: float64(u.rconCommands-u.lastRcon) / minutes,
+		}
+		var avgLatency time.Duration
+		if events := u.eventsHandled - u.lastEvents; events > 0 {
+			avgLatency = (u.eventLatency - u.lastLatency) / time.Duration(events)
+			window.AvgEventLatencyMs = durationMs(avgLatency)
+		}
+		u.window = window
+
+		if t.maxHostAPICallsPerMinute > 0 && window.HostAPICallsPerMinute > t.maxHostAPICallsPerMinute {
+			exceeded = append(exceeded, fmt.Sprintf("%.0f host api calls/min exceeds %.0f", window.HostAPICallsPerMinute, t.maxHostAPICallsPerMinute))
+		}
+		if t.maxRconCommandsPerMinute > 0 && window.RconCommandsPerMinute > t.maxRconCommandsPerMinute {
+			exceeded = append(exceeded, fmt.Sprintf("%.0f rcon commands/min exceeds %.0f", window.RconCommandsPerMinute, t.maxRconCommandsPerMinute))
+		}
+		if t.maxEventLatency > 0 && avgLatency > t.maxEventLatency {
+			exceeded = append(exceeded, fmt.Sprintf("average event latency %s exceeds %s", avgLatency.Round(time.Millisecond), t.maxEventLatency))
+		}
+	}
+	u.lastSample = now
+	u.lastHostAPITotal = u.hostAPITotal
+	u.lastRcon = u.rconCommands
+	u.lastEvents = u.eventsHandled
+	u.lastLatency = u.eventLatency
+
+	reason := ""
+	if len(exceeded) > 0 {
+		u.strikes++
+		if u.strikes >= t.strikes {
+			reason = strings.Join(exceeded, "; ")
+		}
+	} else {
+		u.strikes = 0
+	}
+	// Panics quarantine on the first sample that sees them, without strikes.
+	if panics := u.panics - u.panicBaseline; t.maxPanics > 0 && panics >= t.maxPanics {
+		reason = fmt.Sprintf("%d panics reached the limit of %d", panics, t.maxPanics)
+	}
+	if reason != "" {
+		u.quarantine = &PluginQuarantine{Reason: reason, At: now}
+	}
+	return reason
+}
+
+// snapshot returns the metrics served for the instance.
+func (u *pluginResourceUsage) snapshot() *PluginResourceMetrics {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	metrics := &PluginResourceMetrics{
+		HostAPICalls:      make(map[string]uint64, len(u.hostAPICalls)),
+		HostAPICallsTotal: u.hostAPITotal,
+		HostAPIErrors:     u.hostAPIErrors,
+		RconCommands:      u.rconCommands,
+		Events: PluginEventMetrics{
+			Handled:      u.eventsHandled,
+			Errors:       u.eventErrors,
+			MaxLatencyMs: durationMs(u.eventLatencyMax),
+		},
+		Panics: u.panics,
+	}
+	for method, count := range u.hostAPICalls {
+		metrics.HostAPICalls[method] = count
+	}
+	if u.eventsHandled > 0 {
+		metrics.Events.AvgLatencyMs = durationMs(u.eventLatency / time.Duration(u.eventsHandled))
+	}
+	if u.process != nil {
+		process := *u.process
+		metrics.Process = &process
+	}
+	if u.window != nil {
+		window := *u.window
+		metrics.Window = &window
+	}
+	if u.quarantine != nil {
+		q := *u.quarantine
+		metrics.Quarantine = &q
+	}
+	return metrics
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// instrumentHostAPIService returns a copy of a gRPC service description
+// whose handlers count every call into usage. The same description serves
+// both broker-connected subprocesses and the WASM host route, so calls are
+// counted however the plugin reaches the host. A nil usage returns desc.
+func instrumentHostAPIService(desc *grpc.ServiceDesc, usage *pluginResourceUsage) *grpc.ServiceDesc {
+	if usage == nil {
+		return desc
+	}
+	instrumented := *desc
+	instrumented.Methods = make([]grpc.MethodDesc, len(desc.Methods))
+	for i, method := range desc.Methods {
+		name := method.MethodName
+		handler := method.Handler
+		instrumented.Methods[i] = grpc.MethodDesc{
+			MethodName: name,
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				resp, err := handler(srv, ctx, dec, interceptor)
+				usage.recordHostAPICall(name, err)
+				return resp, err
+			},
+		}
+	}
+	return &instrumented
+}
+
+// resourceAccountedPlugin is implemented by plugin shims that record HostAPI
+// usage. The manager hands each one its instance's usage before Initialize.
+type resourceAccountedPlugin interface {
+	setResourceUsage(usage *pluginResourceUsage)
+}
+
+// processReporter is implemented by plugin shims backed by an OS process.
+// processID returns 0 while no process is running.
+type processReporter interface {
+	processID() int
+}
+
+// resourceUsage returns the instance's accounting, creating it on first use.
+func (pi *PluginInstance) resourceUsage() *pluginResourceUsage {
+	pi.mu.Lock()
+	defer pi.mu.Unlock()
+	if pi.usage == nil {
+		pi.usage = newPluginResourceUsage()
+	}
+	return pi.usage
+}
+
+// GetPluginInstanceMetrics returns the resource accounting for a plugin
+// instance.
+func (pm *PluginManager) GetPluginInstanceMetrics(serverID, instanceID uuid.UUID) (*PluginResourceMetrics, error) {
+	pm.mu.RLock()
+	instance, err := pm.getPluginInstanceUnsafe(serverID, instanceID)
+	pm.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	return instance.resourceUsage().snapshot(), nil
+}
+
+// startPluginResourceMonitor samples every running instance at the
+// configured interval until the manager stops.
+func (pm *PluginManager) startPluginResourceMonitor() {
+	interval := resourceSampleInterval()
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-pm.ctx.Done():
+				return
+			case <-ticker.C:
+				pm.samplePluginResources(time.Now())
+			}
+		}
+	}()
+}
+
+// samplePluginResources samples each running instance's process and
+// quarantines the instances over a threshold.
+func (pm *PluginManager) samplePluginResources(now time.Time) {
+	type target struct {
+		instance *PluginInstance
+		plugin   Plugin
+	}
+	var targets []target
+	pm.mu.RLock()
+	for _, serverPlugins := range pm.plugins {
+		for _, instance := range serverPlugins {
+			if instance.Enabled && instance.Plugin != nil && instance.getStatus() == PluginStatusRunning {
+				targets = append(targets, target{instance: instance, plugin: instance.Plugin})
+			}
+		}
+	}
+	pm.mu.RUnlock()
+
+	thresholds := quarantineThresholdsFromConfig()
+	for _, t := range targets {
+		var (
+			pid     int
+			cpuTime time.Duration
+			rss     uint64
+			sampled bool
+		)
+		if reporter, ok := t.plugin.(processReporter); ok {
+			pid = reporter.processID()
+		}
+		if pid > 0 {
+			var err error
+			cpuTime, rss, err = readProcessUsage(pid)
+			if err != nil {
+				log.Debug().
+					Err(err).
+					Str("instanceID", t.instance.ID.String()).
+					Int("pid", pid).
+					Msg("Failed to sample plugin subprocess resources")
+			} else {
+				sampled = true
+			}
+		}
+
+		if reason := t.instance.resourceUsage().sample(now, pid, cpuTime, rss, sampled, thresholds); reason != "" {
+			// Disabling can wait up to 30s on a wedged plugin; do not hold
+			// up sampling the others.
+			go pm.quarantinePluginInstance(t.instance, reason)
+		}
+	}
+}
+
+// quarantinePluginInstance disables an instance that exceeded a threshold,
+// records why on the instance, and publishes a PLUGIN_QUARANTINED event so
+// operators can be notified through webhooks.
+func (pm *PluginManager) quarantinePluginInstance(instance *PluginInstance, reason string) {
+	log.Warn().
+		Str("serverID", instance.ServerID.String()).
+		Str("instanceID", instance.ID.String()).
+		Str("pluginID", instance.PluginID).
+		Str("reason", reason).
+		Msg("Quarantining plugin instance that exceeded its resource limits")
+
+	if err := pm.DisablePluginInstance(instance.ServerID, instance.ID); err != nil {
+		log.Error().
+			Err(err).
+			Str("serverID", instance.ServerID.String()).
+			Str("instanceID", instance.ID.String()).
+			Msg("Failed to disable quarantined plugin instance")
+	}
+
+	instance.mu.Lock()
+	instance.LastError = "quarantined: " + reason
+	instance.mu.Unlock()
+
+	if pm.eventManager == nil {
+		return
+	}
+	pm.eventManager.PublishEvent(instance.ServerID, &event_manager.PluginQuarantinedEventData{
+		PluginInstanceID: instance.ID.String(),
+		PluginID:         instance.PluginID,
+		PluginName:       instance.PluginName,
+		Reason:           reason,
+	}, nil)
+}
@@ -0,0 +1,203 @@
+package plugin_manager
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
+)
+
+func TestPluginResourceUsageQuarantinesAfterStrikes(t *testing.T) {
+	t.Parallel()
+
+	usage := newPluginResourceUsage()
+	thresholds := quarantineThresholds{maxRconCommandsPerMinute: 10, strikes: 2}
+	start := time.Now()
+	sendRcon := func(n int) {
+		for i := 0; i < n; i++ {
+			usage.recordHostAPICall("RconBroadcast", nil)
+		}
+	}
+
+	if reason := usage.sample(start, 0, 0, 0, false, thresholds); reason != "" {
+		t.Fatalf("baseline sample reason = %q, want none", reason)
+	}
+	sendRcon(20)
+	if reason := usage.sample(start.Add(time.Minute), 0, 0, 0, false, thresholds); reason != "" {
+		t.Fatalf("first strike reason = %q, want none", reason)
+	}
+	sendRcon(20)
+	reason := usage.sample(start.Add(2*time.Minute), 0, 0, 0, false, thresholds)
+	if !strings.Contains(reason, "rcon commands/min") {
+		t.Fatalf("second strike reason = %q, want the rcon rate", reason)
+	}
+
+	sendRcon(20)
+	if again := usage.sample(start.Add(3*time.Minute), 0, 0, 0, false, thresholds); again != "" {
+		t.Fatalf("sample after quarantine reason = %q, want none", again)
+	}
+	metrics := usage.snapshot()
+	if metrics.Quarantine == nil || metrics.Quarantine.Reason != reason {
+		t.Fatalf("Quarantine = %+v, want reason %q", metrics.Quarantine, reason)
+	}
+	if metrics.RconCommands != 60 || metrics.HostAPICalls["RconBroadcast"] != 60 {
+		t.Fatalf("RconCommands = %d, HostAPICalls = %v, want 60", metrics.RconCommands, metrics.HostAPICalls)
+	}
+
+	usage.clearQuarantine()
+	if usage.quarantined() != nil {
+		t.Fatal("quarantined() after clearQuarantine != nil")
+	}
+}
+
+func TestPluginResourceUsageStrikesResetBelowThreshold(t *testing.T) {
+	t.Parallel()
+
+	usage := newPluginResourceUsage()
+	thresholds := quarantineThresholds{maxRSSBytes: 100 << 20, strikes: 2}
+	start := time.Now()
+
+	samples := []uint64{200 << 20, 50 << 20, 200 << 20}
+	for i, rss := range samples {
+		if reason := usage.sample(start.Add(time.Duration(i)*time.Minute), 42, 0, rss, true, thresholds); reason != "" {
+			t.Fatalf("sample %d reason = %q, want none", i, reason)
+		}
+	}
+	if reason := usage.sample(start.Add(3*time.Minute), 42, 0, 200<<20, true, thresholds); !strings.Contains(reason, "rss") {
+		t.Fatalf("consecutive strike reason = %q, want the rss limit", reason)
+	}
+}
+
+func TestPluginResourceUsageCPUPercent(t *testing.T) {
+	t.Parallel()
+
+	usage := newPluginResourceUsage()
+	start := time.Now()
+	usage.sample(start, 42, time.Second, 1<<20, true, quarantineThresholds{strikes: 1})
+	usage.sample(start.Add(2*time.Second), 42, 2*time.Second, 1<<20, true, quarantineThresholds{strikes: 1})
+
+	process := usage.snapshot().Process
+	if process == nil || process.PID != 42 || process.CPUPercent != 50 {
+		t.Fatalf("Process = %+v, want pid 42 at 50%% cpu", process)
+	}
+
+	// A new process starts a new CPU baseline.
+	usage.sample(start.Add(4*time.Second), 43, 10*time.Second, 1<<20, true, quarantineThresholds{strikes: 1})
+	if process := usage.snapshot().Process; process.CPUPercent != 0 {
+		t.Fatalf("CPUPercent after the PID changed = %v, want 0", process.CPUPercent)
+	}
+}
+
+func TestPluginResourceUsagePanicLimitCountsSinceEnable(t *testing.T) {
+	t.Parallel()
+
+	usage := newPluginResourceUsage()
+	thresholds := quarantineThresholds{maxPanics: 2, strikes: 3}
+	start := time.Now()
+
+	usage.recordPanic()
+	usage.recordPanic()
+	if reason := usage.sample(start, 0, 0, 0, false, thresholds); !strings.Contains(reason, "panics") {
+		t.Fatalf("reason = %q, want the panic limit without waiting for strikes", reason)
+	}
+
+	usage.clearQuarantine()
+	usage.recordPanic()
+	if reason := usage.sample(start.Add(time.Minute), 0, 0, 0, false, thresholds); reason != "" {
+		t.Fatalf("reason after re-enable = %q, want panics before the enable ignored", reason)
+	}
+}
+
+func TestInstrumentHostAPIServiceCountsCalls(t *testing.T) {
+	t.Parallel()
+
+	failure := errors.New("denied")
+	desc := &grpc.ServiceDesc{
+		ServiceName: "test.HostAPI",
+		Methods: []grpc.MethodDesc{
+			{MethodName: "LogInfo", Handler: func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+				return "ok", nil
+			}},
+			{MethodName: "RconKickPlayer", Handler: func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+				return nil, failure
+			}},
+		},
+	}
+	usage := newPluginResourceUsage()
+	instrumented := instrumentHostAPIService(desc, usage)
+	if instrumented == desc {
+		t.Fatal("instrumentHostAPIService() returned the original description")
+	}
+
+	for _, method := range instrumented.Methods {
+		if _, err := method.Handler(nil, context.Background(), nil, nil); method.MethodName == "RconKickPlayer" && !errors.Is(err, failure) {
+			t.Fatalf("%s error = %v, want the handler's error", method.MethodName, err)
+		}
+	}
+
+	metrics := usage.snapshot()
+	if metrics.HostAPICalls["LogInfo"] != 1 || metrics.HostAPICalls["RconKickPlayer"] != 1 {
+		t.Fatalf("HostAPICalls = %v, want one call each", metrics.HostAPICalls)
+	}
+	if metrics.HostAPIErrors != 1 || metrics.RconCommands != 0 {
+		t.Fatalf("HostAPIErrors = %d, RconCommands = %d, want 1 and 0", metrics.HostAPIErrors, metrics.RconCommands)
+	}
+	if instrumentHostAPIService(desc, nil) != desc {
+		t.Fatal("instrumentHostAPIService(nil usage) should return the original description")
+	}
+}
+
+func TestQuarantinePluginInstanceDisablesAndNotifies(t *testing.T) {
+	t.Parallel()
+
+	db := openTestSQLDB(t, &testSQLDriver{
+		execContext: func(string, []driver.NamedValue) (driver.Result, error) {
+			return driver.RowsAffected(1), nil
+		},
+	})
+	events := event_manager.NewEventManager(t.Context(), 10)
+	subscriber := events.Subscribe(event_manager.EventFilter{
+		Types: []event_manager.EventType{event_manager.EventTypePluginQuarantined},
+	}, nil, 1)
+
+	serverID := uuid.New()
+	instance := &PluginInstance{
+		ID:       uuid.New(),
+		ServerID: serverID,
+		PluginID: "com.example.noisy",
+		Status:   PluginStatusRunning,
+		Enabled:  true,
+		Plugin:   &noopPlugin{},
+	}
+	pm := &PluginManager{
+		db:           db,
+		eventManager: events,
+		plugins:      map[uuid.UUID]map[uuid.UUID]*PluginInstance{serverID: {instance.ID: instance}},
+	}
+
+	pm.quarantinePluginInstance(instance, "cpu 250.0% exceeds 100.0%")
+
+	if instance.Enabled || instance.getStatus() != PluginStatusDisabled {
+		t.Fatalf("Enabled = %v, Status = %s, want a disabled instance", instance.Enabled, instance.getStatus())
+	}
+	if got := instance.getError(); got != "quarantined: cpu 250.0% exceeds 100.0%" {
+		t.Fatalf("LastError = %q, want the quarantine reason", got)
+	}
+
+	select {
+	case event := <-subscriber.Channel:
+		data, ok := event.Data.(*event_manager.PluginQuarantinedEventData)
+		if !ok || event.ServerID != serverID || data.PluginInstanceID != instance.ID.String() {
+			t.Fatalf("event = %+v, want a PLUGIN_QUARANTINED event for the instance", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no PLUGIN_QUARANTINED event published")
+	}
+}
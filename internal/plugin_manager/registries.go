@@ -31,6 +31,10 @@ func (r *pluginRegistry) RegisterPlugin(definition PluginDefinition) error {
 		return fmt.Errorf("plugin %s must have a CreateInstance function", definition.ID)
 	}
 
+	if err := validatePluginHTTPSurface(definition); err != nil {
+		return err
+	}
+
 	if definition.Source == "" {
 		definition.Source = PluginSourceBundled
 	}
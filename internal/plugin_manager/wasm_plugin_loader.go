@@ -0,0 +1,405 @@
+package plugin_manager
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/experimental"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.codycody31.dev/squad-aegis/internal/shared/config"
+	"go.codycody31.dev/squad-aegis/pkg/pluginrpc"
+)
+
+const (
+	defaultWasmMemoryLimitMB = 128
+	defaultWasmFuel          = 200_000_000
+	wasmPageSize             = 64 * 1024
+
+	// wasmInitTimeout bounds a module's _initialize. Fuel only counts
+	// function calls, so without it a loop in init would run forever.
+	wasmInitTimeout = 30 * time.Second
+)
+
+// wasmPluginModuleLauncher is the WASM counterpart of
+// nativePluginSubprocessLauncher. Tests override it to avoid compiling
+// real modules.
+var wasmPluginModuleLauncher = launchWasmPluginModule
+
+// wasmCompilationCache shares compiled code between the instances of a
+// module, so only the first instance of a plugin pays for compilation.
+var wasmCompilationCache = wazero.NewCompilationCache()
+
+var (
+	errWasmFuelExhausted = errors.New("wasm plugin ran out of fuel")
+	errWasmModuleClosed  = errors.New("wasm plugin module is closed")
+)
+
+// isWasmTarget reports whether a manifest target is a WASM module rather
+// than a native executable.
+func isWasmTarget(target PluginPackageTarget) bool {
+	return target.TargetOS == pluginrpc.WasmTargetOS && target.TargetArch == pluginrpc.WasmTargetArch
+}
+
+func wasmMemoryLimitPages() uint32 {
+	limitMB := defaultWasmMemoryLimitMB
+	if config.Config != nil && config.Config.Plugins.WasmMemoryLimitMB > 0 {
+		limitMB = config.Config.Plugins.WasmMemoryLimitMB
+	}
+	// WASM memories are capped at 65536 pages (4 GiB).
+	pages := uint64(limitMB) * 1024 * 1024 / wasmPageSize
+	if pages > 65536 {
+		pages = 65536
+	}
+	return uint32(pages)
+}
+
+func wasmFuelLimit() int64 {
+	if config.Config == nil {
+		return defaultWasmFuel
+	}
+	return config.Config.Plugins.WasmFuel
+}
+
+// wasmModule is one instance of a WASM plugin. A module runs one call at a
+// time, so calls from the host are serialized.
+type wasmModule struct {
+	pluginID string
+	runtime  wazero.Runtime
+	module   api.Module
+	alloc    api.Function
+	free     api.Function
+	call     api.Function
+	fuel     int64
+
+	mu sync.Mutex
+	// reply holds the envelope of the last host_call until the guest
+	// copies it with host_reply. Host functions run inside a call, under mu.
+	reply []byte
+
+	host      hostAPIRoute
+	closed    atomic.Bool
+	closeOnce sync.Once
+}
+
+// launchWasmPluginModule verifies a WASM plugin module's checksum, then
+// compiles and instantiates it. The returned handle drives the module's
+// Plugin service through its exports.
+func launchWasmPluginModule(runtimePath, expectedSHA256 string) (*pluginSubprocessHandle, error) {
+	wasmBytes, err := readVerifiedWasmModule(runtimePath, expectedSHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	module, err := newWasmModule(context.Background(), filepath.Base(runtimePath), wasmBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &pluginSubprocessHandle{
+		wasm:      module,
+		hostRoute: &module.host,
+		rpc:       pluginrpc.NewWasmPluginClient(pluginrpc.NewWasmConn(module.invoke)),
+	}, nil
+}
+
+// readVerifiedWasmModule reads a module with O_NOFOLLOW and checks its
+// SHA-256. The verified bytes are compiled directly, so the file cannot
+// change between verification and use.
+func readVerifiedWasmModule(runtimePath, expectedSHA256 string) ([]byte, error) {
+	expected := strings.TrimSpace(expectedSHA256)
+	if expected == "" {
+		return nil, fmt.Errorf("refusing to load wasm plugin: no expected checksum configured")
+	}
+	file, err := openNoFollow(runtimePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	maxBytes := pluginMaxUploadSize()
+	wasmBytes, err := io.ReadAll(io.LimitReader(file, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wasm plugin module: %w", err)
+	}
+	if int64(len(wasmBytes)) > maxBytes {
+		return nil, fmt.Errorf("wasm plugin module exceeds maximum allowed size of %d bytes", maxBytes)
+	}
+	actual := fmt.Sprintf("%x", sha256.Sum256(wasmBytes))
+	if !strings.EqualFold(expected, actual) {
+		return nil, fmt.Errorf("wasm plugin module checksum mismatch: expected %s, got %s", expected, actual)
+	}
+	return wasmBytes, nil
+}
+
+// newWasmModule instantiates a module in its own runtime. The module gets
+// WASI clocks and randomness but no filesystem, environment or arguments,
+// and reaches the host only through the aegis imports.
+func newWasmModule(ctx context.Context, name string, wasmBytes []byte) (*wasmModule, error) {
+	runtimeConfig := wazero.NewRuntimeConfig().
+		WithCloseOnContextDone(true).
+		WithMemoryLimitPages(wasmMemoryLimitPages()).
+		WithCompilationCache(wasmCompilationCache)
+	r := wazero.NewRuntimeWithConfig(ctx, runtimeConfig)
+
+	m := &wasmModule{pluginID: name, runtime: r, fuel: wasmFuelLimit()}
+	fail := func(err error) (*wasmModule, error) {
+		_ = r.Close(ctx)
+		return nil, err
+	}
+
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, r); err != nil {
+		return fail(fmt.Errorf("failed to instantiate wasi: %w", err))
+	}
+	i32 := api.ValueTypeI32
+	_, err := r.NewHostModuleBuilder(pluginrpc.WasmHostModule).
+		NewFunctionBuilder().
+		WithGoModuleFunction(api.GoModuleFunc(m.hostCall), []api.ValueType{i32, i32, i32, i32, i32, i32}, []api.ValueType{i32}).
+		Export(pluginrpc.WasmHostCallFunc).
+		NewFunctionBuilder().
+		WithGoModuleFunction(api.GoModuleFunc(m.hostReply), []api.ValueType{i32}, nil).
+		Export(pluginrpc.WasmHostReplyFunc).
+		Instantiate(ctx)
+	if err != nil {
+		return fail(fmt.Errorf("failed to instantiate host module: %w", err))
+	}
+
+	compiled, err := r.CompileModule(experimental.WithFunctionListenerFactory(ctx, wasmFuelMeter{}), wasmBytes)
+	if err != nil {
+		return fail(fmt.Errorf("failed to compile wasm plugin module: %w", err))
+	}
+	exports := compiled.ExportedFunctions()
+	for _, export := range []string{pluginrpc.WasmAllocFunc, pluginrpc.WasmFreeFunc, pluginrpc.WasmCallFunc} {
+		if _, ok := exports[export]; !ok {
+			return fail(fmt.Errorf("wasm plugin module does not export %s; build it with pluginrpc and -buildmode=c-shared", export))
+		}
+	}
+
+	output := &wasmLogWriter{pluginID: name}
+	moduleConfig := wazero.NewModuleConfig().
+		WithName(name).
+		WithStartFunctions("_initialize").
+		WithSysWalltime().
+		WithSysNanotime().
+		WithSysNanosleep().
+		WithRandSource(rand.Reader).
+		WithStdout(output).
+		WithStderr(output)
+
+	initCtx, cancelInit := context.WithTimeout(ctx, wasmInitTimeout)
+	initCtx, done := m.meter(initCtx)
+	module, err := r.InstantiateModule(initCtx, compiled, moduleConfig)
+	done()
+	if err != nil {
+		err = m.callError(initCtx, err)
+		cancelInit()
+		return fail(fmt.Errorf("failed to initialize wasm plugin module: %w", err))
+	}
+	cancelInit()
+
+	m.module = module
+	m.alloc = module.ExportedFunction(pluginrpc.WasmAllocFunc)
+	m.free = module.ExportedFunction(pluginrpc.WasmFreeFunc)
+	m.call = module.ExportedFunction(pluginrpc.WasmCallFunc)
+	return m, nil
+}
+
+// invoke runs one Plugin service method in the module. It implements
+// pluginrpc.WasmInvoker.
+func (m *wasmModule) invoke(ctx context.Context, method, _ string, payload []byte) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.exited() {
+		return nil, errWasmModuleClosed
+	}
+
+	ctx, done := m.meter(ctx)
+	defer done()
+
+	methodPtr, err := m.write(ctx, []byte(method))
+	if err != nil {
+		return nil, m.callError(ctx, err)
+	}
+	defer m.release(ctx, methodPtr)
+	payloadPtr, err := m.write(ctx, payload)
+	if err != nil {
+		return nil, m.callError(ctx, err)
+	}
+	defer m.release(ctx, payloadPtr)
+
+	results, err := m.call.Call(ctx, uint64(methodPtr), uint64(len(method)), uint64(payloadPtr), uint64(len(payload)))
+	if err != nil {
+		return nil, m.callError(ctx, err)
+	}
+	replyPtr, replySize := uint32(results[0]>>32), uint32(results[0])
+	reply, ok := m.module.Memory().Read(replyPtr, replySize)
+	if !ok {
+		return nil, fmt.Errorf("wasm plugin returned an out of bounds reply")
+	}
+	reply = bytes.Clone(reply)
+	m.release(ctx, replyPtr)
+	return reply, nil
+}
+
+// write copies data into a buffer allocated by the module.
+func (m *wasmModule) write(ctx context.Context, data []byte) (uint32, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+	results, err := m.alloc.Call(ctx, uint64(len(data)))
+	if err != nil {
+		return 0, err
+	}
+	ptr := uint32(results[0])
+	if !m.module.Memory().Write(ptr, data) {
+		return 0, fmt.Errorf("wasm plugin allocated an out of bounds buffer")
+	}
+	return ptr, nil
+}
+
+func (m *wasmModule) release(ctx context.Context, ptr uint32) {
+	if ptr == 0 || m.module.IsClosed() {
+		return
+	}
+	if _, err := m.free.Call(ctx, uint64(ptr)); err != nil {
+		log.Debug().Err(err).Str("plugin", m.pluginID).Msg("Failed to free wasm plugin buffer")
+	}
+}
+
+// callError explains a failed call. A call that runs out of fuel, times out
+// or traps leaves the module closed, and the instance must be restarted.
+func (m *wasmModule) callError(ctx context.Context, err error) error {
+	if errors.Is(context.Cause(ctx), errWasmFuelExhausted) {
+		m.closed.Store(true)
+		return fmt.Errorf("%w: more than %d guest function calls", errWasmFuelExhausted, m.fuel)
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		m.closed.Store(true)
+		return fmt.Errorf("wasm plugin call timed out: %w", err)
+	}
+	if m.module == nil || m.module.IsClosed() {
+		m.closed.Store(true)
+	}
+	return err
+}
+
+// hostCall implements the host_call import: it runs one HostAPI method and
+// returns the reply size for host_reply.
+func (m *wasmModule) hostCall(ctx context.Context, mod api.Module, stack []uint64) {
+	memory := mod.Memory()
+	method, okMethod := memory.Read(uint32(stack[0]), uint32(stack[1]))
+	serverID, okServer := memory.Read(uint32(stack[2]), uint32(stack[3]))
+	payload, okPayload := memory.Read(uint32(stack[4]), uint32(stack[5]))
+
+	var reply []byte
+	if !okMethod || !okServer || !okPayload {
+		reply = pluginrpc.EncodeWasmReply(nil, status.Error(codes.InvalidArgument, "host call arguments are out of bounds"))
+	} else {
+		reply, _ = m.host.invoke(ctx, string(method), string(serverID), bytes.Clone(payload))
+	}
+	m.reply = reply
+	stack[0] = uint64(len(reply))
+}
+
+// hostReply implements the host_reply import.
+func (m *wasmModule) hostReply(_ context.Context, mod api.Module, stack []uint64) {
+	reply := m.reply
+	m.reply = nil
+	if len(reply) > 0 && !mod.Memory().Write(uint32(stack[0]), reply) {
+		panic("host_reply buffer is out of bounds")
+	}
+}
+
+// exited reports whether the module has stopped, after a trap, running out
+// of fuel or Close.
+func (m *wasmModule) exited() bool {
+	return m.closed.Load() || (m.module != nil && m.module.IsClosed())
+}
+
+// Close stops the module, interrupting a call that is still running. Safe
+// to call multiple times.
+func (m *wasmModule) Close() {
+	m.closed.Store(true)
+	m.closeOnce.Do(func() {
+		if err := m.runtime.Close(context.Background()); err != nil {
+			log.Debug().Err(err).Str("plugin", m.pluginID).Msg("Failed to close wasm plugin runtime")
+		}
+	})
+}
+
+// wasmFuel is the budget of one call into a module.
+type wasmFuel struct {
+	remaining int64
+	cancel    context.CancelCauseFunc
+}
+
+type wasmFuelKey struct{}
+
+// meter attaches the module's fuel budget to a call. When the budget runs
+// out the call's context is cancelled, which stops the module.
+//
+// Fuel is charged per guest function call, not per instruction: wazero has no
+// hook at loop back-edges, so a loop that makes no calls burns no fuel. Such a
+// loop is only stopped by the deadline on the call's context, which is the
+// host's per-call timeout or wasmInitTimeout.
+func (m *wasmModule) meter(ctx context.Context) (context.Context, func()) {
+	if m.fuel <= 0 {
+		return ctx, func() {}
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	fuel := &wasmFuel{remaining: m.fuel, cancel: cancel}
+	return context.WithValue(ctx, wasmFuelKey{}, fuel), func() { cancel(nil) }
+}
+
+// wasmFuelMeter charges one unit of fuel per guest function call. It bounds
+// runaway recursion and call-heavy work, not CPU time.
+type wasmFuelMeter struct{}
+
+func (wasmFuelMeter) NewFunctionListener(definition api.FunctionDefinition) experimental.FunctionListener {
+	if _, _, isImport := definition.Import(); isImport {
+		return nil
+	}
+	return wasmFuelMeter{}
+}
+
+func (wasmFuelMeter) Before(ctx context.Context, _ api.Module, _ api.FunctionDefinition, _ []uint64, _ experimental.StackIterator) {
+	fuel, ok := ctx.Value(wasmFuelKey{}).(*wasmFuel)
+	if !ok {
+		return
+	}
+	fuel.remaining--
+	if fuel.remaining == 0 {
+		fuel.cancel(errWasmFuelExhausted)
+	}
+}
+
+func (wasmFuelMeter) After(context.Context, api.Module, api.FunctionDefinition, []uint64) {}
+
+func (wasmFuelMeter) Abort(context.Context, api.Module, api.FunctionDefinition, error) {}
+
+// wasmLogWriter forwards a module's stdout and stderr to the host log.
+type wasmLogWriter struct {
+	pluginID string
+}
+
+func (w *wasmLogWriter) Write(p []byte) (int, error) {
+	if line := strings.TrimSpace(string(p)); line != "" {
+		log.Info().Str("plugin", w.pluginID).Msg(line)
+	}
+	return len(p), nil
+}
@@ -0,0 +1,206 @@
+package plugin_manager
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
+	"go.codycody31.dev/squad-aegis/internal/shared/config"
+	"go.codycody31.dev/squad-aegis/pkg/pluginrpc"
+)
+
+// buildExampleWasmModule compiles a Go package as a WASI reactor module in
+// t.TempDir(). Returns the path + expected SHA-256.
+func buildExampleWasmModule(t *testing.T, pkg string) (path, sha string) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("wasm integration tests are skipped in short mode")
+	}
+	outPath := filepath.Join(t.TempDir(), filepath.Base(pkg)+".wasm")
+	cmd := exec.Command("go", "build", "-buildmode=c-shared", "-o", outPath, "./"+pkg)
+	cmd.Env = append(os.Environ(), "GOOS="+pluginrpc.WasmTargetOS, "GOARCH="+pluginrpc.WasmTargetArch)
+	if cwd, err := os.Getwd(); err == nil {
+		for d := cwd; d != "/"; d = filepath.Dir(d) {
+			if _, err := os.Stat(filepath.Join(d, "go.mod")); err == nil {
+				cmd.Dir = d
+				break
+			}
+		}
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go build %s: %v\n%s", pkg, err, out)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read built module: %v", err)
+	}
+	return outPath, fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+func wasmHelloTestManifest() PluginPackageManifest {
+	return PluginPackageManifest{
+		PluginID: "com.squad-aegis.plugins.examples.wasm-hello",
+		Name:     "WASM Hello Example",
+		Version:  "0.1.0",
+		Authors:  []ManifestAuthor{{Name: "Squad Aegis"}},
+	}
+}
+
+func wasmHelloTestTarget() PluginPackageTarget {
+	return PluginPackageTarget{
+		MinHostAPIVersion: NativePluginHostAPIVersion,
+		RequiredCapabilities: []string{
+			NativePluginCapabilityAPIRCON,
+			NativePluginCapabilityEventsRCON,
+		},
+		TargetOS:   pluginrpc.WasmTargetOS,
+		TargetArch: pluginrpc.WasmTargetArch,
+	}
+}
+
+func TestWasmPluginLifecycleWithHostAPICallbacks(t *testing.T) {
+	path, sha := buildExampleWasmModule(t, "examples/wasm-plugin-hello")
+
+	def, err := peekNativePluginDefinition(path, sha, wasmHelloTestManifest(), wasmHelloTestTarget())
+	if err != nil {
+		t.Fatalf("peekNativePluginDefinition() error = %v", err)
+	}
+	if len(def.Events) == 0 {
+		t.Fatal("def.Events is empty; expected RCON_CHAT_MESSAGE from the module")
+	}
+
+	instance := def.CreateInstance()
+	rcon := &fakeRconAPI{}
+	logAPI := &fakeLogAPI{}
+	serverID := uuid.New()
+	apis := &PluginAPIs{
+		ServerAPI: &fakeServerAPI{id: serverID},
+		RconAPI:   rcon,
+		LogAPI:    logAPI,
+	}
+	if err := instance.Initialize(map[string]interface{}{"trigger": "!hello", "response": "hi"}, apis); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	t.Cleanup(func() { _ = instance.Stop() })
+
+	chatRaw, _ := json.Marshal(map[string]interface{}{
+		"eos_id":      "0002a10186d9414496bf20d22d3860ba",
+		"player_name": "Alice",
+		"message":     "!hello",
+	})
+	event := &PluginEvent{
+		ID:        uuid.New(),
+		ServerID:  serverID,
+		Source:    EventSourceRCON,
+		Type:      string(event_manager.EventTypeRconChatMessage),
+		Data:      json.RawMessage(chatRaw),
+		Timestamp: time.Now(),
+	}
+	if err := instance.HandleEvent(event); err != nil {
+		t.Fatalf("HandleEvent() error = %v", err)
+	}
+	if got := rcon.lastMessage.Load(); got != "hi" {
+		t.Fatalf("RconAPI.SendWarningToPlayer message = %v, want hi", got)
+	}
+	if logAPI.infoCount.Load() != 1 {
+		t.Fatalf("LogAPI.Info count = %d, want 1", logAPI.infoCount.Load())
+	}
+	if got := instance.GetConfig()["response"]; got != "hi" {
+		t.Fatalf("GetConfig()[response] = %v, want hi", got)
+	}
+
+	if err := instance.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if err := instance.HandleEvent(event); err == nil {
+		t.Fatal("HandleEvent() after Stop error = nil, want error")
+	}
+}
+
+func TestWasmPluginRejectsBadChecksum(t *testing.T) {
+	path, _ := buildExampleWasmModule(t, "examples/wasm-plugin-hello")
+
+	_, err := peekNativePluginDefinition(path, strings.Repeat("0", 64), wasmHelloTestManifest(), wasmHelloTestTarget())
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("peekNativePluginDefinition() error = %v, want checksum mismatch", err)
+	}
+}
+
+func TestWasmPluginStopsWhenFuelRunsOut(t *testing.T) {
+	path, sha := buildExampleWasmModule(t, "examples/wasm-plugin-hello")
+
+	prev := config.Config
+	cfg := config.Struct{}
+	cfg.Plugins.WasmFuel = 1000
+	config.Config = &cfg
+	t.Cleanup(func() { config.Config = prev })
+
+	_, err := wasmPluginModuleLauncher(path, sha)
+	if !errors.Is(err, errWasmFuelExhausted) {
+		t.Fatalf("launch error = %v, want %v", err, errWasmFuelExhausted)
+	}
+}
+
+func TestSelectedManifestTargetFallsBackToWasm(t *testing.T) {
+	native := PluginPackageTarget{
+		MinHostAPIVersion: NativePluginHostAPIVersion,
+		TargetOS:          runtime.GOOS,
+		TargetArch:        runtime.GOARCH,
+		LibraryPath:       "bin/plugin",
+	}
+	wasm := PluginPackageTarget{
+		MinHostAPIVersion: NativePluginHostAPIVersion,
+		TargetOS:          pluginrpc.WasmTargetOS,
+		TargetArch:        pluginrpc.WasmTargetArch,
+		LibraryPath:       "bin/plugin.wasm",
+	}
+
+	got, err := selectedManifestTarget(PluginPackageManifest{Targets: []PluginPackageTarget{wasm, native}})
+	if err != nil {
+		t.Fatalf("selectedManifestTarget() error = %v", err)
+	}
+	if got.LibraryPath != native.LibraryPath {
+		t.Fatalf("selectedManifestTarget() = %q, want the native target", got.LibraryPath)
+	}
+
+	got, err = selectedManifestTarget(PluginPackageManifest{Targets: []PluginPackageTarget{wasm}})
+	if err != nil {
+		t.Fatalf("selectedManifestTarget() wasm only error = %v", err)
+	}
+	if !isWasmTarget(got) {
+		t.Fatalf("selectedManifestTarget() = %s/%s, want the wasm target", got.TargetOS, got.TargetArch)
+	}
+	if err := validatePluginCompatibility(PluginPackageManifest{}, got); err != nil {
+		t.Fatalf("validatePluginCompatibility() wasm error = %v", err)
+	}
+
+	// Connectors are subprocesses only and never pick a wasm target.
+	if _, err := selectedConnectorManifestTarget(ConnectorPackageManifest{Targets: []PluginPackageTarget{wasm}}); err == nil {
+		t.Fatal("selectedConnectorManifestTarget() wasm only error = nil, want error")
+	}
+
+	version := PluginCatalogVersion{
+		MinHostAPIVersion: NativePluginHostAPIVersion,
+		Downloads: []PluginCatalogDownload{
+			{TargetOS: pluginrpc.WasmTargetOS, TargetArch: pluginrpc.WasmTargetArch, URL: "plugin-wasm.zip"},
+		},
+	}
+	if download, ok := version.hostDownload(); !ok || download.URL != "plugin-wasm.zip" {
+		t.Fatalf("hostDownload() = %+v, %v, want the wasm download", download, ok)
+	}
+	version.Downloads = append(version.Downloads, PluginCatalogDownload{TargetOS: runtime.GOOS, TargetArch: runtime.GOARCH, URL: "plugin-native.zip"})
+	if download, ok := version.hostDownload(); !ok || download.URL != "plugin-native.zip" {
+		t.Fatalf("hostDownload() = %+v, %v, want the native download", download, ok)
+	}
+}
@@ -15,6 +15,13 @@ const (
 	ManifestSignatureFile     = "manifest.sig"
 	ManifestPublicKeyFile     = "manifest.pub"
 	SignedManifestPayloadFile = "manifest.signed.json"
+
+	// A plugin catalog is signed like a bundle manifest, with index.json in
+	// place of manifest.json.
+	CatalogIndexFile         = "index.json"
+	CatalogSignedPayloadFile = "index.signed.json"
+	CatalogSignatureFile     = "index.sig"
+	CatalogPublicKeyFile     = "index.pub"
 )
 
 // SignedManifestPayload is the canonical wrapper that the signature covers.
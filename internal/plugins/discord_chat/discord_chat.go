@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"go.codycody31.dev/squad-aegis/internal/event_manager"
 	"go.codycody31.dev/squad-aegis/internal/plugin_manager"
 	"go.codycody31.dev/squad-aegis/internal/shared/plug_config_schema"
@@ -35,6 +36,9 @@ func Define() plugin_manager.PluginDefinition {
 		Description:        "The Discord Chat plugin will log in-game chat to a Discord channel.",
 		RequiredConnectors: []string{"discord"},
 
+		// A global instance relays chat from several servers into one channel.
+		AllowGlobalInstances: true,
+
 		ConfigSchema: plug_config_schema.ConfigSchema{
 			Fields: []plug_config_schema.ConfigField{
 				plug_config_schema.NewStringField(
@@ -231,7 +235,7 @@ func (p *DiscordChatPlugin) handleChatMessage(rawEvent *plugin_manager.PluginEve
 
 	// Send Discord embed in a goroutine to avoid blocking
 	go func() {
-		if err := p.sendChatEmbed(event); err != nil {
+		if err := p.sendChatEmbed(rawEvent.ServerID, event); err != nil {
 			p.apis.LogAPI.Error("Failed to send Discord embed for chat message", err, map[string]interface{}{
 				"player_name": event.PlayerName,
 				"chat_type":   event.ChatType,
@@ -243,8 +247,20 @@ func (p *DiscordChatPlugin) handleChatMessage(rawEvent *plugin_manager.PluginEve
 	return nil
 }
 
+// serverAPI returns the ServerAPI for the server a chat message came from.
+// Global instances resolve it through their server scope.
+func (p *DiscordChatPlugin) serverAPI(serverID uuid.UUID) (plugin_manager.ServerAPI, error) {
+	if p.apis.ServerScopeAPI != nil {
+		return p.apis.ServerScopeAPI.ServerAPI(serverID)
+	}
+	if p.apis.ServerAPI == nil {
+		return nil, fmt.Errorf("server api is unavailable")
+	}
+	return p.apis.ServerAPI, nil
+}
+
 // sendChatEmbed sends the chat message as a Discord embed
-func (p *DiscordChatPlugin) sendChatEmbed(event *event_manager.RconChatMessageData) error {
+func (p *DiscordChatPlugin) sendChatEmbed(serverID uuid.UUID, event *event_manager.RconChatMessageData) error {
 	channelID := p.getStringConfig("channel_id")
 	if channelID == "" {
 		return fmt.Errorf("channel_id not configured")
@@ -254,18 +270,22 @@ func (p *DiscordChatPlugin) sendChatEmbed(event *event_manager.RconChatMessageDa
 	var teamInfo string = "Unknown"
 	var squadInfo string = "Unknown"
 
-	// Try to get current player list to find team/squad info
-	if players, err := p.apis.ServerAPI.GetPlayers(); err == nil {
-		playerID := event.PreferredPlayerID()
-		for _, player := range players {
-			if player.MatchesPlayerID(playerID) {
-				teamInfo = fmt.Sprintf("%d", player.TeamID)
-				if player.SquadID > 0 {
-					squadInfo = fmt.Sprintf("%d", player.SquadID)
-				} else {
-					squadInfo = "Unassigned"
+	// Try to get current player list to find team/squad info. Without a
+	// server API the message is still relayed with them left as Unknown.
+	serverAPI, _ := p.serverAPI(serverID)
+	if serverAPI != nil {
+		if players, err := serverAPI.GetPlayers(); err == nil {
+			playerID := event.PreferredPlayerID()
+			for _, player := range players {
+				if player.MatchesPlayerID(playerID) {
+					teamInfo = fmt.Sprintf("%d", player.TeamID)
+					if player.SquadID > 0 {
+						squadInfo = fmt.Sprintf("%d", player.SquadID)
+					} else {
+						squadInfo = "Unassigned"
+					}
+					break
 				}
-				break
 			}
 		}
 	}
@@ -321,6 +341,13 @@ func (p *DiscordChatPlugin) sendChatEmbed(event *event_manager.RconChatMessageDa
 		Timestamp: func() *time.Time { t := time.Now(); return &t }(),
 	}
 
+	// Name the originating server when one channel relays several servers
+	if p.apis.ServerScopeAPI != nil && serverAPI != nil {
+		if info, err := serverAPI.GetServerInfo(); err == nil && info.Name != "" {
+			embed.Footer = &plugin_manager.DiscordEmbedFooter{Text: info.Name}
+		}
+	}
+
 	if _, err := p.discordAPI.SendEmbed(channelID, embed); err != nil {
 		return fmt.Errorf("failed to send Discord embed: %w", err)
 	}
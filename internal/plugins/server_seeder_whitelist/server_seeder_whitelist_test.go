@@ -2,13 +2,13 @@ package server_seeder_whitelist
 
 import (
 	"encoding/json"
-	"errors"
 	"strings"
 	"testing"
 	"time"
 
 	"go.codycody31.dev/squad-aegis/internal/plugin_manager"
 	"go.codycody31.dev/squad-aegis/internal/shared/whitelistprogress"
+	"go.codycody31.dev/squad-aegis/pkg/plugintest"
 )
 
 func TestLoadPlayerProgressMigratesLegacySeederState(t *testing.T) {
@@ -25,26 +25,16 @@ func TestLoadPlayerProgressMigratesLegacySeederState(t *testing.T) {
 		t.Fatalf("marshal legacy state: %v", err)
 	}
 
-	db := &fakeDatabaseAPI{
-		data: map[string]string{
-			"player_progress": string(legacyState),
-		},
-	}
+	host := plugintest.NewHost(t)
+	host.Storage.Set("player_progress", string(legacyState))
 
-	plugin := &ServerSeederWhitelistPlugin{
-		playerProgress: make(map[string]*PlayerProgressRecord),
-	}
-
-	err = plugin.Initialize(map[string]interface{}{
+	instance := host.Run(&ServerSeederWhitelistPlugin{}, map[string]interface{}{
 		"hours_to_whitelist": 6,
-	}, &plugin_manager.PluginAPIs{
-		DatabaseAPI: db,
-		LogAPI:      fakeLogAPI{},
 	})
-	if err != nil {
-		t.Fatalf("initialize plugin: %v", err)
-	}
+	plugin := instance.Plugin().(*ServerSeederWhitelistPlugin)
 
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
 	record := plugin.playerProgress["76561198000000001"]
 	if record == nil {
 		t.Fatalf("expected migrated player record")
@@ -58,7 +48,7 @@ func TestLoadPlayerProgressMigratesLegacySeederState(t *testing.T) {
 		t.Fatalf("lifetime seconds = %d, want %d", got, want)
 	}
 
-	migratedRaw, ok := db.data["player_progress"]
+	migratedRaw, ok := host.Storage.Get("player_progress")
 	if !ok {
 		t.Fatalf("expected migrated state to be persisted")
 	}
@@ -74,32 +64,19 @@ func TestLoadPlayerProgressMigratesLegacySeederState(t *testing.T) {
 }
 
 func TestUpdateConfigRemovesManagedRoleWhenThresholdIncreases(t *testing.T) {
-	admins := &fakeAdminAPI{
-		admins: []*plugin_manager.TemporaryAdminInfo{
-			{
-				SteamID:  "76561198000000002",
-				RoleName: "seeder_whitelist",
-			},
-		},
-	}
-
-	plugin := &ServerSeederWhitelistPlugin{
-		playerProgress: make(map[string]*PlayerProgressRecord),
-	}
+	host := plugintest.NewHost(t)
+	host.Admins.Grant(plugin_manager.TemporaryAdminInfo{
+		SteamID:  "76561198000000002",
+		RoleName: "seeder_whitelist",
+	})
 
-	err := plugin.Initialize(map[string]interface{}{
+	instance := host.Run(&ServerSeederWhitelistPlugin{}, map[string]interface{}{
 		"hours_to_whitelist":        6,
 		"auto_add_temporary_admins": true,
-	}, &plugin_manager.PluginAPIs{
-		DatabaseAPI: &fakeDatabaseAPI{data: map[string]string{}},
-		AdminAPI:    admins,
-		RconAPI:     &fakeRconAPI{},
-		LogAPI:      fakeLogAPI{},
 	})
-	if err != nil {
-		t.Fatalf("initialize plugin: %v", err)
-	}
+	plugin := instance.Plugin().(*ServerSeederWhitelistPlugin)
 
+	plugin.mu.Lock()
 	plugin.playerProgress["76561198000000002"] = &PlayerProgressRecord{
 		PlayerID:         "76561198000000002",
 		QualifiedSeconds: whitelistprogress.RequiredSeconds(6),
@@ -107,15 +84,9 @@ func TestUpdateConfigRemovesManagedRoleWhenThresholdIncreases(t *testing.T) {
 		LastEarnedAt:     time.Now(),
 		LastSeenAt:       time.Now(),
 	}
-	plugin.status = plugin_manager.PluginStatusRunning
-	plugin.adminSyncTicker = time.NewTicker(time.Hour)
-	defer func() {
-		if plugin.adminSyncTicker != nil {
-			plugin.adminSyncTicker.Stop()
-		}
-	}()
+	plugin.mu.Unlock()
 
-	err = plugin.UpdateConfig(map[string]interface{}{
+	err := plugin.UpdateConfig(map[string]interface{}{
 		"hours_to_whitelist":        8,
 		"auto_add_temporary_admins": true,
 	})
@@ -123,40 +94,32 @@ func TestUpdateConfigRemovesManagedRoleWhenThresholdIncreases(t *testing.T) {
 		t.Fatalf("update config: %v", err)
 	}
 
-	if len(admins.addCalls) != 0 {
-		t.Fatalf("expected no admin add calls, got %d", len(admins.addCalls))
+	if adds := host.Admins.CallsOf(plugintest.AdminCallAdd); len(adds) != 0 {
+		t.Fatalf("expected no admin add calls, got %d", len(adds))
 	}
 
-	if len(admins.removeRoleCalls) != 1 {
-		t.Fatalf("expected one role removal, got %d", len(admins.removeRoleCalls))
+	removals := host.Admins.CallsOf(plugintest.AdminCallRemoveRole)
+	if len(removals) != 1 {
+		t.Fatalf("expected one role removal, got %d", len(removals))
 	}
 
-	call := admins.removeRoleCalls[0]
-	if call.playerID != "76561198000000002" {
-		t.Fatalf("removed player ID = %q", call.playerID)
+	call := removals[0]
+	if call.PlayerID != "76561198000000002" {
+		t.Fatalf("removed player ID = %q", call.PlayerID)
 	}
-	if call.roleName != "seeder_whitelist" {
-		t.Fatalf("removed role = %q", call.roleName)
+	if call.RoleName != "seeder_whitelist" {
+		t.Fatalf("removed role = %q", call.RoleName)
 	}
 }
 
 func TestSendProgressToPlayerResolvesLegacyEOSRecordAcrossIdentifiers(t *testing.T) {
-	rcon := &fakeRconAPI{}
-	plugin := &ServerSeederWhitelistPlugin{
-		playerProgress: make(map[string]*PlayerProgressRecord),
-	}
-
-	err := plugin.Initialize(map[string]interface{}{
+	host := plugintest.NewHost(t)
+	instance := host.Run(&ServerSeederWhitelistPlugin{}, map[string]interface{}{
 		"hours_to_whitelist": 6,
-	}, &plugin_manager.PluginAPIs{
-		DatabaseAPI: &fakeDatabaseAPI{data: map[string]string{}},
-		RconAPI:     rcon,
-		LogAPI:      fakeLogAPI{},
 	})
-	if err != nil {
-		t.Fatalf("initialize plugin: %v", err)
-	}
+	plugin := instance.Plugin().(*ServerSeederWhitelistPlugin)
 
+	plugin.mu.Lock()
 	plugin.playerProgress["abcdef0123456789abcdef0123456789"] = &PlayerProgressRecord{
 		PlayerID:         "abcdef0123456789abcdef0123456789",
 		EOSID:            "abcdef0123456789abcdef0123456789",
@@ -165,165 +128,18 @@ func TestSendProgressToPlayerResolvesLegacyEOSRecordAcrossIdentifiers(t *testing
 		LastEarnedAt:     time.Now(),
 		LastSeenAt:       time.Now(),
 	}
+	plugin.mu.Unlock()
 
-	err = plugin.sendProgressToPlayer("76561198000000021", "76561198000000021", "ABCDEF0123456789ABCDEF0123456789")
+	err := plugin.sendProgressToPlayer("76561198000000021", "76561198000000021", "ABCDEF0123456789ABCDEF0123456789")
 	if err != nil {
 		t.Fatalf("send progress: %v", err)
 	}
 
-	if len(rcon.warnings) != 1 {
-		t.Fatalf("warning count = %d, want 1", len(rcon.warnings))
-	}
-	if strings.Contains(rcon.warnings[0].message, "No seeding progress found") {
-		t.Fatalf("expected cross-identifier lookup to find progress, got %q", rcon.warnings[0].message)
-	}
-}
-
-type fakeDatabaseAPI struct {
-	data map[string]string
-}
-
-func (f *fakeDatabaseAPI) GetPluginData(key string) (string, error) {
-	value, ok := f.data[key]
-	if !ok {
-		return "", errors.New("key not found")
+	warnings := host.Rcon.CommandsOf(plugintest.CommandWarn)
+	if len(warnings) != 1 {
+		t.Fatalf("warning count = %d, want 1", len(warnings))
 	}
-	return value, nil
-}
-
-func (f *fakeDatabaseAPI) SetPluginData(key string, value string) error {
-	if f.data == nil {
-		f.data = make(map[string]string)
+	if strings.Contains(warnings[0].Message, "No seeding progress found") {
+		t.Fatalf("expected cross-identifier lookup to find progress, got %q", warnings[0].Message)
 	}
-	f.data[key] = value
-	return nil
-}
-
-func (f *fakeDatabaseAPI) DeletePluginData(key string) error {
-	delete(f.data, key)
-	return nil
-}
-
-type fakeAdminAPI struct {
-	admins          []*plugin_manager.TemporaryAdminInfo
-	addCalls        []addAdminCall
-	removeRoleCalls []removeRoleCall
 }
-
-type addAdminCall struct {
-	playerID  string
-	roleName  string
-	notes     string
-	expiresAt *time.Time
-}
-
-type removeRoleCall struct {
-	playerID string
-	roleName string
-	notes    string
-}
-
-func (f *fakeAdminAPI) AddTemporaryAdmin(playerID string, roleName string, notes string, expiresAt *time.Time) error {
-	f.addCalls = append(f.addCalls, addAdminCall{
-		playerID:  playerID,
-		roleName:  roleName,
-		notes:     notes,
-		expiresAt: expiresAt,
-	})
-	return nil
-}
-
-func (f *fakeAdminAPI) RemoveTemporaryAdmin(playerID string, notes string) error {
-	return nil
-}
-
-func (f *fakeAdminAPI) RemoveTemporaryAdminRole(playerID string, roleName string, notes string) error {
-	f.removeRoleCalls = append(f.removeRoleCalls, removeRoleCall{
-		playerID: playerID,
-		roleName: roleName,
-		notes:    notes,
-	})
-	return nil
-}
-
-func (f *fakeAdminAPI) GetPlayerAdminStatus(playerID string) (*plugin_manager.PlayerAdminStatus, error) {
-	return nil, errors.New("not implemented")
-}
-
-func (f *fakeAdminAPI) ListTemporaryAdmins() ([]*plugin_manager.TemporaryAdminInfo, error) {
-	return f.admins, nil
-}
-
-type fakeRconAPI struct {
-	commands []string
-	warnings []warningCall
-}
-
-type warningCall struct {
-	playerID string
-	message  string
-}
-
-func (f *fakeRconAPI) SendCommand(command string) (string, error) {
-	f.commands = append(f.commands, command)
-	return "", nil
-}
-
-func (f *fakeRconAPI) Broadcast(message string) error {
-	return nil
-}
-
-func (f *fakeRconAPI) SendWarningToPlayer(playerID string, message string) error {
-	f.warnings = append(f.warnings, warningCall{
-		playerID: playerID,
-		message:  message,
-	})
-	return nil
-}
-
-func (f *fakeRconAPI) KickPlayer(playerID string, reason string) error {
-	return nil
-}
-
-func (f *fakeRconAPI) BanPlayer(playerID string, reason string, duration time.Duration) error {
-	return nil
-}
-
-func (f *fakeRconAPI) BanWithEvidence(playerID string, reason string, duration time.Duration, eventID string, eventType string) (string, error) {
-	return "", nil
-}
-
-func (f *fakeRconAPI) WarnPlayerWithRule(playerID string, message string, ruleID *string) error {
-	return nil
-}
-
-func (f *fakeRconAPI) KickPlayerWithRule(playerID string, reason string, ruleID *string) error {
-	return nil
-}
-
-func (f *fakeRconAPI) BanPlayerWithRule(playerID string, reason string, duration time.Duration, ruleID *string) error {
-	return nil
-}
-
-func (f *fakeRconAPI) BanWithEvidenceAndRule(playerID string, reason string, duration time.Duration, eventID string, eventType string, ruleID *string) (string, error) {
-	return "", nil
-}
-
-func (f *fakeRconAPI) BanWithEvidenceAndRuleAndMetadata(playerID string, reason string, duration time.Duration, eventID string, eventType string, ruleID *string, metadata map[string]interface{}) (string, error) {
-	return "", nil
-}
-
-func (f *fakeRconAPI) RemovePlayerFromSquad(playerID string) error {
-	return nil
-}
-
-func (f *fakeRconAPI) RemovePlayerFromSquadById(playerID string) error {
-	return nil
-}
-
-type fakeLogAPI struct{}
-
-func (fakeLogAPI) Info(message string, fields map[string]interface{})             {}
-func (fakeLogAPI) Warn(message string, fields map[string]interface{})             {}
-func (fakeLogAPI) Error(message string, err error, fields map[string]interface{}) {}
-func (fakeLogAPI) Debug(message string, fields map[string]interface{})            {}
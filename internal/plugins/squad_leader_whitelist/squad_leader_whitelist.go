@@ -17,6 +17,7 @@ import (
 	"go.codycody31.dev/squad-aegis/internal/shared/plug_config_schema"
 	"go.codycody31.dev/squad-aegis/internal/shared/utils"
 	"go.codycody31.dev/squad-aegis/internal/shared/whitelistprogress"
+	"go.codycody31.dev/squad-aegis/pkg/plugintest"
 )
 
 // SquadLeaderWhitelistPlugin manages a progressive whitelist for players who lead squads effectively
@@ -720,42 +721,27 @@ func (p *SquadLeaderWhitelistPlugin) getSquadSize(players []*plugin_manager.Play
 
 // progressTrackingLoop handles the periodic progress tracking
 func (p *SquadLeaderWhitelistPlugin) progressTrackingLoop() {
-	for {
-		select {
-		case <-p.ctx.Done():
-			return
-		case <-p.progressTicker.C:
-			if err := p.trackProgress(); err != nil {
-				p.apis.LogAPI.Error("Failed to track squad leader progress", err, nil)
-			}
+	for plugintest.WaitForTick(&p.mu, &p.ctx, &p.progressTicker) {
+		if err := p.trackProgress(); err != nil {
+			p.apis.LogAPI.Error("Failed to track squad leader progress", err, nil)
 		}
 	}
 }
 
 // decayLoop handles the periodic progress decay
 func (p *SquadLeaderWhitelistPlugin) decayLoop() {
-	for {
-		select {
-		case <-p.ctx.Done():
-			return
-		case <-p.decayTicker.C:
-			if err := p.decayProgress(); err != nil {
-				p.apis.LogAPI.Error("Failed to decay squad leader progress", err, nil)
-			}
+	for plugintest.WaitForTick(&p.mu, &p.ctx, &p.decayTicker) {
+		if err := p.decayProgress(); err != nil {
+			p.apis.LogAPI.Error("Failed to decay squad leader progress", err, nil)
 		}
 	}
 }
 
 // adminSyncLoop handles periodic admin synchronization
 func (p *SquadLeaderWhitelistPlugin) adminSyncLoop() {
-	for {
-		select {
-		case <-p.ctx.Done():
-			return
-		case <-p.adminSyncTicker.C:
-			if err := p.syncTemporaryAdmins(); err != nil {
-				p.apis.LogAPI.Error("Failed to sync temporary admins", err, nil)
-			}
+	for plugintest.WaitForTick(&p.mu, &p.ctx, &p.adminSyncTicker) {
+		if err := p.syncTemporaryAdmins(); err != nil {
+			p.apis.LogAPI.Error("Failed to sync temporary admins", err, nil)
 		}
 	}
 }
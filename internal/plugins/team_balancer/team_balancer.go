@@ -185,6 +185,9 @@ func Define() plugin_manager.PluginDefinition {
 			event_manager.EventTypeRconChatMessage,
 		},
 
+		HTTPRoutes: httpRoutes(),
+		Panels:     panels(),
+
 		CreateInstance: func() plugin_manager.Plugin {
 			return &TeamBalancerPlugin{}
 		},
@@ -1373,6 +1376,13 @@ func (p *TeamBalancerPlugin) executeScramble(isSimulated bool) error {
 
 	// Save scramble time and reset streak
 	p.mu.Lock()
+	p.recordScramble(ScrambleRecord{
+		ExecutedAt:     time.Now(),
+		WinStreakTeam:  p.winStreakTeam,
+		WinStreakCount: p.winStreakCount,
+		PlayersMoved:   completed,
+		PlayersFailed:  failed,
+	})
 	p.saveScrambleTime()
 	p.resetStreak("Post-scramble")
 	p.mu.Unlock()
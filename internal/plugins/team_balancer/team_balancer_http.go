@@ -0,0 +1,137 @@
+package team_balancer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.codycody31.dev/squad-aegis/internal/plugin_manager"
+)
+
+// maxScrambleHistory bounds the scramble history kept in plugin data.
+const maxScrambleHistory = 50
+
+// ScrambleRecord is one executed scramble, kept for the history panel.
+type ScrambleRecord struct {
+	ExecutedAt     time.Time `json:"executed_at"`
+	WinStreakTeam  int       `json:"win_streak_team"`
+	WinStreakCount int       `json:"win_streak_count"`
+	PlayersMoved   int       `json:"players_moved"`
+	PlayersFailed  int       `json:"players_failed"`
+}
+
+func httpRoutes() []plugin_manager.PluginHTTPRoute {
+	return []plugin_manager.PluginHTTPRoute{
+		{
+			Method:      http.MethodGet,
+			Path:        "/status",
+			Access:      plugin_manager.PluginHTTPAccessView,
+			Description: "Current win streak and scramble state",
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/scrambles",
+			Access:      plugin_manager.PluginHTTPAccessView,
+			Description: "Recently executed scrambles",
+		},
+	}
+}
+
+func panels() []plugin_manager.PluginPanel {
+	return []plugin_manager.PluginPanel{
+		{
+			ID:             "status",
+			Title:          "Balance Status",
+			Type:           plugin_manager.PluginPanelTypeStats,
+			Route:          "/status",
+			RefreshSeconds: 30,
+		},
+		{
+			ID:          "scrambles",
+			Title:       "Scramble History",
+			Description: fmt.Sprintf("The last %d scrambles this instance executed.", maxScrambleHistory),
+			Type:        plugin_manager.PluginPanelTypeTable,
+			Route:       "/scrambles",
+			Columns: []plugin_manager.PluginPanelColumn{
+				{Key: "executed_at", Label: "Executed", Format: "datetime"},
+				{Key: "win_streak_team", Label: "Streak Team", Format: "number"},
+				{Key: "win_streak_count", Label: "Streak Wins", Format: "number"},
+				{Key: "players_moved", Label: "Moved", Format: "number"},
+				{Key: "players_failed", Label: "Failed", Format: "number"},
+			},
+		},
+	}
+}
+
+// HandleHTTP serves the routes declared in httpRoutes.
+func (p *TeamBalancerPlugin) HandleHTTP(_ context.Context, req *plugin_manager.PluginHTTPRequest) (*plugin_manager.PluginHTTPResponse, error) {
+	switch req.Route {
+	case "/status":
+		p.mu.Lock()
+		tracking := "enabled"
+		if p.manuallyDisabled || !p.getBoolConfig("enable_win_streak_tracking") {
+			tracking = "disabled"
+		}
+		lastScramble := "never"
+		if !p.lastScrambleTime.IsZero() {
+			lastScramble = p.lastScrambleTime.UTC().Format(time.RFC3339)
+		}
+		stats := []map[string]interface{}{
+			{"label": "Tracking", "value": tracking},
+			{"label": "Win Streak", "value": fmt.Sprintf("Team %d (%d wins)", p.winStreakTeam, p.winStreakCount)},
+			{"label": "Scramble Threshold", "value": p.getIntConfig("max_win_streak")},
+			{"label": "Scramble Pending", "value": p.scramblePending || p.scrambleInProgress},
+			{"label": "Last Scramble", "value": lastScramble},
+		}
+		p.mu.Unlock()
+		return plugin_manager.NewPluginHTTPJSONResponse(http.StatusOK, map[string]interface{}{"stats": stats})
+	case "/scrambles":
+		p.mu.Lock()
+		history := p.loadScrambleHistory()
+		p.mu.Unlock()
+		// Newest first
+		rows := make([]ScrambleRecord, 0, len(history))
+		for i := len(history) - 1; i >= 0; i-- {
+			rows = append(rows, history[i])
+		}
+		return plugin_manager.NewPluginHTTPJSONResponse(http.StatusOK, map[string]interface{}{"rows": rows})
+	}
+	return plugin_manager.NewPluginHTTPJSONResponse(http.StatusNotFound, map[string]interface{}{"message": "not found"})
+}
+
+// loadScrambleHistory reads the stored history, oldest first. The caller
+// holds p.mu.
+func (p *TeamBalancerPlugin) loadScrambleHistory() []ScrambleRecord {
+	var history []ScrambleRecord
+	historyJSON, err := p.apis.DatabaseAPI.GetPluginData("scramble_history")
+	if err != nil || historyJSON == "" {
+		return history
+	}
+	if err := json.Unmarshal([]byte(historyJSON), &history); err != nil {
+		p.apis.LogAPI.Warn("Failed to read scramble history", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil
+	}
+	return history
+}
+
+// recordScramble appends a scramble to the stored history, dropping the
+// oldest entries past maxScrambleHistory. The caller holds p.mu.
+func (p *TeamBalancerPlugin) recordScramble(record ScrambleRecord) {
+	history := append(p.loadScrambleHistory(), record)
+	if len(history) > maxScrambleHistory {
+		history = history[len(history)-maxScrambleHistory:]
+	}
+	historyJSON, err := json.Marshal(history)
+	if err != nil {
+		return
+	}
+	if err := p.apis.DatabaseAPI.SetPluginData("scramble_history", string(historyJSON)); err != nil {
+		p.apis.LogAPI.Warn("Failed to save scramble history", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
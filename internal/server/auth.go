@@ -34,6 +34,11 @@ type UpdatePasswordRequest struct {
 func (s *Server) AuthLogin(c *gin.Context) {
 	var req AuthLoginRequest
 
+	if s.passwordLoginDisabled() {
+		responses.Forbidden(c, "Password login is disabled, please sign in with SSO", nil)
+		return
+	}
+
 	if err := c.ShouldBindJSON(&req); err != nil {
 		responses.BadRequest(c, "Invalid request payload", nil)
 		return
@@ -58,7 +63,40 @@ func (s *Server) AuthLogin(c *gin.Context) {
 		return
 	}
 
-	session, err := core.CreateSession(c.Copy(), tx, user.Id, c.ClientIP(), time.Hour*24)
+	twoFactorEnabled, err := core.IsTwoFactorEnabled(c.Copy(), tx, user.Id)
+	if err != nil {
+		_ = tx.Rollback()
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	// Users with 2FA get a short-lived challenge instead of a session; the
+	// session is issued by AuthLoginTwoFactor once the code checks out.
+	if twoFactorEnabled {
+		challengeToken, expiresAt, err := core.CreateTwoFactorChallenge(c.Copy(), tx, user.Id, c.ClientIP(), models.AuthMethodPasswordTwoFactor)
+		if err != nil {
+			_ = tx.Rollback()
+			responses.InternalServerError(c, err, nil)
+			return
+		}
+
+		if err := tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("login transaction commit failed")
+			responses.InternalServerError(c, err, nil)
+			return
+		}
+
+		responses.Success(c, "Two-factor verification required", &gin.H{
+			"two_factor_required": true,
+			"challenge": gin.H{
+				"token":      challengeToken,
+				"expires_at": expiresAt,
+			},
+		})
+		return
+	}
+
+	session, err := core.CreateSession(c.Copy(), tx, user.Id, c.ClientIP(), time.Hour*24, models.AuthMethodPassword)
 	if err != nil {
 		log.Error().Err(err).Msg("CreateSession failed")
 		if rbErr := tx.Rollback(); rbErr != nil {
@@ -129,9 +167,16 @@ func (s *Server) AuthInitial(c *gin.Context) {
 		return
 	}
 
+	twoFactorRequired, err := core.UserNeedsTwoFactorEnrollment(c.Copy(), s.Dependencies.DB, user)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
 	responses.Success(c, "User authenticated", &gin.H{
-		"user":              user,
-		"serverPermissions": serverPermissions,
+		"user":                        user,
+		"serverPermissions":           serverPermissions,
+		"twoFactorEnrollmentRequired": twoFactorRequired,
 	})
 }
 
@@ -436,6 +481,16 @@ func (s *Server) RequirePermission(perm permissions.Permission) gin.HandlerFunc
 			return
 		}
 
+		if !s.enforceTwoFactorPolicy(c, user, anyPrivileged(perm)) {
+			return
+		}
+
+		if len(apiTokenScope(c, perm)) == 0 {
+			responses.Forbidden(c, "API token does not grant the required permission", nil)
+			c.Abort()
+			return
+		}
+
 		// Super admins have all permissions
 		if user.SuperAdmin {
 			c.Next()
@@ -484,6 +539,19 @@ func (s *Server) RequireAnyPermission(perms ...permissions.Permission) gin.Handl
 			return
 		}
 
+		if !s.enforceTwoFactorPolicy(c, user, allPrivileged(perms...)) {
+			return
+		}
+
+		// API tokens may only satisfy the check through permissions they were
+		// granted.
+		perms := apiTokenScope(c, perms...)
+		if len(perms) == 0 {
+			responses.Forbidden(c, "API token does not grant any of the required permissions", nil)
+			c.Abort()
+			return
+		}
+
 		if user.SuperAdmin {
 			c.Next()
 			return
@@ -530,6 +598,16 @@ func (s *Server) RequireAllPermissions(perms ...permissions.Permission) gin.Hand
 			return
 		}
 
+		if !s.enforceTwoFactorPolicy(c, user, anyPrivileged(perms...)) {
+			return
+		}
+
+		if len(apiTokenScope(c, perms...)) != len(perms) {
+			responses.Forbidden(c, "API token does not grant all of the required permissions", nil)
+			c.Abort()
+			return
+		}
+
 		if user.SuperAdmin {
 			c.Next()
 			return
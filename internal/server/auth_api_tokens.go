@@ -0,0 +1,304 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/rs/zerolog/log"
+	"go.codycody31.dev/squad-aegis/internal/core"
+	"go.codycody31.dev/squad-aegis/internal/models"
+	"go.codycody31.dev/squad-aegis/internal/permissions"
+	"go.codycody31.dev/squad-aegis/internal/server/responses"
+)
+
+type APITokenCreateRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	ServerIds     []string `json:"server_ids" binding:"required,min=1"`
+	Permissions   []string `json:"permissions" binding:"required,min=1"`
+	ExpiresInDays int      `json:"expires_in_days"`
+}
+
+// apiTokenContextKey carries the authenticating token's ID on the request
+// context so audit entries can be attributed to it.
+type apiTokenContextKey struct{}
+
+func apiTokenIDFromContext(ctx context.Context) *uuid.UUID {
+	if id, ok := ctx.Value(apiTokenContextKey{}).(uuid.UUID); ok {
+		return &id
+	}
+	return nil
+}
+
+// requestAPIToken returns the token the request authenticated with, or nil
+// for session-authenticated requests.
+func requestAPIToken(c *gin.Context) *models.APIToken {
+	if value, exists := c.Get("api_token"); exists {
+		return value.(*models.APIToken)
+	}
+	return nil
+}
+
+// authAPIToken authenticates a request carrying a personal API token. Tokens
+// are only valid on routes under a server the token is scoped to; which
+// permissions they may exercise there is checked by RequirePermission and
+// friends, and routes without a permission gate must use RejectAPIToken.
+func (s *Server) authAPIToken(c *gin.Context, secret string, required bool) {
+	token, err := core.AuthenticateAPIToken(c.Request.Context(), s.Dependencies.DB, secret)
+	if err != nil {
+		if !errors.Is(err, core.ErrAPITokenInvalid) {
+			log.Error().Err(err).Msg("Failed to authenticate api token")
+		}
+		if required {
+			responses.Unauthorized(c, "Invalid or expired API token", nil)
+			c.Abort()
+		}
+		return
+	}
+
+	serverId, err := uuid.Parse(c.Param("serverId"))
+	if err != nil || !token.AllowsServer(serverId) {
+		if required {
+			responses.Forbidden(c, "API token is not valid for this resource", nil)
+			c.Abort()
+		}
+		return
+	}
+
+	if err := core.TouchAPIToken(c.Request.Context(), s.Dependencies.DB, token.Id, c.ClientIP()); err != nil {
+		responses.InternalServerError(c, err, nil)
+		c.Abort()
+		return
+	}
+
+	c.Set("session", &models.Session{
+		Id:         token.Id,
+		UserId:     token.UserId,
+		CreatedAt:  token.CreatedAt,
+		ExpiresAt:  null.TimeFrom(token.ExpiresAt),
+		LastSeen:   time.Now(),
+		LastSeenIp: c.ClientIP(),
+		AuthMethod: models.AuthMethodAPIToken,
+	})
+	c.Set("api_token", token)
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), apiTokenContextKey{}, token.Id))
+}
+
+// apiTokenScope narrows perms to the ones the request's API token was
+// granted. Session-authenticated requests get perms back unchanged.
+func apiTokenScope(c *gin.Context, perms ...permissions.Permission) []permissions.Permission {
+	token := requestAPIToken(c)
+	if token == nil {
+		return perms
+	}
+
+	scoped := make([]permissions.Permission, 0, len(perms))
+	for _, perm := range perms {
+		if token.AllowsPermission(string(perm)) {
+			scoped = append(scoped, perm)
+		}
+	}
+	return scoped
+}
+
+// rejectAPIToken aborts requests authenticated with an API token. It guards
+// routes that tokens can never be scoped to, such as super admin pages.
+func rejectAPIToken(c *gin.Context) bool {
+	if requestAPIToken(c) == nil {
+		return false
+	}
+	responses.Forbidden(c, "This endpoint cannot be used with an API token", nil)
+	c.Abort()
+	return true
+}
+
+// RejectAPIToken is middleware for server routes that have no permission gate
+// a token could be scoped to. Without it a token valid for the server would
+// reach them whatever permissions it was granted.
+func (s *Server) RejectAPIToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rejectAPIToken(c)
+	}
+}
+
+// AuthAPITokensList lists the current user's API tokens
+func (s *Server) AuthAPITokensList(c *gin.Context) {
+	session := c.MustGet("session").(*models.Session)
+
+	tokens, err := core.ListAPITokens(c.Request.Context(), s.Dependencies.DB, &session.UserId)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	responses.Success(c, "API tokens fetched successfully", &gin.H{"tokens": tokens})
+}
+
+// AuthAPITokenCreate issues a new API token for the current user. The secret
+// is returned once and cannot be retrieved again.
+func (s *Server) AuthAPITokenCreate(c *gin.Context) {
+	user := s.getUserFromSession(c)
+	if user == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return
+	}
+
+	var req APITokenCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		responses.BadRequest(c, "Token name is required", nil)
+		return
+	}
+
+	if req.ExpiresInDays == 0 {
+		req.ExpiresInDays = 90
+	}
+	lifetime := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	if req.ExpiresInDays < 0 || lifetime > core.APITokenMaxLifetime {
+		responses.BadRequest(c, "Token lifetime must be between 1 and 365 days", nil)
+		return
+	}
+
+	validPermissions := map[string]bool{}
+	for _, perm := range permissions.AllPermissions() {
+		if !perm.IsWildcard() {
+			validPermissions[perm.String()] = true
+		}
+	}
+	seenPermissions := map[string]bool{}
+	var scopes []string
+	for _, perm := range req.Permissions {
+		if !validPermissions[perm] {
+			responses.BadRequest(c, "Unknown or disallowed permission: "+perm, nil)
+			return
+		}
+		if !seenPermissions[perm] {
+			seenPermissions[perm] = true
+			scopes = append(scopes, perm)
+		}
+	}
+
+	seenServers := map[uuid.UUID]bool{}
+	var serverIds []uuid.UUID
+	for _, raw := range req.ServerIds {
+		serverId, err := uuid.Parse(raw)
+		if err != nil {
+			responses.BadRequest(c, "Invalid server ID: "+raw, nil)
+			return
+		}
+		if seenServers[serverId] {
+			continue
+		}
+		// GetServerById returns an empty server when the user has no access.
+		server, err := core.GetServerById(c.Request.Context(), s.Dependencies.DB, serverId, user)
+		if err != nil {
+			responses.InternalServerError(c, err, nil)
+			return
+		}
+		if server.Id != serverId {
+			responses.BadRequest(c, "You do not have access to server "+raw, nil)
+			return
+		}
+		seenServers[serverId] = true
+		serverIds = append(serverIds, serverId)
+	}
+
+	token := &models.APIToken{
+		UserId:      user.Id,
+		Name:        name,
+		ServerIds:   serverIds,
+		Permissions: scopes,
+		ExpiresAt:   time.Now().Add(lifetime),
+	}
+
+	secret, err := core.CreateAPIToken(c.Request.Context(), s.Dependencies.DB, token)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), nil, &user.Id, "auth:api_token:create", map[string]interface{}{
+		"token_id":    token.Id.String(),
+		"name":        token.Name,
+		"server_ids":  token.ServerIds,
+		"permissions": token.Permissions,
+		"expires_at":  token.ExpiresAt,
+	})
+
+	responses.Success(c, "API token created successfully", &gin.H{
+		"token":  token,
+		"secret": secret,
+	})
+}
+
+// AuthAPITokenRevoke revokes one of the current user's API tokens
+func (s *Server) AuthAPITokenRevoke(c *gin.Context) {
+	session := c.MustGet("session").(*models.Session)
+
+	tokenId, err := uuid.Parse(c.Param("tokenId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid token ID", nil)
+		return
+	}
+
+	if err := core.RevokeAPIToken(c.Request.Context(), s.Dependencies.DB, tokenId, &session.UserId, session.UserId); err != nil {
+		if errors.Is(err, core.ErrAPITokenNotFound) {
+			responses.NotFound(c, "API token not found", nil)
+			return
+		}
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), nil, &session.UserId, "auth:api_token:revoke", map[string]interface{}{
+		"token_id": tokenId.String(),
+	})
+
+	responses.SimpleSuccess(c, "API token revoked successfully")
+}
+
+// SudoAPITokensList lists API tokens across all users
+func (s *Server) SudoAPITokensList(c *gin.Context) {
+	tokens, err := core.ListAPITokens(c.Request.Context(), s.Dependencies.DB, nil)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	responses.Success(c, "API tokens fetched successfully", &gin.H{"tokens": tokens})
+}
+
+// SudoAPITokenRevoke revokes any user's API token
+func (s *Server) SudoAPITokenRevoke(c *gin.Context) {
+	session := c.MustGet("session").(*models.Session)
+
+	tokenId, err := uuid.Parse(c.Param("tokenId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid token ID", nil)
+		return
+	}
+
+	if err := core.RevokeAPIToken(c.Request.Context(), s.Dependencies.DB, tokenId, nil, session.UserId); err != nil {
+		if errors.Is(err, core.ErrAPITokenNotFound) {
+			responses.NotFound(c, "API token not found", nil)
+			return
+		}
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), nil, &session.UserId, "sudo:api_token:revoke", map[string]interface{}{
+		"token_id": tokenId.String(),
+	})
+
+	responses.SimpleSuccess(c, "API token revoked successfully")
+}
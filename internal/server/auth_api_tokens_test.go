@@ -0,0 +1,156 @@
+package server
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"go.codycody31.dev/squad-aegis/internal/core"
+	"go.codycody31.dev/squad-aegis/internal/models"
+	"go.codycody31.dev/squad-aegis/internal/permissions"
+)
+
+func apiTokenTestRows(tokenID, userID, serverID uuid.UUID, perms string) *authTestSQLRows {
+	now := time.Now().UTC()
+	return &authTestSQLRows{
+		columns: []string{"id", "user_id", "name", "token_prefix", "server_ids", "permissions", "expires_at", "last_used_at", "last_used_ip", "revoked_at", "created_at"},
+		values: [][]driver.Value{{
+			tokenID.String(),
+			userID.String(),
+			"bot",
+			"sat_abcdef",
+			"{" + serverID.String() + "}",
+			perms,
+			now.Add(time.Hour),
+			nil,
+			nil,
+			nil,
+			now,
+		}},
+	}
+}
+
+func newAPITokenTestContext(serverID uuid.UUID, secret string) (*gin.Context, *httptest.ResponseRecorder) {
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/servers/"+serverID.String()+"/bans", nil)
+	c.Request.Header.Set("Authorization", "Bearer "+secret)
+	c.Params = gin.Params{{Key: "serverId", Value: serverID.String()}}
+	return c, recorder
+}
+
+func TestAuthSessionAcceptsScopedAPIToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tokenID := uuid.New()
+	userID := uuid.New()
+	serverID := uuid.New()
+	secret := "sat_scoped-token"
+	touched := false
+
+	db := openAuthTestDB(t, &authTestSQLDriver{
+		queryContext: func(query string, args []driver.NamedValue) (driver.Rows, error) {
+			if got, want := fmt.Sprint(args[0].Value), core.HashAPIToken(secret); got != want {
+				t.Fatalf("token hash = %q, want %q", got, want)
+			}
+			return apiTokenTestRows(tokenID, userID, serverID, "{ui:bans:view}"), nil
+		},
+		execContext: func(query string, args []driver.NamedValue) (driver.Result, error) {
+			touched = true
+			return driver.RowsAffected(1), nil
+		},
+	})
+
+	server := &Server{Dependencies: &Dependencies{DB: db}}
+	c, recorder := newAPITokenTestContext(serverID, secret)
+
+	server.authSession(c, true)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusOK)
+	}
+	session, ok := c.MustGet("session").(*models.Session)
+	if !ok || session.UserId != userID || session.AuthMethod != models.AuthMethodAPIToken {
+		t.Fatalf("session = %+v, want api token session for user %s", session, userID)
+	}
+	if !touched {
+		t.Fatal("token last-used timestamp was not updated")
+	}
+	if got := apiTokenIDFromContext(c.Request.Context()); got == nil || *got != tokenID {
+		t.Fatalf("apiTokenIDFromContext() = %v, want %s", got, tokenID)
+	}
+
+	scoped := apiTokenScope(c, permissions.UIBansView, permissions.UIBansDelete)
+	if len(scoped) != 1 || scoped[0] != permissions.UIBansView {
+		t.Fatalf("apiTokenScope() = %v, want [%s]", scoped, permissions.UIBansView)
+	}
+}
+
+func TestAuthSessionRejectsAPITokenOutsideServerScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := openAuthTestDB(t, &authTestSQLDriver{
+		queryContext: func(query string, args []driver.NamedValue) (driver.Rows, error) {
+			return apiTokenTestRows(uuid.New(), uuid.New(), uuid.New(), "{ui:bans:view}"), nil
+		},
+		execContext: func(query string, args []driver.NamedValue) (driver.Result, error) {
+			t.Fatalf("unexpected exec: %s", query)
+			return nil, nil
+		},
+	})
+
+	server := &Server{Dependencies: &Dependencies{DB: db}}
+	c, recorder := newAPITokenTestContext(uuid.New(), "sat_other-server")
+
+	server.authSession(c, true)
+
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusForbidden)
+	}
+	if _, exists := c.Get("session"); exists {
+		t.Fatal("session unexpectedly present in context")
+	}
+}
+
+func TestAuthIsSuperAdminRejectsAPIToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	server := &Server{Dependencies: &Dependencies{}}
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/sudo/sessions", nil)
+	c.Set("session", &models.Session{UserId: uuid.New(), AuthMethod: models.AuthMethodAPIToken})
+	c.Set("api_token", &models.APIToken{})
+
+	server.AuthIsSuperAdmin()(c)
+
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusForbidden)
+	}
+}
+
+func TestRejectAPITokenBlocksUngatedRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	server := &Server{}
+	serverID := uuid.New()
+
+	c, recorder := newAPITokenTestContext(serverID, "sat_scoped-token")
+	c.Set("api_token", &models.APIToken{Id: uuid.New(), ServerIds: []uuid.UUID{serverID}})
+	server.RejectAPIToken()(c)
+	if !c.IsAborted() || recorder.Code != http.StatusForbidden {
+		t.Fatalf("token request aborted = %v, status = %d, want a 403", c.IsAborted(), recorder.Code)
+	}
+
+	c, recorder = newAPITokenTestContext(serverID, "")
+	server.RejectAPIToken()(c)
+	if c.IsAborted() || recorder.Code != http.StatusOK {
+		t.Fatalf("session request aborted = %v, status = %d, want it to pass", c.IsAborted(), recorder.Code)
+	}
+}
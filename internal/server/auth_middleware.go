@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"go.codycody31.dev/squad-aegis/internal/core"
 	"go.codycody31.dev/squad-aegis/internal/models"
 	"go.codycody31.dev/squad-aegis/internal/server/responses"
 )
@@ -29,6 +30,12 @@ func (s *Server) authSession(c *gin.Context, required bool) {
 		sessionToken = sessionToken[7:]
 	}
 
+	// Personal API tokens are only accepted in the Authorization header.
+	if core.IsAPIToken(sessionToken) {
+		s.authAPIToken(c, sessionToken, required)
+		return
+	}
+
 	// Only allow query parameter tokens for upgrade-style requests plus the
 	// storage download endpoint that currently relies on window.open().
 	if sessionToken == "" {
@@ -108,6 +115,10 @@ func (s *Server) AuthIsSuperAdmin() func(c *gin.Context) {
 		}
 		session := sess.(*models.Session)
 
+		if rejectAPIToken(c) {
+			return
+		}
+
 		userIsSuperAdmin := s.Dependencies.DB.QueryRow("SELECT FROM users WHERE id = $1 AND super_admin = true", session.UserId)
 		if err := userIsSuperAdmin.Scan(); err != nil {
 			c.JSON(http.StatusForbidden, gin.H{
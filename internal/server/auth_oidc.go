@@ -0,0 +1,320 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"go.codycody31.dev/squad-aegis/internal/core"
+	"go.codycody31.dev/squad-aegis/internal/models"
+	"go.codycody31.dev/squad-aegis/internal/oidc"
+	"go.codycody31.dev/squad-aegis/internal/server/responses"
+	"go.codycody31.dev/squad-aegis/internal/shared/config"
+)
+
+type OIDCGroupMappingCreateRequest struct {
+	GroupName    string  `json:"group_name" binding:"required"`
+	ServerId     *string `json:"server_id"`
+	ServerRoleId *string `json:"server_role_id"`
+	SuperAdmin   bool    `json:"super_admin"`
+}
+
+// passwordLoginDisabled reports whether username/password login has been
+// turned off in favour of SSO.
+func (s *Server) passwordLoginDisabled() bool {
+	return s.Dependencies.OIDCProvider != nil && config.Config.Auth.OIDC.DisablePasswordLogin
+}
+
+// AuthOIDCConfig tells the login page which sign-in methods are available
+func (s *Server) AuthOIDCConfig(c *gin.Context) {
+	responses.Success(c, "Login options fetched successfully", &gin.H{
+		"sso_enabled":            s.Dependencies.OIDCProvider != nil,
+		"sso_provider_name":      config.Config.Auth.OIDC.ProviderName,
+		"password_login_enabled": !s.passwordLoginDisabled(),
+	})
+}
+
+// AuthOIDCLogin redirects the browser to the identity provider
+func (s *Server) AuthOIDCLogin(c *gin.Context) {
+	provider := s.Dependencies.OIDCProvider
+	if provider == nil {
+		responses.NotFound(c, "SSO login is not enabled", nil)
+		return
+	}
+
+	state, err := oidc.RandomToken()
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+	nonce, err := oidc.RandomToken()
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+	codeVerifier, err := oidc.RandomToken()
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	if err := core.CreateOIDCState(c.Request.Context(), s.Dependencies.DB, state, nonce, codeVerifier); err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	authURL, err := provider.AuthCodeURL(c.Request.Context(), state, nonce, codeVerifier)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to build SSO authorization URL")
+		redirectSSOError(c, "The identity provider is unavailable")
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// AuthOIDCCallback completes the authorization code flow, provisions or
+// resolves the local user, applies group mappings and issues a session.
+func (s *Server) AuthOIDCCallback(c *gin.Context) {
+	provider := s.Dependencies.OIDCProvider
+	if provider == nil {
+		responses.NotFound(c, "SSO login is not enabled", nil)
+		return
+	}
+
+	if idpErr := c.Query("error"); idpErr != "" {
+		log.Warn().Str("error", idpErr).Str("description", c.Query("error_description")).Msg("Identity provider rejected SSO login")
+		redirectSSOError(c, "Sign-in was cancelled or rejected by the identity provider")
+		return
+	}
+
+	nonce, codeVerifier, err := core.ConsumeOIDCState(c.Request.Context(), s.Dependencies.DB, c.Query("state"))
+	if err != nil {
+		if !errors.Is(err, core.ErrOIDCStateInvalid) {
+			log.Error().Err(err).Msg("Failed to load SSO state")
+		}
+		redirectSSOError(c, "Your sign-in request expired, please try again")
+		return
+	}
+
+	claims, err := provider.Exchange(c.Request.Context(), c.Query("code"), codeVerifier, nonce)
+	if err != nil {
+		log.Warn().Err(err).Str("client_ip", c.ClientIP()).Msg("SSO code exchange failed")
+		redirectSSOError(c, "Could not verify your identity with the identity provider")
+		return
+	}
+
+	identity := &models.ExternalIdentity{
+		Provider: config.Config.Auth.OIDC.IssuerUrl,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+		Name:     claims.Name,
+		Username: claims.Username,
+		Groups:   claims.Groups,
+	}
+	if claims.SteamID != "" {
+		steamId, err := strconv.ParseInt(claims.SteamID, 10, 64)
+		if err != nil || steamId <= 0 {
+			log.Warn().Str("subject", claims.Subject).Str("steam_id", claims.SteamID).Msg("Ignoring invalid Steam ID claim")
+		} else {
+			identity.SteamId = steamId
+		}
+	}
+
+	tx, err := s.Dependencies.DB.BeginTx(c.Request.Context(), nil)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to begin SSO login transaction")
+		redirectSSOError(c, "Sign-in failed, please try again")
+		return
+	}
+	defer tx.Rollback()
+
+	user, created, err := core.ResolveExternalUser(c.Request.Context(), tx, identity, config.Config.Auth.OIDC.AutoProvision)
+	if err != nil {
+		if errors.Is(err, core.ErrOIDCProvisioningBlocked) {
+			log.Warn().Str("subject", claims.Subject).Str("email", claims.Email).Msg("SSO login for unknown user rejected")
+			redirectSSOError(c, "No Squad Aegis account is linked to your identity")
+			return
+		}
+		log.Error().Err(err).Str("subject", claims.Subject).Msg("Failed to resolve SSO user")
+		redirectSSOError(c, "Sign-in failed, please try again")
+		return
+	}
+
+	affectedServers, err := core.SyncOIDCGroupMappings(c.Request.Context(), tx, user.Id, identity.Groups)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", user.Id.String()).Msg("Failed to apply SSO group mappings")
+		redirectSSOError(c, "Sign-in failed, please try again")
+		return
+	}
+
+	twoFactorEnabled, err := core.IsTwoFactorEnabled(c.Request.Context(), tx, user.Id)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to check two-factor status")
+		redirectSSOError(c, "Sign-in failed, please try again")
+		return
+	}
+
+	// Local 2FA still applies on top of SSO; hand the login page a challenge
+	// instead of a session.
+	var session *models.Session
+	var challengeToken string
+	if twoFactorEnabled {
+		challengeToken, _, err = core.CreateTwoFactorChallenge(c.Request.Context(), tx, user.Id, c.ClientIP(), models.AuthMethodOIDCTwoFactor)
+	} else {
+		session, err = core.CreateSession(c.Request.Context(), tx, user.Id, c.ClientIP(), time.Hour*24, models.AuthMethodOIDC)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to complete SSO login")
+		redirectSSOError(c, "Sign-in failed, please try again")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("SSO login transaction commit failed")
+		redirectSSOError(c, "Sign-in failed, please try again")
+		return
+	}
+
+	for _, serverId := range affectedServers {
+		s.Dependencies.PermissionService.InvalidateUserCache(user.Id, serverId)
+	}
+
+	auditChanges := map[string]interface{}{
+		"subject":   identity.Subject,
+		"email":     identity.Email,
+		"groups":    identity.Groups,
+		"client_ip": c.ClientIP(),
+	}
+	if created {
+		s.CreateAuditLog(c.Request.Context(), nil, &user.Id, "auth:sso:provision", auditChanges)
+	}
+	s.CreateAuditLog(c.Request.Context(), nil, &user.Id, "auth:sso:login", auditChanges)
+
+	if challengeToken != "" {
+		c.Redirect(http.StatusFound, "/login?challenge="+url.QueryEscape(challengeToken))
+		return
+	}
+
+	// The web UI reads the session token from this cookie, so it cannot be
+	// HttpOnly.
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie("session", session.Token, int((24 * time.Hour).Seconds()), "/", "", strings.HasPrefix(config.Config.App.Url, "https://"), false)
+	c.Redirect(http.StatusFound, "/dashboard")
+}
+
+func redirectSSOError(c *gin.Context, message string) {
+	c.Redirect(http.StatusFound, "/login?sso_error="+url.QueryEscape(message))
+}
+
+// SudoOIDCGroupMappingsList lists IdP group to role mappings
+func (s *Server) SudoOIDCGroupMappingsList(c *gin.Context) {
+	mappings, err := core.ListOIDCGroupMappings(c.Request.Context(), s.Dependencies.DB)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	responses.Success(c, "Group mappings fetched successfully", &gin.H{"mappings": mappings})
+}
+
+// SudoOIDCGroupMappingCreate maps an IdP group to a server role or to super admin
+func (s *Server) SudoOIDCGroupMappingCreate(c *gin.Context) {
+	session := c.MustGet("session").(*models.Session)
+	var req OIDCGroupMappingCreateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+		return
+	}
+
+	mapping := &models.OIDCGroupMapping{
+		GroupName:  strings.TrimSpace(req.GroupName),
+		SuperAdmin: req.SuperAdmin,
+	}
+	if mapping.GroupName == "" {
+		responses.BadRequest(c, "Group name is required", nil)
+		return
+	}
+
+	if !req.SuperAdmin {
+		if req.ServerId == nil || req.ServerRoleId == nil {
+			responses.BadRequest(c, "A server and role are required unless the mapping grants super admin", nil)
+			return
+		}
+		serverId, err := uuid.Parse(*req.ServerId)
+		if err != nil {
+			responses.BadRequest(c, "Invalid server ID", nil)
+			return
+		}
+		roleId, err := uuid.Parse(*req.ServerRoleId)
+		if err != nil {
+			responses.BadRequest(c, "Invalid role ID", nil)
+			return
+		}
+
+		var roleServerId uuid.UUID
+		err = s.Dependencies.DB.QueryRowContext(c.Request.Context(), "SELECT server_id FROM server_roles WHERE id = $1", roleId).Scan(&roleServerId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				responses.BadRequest(c, "Role not found", nil)
+				return
+			}
+			responses.InternalServerError(c, err, nil)
+			return
+		}
+		if roleServerId != serverId {
+			responses.BadRequest(c, "Role does not belong to the selected server", nil)
+			return
+		}
+
+		mapping.ServerId = &serverId
+		mapping.ServerRoleId = &roleId
+	} else if req.ServerId != nil || req.ServerRoleId != nil {
+		responses.BadRequest(c, "Super admin mappings cannot target a server role", nil)
+		return
+	}
+
+	if err := core.CreateOIDCGroupMapping(c.Request.Context(), s.Dependencies.DB, mapping); err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), mapping.ServerId, &session.UserId, "sudo:sso:group_mapping:create", mapping)
+
+	responses.Success(c, "Group mapping created successfully", &gin.H{"mapping": mapping})
+}
+
+// SudoOIDCGroupMappingDelete removes a group mapping. Roles granted by it are
+// revoked at the affected users' next SSO login.
+func (s *Server) SudoOIDCGroupMappingDelete(c *gin.Context) {
+	session := c.MustGet("session").(*models.Session)
+
+	mappingId, err := uuid.Parse(c.Param("mappingId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid mapping ID", nil)
+		return
+	}
+
+	if err := core.DeleteOIDCGroupMapping(c.Request.Context(), s.Dependencies.DB, mappingId); err != nil {
+		if errors.Is(err, core.ErrOIDCMappingNotFound) {
+			responses.NotFound(c, "Group mapping not found", nil)
+			return
+		}
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), nil, &session.UserId, "sudo:sso:group_mapping:delete", map[string]interface{}{
+		"mapping_id": mappingId.String(),
+	})
+
+	responses.SimpleSuccess(c, "Group mapping deleted successfully")
+}
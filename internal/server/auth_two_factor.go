@@ -0,0 +1,550 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"go.codycody31.dev/squad-aegis/internal/core"
+	"go.codycody31.dev/squad-aegis/internal/models"
+	"go.codycody31.dev/squad-aegis/internal/permissions"
+	"go.codycody31.dev/squad-aegis/internal/server/responses"
+	"go.codycody31.dev/squad-aegis/internal/shared/config"
+)
+
+type TwoFactorEnrollRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type AuthLoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+type SecurityPolicyUpdateRequest struct {
+	RequireTwoFactorForPrivileged bool `json:"require_two_factor_for_privileged"`
+}
+
+// AuthTwoFactorStatus returns the current user's 2FA enrollment state
+func (s *Server) AuthTwoFactorStatus(c *gin.Context) {
+	session := c.MustGet("session").(*models.Session)
+	ctx := c.Request.Context()
+
+	user, err := core.GetUserById(ctx, s.Dependencies.DB, session.UserId, &session.UserId)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	enabled, err := core.IsTwoFactorEnabled(ctx, s.Dependencies.DB, user.Id)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	remaining := 0
+	if enabled {
+		remaining, err = core.CountUnusedRecoveryCodes(ctx, s.Dependencies.DB, user.Id)
+		if err != nil {
+			responses.InternalServerError(c, err, nil)
+			return
+		}
+	}
+
+	required, err := core.UserNeedsTwoFactorEnrollment(ctx, s.Dependencies.DB, user)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	verifiedAt, err := core.GetSessionTwoFactorVerifiedAt(ctx, s.Dependencies.DB, session.Id)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	responses.Success(c, "Two-factor status fetched successfully", &gin.H{
+		"enabled":                  enabled,
+		"recovery_codes_remaining": remaining,
+		"enrollment_required":      required,
+		"session_verified_at":      verifiedAt,
+	})
+}
+
+// AuthTwoFactorEnroll starts TOTP enrollment and returns the secret and
+// provisioning URI. The enrollment is not active until confirmed.
+func (s *Server) AuthTwoFactorEnroll(c *gin.Context) {
+	session := c.MustGet("session").(*models.Session)
+	var req TwoFactorEnrollRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.BadRequest(c, "Invalid request payload", nil)
+		return
+	}
+
+	tx, err := s.Dependencies.DB.BeginTx(c.Copy(), nil)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+	defer tx.Rollback()
+
+	user, err := core.GetUserById(c.Copy(), tx, session.UserId, &session.UserId)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	if err := user.ComparePassword(req.Password); err != nil {
+		responses.BadRequest(c, "Current password is incorrect", nil)
+		return
+	}
+
+	secret, err := core.BeginTwoFactorEnrollment(c.Copy(), tx, user.Id)
+	if err != nil {
+		if errors.Is(err, core.ErrTwoFactorAlreadyEnabled) {
+			responses.Conflict(c, err.Error(), nil)
+			return
+		}
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	responses.Success(c, "Two-factor enrollment started", &gin.H{
+		"secret":           secret,
+		"provisioning_uri": core.TOTPProvisioningURI(config.Config.Auth.TwoFactorIssuer, user.Username, secret),
+	})
+}
+
+// AuthTwoFactorEnable confirms a pending enrollment and returns the recovery codes
+func (s *Server) AuthTwoFactorEnable(c *gin.Context) {
+	session := c.MustGet("session").(*models.Session)
+	var req TwoFactorCodeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.BadRequest(c, "Invalid request payload", nil)
+		return
+	}
+
+	tx, err := s.Dependencies.DB.BeginTx(c.Copy(), nil)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+	defer tx.Rollback()
+
+	codes, err := core.ConfirmTwoFactorEnrollment(c.Copy(), tx, session.UserId, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, core.ErrTwoFactorNotEnrolled):
+			responses.BadRequest(c, "Start enrollment before enabling two-factor authentication", nil)
+		case errors.Is(err, core.ErrTwoFactorAlreadyEnabled):
+			responses.Conflict(c, err.Error(), nil)
+		case errors.Is(err, core.ErrInvalidTwoFactorCode):
+			responses.BadRequest(c, "Invalid verification code", nil)
+		default:
+			responses.InternalServerError(c, err, nil)
+		}
+		return
+	}
+
+	if err := core.MarkSessionTwoFactorVerified(c.Copy(), tx, session.Id); err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), nil, &session.UserId, "auth:two_factor:enable", map[string]interface{}{})
+
+	responses.Success(c, "Two-factor authentication enabled", &gin.H{
+		"recovery_codes": codes,
+	})
+}
+
+// AuthTwoFactorDisable removes the user's enrollment after checking both factors
+func (s *Server) AuthTwoFactorDisable(c *gin.Context) {
+	session := c.MustGet("session").(*models.Session)
+	var req TwoFactorDisableRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.BadRequest(c, "Invalid request payload", nil)
+		return
+	}
+
+	tx, err := s.Dependencies.DB.BeginTx(c.Copy(), nil)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+	defer tx.Rollback()
+
+	user, err := core.GetUserById(c.Copy(), tx, session.UserId, &session.UserId)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	if err := user.ComparePassword(req.Password); err != nil {
+		responses.BadRequest(c, "Current password is incorrect", nil)
+		return
+	}
+
+	if _, err := core.VerifyTwoFactorCode(c.Copy(), tx, user.Id, req.Code); err != nil {
+		if errors.Is(err, core.ErrTwoFactorNotEnrolled) || errors.Is(err, core.ErrInvalidTwoFactorCode) {
+			responses.BadRequest(c, "Invalid verification code", nil)
+			return
+		}
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	if err := core.DisableTwoFactor(c.Copy(), tx, user.Id); err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), nil, &session.UserId, "auth:two_factor:disable", map[string]interface{}{})
+
+	responses.SimpleSuccess(c, "Two-factor authentication disabled")
+}
+
+// AuthTwoFactorRegenerateRecoveryCodes replaces all recovery codes
+func (s *Server) AuthTwoFactorRegenerateRecoveryCodes(c *gin.Context) {
+	session := c.MustGet("session").(*models.Session)
+	var req TwoFactorCodeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.BadRequest(c, "Invalid request payload", nil)
+		return
+	}
+
+	tx, err := s.Dependencies.DB.BeginTx(c.Copy(), nil)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := core.VerifyTwoFactorCode(c.Copy(), tx, session.UserId, req.Code); err != nil {
+		if errors.Is(err, core.ErrTwoFactorNotEnrolled) || errors.Is(err, core.ErrInvalidTwoFactorCode) {
+			responses.BadRequest(c, "Invalid verification code", nil)
+			return
+		}
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	codes, err := core.ReplaceRecoveryCodes(c.Copy(), tx, session.UserId)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), nil, &session.UserId, "auth:two_factor:recovery_codes_regenerate", map[string]interface{}{})
+
+	responses.Success(c, "Recovery codes regenerated", &gin.H{
+		"recovery_codes": codes,
+	})
+}
+
+// AuthTwoFactorVerify re-verifies the second factor for the current session so
+// that endpoints guarded by RequireRecentTwoFactor can be used.
+func (s *Server) AuthTwoFactorVerify(c *gin.Context) {
+	session := c.MustGet("session").(*models.Session)
+	var req TwoFactorCodeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.BadRequest(c, "Invalid request payload", nil)
+		return
+	}
+
+	tx, err := s.Dependencies.DB.BeginTx(c.Copy(), nil)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := core.VerifyTwoFactorCode(c.Copy(), tx, session.UserId, req.Code); err != nil {
+		if errors.Is(err, core.ErrTwoFactorNotEnrolled) || errors.Is(err, core.ErrInvalidTwoFactorCode) {
+			log.Warn().Str("user_id", session.UserId.String()).Str("client_ip", c.ClientIP()).Msg("Two-factor re-verification failed")
+			responses.BadRequest(c, "Invalid verification code", nil)
+			return
+		}
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	if err := core.MarkSessionTwoFactorVerified(c.Copy(), tx, session.Id); err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	responses.Success(c, "Two-factor verified", &gin.H{
+		"valid_for_seconds": config.Config.Auth.TwoFactorReverifySeconds,
+	})
+}
+
+// AuthLoginTwoFactor exchanges a login challenge and a second-factor code for a session
+func (s *Server) AuthLoginTwoFactor(c *gin.Context) {
+	var req AuthLoginTwoFactorRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.BadRequest(c, "Invalid request payload", nil)
+		return
+	}
+
+	// The attempt counter must persist even when the code is wrong, so the
+	// challenge is claimed outside the login transaction.
+	userId, authMethod, err := core.ClaimTwoFactorChallenge(c.Copy(), s.Dependencies.DB, req.ChallengeToken)
+	if err != nil {
+		if errors.Is(err, core.ErrTwoFactorChallengeExpired) {
+			responses.Unauthorized(c, "Login challenge expired, please log in again", nil)
+			return
+		}
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	tx, err := s.Dependencies.DB.BeginTx(c.Copy(), nil)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+	defer tx.Rollback()
+
+	method, err := core.VerifyTwoFactorCode(c.Copy(), tx, userId, req.Code)
+	if err != nil {
+		if errors.Is(err, core.ErrInvalidTwoFactorCode) || errors.Is(err, core.ErrTwoFactorNotEnrolled) {
+			log.Warn().Str("user_id", userId.String()).Str("client_ip", c.ClientIP()).Msg("Two-factor login failed")
+			responses.Unauthorized(c, "invalid verification code", nil)
+			return
+		}
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	if err := core.DeleteTwoFactorChallenge(c.Copy(), tx, req.ChallengeToken); err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	session, err := core.CreateSession(c.Copy(), tx, userId, c.ClientIP(), time.Hour*24, authMethod)
+	if err != nil {
+		log.Error().Err(err).Msg("CreateSession failed")
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	if err := core.MarkSessionTwoFactorVerified(c.Copy(), tx, session.Id); err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("login transaction commit failed")
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	if method == "recovery_code" {
+		s.CreateAuditLog(c.Request.Context(), nil, &userId, "auth:two_factor:recovery_code_used", map[string]interface{}{
+			"client_ip": c.ClientIP(),
+		})
+	}
+
+	responses.Success(c, "User logged in successfully", &gin.H{
+		"session": gin.H{
+			"token":      session.Token,
+			"expires_at": session.ExpiresAt,
+		},
+	})
+}
+
+// SecurityPolicyGet returns the instance-wide security policy
+func (s *Server) SecurityPolicyGet(c *gin.Context) {
+	policy, err := core.GetSecurityPolicy(c.Request.Context(), s.Dependencies.DB)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	responses.Success(c, "Security policy fetched successfully", &gin.H{"policy": policy})
+}
+
+// SecurityPolicyUpdate updates the instance-wide security policy
+func (s *Server) SecurityPolicyUpdate(c *gin.Context) {
+	session := c.MustGet("session").(*models.Session)
+	var req SecurityPolicyUpdateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.BadRequest(c, "Invalid request payload", nil)
+		return
+	}
+
+	policy := &models.SecurityPolicy{
+		RequireTwoFactorForPrivileged: req.RequireTwoFactorForPrivileged,
+	}
+
+	if err := core.UpdateSecurityPolicy(c.Request.Context(), s.Dependencies.DB, policy, session.UserId); err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), nil, &session.UserId, "sudo:security_policy:update", req)
+
+	responses.SimpleSuccess(c, "Security policy updated successfully")
+}
+
+// enforceTwoFactorPolicy aborts the request when it needs a privileged
+// permission and the security policy requires the user to enroll in 2FA
+// first. It returns false if the request was aborted.
+func (s *Server) enforceTwoFactorPolicy(c *gin.Context, user *models.User, privileged bool) bool {
+	if !privileged {
+		return true
+	}
+
+	policy, err := core.GetSecurityPolicy(c.Request.Context(), s.Dependencies.DB)
+	if err != nil {
+		responses.InternalServerError(c, fmt.Errorf("failed to load security policy: %w", err), nil)
+		c.Abort()
+		return false
+	}
+	if !policy.RequireTwoFactorForPrivileged {
+		return true
+	}
+
+	enabled, err := core.IsTwoFactorEnabled(c.Request.Context(), s.Dependencies.DB, user.Id)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		c.Abort()
+		return false
+	}
+	if !enabled {
+		responses.Forbidden(c, "Two-factor authentication must be enabled to use this feature", &gin.H{
+			"reason": "two_factor_enrollment_required",
+		})
+		c.Abort()
+		return false
+	}
+
+	return true
+}
+
+// RequireRecentTwoFactor forces users with 2FA enabled (or required by policy)
+// to have verified their second factor within the configured window before
+// running a sensitive action.
+func (s *Server) RequireRecentTwoFactor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		session := c.MustGet("session").(*models.Session)
+		ctx := c.Request.Context()
+
+		user, err := core.GetUserById(ctx, s.Dependencies.DB, session.UserId, &session.UserId)
+		if err != nil {
+			responses.Unauthorized(c, "Unauthorized", nil)
+			c.Abort()
+			return
+		}
+
+		enabled, err := core.IsTwoFactorEnabled(ctx, s.Dependencies.DB, user.Id)
+		if err != nil {
+			responses.InternalServerError(c, err, nil)
+			c.Abort()
+			return
+		}
+
+		if !enabled {
+			required, err := core.UserNeedsTwoFactorEnrollment(ctx, s.Dependencies.DB, user)
+			if err != nil {
+				responses.InternalServerError(c, err, nil)
+				c.Abort()
+				return
+			}
+			if required {
+				responses.Forbidden(c, "Two-factor authentication must be enabled to use this feature", &gin.H{
+					"reason": "two_factor_enrollment_required",
+				})
+				c.Abort()
+				return
+			}
+			c.Next()
+			return
+		}
+
+		window := time.Duration(config.Config.Auth.TwoFactorReverifySeconds) * time.Second
+		fresh, err := core.IsSessionTwoFactorFresh(ctx, s.Dependencies.DB, session.Id, window)
+		if err != nil {
+			responses.InternalServerError(c, err, nil)
+			c.Abort()
+			return
+		}
+
+		if !fresh {
+			responses.Forbidden(c, "Please re-verify your two-factor code to continue", &gin.H{
+				"reason": "two_factor_reverification_required",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// anyPrivileged reports whether at least one of perms is covered by the
+// require-2FA policy.
+func anyPrivileged(perms ...permissions.Permission) bool {
+	for _, perm := range perms {
+		if core.IsPrivilegedPermission(string(perm)) {
+			return true
+		}
+	}
+	return false
+}
+
+// allPrivileged reports whether every one of perms is covered by the
+// require-2FA policy, i.e. there is no unprivileged way to satisfy an
+// any-of check.
+func allPrivileged(perms ...permissions.Permission) bool {
+	for _, perm := range perms {
+		if !core.IsPrivilegedPermission(string(perm)) {
+			return false
+		}
+	}
+	return len(perms) > 0
+}
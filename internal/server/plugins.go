@@ -3,6 +3,7 @@ package server
 import (
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -209,9 +210,11 @@ func (s *Server) ServerPluginCreate(c *gin.Context) {
 	}
 
 	var request struct {
-		PluginID string                 `json:"plugin_id" binding:"required"`
-		Notes    string                 `json:"notes"`
-		Config   map[string]interface{} `json:"config"`
+		PluginID         string                           `json:"plugin_id" binding:"required"`
+		Notes            string                           `json:"notes"`
+		Config           map[string]interface{}           `json:"config"`
+		CapabilityGrants map[string]bool                  `json:"capability_grants"`
+		RconPolicy       plugin_manager.RconCommandPolicy `json:"rcon_policy"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -223,15 +226,40 @@ func (s *Server) ServerPluginCreate(c *gin.Context) {
 		request.Config = make(map[string]interface{})
 	}
 
-	instance, err := s.Dependencies.PluginManager.CreatePluginInstance(serverID, request.PluginID, request.Notes, request.Config)
+	instance, err := s.Dependencies.PluginManager.CreatePluginInstance(serverID, request.PluginID, request.Notes, request.Config, request.CapabilityGrants, request.RconPolicy)
 	if err != nil {
+		if respondCapabilityApprovalRequired(c, err) {
+			return
+		}
 		responses.BadRequest(c, "Failed to create plugin instance", &gin.H{"error": err.Error()})
 		return
 	}
 
+	s.CreateAuditLog(c.Request.Context(), &serverID, s.pluginAuditActorID(c), "plugin:instance:create", gin.H{
+		"instance_id":       instance.ID,
+		"plugin_id":         instance.PluginID,
+		"capability_grants": instance.CapabilityGrants,
+		"rcon_policy":       instance.RconPolicy,
+	})
+
 	responses.Success(c, "Plugin instance created successfully", &gin.H{"plugin": instance})
 }
 
+// respondCapabilityApprovalRequired writes a 400 listing the capabilities
+// awaiting operator review, returning false for other errors.
+func respondCapabilityApprovalRequired(c *gin.Context, err error) bool {
+	var approvalErr *plugin_manager.CapabilityApprovalError
+	if !errors.As(err, &approvalErr) {
+		return false
+	}
+	responses.BadRequest(c, "Plugin capabilities need approval", &gin.H{
+		"error":                     err.Error(),
+		"needs_capability_approval": true,
+		"pending_capabilities":      approvalErr.Pending,
+	})
+	return true
+}
+
 // ServerPluginGet returns a specific plugin instance
 func (s *Server) ServerPluginGet(c *gin.Context) {
 	if !s.requirePluginManager(c) {
@@ -331,15 +359,78 @@ func (s *Server) ServerPluginEnable(c *gin.Context) {
 		return
 	}
 
-	if err := s.Dependencies.PluginManager.EnablePluginInstance(serverID, instanceID); err != nil {
+	// The body is optional; it carries capability grant decisions
+	var request struct {
+		CapabilityGrants map[string]bool `json:"capability_grants"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil && !errors.Is(err, io.EOF) {
+		responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.Dependencies.PluginManager.EnablePluginInstance(serverID, instanceID, request.CapabilityGrants); err != nil {
+		if respondCapabilityApprovalRequired(c, err) {
+			return
+		}
 		responses.BadRequest(c, "Failed to enable plugin instance", &gin.H{"error": err.Error()})
 		return
 	}
 
+	if len(request.CapabilityGrants) > 0 {
+		s.CreateAuditLog(c.Request.Context(), &serverID, s.pluginAuditActorID(c), "plugin:instance:capability_grants", gin.H{
+			"instance_id":       instanceID,
+			"capability_grants": request.CapabilityGrants,
+		})
+	}
+
 	log.Info().Str("server_id", serverID.String()).Str("plugin_id", instanceID.String()).Msg("Enabled plugin instance")
 	responses.Success(c, "Plugin instance enabled successfully", nil)
 }
 
+// ServerPluginRconPolicyUpdate replaces a plugin instance's RCON command
+// policy. It applies to the next command the plugin sends.
+func (s *Server) ServerPluginRconPolicyUpdate(c *gin.Context) {
+	if !s.requirePluginManager(c) {
+		return
+	}
+
+	serverID, err := uuid.Parse(c.Param("serverId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
+		return
+	}
+
+	instanceID, err := uuid.Parse(c.Param("pluginId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid plugin instance ID", &gin.H{"error": err.Error()})
+		return
+	}
+
+	var policy plugin_manager.RconCommandPolicy
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.Dependencies.PluginManager.UpdatePluginRconPolicy(serverID, instanceID, policy); err != nil {
+		responses.BadRequest(c, "Failed to update plugin RCON policy", &gin.H{"error": err.Error()})
+		return
+	}
+
+	instance, err := s.Dependencies.PluginManager.GetPluginInstance(serverID, instanceID)
+	if err != nil {
+		responses.NotFound(c, "Plugin instance not found", &gin.H{"error": err.Error()})
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), &serverID, s.pluginAuditActorID(c), "plugin:instance:rcon_policy", gin.H{
+		"instance_id": instanceID,
+		"rcon_policy": instance.RconPolicy,
+	})
+
+	responses.Success(c, "Plugin RCON policy updated successfully", &gin.H{"rcon_policy": instance.RconPolicy})
+}
+
 // ServerPluginDisable disables a plugin instance
 func (s *Server) ServerPluginDisable(c *gin.Context) {
 	if !s.requirePluginManager(c) {
@@ -442,9 +533,10 @@ func (s *Server) ServerPluginLogs(c *gin.Context) {
 	responses.Success(c, "Plugin logs fetched successfully", &gin.H{"logs": logs})
 }
 
-// ServerPluginMetrics returns metrics for a plugin instance. The body is
-// currently empty; the route is kept for client compatibility while plugin
-// metrics are reworked. Use the logs endpoints for per-plugin runtime data.
+// ServerPluginMetrics returns the resource accounting for a plugin instance:
+// subprocess CPU and RSS, HostAPI calls by method, RCON commands, event
+// latency, panics, and the quarantine record if the instance was disabled
+// for exceeding a threshold.
 func (s *Server) ServerPluginMetrics(c *gin.Context) {
 	if !s.requirePluginManager(c) {
 		return
@@ -462,14 +554,13 @@ func (s *Server) ServerPluginMetrics(c *gin.Context) {
 		return
 	}
 
-	// Verify the plugin instance exists and belongs to this server before
-	// returning success so a 404 path is preserved for invalid IDs.
-	if _, err := s.Dependencies.PluginManager.GetPluginInstance(serverID, instanceID); err != nil {
+	metrics, err := s.Dependencies.PluginManager.GetPluginInstanceMetrics(serverID, instanceID)
+	if err != nil {
 		responses.NotFound(c, "Plugin instance not found", &gin.H{"error": err.Error()})
 		return
 	}
 
-	responses.Success(c, "Plugin metrics fetched successfully", &gin.H{"metrics": &gin.H{}})
+	responses.Success(c, "Plugin metrics fetched successfully", &gin.H{"metrics": metrics})
 }
 
 // ServerPluginLogsAll returns aggregated logs for all plugin instances for a server
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"go.codycody31.dev/squad-aegis/internal/plugin_manager"
+	"go.codycody31.dev/squad-aegis/internal/server/responses"
+)
+
+// PluginCatalogInstallRequest selects a catalog plugin to install or upgrade.
+// An empty version installs the newest version available for this host.
+type PluginCatalogInstallRequest struct {
+	PluginID string `json:"plugin_id" binding:"required"`
+	Version  string `json:"version"`
+}
+
+// PluginCatalogList returns the plugins offered by the configured catalogs
+// with their install and update state
+func (s *Server) PluginCatalogList(c *gin.Context) {
+	if !s.requirePluginManager(c) {
+		return
+	}
+
+	pm := s.Dependencies.PluginManager
+	responses.Success(c, "Plugin catalog fetched successfully", &gin.H{
+		"plugins":  pm.ListPluginCatalog(),
+		"catalogs": pm.PluginCatalogStatuses(),
+	})
+}
+
+// PluginCatalogRefresh re-fetches the configured catalogs
+func (s *Server) PluginCatalogRefresh(c *gin.Context) {
+	if !s.requirePluginManager(c) {
+		return
+	}
+
+	pm := s.Dependencies.PluginManager
+	catalogs := pm.RefreshPluginCatalogs(c.Request.Context())
+	responses.Success(c, "Plugin catalog refreshed successfully", &gin.H{
+		"plugins":  pm.ListPluginCatalog(),
+		"catalogs": catalogs,
+	})
+}
+
+// PluginCatalogInstall downloads and installs a plugin from a catalog. When
+// the plugin is already installed this upgrades it in place.
+func (s *Server) PluginCatalogInstall(c *gin.Context) {
+	if !s.requirePluginManager(c) {
+		return
+	}
+
+	var req PluginCatalogInstallRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+		return
+	}
+	req.PluginID = strings.TrimSpace(req.PluginID)
+	req.Version = strings.TrimSpace(req.Version)
+
+	pm := s.Dependencies.PluginManager
+	previousVersion := ""
+	for _, pkg := range pm.ListInstalledPluginPackages() {
+		if pkg.PluginID == req.PluginID {
+			previousVersion = pkg.Version
+			break
+		}
+	}
+
+	pkg, err := pm.InstallPluginFromCatalog(c.Request.Context(), req.PluginID, req.Version)
+	if err != nil {
+		log.Error().Err(err).Str("plugin_id", req.PluginID).Str("version", req.Version).Msg("Failed to install plugin from catalog")
+		s.CreateAuditLog(c.Request.Context(), nil, s.pluginAuditActorID(c), "plugin:package:catalog_install_failed", gin.H{
+			"plugin_id": req.PluginID,
+			"version":   req.Version,
+			"error":     err.Error(),
+		})
+		if errors.Is(err, plugin_manager.ErrCatalogPluginNotFound) {
+			responses.NotFound(c, err.Error(), &gin.H{"error": err.Error()})
+			return
+		}
+		responses.BadRequest(c, "Failed to install plugin from catalog", &gin.H{"error": err.Error()})
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), nil, s.pluginAuditActorID(c), "plugin:package:catalog_install", gin.H{
+		"plugin_id":            pkg.PluginID,
+		"version":              pkg.Version,
+		"previous_version":     previousVersion,
+		"signature_key_id":     pkg.SignatureKeyID,
+		"signature_expires_at": pkg.SignatureExpiresAt,
+		"install_state":        pkg.InstallState,
+	})
+
+	message := "Plugin installed successfully"
+	if pkg.InstallState == plugin_manager.PluginInstallStatePendingRestart {
+		message = "Plugin installed successfully and will activate after restart"
+	}
+
+	responses.Success(c, message, &gin.H{"plugin": pkg})
+}
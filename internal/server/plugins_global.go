@@ -0,0 +1,268 @@
+package server
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"go.codycody31.dev/squad-aegis/internal/plugin_manager"
+	"go.codycody31.dev/squad-aegis/internal/server/responses"
+)
+
+// Global plugin instances span several servers, so they are managed by
+// super admins outside any one server's routes. Their audit entries carry
+// no server.
+
+// globalPluginInstanceID parses the instance ID of a global plugin route,
+// writing a 400 when it is malformed.
+func globalPluginInstanceID(c *gin.Context) (uuid.UUID, bool) {
+	instanceID, err := uuid.Parse(c.Param("pluginId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid plugin instance ID", &gin.H{"error": err.Error()})
+		return uuid.Nil, false
+	}
+	return instanceID, true
+}
+
+// GlobalPluginList returns every global plugin instance
+func (s *Server) GlobalPluginList(c *gin.Context) {
+	if !s.requirePluginManager(c) {
+		return
+	}
+
+	plugins := s.Dependencies.PluginManager.GetGlobalPluginInstances()
+	responses.Success(c, "Global plugins fetched successfully", &gin.H{"plugins": plugins})
+}
+
+// GlobalPluginCreate creates a plugin instance that receives events from
+// every server in server_ids
+func (s *Server) GlobalPluginCreate(c *gin.Context) {
+	if !s.requirePluginManager(c) {
+		return
+	}
+
+	var request struct {
+		PluginID         string                           `json:"plugin_id" binding:"required"`
+		ServerIDs        []uuid.UUID                      `json:"server_ids" binding:"required"`
+		Notes            string                           `json:"notes"`
+		Config           map[string]interface{}           `json:"config"`
+		CapabilityGrants map[string]bool                  `json:"capability_grants"`
+		RconPolicy       plugin_manager.RconCommandPolicy `json:"rcon_policy"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+		return
+	}
+
+	if request.Config == nil {
+		request.Config = make(map[string]interface{})
+	}
+
+	instance, err := s.Dependencies.PluginManager.CreateGlobalPluginInstance(request.ServerIDs, request.PluginID, request.Notes, request.Config, request.CapabilityGrants, request.RconPolicy)
+	if err != nil {
+		if respondCapabilityApprovalRequired(c, err) {
+			return
+		}
+		responses.BadRequest(c, "Failed to create global plugin instance", &gin.H{"error": err.Error()})
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), nil, s.pluginAuditActorID(c), "plugin:global:create", gin.H{
+		"instance_id":       instance.ID,
+		"plugin_id":         instance.PluginID,
+		"server_ids":        instance.ServerIDs,
+		"capability_grants": instance.CapabilityGrants,
+		"rcon_policy":       instance.RconPolicy,
+	})
+
+	responses.Success(c, "Global plugin instance created successfully", &gin.H{"plugin": instance})
+}
+
+// GlobalPluginGet returns a global plugin instance
+func (s *Server) GlobalPluginGet(c *gin.Context) {
+	if !s.requirePluginManager(c) {
+		return
+	}
+
+	instanceID, ok := globalPluginInstanceID(c)
+	if !ok {
+		return
+	}
+
+	instance, err := s.Dependencies.PluginManager.GetPluginInstance(plugin_manager.GlobalServerID, instanceID)
+	if err != nil {
+		responses.NotFound(c, "Plugin instance not found", &gin.H{"error": err.Error()})
+		return
+	}
+
+	responses.Success(c, "Global plugin instance fetched successfully", &gin.H{"plugin": instance})
+}
+
+// GlobalPluginUpdate updates a global plugin instance's config or log level
+func (s *Server) GlobalPluginUpdate(c *gin.Context) {
+	if !s.requirePluginManager(c) {
+		return
+	}
+
+	instanceID, ok := globalPluginInstanceID(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Config   map[string]interface{} `json:"config"`
+		LogLevel *string                `json:"log_level"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+		return
+	}
+
+	if request.Config == nil && request.LogLevel == nil {
+		responses.BadRequest(c, "At least one of config or log_level must be provided", &gin.H{})
+		return
+	}
+
+	if request.Config != nil {
+		if err := s.Dependencies.PluginManager.UpdatePluginConfig(plugin_manager.GlobalServerID, instanceID, request.Config); err != nil {
+			responses.BadRequest(c, "Failed to update plugin instance config", &gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if request.LogLevel != nil {
+		if err := s.Dependencies.PluginManager.UpdatePluginLogLevel(plugin_manager.GlobalServerID, instanceID, *request.LogLevel); err != nil {
+			responses.BadRequest(c, "Failed to update plugin instance log level", &gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	log.Info().Str("plugin_id", instanceID.String()).Msg("Updated global plugin instance configuration")
+	responses.Success(c, "Global plugin instance updated successfully", nil)
+}
+
+// GlobalPluginServersUpdate replaces the servers a global plugin instance
+// receives events from and may act on
+func (s *Server) GlobalPluginServersUpdate(c *gin.Context) {
+	if !s.requirePluginManager(c) {
+		return
+	}
+
+	instanceID, ok := globalPluginInstanceID(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		ServerIDs []uuid.UUID `json:"server_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.Dependencies.PluginManager.UpdateGlobalPluginServers(instanceID, request.ServerIDs); err != nil {
+		responses.BadRequest(c, "Failed to update global plugin servers", &gin.H{"error": err.Error()})
+		return
+	}
+
+	instance, err := s.Dependencies.PluginManager.GetPluginInstance(plugin_manager.GlobalServerID, instanceID)
+	if err != nil {
+		responses.NotFound(c, "Plugin instance not found", &gin.H{"error": err.Error()})
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), nil, s.pluginAuditActorID(c), "plugin:global:servers", gin.H{
+		"instance_id": instanceID,
+		"server_ids":  instance.ServerIDs,
+	})
+
+	responses.Success(c, "Global plugin servers updated successfully", &gin.H{"plugin": instance})
+}
+
+// GlobalPluginEnable enables a global plugin instance
+func (s *Server) GlobalPluginEnable(c *gin.Context) {
+	if !s.requirePluginManager(c) {
+		return
+	}
+
+	instanceID, ok := globalPluginInstanceID(c)
+	if !ok {
+		return
+	}
+
+	// The body is optional; it carries capability grant decisions
+	var request struct {
+		CapabilityGrants map[string]bool `json:"capability_grants"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil && !errors.Is(err, io.EOF) {
+		responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.Dependencies.PluginManager.EnablePluginInstance(plugin_manager.GlobalServerID, instanceID, request.CapabilityGrants); err != nil {
+		if respondCapabilityApprovalRequired(c, err) {
+			return
+		}
+		responses.BadRequest(c, "Failed to enable plugin instance", &gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(request.CapabilityGrants) > 0 {
+		s.CreateAuditLog(c.Request.Context(), nil, s.pluginAuditActorID(c), "plugin:global:capability_grants", gin.H{
+			"instance_id":       instanceID,
+			"capability_grants": request.CapabilityGrants,
+		})
+	}
+
+	log.Info().Str("plugin_id", instanceID.String()).Msg("Enabled global plugin instance")
+	responses.Success(c, "Global plugin instance enabled successfully", nil)
+}
+
+// GlobalPluginDisable disables a global plugin instance
+func (s *Server) GlobalPluginDisable(c *gin.Context) {
+	if !s.requirePluginManager(c) {
+		return
+	}
+
+	instanceID, ok := globalPluginInstanceID(c)
+	if !ok {
+		return
+	}
+
+	if err := s.Dependencies.PluginManager.DisablePluginInstance(plugin_manager.GlobalServerID, instanceID); err != nil {
+		responses.BadRequest(c, "Failed to disable plugin instance", &gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Info().Str("plugin_id", instanceID.String()).Msg("Disabled global plugin instance")
+	responses.Success(c, "Global plugin instance disabled successfully", nil)
+}
+
+// GlobalPluginDelete deletes a global plugin instance
+func (s *Server) GlobalPluginDelete(c *gin.Context) {
+	if !s.requirePluginManager(c) {
+		return
+	}
+
+	instanceID, ok := globalPluginInstanceID(c)
+	if !ok {
+		return
+	}
+
+	if err := s.Dependencies.PluginManager.DeletePluginInstance(plugin_manager.GlobalServerID, instanceID); err != nil {
+		responses.BadRequest(c, "Failed to delete plugin instance", &gin.H{"error": err.Error()})
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), nil, s.pluginAuditActorID(c), "plugin:global:delete", gin.H{
+		"instance_id": instanceID,
+	})
+
+	log.Info().Str("plugin_id", instanceID.String()).Msg("Deleted global plugin instance")
+	responses.Success(c, "Global plugin instance deleted successfully", nil)
+}
@@ -9,10 +9,12 @@ import (
 	"strings"
 	"sync"
 
+	"go.codycody31.dev/squad-aegis/internal/audit"
 	"go.codycody31.dev/squad-aegis/internal/clickhouse"
 	"go.codycody31.dev/squad-aegis/internal/core"
 	"go.codycody31.dev/squad-aegis/internal/event_manager"
 	"go.codycody31.dev/squad-aegis/internal/logwatcher_manager"
+	"go.codycody31.dev/squad-aegis/internal/oidc"
 	"go.codycody31.dev/squad-aegis/internal/permissions"
 	"go.codycody31.dev/squad-aegis/internal/plugin_manager"
 	"go.codycody31.dev/squad-aegis/internal/rcon_manager"
@@ -20,6 +22,7 @@ import (
 	"go.codycody31.dev/squad-aegis/internal/shared/config"
 	"go.codycody31.dev/squad-aegis/internal/storage"
 	"go.codycody31.dev/squad-aegis/internal/valkey"
+	"go.codycody31.dev/squad-aegis/internal/webhook_dispatcher"
 	"go.codycody31.dev/squad-aegis/internal/workflow_manager"
 
 	"github.com/gin-gonic/gin"
@@ -31,6 +34,10 @@ type Server struct {
 	// bansCfgMu provides per-server locking for Bans.cfg read-modify-write
 	// cycles. Keyed by server UUID string, values are *sync.Mutex.
 	bansCfgMu sync.Map
+
+	// configFilesMu serializes reads and writes of managed ServerConfig
+	// files. Keyed by "serverID/fileName", values are *sync.Mutex.
+	configFilesMu sync.Map
 }
 
 type Dependencies struct {
@@ -46,6 +53,10 @@ type Dependencies struct {
 	Storage              storage.Storage
 	PermissionService    *permissions.Service
 	PermissionRepo       *permissions.Repository
+	OIDCProvider         *oidc.Provider  // nil when SSO is disabled
+	AuditStreamer        *audit.Streamer // nil when no audit sinks are configured
+	AuditSigner          *audit.Signer   // nil when checkpoint signing is disabled
+	WebhookDispatcher    *webhook_dispatcher.Dispatcher
 }
 
 func New(serverDependencies *Dependencies) *Server {
@@ -132,6 +143,20 @@ func NewRouter(server *Server) *gin.Engine {
 			authGroup.PATCH("/me/password", server.AuthSession, server.UpdateUserPassword)
 			authGroup.POST("/logout", server.AuthSession, server.AuthLogout)
 
+			// Two-factor authentication
+			twoFactorLimit := RateLimitMiddleware(10.0/60, 5)
+			authGroup.GET("/2fa", server.AuthSession, server.AuthTwoFactorStatus)
+			authGroup.POST("/2fa/enroll", server.AuthSession, twoFactorLimit, server.AuthTwoFactorEnroll)
+			authGroup.POST("/2fa/enable", server.AuthSession, twoFactorLimit, server.AuthTwoFactorEnable)
+			authGroup.POST("/2fa/disable", server.AuthSession, twoFactorLimit, server.AuthTwoFactorDisable)
+			authGroup.POST("/2fa/recovery-codes", server.AuthSession, twoFactorLimit, server.AuthTwoFactorRegenerateRecoveryCodes)
+			authGroup.POST("/2fa/verify", server.AuthSession, twoFactorLimit, server.AuthTwoFactorVerify)
+
+			// Personal API tokens
+			authGroup.GET("/tokens", server.AuthSession, server.AuthAPITokensList)
+			authGroup.POST("/tokens", server.AuthSession, server.AuthAPITokenCreate)
+			authGroup.DELETE("/tokens/:tokenId", server.AuthSession, server.AuthAPITokenRevoke)
+
 			authGroup.Use(func(c *gin.Context) {
 				if IsLoggedIn(c) {
 					c.JSON(http.StatusUnauthorized, gin.H{
@@ -143,6 +168,12 @@ func NewRouter(server *Server) *gin.Engine {
 				}
 			})
 			authGroup.POST("/login", RateLimitMiddleware(5.0/60, 5), server.AuthLogin)
+			authGroup.POST("/login/2fa", RateLimitMiddleware(5.0/60, 5), server.AuthLoginTwoFactor)
+
+			// Single sign-on
+			authGroup.GET("/oidc", server.AuthOIDCConfig)
+			authGroup.GET("/oidc/login", RateLimitMiddleware(10.0/60, 5), server.AuthOIDCLogin)
+			authGroup.GET("/oidc/callback", RateLimitMiddleware(10.0/60, 5), server.AuthOIDCCallback)
 		}
 
 		usersGroup := apiGroup.Group("/users")
@@ -154,6 +185,7 @@ func NewRouter(server *Server) *gin.Engine {
 			usersGroup.POST("", server.UserCreate)
 			usersGroup.PUT("/:userId", server.UserUpdate)
 			usersGroup.DELETE("/:userId", server.UserDelete)
+			usersGroup.DELETE("/:userId/2fa", server.RequireRecentTwoFactor(), server.UserTwoFactorReset)
 		}
 
 		// Permission system routes
@@ -226,19 +258,19 @@ func NewRouter(server *Server) *gin.Engine {
 
 			serverGroup := serversGroup.Group("/:serverId")
 			{
-				serverGroup.GET("", server.ServerGet)
+				serverGroup.GET("", server.RejectAPIToken(), server.ServerGet)
 				serverGroup.PUT("", server.RequirePermission(permissions.UISettingsManage), server.ServerUpdate)
 				serverGroup.DELETE("", server.AuthIsSuperAdmin(), server.ServerDelete)
 
-				serverGroup.GET("/metrics", server.ServerMetrics)
-				serverGroup.GET("/metrics/history", server.ServerMetricsHistory)
-				serverGroup.GET("/status", server.ServerStatus)
+				serverGroup.GET("/metrics", server.RejectAPIToken(), server.ServerMetrics)
+				serverGroup.GET("/metrics/history", server.RejectAPIToken(), server.ServerMetricsHistory)
+				serverGroup.GET("/status", server.RejectAPIToken(), server.ServerStatus)
 				serverGroup.GET("/audit-logs", server.RequirePermission(permissions.UIAuditLogsView), server.ServerAuditLogs)
 
-				serverGroup.GET("/rcon/commands", server.RconCommandList)
-				serverGroup.GET("/rcon/commands/autocomplete", server.RconCommandAutocomplete)
+				serverGroup.GET("/rcon/commands", server.RejectAPIToken(), server.RconCommandList)
+				serverGroup.GET("/rcon/commands/autocomplete", server.RejectAPIToken(), server.RconCommandAutocomplete)
 				serverGroup.POST("/rcon/execute", server.RequirePermission(permissions.UIConsoleExecute), server.ServerRconExecute)
-				serverGroup.GET("/rcon/server-population", server.ServerRconServerPopulation)
+				serverGroup.GET("/rcon/server-population", server.RejectAPIToken(), server.ServerRconServerPopulation)
 				serverGroup.GET("/rcon/available-layers", server.RequirePermission(permissions.UIMapsChange), server.ServerRconAvailableLayers)
 				serverGroup.POST("/rcon/change-layer", server.RequirePermission(permissions.UIMapsChange), server.ServerRconChangeLayer)
 				serverGroup.POST("/rcon/set-next-layer", server.RequirePermission(permissions.UIMapsChange), server.ServerRconSetNextLayer)
@@ -300,7 +332,7 @@ func NewRouter(server *Server) *gin.Engine {
 				}
 
 				// Server info endpoints
-				serverGroup.GET("/rcon/server-info", server.ServerRconServerInfo)
+				serverGroup.GET("/rcon/server-info", server.RejectAPIToken(), server.ServerRconServerInfo)
 
 				// Plugin management routes for specific servers
 				pluginGroup := serverGroup.Group("/plugins")
@@ -316,12 +348,13 @@ func NewRouter(server *Server) *gin.Engine {
 					pluginGroup.PUT("/:pluginId", pluginManagePerm, server.ServerPluginUpdate)
 					pluginGroup.POST("/:pluginId/enable", pluginManagePerm, server.ServerPluginEnable)
 					pluginGroup.POST("/:pluginId/disable", pluginManagePerm, server.ServerPluginDisable)
+					pluginGroup.PUT("/:pluginId/rcon-policy", pluginManagePerm, server.ServerPluginRconPolicyUpdate)
 					pluginGroup.DELETE("/:pluginId", pluginManagePerm, server.ServerPluginDelete)
 					pluginGroup.GET("/:pluginId/logs", pluginManagePerm, server.ServerPluginLogs)
 					pluginGroup.GET("/:pluginId/logs/ws", pluginManagePerm, server.ServerPluginLogsWebSocket)
-					// Compatibility route: clients rely on the route returning
-					// 200 even though it currently emits an empty object.
 					pluginGroup.GET("/:pluginId/metrics", pluginViewPerm, server.ServerPluginMetrics)
+					pluginGroup.GET("/:pluginId/panels", pluginViewPerm, server.ServerPluginPanels)
+					pluginGroup.Any("/:pluginId/http/*path", pluginViewPerm, server.ServerPluginHTTP)
 					pluginGroup.GET("/:pluginId/data", pluginManagePerm, server.ServerPluginDataGet)
 					pluginGroup.POST("/:pluginId/data", pluginManagePerm, server.ServerPluginDataSet)
 					pluginGroup.DELETE("/:pluginId/data", pluginManagePerm, server.ServerPluginDataClear)
@@ -341,6 +374,9 @@ func NewRouter(server *Server) *gin.Engine {
 					rulesGroup.PUT("/:ruleId", server.RequirePermission(permissions.UIRulesManage), server.updateServerRule)
 					rulesGroup.DELETE("/:ruleId", server.RequirePermission(permissions.UIRulesManage), server.deleteServerRule)
 					rulesGroup.PUT("/bulk", server.RequirePermission(permissions.UIRulesManage), server.bulkUpdateServerRules) // Bulk update endpoint
+					rulesGroup.GET("/translations", server.RequirePermission(permissions.UIRulesView), server.listServerRuleTranslations)
+					rulesGroup.PUT("/:ruleId/translations/:language", server.RequirePermission(permissions.UIRulesManage), server.upsertServerRuleTranslation)
+					rulesGroup.DELETE("/:ruleId/translations/:language", server.RequirePermission(permissions.UIRulesManage), server.deleteServerRuleTranslation)
 				}
 
 				// Server MOTD
@@ -353,6 +389,25 @@ func NewRouter(server *Server) *gin.Engine {
 					motdGroup.GET("/preview", server.RequirePermission(permissions.UIMOTDView), server.previewMOTD)
 					motdGroup.POST("/upload", motdManagePerm, server.uploadMOTD)
 					motdGroup.POST("/test-connection", motdManagePerm, server.testMOTDConnection)
+					motdGroup.GET("/schedules", server.RequirePermission(permissions.UIMOTDView), server.listMOTDSchedules)
+					motdGroup.POST("/schedules", motdManagePerm, server.createMOTDSchedule)
+					motdGroup.PUT("/schedules/:scheduleId", motdManagePerm, server.updateMOTDSchedule)
+					motdGroup.DELETE("/schedules/:scheduleId", motdManagePerm, server.deleteMOTDSchedule)
+				}
+
+				// Managed ServerConfig files. They can hold credentials such as
+				// the RCON password, so reading them also needs the manage permission.
+				configFilesGroup := serverGroup.Group("/config-files")
+				{
+					configFilesGroup.Use(server.RequirePermission(permissions.UISettingsManage))
+					configFilesGroup.GET("", server.ServerConfigFilesList)
+					configFilesGroup.GET("/:fileName", server.ServerConfigFileGet)
+					configFilesGroup.PUT("/:fileName", server.ServerConfigFileUpdate)
+					configFilesGroup.POST("/:fileName/preview", server.ServerConfigFilePreview)
+					configFilesGroup.POST("/:fileName/adopt", server.ServerConfigFileAdopt)
+					configFilesGroup.GET("/:fileName/revisions", server.ServerConfigFileRevisionsList)
+					configFilesGroup.GET("/:fileName/revisions/:revisionId", server.ServerConfigFileRevisionGet)
+					configFilesGroup.POST("/:fileName/revisions/:revisionId/restore", server.ServerConfigFileRevisionRestore)
 				}
 
 				// Server Workflows
@@ -361,15 +416,23 @@ func NewRouter(server *Server) *gin.Engine {
 					workflowsGroup.Use(server.RequirePermission(permissions.UIWorkflowsManage))
 					workflowsGroup.GET("", server.ServerWorkflowsList)
 					workflowsGroup.POST("", server.ServerWorkflowCreate)
+					workflowsGroup.POST("/import", server.ServerWorkflowImport)
 
 					workflowGroup := workflowsGroup.Group("/:workflowId")
 					{
 						workflowGroup.GET("", server.ServerWorkflowGet)
 						workflowGroup.PUT("", server.ServerWorkflowUpdate)
 						workflowGroup.DELETE("", server.ServerWorkflowDelete)
+						workflowGroup.GET("/export", server.ServerWorkflowExport)
 						workflowGroup.GET("/executions", server.ServerWorkflowExecutions)
 						workflowGroup.GET("/executions/stats", server.ServerWorkflowExecutionStats)
 
+						// Workflow revision history and rollback
+						workflowGroup.GET("/revisions", server.ServerWorkflowRevisionsList)
+						workflowGroup.GET("/revisions/diff", server.ServerWorkflowRevisionDiff)
+						workflowGroup.GET("/revisions/:revisionId", server.ServerWorkflowRevisionGet)
+						workflowGroup.POST("/revisions/:revisionId/rollback", server.ServerWorkflowRevisionRollback)
+
 						// Workflow execution details and logs
 						executionGroup := workflowGroup.Group("/executions/:executionId")
 						{
@@ -393,11 +456,48 @@ func NewRouter(server *Server) *gin.Engine {
 							kvGroup.GET("", server.ServerWorkflowKVList)
 							kvGroup.GET("/:key", server.ServerWorkflowKVGet)
 							kvGroup.POST("", server.ServerWorkflowKVSet)
+							kvGroup.POST("/increment", server.ServerWorkflowKVIncrement)
+							kvGroup.POST("/compare-and-set", server.ServerWorkflowKVCompareAndSet)
 							kvGroup.DELETE("/:key", server.ServerWorkflowKVDelete)
 							kvGroup.DELETE("", server.ServerWorkflowKVClear)
 						}
 					}
 				}
+
+				// KV namespaces shared between a server's workflows
+				kvNamespacesGroup := serverGroup.Group("/workflow-kv-namespaces")
+				{
+					kvNamespacesGroup.Use(server.RequirePermission(permissions.UIWorkflowsManage))
+					kvNamespacesGroup.GET("", server.ServerKVNamespacesList)
+					kvNamespacesGroup.POST("", server.ServerKVNamespaceCreate)
+
+					kvNamespaceGroup := kvNamespacesGroup.Group("/:namespaceId")
+					{
+						kvNamespaceGroup.GET("", server.ServerKVNamespaceGet)
+						kvNamespaceGroup.PUT("", server.ServerKVNamespaceUpdate)
+						kvNamespaceGroup.DELETE("", server.ServerKVNamespaceDelete)
+						kvNamespaceGroup.GET("/kv", server.ServerKVNamespaceEntriesList)
+						kvNamespaceGroup.GET("/kv/:key", server.ServerKVNamespaceEntryGet)
+						kvNamespaceGroup.POST("/kv", server.ServerKVNamespaceEntrySet)
+						kvNamespaceGroup.POST("/kv/increment", server.ServerKVNamespaceEntryIncrement)
+						kvNamespaceGroup.POST("/kv/compare-and-set", server.ServerKVNamespaceEntryCompareAndSet)
+						kvNamespaceGroup.DELETE("/kv/:key", server.ServerKVNamespaceEntryDelete)
+						kvNamespaceGroup.DELETE("/kv", server.ServerKVNamespaceEntriesClear)
+					}
+				}
+
+				// Shared Lua modules for workflow scripts
+				luaModulesGroup := serverGroup.Group("/lua-modules")
+				{
+					luaModulesGroup.Use(server.RequirePermission(permissions.UIWorkflowsManage))
+					luaModulesGroup.GET("", server.LuaModulesList)
+					luaModulesGroup.POST("", server.LuaModuleCreate)
+					luaModulesGroup.GET("/:moduleId", server.LuaModuleGet)
+					luaModulesGroup.PUT("/:moduleId", server.LuaModuleUpdate)
+					luaModulesGroup.DELETE("/:moduleId", server.LuaModuleDelete)
+					luaModulesGroup.GET("/:moduleId/versions/:version", server.LuaModuleVersionGet)
+					luaModulesGroup.POST("/:moduleId/test", server.LuaModuleTest)
+				}
 			}
 		}
 
@@ -410,6 +510,24 @@ func NewRouter(server *Server) *gin.Engine {
 			pluginsGroup.GET("/installed", server.AuthIsSuperAdmin(), server.PluginListInstalled)
 			pluginsGroup.POST("/upload", server.AuthIsSuperAdmin(), RateLimitMiddleware(10.0/60, 3), server.PluginUpload)
 			pluginsGroup.DELETE("/installed/:pluginId", server.AuthIsSuperAdmin(), server.PluginInstalledDelete)
+			pluginsGroup.GET("/catalog", server.AuthIsSuperAdmin(), server.PluginCatalogList)
+			pluginsGroup.POST("/catalog/refresh", server.AuthIsSuperAdmin(), RateLimitMiddleware(10.0/60, 3), server.PluginCatalogRefresh)
+			pluginsGroup.POST("/catalog/install", server.AuthIsSuperAdmin(), RateLimitMiddleware(10.0/60, 3), server.PluginCatalogInstall)
+
+			// Global plugin instances span several servers
+			globalPluginsGroup := pluginsGroup.Group("/global")
+			{
+				globalPluginsGroup.Use(server.AuthIsSuperAdmin())
+
+				globalPluginsGroup.GET("", server.GlobalPluginList)
+				globalPluginsGroup.POST("", server.GlobalPluginCreate)
+				globalPluginsGroup.GET("/:pluginId", server.GlobalPluginGet)
+				globalPluginsGroup.PUT("/:pluginId", server.GlobalPluginUpdate)
+				globalPluginsGroup.PUT("/:pluginId/servers", server.GlobalPluginServersUpdate)
+				globalPluginsGroup.POST("/:pluginId/enable", server.GlobalPluginEnable)
+				globalPluginsGroup.POST("/:pluginId/disable", server.GlobalPluginDisable)
+				globalPluginsGroup.DELETE("/:pluginId", server.GlobalPluginDelete)
+			}
 		}
 
 		connectorsGroup := apiGroup.Group("/connectors")
@@ -451,12 +569,22 @@ func NewRouter(server *Server) *gin.Engine {
 			sudoGroup.Use(server.AuthSession)
 			sudoGroup.Use(server.AuthIsSuperAdmin())
 
+			// Sensitive actions demand a recent second-factor verification
+			recentTwoFactor := server.RequireRecentTwoFactor()
+
+			// Security policy
+			sudoGroup.GET("/security/policy", server.SecurityPolicyGet)
+			sudoGroup.PUT("/security/policy", recentTwoFactor, server.SecurityPolicyUpdate)
+			sudoGroup.GET("/oidc/group-mappings", server.SudoOIDCGroupMappingsList)
+			sudoGroup.POST("/oidc/group-mappings", recentTwoFactor, server.SudoOIDCGroupMappingCreate)
+			sudoGroup.DELETE("/oidc/group-mappings/:mappingId", recentTwoFactor, server.SudoOIDCGroupMappingDelete)
+
 			// Storage management
 			sudoGroup.GET("/storage/summary", server.GetStorageSummary)
 			sudoGroup.GET("/storage/files", server.GetStorageFiles)
 			sudoGroup.GET("/storage/files/*path", server.DownloadStorageFile)
-			sudoGroup.DELETE("/storage/files/*path", server.DeleteStorageFile)
-			sudoGroup.POST("/storage/files/bulk-delete", server.BulkDeleteStorageFiles)
+			sudoGroup.DELETE("/storage/files/*path", recentTwoFactor, server.DeleteStorageFile)
+			sudoGroup.POST("/storage/files/bulk-delete", recentTwoFactor, server.BulkDeleteStorageFiles)
 
 			// Metrics and analytics
 			sudoGroup.GET("/metrics/overview", server.GetMetricsOverview)
@@ -471,20 +599,45 @@ func NewRouter(server *Server) *gin.Engine {
 			// Global audit logs
 			sudoGroup.GET("/audit/logs", server.GetGlobalAuditLogs)
 			sudoGroup.GET("/audit/stats", server.GetGlobalAuditStats)
-			sudoGroup.GET("/audit/export", server.ExportGlobalAuditLogs)
+			sudoGroup.GET("/audit/export", recentTwoFactor, server.ExportGlobalAuditLogs)
+			sudoGroup.GET("/audit/verify", server.VerifyAuditChains)
+			sudoGroup.GET("/audit/checkpoints", server.GetAuditCheckpoints)
+			sudoGroup.POST("/audit/checkpoints", server.CreateAuditCheckpoints)
 
 			// Session management
 			sudoGroup.GET("/sessions", server.GetAllSessions)
 			sudoGroup.GET("/sessions/stats", server.GetSessionStats)
-			sudoGroup.DELETE("/sessions/:sessionId", server.DeleteSession)
-			sudoGroup.DELETE("/sessions/user/:userId", server.DeleteUserSessions)
+			sudoGroup.DELETE("/sessions/:sessionId", recentTwoFactor, server.DeleteSession)
+			sudoGroup.DELETE("/sessions/user/:userId", recentTwoFactor, server.DeleteUserSessions)
 			sudoGroup.POST("/sessions/cleanup", server.CleanupExpiredSessions)
 
+			// Webhook subscriptions
+			sudoGroup.GET("/webhooks", server.SudoWebhooksList)
+			sudoGroup.POST("/webhooks", recentTwoFactor, server.SudoWebhookCreate)
+			sudoGroup.PUT("/webhooks/:webhookId", recentTwoFactor, server.SudoWebhookUpdate)
+			sudoGroup.DELETE("/webhooks/:webhookId", recentTwoFactor, server.SudoWebhookDelete)
+			sudoGroup.POST("/webhooks/:webhookId/ping", server.SudoWebhookPing)
+			sudoGroup.GET("/webhook-deliveries", server.SudoWebhookDeliveries)
+			sudoGroup.POST("/webhook-deliveries/:deliveryId/retry", server.SudoWebhookDeliveryRetry)
+
+			// Global Lua modules, available to every server's workflows
+			sudoGroup.GET("/lua-modules", server.LuaModulesList)
+			sudoGroup.POST("/lua-modules", server.LuaModuleCreate)
+			sudoGroup.GET("/lua-modules/:moduleId", server.LuaModuleGet)
+			sudoGroup.PUT("/lua-modules/:moduleId", server.LuaModuleUpdate)
+			sudoGroup.DELETE("/lua-modules/:moduleId", server.LuaModuleDelete)
+			sudoGroup.GET("/lua-modules/:moduleId/versions/:version", server.LuaModuleVersionGet)
+			sudoGroup.POST("/lua-modules/:moduleId/test", server.LuaModuleTest)
+
+			// API token management
+			sudoGroup.GET("/api-tokens", server.SudoAPITokensList)
+			sudoGroup.DELETE("/api-tokens/:tokenId", server.SudoAPITokenRevoke)
+
 			// Database statistics
 			sudoGroup.GET("/database/overview", server.GetDatabaseOverview)
 			sudoGroup.GET("/database/postgresql", server.GetPostgreSQLStats)
 			sudoGroup.GET("/database/clickhouse", server.GetClickHouseStats)
-			sudoGroup.POST("/database/optimize/:type", server.OptimizeDatabase)
+			sudoGroup.POST("/database/optimize/:type", recentTwoFactor, server.OptimizeDatabase)
 		}
 
 		// Public server config endpoints - intentionally unauthenticated.
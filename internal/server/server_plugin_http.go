@@ -0,0 +1,165 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/models"
+	"go.codycody31.dev/squad-aegis/internal/permissions"
+	"go.codycody31.dev/squad-aegis/internal/plugin_manager"
+	"go.codycody31.dev/squad-aegis/internal/server/responses"
+)
+
+// errPluginHTTPForbidden marks a plugin route the user cannot reach.
+var errPluginHTTPForbidden = errors.New("plugin route requires plugin manage permission")
+
+// canManageServerPlugins reports whether the request may use plugin routes
+// that need plugin manage permission. The route middleware has already
+// checked view access to the server.
+func (s *Server) canManageServerPlugins(c *gin.Context, user *models.User, serverID uuid.UUID) (bool, error) {
+	if len(apiTokenScope(c, permissions.UIPluginsManage)) == 0 {
+		return false, nil
+	}
+	if user.SuperAdmin {
+		return true, nil
+	}
+	return s.Dependencies.PermissionService.HasPermission(c.Request.Context(), user.Id, serverID, permissions.UIPluginsManage)
+}
+
+// ServerPluginPanels returns the dashboard panels a plugin instance
+// declares. Panels backed by manage routes are hidden from users who
+// cannot manage plugins.
+func (s *Server) ServerPluginPanels(c *gin.Context) {
+	if !s.requirePluginManager(c) {
+		return
+	}
+	user := s.getUserFromSession(c)
+	if user == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return
+	}
+
+	serverID, err := uuid.Parse(c.Param("serverId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
+		return
+	}
+	instanceID, err := uuid.Parse(c.Param("pluginId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid plugin instance ID", &gin.H{"error": err.Error()})
+		return
+	}
+
+	panels, err := s.Dependencies.PluginManager.GetPluginPanels(serverID, instanceID)
+	if err != nil {
+		responses.NotFound(c, "Plugin instance not found", nil)
+		return
+	}
+	canManage, err := s.canManageServerPlugins(c, user, serverID)
+	if err != nil {
+		responses.InternalServerError(c, fmt.Errorf("failed to check permissions: %w", err), nil)
+		return
+	}
+
+	visible := make([]plugin_manager.PluginPanel, 0, len(panels))
+	for _, panel := range panels {
+		if panel.Access == plugin_manager.PluginHTTPAccessManage && !canManage {
+			continue
+		}
+		visible = append(visible, panel)
+	}
+
+	responses.Success(c, "Plugin panels fetched successfully", &gin.H{"panels": visible, "can_manage": canManage})
+}
+
+// ServerPluginHTTP forwards a request to one of the routes a plugin
+// instance declares. Plugin responses are limited to data content types and
+// served with headers that stop browsers from executing them.
+func (s *Server) ServerPluginHTTP(c *gin.Context) {
+	if !s.requirePluginManager(c) {
+		return
+	}
+	user := s.getUserFromSession(c)
+	if user == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return
+	}
+
+	serverID, err := uuid.Parse(c.Param("serverId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
+		return
+	}
+	instanceID, err := uuid.Parse(c.Param("pluginId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid plugin instance ID", &gin.H{"error": err.Error()})
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, plugin_manager.MaxPluginHTTPBodySize))
+	if err != nil {
+		responses.BadRequest(c, "Request body is too large", nil)
+		return
+	}
+
+	req := &plugin_manager.PluginHTTPRequest{
+		Method: c.Request.Method,
+		Path:   c.Param("path"),
+		Query:  c.Request.URL.Query(),
+		Body:   body,
+		UserID: user.Id.String(),
+	}
+	resp, err := s.Dependencies.PluginManager.ServePluginHTTP(c.Request.Context(), serverID, instanceID, req, func(route plugin_manager.PluginHTTPRoute) error {
+		if route.Access != plugin_manager.PluginHTTPAccessManage {
+			return nil
+		}
+		canManage, err := s.canManageServerPlugins(c, user, serverID)
+		if err != nil {
+			return err
+		}
+		if !canManage {
+			return errPluginHTTPForbidden
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, errPluginHTTPForbidden):
+		responses.Forbidden(c, "You don't have the required permission", nil)
+		return
+	case errors.Is(err, plugin_manager.ErrPluginHTTPRouteNotFound):
+		responses.NotFound(c, "Plugin route not found", nil)
+		return
+	case errors.Is(err, plugin_manager.ErrPluginHTTPNotRunning):
+		responses.BadRequest(c, "Plugin instance is not running", nil)
+		return
+	case err != nil:
+		if _, lookupErr := s.Dependencies.PluginManager.GetPluginInstance(serverID, instanceID); lookupErr != nil {
+			responses.NotFound(c, "Plugin instance not found", nil)
+			return
+		}
+		responses.InternalServerError(c, fmt.Errorf("plugin request failed: %w", err), nil)
+		return
+	}
+
+	if c.Request.Method != http.MethodGet {
+		s.CreateAuditLog(c.Request.Context(), &serverID, &user.Id, "plugin:http", gin.H{
+			"instanceId": instanceID.String(),
+			"method":     req.Method,
+			"path":       req.Path,
+			"status":     resp.Status,
+		})
+	}
+
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", "default-src 'none'; sandbox")
+	c.Header("Cache-Control", "no-store")
+	if len(resp.Body) == 0 {
+		c.Status(resp.Status)
+		return
+	}
+	c.Data(resp.Status, resp.ContentType, resp.Body)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/leighmacdonald/steamid/v3/steamid"
 	"go.codycody31.dev/squad-aegis/internal/core"
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
 	"go.codycody31.dev/squad-aegis/internal/models"
 	"go.codycody31.dev/squad-aegis/internal/server/responses"
 	"go.codycody31.dev/squad-aegis/internal/shared/utils"
@@ -434,6 +435,20 @@ func (s *Server) ServerAdminsAdd(c *gin.Context) {
 
 	s.CreateAuditLog(c.Request.Context(), &serverId, &user.Id, "server:admin:create", auditData)
 
+	adminEvent := event_manager.AegisAdminData{
+		AdminID:  adminID.String(),
+		SteamID:  identifiers.SteamID,
+		EosID:    identifiers.EOSID,
+		RoleID:   request.ServerRoleID,
+		RoleName: roleName,
+		ActorID:  user.Id.String(),
+		Action:   "added",
+	}
+	if targetUser != nil {
+		adminEvent.UserID = targetUser.Id.String()
+	}
+	s.publishAegisEvent(serverId, adminEvent)
+
 	responses.Success(c, "Admin created successfully", &gin.H{
 		"adminId": adminID.String(),
 	})
@@ -628,6 +643,22 @@ func (s *Server) ServerAdminsRemove(c *gin.Context) {
 
 	s.CreateAuditLog(c.Request.Context(), &serverId, &user.Id, "server:admin:remove", auditData)
 
+	adminEvent := event_manager.AegisAdminData{
+		AdminID:  adminId.String(),
+		EosID:    eosID.String,
+		RoleID:   roleId.String(),
+		RoleName: roleName,
+		ActorID:  user.Id.String(),
+		Action:   "removed",
+	}
+	if userId.Valid {
+		adminEvent.UserID = userId.String
+	}
+	if steamID.Valid {
+		adminEvent.SteamID = strconv.FormatInt(steamID.Int64, 10)
+	}
+	s.publishAegisEvent(serverId, adminEvent)
+
 	responses.Success(c, "Admin deleted successfully", nil)
 }
 
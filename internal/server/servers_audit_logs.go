@@ -11,7 +11,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+	"go.codycody31.dev/squad-aegis/internal/audit"
 	"go.codycody31.dev/squad-aegis/internal/core"
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
 	"go.codycody31.dev/squad-aegis/internal/server/responses"
 )
 
@@ -23,6 +25,9 @@ type AuditLogEntry struct {
 	Action    string          `json:"action"`
 	Changes   json.RawMessage `json:"changes,omitempty"`
 	Timestamp time.Time       `json:"timestamp"`
+	// Set when the action was performed with a personal API token.
+	APITokenID   *uuid.UUID `json:"api_token_id,omitempty"`
+	APITokenName string     `json:"api_token_name,omitempty"`
 }
 
 // CreateAuditLog creates a new audit log entry
@@ -34,18 +39,52 @@ func (s *Server) CreateAuditLog(ctx context.Context, serverID *uuid.UUID, userID
 		return
 	}
 
-	// Insert the audit log into the database. username_snapshot captures the acting
-	// user's name at write time so the row remains attributable after user deletion
-	// (FK is ON DELETE SET NULL).
-	_, err = s.Dependencies.DB.ExecContext(ctx, `
-		INSERT INTO audit_logs (id, server_id, user_id, action, changes, timestamp, username_snapshot)
-		VALUES ($1, $2, $3, $4, $5, $6, (SELECT username FROM users WHERE id = $3))
-	`, uuid.New(), serverID, userID, action, changesJSON, time.Now())
-
-	if err != nil {
+	// Append the entry to its server's hash chain. username_snapshot captures the
+	// acting user's name at write time so the row remains attributable after user
+	// deletion (FK is ON DELETE SET NULL). api_token_id is set when the request
+	// was authenticated with a personal API token.
+	entry := &audit.Entry{
+		ServerID:   serverID,
+		ActorID:    userID,
+		APITokenID: apiTokenIDFromContext(ctx),
+		Action:     action,
+		Changes:    changesJSON,
+		Timestamp:  time.Now(),
+	}
+	if err := core.AppendAuditLog(ctx, s.Dependencies.DB, entry); err != nil {
 		log.Error().Err(err).Msg("Failed to create audit log")
 		return
 	}
+
+	if s.Dependencies.AuditStreamer != nil {
+		s.Dependencies.AuditStreamer.Publish(entry)
+	}
+
+	eventData := event_manager.AegisAuditActionData{
+		AuditLogID: entry.ID.String(),
+		Action:     action,
+		Username:   entry.Username,
+	}
+	if userID != nil {
+		eventData.ActorID = userID.String()
+	}
+	// Non-object changes are left out of the event rather than failing it.
+	_ = json.Unmarshal(changesJSON, &eventData.Changes)
+
+	eventServerID := uuid.Nil
+	if serverID != nil {
+		eventServerID = *serverID
+	}
+	s.publishAegisEvent(eventServerID, eventData)
+}
+
+// publishAegisEvent raises an event for an action taken in the panel so that
+// workflows, plugins and webhook subscriptions can react to it.
+func (s *Server) publishAegisEvent(serverID uuid.UUID, data event_manager.EventData) {
+	if s.Dependencies.EventManager == nil {
+		return
+	}
+	s.Dependencies.EventManager.PublishEvent(serverID, data, nil)
 }
 
 // ServerAuditLogs handles listing audit logs for a server
@@ -84,9 +123,10 @@ func (s *Server) ServerAuditLogs(c *gin.Context) {
 	// Build the query. COALESCE prefers the live username, falls back to the
 	// snapshot captured at write time so rows for deleted users still show a name.
 	query := `
-		SELECT al.id, al.user_id, COALESCE(u.username, al.username_snapshot), al.action, al.changes, al.timestamp
+		SELECT al.id, al.user_id, COALESCE(u.username, al.username_snapshot), al.action, al.changes, al.timestamp, al.api_token_id, t.name
 		FROM audit_logs al
 		LEFT JOIN users u ON al.user_id = u.id
+		LEFT JOIN api_tokens t ON al.api_token_id = t.id
 		WHERE al.server_id = $1
 	`
 	countQuery := `
@@ -177,7 +217,7 @@ func (s *Server) ServerAuditLogs(c *gin.Context) {
 	logs := []AuditLogEntry{}
 	for rows.Next() {
 		var log AuditLogEntry
-		var username sql.NullString
+		var username, tokenName sql.NullString
 		err := rows.Scan(
 			&log.ID,
 			&log.UserID,
@@ -185,6 +225,8 @@ func (s *Server) ServerAuditLogs(c *gin.Context) {
 			&log.Action,
 			&log.Changes,
 			&log.Timestamp,
+			&log.APITokenID,
+			&tokenName,
 		)
 		if err != nil {
 			responses.BadRequest(c, "Failed to scan audit log", &gin.H{"error": err.Error()})
@@ -196,6 +238,7 @@ func (s *Server) ServerAuditLogs(c *gin.Context) {
 		} else {
 			log.Username = "System"
 		}
+		log.APITokenName = tokenName.String
 
 		logs = append(logs, log)
 	}
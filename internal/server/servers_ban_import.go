@@ -13,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"go.codycody31.dev/squad-aegis/internal/core"
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
 	"go.codycody31.dev/squad-aegis/internal/file_upload"
 	"go.codycody31.dev/squad-aegis/internal/models"
 	"go.codycody31.dev/squad-aegis/internal/server/responses"
@@ -377,6 +378,11 @@ func (s *Server) ServerBanImportExecute(c *gin.Context) {
 	}
 	importedSteamIDs := map[string]bool(nil)
 	importedEOSIDs := map[string]bool(nil)
+	type importedBan struct {
+		data      event_manager.AegisBanData
+		expiresAt *time.Time
+	}
+	var importedBans []importedBan
 
 	if len(newBans) == 0 {
 		responses.Success(c, "No new bans to import", &gin.H{"result": result})
@@ -442,15 +448,33 @@ func (s *Server) ServerBanImportExecute(c *gin.Context) {
 			playerLabel = ban.EOSID
 		}
 
+		banID := uuid.New()
 		_, err = tx.ExecContext(ctx, `
 			INSERT INTO server_bans (id, server_id, admin_id, steam_id, eos_id, reason, expires_at, created_at, updated_at)
 			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		`, uuid.New(), serverID, user.Id, steamIDPtr, eosIDPtr, reason, expiresAt, now, now)
+		`, banID, serverID, user.Id, steamIDPtr, eosIDPtr, reason, expiresAt, now, now)
 		if err != nil {
 			result.Errors = append(result.Errors, fmt.Sprintf("failed to insert ban for %s: %v", playerLabel, err))
 			continue
 		}
 
+		bannedEOSID := ""
+		if eosIDPtr != nil {
+			bannedEOSID = *eosIDPtr
+		}
+		importedBans = append(importedBans, importedBan{
+			data: event_manager.AegisBanData{
+				BanID:   banID.String(),
+				SteamID: ban.SteamID,
+				EosID:   bannedEOSID,
+				Reason:  reason,
+				ActorID: user.Id.String(),
+				Action:  "created",
+				Source:  event_manager.AegisBanSourceImport,
+			},
+			expiresAt: expiresAt,
+		})
+
 		if ban.SteamID != "" {
 			if importedSteamIDs == nil {
 				importedSteamIDs = make(map[string]bool)
@@ -507,6 +531,9 @@ func (s *Server) ServerBanImportExecute(c *gin.Context) {
 		"errors":        result.Errors,
 	}
 	s.CreateAuditLog(c.Request.Context(), &serverID, &user.Id, "server:ban:import", auditData)
+	for _, imported := range importedBans {
+		s.Dependencies.EventManager.PublishAegisBan(serverID, imported.data, imported.expiresAt)
+	}
 
 	responses.Success(c, "Bans imported successfully", &gin.H{"result": result})
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"go.codycody31.dev/squad-aegis/internal/core"
 	dbpkg "go.codycody31.dev/squad-aegis/internal/db"
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
 	"go.codycody31.dev/squad-aegis/internal/file_upload"
 	"go.codycody31.dev/squad-aegis/internal/models"
 	"go.codycody31.dev/squad-aegis/internal/server/responses"
@@ -387,6 +388,16 @@ func (s *Server) ServerBansAdd(c *gin.Context) {
 
 	s.CreateAuditLog(c.Request.Context(), &serverId, &user.Id, "server:ban:create", auditData)
 
+	s.Dependencies.EventManager.PublishAegisBan(serverId, event_manager.AegisBanData{
+		BanID:   banID.String(),
+		SteamID: request.SteamID,
+		EosID:   subject.normalizedEOSID,
+		Reason:  request.Reason,
+		ActorID: user.Id.String(),
+		Action:  "created",
+		Source:  event_manager.AegisBanSourcePanel,
+	}, expiresAt)
+
 	r := squadRcon.NewSquadRcon(s.Dependencies.RconManager, server.Id)
 	if err := r.BanPlayer(rconPlayerID, request.Reason); err != nil {
 		log.Warn().Err(err).Str("playerID", rconPlayerID).Str("serverId", serverId.String()).Msg("Failed to kick player after ban")
@@ -516,6 +527,15 @@ func (s *Server) ServerBansRemove(c *gin.Context) {
 	}
 
 	s.CreateAuditLog(c.Request.Context(), &serverId, &user.Id, "server:ban:delete", auditData)
+	s.Dependencies.EventManager.PublishAegisBan(serverId, event_manager.AegisBanData{
+		BanID:   banId.String(),
+		SteamID: steamIDStr,
+		EosID:   eosID,
+		Reason:  reason,
+		ActorID: user.Id.String(),
+		Action:  "removed",
+		Source:  event_manager.AegisBanSourcePanel,
+	}, nil)
 
 	responses.Success(c, "Ban removed successfully", nil)
 }
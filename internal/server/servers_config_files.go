@@ -0,0 +1,803 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"go.codycody31.dev/squad-aegis/internal/core"
+	dbpkg "go.codycody31.dev/squad-aegis/internal/db"
+	"go.codycody31.dev/squad-aegis/internal/file_upload"
+	"go.codycody31.dev/squad-aegis/internal/models"
+	"go.codycody31.dev/squad-aegis/internal/server/responses"
+	"go.codycody31.dev/squad-aegis/internal/squad_config"
+)
+
+// configFileDriftInterval is how often files Aegis manages are compared with
+// the host.
+const configFileDriftInterval = 10 * time.Minute
+
+var errConfigFileBaseChanged = errors.New("the file changed on the host since it was loaded")
+
+// configFileValidationError blocks an upload of a file with errors
+type configFileValidationError struct {
+	Issues []squad_config.Issue
+}
+
+func (e *configFileValidationError) Error() string {
+	return "the file has validation errors"
+}
+
+// configFileView is a parsed file as returned by the API
+type configFileView struct {
+	Spec        squad_config.Spec             `json:"spec"`
+	Content     string                        `json:"content"`
+	ContentHash string                        `json:"content_hash"`
+	Settings    map[string]interface{}        `json:"settings,omitempty"`
+	Entries     []string                      `json:"entries,omitempty"`
+	Issues      []squad_config.Issue          `json:"issues"`
+	State       *models.ServerConfigFileState `json:"state,omitempty"`
+	Drifted     bool                          `json:"drifted"`
+}
+
+func newConfigFileView(file *squad_config.File, content string, state *models.ServerConfigFileState) configFileView {
+	view := configFileView{
+		Spec:        file.Spec,
+		Content:     content,
+		ContentHash: squad_config.Hash(content),
+		Issues:      file.Validate(),
+		State:       state,
+		Drifted:     state != nil && state.DriftDetectedAt != nil,
+	}
+	if file.Spec.Format == squad_config.FormatSettings {
+		view.Settings = file.Settings()
+	} else {
+		view.Entries = file.Entries()
+	}
+	return view
+}
+
+// configFileUploader connects to a managed file using the server's log
+// source credentials, which already reach Bans.cfg and the logs.
+func configFileUploader(server *models.Server, spec squad_config.Spec) (file_upload.Uploader, error) {
+	if server.SquadGamePath == nil || *server.SquadGamePath == "" {
+		return nil, fmt.Errorf("server does not have a SquadGame base path configured")
+	}
+	if server.LogSourceType == nil || *server.LogSourceType == "" {
+		return nil, fmt.Errorf("server does not have local/FTP/SFTP file access configured")
+	}
+
+	config := file_upload.UploadConfig{
+		Protocol: *server.LogSourceType,
+		FilePath: buildServerConfigPath(*server.SquadGamePath, server.LogSourceType, spec.RelPath()),
+	}
+
+	switch *server.LogSourceType {
+	case "local":
+	case "sftp", "ftp":
+		if server.LogHost == nil || server.LogUsername == nil || server.LogPassword == nil {
+			return nil, fmt.Errorf("server log credentials are incomplete")
+		}
+		config.Host = *server.LogHost
+		config.Username = *server.LogUsername
+		config.Password = *server.LogPassword
+		config.Port = 22
+		if *server.LogSourceType == "ftp" {
+			config.Port = 21
+		}
+		if server.LogPort != nil {
+			config.Port = *server.LogPort
+		}
+	default:
+		return nil, fmt.Errorf("server log source type %q is not supported for config files", *server.LogSourceType)
+	}
+
+	return file_upload.NewUploader(config)
+}
+
+// readConfigFile reads a managed file from the host
+func readConfigFile(ctx context.Context, server *models.Server, spec squad_config.Spec) (string, error) {
+	uploader, err := configFileUploader(server, spec)
+	if err != nil {
+		return "", err
+	}
+	defer uploader.Close()
+
+	content, err := uploader.Read(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", spec.Name, err)
+	}
+	return content, nil
+}
+
+// applyConfigFileChange builds the new file from the host's current content
+// and the requested change.
+func applyConfigFileChange(spec squad_config.Spec, current string, request *models.ServerConfigFileChangeRequest) (*squad_config.File, error) {
+	if request.Content != nil {
+		if len(request.Settings) > 0 || request.Entries != nil {
+			return nil, fmt.Errorf("content cannot be combined with settings or entries")
+		}
+		return squad_config.Parse(spec, *request.Content), nil
+	}
+	if len(request.Settings) == 0 && request.Entries == nil {
+		return nil, fmt.Errorf("content, settings or entries is required")
+	}
+
+	file := squad_config.Parse(spec, current)
+
+	keys := make([]string, 0, len(request.Settings))
+	for key := range request.Settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // New settings are appended in a stable order
+	for _, key := range keys {
+		if err := file.Set(key, request.Settings[key]); err != nil {
+			return nil, err
+		}
+	}
+
+	if request.Entries != nil {
+		if err := file.SetEntries(request.Entries); err != nil {
+			return nil, err
+		}
+	}
+	return file, nil
+}
+
+// configFileLock serializes writes to one server's file
+func (s *Server) configFileLock(serverID uuid.UUID, name string) *sync.Mutex {
+	mu, _ := s.configFilesMu.LoadOrStore(serverID.String()+"/"+name, &sync.Mutex{})
+	return mu.(*sync.Mutex)
+}
+
+// writeConfigFile applies a change to a managed file, uploads it atomically
+// and records the revision. It returns the new content, its diff from the
+// host's previous content, and the revision, which is nil when nothing
+// changed.
+func (s *Server) writeConfigFile(ctx context.Context, server *models.Server, spec squad_config.Spec, request *models.ServerConfigFileChangeRequest, source string, userID *uuid.UUID) (string, []squad_config.DiffLine, *models.ServerConfigFileRevision, error) {
+	mu := s.configFileLock(server.Id, spec.Name)
+	mu.Lock()
+	defer mu.Unlock()
+
+	uploader, err := configFileUploader(server, spec)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	defer uploader.Close()
+
+	current, err := uploader.Read(ctx)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to read %s: %w", spec.Name, err)
+	}
+	if request.BaseHash != nil && *request.BaseHash != squad_config.Hash(current) {
+		return "", nil, nil, errConfigFileBaseChanged
+	}
+
+	file, err := applyConfigFileChange(spec, current, request)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if issues := file.Validate(); squad_config.HasErrors(issues) {
+		return "", nil, nil, &configFileValidationError{Issues: issues}
+	}
+
+	content := file.Render()
+	diff := squad_config.Diff(current, content)
+	if content == current {
+		return content, diff, nil, nil
+	}
+
+	if err := uploadMOTDContent(ctx, uploader, content); err != nil {
+		return "", nil, nil, fmt.Errorf("failed to write %s: %w", spec.Name, err)
+	}
+
+	tx, err := s.Dependencies.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	defer tx.Rollback()
+
+	revision, err := recordConfigFileRevision(ctx, tx, server.Id, spec, content, source, request.Comment, userID)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if err := markConfigFileSynced(ctx, tx, server.Id, spec.Name, revision.ContentHash); err != nil {
+		return "", nil, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return "", nil, nil, err
+	}
+
+	log.Info().
+		Str("serverId", server.Id.String()).
+		Str("file", spec.Name).
+		Str("source", source).
+		Msg("Wrote server config file")
+
+	return content, diff, revision, nil
+}
+
+// checkConfigFileDrift compares content read from the host with the content
+// Aegis last wrote. The first sighting of each external change is kept as a
+// revision. Files Aegis has never written or adopted are not tracked.
+func (s *Server) checkConfigFileDrift(ctx context.Context, serverID uuid.UUID, spec squad_config.Spec, content string) (*models.ServerConfigFileState, error) {
+	state, err := s.getConfigFileState(ctx, serverID, spec.Name)
+	if err != nil || state == nil {
+		return state, err
+	}
+
+	hash := squad_config.Hash(content)
+	if hash == state.ContentHash {
+		_, err = s.Dependencies.DB.ExecContext(ctx, `
+			UPDATE server_config_files
+			SET checked_at = NOW(), drift_hash = NULL, drift_detected_at = NULL, last_error = NULL
+			WHERE server_id = $1 AND file_name = $2
+		`, serverID, spec.Name)
+		if err != nil {
+			return nil, err
+		}
+		return s.getConfigFileState(ctx, serverID, spec.Name)
+	}
+
+	if state.DriftHash == nil || *state.DriftHash != hash {
+		if _, err := recordConfigFileRevision(ctx, s.Dependencies.DB, serverID, spec, content, models.ConfigRevisionExternal, nil, nil); err != nil {
+			return nil, err
+		}
+		log.Warn().
+			Str("serverId", serverID.String()).
+			Str("file", spec.Name).
+			Msg("Server config file changed outside Aegis")
+	}
+
+	_, err = s.Dependencies.DB.ExecContext(ctx, `
+		UPDATE server_config_files
+		SET checked_at = NOW(), last_error = NULL,
+		    drift_detected_at = CASE WHEN drift_hash = $3 THEN drift_detected_at ELSE NOW() END,
+		    drift_hash = $3
+		WHERE server_id = $1 AND file_name = $2
+	`, serverID, spec.Name, hash)
+	if err != nil {
+		return nil, err
+	}
+	return s.getConfigFileState(ctx, serverID, spec.Name)
+}
+
+// readAndCheckConfigFile reads a file from the host and checks it for
+// drift. It holds the file's lock so an upload in progress is not mistaken
+// for an external change.
+func (s *Server) readAndCheckConfigFile(ctx context.Context, server *models.Server, spec squad_config.Spec) (string, *models.ServerConfigFileState, error) {
+	mu := s.configFileLock(server.Id, spec.Name)
+	mu.Lock()
+	defer mu.Unlock()
+
+	content, err := readConfigFile(ctx, server, spec)
+	if err != nil {
+		s.recordConfigFileCheckError(ctx, server.Id, spec.Name, err)
+		return "", nil, err
+	}
+
+	state, err := s.checkConfigFileDrift(ctx, server.Id, spec, content)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to check drift: %w", err)
+	}
+	return content, state, nil
+}
+
+// recordConfigFileCheckError notes that a tracked file could not be read
+func (s *Server) recordConfigFileCheckError(ctx context.Context, serverID uuid.UUID, name string, checkErr error) {
+	_, err := s.Dependencies.DB.ExecContext(ctx, `
+		UPDATE server_config_files SET checked_at = NOW(), last_error = $3
+		WHERE server_id = $1 AND file_name = $2
+	`, serverID, name, checkErr.Error())
+	if err != nil {
+		log.Error().Err(err).Str("serverId", serverID.String()).Str("file", name).Msg("Failed to record config file check error")
+	}
+}
+
+// CheckConfigFileDrift compares every file Aegis manages with the host
+func (s *Server) CheckConfigFileDrift(ctx context.Context) {
+	rows, err := s.Dependencies.DB.QueryContext(ctx, `SELECT server_id, file_name FROM server_config_files ORDER BY server_id`)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list managed config files")
+		return
+	}
+
+	files := map[uuid.UUID][]string{}
+	for rows.Next() {
+		var serverID uuid.UUID
+		var name string
+		if err := rows.Scan(&serverID, &name); err != nil {
+			rows.Close()
+			log.Error().Err(err).Msg("Failed to scan managed config file")
+			return
+		}
+		files[serverID] = append(files[serverID], name)
+	}
+	rows.Close()
+
+	for serverID, names := range files {
+		server, err := core.GetServerById(ctx, s.Dependencies.DB, serverID, nil)
+		if err != nil {
+			log.Error().Err(err).Str("serverId", serverID.String()).Msg("Failed to load server for config drift check")
+			continue
+		}
+
+		for _, name := range names {
+			if ctx.Err() != nil {
+				return
+			}
+			spec, ok := squad_config.Lookup(name)
+			if !ok {
+				continue
+			}
+
+			if _, _, err := s.readAndCheckConfigFile(ctx, server, spec); err != nil {
+				log.Error().Err(err).Str("serverId", serverID.String()).Str("file", name).Msg("Failed to check config file drift")
+			}
+		}
+	}
+}
+
+// StartConfigFileDriftChecks checks managed config files for drift until ctx
+// is done.
+func (s *Server) StartConfigFileDriftChecks(ctx context.Context) {
+	ticker := time.NewTicker(configFileDriftInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.CheckConfigFileDrift(ctx)
+		}
+	}
+}
+
+func scanConfigFileState(scanner interface{ Scan(...interface{}) error }) (*models.ServerConfigFileState, error) {
+	var state models.ServerConfigFileState
+	err := scanner.Scan(&state.ServerID, &state.FileName, &state.ContentHash, &state.SyncedAt,
+		&state.CheckedAt, &state.DriftHash, &state.DriftDetectedAt, &state.LastError)
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+const configFileStateColumns = `server_id, file_name, content_hash, synced_at, checked_at, drift_hash, drift_detected_at, last_error`
+
+// getConfigFileState returns the tracking state of a file, or nil if Aegis
+// does not track it yet
+func (s *Server) getConfigFileState(ctx context.Context, serverID uuid.UUID, name string) (*models.ServerConfigFileState, error) {
+	row := s.Dependencies.DB.QueryRowContext(ctx, `
+		SELECT `+configFileStateColumns+`
+		FROM server_config_files WHERE server_id = $1 AND file_name = $2
+	`, serverID, name)
+
+	state, err := scanConfigFileState(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return state, err
+}
+
+func (s *Server) getConfigFileStates(ctx context.Context, serverID uuid.UUID) (map[string]*models.ServerConfigFileState, error) {
+	rows, err := s.Dependencies.DB.QueryContext(ctx, `
+		SELECT `+configFileStateColumns+`
+		FROM server_config_files WHERE server_id = $1
+	`, serverID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	states := map[string]*models.ServerConfigFileState{}
+	for rows.Next() {
+		state, err := scanConfigFileState(rows)
+		if err != nil {
+			return nil, err
+		}
+		states[state.FileName] = state
+	}
+	return states, rows.Err()
+}
+
+// recordConfigFileRevision stores content as a revision with its secret
+// settings redacted. The hash is of the full content so drift checks still
+// match the host.
+func recordConfigFileRevision(ctx context.Context, executor dbpkg.Executor, serverID uuid.UUID, spec squad_config.Spec, content, source string, comment *string, userID *uuid.UUID) (*models.ServerConfigFileRevision, error) {
+	revision := &models.ServerConfigFileRevision{
+		ServerID:    serverID,
+		FileName:    spec.Name,
+		ContentHash: squad_config.Hash(content),
+		Source:      source,
+		Comment:     comment,
+		CreatedBy:   userID,
+	}
+
+	err := executor.QueryRowContext(ctx, `
+		INSERT INTO server_config_file_revisions (server_id, file_name, content, content_hash, source, comment, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`, serverID, spec.Name, squad_config.RedactSecrets(spec, content), revision.ContentHash, source, comment, userID).Scan(&revision.ID, &revision.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record revision: %w", err)
+	}
+	return revision, nil
+}
+
+// markConfigFileSynced makes hash the content Aegis expects on the host
+func markConfigFileSynced(ctx context.Context, executor dbpkg.Executor, serverID uuid.UUID, name, hash string) error {
+	_, err := executor.ExecContext(ctx, `
+		INSERT INTO server_config_files (server_id, file_name, content_hash, synced_at, checked_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (server_id, file_name) DO UPDATE
+		SET content_hash = EXCLUDED.content_hash, synced_at = NOW(), checked_at = NOW(),
+		    drift_hash = NULL, drift_detected_at = NULL, last_error = NULL
+	`, serverID, name, hash)
+	return err
+}
+
+// loadConfigFileServer resolves :serverId and :fileName
+func (s *Server) loadConfigFileServer(c *gin.Context) (*models.Server, squad_config.Spec, bool) {
+	serverID, err := uuid.Parse(c.Param("serverId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
+		return nil, squad_config.Spec{}, false
+	}
+
+	spec, ok := squad_config.Lookup(c.Param("fileName"))
+	if !ok {
+		responses.NotFound(c, "Unknown config file", nil)
+		return nil, squad_config.Spec{}, false
+	}
+
+	server, err := core.GetServerById(c.Request.Context(), s.Dependencies.DB, serverID, nil)
+	if err != nil {
+		responses.BadRequest(c, "Failed to get server", &gin.H{"error": err.Error()})
+		return nil, squad_config.Spec{}, false
+	}
+
+	return server, spec, true
+}
+
+// respondConfigFileWriteError maps writeConfigFile errors to responses
+func respondConfigFileWriteError(c *gin.Context, spec squad_config.Spec, err error) {
+	var validationErr *configFileValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		responses.BadRequest(c, "The file has validation errors", &gin.H{"issues": validationErr.Issues})
+	case errors.Is(err, errConfigFileBaseChanged):
+		responses.Conflict(c, err.Error(), nil)
+	default:
+		responses.BadRequest(c, "Failed to update "+spec.Name, &gin.H{"error": err.Error()})
+	}
+}
+
+// ServerConfigFilesList lists the managed files and their tracking state
+// without contacting the host
+func (s *Server) ServerConfigFilesList(c *gin.Context) {
+	serverID, err := uuid.Parse(c.Param("serverId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
+		return
+	}
+
+	states, err := s.getConfigFileStates(c.Request.Context(), serverID)
+	if err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get config files"})
+		return
+	}
+
+	files := []gin.H{}
+	for _, spec := range squad_config.Specs() {
+		state := states[spec.Name]
+		files = append(files, gin.H{
+			"spec":    spec,
+			"state":   state,
+			"drifted": state != nil && state.DriftDetectedAt != nil,
+		})
+	}
+
+	responses.Success(c, "Config files fetched successfully", &gin.H{"files": files})
+}
+
+// ServerConfigFileGet reads, parses and validates a file from the host,
+// checking it for drift
+func (s *Server) ServerConfigFileGet(c *gin.Context) {
+	server, spec, ok := s.loadConfigFileServer(c)
+	if !ok {
+		return
+	}
+
+	content, state, err := s.readAndCheckConfigFile(c.Request.Context(), server, spec)
+	if err != nil {
+		responses.BadRequest(c, "Failed to read "+spec.Name, &gin.H{"error": err.Error()})
+		return
+	}
+
+	file := squad_config.Parse(spec, content)
+	responses.Success(c, "Config file fetched successfully", &gin.H{"file": newConfigFileView(file, content, state)})
+}
+
+// ServerConfigFilePreview shows the result of a change without writing it
+func (s *Server) ServerConfigFilePreview(c *gin.Context) {
+	server, spec, ok := s.loadConfigFileServer(c)
+	if !ok {
+		return
+	}
+
+	var request models.ServerConfigFileChangeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+		return
+	}
+
+	current, err := readConfigFile(c.Request.Context(), server, spec)
+	if err != nil {
+		responses.BadRequest(c, "Failed to read "+spec.Name, &gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := applyConfigFileChange(spec, current, &request)
+	if err != nil {
+		responses.BadRequest(c, "Invalid change", &gin.H{"error": err.Error()})
+		return
+	}
+
+	content := file.Render()
+	diff := squad_config.Diff(current, content)
+	data := gin.H{
+		"file":          newConfigFileView(file, content, nil),
+		"base_hash":     squad_config.Hash(current),
+		"diff":          diff,
+		"changed":       content != current,
+		"setting_diffs": []squad_config.SettingChange{},
+	}
+	if spec.Format == squad_config.FormatSettings {
+		data["setting_diffs"] = squad_config.DiffSettings(squad_config.Parse(spec, current), file)
+	}
+
+	responses.Success(c, "Config file change previewed successfully", &data)
+}
+
+// ServerConfigFileUpdate validates a change and uploads the file atomically
+func (s *Server) ServerConfigFileUpdate(c *gin.Context) {
+	user := s.getUserFromSession(c)
+	if user == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return
+	}
+
+	server, spec, ok := s.loadConfigFileServer(c)
+	if !ok {
+		return
+	}
+
+	var request models.ServerConfigFileChangeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+		return
+	}
+
+	content, diff, revision, err := s.writeConfigFile(c.Request.Context(), server, spec, &request, models.ConfigRevisionUpload, &user.Id)
+	if err != nil {
+		respondConfigFileWriteError(c, spec, err)
+		return
+	}
+	if revision == nil {
+		responses.Success(c, "No changes to upload", &gin.H{"changed": false, "content_hash": squad_config.Hash(content)})
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), &server.Id, &user.Id, "server:config_file:update", map[string]interface{}{
+		"file":        spec.Name,
+		"revisionId":  revision.ID.String(),
+		"changes":     squad_config.Summary(diff),
+		"contentHash": revision.ContentHash,
+	})
+
+	responses.Success(c, spec.Name+" uploaded successfully", &gin.H{
+		"changed":  true,
+		"revision": revision,
+		"diff":     diff,
+	})
+}
+
+// ServerConfigFileAdopt accepts the host's current content as the baseline,
+// clearing any drift
+func (s *Server) ServerConfigFileAdopt(c *gin.Context) {
+	user := s.getUserFromSession(c)
+	if user == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return
+	}
+
+	server, spec, ok := s.loadConfigFileServer(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+
+	mu := s.configFileLock(server.Id, spec.Name)
+	mu.Lock()
+	defer mu.Unlock()
+
+	content, err := readConfigFile(ctx, server, spec)
+	if err != nil {
+		responses.BadRequest(c, "Failed to read "+spec.Name, &gin.H{"error": err.Error()})
+		return
+	}
+
+	tx, err := s.Dependencies.DB.BeginTx(ctx, nil)
+	if err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to adopt config file"})
+		return
+	}
+	defer tx.Rollback()
+
+	revision, err := recordConfigFileRevision(ctx, tx, server.Id, spec, content, models.ConfigRevisionAdopt, nil, &user.Id)
+	if err == nil {
+		err = markConfigFileSynced(ctx, tx, server.Id, spec.Name, revision.ContentHash)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to adopt config file"})
+		return
+	}
+
+	s.CreateAuditLog(ctx, &server.Id, &user.Id, "server:config_file:adopt", map[string]interface{}{
+		"file":        spec.Name,
+		"revisionId":  revision.ID.String(),
+		"contentHash": revision.ContentHash,
+	})
+
+	responses.Success(c, spec.Name+" adopted successfully", &gin.H{"revision": revision})
+}
+
+// ServerConfigFileRevisionsList returns a file's revisions, newest first
+func (s *Server) ServerConfigFileRevisionsList(c *gin.Context) {
+	server, spec, ok := s.loadConfigFileServer(c)
+	if !ok {
+		return
+	}
+
+	rows, err := s.Dependencies.DB.QueryContext(c.Request.Context(), `
+		SELECT id, server_id, file_name, content_hash, source, comment, created_by, created_at
+		FROM server_config_file_revisions
+		WHERE server_id = $1 AND file_name = $2
+		ORDER BY created_at DESC
+		LIMIT 100
+	`, server.Id, spec.Name)
+	if err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get revisions"})
+		return
+	}
+	defer rows.Close()
+
+	revisions := []models.ServerConfigFileRevision{}
+	for rows.Next() {
+		var revision models.ServerConfigFileRevision
+		if err := rows.Scan(&revision.ID, &revision.ServerID, &revision.FileName, &revision.ContentHash,
+			&revision.Source, &revision.Comment, &revision.CreatedBy, &revision.CreatedAt); err != nil {
+			responses.InternalServerError(c, err, &gin.H{"error": "Failed to get revisions"})
+			return
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get revisions"})
+		return
+	}
+
+	responses.Success(c, "Revisions fetched successfully", &gin.H{"revisions": revisions})
+}
+
+// loadConfigFileRevision loads the :revisionId revision of a file
+func (s *Server) loadConfigFileRevision(c *gin.Context, server *models.Server, spec squad_config.Spec) (*models.ServerConfigFileRevision, bool) {
+	revisionID, err := uuid.Parse(c.Param("revisionId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid revision ID", &gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	var revision models.ServerConfigFileRevision
+	err = s.Dependencies.DB.QueryRowContext(c.Request.Context(), `
+		SELECT id, server_id, file_name, content, content_hash, source, comment, created_by, created_at
+		FROM server_config_file_revisions
+		WHERE id = $1 AND server_id = $2 AND file_name = $3
+	`, revisionID, server.Id, spec.Name).Scan(&revision.ID, &revision.ServerID, &revision.FileName, &revision.Content,
+		&revision.ContentHash, &revision.Source, &revision.Comment, &revision.CreatedBy, &revision.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			responses.NotFound(c, "Revision not found", nil)
+			return nil, false
+		}
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get revision"})
+		return nil, false
+	}
+
+	return &revision, true
+}
+
+// ServerConfigFileRevisionGet returns a revision with its content
+func (s *Server) ServerConfigFileRevisionGet(c *gin.Context) {
+	server, spec, ok := s.loadConfigFileServer(c)
+	if !ok {
+		return
+	}
+
+	revision, ok := s.loadConfigFileRevision(c, server, spec)
+	if !ok {
+		return
+	}
+
+	responses.Success(c, "Revision fetched successfully", &gin.H{"revision": revision})
+}
+
+// ServerConfigFileRevisionRestore uploads an earlier revision again
+func (s *Server) ServerConfigFileRevisionRestore(c *gin.Context) {
+	user := s.getUserFromSession(c)
+	if user == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return
+	}
+
+	server, spec, ok := s.loadConfigFileServer(c)
+	if !ok {
+		return
+	}
+
+	revision, ok := s.loadConfigFileRevision(c, server, spec)
+	if !ok {
+		return
+	}
+
+	// Revisions do not keep secrets, so the host's current values are kept.
+	current, err := readConfigFile(c.Request.Context(), server, spec)
+	if err != nil {
+		respondConfigFileWriteError(c, spec, err)
+		return
+	}
+	content, err := squad_config.RestoreSecrets(spec, revision.Content, current)
+	if err != nil {
+		responses.BadRequest(c, "Failed to restore revision", &gin.H{"error": err.Error()})
+		return
+	}
+	baseHash := squad_config.Hash(current)
+
+	comment := fmt.Sprintf("Restored revision from %s", revision.CreatedAt.Format(time.RFC3339))
+	request := &models.ServerConfigFileChangeRequest{Content: &content, BaseHash: &baseHash, Comment: &comment}
+
+	_, diff, restored, err := s.writeConfigFile(c.Request.Context(), server, spec, request, models.ConfigRevisionRestore, &user.Id)
+	if err != nil {
+		respondConfigFileWriteError(c, spec, err)
+		return
+	}
+	if restored == nil {
+		responses.Success(c, "The host already has this revision", &gin.H{"changed": false})
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), &server.Id, &user.Id, "server:config_file:restore", map[string]interface{}{
+		"file":         spec.Name,
+		"revisionId":   restored.ID.String(),
+		"restoredFrom": revision.ID.String(),
+		"changes":      squad_config.Summary(diff),
+	})
+
+	responses.Success(c, spec.Name+" restored successfully", &gin.H{
+		"changed":  true,
+		"revision": restored,
+		"diff":     diff,
+	})
+}
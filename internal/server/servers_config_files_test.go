@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.codycody31.dev/squad-aegis/internal/models"
+	"go.codycody31.dev/squad-aegis/internal/squad_config"
+)
+
+func TestApplyConfigFileChange(t *testing.T) {
+	spec, _ := squad_config.Lookup("Server.cfg")
+	current := "// settings\nServerName=\"Old\"\nMaxPlayers=80\n"
+
+	file, err := applyConfigFileChange(spec, current, &models.ServerConfigFileChangeRequest{
+		Settings: map[string]interface{}{"MaxPlayers": float64(100), "ShouldAdvertise": true, "ServerName": nil},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rendered := file.Render(); rendered != "// settings\nMaxPlayers=100\nShouldAdvertise=true\n" {
+		t.Errorf("unexpected rendered file %q", rendered)
+	}
+
+	content := "MaxPlayers=50\n"
+	if _, err := applyConfigFileChange(spec, current, &models.ServerConfigFileChangeRequest{
+		Content:  &content,
+		Settings: map[string]interface{}{"MaxPlayers": 1},
+	}); err == nil {
+		t.Error("expected content combined with settings to fail")
+	}
+	if _, err := applyConfigFileChange(spec, current, &models.ServerConfigFileChangeRequest{}); err == nil {
+		t.Error("expected an empty change to fail")
+	}
+	if _, err := applyConfigFileChange(spec, current, &models.ServerConfigFileChangeRequest{Entries: []string{"Layer"}}); err == nil {
+		t.Error("expected entries to fail for a settings file")
+	}
+}
+
+func TestConfigFileUploaderLocal(t *testing.T) {
+	base := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(base, "ServerConfig"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(base, "ServerConfig", "LayerRotation.cfg"), []byte("Narva_RAAS_v1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	local := "local"
+	server := &models.Server{SquadGamePath: &base, LogSourceType: &local}
+	spec, _ := squad_config.Lookup("LayerRotation.cfg")
+
+	content, err := readConfigFile(context.Background(), server, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content != "Narva_RAAS_v1\n" {
+		t.Errorf("unexpected content %q", content)
+	}
+
+	ftp := "ftp"
+	if _, err := configFileUploader(&models.Server{SquadGamePath: &base, LogSourceType: &ftp}, spec); err == nil {
+		t.Error("expected missing FTP credentials to fail")
+	}
+	if _, err := configFileUploader(&models.Server{LogSourceType: &local}, spec); err == nil {
+		t.Error("expected a missing SquadGame path to fail")
+	}
+}
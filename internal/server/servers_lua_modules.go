@@ -0,0 +1,378 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/models"
+	"go.codycody31.dev/squad-aegis/internal/server/responses"
+	"go.codycody31.dev/squad-aegis/internal/workflow_manager"
+)
+
+// maxLuaModuleSourceSize bounds a module's source and its tests
+const maxLuaModuleSourceSize = 256 * 1024
+
+// luaModuleScope returns the server the request is scoped to, or nil for
+// the global module library under /sudo.
+func luaModuleScope(c *gin.Context) (*uuid.UUID, bool) {
+	raw := c.Param("serverId")
+	if raw == "" {
+		return nil, true
+	}
+
+	serverID, err := uuid.Parse(raw)
+	if err != nil {
+		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return &serverID, true
+}
+
+// loadScopedLuaModule loads the :moduleId module if it is visible in scope.
+// Servers can read global modules but only change their own.
+func (s *Server) loadScopedLuaModule(c *gin.Context, workflowDB *workflow_manager.WorkflowDatabase, scope *uuid.UUID, write bool) (*models.LuaModule, bool) {
+	moduleID, err := uuid.Parse(c.Param("moduleId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid module ID", &gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	module, err := workflowDB.GetLuaModule(moduleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			responses.NotFound(c, "Module not found", nil)
+			return nil, false
+		}
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get module"})
+		return nil, false
+	}
+
+	switch {
+	case module.ServerID == nil && scope == nil:
+	case module.ServerID != nil && scope != nil && *module.ServerID == *scope:
+	case module.ServerID == nil && !write:
+	case module.ServerID == nil:
+		responses.Forbidden(c, "Global modules can only be changed by super admins", nil)
+		return nil, false
+	default:
+		responses.NotFound(c, "Module not found", nil)
+		return nil, false
+	}
+
+	return module, true
+}
+
+func validateLuaModuleSource(c *gin.Context, source, testSource string) bool {
+	if strings.TrimSpace(source) == "" {
+		responses.BadRequest(c, "Module source is required", nil)
+		return false
+	}
+	if len(source) > maxLuaModuleSourceSize || len(testSource) > maxLuaModuleSourceSize {
+		responses.BadRequest(c, "Module source and tests must each be at most 256 KB", nil)
+		return false
+	}
+	return true
+}
+
+func luaModuleAuditScope(scope *uuid.UUID, action string) string {
+	if scope == nil {
+		return "sudo:lua_module:" + action
+	}
+	return "server:lua_module:" + action
+}
+
+// LuaModulesList lists the modules visible in scope
+func (s *Server) LuaModulesList(c *gin.Context) {
+	scope, ok := luaModuleScope(c)
+	if !ok {
+		return
+	}
+
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+	modules, err := workflowDB.ListLuaModules(scope)
+	if err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to list modules"})
+		return
+	}
+
+	responses.Success(c, "Modules retrieved successfully", &gin.H{"modules": modules})
+}
+
+// LuaModuleGet returns a module with its latest version and version history
+func (s *Server) LuaModuleGet(c *gin.Context) {
+	scope, ok := luaModuleScope(c)
+	if !ok {
+		return
+	}
+
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+	module, ok := s.loadScopedLuaModule(c, workflowDB, scope, false)
+	if !ok {
+		return
+	}
+
+	latest, err := workflowDB.GetLuaModuleVersion(module.ID, 0)
+	if err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get module version"})
+		return
+	}
+
+	versions, err := workflowDB.ListLuaModuleVersions(module.ID)
+	if err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to list module versions"})
+		return
+	}
+
+	responses.Success(c, "Module retrieved successfully", &gin.H{
+		"module":   module,
+		"latest":   latest,
+		"versions": versions,
+	})
+}
+
+// LuaModuleVersionGet returns one version of a module
+func (s *Server) LuaModuleVersionGet(c *gin.Context) {
+	scope, ok := luaModuleScope(c)
+	if !ok {
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		responses.BadRequest(c, "Invalid version", nil)
+		return
+	}
+
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+	module, ok := s.loadScopedLuaModule(c, workflowDB, scope, false)
+	if !ok {
+		return
+	}
+
+	moduleVersion, err := workflowDB.GetLuaModuleVersion(module.ID, version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			responses.NotFound(c, "Module version not found", nil)
+			return
+		}
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get module version"})
+		return
+	}
+
+	responses.Success(c, "Module version retrieved successfully", &gin.H{"version": moduleVersion})
+}
+
+// LuaModuleCreate creates a module with its first version
+func (s *Server) LuaModuleCreate(c *gin.Context) {
+	user := s.getUserFromSession(c)
+	if user == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return
+	}
+
+	scope, ok := luaModuleScope(c)
+	if !ok {
+		return
+	}
+
+	var request models.LuaModuleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+		return
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	if !workflow_manager.ValidLuaModuleName(request.Name) {
+		responses.BadRequest(c, "Module names must be lowercase words separated by dots, such as utils.cooldown", nil)
+		return
+	}
+	if !validateLuaModuleSource(c, request.Source, request.TestSource) {
+		return
+	}
+
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+
+	existing, err := workflowDB.ListLuaModules(scope)
+	if err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to list modules"})
+		return
+	}
+	for _, module := range existing {
+		sameScope := (module.ServerID == nil) == (scope == nil)
+		if module.Name == request.Name && sameScope {
+			responses.Conflict(c, "A module with this name already exists", nil)
+			return
+		}
+	}
+
+	now := time.Now()
+	module := &models.LuaModule{
+		ID:          uuid.New(),
+		ServerID:    scope,
+		Name:        request.Name,
+		Description: request.Description,
+		CreatedBy:   &user.Id,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	version := &models.LuaModuleVersion{
+		ID:         uuid.New(),
+		Source:     request.Source,
+		TestSource: request.TestSource,
+		CreatedBy:  &user.Id,
+		CreatedAt:  now,
+	}
+
+	if err := workflowDB.CreateLuaModule(module, version); err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to create module"})
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), scope, &user.Id, luaModuleAuditScope(scope, "create"), map[string]interface{}{
+		"module_id": module.ID.String(),
+		"name":      module.Name,
+	})
+
+	responses.Success(c, "Module created successfully", &gin.H{
+		"module":  module,
+		"version": version,
+	})
+}
+
+// LuaModuleUpdate saves a new version of a module. The name cannot change,
+// since workflows require modules by name.
+func (s *Server) LuaModuleUpdate(c *gin.Context) {
+	user := s.getUserFromSession(c)
+	if user == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return
+	}
+
+	scope, ok := luaModuleScope(c)
+	if !ok {
+		return
+	}
+
+	var request models.LuaModuleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+		return
+	}
+	if !validateLuaModuleSource(c, request.Source, request.TestSource) {
+		return
+	}
+
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+	module, ok := s.loadScopedLuaModule(c, workflowDB, scope, true)
+	if !ok {
+		return
+	}
+
+	if request.Description != nil {
+		module.Description = request.Description
+	}
+	version := &models.LuaModuleVersion{
+		ID:         uuid.New(),
+		Source:     request.Source,
+		TestSource: request.TestSource,
+		CreatedBy:  &user.Id,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := workflowDB.AddLuaModuleVersion(module, version); err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to save module version"})
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), scope, &user.Id, luaModuleAuditScope(scope, "update"), map[string]interface{}{
+		"module_id": module.ID.String(),
+		"name":      module.Name,
+		"version":   version.Version,
+	})
+
+	responses.Success(c, "Module version saved successfully", &gin.H{
+		"module":  module,
+		"version": version,
+	})
+}
+
+// LuaModuleDelete deletes a module and all of its versions
+func (s *Server) LuaModuleDelete(c *gin.Context) {
+	user := s.getUserFromSession(c)
+	if user == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return
+	}
+
+	scope, ok := luaModuleScope(c)
+	if !ok {
+		return
+	}
+
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+	module, ok := s.loadScopedLuaModule(c, workflowDB, scope, true)
+	if !ok {
+		return
+	}
+
+	if err := workflowDB.DeleteLuaModule(module.ID); err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to delete module"})
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), scope, &user.Id, luaModuleAuditScope(scope, "delete"), map[string]interface{}{
+		"module_id": module.ID.String(),
+		"name":      module.Name,
+	})
+
+	responses.SimpleSuccess(c, "Module deleted successfully")
+}
+
+// LuaModuleTest runs a module's tests against mocked workflow APIs. The
+// request may carry unsaved source or tests to try a draft.
+func (s *Server) LuaModuleTest(c *gin.Context) {
+	scope, ok := luaModuleScope(c)
+	if !ok {
+		return
+	}
+
+	var request models.LuaModuleTestRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+	module, ok := s.loadScopedLuaModule(c, workflowDB, scope, false)
+	if !ok {
+		return
+	}
+
+	latest, err := workflowDB.GetLuaModuleVersion(module.ID, 0)
+	if err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get module version"})
+		return
+	}
+
+	source, testSource := latest.Source, latest.TestSource
+	if request.Source != nil {
+		source = *request.Source
+	}
+	if request.TestSource != nil {
+		testSource = *request.TestSource
+	}
+	if !validateLuaModuleSource(c, source, testSource) {
+		return
+	}
+
+	report := s.Dependencies.WorkflowManager.RunLuaModuleTests(c.Request.Context(), scope, module.Name, source, testSource)
+
+	responses.Success(c, "Module tests completed", &gin.H{"report": report})
+}
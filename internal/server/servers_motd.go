@@ -3,8 +3,10 @@ package server
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -55,6 +57,21 @@ func (s *Server) updateMOTDConfig(c *gin.Context) {
 		return
 	}
 
+	if req.Template != nil && *req.Template != "" {
+		if _, err := motd.ParseTemplate(*req.Template); err != nil {
+			responses.BadRequest(c, "Invalid MOTD template", &gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Language != nil && strings.TrimSpace(*req.Language) != "" && !validRuleLanguage(strings.TrimSpace(*req.Language)) {
+		responses.BadRequest(c, "Invalid language", nil)
+		return
+	}
+	if req.RefreshIntervalMinutes != nil && *req.RefreshIntervalMinutes < 0 {
+		responses.BadRequest(c, "Refresh interval cannot be negative", nil)
+		return
+	}
+
 	// Ensure config exists
 	config, err := s.fetchOrCreateMOTDConfig(c.Request.Context(), serverID)
 	if err != nil {
@@ -87,6 +104,31 @@ func (s *Server) updateMOTDConfig(c *gin.Context) {
 		args = append(args, *req.IncludeRuleDescriptions)
 		argIndex++
 	}
+	if req.Template != nil {
+		query += fmt.Sprintf(", template = $%d", argIndex)
+		args = append(args, nullIfEmpty(*req.Template))
+		argIndex++
+	}
+	if req.Language != nil {
+		query += fmt.Sprintf(", language = $%d", argIndex)
+		args = append(args, nullIfEmpty(strings.TrimSpace(*req.Language)))
+		argIndex++
+	}
+	if req.Variables != nil {
+		variables, err := json.Marshal(req.Variables)
+		if err != nil {
+			responses.BadRequest(c, "Invalid variables", &gin.H{"error": err.Error()})
+			return
+		}
+		query += fmt.Sprintf(", variables = $%d", argIndex)
+		args = append(args, variables)
+		argIndex++
+	}
+	if req.RefreshIntervalMinutes != nil {
+		query += fmt.Sprintf(", refresh_interval_minutes = $%d", argIndex)
+		args = append(args, *req.RefreshIntervalMinutes)
+		argIndex++
+	}
 	if req.UploadEnabled != nil {
 		query += fmt.Sprintf(", upload_enabled = $%d", argIndex)
 		args = append(args, *req.UploadEnabled)
@@ -166,20 +208,46 @@ func (s *Server) previewMOTD(c *gin.Context) {
 		return
 	}
 
-	rules, err := s.fetchServerRulesHierarchy(c.Request.Context(), serverID)
+	schedules, err := s.fetchMOTDSchedules(c.Request.Context(), serverID)
 	if err != nil {
-		responses.InternalServerError(c, fmt.Errorf("failed to fetch rules: %w", err), nil)
+		responses.InternalServerError(c, fmt.Errorf("failed to fetch MOTD schedules: %w", err), nil)
 		return
 	}
 
-	generator := motd.NewGenerator()
-	content := generator.GenerateMOTD(config, rules)
-	rulesCount := generator.CountRules(rules)
+	// Preview the variant that would be uploaded now, or a chosen schedule
+	now := time.Now()
+	input := &motdRenderInput{Config: config, Schedules: schedules, Language: c.Query("language"), Now: now}
+	if scheduleID := c.Query("schedule_id"); scheduleID != "" {
+		for i := range schedules {
+			if schedules[i].ID.String() != scheduleID {
+				continue
+			}
+			input.Active = &schedules[i]
+			start, end, _, ok := motd.Occurrence(&schedules[i], now)
+			if !ok {
+				start, end = schedules[i].StartsAt, schedules[i].EndsAt
+			}
+			input.ActiveEvent = &motd.Event{Name: schedules[i].Name, StartsAt: start, EndsAt: end}
+		}
+		if input.Active == nil {
+			responses.NotFound(c, "MOTD schedule not found", nil)
+			return
+		}
+	} else {
+		input.Active, input.ActiveEvent = motd.ActiveSchedule(schedules, now)
+	}
+
+	generated, err := s.renderMOTD(c.Request.Context(), serverID, input)
+	if err != nil {
+		responses.BadRequest(c, "Failed to generate MOTD", &gin.H{"error": err.Error()})
+		return
+	}
 
 	responses.Success(c, "MOTD preview generated", &gin.H{
-		"content":      content,
-		"rules_count":  rulesCount,
-		"generated_at": time.Now().Format(time.RFC3339),
+		"content":      generated.Content,
+		"rules_count":  generated.RulesCount,
+		"variant":      generated.Variant,
+		"generated_at": now.Format(time.RFC3339),
 	})
 }
 
@@ -210,17 +278,13 @@ func (s *Server) uploadMOTD(c *gin.Context) {
 		return
 	}
 
-	// Fetch rules and generate MOTD
-	rules, err := s.fetchServerRulesHierarchy(c.Request.Context(), serverID)
+	generated, err := s.generateMOTD(c.Request.Context(), serverID, config)
 	if err != nil {
 		s.updateMOTDUploadError(c.Request.Context(), config.ID, err.Error())
-		responses.InternalServerError(c, fmt.Errorf("failed to fetch rules: %w", err), nil)
+		responses.InternalServerError(c, fmt.Errorf("failed to generate MOTD: %w", err), nil)
 		return
 	}
 
-	generator := motd.NewGenerator()
-	content := generator.GenerateMOTD(config, rules)
-
 	// Create uploader
 	uploader, err := file_upload.NewUploader(uploadConfig)
 	if err != nil {
@@ -231,14 +295,14 @@ func (s *Server) uploadMOTD(c *gin.Context) {
 	defer uploader.Close()
 
 	// Upload
-	if err := uploadMOTDContent(c.Request.Context(), uploader, content); err != nil {
+	if err := uploadMOTDContent(c.Request.Context(), uploader, generated.Content); err != nil {
 		s.updateMOTDUploadError(c.Request.Context(), config.ID, err.Error())
 		responses.InternalServerError(c, fmt.Errorf("failed to upload: %w", err), nil)
 		return
 	}
 
 	// Update success status
-	s.updateMOTDUploadSuccess(c.Request.Context(), config.ID, content)
+	s.updateMOTDUploadSuccess(c.Request.Context(), config.ID, generated)
 
 	responses.Success(c, "MOTD uploaded successfully", &gin.H{
 		"uploaded_at": time.Now().Format(time.RFC3339),
@@ -291,27 +355,43 @@ func (s *Server) testMOTDConnection(c *gin.Context) {
 
 // Helper functions
 
-func (s *Server) fetchOrCreateMOTDConfig(ctx context.Context, serverID uuid.UUID) (*models.ServerMOTDConfig, error) {
-	var config models.ServerMOTDConfig
-
-	query := `
-		SELECT id, server_id, prefix_text, suffix_text, auto_generate_from_rules, include_rule_descriptions,
-		       upload_enabled, auto_upload_on_change, use_log_credentials, upload_host, upload_port,
-		       upload_username, upload_password, upload_protocol, last_uploaded_at, last_upload_error,
-		       last_generated_content, created_at, updated_at
-		FROM server_motd_config
-		WHERE server_id = $1
-	`
+const motdConfigColumns = `id, server_id, prefix_text, suffix_text, auto_generate_from_rules, include_rule_descriptions,
+	template, language, variables, refresh_interval_minutes,
+	upload_enabled, auto_upload_on_change, use_log_credentials, upload_host, upload_port,
+	upload_username, upload_password, upload_protocol, last_uploaded_at, last_upload_error,
+	last_generated_content, active_variant, created_at, updated_at`
 
-	err := s.Dependencies.DB.QueryRowContext(ctx, query, serverID).Scan(
+func scanMOTDConfig(scanner interface{ Scan(...interface{}) error }, config *models.ServerMOTDConfig) error {
+	var variables []byte
+	err := scanner.Scan(
 		&config.ID, &config.ServerID, &config.PrefixText, &config.SuffixText,
 		&config.AutoGenerateFromRules, &config.IncludeRuleDescriptions,
+		&config.Template, &config.Language, &variables, &config.RefreshIntervalMinutes,
 		&config.UploadEnabled, &config.AutoUploadOnChange, &config.UseLogCredentials,
 		&config.UploadHost, &config.UploadPort,
 		&config.UploadUsername, &config.UploadPassword, &config.UploadProtocol,
 		&config.LastUploadedAt, &config.LastUploadError, &config.LastGeneratedContent,
-		&config.CreatedAt, &config.UpdatedAt,
+		&config.ActiveVariant, &config.CreatedAt, &config.UpdatedAt,
 	)
+	if err != nil {
+		return err
+	}
+
+	config.Variables = map[string]string{}
+	if len(variables) > 0 {
+		if err := json.Unmarshal(variables, &config.Variables); err != nil {
+			return fmt.Errorf("failed to parse MOTD variables: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Server) fetchOrCreateMOTDConfig(ctx context.Context, serverID uuid.UUID) (*models.ServerMOTDConfig, error) {
+	var config models.ServerMOTDConfig
+
+	query := `SELECT ` + motdConfigColumns + ` FROM server_motd_config WHERE server_id = $1`
+
+	err := scanMOTDConfig(s.Dependencies.DB.QueryRowContext(ctx, query, serverID), &config)
 
 	if err == sql.ErrNoRows {
 		// Create default config using INSERT ... ON CONFLICT to handle race conditions
@@ -337,26 +417,14 @@ func (s *Server) fetchOrCreateMOTDConfig(ctx context.Context, serverID uuid.UUID
 			    created_at, updated_at)
 			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 			ON CONFLICT (server_id) DO UPDATE SET updated_at = server_motd_config.updated_at
-			RETURNING id, server_id, prefix_text, suffix_text, auto_generate_from_rules, include_rule_descriptions,
-			    upload_enabled, auto_upload_on_change, use_log_credentials, upload_host, upload_port,
-			    upload_username, upload_password, upload_protocol, last_uploaded_at, last_upload_error,
-			    last_generated_content, created_at, updated_at
-		`
+			RETURNING ` + motdConfigColumns
 
-		err = s.Dependencies.DB.QueryRowContext(ctx, upsertQuery,
+		err = scanMOTDConfig(s.Dependencies.DB.QueryRowContext(ctx, upsertQuery,
 			config.ID, config.ServerID, config.PrefixText, config.SuffixText,
 			config.AutoGenerateFromRules, config.IncludeRuleDescriptions,
 			config.UploadEnabled, config.AutoUploadOnChange,
 			config.UseLogCredentials, config.CreatedAt, config.UpdatedAt,
-		).Scan(
-			&config.ID, &config.ServerID, &config.PrefixText, &config.SuffixText,
-			&config.AutoGenerateFromRules, &config.IncludeRuleDescriptions,
-			&config.UploadEnabled, &config.AutoUploadOnChange, &config.UseLogCredentials,
-			&config.UploadHost, &config.UploadPort,
-			&config.UploadUsername, &config.UploadPassword, &config.UploadProtocol,
-			&config.LastUploadedAt, &config.LastUploadError, &config.LastGeneratedContent,
-			&config.CreatedAt, &config.UpdatedAt,
-		)
+		), &config)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create MOTD config: %w", err)
 		}
@@ -557,10 +625,10 @@ func (s *Server) updateMOTDUploadError(ctx context.Context, configID uuid.UUID,
 	s.Dependencies.DB.ExecContext(ctx, query, errorMsg, configID)
 }
 
-func (s *Server) updateMOTDUploadSuccess(ctx context.Context, configID uuid.UUID, content string) {
+func (s *Server) updateMOTDUploadSuccess(ctx context.Context, configID uuid.UUID, generated *generatedMOTD) {
 	query := `UPDATE server_motd_config SET last_uploaded_at = NOW(), last_upload_error = NULL,
-	          last_generated_content = $1, updated_at = NOW() WHERE id = $2`
-	s.Dependencies.DB.ExecContext(ctx, query, content, configID)
+	          last_generated_content = $1, active_variant = $2, updated_at = NOW() WHERE id = $3`
+	s.Dependencies.DB.ExecContext(ctx, query, generated.Content, generated.Variant, configID)
 }
 
 // triggerMOTDUpload triggers an MOTD upload in the background
@@ -570,34 +638,37 @@ func (s *Server) triggerMOTDUpload(ctx context.Context, serverID uuid.UUID) {
 		return
 	}
 
-	uploadConfig, err := s.getUploadConfig(ctx, serverID, config)
+	generated, err := s.generateMOTD(ctx, serverID, config)
 	if err != nil {
 		s.updateMOTDUploadError(ctx, config.ID, err.Error())
 		return
 	}
 
-	rules, err := s.fetchServerRulesHierarchy(ctx, serverID)
+	s.publishMOTD(ctx, serverID, config, generated)
+}
+
+// publishMOTD uploads generated content and records the outcome
+func (s *Server) publishMOTD(ctx context.Context, serverID uuid.UUID, config *models.ServerMOTDConfig, generated *generatedMOTD) error {
+	uploadConfig, err := s.getUploadConfig(ctx, serverID, config)
 	if err != nil {
 		s.updateMOTDUploadError(ctx, config.ID, err.Error())
-		return
+		return err
 	}
 
-	generator := motd.NewGenerator()
-	content := generator.GenerateMOTD(config, rules)
-
 	uploader, err := file_upload.NewUploader(uploadConfig)
 	if err != nil {
 		s.updateMOTDUploadError(ctx, config.ID, err.Error())
-		return
+		return err
 	}
 	defer uploader.Close()
 
-	if err := uploadMOTDContent(ctx, uploader, content); err != nil {
+	if err := uploadMOTDContent(ctx, uploader, generated.Content); err != nil {
 		s.updateMOTDUploadError(ctx, config.ID, err.Error())
-		return
+		return err
 	}
 
-	s.updateMOTDUploadSuccess(ctx, config.ID, content)
+	s.updateMOTDUploadSuccess(ctx, config.ID, generated)
+	return nil
 }
 
 func uploadMOTDContent(ctx context.Context, uploader file_upload.Uploader, content string) error {
@@ -0,0 +1,338 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"go.codycody31.dev/squad-aegis/internal/models"
+	"go.codycody31.dev/squad-aegis/internal/motd"
+	"go.codycody31.dev/squad-aegis/internal/server/responses"
+)
+
+// motdSchedulerInterval is how often scheduled MOTD variants are checked
+const motdSchedulerInterval = time.Minute
+
+const motdScheduleColumns = `id, server_id, name, template, language, starts_at, ends_at, recurrence, enabled, created_at, updated_at`
+
+func scanMOTDSchedule(scanner interface{ Scan(...interface{}) error }) (*models.ServerMOTDSchedule, error) {
+	var schedule models.ServerMOTDSchedule
+	err := scanner.Scan(&schedule.ID, &schedule.ServerID, &schedule.Name, &schedule.Template, &schedule.Language,
+		&schedule.StartsAt, &schedule.EndsAt, &schedule.Recurrence, &schedule.Enabled,
+		&schedule.CreatedAt, &schedule.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (s *Server) fetchMOTDSchedules(ctx context.Context, serverID uuid.UUID) ([]models.ServerMOTDSchedule, error) {
+	rows, err := s.Dependencies.DB.QueryContext(ctx, `
+		SELECT `+motdScheduleColumns+`
+		FROM server_motd_schedules WHERE server_id = $1
+		ORDER BY starts_at ASC
+	`, serverID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := []models.ServerMOTDSchedule{}
+	for rows.Next() {
+		schedule, err := scanMOTDSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, *schedule)
+	}
+	return schedules, rows.Err()
+}
+
+// validateMOTDScheduleRequest normalizes a schedule request, returning a
+// message describing the first problem found
+func validateMOTDScheduleRequest(req *models.ServerMOTDScheduleRequest) string {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return "Schedule name is required"
+	}
+	if len(req.Name) > 100 {
+		return "Schedule name must be 100 characters or fewer"
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		return "Schedule must end after it starts"
+	}
+
+	switch req.Recurrence {
+	case "":
+		req.Recurrence = models.MOTDRecurrenceOnce
+	case models.MOTDRecurrenceOnce, models.MOTDRecurrenceDaily, models.MOTDRecurrenceWeekly:
+	default:
+		return "Recurrence must be once, daily or weekly"
+	}
+	// A window as long as its period would never close
+	if period := motd.RecurrencePeriod(req.Recurrence); period > 0 && req.EndsAt.Sub(req.StartsAt) >= period {
+		return "A recurring schedule's window must be shorter than its recurrence"
+	}
+
+	if req.Template != nil && *req.Template != "" {
+		if _, err := motd.ParseTemplate(*req.Template); err != nil {
+			return "Invalid MOTD template: " + err.Error()
+		}
+	}
+	if req.Language != nil {
+		language := strings.TrimSpace(*req.Language)
+		if language != "" && !validRuleLanguage(language) {
+			return "Invalid language"
+		}
+		req.Language = &language
+	}
+	return ""
+}
+
+// listMOTDSchedules lists a server's MOTD schedules with their next window
+func (s *Server) listMOTDSchedules(c *gin.Context) {
+	serverID, err := uuid.Parse(c.Param("serverId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
+		return
+	}
+
+	schedules, err := s.fetchMOTDSchedules(c.Request.Context(), serverID)
+	if err != nil {
+		responses.InternalServerError(c, fmt.Errorf("failed to fetch MOTD schedules: %w", err), nil)
+		return
+	}
+
+	now := time.Now()
+	active, _ := motd.ActiveSchedule(schedules, now)
+	items := make([]gin.H, 0, len(schedules))
+	for i := range schedules {
+		item := gin.H{"schedule": schedules[i], "active": active != nil && active.ID == schedules[i].ID}
+		if start, end, _, ok := motd.Occurrence(&schedules[i], now); ok {
+			item["window_starts_at"] = start
+			item["window_ends_at"] = end
+		}
+		items = append(items, item)
+	}
+
+	responses.Success(c, "MOTD schedules retrieved", &gin.H{"schedules": items})
+}
+
+// createMOTDSchedule adds a scheduled MOTD variant
+func (s *Server) createMOTDSchedule(c *gin.Context) {
+	serverID, err := uuid.Parse(c.Param("serverId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
+		return
+	}
+
+	var req models.ServerMOTDScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.BadRequest(c, "Invalid request body", &gin.H{"error": err.Error()})
+		return
+	}
+	if msg := validateMOTDScheduleRequest(&req); msg != "" {
+		responses.BadRequest(c, msg, nil)
+		return
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	row := s.Dependencies.DB.QueryRowContext(c.Request.Context(), `
+		INSERT INTO server_motd_schedules (server_id, name, template, language, starts_at, ends_at, recurrence, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+motdScheduleColumns,
+		serverID, req.Name, optionalString(req.Template), optionalString(req.Language),
+		req.StartsAt, req.EndsAt, req.Recurrence, enabled)
+	schedule, err := scanMOTDSchedule(row)
+	if err != nil {
+		responses.InternalServerError(c, fmt.Errorf("failed to create MOTD schedule: %w", err), nil)
+		return
+	}
+
+	responses.Success(c, "MOTD schedule created", &gin.H{"schedule": schedule})
+}
+
+// updateMOTDSchedule replaces a scheduled MOTD variant
+func (s *Server) updateMOTDSchedule(c *gin.Context) {
+	serverID, err := uuid.Parse(c.Param("serverId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
+		return
+	}
+	scheduleID, err := uuid.Parse(c.Param("scheduleId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid schedule ID", &gin.H{"error": err.Error()})
+		return
+	}
+
+	var req models.ServerMOTDScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.BadRequest(c, "Invalid request body", &gin.H{"error": err.Error()})
+		return
+	}
+	if msg := validateMOTDScheduleRequest(&req); msg != "" {
+		responses.BadRequest(c, msg, nil)
+		return
+	}
+
+	row := s.Dependencies.DB.QueryRowContext(c.Request.Context(), `
+		UPDATE server_motd_schedules
+		SET name = $3, template = $4, language = $5, starts_at = $6, ends_at = $7, recurrence = $8,
+		    enabled = COALESCE($9, enabled), updated_at = NOW()
+		WHERE id = $1 AND server_id = $2
+		RETURNING `+motdScheduleColumns,
+		scheduleID, serverID, req.Name, optionalString(req.Template), optionalString(req.Language),
+		req.StartsAt, req.EndsAt, req.Recurrence, req.Enabled)
+	schedule, err := scanMOTDSchedule(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		responses.NotFound(c, "MOTD schedule not found", nil)
+		return
+	}
+	if err != nil {
+		responses.InternalServerError(c, fmt.Errorf("failed to update MOTD schedule: %w", err), nil)
+		return
+	}
+
+	responses.Success(c, "MOTD schedule updated", &gin.H{"schedule": schedule})
+}
+
+// deleteMOTDSchedule removes a scheduled MOTD variant. If it is on the
+// server, the scheduler switches back on its next pass.
+func (s *Server) deleteMOTDSchedule(c *gin.Context) {
+	serverID, err := uuid.Parse(c.Param("serverId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
+		return
+	}
+	scheduleID, err := uuid.Parse(c.Param("scheduleId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid schedule ID", &gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := s.Dependencies.DB.ExecContext(c.Request.Context(),
+		`DELETE FROM server_motd_schedules WHERE id = $1 AND server_id = $2`, scheduleID, serverID)
+	if err != nil {
+		responses.InternalServerError(c, fmt.Errorf("failed to delete MOTD schedule: %w", err), nil)
+		return
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		responses.NotFound(c, "MOTD schedule not found", nil)
+		return
+	}
+
+	responses.Success(c, "MOTD schedule deleted", nil)
+}
+
+// RunMOTDSchedules uploads the MOTD of servers whose scheduled variant has
+// changed or whose refresh interval has elapsed with new content
+func (s *Server) RunMOTDSchedules(ctx context.Context) {
+	rows, err := s.Dependencies.DB.QueryContext(ctx, `
+		SELECT c.server_id FROM server_motd_config c
+		WHERE c.upload_enabled AND (
+			c.refresh_interval_minutes > 0
+			OR COALESCE(c.active_variant, $1) <> $1
+			OR EXISTS (SELECT 1 FROM server_motd_schedules s WHERE s.server_id = c.server_id AND s.enabled)
+		)
+	`, motdDefaultVariant)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list servers for scheduled MOTD uploads")
+		return
+	}
+
+	var serverIDs []uuid.UUID
+	for rows.Next() {
+		var serverID uuid.UUID
+		if err := rows.Scan(&serverID); err != nil {
+			log.Error().Err(err).Msg("Failed to scan server for scheduled MOTD uploads")
+			continue
+		}
+		serverIDs = append(serverIDs, serverID)
+	}
+	rows.Close()
+
+	for _, serverID := range serverIDs {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.runMOTDSchedule(ctx, serverID, time.Now()); err != nil {
+			log.Error().Err(err).Str("serverId", serverID.String()).Msg("Failed to upload scheduled MOTD")
+		}
+	}
+}
+
+func (s *Server) runMOTDSchedule(ctx context.Context, serverID uuid.UUID, now time.Time) error {
+	config, err := s.fetchOrCreateMOTDConfig(ctx, serverID)
+	if err != nil {
+		return err
+	}
+	if !config.UploadEnabled {
+		return nil
+	}
+
+	generated, err := s.generateMOTD(ctx, serverID, config)
+	if err != nil {
+		s.updateMOTDUploadError(ctx, config.ID, err.Error())
+		return err
+	}
+
+	if !motdUploadDue(config, generated, now) {
+		return nil
+	}
+	return s.publishMOTD(ctx, serverID, config, generated)
+}
+
+// motdUploadDue reports whether generated content should replace what is on
+// the server: the variant changed, or the refresh interval elapsed and the
+// content differs from the last upload
+func motdUploadDue(config *models.ServerMOTDConfig, generated *generatedMOTD, now time.Time) bool {
+	current := motdDefaultVariant
+	if config.ActiveVariant != nil {
+		current = *config.ActiveVariant
+	}
+	if generated.Variant != current {
+		return true
+	}
+
+	if config.RefreshIntervalMinutes <= 0 {
+		return false
+	}
+	if config.LastGeneratedContent != nil && *config.LastGeneratedContent == generated.Content {
+		return false
+	}
+	interval := time.Duration(config.RefreshIntervalMinutes) * time.Minute
+	return config.LastUploadedAt == nil || now.Sub(*config.LastUploadedAt) >= interval
+}
+
+// StartMOTDScheduler runs scheduled MOTD uploads until ctx is done.
+func (s *Server) StartMOTDScheduler(ctx context.Context) {
+	ticker := time.NewTicker(motdSchedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.RunMOTDSchedules(ctx)
+		}
+	}
+}
+
+// optionalString stores nil and empty strings as NULL
+func optionalString(value *string) interface{} {
+	if value == nil {
+		return nil
+	}
+	return nullIfEmpty(*value)
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+func TestMOTDUploadDue(t *testing.T) {
+	now := time.Now()
+	lastUpload := now.Add(-10 * time.Minute)
+	content := "Welcome"
+	eventVariant := "6a1f1c9e-4d6e-4ad2-9f5c-0b7c6c1d2e3f"
+
+	tests := []struct {
+		name      string
+		config    models.ServerMOTDConfig
+		generated generatedMOTD
+		want      bool
+	}{
+		{
+			name:      "never uploaded default",
+			config:    models.ServerMOTDConfig{},
+			generated: generatedMOTD{Content: content, Variant: motdDefaultVariant},
+			want:      false,
+		},
+		{
+			name:      "schedule opened",
+			config:    models.ServerMOTDConfig{LastGeneratedContent: &content},
+			generated: generatedMOTD{Content: content, Variant: eventVariant},
+			want:      true,
+		},
+		{
+			name:      "schedule closed",
+			config:    models.ServerMOTDConfig{ActiveVariant: &eventVariant},
+			generated: generatedMOTD{Content: content, Variant: motdDefaultVariant},
+			want:      true,
+		},
+		{
+			name:      "refresh elapsed with new content",
+			config:    models.ServerMOTDConfig{RefreshIntervalMinutes: 5, LastUploadedAt: &lastUpload, LastGeneratedContent: &content},
+			generated: generatedMOTD{Content: "Seeding 12/50", Variant: motdDefaultVariant},
+			want:      true,
+		},
+		{
+			name:      "refresh elapsed with same content",
+			config:    models.ServerMOTDConfig{RefreshIntervalMinutes: 5, LastUploadedAt: &lastUpload, LastGeneratedContent: &content},
+			generated: generatedMOTD{Content: content, Variant: motdDefaultVariant},
+			want:      false,
+		},
+		{
+			name:      "refresh not elapsed",
+			config:    models.ServerMOTDConfig{RefreshIntervalMinutes: 30, LastUploadedAt: &lastUpload, LastGeneratedContent: &content},
+			generated: generatedMOTD{Content: "Seeding 12/50", Variant: motdDefaultVariant},
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := motdUploadDue(&tt.config, &tt.generated, now); got != tt.want {
+				t.Errorf("motdUploadDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateMOTDScheduleRequest(t *testing.T) {
+	start := time.Date(2025, 6, 1, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		duration   time.Duration
+		recurrence string
+		wantError  bool
+	}{
+		{name: "one-off longer than a day", duration: 48 * time.Hour, recurrence: models.MOTDRecurrenceOnce},
+		{name: "daily evening window", duration: 4 * time.Hour, recurrence: models.MOTDRecurrenceDaily},
+		{name: "daily window of a full day", duration: 24 * time.Hour, recurrence: models.MOTDRecurrenceDaily, wantError: true},
+		{name: "weekly weekend window", duration: 48 * time.Hour, recurrence: models.MOTDRecurrenceWeekly},
+		{name: "weekly window over a week", duration: 8 * 24 * time.Hour, recurrence: models.MOTDRecurrenceWeekly, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := models.ServerMOTDScheduleRequest{Name: "Event", StartsAt: start, EndsAt: start.Add(tt.duration), Recurrence: tt.recurrence}
+			if msg := validateMOTDScheduleRequest(&req); (msg != "") != tt.wantError {
+				t.Fatalf("validateMOTDScheduleRequest() = %q, want error %v", msg, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,337 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"go.codycody31.dev/squad-aegis/internal/core"
+	"go.codycody31.dev/squad-aegis/internal/models"
+	"go.codycody31.dev/squad-aegis/internal/motd"
+	"go.codycody31.dev/squad-aegis/internal/shared/whitelistprogress"
+	squadRcon "go.codycody31.dev/squad-aegis/internal/squad-rcon"
+)
+
+const (
+	// motdDefaultVariant is the active variant when no schedule is open
+	motdDefaultVariant = "default"
+
+	motdSeederPluginID       = "server_seeder_whitelist"
+	motdDefaultSeedThreshold = 50
+	motdTopSeedersLimit      = 25
+	motdSeedingStatusSeeding = "seeding"
+	motdSeedingStatusLive    = "live"
+	motdSeedingStatusUnknown = "unknown"
+)
+
+// motdRenderInput selects what renderMOTD generates. Active overrides the
+// config's template and language; Language, when set, overrides both.
+type motdRenderInput struct {
+	Config      *models.ServerMOTDConfig
+	Schedules   []models.ServerMOTDSchedule
+	Active      *models.ServerMOTDSchedule
+	ActiveEvent *motd.Event
+	Language    string
+	Now         time.Time
+}
+
+// generatedMOTD is MOTD content and the variant it was generated from
+type generatedMOTD struct {
+	Content    string
+	Variant    string
+	RulesCount int
+}
+
+// generateMOTD generates the MOTD that should be on the server right now
+func (s *Server) generateMOTD(ctx context.Context, serverID uuid.UUID, config *models.ServerMOTDConfig) (*generatedMOTD, error) {
+	schedules, err := s.fetchMOTDSchedules(ctx, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch MOTD schedules: %w", err)
+	}
+
+	now := time.Now()
+	active, event := motd.ActiveSchedule(schedules, now)
+	return s.renderMOTD(ctx, serverID, &motdRenderInput{
+		Config:      config,
+		Schedules:   schedules,
+		Active:      active,
+		ActiveEvent: event,
+		Now:         now,
+	})
+}
+
+// renderMOTD generates MOTD content. Without a template the prefix, rules
+// and suffix layout is used, with rules in the selected language.
+func (s *Server) renderMOTD(ctx context.Context, serverID uuid.UUID, input *motdRenderInput) (*generatedMOTD, error) {
+	config := input.Config
+
+	template := ""
+	if config.Template != nil {
+		template = *config.Template
+	}
+	language := ""
+	if config.Language != nil {
+		language = *config.Language
+	}
+
+	variant := motdDefaultVariant
+	if input.Active != nil {
+		variant = input.Active.ID.String()
+		if input.Active.Template != nil && *input.Active.Template != "" {
+			template = *input.Active.Template
+		}
+		if input.Active.Language != nil && *input.Active.Language != "" {
+			language = *input.Active.Language
+		}
+	}
+	if input.Language != "" {
+		language = input.Language
+	}
+
+	rules, err := s.fetchServerRulesHierarchy(ctx, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rules: %w", err)
+	}
+
+	generator := motd.NewGenerator()
+	rulesCount := generator.CountRules(rules)
+
+	// Translations are loaded once per language
+	translations := map[string]map[uuid.UUID]models.ServerRuleTranslation{}
+	localize := func(language string) ([]models.ServerRule, error) {
+		if language == "" {
+			return rules, nil
+		}
+		if _, ok := translations[language]; !ok {
+			loaded, err := s.fetchRuleTranslations(ctx, serverID, language)
+			if err != nil {
+				return nil, err
+			}
+			translations[language] = loaded
+		}
+		return motd.LocalizeRules(rules, translations[language]), nil
+	}
+
+	localized, err := localize(language)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rule translations: %w", err)
+	}
+
+	if template == "" {
+		return &generatedMOTD{
+			Content:    generator.GenerateMOTD(config, localized),
+			Variant:    variant,
+			RulesCount: rulesCount,
+		}, nil
+	}
+
+	data := &motd.TemplateData{
+		Language:    language,
+		Rules:       generator.RulesText(localized, config.IncludeRuleDescriptions),
+		RulesCount:  rulesCount,
+		Vars:        config.Variables,
+		ActiveEvent: input.ActiveEvent,
+		NextEvent:   motd.NextEvent(input.Schedules, input.Now),
+		Now:         input.Now,
+		RulesFor: func(language string) string {
+			localized, err := localize(language)
+			if err != nil {
+				log.Warn().Err(err).Str("serverId", serverID.String()).Str("language", language).Msg("Failed to fetch rule translations for MOTD")
+				localized = rules
+			}
+			return generator.RulesText(localized, config.IncludeRuleDescriptions)
+		},
+	}
+	if data.Vars == nil {
+		data.Vars = map[string]string{}
+	}
+
+	if server, err := core.GetServerById(ctx, s.Dependencies.DB, serverID, nil); err == nil {
+		data.ServerName = server.Name
+	}
+
+	// Seeding data needs RCON and plugin storage, so only gather it when used
+	if motd.TemplateUses(template, "Seeding") {
+		data.Seeding = s.motdSeedingStatus(ctx, serverID)
+	}
+	if motd.TemplateUses(template, "TopSeeders") {
+		seeders, err := s.motdTopSeeders(ctx, serverID)
+		if err != nil {
+			log.Warn().Err(err).Str("serverId", serverID.String()).Msg("Failed to load top seeders for MOTD")
+		}
+		data.TopSeeders = seeders
+	}
+
+	content, err := motd.RenderTemplate(template, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render MOTD template: %w", err)
+	}
+
+	return &generatedMOTD{Content: content, Variant: variant, RulesCount: rulesCount}, nil
+}
+
+// fetchRuleTranslations returns a server's rule translations for a language
+func (s *Server) fetchRuleTranslations(ctx context.Context, serverID uuid.UUID, language string) (map[uuid.UUID]models.ServerRuleTranslation, error) {
+	rows, err := s.Dependencies.DB.QueryContext(ctx, `
+		SELECT t.rule_id, t.language, t.title, t.description, t.created_at, t.updated_at
+		FROM server_rule_translations t
+		JOIN server_rules r ON r.id = t.rule_id
+		WHERE r.server_id = $1 AND t.language = $2
+	`, serverID, language)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := make(map[uuid.UUID]models.ServerRuleTranslation)
+	for rows.Next() {
+		var translation models.ServerRuleTranslation
+		if err := rows.Scan(&translation.RuleID, &translation.Language, &translation.Title,
+			&translation.Description, &translation.CreatedAt, &translation.UpdatedAt); err != nil {
+			return nil, err
+		}
+		translations[translation.RuleID] = translation
+	}
+	return translations, rows.Err()
+}
+
+// motdSeederInstance returns the server's seeder whitelist plugin instance
+// and its config, preferring an enabled one
+func (s *Server) motdSeederInstance(ctx context.Context, serverID uuid.UUID) (uuid.UUID, map[string]interface{}, error) {
+	var instanceID uuid.UUID
+	var configJSON []byte
+	err := s.Dependencies.DB.QueryRowContext(ctx, `
+		SELECT id, config FROM plugin_instances
+		WHERE server_id = $1 AND plugin_id = $2
+		ORDER BY enabled DESC, created_at ASC
+		LIMIT 1
+	`, serverID, motdSeederPluginID).Scan(&instanceID, &configJSON)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	config := map[string]interface{}{}
+	if len(configJSON) > 0 {
+		if err := json.Unmarshal(configJSON, &config); err != nil {
+			return uuid.Nil, nil, err
+		}
+	}
+	return instanceID, config, nil
+}
+
+// motdSeedingStatus compares the live player count with the seeder
+// whitelist's seeding threshold
+func (s *Server) motdSeedingStatus(ctx context.Context, serverID uuid.UUID) motd.SeedingStatus {
+	status := motd.SeedingStatus{Threshold: motdDefaultSeedThreshold, Status: motdSeedingStatusUnknown}
+
+	if _, config, err := s.motdSeederInstance(ctx, serverID); err == nil {
+		if threshold, ok := config["seeding_threshold"].(float64); ok && threshold > 0 {
+			status.Threshold = int(threshold)
+		}
+	}
+
+	if s.Dependencies.RconManager == nil {
+		return status
+	}
+	info, err := squadRcon.NewSquadRcon(s.Dependencies.RconManager, serverID).GetServerInfo()
+	if err != nil {
+		log.Debug().Err(err).Str("serverId", serverID.String()).Msg("Failed to get server info for MOTD seeding status")
+		return status
+	}
+
+	status.Known = true
+	status.PlayerCount = info.PlayerCount
+	status.MaxPlayers = info.MaxPlayers
+	status.IsSeeding = info.PlayerCount < status.Threshold
+	status.Status = motdSeedingStatusLive
+	if status.IsSeeding {
+		status.Status = motdSeedingStatusSeeding
+	}
+	return status
+}
+
+// motdTopSeeders ranks players by qualified seeding time from the seeder
+// whitelist plugin's stored progress
+func (s *Server) motdTopSeeders(ctx context.Context, serverID uuid.UUID) ([]motd.Seeder, error) {
+	instanceID, _, err := s.motdSeederInstance(ctx, serverID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var raw string
+	err = s.Dependencies.DB.QueryRowContext(ctx,
+		`SELECT value FROM plugin_data WHERE plugin_instance_id = $1 AND key = 'player_progress'`,
+		instanceID,
+	).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := whitelistprogress.ParseState(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*whitelistprogress.PlayerRecord, 0, len(state.Players))
+	for _, record := range state.Players {
+		if record != nil && record.QualifiedSeconds > 0 {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].QualifiedSeconds != records[j].QualifiedSeconds {
+			return records[i].QualifiedSeconds > records[j].QualifiedSeconds
+		}
+		return records[i].PlayerID < records[j].PlayerID
+	})
+	if len(records) > motdTopSeedersLimit {
+		records = records[:motdTopSeedersLimit]
+	}
+
+	identifiers := make([]PlayerIdentifier, 0, len(records))
+	for _, record := range records {
+		if record.SteamID != "" {
+			identifiers = append(identifiers, PlayerIdentifier{Value: record.SteamID, IsSteam: true})
+		} else if record.EOSID != "" {
+			identifiers = append(identifiers, PlayerIdentifier{Value: record.EOSID})
+		}
+	}
+	names := s.lookupPlayerNamesBatchByIdentifiers(ctx, identifiers)
+
+	seeders := make([]motd.Seeder, 0, len(records))
+	for _, record := range records {
+		name := names["steam:"+record.SteamID]
+		if name == "" {
+			name = names["eos:"+record.EOSID]
+		}
+		if name == "" {
+			name = record.PlayerID
+		}
+		seeders = append(seeders, motd.Seeder{
+			Name:     name,
+			PlayerID: record.PlayerID,
+			Hours:    whitelistprogress.SecondsToHours(record.QualifiedSeconds),
+		})
+	}
+	return seeders, nil
+}
+
+// nullIfEmpty stores empty strings as NULL
+func nullIfEmpty(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
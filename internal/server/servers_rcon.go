@@ -13,6 +13,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"go.codycody31.dev/squad-aegis/internal/commands"
 	"go.codycody31.dev/squad-aegis/internal/core"
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
 	"go.codycody31.dev/squad-aegis/internal/server/responses"
 	"go.codycody31.dev/squad-aegis/internal/shared/utils"
 	squadRcon "go.codycody31.dev/squad-aegis/internal/squad-rcon"
@@ -767,6 +768,15 @@ func (s *Server) ServerRconPlayerBan(c *gin.Context) {
 	}
 
 	s.CreateAuditLog(c.Request.Context(), &serverId, &user.Id, "server:rcon:player:ban", auditData)
+	s.Dependencies.EventManager.PublishAegisBan(serverId, event_manager.AegisBanData{
+		BanID:   banID.String(),
+		SteamID: request.SteamId,
+		EosID:   utils.NormalizeEOSID(request.EosId),
+		Reason:  request.Reason,
+		ActorID: user.Id.String(),
+		Action:  "created",
+		Source:  event_manager.AegisBanSourceRcon,
+	}, expiresAt)
 
 	responses.Success(c, "Player banned successfully", &gin.H{
 		"banId": persistedBanID,
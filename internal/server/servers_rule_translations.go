@@ -0,0 +1,164 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/models"
+	"go.codycody31.dev/squad-aegis/internal/server/responses"
+)
+
+// ruleLanguagePattern accepts language tags such as "de" or "pt-BR"
+var ruleLanguagePattern = regexp.MustCompile(`^[A-Za-z]{2,8}(-[A-Za-z0-9]{1,8})*$`)
+
+func validRuleLanguage(language string) bool {
+	return len(language) <= 16 && ruleLanguagePattern.MatchString(language)
+}
+
+func parseRuleLanguage(c *gin.Context) (string, bool) {
+	language := strings.TrimSpace(c.Param("language"))
+	if !validRuleLanguage(language) {
+		responses.BadRequest(c, "Invalid language", nil)
+		return "", false
+	}
+	return language, true
+}
+
+// listServerRuleTranslations lists all rule translations of a server,
+// optionally filtered by the language query parameter
+func (s *Server) listServerRuleTranslations(c *gin.Context) {
+	serverID, err := uuid.Parse(c.Param("serverId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
+		return
+	}
+
+	query := `
+		SELECT t.rule_id, t.language, t.title, t.description, t.created_at, t.updated_at
+		FROM server_rule_translations t
+		JOIN server_rules r ON r.id = t.rule_id
+		WHERE r.server_id = $1`
+	args := []interface{}{serverID}
+	if language := c.Query("language"); language != "" {
+		query += ` AND t.language = $2`
+		args = append(args, language)
+	}
+	query += ` ORDER BY t.language, r.display_order`
+
+	rows, err := s.Dependencies.DB.QueryContext(c.Request.Context(), query, args...)
+	if err != nil {
+		responses.InternalServerError(c, fmt.Errorf("failed to fetch rule translations: %w", err), nil)
+		return
+	}
+	defer rows.Close()
+
+	translations := []models.ServerRuleTranslation{}
+	languages := []string{}
+	seen := map[string]bool{}
+	for rows.Next() {
+		var translation models.ServerRuleTranslation
+		if err := rows.Scan(&translation.RuleID, &translation.Language, &translation.Title,
+			&translation.Description, &translation.CreatedAt, &translation.UpdatedAt); err != nil {
+			responses.InternalServerError(c, fmt.Errorf("failed to scan rule translation: %w", err), nil)
+			return
+		}
+		translations = append(translations, translation)
+		if !seen[translation.Language] {
+			seen[translation.Language] = true
+			languages = append(languages, translation.Language)
+		}
+	}
+
+	responses.Success(c, "Rule translations retrieved", &gin.H{
+		"translations": translations,
+		"languages":    languages,
+	})
+}
+
+// upsertServerRuleTranslation sets a rule's text in one language
+func (s *Server) upsertServerRuleTranslation(c *gin.Context) {
+	serverID, err := uuid.Parse(c.Param("serverId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
+		return
+	}
+	ruleID, err := uuid.Parse(c.Param("ruleId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid rule ID", &gin.H{"error": err.Error()})
+		return
+	}
+	language, ok := parseRuleLanguage(c)
+	if !ok {
+		return
+	}
+
+	var req models.ServerRuleTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.BadRequest(c, "Invalid request body", &gin.H{"error": err.Error()})
+		return
+	}
+
+	// The rule must belong to the server in the path
+	translation := models.ServerRuleTranslation{RuleID: ruleID, Language: language}
+	err = s.Dependencies.DB.QueryRowContext(c.Request.Context(), `
+		INSERT INTO server_rule_translations (rule_id, language, title, description)
+		SELECT id, $3, $4, $5 FROM server_rules WHERE id = $1 AND server_id = $2
+		ON CONFLICT (rule_id, language) DO UPDATE
+		SET title = EXCLUDED.title, description = EXCLUDED.description, updated_at = NOW()
+		RETURNING title, description, created_at, updated_at
+	`, ruleID, serverID, language, req.Title, req.Description).Scan(
+		&translation.Title, &translation.Description, &translation.CreatedAt, &translation.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			responses.NotFound(c, "Rule not found", nil)
+			return
+		}
+		responses.InternalServerError(c, fmt.Errorf("failed to save rule translation: %w", err), nil)
+		return
+	}
+
+	s.TriggerMOTDUploadIfEnabled(c.Request.Context(), serverID)
+
+	responses.Success(c, "Rule translation saved", &gin.H{"translation": translation})
+}
+
+// deleteServerRuleTranslation removes a rule's text in one language
+func (s *Server) deleteServerRuleTranslation(c *gin.Context) {
+	serverID, err := uuid.Parse(c.Param("serverId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
+		return
+	}
+	ruleID, err := uuid.Parse(c.Param("ruleId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid rule ID", &gin.H{"error": err.Error()})
+		return
+	}
+	language, ok := parseRuleLanguage(c)
+	if !ok {
+		return
+	}
+
+	result, err := s.Dependencies.DB.ExecContext(c.Request.Context(), `
+		DELETE FROM server_rule_translations t
+		USING server_rules r
+		WHERE t.rule_id = r.id AND r.server_id = $1 AND t.rule_id = $2 AND t.language = $3
+	`, serverID, ruleID, language)
+	if err != nil {
+		responses.InternalServerError(c, fmt.Errorf("failed to delete rule translation: %w", err), nil)
+		return
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		responses.NotFound(c, "Rule translation not found", nil)
+		return
+	}
+
+	s.TriggerMOTDUploadIfEnabled(c.Request.Context(), serverID)
+
+	responses.Success(c, "Rule translation deleted", nil)
+}
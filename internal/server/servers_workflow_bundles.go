@@ -0,0 +1,243 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/core"
+	"go.codycody31.dev/squad-aegis/internal/models"
+	"go.codycody31.dev/squad-aegis/internal/permissions"
+	"go.codycody31.dev/squad-aegis/internal/server/responses"
+	"go.codycody31.dev/squad-aegis/internal/workflow_manager"
+)
+
+// ServerWorkflowExport exports a workflow as a portable bundle. Pass
+// include_kv=true to seed the importer's KV store with this one's contents.
+func (s *Server) ServerWorkflowExport(c *gin.Context) {
+	user := s.getUserFromSession(c)
+	if user == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return
+	}
+
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+	workflow, ok := s.loadServerWorkflow(c, workflowDB)
+	if !ok {
+		return
+	}
+
+	var kvSeed map[string]interface{}
+	if c.Query("include_kv") == "true" {
+		pairs, err := workflowDB.WorkflowKV(workflow.ID).All()
+		if err != nil {
+			responses.InternalServerError(c, err, &gin.H{"error": "Failed to get KV store"})
+			return
+		}
+		kvSeed = pairs
+	}
+
+	bundle, err := workflow_manager.ExportWorkflowBundle(workflow, kvSeed)
+	if err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to export workflow"})
+		return
+	}
+
+	responses.Success(c, "Workflow exported successfully", &gin.H{
+		"bundle": bundle,
+	})
+}
+
+// canManageServerWorkflows reports whether the request may manage workflows
+// on serverID. Imports check this for every target besides the route's own
+// server, which the route middleware already covers.
+func (s *Server) canManageServerWorkflows(c *gin.Context, user *models.User, serverID uuid.UUID) (bool, error) {
+	if token := requestAPIToken(c); token != nil && !token.AllowsServer(serverID) {
+		return false, nil
+	}
+
+	// GetServerById returns an empty server when the user has no access.
+	server, err := core.GetServerById(c.Request.Context(), s.Dependencies.DB, serverID, user)
+	if err != nil {
+		return false, err
+	}
+	if server.Id != serverID {
+		return false, nil
+	}
+
+	if user.SuperAdmin {
+		return true, nil
+	}
+	return s.Dependencies.PermissionService.HasPermission(c.Request.Context(), user.Id, serverID, permissions.UIWorkflowsManage)
+}
+
+// ServerWorkflowImport creates a workflow from a bundle on the route's server
+// and any additional servers in the request. Every target is validated
+// before anything is created.
+func (s *Server) ServerWorkflowImport(c *gin.Context) {
+	user := s.getUserFromSession(c)
+	if user == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return
+	}
+
+	serverID, err := uuid.Parse(c.Param("serverId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
+		return
+	}
+
+	var request models.WorkflowBundleImportRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := workflow_manager.ValidateWorkflowBundle(&request.Bundle); err != nil {
+		responses.BadRequest(c, "Invalid workflow bundle", &gin.H{"error": err.Error()})
+		return
+	}
+
+	targets := []uuid.UUID{serverID}
+	seen := map[uuid.UUID]bool{serverID: true}
+	for _, target := range request.ServerIDs {
+		if seen[target] {
+			continue
+		}
+		allowed, err := s.canManageServerWorkflows(c, user, target)
+		if err != nil {
+			responses.InternalServerError(c, fmt.Errorf("failed to check permissions: %w", err), nil)
+			return
+		}
+		if !allowed {
+			responses.Forbidden(c, "You cannot manage workflows on server "+target.String(), nil)
+			return
+		}
+		seen[target] = true
+		targets = append(targets, target)
+	}
+
+	name := request.Bundle.Name
+	if request.Name != nil && *request.Name != "" {
+		name = *request.Name
+	}
+
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+	resolved := make(map[uuid.UUID]*models.WorkflowBundle, len(targets))
+	for _, target := range targets {
+		values := map[string]interface{}{}
+		for name, value := range request.Parameters {
+			values[name] = value
+		}
+		for name, value := range request.ServerParameters[target] {
+			values[name] = value
+		}
+
+		bundle, err := workflow_manager.ResolveWorkflowBundle(&request.Bundle, values)
+		if err == nil {
+			err = workflow_manager.ValidateWorkflowDefinition(&bundle.Definition)
+		}
+		if err != nil {
+			responses.BadRequest(c, "Invalid parameters for server "+target.String(), &gin.H{"error": err.Error()})
+			return
+		}
+
+		// call_workflow targets have to exist on each server
+		serverWorkflows, err := workflowDB.GetWorkflowsByServerID(target)
+		if err != nil {
+			responses.InternalServerError(c, err, &gin.H{"error": "Failed to get workflows"})
+			return
+		}
+		candidate := &models.ServerWorkflow{ID: uuid.New(), ServerID: target, Name: name, Definition: bundle.Definition}
+		if err := workflow_manager.CheckWorkflowCalls(candidate, serverWorkflows); err != nil {
+			responses.BadRequest(c, "Invalid parameters for server "+target.String(), &gin.H{"error": err.Error()})
+			return
+		}
+
+		resolved[target] = bundle
+	}
+
+	changeNote := "Imported from bundle"
+	results := make([]models.WorkflowBundleImportResult, 0, len(targets))
+	for _, target := range targets {
+		bundle := resolved[target]
+		result := models.WorkflowBundleImportResult{ServerID: target}
+
+		now := time.Now()
+		workflow := &models.ServerWorkflow{
+			ID:          uuid.New(),
+			ServerID:    target,
+			Name:        name,
+			Description: bundle.Description,
+			Enabled:     request.Enabled,
+			Definition:  bundle.Definition,
+			CreatedBy:   user.Id,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+
+		if err := importWorkflowBundle(workflowDB, workflow, bundle, request.IncludeKVSeed, &changeNote); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.WorkflowID = &workflow.ID
+			s.CreateAuditLog(c.Request.Context(), &target, &user.Id, "server:workflow:import", map[string]interface{}{
+				"workflow_id": workflow.ID.String(),
+				"name":        workflow.Name,
+			})
+		}
+		results = append(results, result)
+	}
+
+	// Reload workflows in the workflow manager
+	if err := s.Dependencies.WorkflowManager.ReloadWorkflows(); err != nil {
+		// Log error but don't fail the request since workflows were created
+	}
+
+	responses.Success(c, "Workflow import completed", &gin.H{
+		"results": results,
+	})
+}
+
+// importWorkflowBundle creates workflow with the bundle's variables and,
+// when includeKV is set, its KV seed. A partly imported workflow is removed
+// again.
+func importWorkflowBundle(workflowDB *workflow_manager.WorkflowDatabase, workflow *models.ServerWorkflow, bundle *models.WorkflowBundle, includeKV bool, changeNote *string) error {
+	if err := workflowDB.CreateWorkflow(workflow, changeNote); err != nil {
+		return fmt.Errorf("failed to create workflow: %w", err)
+	}
+
+	if err := seedImportedWorkflow(workflowDB, workflow, bundle, includeKV); err != nil {
+		_ = workflowDB.DeleteWorkflow(workflow.ID)
+		return err
+	}
+
+	return nil
+}
+
+func seedImportedWorkflow(workflowDB *workflow_manager.WorkflowDatabase, workflow *models.ServerWorkflow, bundle *models.WorkflowBundle, includeKV bool) error {
+	for _, variable := range bundle.Variables {
+		err := workflowDB.CreateWorkflowVariable(&models.ServerWorkflowVariable{
+			ID:          uuid.New(),
+			WorkflowID:  workflow.ID,
+			Name:        variable.Name,
+			Value:       variable.Value,
+			Description: variable.Description,
+			CreatedAt:   workflow.CreatedAt,
+			UpdatedAt:   workflow.CreatedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create variable %s: %w", variable.Name, err)
+		}
+	}
+
+	if includeKV {
+		for key, value := range bundle.KVSeed {
+			if err := workflowDB.WorkflowKV(workflow.ID).Set(key, value, 0); err != nil {
+				return fmt.Errorf("failed to seed KV key %s: %w", key, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,565 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/models"
+	"go.codycody31.dev/squad-aegis/internal/server/responses"
+	"go.codycody31.dev/squad-aegis/internal/workflow_manager"
+)
+
+// maxKVKeyLength matches the key column
+const maxKVKeyLength = 255
+
+// workflowKVStore returns the :workflowId workflow's KV store
+func (s *Server) workflowKVStore(c *gin.Context) (*workflow_manager.KVStore, bool) {
+	if s.getUserFromSession(c) == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return nil, false
+	}
+
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+	workflow, ok := s.loadServerWorkflow(c, workflowDB)
+	if !ok {
+		return nil, false
+	}
+
+	return workflowDB.WorkflowKV(workflow.ID), true
+}
+
+// loadKVNamespace loads the :namespaceId namespace if it belongs to the
+// :serverId server
+func (s *Server) loadKVNamespace(c *gin.Context, workflowDB *workflow_manager.WorkflowDatabase) (*models.WorkflowKVNamespace, bool) {
+	serverID, err := uuid.Parse(c.Param("serverId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	namespaceID, err := uuid.Parse(c.Param("namespaceId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid namespace ID", &gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	namespace, err := workflowDB.GetKVNamespace(namespaceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			responses.NotFound(c, "Namespace not found", nil)
+			return nil, false
+		}
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get namespace"})
+		return nil, false
+	}
+
+	if namespace.ServerID != serverID {
+		responses.NotFound(c, "Namespace not found", nil)
+		return nil, false
+	}
+
+	return namespace, true
+}
+
+// namespaceKVStore returns the :namespaceId namespace's KV store
+func (s *Server) namespaceKVStore(c *gin.Context) (*workflow_manager.KVStore, bool) {
+	if s.getUserFromSession(c) == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return nil, false
+	}
+
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+	namespace, ok := s.loadKVNamespace(c, workflowDB)
+	if !ok {
+		return nil, false
+	}
+
+	return workflowDB.NamespaceKV(namespace.ID), true
+}
+
+func validateKVKey(c *gin.Context, key string, ttlSeconds int) bool {
+	if len(key) > maxKVKeyLength {
+		responses.BadRequest(c, "Key is too long (max 255 characters)", nil)
+		return false
+	}
+	if ttlSeconds < 0 {
+		responses.BadRequest(c, "ttl_seconds cannot be negative", nil)
+		return false
+	}
+	return true
+}
+
+func kvList(c *gin.Context, store *workflow_manager.KVStore) {
+	entries, err := store.Entries()
+	if err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get KV pairs"})
+		return
+	}
+
+	responses.Success(c, "KV pairs retrieved successfully", &gin.H{"kv_pairs": entries})
+}
+
+func kvGet(c *gin.Context, store *workflow_manager.KVStore) {
+	key := c.Param("key")
+	if key == "" {
+		responses.BadRequest(c, "Key is required", nil)
+		return
+	}
+
+	value, err := store.Get(key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			responses.NotFound(c, "Key not found", nil)
+			return
+		}
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get KV value"})
+		return
+	}
+
+	response := gin.H{
+		"key":   key,
+		"value": value,
+	}
+	if ttl, err := store.TTL(key); err == nil && ttl != nil {
+		response["ttl_seconds"] = int(ttl.Seconds())
+	}
+
+	responses.Success(c, "KV pair retrieved successfully", &response)
+}
+
+func kvSet(c *gin.Context, store *workflow_manager.KVStore) {
+	var req struct {
+		Key        string      `json:"key" binding:"required"`
+		Value      interface{} `json:"value" binding:"required"`
+		TTLSeconds int         `json:"ttl_seconds"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.BadRequest(c, "Invalid request body", &gin.H{"error": err.Error()})
+		return
+	}
+	if !validateKVKey(c, req.Key, req.TTLSeconds) {
+		return
+	}
+
+	if err := store.Set(req.Key, req.Value, time.Duration(req.TTLSeconds)*time.Second); err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to set KV value"})
+		return
+	}
+
+	responses.Success(c, "KV pair set successfully", &gin.H{
+		"key":   req.Key,
+		"value": req.Value,
+	})
+}
+
+func kvIncrement(c *gin.Context, store *workflow_manager.KVStore) {
+	var req struct {
+		Key        string   `json:"key" binding:"required"`
+		Delta      *float64 `json:"delta"`
+		Min        *float64 `json:"min"`
+		Max        *float64 `json:"max"`
+		TTLSeconds int      `json:"ttl_seconds"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.BadRequest(c, "Invalid request body", &gin.H{"error": err.Error()})
+		return
+	}
+	if !validateKVKey(c, req.Key, req.TTLSeconds) {
+		return
+	}
+
+	delta := 1.0
+	if req.Delta != nil {
+		delta = *req.Delta
+	}
+
+	value, err := store.Increment(req.Key, delta, workflow_manager.KVBounds{Min: req.Min, Max: req.Max}, time.Duration(req.TTLSeconds)*time.Second)
+	if err != nil {
+		switch {
+		case errors.Is(err, workflow_manager.ErrKVOutOfBounds):
+			responses.Conflict(c, "Increment would pass its bounds", &gin.H{"error": err.Error()})
+		case errors.Is(err, workflow_manager.ErrKVNotNumber):
+			responses.BadRequest(c, "Existing value is not a number", nil)
+		default:
+			responses.InternalServerError(c, err, &gin.H{"error": "Failed to increment KV value"})
+		}
+		return
+	}
+
+	responses.Success(c, "KV value incremented successfully", &gin.H{
+		"key":   req.Key,
+		"value": value,
+	})
+}
+
+func kvCompareAndSet(c *gin.Context, store *workflow_manager.KVStore) {
+	var req struct {
+		Key string `json:"key" binding:"required"`
+		// Null requires the key to be missing
+		Expected   interface{} `json:"expected"`
+		Value      interface{} `json:"value" binding:"required"`
+		TTLSeconds int         `json:"ttl_seconds"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.BadRequest(c, "Invalid request body", &gin.H{"error": err.Error()})
+		return
+	}
+	if !validateKVKey(c, req.Key, req.TTLSeconds) {
+		return
+	}
+
+	swapped, err := store.CompareAndSet(req.Key, req.Expected, req.Value, time.Duration(req.TTLSeconds)*time.Second)
+	if err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to set KV value"})
+		return
+	}
+
+	responses.Success(c, "KV compare-and-set completed", &gin.H{
+		"key":     req.Key,
+		"swapped": swapped,
+	})
+}
+
+func kvDelete(c *gin.Context, store *workflow_manager.KVStore) {
+	key := c.Param("key")
+	if key == "" {
+		responses.BadRequest(c, "Key is required", nil)
+		return
+	}
+
+	if err := store.Delete(key); err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to delete KV value"})
+		return
+	}
+
+	responses.Success(c, "KV pair deleted successfully", nil)
+}
+
+func kvClear(c *gin.Context, store *workflow_manager.KVStore) {
+	if err := store.Clear(); err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to clear KV store"})
+		return
+	}
+
+	responses.Success(c, "KV store cleared successfully", nil)
+}
+
+// ServerWorkflowKVList lists all KV pairs for a workflow
+func (s *Server) ServerWorkflowKVList(c *gin.Context) {
+	if store, ok := s.workflowKVStore(c); ok {
+		kvList(c, store)
+	}
+}
+
+// ServerWorkflowKVGet gets a specific KV pair
+func (s *Server) ServerWorkflowKVGet(c *gin.Context) {
+	if store, ok := s.workflowKVStore(c); ok {
+		kvGet(c, store)
+	}
+}
+
+// ServerWorkflowKVSet sets a KV pair, optionally with a TTL
+func (s *Server) ServerWorkflowKVSet(c *gin.Context) {
+	if store, ok := s.workflowKVStore(c); ok {
+		kvSet(c, store)
+	}
+}
+
+// ServerWorkflowKVIncrement atomically increments a numeric KV pair
+func (s *Server) ServerWorkflowKVIncrement(c *gin.Context) {
+	if store, ok := s.workflowKVStore(c); ok {
+		kvIncrement(c, store)
+	}
+}
+
+// ServerWorkflowKVCompareAndSet atomically replaces a KV pair if it holds an
+// expected value
+func (s *Server) ServerWorkflowKVCompareAndSet(c *gin.Context) {
+	if store, ok := s.workflowKVStore(c); ok {
+		kvCompareAndSet(c, store)
+	}
+}
+
+// ServerWorkflowKVDelete deletes a KV pair
+func (s *Server) ServerWorkflowKVDelete(c *gin.Context) {
+	if store, ok := s.workflowKVStore(c); ok {
+		kvDelete(c, store)
+	}
+}
+
+// ServerWorkflowKVClear clears all KV pairs for a workflow
+func (s *Server) ServerWorkflowKVClear(c *gin.Context) {
+	if store, ok := s.workflowKVStore(c); ok {
+		kvClear(c, store)
+	}
+}
+
+// ServerKVNamespaceEntriesList lists all KV pairs in a shared namespace
+func (s *Server) ServerKVNamespaceEntriesList(c *gin.Context) {
+	if store, ok := s.namespaceKVStore(c); ok {
+		kvList(c, store)
+	}
+}
+
+// ServerKVNamespaceEntryGet gets a KV pair in a shared namespace
+func (s *Server) ServerKVNamespaceEntryGet(c *gin.Context) {
+	if store, ok := s.namespaceKVStore(c); ok {
+		kvGet(c, store)
+	}
+}
+
+// ServerKVNamespaceEntrySet sets a KV pair in a shared namespace
+func (s *Server) ServerKVNamespaceEntrySet(c *gin.Context) {
+	if store, ok := s.namespaceKVStore(c); ok {
+		kvSet(c, store)
+	}
+}
+
+// ServerKVNamespaceEntryIncrement atomically increments a numeric KV pair in
+// a shared namespace
+func (s *Server) ServerKVNamespaceEntryIncrement(c *gin.Context) {
+	if store, ok := s.namespaceKVStore(c); ok {
+		kvIncrement(c, store)
+	}
+}
+
+// ServerKVNamespaceEntryCompareAndSet atomically replaces a KV pair in a
+// shared namespace if it holds an expected value
+func (s *Server) ServerKVNamespaceEntryCompareAndSet(c *gin.Context) {
+	if store, ok := s.namespaceKVStore(c); ok {
+		kvCompareAndSet(c, store)
+	}
+}
+
+// ServerKVNamespaceEntryDelete deletes a KV pair in a shared namespace
+func (s *Server) ServerKVNamespaceEntryDelete(c *gin.Context) {
+	if store, ok := s.namespaceKVStore(c); ok {
+		kvDelete(c, store)
+	}
+}
+
+// ServerKVNamespaceEntriesClear clears all KV pairs in a shared namespace
+func (s *Server) ServerKVNamespaceEntriesClear(c *gin.Context) {
+	if store, ok := s.namespaceKVStore(c); ok {
+		kvClear(c, store)
+	}
+}
+
+// validateKVGrants checks that grants name distinct workflows on serverID
+// with a known access level
+func validateKVGrants(c *gin.Context, workflowDB *workflow_manager.WorkflowDatabase, serverID uuid.UUID, grants []models.WorkflowKVGrant) bool {
+	serverWorkflows, err := workflowDB.GetWorkflowsByServerID(serverID)
+	if err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get workflows"})
+		return false
+	}
+
+	onServer := make(map[uuid.UUID]bool, len(serverWorkflows))
+	for _, workflow := range serverWorkflows {
+		onServer[workflow.ID] = true
+	}
+
+	seen := map[uuid.UUID]bool{}
+	for _, grant := range grants {
+		if grant.Access != models.KVAccessRead && grant.Access != models.KVAccessWrite {
+			responses.BadRequest(c, "Grant access must be read or write", nil)
+			return false
+		}
+		if !onServer[grant.WorkflowID] {
+			responses.BadRequest(c, "Grants can only name workflows on this server", &gin.H{"workflow_id": grant.WorkflowID})
+			return false
+		}
+		if seen[grant.WorkflowID] {
+			responses.BadRequest(c, "A workflow can only be granted access once", &gin.H{"workflow_id": grant.WorkflowID})
+			return false
+		}
+		seen[grant.WorkflowID] = true
+	}
+
+	return true
+}
+
+// ServerKVNamespacesList lists a server's shared KV namespaces
+func (s *Server) ServerKVNamespacesList(c *gin.Context) {
+	serverID, err := uuid.Parse(c.Param("serverId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
+		return
+	}
+
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+	namespaces, err := workflowDB.ListKVNamespaces(serverID)
+	if err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to list namespaces"})
+		return
+	}
+
+	responses.Success(c, "Namespaces retrieved successfully", &gin.H{"namespaces": namespaces})
+}
+
+// ServerKVNamespaceGet returns a shared KV namespace with its grants
+func (s *Server) ServerKVNamespaceGet(c *gin.Context) {
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+	namespace, ok := s.loadKVNamespace(c, workflowDB)
+	if !ok {
+		return
+	}
+
+	responses.Success(c, "Namespace retrieved successfully", &gin.H{"namespace": namespace})
+}
+
+// ServerKVNamespaceCreate creates a shared KV namespace
+func (s *Server) ServerKVNamespaceCreate(c *gin.Context) {
+	user := s.getUserFromSession(c)
+	if user == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return
+	}
+
+	serverID, err := uuid.Parse(c.Param("serverId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
+		return
+	}
+
+	var request models.WorkflowKVNamespaceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+		return
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	if !workflow_manager.ValidKVNamespaceName(request.Name) {
+		responses.BadRequest(c, "Namespace names must be lowercase letters, digits, dots, dashes or underscores", nil)
+		return
+	}
+
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+	if !validateKVGrants(c, workflowDB, serverID, request.Grants) {
+		return
+	}
+
+	existing, err := workflowDB.ListKVNamespaces(serverID)
+	if err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to list namespaces"})
+		return
+	}
+	for _, namespace := range existing {
+		if namespace.Name == request.Name {
+			responses.Conflict(c, "A namespace with this name already exists", nil)
+			return
+		}
+	}
+
+	now := time.Now()
+	namespace := &models.WorkflowKVNamespace{
+		ID:          uuid.New(),
+		ServerID:    serverID,
+		Name:        request.Name,
+		Description: request.Description,
+		Grants:      request.Grants,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if namespace.Grants == nil {
+		namespace.Grants = []models.WorkflowKVGrant{}
+	}
+
+	if err := workflowDB.CreateKVNamespace(namespace); err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to create namespace"})
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), &serverID, &user.Id, "server:workflow_kv_namespace:create", map[string]interface{}{
+		"namespace_id": namespace.ID.String(),
+		"name":         namespace.Name,
+		"grants":       namespace.Grants,
+	})
+
+	responses.Success(c, "Namespace created successfully", &gin.H{"namespace": namespace})
+}
+
+// ServerKVNamespaceUpdate updates a shared KV namespace's description and
+// replaces its grants
+func (s *Server) ServerKVNamespaceUpdate(c *gin.Context) {
+	user := s.getUserFromSession(c)
+	if user == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return
+	}
+
+	var request models.WorkflowKVNamespaceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+		return
+	}
+
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+	namespace, ok := s.loadKVNamespace(c, workflowDB)
+	if !ok {
+		return
+	}
+
+	if request.Name != "" && request.Name != namespace.Name {
+		responses.BadRequest(c, "Namespaces cannot be renamed", nil)
+		return
+	}
+	if !validateKVGrants(c, workflowDB, namespace.ServerID, request.Grants) {
+		return
+	}
+
+	if request.Description != nil {
+		namespace.Description = request.Description
+	}
+	namespace.Grants = request.Grants
+	if namespace.Grants == nil {
+		namespace.Grants = []models.WorkflowKVGrant{}
+	}
+
+	if err := workflowDB.UpdateKVNamespace(namespace); err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to update namespace"})
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), &namespace.ServerID, &user.Id, "server:workflow_kv_namespace:update", map[string]interface{}{
+		"namespace_id": namespace.ID.String(),
+		"name":         namespace.Name,
+		"grants":       namespace.Grants,
+	})
+
+	responses.Success(c, "Namespace updated successfully", &gin.H{"namespace": namespace})
+}
+
+// ServerKVNamespaceDelete deletes a shared KV namespace and its entries
+func (s *Server) ServerKVNamespaceDelete(c *gin.Context) {
+	user := s.getUserFromSession(c)
+	if user == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return
+	}
+
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+	namespace, ok := s.loadKVNamespace(c, workflowDB)
+	if !ok {
+		return
+	}
+
+	if err := workflowDB.DeleteKVNamespace(namespace.ID); err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to delete namespace"})
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), &namespace.ServerID, &user.Id, "server:workflow_kv_namespace:delete", map[string]interface{}{
+		"namespace_id": namespace.ID.String(),
+		"name":         namespace.Name,
+	})
+
+	responses.SimpleSuccess(c, "Namespace deleted successfully")
+}
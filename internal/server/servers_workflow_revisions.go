@@ -0,0 +1,253 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/models"
+	"go.codycody31.dev/squad-aegis/internal/server/responses"
+	"go.codycody31.dev/squad-aegis/internal/workflow_manager"
+)
+
+// loadServerWorkflow loads the :workflowId workflow if it belongs to the
+// :serverId server.
+func (s *Server) loadServerWorkflow(c *gin.Context, workflowDB *workflow_manager.WorkflowDatabase) (*models.ServerWorkflow, bool) {
+	serverID, err := uuid.Parse(c.Param("serverId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	workflowID, err := uuid.Parse(c.Param("workflowId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid workflow ID", &gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	workflow, err := workflowDB.GetWorkflow(workflowID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			responses.NotFound(c, "Workflow not found", nil)
+			return nil, false
+		}
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get workflow"})
+		return nil, false
+	}
+
+	if workflow.ServerID != serverID {
+		responses.NotFound(c, "Workflow not found", nil)
+		return nil, false
+	}
+
+	return workflow, true
+}
+
+// loadWorkflowRevision loads a revision of workflow by the ID in raw
+func loadWorkflowRevision(c *gin.Context, workflowDB *workflow_manager.WorkflowDatabase, workflow *models.ServerWorkflow, raw string) (*models.ServerWorkflowRevision, bool) {
+	revisionID, err := uuid.Parse(raw)
+	if err != nil {
+		responses.BadRequest(c, "Invalid revision ID", &gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	revision, err := workflowDB.GetWorkflowRevision(workflow.ID, revisionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			responses.NotFound(c, "Revision not found", nil)
+			return nil, false
+		}
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get revision"})
+		return nil, false
+	}
+
+	return revision, true
+}
+
+// ServerWorkflowRevisionsList returns a workflow's revisions, newest first
+func (s *Server) ServerWorkflowRevisionsList(c *gin.Context) {
+	user := s.getUserFromSession(c)
+	if user == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 1000 {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+	workflow, ok := s.loadServerWorkflow(c, workflowDB)
+	if !ok {
+		return
+	}
+
+	revisions, err := workflowDB.ListWorkflowRevisions(workflow.ID, limit, offset)
+	if err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get revisions"})
+		return
+	}
+
+	responses.Success(c, "Revisions retrieved successfully", &gin.H{
+		"revisions":           revisions,
+		"current_revision_id": workflow.CurrentRevisionID,
+		"limit":               limit,
+		"offset":              offset,
+	})
+}
+
+// ServerWorkflowRevisionGet returns one revision of a workflow
+func (s *Server) ServerWorkflowRevisionGet(c *gin.Context) {
+	user := s.getUserFromSession(c)
+	if user == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return
+	}
+
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+	workflow, ok := s.loadServerWorkflow(c, workflowDB)
+	if !ok {
+		return
+	}
+
+	revision, ok := loadWorkflowRevision(c, workflowDB, workflow, c.Param("revisionId"))
+	if !ok {
+		return
+	}
+
+	responses.Success(c, "Revision retrieved successfully", &gin.H{
+		"revision": revision,
+	})
+}
+
+// ServerWorkflowRevisionDiff compares two revisions. "to" defaults to the
+// workflow's current revision.
+func (s *Server) ServerWorkflowRevisionDiff(c *gin.Context) {
+	user := s.getUserFromSession(c)
+	if user == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return
+	}
+
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+	workflow, ok := s.loadServerWorkflow(c, workflowDB)
+	if !ok {
+		return
+	}
+
+	from, ok := loadWorkflowRevision(c, workflowDB, workflow, c.Query("from"))
+	if !ok {
+		return
+	}
+
+	toID := c.Query("to")
+	if toID == "" {
+		if workflow.CurrentRevisionID == nil {
+			responses.BadRequest(c, "Workflow has no current revision", nil)
+			return
+		}
+		toID = workflow.CurrentRevisionID.String()
+	}
+	to, ok := loadWorkflowRevision(c, workflowDB, workflow, toID)
+	if !ok {
+		return
+	}
+
+	changes, err := workflow_manager.DiffWorkflowRevisions(from, to)
+	if err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to compare revisions"})
+		return
+	}
+
+	responses.Success(c, "Revisions compared successfully", &gin.H{
+		"from":    from,
+		"to":      to,
+		"changes": changes,
+	})
+}
+
+// ServerWorkflowRevisionRollback restores a workflow to an earlier revision.
+// History is never rewritten: the restored content is saved as a new
+// revision that records which one it came from.
+func (s *Server) ServerWorkflowRevisionRollback(c *gin.Context) {
+	user := s.getUserFromSession(c)
+	if user == nil {
+		responses.Unauthorized(c, "Unauthorized", nil)
+		return
+	}
+
+	var request struct {
+		ChangeNote *string `json:"change_note,omitempty"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
+	workflow, ok := s.loadServerWorkflow(c, workflowDB)
+	if !ok {
+		return
+	}
+
+	target, ok := loadWorkflowRevision(c, workflowDB, workflow, c.Param("revisionId"))
+	if !ok {
+		return
+	}
+
+	if workflow.CurrentRevisionID != nil && *workflow.CurrentRevisionID == target.ID {
+		responses.BadRequest(c, "Revision is already current", nil)
+		return
+	}
+
+	changeNote := request.ChangeNote
+	if changeNote == nil {
+		note := "Rolled back to revision " + strconv.Itoa(target.Revision)
+		changeNote = &note
+	}
+
+	workflow.Name = target.Name
+	workflow.Description = target.Description
+	workflow.Definition = target.Definition
+	workflow.UpdatedAt = time.Now()
+
+	// The workflows an old revision called may have changed since
+	if !s.checkWorkflowCalls(c, workflowDB, workflow) {
+		return
+	}
+
+	revision, err := workflowDB.UpdateWorkflowWithRevision(workflow, user.Id, changeNote, &target.ID)
+	if err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to roll back workflow"})
+		return
+	}
+
+	// Reload workflows in the workflow manager
+	if err := s.Dependencies.WorkflowManager.ReloadWorkflows(); err != nil {
+		// Log error but don't fail the request since workflow was rolled back
+	}
+
+	s.CreateAuditLog(c.Request.Context(), &workflow.ServerID, &user.Id, "server:workflow:rollback", map[string]interface{}{
+		"workflow_id":       workflow.ID.String(),
+		"revision_id":       revision.ID.String(),
+		"revision":          revision.Revision,
+		"restored_from":     target.ID.String(),
+		"restored_revision": target.Revision,
+	})
+
+	responses.Success(c, "Workflow rolled back successfully", &gin.H{
+		"workflow": workflow,
+		"revision": revision,
+	})
+}
@@ -125,8 +125,17 @@ func (s *Server) ServerWorkflowCreate(c *gin.Context) {
 		UpdatedAt:   time.Now(),
 	}
 
+	if err := workflow_manager.ValidateWorkflowConcurrency(workflow.Definition.Concurrency); err != nil {
+		responses.BadRequest(c, "Invalid concurrency settings", &gin.H{"error": err.Error()})
+		return
+	}
+
 	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
-	if err := workflowDB.CreateWorkflow(workflow); err != nil {
+	if !s.checkWorkflowCalls(c, workflowDB, workflow) {
+		return
+	}
+
+	if err := workflowDB.CreateWorkflow(workflow, request.ChangeNote); err != nil {
 		responses.InternalServerError(c, err, &gin.H{"error": "Failed to create workflow"})
 		return
 	}
@@ -187,6 +196,8 @@ func (s *Server) ServerWorkflowUpdate(c *gin.Context) {
 		return
 	}
 
+	before := workflow_manager.WorkflowRevisionContent(workflow)
+
 	// Update fields
 	if request.Name != nil {
 		workflow.Name = *request.Name
@@ -202,8 +213,29 @@ func (s *Server) ServerWorkflowUpdate(c *gin.Context) {
 	}
 	workflow.UpdatedAt = time.Now()
 
-	// Update workflow
-	if err := workflowDB.UpdateWorkflow(workflow); err != nil {
+	// Only changes to what the workflow does get a new revision; toggling
+	// enabled does not.
+	if workflow_manager.WorkflowRevisionContent(workflow) != before {
+		if err := workflow_manager.ValidateWorkflowConcurrency(workflow.Definition.Concurrency); err != nil {
+			responses.BadRequest(c, "Invalid concurrency settings", &gin.H{"error": err.Error()})
+			return
+		}
+		if !s.checkWorkflowCalls(c, workflowDB, workflow) {
+			return
+		}
+
+		revision, err := workflowDB.UpdateWorkflowWithRevision(workflow, user.Id, request.ChangeNote, nil)
+		if err != nil {
+			responses.InternalServerError(c, err, &gin.H{"error": "Failed to update workflow"})
+			return
+		}
+
+		s.CreateAuditLog(c.Request.Context(), &serverID, &user.Id, "server:workflow:revision", map[string]interface{}{
+			"workflow_id": workflow.ID.String(),
+			"revision_id": revision.ID.String(),
+			"revision":    revision.Revision,
+		})
+	} else if err := workflowDB.UpdateWorkflow(workflow); err != nil {
 		responses.InternalServerError(c, err, &gin.H{"error": "Failed to update workflow"})
 		return
 	}
@@ -218,6 +250,23 @@ func (s *Server) ServerWorkflowUpdate(c *gin.Context) {
 	})
 }
 
+// checkWorkflowCalls rejects a workflow whose call_workflow steps target
+// other servers' workflows or would form a call cycle once it is saved.
+func (s *Server) checkWorkflowCalls(c *gin.Context, workflowDB *workflow_manager.WorkflowDatabase, workflow *models.ServerWorkflow) bool {
+	serverWorkflows, err := workflowDB.GetWorkflowsByServerID(workflow.ServerID)
+	if err != nil {
+		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get workflows"})
+		return false
+	}
+
+	if err := workflow_manager.CheckWorkflowCalls(workflow, serverWorkflows); err != nil {
+		responses.BadRequest(c, "Invalid workflow call", &gin.H{"error": err.Error()})
+		return false
+	}
+
+	return true
+}
+
 // ServerWorkflowDelete deletes a workflow
 func (s *Server) ServerWorkflowDelete(c *gin.Context) {
 	user := s.getUserFromSession(c)
@@ -916,292 +965,3 @@ func (s *Server) ServerWorkflowVariableDelete(c *gin.Context) {
 
 	responses.Success(c, "Variable deleted successfully", nil)
 }
-
-// ServerWorkflowKVList lists all KV pairs for a workflow
-func (s *Server) ServerWorkflowKVList(c *gin.Context) {
-	user := s.getUserFromSession(c)
-	if user == nil {
-		responses.Unauthorized(c, "Unauthorized", nil)
-		return
-	}
-
-	serverID, err := uuid.Parse(c.Param("serverId"))
-	if err != nil {
-		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
-		return
-	}
-
-	workflowID, err := uuid.Parse(c.Param("workflowId"))
-	if err != nil {
-		responses.BadRequest(c, "Invalid workflow ID", &gin.H{"error": err.Error()})
-		return
-	}
-
-	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
-
-	// Verify workflow exists and belongs to server
-	workflow, err := workflowDB.GetWorkflow(workflowID)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			responses.NotFound(c, "Workflow not found", nil)
-			return
-		}
-		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get workflow"})
-		return
-	}
-
-	if workflow.ServerID != serverID {
-		responses.NotFound(c, "Workflow not found", nil)
-		return
-	}
-
-	// Get all KV pairs
-	kvPairs, err := workflowDB.GetAllKVPairs(workflowID)
-	if err != nil {
-		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get KV pairs"})
-		return
-	}
-
-	// Convert map to array for consistent API response
-	type KVPairResponse struct {
-		Key   string      `json:"key"`
-		Value interface{} `json:"value"`
-	}
-
-	kvArray := make([]KVPairResponse, 0, len(kvPairs))
-	for key, value := range kvPairs {
-		kvArray = append(kvArray, KVPairResponse{
-			Key:   key,
-			Value: value,
-		})
-	}
-
-	responses.Success(c, "KV pairs retrieved successfully", &gin.H{"kv_pairs": kvArray})
-}
-
-// ServerWorkflowKVGet gets a specific KV pair
-func (s *Server) ServerWorkflowKVGet(c *gin.Context) {
-	user := s.getUserFromSession(c)
-	if user == nil {
-		responses.Unauthorized(c, "Unauthorized", nil)
-		return
-	}
-
-	serverID, err := uuid.Parse(c.Param("serverId"))
-	if err != nil {
-		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
-		return
-	}
-
-	workflowID, err := uuid.Parse(c.Param("workflowId"))
-	if err != nil {
-		responses.BadRequest(c, "Invalid workflow ID", &gin.H{"error": err.Error()})
-		return
-	}
-
-	key := c.Param("key")
-	if key == "" {
-		responses.BadRequest(c, "Key is required", nil)
-		return
-	}
-
-	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
-
-	// Verify workflow exists and belongs to server
-	workflow, err := workflowDB.GetWorkflow(workflowID)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			responses.NotFound(c, "Workflow not found", nil)
-			return
-		}
-		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get workflow"})
-		return
-	}
-
-	if workflow.ServerID != serverID {
-		responses.NotFound(c, "Workflow not found", nil)
-		return
-	}
-
-	// Get KV value
-	value, err := workflowDB.GetKVValue(workflowID, key)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			responses.NotFound(c, "Key not found", nil)
-			return
-		}
-		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get KV value"})
-		return
-	}
-
-	responses.Success(c, "KV pair retrieved successfully", &gin.H{
-		"key":   key,
-		"value": value,
-	})
-}
-
-// ServerWorkflowKVSet sets a KV pair
-func (s *Server) ServerWorkflowKVSet(c *gin.Context) {
-	user := s.getUserFromSession(c)
-	if user == nil {
-		responses.Unauthorized(c, "Unauthorized", nil)
-		return
-	}
-
-	serverID, err := uuid.Parse(c.Param("serverId"))
-	if err != nil {
-		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
-		return
-	}
-
-	workflowID, err := uuid.Parse(c.Param("workflowId"))
-	if err != nil {
-		responses.BadRequest(c, "Invalid workflow ID", &gin.H{"error": err.Error()})
-		return
-	}
-
-	var req struct {
-		Key   string      `json:"key" binding:"required"`
-		Value interface{} `json:"value" binding:"required"`
-	}
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		responses.BadRequest(c, "Invalid request body", &gin.H{"error": err.Error()})
-		return
-	}
-
-	// Validate key length
-	if len(req.Key) > 255 {
-		responses.BadRequest(c, "Key is too long (max 255 characters)", nil)
-		return
-	}
-
-	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
-
-	// Verify workflow exists and belongs to server
-	workflow, err := workflowDB.GetWorkflow(workflowID)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			responses.NotFound(c, "Workflow not found", nil)
-			return
-		}
-		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get workflow"})
-		return
-	}
-
-	if workflow.ServerID != serverID {
-		responses.NotFound(c, "Workflow not found", nil)
-		return
-	}
-
-	// Set KV value
-	if err := workflowDB.SetKVValue(workflowID, req.Key, req.Value); err != nil {
-		responses.InternalServerError(c, err, &gin.H{"error": "Failed to set KV value"})
-		return
-	}
-
-	responses.Success(c, "KV pair set successfully", &gin.H{
-		"key":   req.Key,
-		"value": req.Value,
-	})
-}
-
-// ServerWorkflowKVDelete deletes a KV pair
-func (s *Server) ServerWorkflowKVDelete(c *gin.Context) {
-	user := s.getUserFromSession(c)
-	if user == nil {
-		responses.Unauthorized(c, "Unauthorized", nil)
-		return
-	}
-
-	serverID, err := uuid.Parse(c.Param("serverId"))
-	if err != nil {
-		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
-		return
-	}
-
-	workflowID, err := uuid.Parse(c.Param("workflowId"))
-	if err != nil {
-		responses.BadRequest(c, "Invalid workflow ID", &gin.H{"error": err.Error()})
-		return
-	}
-
-	key := c.Param("key")
-	if key == "" {
-		responses.BadRequest(c, "Key is required", nil)
-		return
-	}
-
-	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
-
-	// Verify workflow exists and belongs to server
-	workflow, err := workflowDB.GetWorkflow(workflowID)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			responses.NotFound(c, "Workflow not found", nil)
-			return
-		}
-		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get workflow"})
-		return
-	}
-
-	if workflow.ServerID != serverID {
-		responses.NotFound(c, "Workflow not found", nil)
-		return
-	}
-
-	// Delete KV value
-	if err := workflowDB.DeleteKVValue(workflowID, key); err != nil {
-		responses.InternalServerError(c, err, &gin.H{"error": "Failed to delete KV value"})
-		return
-	}
-
-	responses.Success(c, "KV pair deleted successfully", nil)
-}
-
-// ServerWorkflowKVClear clears all KV pairs for a workflow
-func (s *Server) ServerWorkflowKVClear(c *gin.Context) {
-	user := s.getUserFromSession(c)
-	if user == nil {
-		responses.Unauthorized(c, "Unauthorized", nil)
-		return
-	}
-
-	serverID, err := uuid.Parse(c.Param("serverId"))
-	if err != nil {
-		responses.BadRequest(c, "Invalid server ID", &gin.H{"error": err.Error()})
-		return
-	}
-
-	workflowID, err := uuid.Parse(c.Param("workflowId"))
-	if err != nil {
-		responses.BadRequest(c, "Invalid workflow ID", &gin.H{"error": err.Error()})
-		return
-	}
-
-	workflowDB := workflow_manager.NewWorkflowDatabase(s.Dependencies.DB)
-
-	// Verify workflow exists and belongs to server
-	workflow, err := workflowDB.GetWorkflow(workflowID)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			responses.NotFound(c, "Workflow not found", nil)
-			return
-		}
-		responses.InternalServerError(c, err, &gin.H{"error": "Failed to get workflow"})
-		return
-	}
-
-	if workflow.ServerID != serverID {
-		responses.NotFound(c, "Workflow not found", nil)
-		return
-	}
-
-	// Clear KV store
-	if err := workflowDB.ClearKVStore(workflowID); err != nil {
-		responses.InternalServerError(c, err, &gin.H{"error": "Failed to clear KV store"})
-		return
-	}
-
-	responses.Success(c, "KV store cleared successfully", nil)
-}
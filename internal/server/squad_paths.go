@@ -16,6 +16,10 @@ func buildBansCfgPath(basePath string, logSourceType *string) string {
 	return utils.BuildSquadServerPath(basePath, utils.IsRemoteProtocolPtr(logSourceType), utils.SquadGameBansRelPath)
 }
 
+func buildServerConfigPath(basePath string, logSourceType *string, relPath string) string {
+	return utils.BuildSquadServerPath(basePath, utils.IsRemoteProtocolPtr(logSourceType), relPath)
+}
+
 func buildMotdPath(basePath string, protocol string) string {
 	return utils.BuildSquadServerPath(basePath, utils.IsRemoteProtocol(protocol), utils.SquadGameMotdRelPath)
 }
@@ -8,6 +8,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/audit"
+	"go.codycody31.dev/squad-aegis/internal/core"
 	"go.codycody31.dev/squad-aegis/internal/server/responses"
 )
 
@@ -21,6 +23,9 @@ type GlobalAuditLogEntry struct {
 	Action     string          `json:"action"`
 	Changes    json.RawMessage `json:"changes,omitempty"`
 	Timestamp  time.Time       `json:"timestamp"`
+	// Set when the action was performed with a personal API token.
+	APITokenID   *uuid.UUID `json:"api_token_id,omitempty"`
+	APITokenName string     `json:"api_token_name,omitempty"`
 }
 
 // GlobalAuditStatsResponse represents audit log statistics
@@ -71,10 +76,11 @@ func (s *Server) GetGlobalAuditLogs(c *gin.Context) {
 	// Build the base query. COALESCE prefers the live username, falls back to the
 	// snapshot captured at write time for deleted users.
 	query := `
-		SELECT al.id, al.server_id, s.name as server_name, al.user_id, COALESCE(u.username, al.username_snapshot), al.action, al.changes, al.timestamp
+		SELECT al.id, al.server_id, s.name as server_name, al.user_id, COALESCE(u.username, al.username_snapshot), al.action, al.changes, al.timestamp, al.api_token_id, t.name
 		FROM audit_logs al
 		LEFT JOIN users u ON al.user_id = u.id
 		LEFT JOIN servers s ON al.server_id = s.id
+		LEFT JOIN api_tokens t ON al.api_token_id = t.id
 		WHERE 1=1
 	`
 	countQuery := `
@@ -197,7 +203,7 @@ func (s *Server) GetGlobalAuditLogs(c *gin.Context) {
 	logs := []GlobalAuditLogEntry{}
 	for rows.Next() {
 		var log GlobalAuditLogEntry
-		var serverName, username sql.NullString
+		var serverName, username, tokenName sql.NullString
 
 		err := rows.Scan(
 			&log.ID,
@@ -208,10 +214,13 @@ func (s *Server) GetGlobalAuditLogs(c *gin.Context) {
 			&log.Action,
 			&log.Changes,
 			&log.Timestamp,
+			&log.APITokenID,
+			&tokenName,
 		)
 		if err != nil {
 			continue
 		}
+		log.APITokenName = tokenName.String
 
 		if serverName.Valid {
 			log.ServerName = serverName.String
@@ -355,7 +364,8 @@ func (s *Server) ExportGlobalAuditLogs(c *gin.Context) {
 
 	// Build query with filters (same as GetGlobalAuditLogs but without pagination)
 	query := `
-		SELECT al.id, al.server_id, s.name as server_name, al.user_id, COALESCE(u.username, al.username_snapshot), al.action, al.changes, al.timestamp
+		SELECT al.id, al.server_id, s.name as server_name, al.user_id, COALESCE(u.username, al.username_snapshot), al.action, al.changes, al.timestamp,
+			al.chain_key, al.chain_seq, al.prev_hash, al.entry_hash
 		FROM audit_logs al
 		LEFT JOIN users u ON al.user_id = u.id
 		LEFT JOIN servers s ON al.server_id = s.id
@@ -411,12 +421,15 @@ func (s *Server) ExportGlobalAuditLogs(c *gin.Context) {
 	defer rows.Close()
 
 	// Build CSV
-	csv := "ID,Timestamp,Server,User,Action,Changes\n"
+	csv := "ID,Timestamp,Server,User,Action,Changes,Chain,Sequence,Previous Hash,Entry Hash\n"
 	for rows.Next() {
 		var log GlobalAuditLogEntry
 		var serverName, username sql.NullString
+		var chainKey, prevHash, entryHash sql.NullString
+		var chainSeq sql.NullInt64
 
-		if rows.Scan(&log.ID, &log.ServerID, &serverName, &log.UserID, &username, &log.Action, &log.Changes, &log.Timestamp) == nil {
+		if rows.Scan(&log.ID, &log.ServerID, &serverName, &log.UserID, &username, &log.Action, &log.Changes, &log.Timestamp,
+			&chainKey, &chainSeq, &prevHash, &entryHash) == nil {
 			srvName := "N/A"
 			if serverName.Valid {
 				srvName = serverName.String
@@ -433,13 +446,23 @@ func (s *Server) ExportGlobalAuditLogs(c *gin.Context) {
 			}
 			changes = fmt.Sprintf("%q", changes)
 
-			csv += fmt.Sprintf("%s,%s,%q,%q,%q,%s\n",
+			// Chain columns are empty for entries written before hash chaining
+			seq := ""
+			if chainSeq.Valid {
+				seq = fmt.Sprintf("%d", chainSeq.Int64)
+			}
+
+			csv += fmt.Sprintf("%s,%s,%q,%q,%q,%s,%s,%s,%s,%s\n",
 				log.ID.String(),
 				log.Timestamp.Format("2006-01-02 15:04:05"),
 				srvName,
 				usrName,
 				log.Action,
 				changes,
+				chainKey.String,
+				seq,
+				prevHash.String,
+				entryHash.String,
 			)
 		}
 	}
@@ -450,3 +473,88 @@ func (s *Server) ExportGlobalAuditLogs(c *gin.Context) {
 	c.Header("Content-Type", "text/csv")
 	c.String(200, csv)
 }
+
+// VerifyAuditChains walks the audit hash chains and reports any entries that
+// were modified, removed or reordered. The optional chain query parameter
+// limits verification to one chain ("global" or a server ID).
+func (s *Server) VerifyAuditChains(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var chains []string
+	if chain := c.Query("chain"); chain != "" {
+		if chain != audit.GlobalChain {
+			if _, err := uuid.Parse(chain); err != nil {
+				responses.BadRequest(c, "Invalid chain", &gin.H{"error": "chain must be \"global\" or a server ID"})
+				return
+			}
+		}
+		chains = []string{chain}
+	} else {
+		var err error
+		chains, err = core.ListAuditChains(ctx, s.Dependencies.DB)
+		if err != nil {
+			responses.InternalServerError(c, err, nil)
+			return
+		}
+	}
+
+	trustedKeyId := ""
+	if s.Dependencies.AuditSigner != nil {
+		trustedKeyId = s.Dependencies.AuditSigner.KeyID()
+	}
+
+	valid := true
+	reports := make([]*core.AuditChainReport, 0, len(chains))
+	for _, chain := range chains {
+		report, err := core.VerifyAuditChain(ctx, s.Dependencies.DB, chain, trustedKeyId)
+		if err != nil {
+			responses.InternalServerError(c, err, nil)
+			return
+		}
+		valid = valid && report.Valid
+		reports = append(reports, report)
+	}
+
+	responses.Success(c, "Audit chains verified", &gin.H{
+		"valid":          valid,
+		"signing_key_id": trustedKeyId,
+		"chains":         reports,
+	})
+}
+
+// GetAuditCheckpoints lists the signed checkpoints for one audit chain
+func (s *Server) GetAuditCheckpoints(c *gin.Context) {
+	chain := c.DefaultQuery("chain", audit.GlobalChain)
+
+	limit := 100
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limitInt, err := parseInt(limitStr); err == nil && limitInt > 0 && limitInt <= 1000 {
+			limit = limitInt
+		}
+	}
+
+	checkpoints, err := core.ListAuditCheckpoints(c.Request.Context(), s.Dependencies.DB, chain, limit)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	responses.Success(c, "Audit checkpoints fetched successfully", &gin.H{"checkpoints": checkpoints})
+}
+
+// CreateAuditCheckpoints signs the current head of every audit chain without
+// waiting for the next scheduled checkpoint.
+func (s *Server) CreateAuditCheckpoints(c *gin.Context) {
+	if s.Dependencies.AuditSigner == nil {
+		responses.BadRequest(c, "Audit checkpoint signing is not configured", nil)
+		return
+	}
+
+	created, err := core.CreateAuditCheckpoints(c.Request.Context(), s.Dependencies.DB, s.Dependencies.AuditSigner)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	responses.Success(c, "Audit checkpoints created", &gin.H{"created": created})
+}
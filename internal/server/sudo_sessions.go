@@ -21,6 +21,7 @@ type SessionInfo struct {
 	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
 	LastSeen      time.Time  `json:"last_seen"`
 	LastSeenIP    string     `json:"last_seen_ip"`
+	AuthMethod    string     `json:"auth_method"`
 	IsExpired     bool       `json:"is_expired"`
 	TimeRemaining string     `json:"time_remaining"`
 }
@@ -50,7 +51,7 @@ func (s *Server) GetAllSessions(c *gin.Context) {
 
 	// Build query
 	query := `
-		SELECT s.id, s.user_id, u.username, s.token, s.created_at, s.expires_at, s.last_seen, s.last_seen_ip
+		SELECT s.id, s.user_id, u.username, s.token, s.created_at, s.expires_at, s.last_seen, s.last_seen_ip, s.auth_method
 		FROM sessions s
 		LEFT JOIN users u ON s.user_id = u.id
 		WHERE 1=1
@@ -102,6 +103,7 @@ func (s *Server) GetAllSessions(c *gin.Context) {
 			&expiresAt,
 			&session.LastSeen,
 			&lastSeenIP,
+			&session.AuthMethod,
 		)
 		if err != nil {
 			continue
@@ -123,6 +123,7 @@ func (s *Server) GetSystemConfig(c *gin.Context) {
 			"allow_unsafe_sideload":    cfg.Plugins.AllowUnsafeSideload,
 			"max_upload_size":          cfg.Plugins.MaxUploadSize,
 			"trusted_signing_keys_set": strings.TrimSpace(cfg.Plugins.TrustedSigningKeys) != "",
+			"catalogs_configured":      strings.TrimSpace(cfg.Plugins.CatalogUrls) != "",
 		},
 		Log: gin.H{
 			"level":            cfg.Log.Level,
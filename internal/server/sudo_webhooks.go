@@ -0,0 +1,355 @@
+package server
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"go.codycody31.dev/squad-aegis/internal/core"
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
+	"go.codycody31.dev/squad-aegis/internal/models"
+	"go.codycody31.dev/squad-aegis/internal/server/responses"
+	"go.codycody31.dev/squad-aegis/internal/shared/utils"
+	"go.codycody31.dev/squad-aegis/internal/webhook_dispatcher"
+)
+
+type WebhookSubscriptionRequest struct {
+	Name        string   `json:"name" binding:"required,max=100"`
+	Url         string   `json:"url" binding:"required,url"`
+	EventTypes  []string `json:"event_types"`
+	ServerIds   []string `json:"server_ids"`
+	Enabled     *bool    `json:"enabled"`
+	MaxAttempts int      `json:"max_attempts"`
+	// RotateSecret issues a new signing secret on update.
+	RotateSecret bool `json:"rotate_secret"`
+}
+
+// applyWebhookRequest validates req and copies it onto sub.
+func (s *Server) applyWebhookRequest(c *gin.Context, req *WebhookSubscriptionRequest, sub *models.WebhookSubscription) bool {
+	sub.Name = strings.TrimSpace(req.Name)
+	if sub.Name == "" {
+		responses.BadRequest(c, "Webhook name is required", nil)
+		return false
+	}
+
+	if err := utils.ValidateRemoteURL(req.Url); err != nil {
+		responses.BadRequest(c, "Webhook URL is not allowed", &gin.H{"error": err.Error()})
+		return false
+	}
+	sub.Url = req.Url
+
+	if req.MaxAttempts == 0 {
+		req.MaxAttempts = webhook_dispatcher.DefaultMaxAttempts
+	}
+	if req.MaxAttempts < 1 || req.MaxAttempts > 20 {
+		responses.BadRequest(c, "Max attempts must be between 1 and 20", nil)
+		return false
+	}
+	sub.MaxAttempts = req.MaxAttempts
+
+	if req.Enabled != nil {
+		sub.Enabled = *req.Enabled
+	}
+
+	sub.EventTypes = []string{}
+	seenTypes := map[string]bool{}
+	for _, eventType := range req.EventTypes {
+		eventType = strings.ToUpper(strings.TrimSpace(eventType))
+		if eventType == "" || seenTypes[eventType] {
+			continue
+		}
+		if !event_manager.EventType(eventType).IsKnown() {
+			responses.BadRequest(c, "Unknown event type: "+eventType, nil)
+			return false
+		}
+		seenTypes[eventType] = true
+		sub.EventTypes = append(sub.EventTypes, eventType)
+	}
+
+	sub.ServerIds = []uuid.UUID{}
+	seenServers := map[uuid.UUID]bool{}
+	for _, raw := range req.ServerIds {
+		serverId, err := uuid.Parse(raw)
+		if err != nil {
+			responses.BadRequest(c, "Invalid server ID: "+raw, nil)
+			return false
+		}
+		if seenServers[serverId] {
+			continue
+		}
+		server, err := core.GetServerById(c.Request.Context(), s.Dependencies.DB, serverId, nil)
+		if err != nil || server.Id != serverId {
+			responses.BadRequest(c, "Server not found: "+raw, nil)
+			return false
+		}
+		seenServers[serverId] = true
+		sub.ServerIds = append(sub.ServerIds, serverId)
+	}
+
+	return true
+}
+
+// reloadWebhooks makes the dispatcher pick up subscription changes.
+func (s *Server) reloadWebhooks(c *gin.Context) {
+	if s.Dependencies.WebhookDispatcher == nil {
+		return
+	}
+	if err := s.Dependencies.WebhookDispatcher.Reload(c.Request.Context()); err != nil {
+		log.Error().Err(err).Msg("Failed to reload webhook subscriptions")
+	}
+}
+
+// SudoWebhooksList lists all webhook subscriptions
+func (s *Server) SudoWebhooksList(c *gin.Context) {
+	subs, err := core.ListWebhookSubscriptions(c.Request.Context(), s.Dependencies.DB)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	responses.Success(c, "Webhook subscriptions fetched successfully", &gin.H{"webhooks": subs})
+}
+
+// SudoWebhookCreate creates a webhook subscription. The signing secret is
+// returned once and cannot be retrieved again.
+func (s *Server) SudoWebhookCreate(c *gin.Context) {
+	session := c.MustGet("session").(*models.Session)
+
+	var req WebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+		return
+	}
+
+	sub := &models.WebhookSubscription{Enabled: true, CreatedBy: &session.UserId}
+	if !s.applyWebhookRequest(c, &req, sub) {
+		return
+	}
+
+	secret, err := webhook_dispatcher.GenerateSecret()
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+	sub.Secret = secret
+
+	if err := core.CreateWebhookSubscription(c.Request.Context(), s.Dependencies.DB, sub); err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+	s.reloadWebhooks(c)
+
+	s.CreateAuditLog(c.Request.Context(), nil, &session.UserId, "sudo:webhook:create", map[string]interface{}{
+		"webhook_id":  sub.Id.String(),
+		"name":        sub.Name,
+		"url":         sub.Url,
+		"event_types": sub.EventTypes,
+		"server_ids":  sub.ServerIds,
+	})
+
+	responses.Success(c, "Webhook subscription created successfully", &gin.H{
+		"webhook": sub,
+		"secret":  secret,
+	})
+}
+
+// SudoWebhookUpdate updates a webhook subscription, optionally rotating its secret
+func (s *Server) SudoWebhookUpdate(c *gin.Context) {
+	session := c.MustGet("session").(*models.Session)
+
+	webhookId, err := uuid.Parse(c.Param("webhookId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid webhook ID", nil)
+		return
+	}
+
+	var req WebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.BadRequest(c, "Invalid request payload", &gin.H{"error": err.Error()})
+		return
+	}
+
+	sub, err := core.GetWebhookSubscription(c.Request.Context(), s.Dependencies.DB, webhookId)
+	if err != nil {
+		if errors.Is(err, core.ErrWebhookNotFound) {
+			responses.NotFound(c, "Webhook subscription not found", nil)
+			return
+		}
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	if !s.applyWebhookRequest(c, &req, sub) {
+		return
+	}
+
+	secret := ""
+	if req.RotateSecret {
+		if secret, err = webhook_dispatcher.GenerateSecret(); err != nil {
+			responses.InternalServerError(c, err, nil)
+			return
+		}
+		sub.Secret = secret
+	}
+
+	if err := core.UpdateWebhookSubscription(c.Request.Context(), s.Dependencies.DB, sub); err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+	s.reloadWebhooks(c)
+
+	s.CreateAuditLog(c.Request.Context(), nil, &session.UserId, "sudo:webhook:update", map[string]interface{}{
+		"webhook_id":     sub.Id.String(),
+		"name":           sub.Name,
+		"url":            sub.Url,
+		"event_types":    sub.EventTypes,
+		"server_ids":     sub.ServerIds,
+		"enabled":        sub.Enabled,
+		"secret_rotated": req.RotateSecret,
+	})
+
+	data := gin.H{"webhook": sub}
+	if secret != "" {
+		data["secret"] = secret
+	}
+	responses.Success(c, "Webhook subscription updated successfully", &data)
+}
+
+// SudoWebhookDelete deletes a webhook subscription and its delivery log
+func (s *Server) SudoWebhookDelete(c *gin.Context) {
+	session := c.MustGet("session").(*models.Session)
+
+	webhookId, err := uuid.Parse(c.Param("webhookId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid webhook ID", nil)
+		return
+	}
+
+	if err := core.DeleteWebhookSubscription(c.Request.Context(), s.Dependencies.DB, webhookId); err != nil {
+		if errors.Is(err, core.ErrWebhookNotFound) {
+			responses.NotFound(c, "Webhook subscription not found", nil)
+			return
+		}
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+	s.reloadWebhooks(c)
+
+	s.CreateAuditLog(c.Request.Context(), nil, &session.UserId, "sudo:webhook:delete", map[string]interface{}{
+		"webhook_id": webhookId.String(),
+	})
+
+	responses.SimpleSuccess(c, "Webhook subscription deleted successfully")
+}
+
+// SudoWebhookPing queues a test delivery for a webhook subscription
+func (s *Server) SudoWebhookPing(c *gin.Context) {
+	webhookId, err := uuid.Parse(c.Param("webhookId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid webhook ID", nil)
+		return
+	}
+
+	if s.Dependencies.WebhookDispatcher == nil {
+		responses.BadRequest(c, "Webhook delivery is not running", nil)
+		return
+	}
+
+	sub, err := core.GetWebhookSubscription(c.Request.Context(), s.Dependencies.DB, webhookId)
+	if err != nil {
+		if errors.Is(err, core.ErrWebhookNotFound) {
+			responses.NotFound(c, "Webhook subscription not found", nil)
+			return
+		}
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	delivery, err := s.Dependencies.WebhookDispatcher.SendPing(c.Request.Context(), sub)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	responses.Success(c, "Test delivery queued", &gin.H{"delivery": delivery})
+}
+
+// SudoWebhookDeliveries lists the webhook delivery log, optionally filtered
+// by subscription and status
+func (s *Server) SudoWebhookDeliveries(c *gin.Context) {
+	var subscriptionId *uuid.UUID
+	if raw := c.Query("webhook_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			responses.BadRequest(c, "Invalid webhook ID", nil)
+			return
+		}
+		subscriptionId = &id
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliverySucceeded, models.WebhookDeliveryDead:
+	default:
+		responses.BadRequest(c, "Invalid status", nil)
+		return
+	}
+
+	page := 1
+	limit := 50
+	if pageStr := c.Query("page"); pageStr != "" {
+		if pageInt, err := parseInt(pageStr); err == nil && pageInt > 0 {
+			page = pageInt
+		}
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limitInt, err := parseInt(limitStr); err == nil && limitInt > 0 && limitInt <= 200 {
+			limit = limitInt
+		}
+	}
+
+	deliveries, total, err := core.ListWebhookDeliveries(c.Request.Context(), s.Dependencies.DB, subscriptionId, status, limit, (page-1)*limit)
+	if err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	responses.Success(c, "Webhook deliveries fetched successfully", &gin.H{
+		"deliveries": deliveries,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + limit - 1) / limit,
+		},
+	})
+}
+
+// SudoWebhookDeliveryRetry requeues a finished delivery, such as one in the
+// dead-letter queue
+func (s *Server) SudoWebhookDeliveryRetry(c *gin.Context) {
+	session := c.MustGet("session").(*models.Session)
+
+	deliveryId, err := uuid.Parse(c.Param("deliveryId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid delivery ID", nil)
+		return
+	}
+
+	if err := core.RetryWebhookDelivery(c.Request.Context(), s.Dependencies.DB, deliveryId); err != nil {
+		if errors.Is(err, core.ErrWebhookDeliveryNotFound) {
+			responses.NotFound(c, "Delivery not found or already queued", nil)
+			return
+		}
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), nil, &session.UserId, "sudo:webhook:retry_delivery", map[string]interface{}{
+		"delivery_id": deliveryId.String(),
+	})
+
+	responses.SimpleSuccess(c, "Delivery queued for retry")
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+func TestApplyWebhookRequestNormalizesEventTypes(t *testing.T) {
+	server := &Server{Dependencies: &Dependencies{}}
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/sudo/webhooks", nil)
+
+	req := &WebhookSubscriptionRequest{
+		Name:       "bans",
+		Url:        "https://93.184.216.34/hook",
+		EventTypes: []string{" aegis_ban_created ", "AEGIS_BAN_CREATED", "log_player_died", ""},
+	}
+	var sub models.WebhookSubscription
+	if !server.applyWebhookRequest(c, req, &sub) {
+		t.Fatalf("applyWebhookRequest rejected request: %s", recorder.Body.String())
+	}
+
+	if want := []string{"AEGIS_BAN_CREATED", "LOG_PLAYER_DIED"}; !reflect.DeepEqual(sub.EventTypes, want) {
+		t.Fatalf("EventTypes = %v, want %v", sub.EventTypes, want)
+	}
+}
+
+func TestApplyWebhookRequestRejectsUnknownEventType(t *testing.T) {
+	server := &Server{Dependencies: &Dependencies{}}
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/sudo/webhooks", nil)
+
+	req := &WebhookSubscriptionRequest{
+		Name:       "typo",
+		Url:        "https://93.184.216.34/hook",
+		EventTypes: []string{"AEGIS_BAN_CRATED"},
+	}
+	var sub models.WebhookSubscription
+	if server.applyWebhookRequest(c, req, &sub) {
+		t.Fatal("applyWebhookRequest accepted an unknown event type")
+	}
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusBadRequest)
+	}
+}
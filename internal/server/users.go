@@ -159,3 +159,35 @@ func (s *Server) UserDelete(c *gin.Context) {
 
 	responses.Success(c, "User deleted successfully", nil)
 }
+
+// UserTwoFactorReset removes another user's 2FA enrollment, e.g. after a lost device
+func (s *Server) UserTwoFactorReset(c *gin.Context) {
+	currentUser := s.getUserFromSession(c)
+
+	userId, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		responses.BadRequest(c, "Invalid user ID", &gin.H{"error": err.Error()})
+		return
+	}
+
+	if userId == currentUser.Id {
+		responses.BadRequest(c, "Use your own security settings to disable two-factor authentication", nil)
+		return
+	}
+
+	if _, err := core.GetUserById(c.Request.Context(), s.Dependencies.DB, userId, nil); err != nil {
+		responses.NotFound(c, "User not found", nil)
+		return
+	}
+
+	if err := core.DisableTwoFactor(c.Request.Context(), s.Dependencies.DB, userId); err != nil {
+		responses.InternalServerError(c, err, nil)
+		return
+	}
+
+	s.CreateAuditLog(c.Request.Context(), nil, &currentUser.Id, "user:two_factor:reset", map[string]interface{}{
+		"userId": userId.String(),
+	})
+
+	responses.SimpleSuccess(c, "Two-factor authentication reset successfully")
+}
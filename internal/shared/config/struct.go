@@ -14,6 +14,55 @@ type Struct struct {
 			Password string `default:""`
 		}
 	}
+	Auth struct {
+		// TwoFactorIssuer is the issuer label shown in authenticator apps.
+		TwoFactorIssuer string `default:"Squad Aegis"`
+		// TwoFactorReverifySeconds is how long a second-factor verification
+		// satisfies sensitive endpoints that demand a fresh check.
+		TwoFactorReverifySeconds int `default:"600"`
+		OIDC                     struct {
+			Enabled      bool   `default:"false"`
+			ProviderName string `default:"SSO"` // Label shown on the login button
+			IssuerUrl    string `default:""`
+			ClientId     string `default:""`
+			ClientSecret string `default:""`
+			// RedirectUrl defaults to <App.Url>/api/auth/oidc/callback.
+			RedirectUrl   string `default:""`
+			Scopes        string `default:"openid,profile,email,groups"` // Comma-separated
+			UsernameClaim string `default:"preferred_username"`
+			GroupsClaim   string `default:"groups"`
+			SteamIdClaim  string `default:""` // Optional claim holding a SteamID64 string
+			// AutoProvision creates local accounts for unknown IdP users.
+			AutoProvision bool `default:"true"`
+			// DisablePasswordLogin rejects username/password logins while SSO is enabled.
+			DisablePasswordLogin bool `default:"false"`
+		} `env:"OIDC" yaml:"oidc" flag:"oidc"`
+	}
+	Audit struct {
+		// CheckpointSigningKey is a base64 ed25519 private key (or 32-byte
+		// seed) used to sign periodic checkpoints over each audit chain head.
+		// Empty disables checkpoints.
+		CheckpointSigningKey      string `default:""`
+		CheckpointIntervalSeconds int    `default:"3600"`
+		// Optional sinks that receive a copy of every audit entry.
+		SinkFilePath      string `default:""`
+		SinkSyslogAddress string `default:""` // udp://host:514 or tcp://host:514
+		SinkSyslogTag     string `default:"squad-aegis"`
+		SinkWebhookUrl    string `default:""`
+		SinkWebhookSecret string `default:""`
+	}
+	Workflows struct {
+		Lua struct {
+			// Ceilings for every Lua step. A workflow's lua_limits may lower
+			// them but not raise them.
+			MaxInstructions int64 `default:"100000000"`
+			MaxMemoryMB     int64 `default:"64"`
+			// TimeoutSeconds applies when neither the step nor the workflow
+			// sets one; MaxTimeoutSeconds caps both.
+			TimeoutSeconds    int `default:"30"`
+			MaxTimeoutSeconds int `default:"300"`
+		}
+	}
 	Db struct {
 		Host    string `default:"localhost"`
 		Port    int    `default:"5432"`
@@ -86,6 +135,15 @@ type Struct struct {
 		// host clock drift does not quarantine a freshly-signed bundle.
 		SignatureClockSkewSeconds int `default:"300"`
 
+		// Comma-separated URLs of plugin catalog indexes (index.json). The
+		// index.signed.json, index.sig and index.pub files are fetched from
+		// alongside each index, and the index is only used when its
+		// signature verifies against TrustedSigningKeys and the CRL.
+		// Catalogs are re-fetched every CatalogRefreshSeconds; zero or
+		// negative disables the background refresh.
+		CatalogUrls           string `default:""`
+		CatalogRefreshSeconds int    `default:"3600"`
+
 		// Subprocess rate limiting: per-instance HostAPI token bucket. A
 		// compromised plugin that floods the host with RconAPI/LogAPI calls
 		// is throttled to HostAPIRatePerSec sustained with HostAPIBurst peak.
@@ -98,6 +156,35 @@ type Struct struct {
 		// Zero or negative disables the background health monitor.
 		HealthCheckIntervalSeconds int `default:"10"`
 
+		// Resource accounting: how often each running plugin instance's
+		// subprocess CPU and RSS are sampled from /proc and the quarantine
+		// thresholds are checked. Zero or negative disables sampling and
+		// quarantine; HostAPI, RCON, event and panic counters are still
+		// collected for the metrics route.
+		ResourceSampleIntervalSeconds int `default:"15"`
+
+		// Automatic quarantine. An instance over any threshold for
+		// QuarantineStrikes consecutive samples is disabled and a
+		// PLUGIN_QUARANTINED event is published; QuarantineMaxPanics
+		// quarantines at the first sample that reaches it. Zero disables a
+		// threshold, and all are disabled by default.
+		QuarantineMaxCPUPercent            float64 `default:"0"`
+		QuarantineMaxRSSMB                 int     `default:"0"`
+		QuarantineMaxHostAPICallsPerMinute int     `default:"0"`
+		QuarantineMaxRconCommandsPerMinute int     `default:"0"`
+		QuarantineMaxEventLatencyMs        int     `default:"0"`
+		QuarantineMaxPanics                int     `default:"0"`
+		QuarantineStrikes                  int     `default:"3"`
+
+		// WASM plugin limits. Each instance's linear memory is capped at
+		// WasmMemoryLimitMB, and each call into a module may make at most
+		// WasmFuel guest function calls before the module is stopped. Fuel
+		// does not count loop iterations, so it does not bound CPU time;
+		// only the host's per-call timeouts do. Zero or negative fuel
+		// disables metering.
+		WasmMemoryLimitMB int   `default:"128"`
+		WasmFuel          int64 `default:"200000000"`
+
 		// Subprocess privilege drop (unix only). Set a non-zero UID/GID to
 		// launch native plugin/connector subprocesses under a different
 		// user/group. Groups is a comma-separated list of supplementary
@@ -41,6 +41,9 @@ type ConfigField struct {
 // ConfigSchema defines a configuration schema
 type ConfigSchema struct {
 	Fields []ConfigField `json:"fields"`
+	// Version is bumped when a schema change would break stored configs.
+	// Zero means unversioned.
+	Version int `json:"version,omitempty"`
 }
 
 // IsArrayType checks if the given FieldType is an array type
@@ -0,0 +1,241 @@
+package squad_config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DiffOp is the kind of a diff line.
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffAdd    DiffOp = "add"
+	DiffRemove DiffOp = "remove"
+)
+
+// DiffLine is one line of a line diff. OldLine and NewLine are 1-based and
+// zero when the line is absent on that side.
+type DiffLine struct {
+	Op      DiffOp `json:"op"`
+	Text    string `json:"text"`
+	OldLine int    `json:"old_line,omitempty"`
+	NewLine int    `json:"new_line,omitempty"`
+}
+
+// SettingChange is a setting whose value differs between two files. Old or
+// New is nil when the setting is absent on that side.
+type SettingChange struct {
+	Key string  `json:"key"`
+	Old *string `json:"old"`
+	New *string `json:"new"`
+}
+
+// maxDiffCells bounds the table used for a line diff. Larger inputs are
+// shown as a full replacement.
+const maxDiffCells = 4_000_000
+
+// Hash returns the hex SHA-256 of content, used to detect changes.
+func Hash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// Diff returns a line diff from oldContent to newContent. Line endings are
+// ignored.
+func Diff(oldContent, newContent string) []DiffLine {
+	oldLines := splitLines(oldContent)
+	newLines := splitLines(newContent)
+
+	if len(oldLines)*len(newLines) > maxDiffCells {
+		diff := make([]DiffLine, 0, len(oldLines)+len(newLines))
+		for i, line := range oldLines {
+			diff = append(diff, DiffLine{Op: DiffRemove, Text: line, OldLine: i + 1})
+		}
+		for i, line := range newLines {
+			diff = append(diff, DiffLine{Op: DiffAdd, Text: line, NewLine: i + 1})
+		}
+		return diff
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of
+	// oldLines[i:] and newLines[j:]
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diff := []DiffLine{}
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			diff = append(diff, DiffLine{Op: DiffEqual, Text: oldLines[i], OldLine: i + 1, NewLine: j + 1})
+			i++
+			j++
+		case j < len(newLines) && (i == len(oldLines) || lcs[i][j+1] >= lcs[i+1][j]):
+			diff = append(diff, DiffLine{Op: DiffAdd, Text: newLines[j], NewLine: j + 1})
+			j++
+		default:
+			diff = append(diff, DiffLine{Op: DiffRemove, Text: oldLines[i], OldLine: i + 1})
+			i++
+		}
+	}
+	return diff
+}
+
+// Changed reports whether a diff contains any additions or removals.
+func Changed(diff []DiffLine) bool {
+	for _, line := range diff {
+		if line.Op != DiffEqual {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffSettings compares the settings of two settings files. Keys match
+// case-insensitively and secret values are replaced with a placeholder.
+func DiffSettings(oldFile, newFile *File) []SettingChange {
+	oldSettings := rawSettings(oldFile)
+	newSettings := rawSettings(newFile)
+
+	keys := map[string]string{}
+	for normalized, line := range oldSettings {
+		keys[normalized] = line.Key
+	}
+	for normalized, line := range newSettings {
+		keys[normalized] = line.Key
+	}
+
+	changes := []SettingChange{}
+	for normalized, key := range keys {
+		oldLine, inOld := oldSettings[normalized]
+		newLine, inNew := newSettings[normalized]
+		if inOld && inNew && oldLine.Value == newLine.Value {
+			continue
+		}
+
+		oldValue, newValue := oldLine.Value, newLine.Value
+		change := SettingChange{Key: key}
+		if field, ok := newFile.Spec.Field(key); ok {
+			change.Key = field.Key
+			if field.Secret {
+				oldValue, newValue = redacted, redacted
+			}
+		}
+		if inOld {
+			change.Old = &oldValue
+		}
+		if inNew {
+			change.New = &newValue
+		}
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+const redacted = "********"
+
+// RedactSecrets returns content with the value of every secret setting
+// replaced by a placeholder, for storing where the secret must not be kept.
+func RedactSecrets(spec Spec, content string) string {
+	file := Parse(spec, content)
+	changed := false
+	for i, line := range file.Lines {
+		if line.Kind != LineSetting {
+			continue
+		}
+		if field, ok := spec.Field(line.Key); !ok || !field.Secret {
+			continue
+		}
+		line.Value = redacted
+		line.Raw = line.Key + "=" + redacted
+		if line.Quoted {
+			line.Raw = line.Key + "=" + strconv.Quote(redacted)
+		}
+		file.Lines[i] = line
+		changed = true
+	}
+	if !changed {
+		return content
+	}
+	return file.Render()
+}
+
+// RestoreSecrets puts the secret values from current back into content
+// produced by RedactSecrets. It fails when current has no value to restore.
+func RestoreSecrets(spec Spec, content, current string) (string, error) {
+	file := Parse(spec, content)
+	currentSettings := rawSettings(Parse(spec, current))
+	changed := false
+	for i, line := range file.Lines {
+		if line.Kind != LineSetting || line.Value != redacted {
+			continue
+		}
+		if field, ok := spec.Field(line.Key); !ok || !field.Secret {
+			continue
+		}
+		currentLine, ok := currentSettings[strings.ToLower(line.Key)]
+		if !ok {
+			return "", fmt.Errorf("%s is not stored in revisions and is not set on the host", line.Key)
+		}
+		file.Lines[i] = currentLine
+		changed = true
+	}
+	if !changed {
+		return content, nil
+	}
+	return file.Render(), nil
+}
+
+// rawSettings returns the last line for each setting, keyed by its
+// lowercased name
+func rawSettings(file *File) map[string]Line {
+	settings := map[string]Line{}
+	for _, line := range file.Lines {
+		if line.Kind == LineSetting {
+			settings[strings.ToLower(line.Key)] = line
+		}
+	}
+	return settings
+}
+
+func splitLines(content string) []string {
+	content = strings.TrimPrefix(content, byteOrderMark)
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
+
+// Summary describes a diff in a few words for audit logs.
+func Summary(diff []DiffLine) string {
+	added, removed := 0, 0
+	for _, line := range diff {
+		switch line.Op {
+		case DiffAdd:
+			added++
+		case DiffRemove:
+			removed++
+		}
+	}
+	return fmt.Sprintf("+%d -%d lines", added, removed)
+}
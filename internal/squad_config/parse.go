@@ -0,0 +1,384 @@
+package squad_config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// LineKind classifies a line of a config file.
+type LineKind string
+
+const (
+	LineBlank   LineKind = "blank"
+	LineComment LineKind = "comment"
+	LineSection LineKind = "section"
+	LineSetting LineKind = "setting"
+	LineEntry   LineKind = "entry"
+	LineInvalid LineKind = "invalid"
+)
+
+// Line is one line of a config file. Raw is written back unchanged unless
+// the line is edited, so unedited files round-trip byte for byte.
+type Line struct {
+	Kind   LineKind `json:"kind"`
+	Raw    string   `json:"raw"`
+	Key    string   `json:"key,omitempty"`
+	Value  string   `json:"value,omitempty"`
+	Quoted bool     `json:"quoted,omitempty"`
+}
+
+// Severity says whether an issue blocks an upload.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a validation problem. Line is 1-based, or 0 for the whole file.
+type Issue struct {
+	Line     int      `json:"line,omitempty"`
+	Key      string   `json:"key,omitempty"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// File is a parsed config file.
+type File struct {
+	Spec  Spec
+	Lines []Line
+
+	newline       string
+	finalNewline  bool
+	byteOrderMark bool
+}
+
+const byteOrderMark = "\ufeff"
+
+// Parse parses content as the file described by spec. Parsing never fails;
+// lines it cannot understand are kept and reported by Validate.
+func Parse(spec Spec, content string) *File {
+	file := &File{Spec: spec, newline: "\n"}
+
+	if strings.HasPrefix(content, byteOrderMark) {
+		file.byteOrderMark = true
+		content = strings.TrimPrefix(content, byteOrderMark)
+	}
+	if strings.Contains(content, "\r\n") {
+		file.newline = "\r\n"
+	}
+	if content == "" {
+		// Files written from scratch end with a newline
+		file.finalNewline = true
+		return file
+	}
+
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	file.finalNewline = strings.HasSuffix(content, "\n")
+	content = strings.TrimSuffix(content, "\n")
+
+	for _, raw := range strings.Split(content, "\n") {
+		file.Lines = append(file.Lines, parseLine(spec.Format, raw))
+	}
+	return file
+}
+
+func parseLine(format Format, raw string) Line {
+	trimmed := strings.TrimSpace(raw)
+	switch {
+	case trimmed == "":
+		return Line{Kind: LineBlank, Raw: raw}
+	case strings.HasPrefix(trimmed, "//"), strings.HasPrefix(trimmed, "#"), strings.HasPrefix(trimmed, ";"):
+		return Line{Kind: LineComment, Raw: raw}
+	case format == FormatList:
+		return Line{Kind: LineEntry, Raw: raw, Value: trimmed}
+	case strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]"):
+		return Line{Kind: LineSection, Raw: raw, Key: trimmed[1 : len(trimmed)-1]}
+	}
+
+	key, value, ok := strings.Cut(trimmed, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return Line{Kind: LineInvalid, Raw: raw}
+	}
+
+	line := Line{Kind: LineSetting, Raw: raw, Key: key, Value: strings.TrimSpace(value)}
+	if unquoted, err := strconv.Unquote(line.Value); err == nil && strings.HasPrefix(line.Value, `"`) {
+		line.Value = unquoted
+		line.Quoted = true
+	}
+	return line
+}
+
+// Render returns the file's content, keeping the original line endings.
+func (f *File) Render() string {
+	var builder strings.Builder
+	if f.byteOrderMark {
+		builder.WriteString(byteOrderMark)
+	}
+	for i, line := range f.Lines {
+		if i > 0 {
+			builder.WriteString(f.newline)
+		}
+		builder.WriteString(line.Raw)
+	}
+	if f.finalNewline && len(f.Lines) > 0 {
+		builder.WriteString(f.newline)
+	}
+	return builder.String()
+}
+
+// Settings returns the file's settings keyed by name. Known fields with
+// valid values are typed; everything else is a string. When a key repeats,
+// the last value wins, as in game.
+func (f *File) Settings() map[string]interface{} {
+	settings := map[string]interface{}{}
+	for _, line := range f.Lines {
+		if line.Kind != LineSetting {
+			continue
+		}
+		key := line.Key
+		var value interface{} = line.Value
+		if field, ok := f.Spec.Field(line.Key); ok {
+			key = field.Key
+			if typed, err := typedValue(field, line.Value); err == nil {
+				value = typed
+			}
+		}
+		settings[key] = value
+	}
+	return settings
+}
+
+// Entries returns the entries of a list file in order.
+func (f *File) Entries() []string {
+	entries := []string{}
+	for _, line := range f.Lines {
+		if line.Kind == LineEntry {
+			entries = append(entries, line.Value)
+		}
+	}
+	return entries
+}
+
+// Set changes a setting, editing its last occurrence in place or appending
+// it. A nil value removes every occurrence of the key.
+func (f *File) Set(key string, value interface{}) error {
+	if f.Spec.Format != FormatSettings {
+		return fmt.Errorf("%s does not hold settings", f.Spec.Name)
+	}
+	key = strings.TrimSpace(key)
+	if key == "" || strings.ContainsAny(key, "=\r\n") {
+		return fmt.Errorf("invalid setting name %q", key)
+	}
+
+	field, known := f.Spec.Field(key)
+	if known {
+		key = field.Key
+	}
+
+	if value == nil {
+		lines := f.Lines[:0]
+		for _, line := range f.Lines {
+			if line.Kind != LineSetting || !strings.EqualFold(line.Key, key) {
+				lines = append(lines, line)
+			}
+		}
+		f.Lines = lines
+		return nil
+	}
+
+	text, err := formatValue(value)
+	if err != nil {
+		return fmt.Errorf("setting %s: %w", key, err)
+	}
+
+	index := -1
+	for i, line := range f.Lines {
+		if line.Kind == LineSetting && strings.EqualFold(line.Key, key) {
+			index = i
+		}
+	}
+
+	line := Line{Kind: LineSetting, Key: key, Value: text, Quoted: known && field.Quoted}
+	if index >= 0 {
+		line.Key = f.Lines[index].Key
+		line.Quoted = line.Quoted || f.Lines[index].Quoted
+	}
+	line.Raw = line.Key + "=" + text
+	if line.Quoted {
+		line.Raw = line.Key + "=" + strconv.Quote(text)
+	}
+
+	if index >= 0 {
+		f.Lines[index] = line
+	} else {
+		f.Lines = append(f.Lines, line)
+	}
+	return nil
+}
+
+// SetEntries replaces the entries of a list file. The new entries take the
+// place of the first old entry; comments between old entries stay where
+// they were relative to the rest of the file.
+func (f *File) SetEntries(entries []string) error {
+	if f.Spec.Format != FormatList {
+		return fmt.Errorf("%s does not hold a list", f.Spec.Name)
+	}
+
+	replacement := make([]Line, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.ContainsAny(entry, "\r\n") {
+			return fmt.Errorf("entry %q spans several lines", entry)
+		}
+		replacement = append(replacement, Line{Kind: LineEntry, Raw: entry, Value: entry})
+	}
+
+	lines := make([]Line, 0, len(f.Lines)+len(replacement))
+	inserted := false
+	for _, line := range f.Lines {
+		if line.Kind != LineEntry {
+			lines = append(lines, line)
+			continue
+		}
+		if !inserted {
+			lines = append(lines, replacement...)
+			inserted = true
+		}
+	}
+	if !inserted {
+		lines = append(lines, replacement...)
+	}
+	f.Lines = lines
+	return nil
+}
+
+// Validate checks the file against its spec. Errors block uploads;
+// warnings are shown but allowed.
+func (f *File) Validate() []Issue {
+	issues := []Issue{}
+	seen := map[string]int{}
+
+	for i, line := range f.Lines {
+		number := i + 1
+		switch line.Kind {
+		case LineInvalid:
+			issues = append(issues, Issue{Line: number, Severity: SeverityError, Message: "expected Key=Value"})
+
+		case LineSection:
+			if !f.Spec.Open {
+				issues = append(issues, Issue{Line: number, Severity: SeverityWarning, Message: fmt.Sprintf("unexpected section [%s]", line.Key)})
+			}
+
+		case LineSetting:
+			field, known := f.Spec.Field(line.Key)
+			if !known {
+				if !f.Spec.Open {
+					issues = append(issues, Issue{Line: number, Key: line.Key, Severity: SeverityWarning, Message: "unknown setting"})
+				}
+			} else if _, err := typedValue(field, line.Value); err != nil {
+				issues = append(issues, Issue{Line: number, Key: field.Key, Severity: SeverityError, Message: err.Error()})
+			}
+
+			normalized := strings.ToLower(line.Key)
+			if previous, ok := seen[normalized]; ok {
+				issues = append(issues, Issue{Line: number, Key: line.Key, Severity: SeverityWarning, Message: fmt.Sprintf("overrides the value on line %d", previous)})
+			}
+			seen[normalized] = number
+
+		case LineEntry:
+			if strings.IndexFunc(line.Value, unicode.IsControl) >= 0 {
+				issues = append(issues, Issue{Line: number, Severity: SeverityError, Message: "entry contains control characters"})
+			}
+			if f.Spec.UniqueEntries {
+				normalized := strings.ToLower(line.Value)
+				if previous, ok := seen[normalized]; ok {
+					issues = append(issues, Issue{Line: number, Severity: SeverityWarning, Message: fmt.Sprintf("duplicates line %d", previous)})
+				}
+				seen[normalized] = number
+			}
+		}
+	}
+
+	if f.Spec.Format == FormatList && f.Spec.Name != "ExcludedLayers.cfg" && len(f.Entries()) == 0 {
+		issues = append(issues, Issue{Severity: SeverityWarning, Message: "the rotation is empty"})
+	}
+	return issues
+}
+
+// HasErrors reports whether any issue blocks an upload.
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func typedValue(field Field, value string) (interface{}, error) {
+	switch field.Type {
+	case TypeBool:
+		switch strings.ToLower(value) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return nil, fmt.Errorf("expected true or false, got %q", value)
+
+	case TypeInt:
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("expected a whole number, got %q", value)
+		}
+		if field.Min != nil && number < *field.Min {
+			return nil, fmt.Errorf("must be at least %d", *field.Min)
+		}
+		if field.Max != nil && number > *field.Max {
+			return nil, fmt.Errorf("must be at most %d", *field.Max)
+		}
+		return number, nil
+
+	case TypeEnum:
+		for _, option := range field.Options {
+			if strings.EqualFold(option, value) {
+				return option, nil
+			}
+		}
+		return nil, fmt.Errorf("must be one of %s", strings.Join(field.Options, ", "))
+	}
+
+	if strings.IndexFunc(value, unicode.IsControl) >= 0 {
+		return nil, fmt.Errorf("contains control characters")
+	}
+	return value, nil
+}
+
+// formatValue renders a JSON-decoded value as it appears in a config file.
+func formatValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		if strings.ContainsAny(v, "\r\n") {
+			return "", fmt.Errorf("value spans several lines")
+		}
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return strconv.FormatInt(int64(v), 10), nil
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("unsupported value type %T", value)
+}
@@ -0,0 +1,156 @@
+package squad_config
+
+import "strings"
+
+// Format describes how the lines of a config file are structured.
+type Format string
+
+const (
+	// FormatSettings files hold Key=Value lines, such as Server.cfg.
+	FormatSettings Format = "settings"
+	// FormatList files hold one entry per line, such as LayerRotation.cfg.
+	FormatList Format = "list"
+)
+
+// ValueType is the type of a known setting.
+type ValueType string
+
+const (
+	TypeString ValueType = "string"
+	TypeInt    ValueType = "int"
+	TypeBool   ValueType = "bool"
+	TypeEnum   ValueType = "enum"
+)
+
+// Field describes a setting the parser knows how to type and validate.
+type Field struct {
+	Key         string    `json:"key"`
+	Type        ValueType `json:"type"`
+	Min         *int      `json:"min,omitempty"`
+	Max         *int      `json:"max,omitempty"`
+	Options     []string  `json:"options,omitempty"`
+	Quoted      bool      `json:"quoted,omitempty"`
+	Secret      bool      `json:"secret,omitempty"`
+	Description string    `json:"description"`
+}
+
+// Spec describes one managed file under ServerConfig.
+type Spec struct {
+	Name        string  `json:"name"`
+	Format      Format  `json:"format"`
+	Description string  `json:"description"`
+	Fields      []Field `json:"fields,omitempty"`
+	// Open specs accept any key without a warning
+	Open bool `json:"open,omitempty"`
+	// UniqueEntries warns about duplicate entries in list files
+	UniqueEntries bool `json:"unique_entries,omitempty"`
+}
+
+// RelPath returns the file's path relative to the SquadGame directory.
+func (s Spec) RelPath() string {
+	return "ServerConfig/" + s.Name
+}
+
+// Field returns the known field for key, matched case-insensitively as the
+// game does.
+func (s Spec) Field(key string) (Field, bool) {
+	for _, field := range s.Fields {
+		if strings.EqualFold(field.Key, key) {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+var specs = []Spec{
+	{
+		Name:        "Server.cfg",
+		Format:      FormatSettings,
+		Description: "Server name, player slots, team and rotation behaviour",
+		Fields: []Field{
+			{Key: "ServerName", Type: TypeString, Quoted: true, Description: "Name shown in the server browser"},
+			{Key: "ShouldAdvertise", Type: TypeBool, Description: "List the server in the server browser"},
+			{Key: "IsLANMatch", Type: TypeBool, Description: "Run as a LAN match"},
+			{Key: "MaxPlayers", Type: TypeInt, Min: intPtr(1), Max: intPtr(100), Description: "Maximum number of players"},
+			{Key: "NumReservedSlots", Type: TypeInt, Min: intPtr(0), Max: intPtr(100), Description: "Slots kept for players with the reserve permission"},
+			{Key: "PublicQueueLimit", Type: TypeInt, Min: intPtr(-1), Max: intPtr(100), Description: "Maximum public queue length, -1 for unlimited"},
+			{Key: "MapRotationMode", Type: TypeEnum, Options: []string{"LayerList", "LevelList", "LayerList_Vote"}, Description: "How the next layer is chosen"},
+			{Key: "RandomizeAtStart", Type: TypeBool, Description: "Start from a random point in the rotation"},
+			{Key: "UseVoteFactions", Type: TypeBool, Description: "Let players vote on factions"},
+			{Key: "UseVoteLevel", Type: TypeBool, Description: "Let players vote on the level"},
+			{Key: "UseVoteLayer", Type: TypeBool, Description: "Let players vote on the layer"},
+			{Key: "AllowTeamChanges", Type: TypeBool, Description: "Allow players to change teams"},
+			{Key: "PreventTeamChangeIfUnbalanced", Type: TypeBool, Description: "Block team changes that would unbalance teams"},
+			{Key: "NumPlayersDiffForTeamChanges", Type: TypeInt, Min: intPtr(0), Description: "Player difference allowed by team changes"},
+			{Key: "RejoinSquadDelayAfterKick", Type: TypeInt, Min: intPtr(0), Description: "Seconds before a kicked player may rejoin the squad"},
+			{Key: "RecordDemos", Type: TypeBool, Description: "Record server demos"},
+			{Key: "AllowPublicClientsToRecord", Type: TypeBool, Description: "Let any player record demos"},
+			{Key: "ServerMessageInterval", Type: TypeInt, Min: intPtr(0), Description: "Seconds between ServerMessages.cfg broadcasts"},
+			{Key: "TKAutoKickEnabled", Type: TypeBool, Description: "Kick players automatically for team kills"},
+			{Key: "AutoTKBanNumberTKs", Type: TypeInt, Min: intPtr(0), Description: "Team kills before an automatic ban"},
+			{Key: "AutoTKBanTime", Type: TypeInt, Min: intPtr(0), Description: "Length of an automatic team kill ban in seconds"},
+			{Key: "VehicleKitRequirementDisabled", Type: TypeBool, Description: "Allow any kit to use crewed vehicles"},
+			{Key: "VehicleClaimingDisabled", Type: TypeBool, Description: "Disable vehicle claiming"},
+			{Key: "AllowCommunityAdminAccess", Type: TypeBool, Description: "Grant access to community admins"},
+			{Key: "AllowDevProfiling", Type: TypeBool, Description: "Allow developer profiling"},
+			{Key: "AllowQA", Type: TypeBool, Description: "Allow QA access"},
+		},
+	},
+	{
+		Name:        "Rcon.cfg",
+		Format:      FormatSettings,
+		Description: "RCON listen address, port and password",
+		Fields: []Field{
+			{Key: "IP", Type: TypeString, Description: "Address RCON listens on"},
+			{Key: "Port", Type: TypeInt, Min: intPtr(1), Max: intPtr(65535), Description: "RCON port"},
+			{Key: "Password", Type: TypeString, Secret: true, Description: "RCON password"},
+		},
+	},
+	{
+		Name:        "LayerRotation.cfg",
+		Format:      FormatList,
+		Description: "Layers played in order when MapRotationMode is LayerList",
+	},
+	{
+		Name:        "MapRotation.cfg",
+		Format:      FormatList,
+		Description: "Levels played in order when MapRotationMode is LevelList",
+	},
+	{
+		Name:          "ExcludedLayers.cfg",
+		Format:        FormatList,
+		Description:   "Layers never picked by votes or random rotation",
+		UniqueEntries: true,
+	},
+	{
+		Name:        "VoteConfig.cfg",
+		Format:      FormatSettings,
+		Description: "Layer and faction vote options",
+		Open:        true,
+	},
+	{
+		Name:        "CustomOptions.cfg",
+		Format:      FormatSettings,
+		Description: "Server-specific options read by mods and game modes",
+		Open:        true,
+	},
+}
+
+// Specs returns the managed files in display order.
+func Specs() []Spec {
+	return append([]Spec(nil), specs...)
+}
+
+// Lookup returns the spec for a file name such as "Server.cfg".
+func Lookup(name string) (Spec, bool) {
+	for _, spec := range specs {
+		if spec.Name == name {
+			return spec, true
+		}
+	}
+	return Spec{}, false
+}
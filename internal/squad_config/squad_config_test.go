@@ -0,0 +1,188 @@
+package squad_config
+
+import (
+	"strings"
+	"testing"
+)
+
+func mustLookup(t *testing.T, name string) Spec {
+	t.Helper()
+	spec, ok := Lookup(name)
+	if !ok {
+		t.Fatalf("missing spec for %s", name)
+	}
+	return spec
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	content := "\ufeff// Server settings\r\nServerName=\"My Server\"\r\nMaxPlayers=100\r\n\r\nUnknownKey=1\r\n"
+	file := Parse(mustLookup(t, "Server.cfg"), content)
+
+	if rendered := file.Render(); rendered != content {
+		t.Fatalf("expected an unedited file to round-trip, got %q", rendered)
+	}
+
+	settings := file.Settings()
+	if settings["ServerName"] != "My Server" {
+		t.Errorf("expected the quoted name to be unquoted, got %#v", settings["ServerName"])
+	}
+	if settings["MaxPlayers"] != 100 {
+		t.Errorf("expected MaxPlayers to be typed, got %#v", settings["MaxPlayers"])
+	}
+	if settings["UnknownKey"] != "1" {
+		t.Errorf("expected unknown keys to stay strings, got %#v", settings["UnknownKey"])
+	}
+}
+
+func TestSetKeepsFormatting(t *testing.T) {
+	file := Parse(mustLookup(t, "Server.cfg"), "// header\nservername=\"Old\"\nMaxPlayers=80\n")
+
+	if err := file.Set("ServerName", "New \"Name\""); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Set("AllowTeamChanges", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Set("MaxPlayers", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "// header\nservername=\"New \\\"Name\\\"\"\nAllowTeamChanges=false\n"
+	if rendered := file.Render(); rendered != expected {
+		t.Errorf("expected %q, got %q", expected, rendered)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	file := Parse(mustLookup(t, "Server.cfg"), "MaxPlayers=120\nShouldAdvertise=yes\nMapRotationMode=layerlist_vote\nnot a setting\nFoo=1\nMaxPlayers=90\n")
+
+	issues := file.Validate()
+	errors := map[int]bool{}
+	warnings := map[int]bool{}
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			errors[issue.Line] = true
+		} else {
+			warnings[issue.Line] = true
+		}
+	}
+
+	for _, line := range []int{1, 2, 4} {
+		if !errors[line] {
+			t.Errorf("expected an error on line %d, got %+v", line, issues)
+		}
+	}
+	if errors[3] {
+		t.Errorf("expected enum values to match case-insensitively, got %+v", issues)
+	}
+	if !warnings[5] || !warnings[6] {
+		t.Errorf("expected warnings for the unknown and repeated keys, got %+v", issues)
+	}
+	if !HasErrors(issues) {
+		t.Error("expected HasErrors to report the errors")
+	}
+
+	open := Parse(mustLookup(t, "CustomOptions.cfg"), "[Options]\nAnything=goes\n")
+	if issues := open.Validate(); len(issues) != 0 {
+		t.Errorf("expected open files to accept any key, got %+v", issues)
+	}
+}
+
+func TestListFiles(t *testing.T) {
+	file := Parse(mustLookup(t, "ExcludedLayers.cfg"), "// excluded\nNarva_RAAS_v1\n\nnarva_raas_v1\n")
+
+	if entries := file.Entries(); len(entries) != 2 || entries[0] != "Narva_RAAS_v1" {
+		t.Fatalf("unexpected entries %v", entries)
+	}
+	if issues := file.Validate(); len(issues) != 1 || issues[0].Severity != SeverityWarning || issues[0].Line != 4 {
+		t.Errorf("expected a duplicate warning on line 4, got %+v", issues)
+	}
+
+	if err := file.SetEntries([]string{"Gorodok_RAAS_v1", " ", "Yehorivka_AAS_v2"}); err != nil {
+		t.Fatal(err)
+	}
+	if rendered := file.Render(); rendered != "// excluded\nGorodok_RAAS_v1\nYehorivka_AAS_v2\n\n" {
+		t.Errorf("unexpected rendered list %q", rendered)
+	}
+	if err := file.Set("Key", "value"); err == nil {
+		t.Error("expected Set to fail on a list file")
+	}
+
+	rotation := Parse(mustLookup(t, "LayerRotation.cfg"), "")
+	if issues := rotation.Validate(); len(issues) != 1 {
+		t.Errorf("expected an empty rotation warning, got %+v", issues)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	diff := Diff("a\r\nb\r\nc\r\n", "a\nc\nd\n")
+
+	expected := []DiffLine{
+		{Op: DiffEqual, Text: "a", OldLine: 1, NewLine: 1},
+		{Op: DiffRemove, Text: "b", OldLine: 2},
+		{Op: DiffEqual, Text: "c", OldLine: 3, NewLine: 2},
+		{Op: DiffAdd, Text: "d", NewLine: 3},
+	}
+	if len(diff) != len(expected) {
+		t.Fatalf("expected %+v, got %+v", expected, diff)
+	}
+	for i := range expected {
+		if diff[i] != expected[i] {
+			t.Errorf("line %d: expected %+v, got %+v", i, expected[i], diff[i])
+		}
+	}
+	if !Changed(diff) || Changed(Diff("a\n", "a\r\n")) {
+		t.Error("expected only content changes to count")
+	}
+	if summary := Summary(diff); summary != "+1 -1 lines" {
+		t.Errorf("unexpected summary %q", summary)
+	}
+}
+
+func TestDiffSettingsRedactsSecrets(t *testing.T) {
+	spec := mustLookup(t, "Rcon.cfg")
+	changes := DiffSettings(Parse(spec, "Port=21114\nPassword=old\n"), Parse(spec, "port=21115\nPassword=new\nIP=0.0.0.0\n"))
+
+	if len(changes) != 3 {
+		t.Fatalf("expected three changes, got %+v", changes)
+	}
+	if changes[0].Key != "IP" || changes[0].Old != nil || *changes[0].New != "0.0.0.0" {
+		t.Errorf("unexpected IP change %+v", changes[0])
+	}
+	if changes[1].Key != "Password" || *changes[1].Old != redacted || *changes[1].New != redacted {
+		t.Errorf("expected the password to be redacted, got %+v", changes[1])
+	}
+	if changes[2].Key != "Port" || *changes[2].Old != "21114" || *changes[2].New != "21115" {
+		t.Errorf("unexpected port change %+v", changes[2])
+	}
+}
+
+func TestRedactAndRestoreSecrets(t *testing.T) {
+	spec := mustLookup(t, "Rcon.cfg")
+	content := "IP=0.0.0.0\r\nPort=21114\r\nPassword=hunter2\r\n"
+
+	stored := RedactSecrets(spec, content)
+	if strings.Contains(stored, "hunter2") {
+		t.Fatalf("expected the password to be redacted, got %q", stored)
+	}
+	if want := "IP=0.0.0.0\r\nPort=21114\r\nPassword=" + redacted + "\r\n"; stored != want {
+		t.Errorf("expected %q, got %q", want, stored)
+	}
+
+	restored, err := RestoreSecrets(spec, stored, "Port=1\nPassword=current\n")
+	if err != nil {
+		t.Fatalf("RestoreSecrets failed: %v", err)
+	}
+	if want := "IP=0.0.0.0\r\nPort=21114\r\nPassword=current\r\n"; restored != want {
+		t.Errorf("expected %q, got %q", want, restored)
+	}
+
+	if _, err := RestoreSecrets(spec, stored, "Port=1\n"); err == nil {
+		t.Error("expected an error when the host has no password to restore")
+	}
+
+	layers := mustLookup(t, "LayerRotation.cfg")
+	if got := RedactSecrets(layers, "Narva_AAS_v1\n"); got != "Narva_AAS_v1\n" {
+		t.Errorf("expected files without secrets to be unchanged, got %q", got)
+	}
+}
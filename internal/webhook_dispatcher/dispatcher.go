@@ -0,0 +1,403 @@
+// Package webhook_dispatcher delivers events to outbound webhook
+// subscriptions. Matching events are written to a delivery queue in the
+// database and sent by a background worker, which signs each request, retries
+// failures with exponential backoff and moves deliveries that keep failing to
+// a dead-letter state where they can be inspected and retried by hand.
+package webhook_dispatcher
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/rs/zerolog/log"
+	"go.codycody31.dev/squad-aegis/internal/core"
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
+	"go.codycody31.dev/squad-aegis/internal/models"
+	"go.codycody31.dev/squad-aegis/internal/shared/utils"
+)
+
+// EventTypePing is the type of the synthetic event sent by SendPing.
+const EventTypePing event_manager.EventType = "WEBHOOK_PING"
+
+const (
+	pollInterval      = 5 * time.Second
+	deliveryLease     = 2 * time.Minute
+	claimBatchSize    = 20
+	requestTimeout    = 15 * time.Second
+	maxResponseBody   = 2048
+	deliveryRetention = 30 * 24 * time.Hour
+)
+
+// PingData is the payload of a test delivery.
+type PingData struct {
+	SubscriptionID string `json:"subscription_id"`
+	Message        string `json:"message"`
+}
+
+func (d PingData) GetEventType() event_manager.EventType { return EventTypePing }
+
+// Dispatcher queues and delivers webhook events.
+type Dispatcher struct {
+	db           *sql.DB
+	eventManager *event_manager.EventManager
+	subscriber   *event_manager.EventSubscriber
+	client       *http.Client
+	// validateURL guards against deliveries to internal addresses. It is
+	// checked before every attempt since DNS can change after creation.
+	validateURL func(string) error
+	// allowIP is checked against the address every connection actually
+	// dials, so a name that resolves differently after validateURL cannot
+	// reach an internal host.
+	allowIP func(net.IP) error
+
+	mu            sync.RWMutex
+	subscriptions []*models.WebhookSubscription
+
+	wake   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher creates a new Dispatcher instance.
+func NewDispatcher(ctx context.Context, db *sql.DB, eventManager *event_manager.EventManager) *Dispatcher {
+	ctx, cancel := context.WithCancel(ctx)
+	d := &Dispatcher{
+		db:           db,
+		eventManager: eventManager,
+		validateURL:  utils.ValidateRemoteURL,
+		allowIP:      allowPublicIP,
+		wake:         make(chan struct{}, 1),
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+
+	dialer := &net.Dialer{Timeout: requestTimeout, Control: d.controlDial}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialed instead of the endpoint, defeating allowIP.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	d.client = &http.Client{
+		Transport: transport,
+		Timeout:   requestTimeout,
+		// Redirects are not followed; a redirect could point anywhere.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return d
+}
+
+// controlDial runs after the endpoint's name is resolved and before the
+// connection is made, so it sees the exact address being dialed.
+func (d *Dispatcher) controlDial(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("webhook dial to unresolved address %q", address)
+	}
+	return d.allowIP(ip)
+}
+
+// allowPublicIP rejects the private and internal addresses
+// utils.ValidateRemoteURL does, plus the unspecified address.
+func allowPublicIP(ip net.IP) error {
+	if utils.IsPrivateIP(ip) || ip.IsUnspecified() {
+		return fmt.Errorf("webhook URL resolves to private/internal IP %s: not allowed", ip)
+	}
+	return nil
+}
+
+// Start loads subscriptions, subscribes to events and begins delivering.
+func (d *Dispatcher) Start() error {
+	log.Info().Msg("Starting webhook dispatcher")
+
+	if err := d.Reload(d.ctx); err != nil {
+		return err
+	}
+
+	d.subscriber = d.eventManager.Subscribe(event_manager.EventFilter{}, nil, 1000)
+
+	d.wg.Add(2)
+	go func() {
+		defer d.wg.Done()
+		d.eventLoop()
+	}()
+	go func() {
+		defer d.wg.Done()
+		d.deliveryLoop()
+	}()
+	return nil
+}
+
+// Stop unsubscribes from events and waits for in-flight deliveries.
+func (d *Dispatcher) Stop() {
+	log.Info().Msg("Stopping webhook dispatcher")
+
+	if d.subscriber != nil {
+		d.eventManager.Unsubscribe(d.subscriber.ID)
+	}
+
+	d.cancel()
+	d.wg.Wait()
+}
+
+// Reload refreshes the cached subscriptions. Call it after subscriptions
+// are created, changed or deleted.
+func (d *Dispatcher) Reload(ctx context.Context) error {
+	subs, err := core.ListWebhookSubscriptions(ctx, d.db)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.subscriptions = subs
+	d.mu.Unlock()
+	return nil
+}
+
+// SendPing queues a test delivery for a subscription, regardless of its
+// filter.
+func (d *Dispatcher) SendPing(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookDelivery, error) {
+	event := event_manager.Event{
+		ID:        uuid.New(),
+		Type:      EventTypePing,
+		Data:      PingData{SubscriptionID: sub.Id.String(), Message: "Test delivery from Squad Aegis"},
+		Timestamp: time.Now(),
+	}
+	delivery, err := d.enqueue(ctx, sub, event)
+	if err != nil {
+		return nil, err
+	}
+	d.notify()
+	return delivery, nil
+}
+
+func (d *Dispatcher) notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (d *Dispatcher) subscription(id uuid.UUID) *models.WebhookSubscription {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for _, sub := range d.subscriptions {
+		if sub.Id == id {
+			return sub
+		}
+	}
+	return nil
+}
+
+func (d *Dispatcher) eventLoop() {
+	eventChan := d.subscriber.Channel
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case event, ok := <-eventChan:
+			if !ok {
+				return
+			}
+			d.handleEvent(event)
+		}
+	}
+}
+
+func (d *Dispatcher) handleEvent(event event_manager.Event) {
+	d.mu.RLock()
+	var matched []*models.WebhookSubscription
+	for _, sub := range d.subscriptions {
+		if sub.Enabled && sub.Filter().Matches(event) {
+			matched = append(matched, sub)
+		}
+	}
+	d.mu.RUnlock()
+
+	if len(matched) == 0 {
+		return
+	}
+
+	for _, sub := range matched {
+		if _, err := d.enqueue(d.ctx, sub, event); err != nil {
+			log.Error().Err(err).Str("subscriptionId", sub.Id.String()).Str("eventType", string(event.Type)).Msg("Failed to queue webhook delivery")
+		}
+	}
+	d.notify()
+}
+
+func (d *Dispatcher) enqueue(ctx context.Context, sub *models.WebhookSubscription, event event_manager.Event) (*models.WebhookDelivery, error) {
+	payload, err := BuildPayload(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	delivery := &models.WebhookDelivery{
+		SubscriptionId: sub.Id,
+		EventId:        event.ID,
+		EventType:      string(event.Type),
+		Payload:        string(payload),
+	}
+	if event.ServerID != uuid.Nil {
+		serverID := event.ServerID
+		delivery.ServerId = &serverID
+	}
+
+	if err := core.EnqueueWebhookDelivery(ctx, d.db, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (d *Dispatcher) deliveryLoop() {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	pruneTicker := time.NewTicker(time.Hour)
+	defer pruneTicker.Stop()
+
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-pruneTicker.C:
+			if pruned, err := core.PruneWebhookDeliveries(d.ctx, d.db, deliveryRetention); err != nil {
+				log.Error().Err(err).Msg("Failed to prune webhook deliveries")
+			} else if pruned > 0 {
+				log.Info().Int64("pruned", pruned).Msg("Pruned old webhook deliveries")
+			}
+		case <-ticker.C:
+			d.deliverDue()
+		case <-d.wake:
+			d.deliverDue()
+		}
+	}
+}
+
+// deliverDue sends due deliveries in batches until none are left.
+func (d *Dispatcher) deliverDue() {
+	for d.ctx.Err() == nil {
+		deliveries, err := core.ClaimDueWebhookDeliveries(d.ctx, d.db, deliveryLease, claimBatchSize)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to claim webhook deliveries")
+			return
+		}
+		if len(deliveries) == 0 {
+			return
+		}
+
+		var wg sync.WaitGroup
+		for _, delivery := range deliveries {
+			sub := d.subscription(delivery.SubscriptionId)
+			if sub == nil || !sub.Enabled {
+				// Deleted subscriptions take their deliveries with them. Disabled
+				// ones are not claimed, so this only catches a subscription
+				// disabled since the claim; its deliveries wait out the lease.
+				continue
+			}
+
+			wg.Add(1)
+			go func(sub *models.WebhookSubscription, delivery *models.WebhookDelivery) {
+				defer wg.Done()
+				d.attempt(d.ctx, sub, delivery)
+				if err := core.RecordWebhookAttempt(d.ctx, d.db, delivery); err != nil {
+					log.Error().Err(err).Str("deliveryId", delivery.Id.String()).Msg("Failed to record webhook attempt")
+				}
+			}(sub, delivery)
+		}
+		wg.Wait()
+	}
+}
+
+// attempt sends a delivery once and updates it with the outcome. It does not
+// touch the database.
+func (d *Dispatcher) attempt(ctx context.Context, sub *models.WebhookSubscription, delivery *models.WebhookDelivery) {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = null.TimeFrom(now)
+	delivery.ResponseStatus = null.Int{}
+	delivery.ResponseBody = null.String{}
+	delivery.LastError = null.String{}
+
+	statusCode, body, err := d.send(ctx, sub, delivery, now)
+	delivery.DurationMs = null.IntFrom(time.Since(now).Milliseconds())
+	if statusCode != 0 {
+		delivery.ResponseStatus = null.IntFrom(int64(statusCode))
+		delivery.ResponseBody = null.StringFrom(body)
+	}
+	if err == nil && (statusCode < 200 || statusCode >= 300) {
+		err = fmt.Errorf("endpoint returned status %d", statusCode)
+	}
+
+	if err == nil {
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.NextAttemptAt = null.Time{}
+		delivery.CompletedAt = null.TimeFrom(time.Now())
+		return
+	}
+
+	delivery.LastError = null.StringFrom(err.Error())
+
+	maxAttempts := sub.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	if delivery.Attempts >= maxAttempts {
+		delivery.Status = models.WebhookDeliveryDead
+		delivery.NextAttemptAt = null.Time{}
+		delivery.CompletedAt = null.TimeFrom(time.Now())
+		log.Warn().Str("subscriptionId", sub.Id.String()).Str("deliveryId", delivery.Id.String()).Err(err).Msg("Webhook delivery moved to dead-letter queue")
+		return
+	}
+
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.NextAttemptAt = null.TimeFrom(now.Add(Backoff(delivery.Attempts)))
+}
+
+func (d *Dispatcher) send(ctx context.Context, sub *models.WebhookSubscription, delivery *models.WebhookDelivery, now time.Time) (int, string, error) {
+	if err := d.validateURL(sub.Url); err != nil {
+		return 0, "", fmt.Errorf("webhook URL blocked: %w", err)
+	}
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Squad-Aegis-Webhooks/1.0")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.Id.String())
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(sub.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	// The response is stored as text, which must be valid UTF-8 without NULs.
+	cleaned := strings.ReplaceAll(strings.ToValidUTF8(string(responseBody), "\uFFFD"), "\x00", "")
+	return resp.StatusCode, cleaned, nil
+}
@@ -0,0 +1,128 @@
+package webhook_dispatcher
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+func testDispatcher() *Dispatcher {
+	d := NewDispatcher(context.Background(), nil, nil)
+	// httptest servers listen on loopback, which the default validator blocks.
+	d.validateURL = func(string) error { return nil }
+	d.allowIP = func(net.IP) error { return nil }
+	return d
+}
+
+func testDelivery() *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		Id:        uuid.New(),
+		EventId:   uuid.New(),
+		EventType: "AEGIS_BAN_CREATED",
+		Payload:   `{"type":"AEGIS_BAN_CREATED"}`,
+		Status:    models.WebhookDeliveryPending,
+	}
+}
+
+func TestAttemptSignsAndSucceeds(t *testing.T) {
+	sub := &models.WebhookSubscription{Id: uuid.New(), Secret: "secret", Enabled: true}
+	delivery := testDelivery()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		if !Verify(sub.Secret, r.Header.Get(SignatureHeader), timestamp, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get(EventHeader) != delivery.EventType || r.Header.Get(DeliveryHeader) != delivery.Id.String() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	sub.Url = srv.URL
+
+	testDispatcher().attempt(context.Background(), sub, delivery)
+
+	if delivery.Status != models.WebhookDeliverySucceeded {
+		t.Fatalf("Status = %q, want succeeded (error %v, response %v)", delivery.Status, delivery.LastError, delivery.ResponseStatus)
+	}
+	if delivery.Attempts != 1 || delivery.ResponseStatus.Int64 != http.StatusOK || delivery.ResponseBody.String != "ok" {
+		t.Fatalf("delivery = %+v, want one attempt with a 200 ok response", delivery)
+	}
+	if !delivery.CompletedAt.Valid || delivery.NextAttemptAt.Valid {
+		t.Fatal("succeeded delivery should be completed and not rescheduled")
+	}
+}
+
+func TestAttemptRetriesThenDeadLetters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	sub := &models.WebhookSubscription{Id: uuid.New(), Url: srv.URL, Secret: "secret", Enabled: true, MaxAttempts: 2}
+	delivery := testDelivery()
+	d := testDispatcher()
+
+	before := time.Now()
+	d.attempt(context.Background(), sub, delivery)
+	if delivery.Status != models.WebhookDeliveryPending || !delivery.LastError.Valid {
+		t.Fatalf("after first failure Status = %q, LastError = %v, want pending with an error", delivery.Status, delivery.LastError)
+	}
+	if !delivery.NextAttemptAt.Valid || delivery.NextAttemptAt.Time.Before(before.Add(Backoff(1))) {
+		t.Fatalf("NextAttemptAt = %v, want at least %v from now", delivery.NextAttemptAt, Backoff(1))
+	}
+
+	d.attempt(context.Background(), sub, delivery)
+	if delivery.Status != models.WebhookDeliveryDead || delivery.Attempts != 2 {
+		t.Fatalf("after max attempts Status = %q, Attempts = %d, want dead after 2", delivery.Status, delivery.Attempts)
+	}
+	if delivery.ResponseStatus.Int64 != http.StatusServiceUnavailable || !delivery.CompletedAt.Valid {
+		t.Fatalf("dead delivery = %+v, want the last response recorded", delivery)
+	}
+}
+
+func TestAttemptBlockedURLFails(t *testing.T) {
+	d := NewDispatcher(context.Background(), nil, nil)
+	sub := &models.WebhookSubscription{Id: uuid.New(), Url: "http://127.0.0.1:1/hook", Secret: "secret", Enabled: true}
+	delivery := testDelivery()
+
+	d.attempt(context.Background(), sub, delivery)
+
+	if delivery.Status != models.WebhookDeliveryPending || !delivery.LastError.Valid || delivery.ResponseStatus.Valid {
+		t.Fatalf("delivery = %+v, want a failed attempt without a response", delivery)
+	}
+}
+
+func TestAttemptBlocksInternalDialAfterValidation(t *testing.T) {
+	hit := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer srv.Close()
+
+	// The URL passes validation, as a rebinding name would, but the dial
+	// lands on loopback.
+	d := NewDispatcher(context.Background(), nil, nil)
+	d.validateURL = func(string) error { return nil }
+	sub := &models.WebhookSubscription{Id: uuid.New(), Url: srv.URL, Secret: "secret", Enabled: true}
+	delivery := testDelivery()
+
+	d.attempt(context.Background(), sub, delivery)
+
+	if hit || delivery.ResponseStatus.Valid || !strings.Contains(delivery.LastError.String, "private/internal IP") {
+		t.Fatalf("hit = %v, delivery = %+v, want the dial refused", hit, delivery)
+	}
+}
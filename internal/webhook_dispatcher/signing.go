@@ -0,0 +1,98 @@
+package webhook_dispatcher
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
+)
+
+// Headers sent with every delivery.
+const (
+	EventHeader     = "X-Aegis-Event"
+	DeliveryHeader  = "X-Aegis-Delivery"
+	TimestampHeader = "X-Aegis-Timestamp"
+	SignatureHeader = "X-Aegis-Signature"
+)
+
+const (
+	// DefaultMaxAttempts is how many times a delivery is tried before it is
+	// moved to the dead-letter queue.
+	DefaultMaxAttempts = 8
+	baseBackoff        = 30 * time.Second
+	maxBackoff         = 6 * time.Hour
+)
+
+// Payload is the JSON body of every delivery.
+type Payload struct {
+	ID        uuid.UUID  `json:"id"`
+	Type      string     `json:"type"`
+	ServerID  *uuid.UUID `json:"server_id,omitempty"`
+	Timestamp time.Time  `json:"timestamp"`
+	Data      any        `json:"data"`
+}
+
+// BuildPayload encodes an event for delivery. Events that are not tied to a
+// server, such as global audit actions, omit server_id.
+func BuildPayload(event event_manager.Event) ([]byte, error) {
+	payload := Payload{
+		ID:        event.ID,
+		Type:      string(event.Type),
+		Timestamp: event.Timestamp.UTC(),
+		Data:      event.Data,
+	}
+	if event.ServerID != uuid.Nil {
+		serverID := event.ServerID
+		payload.ServerID = &serverID
+	}
+	return json.Marshal(payload)
+}
+
+// GenerateSecret returns a new random signing secret.
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// Sign returns the X-Aegis-Signature value for a body sent at timestamp (unix
+// seconds). The timestamp is covered so a captured delivery cannot be
+// replayed later with a fresh timestamp header.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature produced by Sign in constant time. Receivers
+// should also reject timestamps too far from their own clock.
+func Verify(secret, signature string, timestamp int64, body []byte) bool {
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
+
+// Backoff returns how long to wait before retrying a delivery that has
+// failed attempts times: 30s, 1m, 2m, 4m and so on, capped at six hours.
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := baseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,110 @@
+package webhook_dispatcher
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"type":"AEGIS_BAN_CREATED"}`)
+	signature := Sign("secret", 1700000000, body)
+
+	if !strings.HasPrefix(signature, "sha256=") {
+		t.Fatalf("Sign() = %q, want sha256= prefix", signature)
+	}
+	if !Verify("secret", signature, 1700000000, body) {
+		t.Fatal("Verify() rejected a valid signature")
+	}
+	if Verify("other", signature, 1700000000, body) {
+		t.Fatal("Verify() accepted the wrong secret")
+	}
+	if Verify("secret", signature, 1700000001, body) {
+		t.Fatal("Verify() accepted a different timestamp")
+	}
+	if Verify("secret", signature, 1700000000, []byte(`{"type":"AEGIS_BAN_REMOVED"}`)) {
+		t.Fatal("Verify() accepted a modified body")
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	first, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+	second, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+	if first == second {
+		t.Fatal("GenerateSecret() returned the same secret twice")
+	}
+	if !strings.HasPrefix(first, "whsec_") || len(first) != len("whsec_")+64 {
+		t.Fatalf("GenerateSecret() = %q, want whsec_ and 64 hex characters", first)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{8, 64 * time.Minute},
+		{12, 6 * time.Hour},
+		{100, 6 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := Backoff(tt.attempts); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestBuildPayload(t *testing.T) {
+	serverID := uuid.New()
+	event := event_manager.Event{
+		ID:        uuid.New(),
+		ServerID:  serverID,
+		Type:      event_manager.EventTypeAegisBanCreated,
+		Data:      event_manager.AegisBanData{BanID: "ban-1", Reason: "cheating", Action: "created"},
+		Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.FixedZone("test", 3600)),
+	}
+
+	body, err := BuildPayload(event)
+	if err != nil {
+		t.Fatalf("BuildPayload() error = %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if decoded["type"] != "AEGIS_BAN_CREATED" || decoded["server_id"] != serverID.String() {
+		t.Fatalf("payload = %s, want type and server_id", body)
+	}
+	if decoded["timestamp"] != "2025-01-02T02:04:05Z" {
+		t.Fatalf("payload timestamp = %v, want UTC", decoded["timestamp"])
+	}
+	if data, ok := decoded["data"].(map[string]any); !ok || data["ban_id"] != "ban-1" {
+		t.Fatalf("payload data = %v, want event data", decoded["data"])
+	}
+
+	// Global events, such as audit actions outside a server, omit server_id.
+	event.ServerID = uuid.Nil
+	body, err = BuildPayload(event)
+	if err != nil {
+		t.Fatalf("BuildPayload() error = %v", err)
+	}
+	if strings.Contains(string(body), "server_id") {
+		t.Fatalf("payload = %s, want no server_id for global events", body)
+	}
+}
@@ -0,0 +1,323 @@
+package workflow_manager
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	lua "github.com/yuin/gopher-lua"
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+// newLuaKVTable builds the Lua API for a KV store. namespace is empty for the
+// workflow's own store; read-only namespaces reject writes.
+func (wm *WorkflowManager) newLuaKVTable(L *lua.LState, workflowContext *models.WorkflowExecutionContext, store *KVStore, namespace string, writable bool) *lua.LTable {
+	withContext := func(event *zerolog.Event) *zerolog.Event {
+		event = event.
+			Str("execution_id", workflowContext.ExecutionID.String()).
+			Str("workflow_id", workflowContext.WorkflowID.String())
+		if namespace != "" {
+			event = event.Str("namespace", namespace)
+		}
+		return event
+	}
+
+	// readOnly pushes the error for a write to a read-only namespace
+	readOnly := func(L *lua.LState, failure lua.LValue) int {
+		L.Push(failure)
+		L.Push(lua.LString(fmt.Sprintf("namespace %s is read-only for this workflow", namespace)))
+		return 2
+	}
+
+	ttlArg := func(L *lua.LState, n int) time.Duration {
+		return time.Duration(float64(L.OptNumber(n, 0)) * float64(time.Second))
+	}
+
+	kvTable := L.NewTable()
+	L.SetField(kvTable, "get", L.NewFunction(func(L *lua.LState) int {
+		key := L.CheckString(1)
+		defaultValue := L.Get(2) // Optional default value
+
+		value, err := store.Get(key)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				// Key doesn't exist, return default value
+				L.Push(defaultValue)
+				return 1
+			}
+			withContext(log.Error().Err(err)).Str("key", key).Msg("LUA kv.get failed")
+			L.Push(lua.LNil)
+			return 1
+		}
+
+		L.Push(wm.convertToLuaValue(L, value))
+		return 1
+	}))
+	L.SetField(kvTable, "set", L.NewFunction(func(L *lua.LState) int {
+		key := L.CheckString(1)
+		value := L.Get(2)
+		ttl := ttlArg(L, 3)
+
+		if !writable {
+			return readOnly(L, lua.LFalse)
+		}
+		if value == lua.LNil {
+			L.Push(lua.LBool(false))
+			L.Push(lua.LString("cannot set nil value, use kv.delete to remove keys"))
+			return 2
+		}
+
+		goValue := wm.convertFromLuaValue(value)
+
+		if err := store.Set(key, goValue, ttl); err != nil {
+			withContext(log.Error().Err(err)).Str("key", key).Msg("LUA kv.set failed")
+			L.Push(lua.LBool(false))
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+
+		withContext(log.Debug()).
+			Str("key", key).
+			Interface("value", goValue).
+			Dur("ttl", ttl).
+			Msg("LUA script set KV store value")
+
+		L.Push(lua.LBool(true))
+		L.Push(lua.LNil)
+		return 2 // Return success boolean and error
+	}))
+	L.SetField(kvTable, "delete", L.NewFunction(func(L *lua.LState) int {
+		key := L.CheckString(1)
+
+		if !writable {
+			return readOnly(L, lua.LFalse)
+		}
+
+		if err := store.Delete(key); err != nil {
+			withContext(log.Error().Err(err)).Str("key", key).Msg("LUA kv.delete failed")
+			L.Push(lua.LBool(false))
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+
+		withContext(log.Debug()).Str("key", key).Msg("LUA script deleted KV store key")
+
+		L.Push(lua.LBool(true))
+		L.Push(lua.LNil)
+		return 2 // Return success boolean and error
+	}))
+	L.SetField(kvTable, "exists", L.NewFunction(func(L *lua.LState) int {
+		key := L.CheckString(1)
+
+		exists, err := store.Exists(key)
+		if err != nil {
+			withContext(log.Error().Err(err)).Str("key", key).Msg("LUA kv.exists failed")
+			L.Push(lua.LBool(false))
+			return 1
+		}
+
+		L.Push(lua.LBool(exists))
+		return 1
+	}))
+	L.SetField(kvTable, "keys", L.NewFunction(func(L *lua.LState) int {
+		keys, err := store.Keys()
+		if err != nil {
+			withContext(log.Error().Err(err)).Msg("LUA kv.keys failed")
+			L.Push(lua.LNil)
+			return 1
+		}
+
+		// Create a Lua table with the keys
+		table := L.NewTable()
+		for i, key := range keys {
+			table.RawSetInt(i+1, lua.LString(key)) // Lua arrays are 1-indexed
+		}
+
+		L.Push(table)
+		return 1
+	}))
+	L.SetField(kvTable, "get_all", L.NewFunction(func(L *lua.LState) int {
+		kvPairs, err := store.All()
+		if err != nil {
+			withContext(log.Error().Err(err)).Msg("LUA kv.get_all failed")
+			L.Push(lua.LNil)
+			return 1
+		}
+
+		// Create a Lua table with the key-value pairs
+		table := L.NewTable()
+		for key, value := range kvPairs {
+			L.SetField(table, key, wm.convertToLuaValue(L, value))
+		}
+
+		L.Push(table)
+		return 1
+	}))
+	L.SetField(kvTable, "clear", L.NewFunction(func(L *lua.LState) int {
+		if !writable {
+			return readOnly(L, lua.LFalse)
+		}
+
+		if err := store.Clear(); err != nil {
+			withContext(log.Error().Err(err)).Msg("LUA kv.clear failed")
+			L.Push(lua.LBool(false))
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+
+		withContext(log.Info()).Msg("LUA script cleared KV store")
+
+		L.Push(lua.LBool(true))
+		L.Push(lua.LNil)
+		return 2 // Return success boolean and error
+	}))
+	L.SetField(kvTable, "count", L.NewFunction(func(L *lua.LState) int {
+		count, err := store.Count()
+		if err != nil {
+			withContext(log.Error().Err(err)).Msg("LUA kv.count failed")
+			L.Push(lua.LNumber(0))
+			return 1
+		}
+
+		L.Push(lua.LNumber(count))
+		return 1
+	}))
+	L.SetField(kvTable, "increment", L.NewFunction(func(L *lua.LState) int {
+		key := L.CheckString(1)
+		delta := L.OptNumber(2, 1) // Default increment by 1
+		opts := L.OptTable(3, L.NewTable())
+
+		if !writable {
+			return readOnly(L, lua.LNil)
+		}
+
+		var bounds KVBounds
+		if min, ok := opts.RawGetString("min").(lua.LNumber); ok {
+			value := float64(min)
+			bounds.Min = &value
+		}
+		if max, ok := opts.RawGetString("max").(lua.LNumber); ok {
+			value := float64(max)
+			bounds.Max = &value
+		}
+		var ttl time.Duration
+		if seconds, ok := opts.RawGetString("ttl").(lua.LNumber); ok {
+			ttl = time.Duration(float64(seconds) * float64(time.Second))
+		}
+
+		newNum, err := store.Increment(key, float64(delta), bounds, ttl)
+		if err != nil {
+			if !errors.Is(err, ErrKVOutOfBounds) && !errors.Is(err, ErrKVNotNumber) {
+				withContext(log.Error().Err(err)).Str("key", key).Msg("LUA kv.increment failed")
+			}
+			L.Push(lua.LNil)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+
+		L.Push(lua.LNumber(newNum))
+		L.Push(lua.LNil)
+		return 2 // Return new value and error
+	}))
+	L.SetField(kvTable, "compare_and_set", L.NewFunction(func(L *lua.LState) int {
+		key := L.CheckString(1)
+		expected := L.Get(2)
+		value := L.Get(3)
+		ttl := ttlArg(L, 4)
+
+		if !writable {
+			return readOnly(L, lua.LFalse)
+		}
+		if value == lua.LNil {
+			L.Push(lua.LBool(false))
+			L.Push(lua.LString("cannot set nil value, use kv.delete to remove keys"))
+			return 2
+		}
+
+		var expectedValue interface{}
+		if expected != lua.LNil {
+			expectedValue = wm.convertFromLuaValue(expected)
+		}
+
+		swapped, err := store.CompareAndSet(key, expectedValue, wm.convertFromLuaValue(value), ttl)
+		if err != nil {
+			withContext(log.Error().Err(err)).Str("key", key).Msg("LUA kv.compare_and_set failed")
+			L.Push(lua.LBool(false))
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+
+		L.Push(lua.LBool(swapped))
+		L.Push(lua.LNil)
+		return 2 // Return whether the value was replaced and error
+	}))
+	L.SetField(kvTable, "ttl", L.NewFunction(func(L *lua.LState) int {
+		key := L.CheckString(1)
+
+		ttl, err := store.TTL(key)
+		if err != nil {
+			if err != sql.ErrNoRows {
+				withContext(log.Error().Err(err)).Str("key", key).Msg("LUA kv.ttl failed")
+			}
+			L.Push(lua.LNil)
+			return 1
+		}
+		if ttl == nil {
+			L.Push(lua.LNil)
+			return 1
+		}
+
+		L.Push(lua.LNumber(ttl.Seconds()))
+		return 1
+	}))
+	L.SetField(kvTable, "expire", L.NewFunction(func(L *lua.LState) int {
+		key := L.CheckString(1)
+		ttl := ttlArg(L, 2)
+
+		if !writable {
+			return readOnly(L, lua.LFalse)
+		}
+
+		exists, err := store.Expire(key, ttl)
+		if err != nil {
+			withContext(log.Error().Err(err)).Str("key", key).Msg("LUA kv.expire failed")
+			L.Push(lua.LBool(false))
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+
+		L.Push(lua.LBool(exists))
+		L.Push(lua.LNil)
+		return 2 // Return whether the key exists and error
+	}))
+
+	return kvTable
+}
+
+// openLuaKVNamespace implements workflow.kv.namespace(name), returning the
+// namespace's KV API or nil and an error if the workflow has no access.
+func (wm *WorkflowManager) openLuaKVNamespace(L *lua.LState, workflowContext *models.WorkflowExecutionContext) int {
+	name := L.CheckString(1)
+
+	namespaceID, access, err := wm.workflowDB.KVNamespaceAccess(workflowContext.ServerID, name, workflowContext.WorkflowID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Error().
+				Err(err).
+				Str("execution_id", workflowContext.ExecutionID.String()).
+				Str("namespace", name).
+				Msg("LUA kv.namespace failed")
+		}
+		L.Push(lua.LNil)
+		L.Push(lua.LString(fmt.Sprintf("namespace %s does not exist or this workflow has no access to it", name)))
+		return 2
+	}
+
+	store := wm.workflowDB.NamespaceKV(namespaceID)
+	L.Push(wm.newLuaKVTable(L, workflowContext, store, name, access == models.KVAccessWrite))
+	L.Push(lua.LNil)
+	return 2
+}
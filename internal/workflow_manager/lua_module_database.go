@@ -0,0 +1,237 @@
+package workflow_manager
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+// Lua module operations
+
+const luaModuleColumns = `id, server_id, name, description, latest_version, created_by, created_at, updated_at`
+
+func scanLuaModule(row interface{ Scan(...any) error }) (*models.LuaModule, error) {
+	var module models.LuaModule
+	var description sql.NullString
+
+	if err := row.Scan(
+		&module.ID,
+		&module.ServerID,
+		&module.Name,
+		&description,
+		&module.LatestVersion,
+		&module.CreatedBy,
+		&module.CreatedAt,
+		&module.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if description.Valid {
+		module.Description = &description.String
+	}
+	return &module, nil
+}
+
+// ListLuaModules returns the modules visible to a server: its own and the
+// global ones. A nil serverID lists only global modules.
+func (wd *WorkflowDatabase) ListLuaModules(serverID *uuid.UUID) ([]models.LuaModule, error) {
+	query := `
+		SELECT ` + luaModuleColumns + `
+		FROM lua_modules
+		WHERE server_id = $1 OR server_id IS NULL
+		ORDER BY name, server_id NULLS LAST
+	`
+
+	rows, err := wd.db.Query(query, serverID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	modules := []models.LuaModule{}
+	for rows.Next() {
+		module, err := scanLuaModule(rows)
+		if err != nil {
+			return nil, err
+		}
+		modules = append(modules, *module)
+	}
+
+	return modules, rows.Err()
+}
+
+// GetLuaModule retrieves a module by ID
+func (wd *WorkflowDatabase) GetLuaModule(moduleID uuid.UUID) (*models.LuaModule, error) {
+	query := `SELECT ` + luaModuleColumns + ` FROM lua_modules WHERE id = $1`
+	return scanLuaModule(wd.db.QueryRow(query, moduleID))
+}
+
+// CreateLuaModule creates a module together with its first version
+func (wd *WorkflowDatabase) CreateLuaModule(module *models.LuaModule, version *models.LuaModuleVersion) error {
+	tx, err := wd.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	module.LatestVersion = 1
+	_, err = tx.Exec(`
+		INSERT INTO lua_modules (id, server_id, name, description, latest_version, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, module.ID, module.ServerID, module.Name, module.Description, module.LatestVersion, module.CreatedBy, module.CreatedAt, module.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	version.ModuleID = module.ID
+	version.Version = module.LatestVersion
+	if err := insertLuaModuleVersion(tx, version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AddLuaModuleVersion saves a new version of a module and makes it the
+// latest. The module's description is updated along with it.
+func (wd *WorkflowDatabase) AddLuaModuleVersion(module *models.LuaModule, version *models.LuaModuleVersion) error {
+	tx, err := wd.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		UPDATE lua_modules
+		SET latest_version = latest_version + 1, description = $2, updated_at = NOW()
+		WHERE id = $1
+		RETURNING latest_version, updated_at
+	`, module.ID, module.Description).Scan(&module.LatestVersion, &module.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	version.ModuleID = module.ID
+	version.Version = module.LatestVersion
+	if err := insertLuaModuleVersion(tx, version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insertLuaModuleVersion(tx *sql.Tx, version *models.LuaModuleVersion) error {
+	_, err := tx.Exec(`
+		INSERT INTO lua_module_versions (id, module_id, version, source, test_source, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, version.ID, version.ModuleID, version.Version, version.Source, version.TestSource, version.CreatedBy, version.CreatedAt)
+	return err
+}
+
+// DeleteLuaModule deletes a module and all of its versions
+func (wd *WorkflowDatabase) DeleteLuaModule(moduleID uuid.UUID) error {
+	_, err := wd.db.Exec(`DELETE FROM lua_modules WHERE id = $1`, moduleID)
+	return err
+}
+
+// GetLuaModuleVersion retrieves one version of a module. Version 0 returns
+// the latest.
+func (wd *WorkflowDatabase) GetLuaModuleVersion(moduleID uuid.UUID, version int) (*models.LuaModuleVersion, error) {
+	query := `
+		SELECT v.id, v.module_id, v.version, v.source, v.test_source, v.created_by, v.created_at
+		FROM lua_module_versions v
+		JOIN lua_modules m ON m.id = v.module_id
+		WHERE v.module_id = $1 AND v.version = CASE WHEN $2 = 0 THEN m.latest_version ELSE $2 END
+	`
+
+	var v models.LuaModuleVersion
+	err := wd.db.QueryRow(query, moduleID, version).Scan(
+		&v.ID,
+		&v.ModuleID,
+		&v.Version,
+		&v.Source,
+		&v.TestSource,
+		&v.CreatedBy,
+		&v.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// ListLuaModuleVersions lists a module's versions, newest first, without
+// their source
+func (wd *WorkflowDatabase) ListLuaModuleVersions(moduleID uuid.UUID) ([]models.LuaModuleVersion, error) {
+	rows, err := wd.db.Query(`
+		SELECT id, module_id, version, created_by, created_at
+		FROM lua_module_versions
+		WHERE module_id = $1
+		ORDER BY version DESC
+	`, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []models.LuaModuleVersion{}
+	for rows.Next() {
+		var v models.LuaModuleVersion
+		if err := rows.Scan(&v.ID, &v.ModuleID, &v.Version, &v.CreatedBy, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+
+	return versions, rows.Err()
+}
+
+// ResolveLuaModule finds the source a server's scripts get for
+// require(name). The server's own module wins over a global one; version 0
+// means the latest. It returns sql.ErrNoRows when nothing matches.
+func (wd *WorkflowDatabase) ResolveLuaModule(serverID *uuid.UUID, name string, version int) (*models.LuaModuleVersion, error) {
+	query := `
+		SELECT v.id, v.module_id, v.version, v.source, v.test_source, v.created_by, v.created_at
+		FROM lua_modules m
+		JOIN lua_module_versions v ON v.module_id = m.id
+		WHERE m.name = $2
+			AND (m.server_id = $1 OR m.server_id IS NULL)
+			AND v.version = CASE WHEN $3 = 0 THEN m.latest_version ELSE $3 END
+		ORDER BY m.server_id NULLS LAST
+		LIMIT 1
+	`
+
+	var v models.LuaModuleVersion
+	err := wd.db.QueryRow(query, serverID, name, version).Scan(
+		&v.ID,
+		&v.ModuleID,
+		&v.Version,
+		&v.Source,
+		&v.TestSource,
+		&v.CreatedBy,
+		&v.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// luaModuleLoader returns a loader that resolves modules for serverID
+func (wd *WorkflowDatabase) luaModuleLoader(serverID *uuid.UUID) luaModuleLoader {
+	return func(name string, version int) (string, error) {
+		v, err := wd.ResolveLuaModule(serverID, name, version)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return "", errLuaModuleNotFound
+			}
+			return "", fmt.Errorf("failed to load module: %w", err)
+		}
+		return v.Source, nil
+	}
+}
@@ -0,0 +1,406 @@
+package workflow_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	lua "github.com/yuin/gopher-lua"
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+var (
+	errLuaModuleNotFound = errors.New("module not found")
+
+	luaModuleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)*$`)
+)
+
+// ValidLuaModuleName reports whether name can be used as a module name:
+// lowercase words separated by dots, such as "utils.cooldown".
+func ValidLuaModuleName(name string) bool {
+	return len(name) <= 100 && luaModuleNamePattern.MatchString(name)
+}
+
+// luaModuleLoader returns the source of a module. Version 0 means the
+// latest; errLuaModuleNotFound is returned when there is no such module.
+type luaModuleLoader func(name string, version int) (string, error)
+
+// parseLuaModuleSpec splits a require() argument of the form "name" or
+// "name@version".
+func parseLuaModuleSpec(spec string) (string, int, error) {
+	name, versionStr, pinned := strings.Cut(spec, "@")
+	if !ValidLuaModuleName(name) {
+		return "", 0, fmt.Errorf("invalid module name %q", name)
+	}
+	if !pinned {
+		return name, 0, nil
+	}
+
+	version, err := strconv.Atoi(versionStr)
+	if err != nil || version < 1 {
+		return "", 0, fmt.Errorf("invalid module version %q", versionStr)
+	}
+	return name, version, nil
+}
+
+// installLuaRequire adds a require() that only loads modules through
+// loader. Each module runs once per state and require returns its cached
+// result afterwards, as in standard Lua.
+func installLuaRequire(L *lua.LState, loader luaModuleLoader) {
+	loaded := map[string]lua.LValue{}
+	loading := map[string]bool{}
+
+	L.SetGlobal("require", L.NewFunction(func(L *lua.LState) int {
+		spec := L.CheckString(1)
+		name, version, err := parseLuaModuleSpec(spec)
+		if err != nil {
+			L.ArgError(1, err.Error())
+			return 0
+		}
+
+		key := name + "@" + strconv.Itoa(version)
+		if value, ok := loaded[key]; ok {
+			L.Push(value)
+			return 1
+		}
+		if loading[key] {
+			L.RaiseError("module %q is required recursively", spec)
+			return 0
+		}
+
+		source, err := loader(name, version)
+		if err != nil {
+			L.RaiseError("module %q: %s", spec, err.Error())
+			return 0
+		}
+
+		chunk, err := L.Load(strings.NewReader(source), "@"+name)
+		if err != nil {
+			L.RaiseError("module %q: %s", spec, err.Error())
+			return 0
+		}
+
+		// Errors unwind through here as panics, so clear the flag in a defer
+		// or a module that fails once could never be required again.
+		loading[key] = true
+		defer delete(loading, key)
+		L.Push(chunk)
+		L.Push(lua.LString(name))
+		L.Call(1, 1)
+
+		value := L.Get(-1)
+		L.Pop(1)
+		if value == lua.LNil {
+			value = lua.LTrue
+		}
+		loaded[key] = value
+
+		L.Push(value)
+		return 1
+	}))
+}
+
+// luaModuleTestPrelude replaces the side-effecting workflow namespaces with
+// in-memory mocks. reset_mocks() runs before every test so state does not
+// leak between them. Tests can inspect workflow.rcon.calls and
+// workflow.log.messages, and script RCON replies through
+// workflow.rcon.responses.
+const luaModuleTestPrelude = `
+local function deep_equal(a, b)
+	if a == b then return true end
+	if type(a) ~= "table" or type(b) ~= "table" then return false end
+	for k, v in pairs(a) do
+		if not deep_equal(v, b[k]) then return false end
+	end
+	for k in pairs(b) do
+		if a[k] == nil then return false end
+	end
+	return true
+end
+
+function assert_equal(actual, expected, message)
+	if not deep_equal(actual, expected) then
+		local detail = "expected " .. workflow.json.encode(expected) .. ", got " .. workflow.json.encode(actual)
+		error((message and (message .. ": ") or "") .. detail, 2)
+	end
+end
+
+function reset_mocks()
+	local log = { messages = {} }
+	for _, level in ipairs({ "debug", "info", "warn", "error" }) do
+		log[level] = function(message)
+			table.insert(log.messages, { level = string.upper(level), message = tostring(message) })
+		end
+	end
+	workflow.log = log
+
+	local variables = {}
+	workflow.variables = variables
+	workflow.variable = {
+		set = function(name, value) variables[name] = value end,
+		get = function(name, default)
+			local value = variables[name]
+			if value == nil then return default end
+			return value
+		end,
+	}
+
+	local store = {}
+	local kv = {}
+	function kv.get(key, default)
+		local value = store[key]
+		if value == nil then return default end
+		return value
+	end
+	function kv.set(key, value) store[key] = value return true end
+	function kv.delete(key) store[key] = nil return true end
+	function kv.compare_and_set(key, expected, value)
+		if not deep_equal(store[key], expected) then return false end
+		store[key] = value
+		return true
+	end
+	function kv.ttl(key) return nil end
+	function kv.expire(key) return store[key] ~= nil end
+	function kv.exists(key) return store[key] ~= nil end
+	function kv.keys()
+		local keys = {}
+		for key in pairs(store) do table.insert(keys, key) end
+		table.sort(keys)
+		return keys
+	end
+	function kv.get_all()
+		local all = {}
+		for key, value in pairs(store) do all[key] = value end
+		return all
+	end
+	function kv.clear() store = {} return true end
+	function kv.count()
+		local count = 0
+		for _ in pairs(store) do count = count + 1 end
+		return count
+	end
+	function kv.increment(key, delta, opts)
+		local value = (tonumber(store[key]) or 0) + (delta or 1)
+		opts = opts or {}
+		if (opts.min and value < opts.min) or (opts.max and value > opts.max) then
+			return nil, "increment would pass its bounds"
+		end
+		store[key] = value
+		return value
+	end
+	-- Namespaces share the script's store in tests
+	function kv.namespace(name) return kv end
+	workflow.kv = kv
+
+	local rcon = { calls = {}, responses = {} }
+	local function record(fn, ...)
+		table.insert(rcon.calls, { fn = fn, args = { ... } })
+	end
+	function rcon.execute(command)
+		record("execute", command)
+		local response = rcon.responses[command]
+		if response == nil then response = "" end
+		return response, nil
+	end
+	for _, fn in ipairs({ "kick", "ban", "ban_with_evidence", "warn", "broadcast" }) do
+		rcon[fn] = function(...)
+			record(fn, ...)
+			return true, ""
+		end
+	end
+	workflow.rcon = rcon
+end
+
+reset_mocks()
+`
+
+// luaTestPrefix marks global functions that RunLuaModuleTests runs.
+const luaTestPrefix = "test_"
+
+// RunLuaModuleTests runs the test_* functions defined by testSource against
+// mocked workflow.rcon, workflow.kv, workflow.variable and workflow.log
+// tables. require(moduleName) loads source, so unsaved drafts can be tested;
+// any other module resolves as it would for serverID's workflows.
+func (wm *WorkflowManager) RunLuaModuleTests(ctx context.Context, serverID *uuid.UUID, moduleName, source, testSource string) *models.LuaModuleTestReport {
+	report := &models.LuaModuleTestReport{Tests: []models.LuaModuleTestResult{}}
+
+	limits := resolveLuaLimits(nil, nil)
+	runCtx, cancel := context.WithTimeout(ctx, limits.Timeout)
+	defer cancel()
+
+	L, budget := newLuaSandbox(runCtx, limits)
+	defer L.Close()
+
+	var fallback luaModuleLoader
+	if wm.workflowDB != nil {
+		fallback = wm.workflowDB.luaModuleLoader(serverID)
+	}
+	installLuaRequire(L, func(name string, version int) (string, error) {
+		if name == moduleName && version == 0 {
+			return source, nil
+		}
+		if fallback == nil {
+			return "", errLuaModuleNotFound
+		}
+		return fallback(name, version)
+	})
+
+	workflowTable := L.NewTable()
+	L.SetField(workflowTable, "execution_id", lua.LString(uuid.Nil.String()))
+	if serverID != nil {
+		L.SetField(workflowTable, "server_id", lua.LString(serverID.String()))
+	}
+	for _, name := range []string{"step_results", "trigger_event", "metadata", "config"} {
+		L.SetField(workflowTable, name, L.NewTable())
+	}
+	wm.addLuaDataFunctions(L, workflowTable)
+	L.SetGlobal("workflow", workflowTable)
+	L.SetGlobal("result", L.NewTable())
+
+	if err := L.DoString(luaModuleTestPrelude); err != nil {
+		report.Error = fmt.Sprintf("failed to set up test environment: %v", err)
+		return report
+	}
+	if err := L.DoString(testSource); err != nil {
+		report.Error = luaTestFailure(budget, limits, err)
+		return report
+	}
+
+	var names []string
+	L.G.Global.ForEach(func(key, value lua.LValue) {
+		if name, ok := key.(lua.LString); ok && strings.HasPrefix(string(name), luaTestPrefix) {
+			if _, ok := value.(*lua.LFunction); ok {
+				names = append(names, string(name))
+			}
+		}
+	})
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		report.Error = "no test_* functions defined"
+		return report
+	}
+
+	report.Passed = true
+	for _, name := range names {
+		result := models.LuaModuleTestResult{Name: name}
+		started := time.Now()
+
+		err := L.CallByParam(lua.P{Fn: L.GetGlobal("reset_mocks"), NRet: 0, Protect: true})
+		if err == nil {
+			err = L.CallByParam(lua.P{Fn: L.GetGlobal(name), NRet: 0, Protect: true})
+		}
+
+		result.DurationMs = time.Since(started).Milliseconds()
+		result.Passed = err == nil
+		if err != nil {
+			result.Error = luaTestFailure(budget, limits, err)
+			report.Passed = false
+		}
+
+		workflowTable, _ := L.GetGlobal("workflow").(*lua.LTable)
+		result.Logs = wm.luaListToSlice(L, workflowTable, "log", "messages")
+		result.RconCalls = wm.luaListToSlice(L, workflowTable, "rcon", "calls")
+		report.Tests = append(report.Tests, result)
+
+		if runCtx.Err() != nil || budget.Violation() != nil {
+			// The budget covers the whole run; later tests would fail the same way.
+			break
+		}
+	}
+
+	return report
+}
+
+// luaTestFailure describes why a test run or test failed
+func luaTestFailure(budget *luaBudget, limits luaLimits, err error) string {
+	if violation := budget.Violation(); violation != nil {
+		return luaViolationMessage(violation, limits)
+	}
+	var apiErr *lua.ApiError
+	if errors.As(err, &apiErr) && apiErr.Object != nil {
+		return apiErr.Object.String()
+	}
+	return err.Error()
+}
+
+// luaListToSlice converts the array table at table[namespace][field] to a
+// slice, keeping its order.
+func (wm *WorkflowManager) luaListToSlice(L *lua.LState, table *lua.LTable, namespace, field string) []interface{} {
+	items := []interface{}{}
+	if table == nil {
+		return items
+	}
+	ns, ok := L.GetField(table, namespace).(*lua.LTable)
+	if !ok {
+		return items
+	}
+	list, ok := L.GetField(ns, field).(*lua.LTable)
+	if !ok {
+		return items
+	}
+
+	for i := 1; i <= list.Len(); i++ {
+		items = append(items, luaValueToJSON(list.RawGetInt(i)))
+	}
+	return items
+}
+
+// luaCycleJSON stands in for a table that contains itself, so a
+// self-referencing table converts instead of recursing forever.
+const luaCycleJSON = "<cycle>"
+
+// luaValueToJSON converts a Lua value for a JSON response. Unlike
+// convertFromLuaValue, tables that are sequences become arrays.
+func luaValueToJSON(value lua.LValue) interface{} {
+	return luaValueToJSONVisiting(value, map[*lua.LTable]bool{})
+}
+
+// luaValueToJSONVisiting converts value, replacing any table already on the
+// path from the root with luaCycleJSON. A table reached twice by different
+// paths is converted both times.
+func luaValueToJSONVisiting(value lua.LValue, visiting map[*lua.LTable]bool) interface{} {
+	table, ok := value.(*lua.LTable)
+	if !ok {
+		switch v := value.(type) {
+		case *lua.LNilType:
+			return nil
+		case lua.LString:
+			return string(v)
+		case lua.LNumber:
+			return float64(v)
+		case lua.LBool:
+			return bool(v)
+		default:
+			return value.String()
+		}
+	}
+	if visiting[table] {
+		return luaCycleJSON
+	}
+	visiting[table] = true
+	defer delete(visiting, table)
+
+	length := table.Len()
+	count := 0
+	table.ForEach(func(lua.LValue, lua.LValue) { count++ })
+	if length > 0 && count == length {
+		items := make([]interface{}, 0, length)
+		for i := 1; i <= length; i++ {
+			items = append(items, luaValueToJSONVisiting(table.RawGetInt(i), visiting))
+		}
+		return items
+	}
+
+	result := make(map[string]interface{}, count)
+	table.ForEach(func(key, val lua.LValue) {
+		result[key.String()] = luaValueToJSONVisiting(val, visiting)
+	})
+	return result
+}
@@ -0,0 +1,211 @@
+package workflow_manager
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func mapLuaModuleLoader(modules map[string]string) luaModuleLoader {
+	return func(name string, version int) (string, error) {
+		key := name
+		if version > 0 {
+			key = name + "@" + strconv.Itoa(version)
+		}
+		source, ok := modules[key]
+		if !ok {
+			return "", errLuaModuleNotFound
+		}
+		return source, nil
+	}
+}
+
+func TestParseLuaModuleSpec(t *testing.T) {
+	tests := []struct {
+		spec    string
+		name    string
+		version int
+		wantErr bool
+	}{
+		{spec: "cooldown", name: "cooldown"},
+		{spec: "utils.players@3", name: "utils.players", version: 3},
+		{spec: "Cooldown", wantErr: true},
+		{spec: "../etc/passwd", wantErr: true},
+		{spec: "cooldown@0", wantErr: true},
+		{spec: "cooldown@latest", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		name, version, err := parseLuaModuleSpec(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseLuaModuleSpec(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (name != tt.name || version != tt.version) {
+			t.Errorf("parseLuaModuleSpec(%q) = %q, %d; want %q, %d", tt.spec, name, version, tt.name, tt.version)
+		}
+	}
+}
+
+func TestLuaRequire(t *testing.T) {
+	L, _ := newLuaSandbox(context.Background(), testLuaLimits())
+	defer L.Close()
+
+	installLuaRequire(L, mapLuaModuleLoader(map[string]string{
+		"counter":   `loads = (loads or 0) + 1 return { value = 2 }`,
+		"counter@1": `return { value = 1 }`,
+		"greeting":  `local counter = require("counter") return function(name) return "hi " .. name .. counter.value end`,
+		"loop.a":    `return require("loop.b")`,
+		"loop.b":    `return require("loop.a")`,
+		"empty":     ``,
+	}))
+
+	script := `
+		local greet = require("greeting")
+		assert(greet("bob") == "hi bob2", "nested require failed")
+		assert(require("counter") == require("counter"), "module not cached")
+		assert(loads == 1, "module ran more than once")
+		assert(require("counter@1").value == 1, "pinned version not loaded")
+		assert(require("empty") == true, "empty module should return true")
+		assert(not pcall(require, "missing"), "missing module loaded")
+		assert(not pcall(require, "loop.a"), "recursive require not detected")
+	`
+	if err := L.DoString(script); err != nil {
+		t.Fatalf("require check failed: %v", err)
+	}
+}
+
+func TestLuaRequireRetriesAfterModuleError(t *testing.T) {
+	L, _ := newLuaSandbox(context.Background(), testLuaLimits())
+	defer L.Close()
+
+	installLuaRequire(L, mapLuaModuleLoader(map[string]string{
+		"flaky": `attempts = (attempts or 0) + 1 if attempts == 1 then error("first load fails") end return { ok = true }`,
+	}))
+
+	script := `
+		local ok, err = pcall(require, "flaky")
+		assert(not ok, "first load should fail")
+		assert(not string.find(tostring(err), "recursively"), "first failure reported as recursion: " .. tostring(err))
+		ok, err = pcall(require, "flaky")
+		assert(ok, "second load failed: " .. tostring(err))
+		assert(err.ok == true, "second load returned the wrong module")
+	`
+	if err := L.DoString(script); err != nil {
+		t.Fatalf("require retry check failed: %v", err)
+	}
+}
+
+func TestRunLuaModuleTests(t *testing.T) {
+	wm := &WorkflowManager{}
+
+	source := `
+		local M = {}
+		function M.warn_once(player_id)
+			local key = "warned_" .. player_id
+			if workflow.kv.exists(key) then
+				return false
+			end
+			workflow.kv.set(key, true)
+			workflow.rcon.warn(player_id, "First warning")
+			workflow.log.info("warned " .. player_id)
+			return true
+		end
+		return M
+	`
+	testSource := `
+		local m = require("moderation")
+
+		function test_warns_once()
+			assert_equal(m.warn_once("p1"), true)
+			assert_equal(m.warn_once("p1"), false)
+			assert_equal(#workflow.rcon.calls, 1)
+		end
+
+		function test_state_is_reset()
+			assert_equal(workflow.kv.count(), 0)
+		end
+
+		function test_fails()
+			assert_equal(m.warn_once("p2"), false, "should not warn")
+		end
+	`
+
+	report := wm.RunLuaModuleTests(context.Background(), nil, "moderation", source, testSource)
+	if report.Error != "" {
+		t.Fatalf("unexpected run error: %s", report.Error)
+	}
+	if report.Passed {
+		t.Fatal("expected the run to fail")
+	}
+	if len(report.Tests) != 3 {
+		t.Fatalf("expected 3 tests, got %d", len(report.Tests))
+	}
+
+	results := map[string]bool{}
+	for _, result := range report.Tests {
+		results[result.Name] = result.Passed
+	}
+	if !results["test_warns_once"] || !results["test_state_is_reset"] || results["test_fails"] {
+		t.Fatalf("unexpected results: %+v", report.Tests)
+	}
+
+	for _, result := range report.Tests {
+		switch result.Name {
+		case "test_warns_once":
+			if len(result.RconCalls) != 1 || len(result.Logs) != 1 {
+				t.Errorf("expected one rcon call and one log, got %v and %v", result.RconCalls, result.Logs)
+			}
+			call := result.RconCalls[0].(map[string]interface{})
+			args := call["args"].([]interface{})
+			if call["fn"] != "warn" || args[0] != "p1" {
+				t.Errorf("unexpected rcon call %v", call)
+			}
+		case "test_fails":
+			if !strings.Contains(result.Error, "should not warn") {
+				t.Errorf("expected assertion message, got %q", result.Error)
+			}
+		}
+	}
+}
+
+func TestRunLuaModuleTestsReportsErrors(t *testing.T) {
+	wm := &WorkflowManager{}
+
+	report := wm.RunLuaModuleTests(context.Background(), nil, "broken", `return {`, `local m = require("broken") function test_x() end`)
+	if report.Passed || report.Error == "" {
+		t.Fatalf("expected a load error, got %+v", report)
+	}
+
+	report = wm.RunLuaModuleTests(context.Background(), nil, "empty", `return {}`, `local x = 1`)
+	if report.Passed || report.Error == "" {
+		t.Fatalf("expected an error for missing tests, got %+v", report)
+	}
+}
+
+func TestLuaValueToJSONStopsAtCycles(t *testing.T) {
+	L, _ := newLuaSandbox(context.Background(), testLuaLimits())
+	defer L.Close()
+
+	if err := L.DoString(`
+		shared = { 1, 2 }
+		root = { name = "root", a = shared, b = shared }
+		root.self = root
+	`); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+
+	got, ok := luaValueToJSON(L.GetGlobal("root")).(map[string]interface{})
+	if !ok {
+		t.Fatalf("luaValueToJSON(root) = %T, want map", got)
+	}
+	if got["self"] != luaCycleJSON {
+		t.Fatalf("self = %v, want %q", got["self"], luaCycleJSON)
+	}
+	for _, key := range []string{"a", "b"} {
+		if items, ok := got[key].([]interface{}); !ok || len(items) != 2 {
+			t.Fatalf("%s = %v, want the shared two-item array", key, got[key])
+		}
+	}
+}
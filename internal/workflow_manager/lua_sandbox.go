@@ -0,0 +1,353 @@
+package workflow_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+	"go.codycody31.dev/squad-aegis/internal/models"
+	"go.codycody31.dev/squad-aegis/internal/shared/config"
+)
+
+// Built-in sandbox limits, used when no configuration is loaded.
+const (
+	defaultLuaMaxInstructions   = 100_000_000
+	defaultLuaMaxMemoryMB       = 64
+	defaultLuaTimeoutSeconds    = 30
+	defaultLuaMaxTimeoutSeconds = 300
+
+	// luaRegisterCheckInterval is how often (in instructions) the strings held
+	// in the running function's registers are measured. Concatenation can
+	// double a string every few instructions, so this check has to be frequent.
+	luaRegisterCheckInterval = 8
+	// luaMemoryCheckInterval is the minimum number of instructions between
+	// full walks of the Lua heap.
+	luaMemoryCheckInterval = 4096
+
+	luaCallStackSize   = 200
+	luaRegistrySize    = 1024 * 20
+	luaRegistryMaxSize = 1024 * 256
+)
+
+var (
+	errLuaInstructionLimit = errors.New("instruction limit exceeded")
+	errLuaMemoryLimit      = errors.New("memory limit exceeded")
+)
+
+// luaLimits bounds the resources a single Lua script may use.
+type luaLimits struct {
+	MaxInstructions int64
+	MaxMemoryBytes  int64
+	Timeout         time.Duration
+}
+
+// resolveLuaLimits combines the server-wide limits with the workflow's
+// lua_limits and the step's timeout_seconds. Workflows may lower the
+// server-wide limits but never raise them.
+func resolveLuaLimits(definition *models.WorkflowDefinition, step *models.WorkflowStep) luaLimits {
+	maxInstructions := int64(defaultLuaMaxInstructions)
+	maxMemoryMB := int64(defaultLuaMaxMemoryMB)
+	timeoutSeconds := defaultLuaTimeoutSeconds
+	maxTimeoutSeconds := defaultLuaMaxTimeoutSeconds
+
+	if config.Config != nil {
+		cfg := config.Config.Workflows.Lua
+		if cfg.MaxInstructions > 0 {
+			maxInstructions = cfg.MaxInstructions
+		}
+		if cfg.MaxMemoryMB > 0 {
+			maxMemoryMB = cfg.MaxMemoryMB
+		}
+		if cfg.MaxTimeoutSeconds > 0 {
+			maxTimeoutSeconds = cfg.MaxTimeoutSeconds
+		}
+		if cfg.TimeoutSeconds > 0 {
+			timeoutSeconds = cfg.TimeoutSeconds
+		}
+	}
+
+	if definition != nil && definition.LuaLimits != nil {
+		overrides := definition.LuaLimits
+		if overrides.MaxInstructions > 0 && overrides.MaxInstructions < maxInstructions {
+			maxInstructions = overrides.MaxInstructions
+		}
+		if overrides.MaxMemoryMB > 0 && overrides.MaxMemoryMB < maxMemoryMB {
+			maxMemoryMB = overrides.MaxMemoryMB
+		}
+		if overrides.TimeoutSeconds > 0 && overrides.TimeoutSeconds < timeoutSeconds {
+			timeoutSeconds = overrides.TimeoutSeconds
+		}
+	}
+
+	if step != nil {
+		if timeoutConfig, ok := step.Config["timeout_seconds"].(float64); ok && timeoutConfig > 0 {
+			timeoutSeconds = int(timeoutConfig)
+		}
+	}
+
+	if timeoutSeconds > maxTimeoutSeconds {
+		timeoutSeconds = maxTimeoutSeconds
+	}
+
+	return luaLimits{
+		MaxInstructions: maxInstructions,
+		MaxMemoryBytes:  maxMemoryMB * 1024 * 1024,
+		Timeout:         time.Duration(timeoutSeconds) * time.Second,
+	}
+}
+
+// luaBudget is the context handed to the Lua VM. gopher-lua checks Done() of
+// its context before every instruction, which lets the budget count
+// instructions and sample memory use, and abort the script by cancelling
+// itself once a limit is crossed.
+type luaBudget struct {
+	context.Context
+	cancel context.CancelCauseFunc
+
+	L               *lua.LState
+	limits          luaLimits
+	instructions    int64
+	nextMemoryCheck int64
+}
+
+func newLuaBudget(parent context.Context, L *lua.LState, limits luaLimits) *luaBudget {
+	ctx, cancel := context.WithCancelCause(parent)
+	return &luaBudget{
+		Context:         ctx,
+		cancel:          cancel,
+		L:               L,
+		limits:          limits,
+		nextMemoryCheck: luaMemoryCheckInterval,
+	}
+}
+
+func (b *luaBudget) Done() <-chan struct{} {
+	b.instructions++
+
+	if b.limits.MaxInstructions > 0 && b.instructions > b.limits.MaxInstructions {
+		b.cancel(errLuaInstructionLimit)
+	} else if b.limits.MaxMemoryBytes > 0 {
+		if b.instructions >= b.nextMemoryCheck {
+			used, objects := luaHeapSize(b.L)
+			if used > b.limits.MaxMemoryBytes {
+				b.cancel(errLuaMemoryLimit)
+			}
+			// Space full walks out in proportion to the heap so their cost
+			// stays a fraction of the script's own work.
+			b.nextMemoryCheck = b.instructions + max(luaMemoryCheckInterval, 4*objects)
+		} else if b.instructions%luaRegisterCheckInterval == 0 && luaRegisterSize(b.L) > b.limits.MaxMemoryBytes {
+			b.cancel(errLuaMemoryLimit)
+		}
+	}
+
+	return b.Context.Done()
+}
+
+// Err reports the limit that stopped the script, if any. gopher-lua raises
+// it as the script's error message.
+func (b *luaBudget) Err() error {
+	if b.Context.Err() == nil {
+		return nil
+	}
+	return context.Cause(b.Context)
+}
+
+// Violation returns the limit the script crossed, or nil.
+func (b *luaBudget) Violation() error {
+	cause := context.Cause(b.Context)
+	if errors.Is(cause, errLuaInstructionLimit) || errors.Is(cause, errLuaMemoryLimit) {
+		return cause
+	}
+	return nil
+}
+
+// luaRegisterSize returns the combined length of the strings held in the
+// running function's registers.
+func luaRegisterSize(L *lua.LState) int64 {
+	var size int64
+	for i := 1; i <= L.GetTop(); i++ {
+		if str, ok := L.Get(i).(lua.LString); ok {
+			size += int64(len(str))
+		}
+	}
+	return size
+}
+
+// luaHeapSize estimates the memory reachable from the globals, the registry
+// and the locals of every active call frame. It returns the estimated size
+// in bytes and the number of objects visited.
+func luaHeapSize(L *lua.LState) (int64, int64) {
+	walker := luaHeapWalker{seen: make(map[lua.LValue]struct{})}
+	walker.walk(L.G.Global)
+	walker.walk(L.G.Registry)
+
+	for level := 0; ; level++ {
+		frame, ok := L.GetStack(level)
+		if !ok {
+			break
+		}
+		for n := 1; ; n++ {
+			name, value := L.GetLocal(frame, n)
+			if name == "" {
+				break
+			}
+			walker.walk(value)
+		}
+	}
+	walker.size += luaRegisterSize(L)
+
+	return walker.size, walker.objects
+}
+
+type luaHeapWalker struct {
+	seen    map[lua.LValue]struct{}
+	size    int64
+	objects int64
+}
+
+// Approximate per-object overheads of the gopher-lua representations.
+const (
+	luaValueSize    = 16
+	luaStringSize   = 32
+	luaTableSize    = 96
+	luaFunctionSize = 128
+)
+
+func (w *luaHeapWalker) walk(value lua.LValue) {
+	switch v := value.(type) {
+	case lua.LString:
+		w.objects++
+		w.size += luaStringSize + int64(len(v))
+	case *lua.LTable:
+		if w.visit(v) {
+			return
+		}
+		w.size += luaTableSize
+		if v.Metatable != nil {
+			w.walk(v.Metatable)
+		}
+		v.ForEach(func(key, val lua.LValue) {
+			w.size += 2 * luaValueSize
+			w.walk(key)
+			w.walk(val)
+		})
+	case *lua.LFunction:
+		if w.visit(v) {
+			return
+		}
+		w.size += luaFunctionSize
+		if v.Env != nil {
+			w.walk(v.Env)
+		}
+		for _, upvalue := range v.Upvalues {
+			if upvalue != nil {
+				w.size += luaValueSize
+				w.walk(upvalue.Value())
+			}
+		}
+	case *lua.LUserData:
+		if w.visit(v) {
+			return
+		}
+		w.size += luaTableSize
+		if v.Metatable != nil {
+			w.walk(v.Metatable)
+		}
+	}
+}
+
+// visit marks an object as seen and reports whether it already was.
+func (w *luaHeapWalker) visit(value lua.LValue) bool {
+	if _, ok := w.seen[value]; ok {
+		return true
+	}
+	w.seen[value] = struct{}{}
+	w.objects++
+	return false
+}
+
+// luaSandboxLibs are the standard libraries scripts may use. io, debug,
+// package, channel and coroutine are never opened.
+var luaSandboxLibs = []struct {
+	name string
+	open lua.LGFunction
+}{
+	{lua.BaseLibName, lua.OpenBase},
+	{lua.TabLibName, lua.OpenTable},
+	{lua.StringLibName, lua.OpenString},
+	{lua.MathLibName, lua.OpenMath},
+	{lua.OsLibName, lua.OpenOs},
+}
+
+// luaBlockedGlobals are base library functions that load code from strings
+// or files, or pull in modules.
+var luaBlockedGlobals = []string{"dofile", "loadfile", "load", "loadstring", "require", "module", "newproxy"}
+
+// luaAllowedOsFuncs are the os functions left in place; everything that
+// touches the process, environment or filesystem is removed.
+var luaAllowedOsFuncs = []string{"clock", "date", "difftime", "time"}
+
+// newLuaSandbox creates a Lua state with only whitelisted libraries, bounded
+// call stack and registry sizes, and a budget that aborts the script once it
+// crosses limits or ctx is done.
+func newLuaSandbox(ctx context.Context, limits luaLimits) (*lua.LState, *luaBudget) {
+	L := lua.NewState(lua.Options{
+		SkipOpenLibs:        true,
+		CallStackSize:       luaCallStackSize,
+		RegistrySize:        luaRegistrySize,
+		RegistryMaxSize:     luaRegistryMaxSize,
+		MinimizeStackMemory: true,
+	})
+
+	for _, lib := range luaSandboxLibs {
+		L.Push(L.NewFunction(lib.open))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+
+	for _, name := range luaBlockedGlobals {
+		L.SetGlobal(name, lua.LNil)
+	}
+
+	if osTable, ok := L.GetGlobal(lua.OsLibName).(*lua.LTable); ok {
+		safeOs := L.NewTable()
+		for _, name := range luaAllowedOsFuncs {
+			safeOs.RawSetString(name, osTable.RawGetString(name))
+		}
+		L.SetGlobal(lua.OsLibName, safeOs)
+	}
+
+	budget := newLuaBudget(ctx, L, limits)
+
+	// string.rep can allocate far more than the memory limit in a single
+	// instruction, before the budget gets a chance to look.
+	if stringTable, ok := L.GetGlobal(lua.StringLibName).(*lua.LTable); ok {
+		if rep, ok := stringTable.RawGetString("rep").(*lua.LFunction); ok {
+			stringTable.RawSetString("rep", L.NewFunction(func(L *lua.LState) int {
+				size := int64(len(L.CheckString(1))) * int64(L.CheckInt(2))
+				if limits.MaxMemoryBytes > 0 && size > limits.MaxMemoryBytes {
+					budget.cancel(errLuaMemoryLimit)
+					L.RaiseError("%s", errLuaMemoryLimit.Error())
+				}
+				return rep.GFunction(L)
+			}))
+		}
+	}
+
+	L.SetContext(budget)
+	return L, budget
+}
+
+// luaViolationMessage describes why a sandboxed script was stopped.
+func luaViolationMessage(violation error, limits luaLimits) string {
+	switch {
+	case errors.Is(violation, errLuaInstructionLimit):
+		return fmt.Sprintf("LUA script aborted: exceeded the limit of %d instructions", limits.MaxInstructions)
+	case errors.Is(violation, errLuaMemoryLimit):
+		return fmt.Sprintf("LUA script aborted: exceeded the memory limit of %d MB", limits.MaxMemoryBytes/(1024*1024))
+	default:
+		return fmt.Sprintf("LUA script aborted: %v", violation)
+	}
+}
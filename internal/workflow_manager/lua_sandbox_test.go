@@ -0,0 +1,144 @@
+package workflow_manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+func testLuaLimits() luaLimits {
+	return luaLimits{
+		MaxInstructions: 1_000_000,
+		MaxMemoryBytes:  8 * 1024 * 1024,
+		Timeout:         10 * time.Second,
+	}
+}
+
+func TestLuaSandboxBlocksUnsafeLibraries(t *testing.T) {
+	L, _ := newLuaSandbox(context.Background(), testLuaLimits())
+	defer L.Close()
+
+	script := `
+		assert(io == nil, "io is available")
+		assert(debug == nil, "debug is available")
+		assert(package == nil, "package is available")
+		assert(require == nil, "require is available")
+		assert(load == nil and loadstring == nil, "load is available")
+		assert(dofile == nil and loadfile == nil, "dofile is available")
+		assert(os.execute == nil, "os.execute is available")
+		assert(os.getenv == nil, "os.getenv is available")
+		assert(os.exit == nil, "os.exit is available")
+		assert(os.remove == nil, "os.remove is available")
+		assert(type(os.time()) == "number", "os.time is missing")
+		assert(string.upper("a") == "A", "string is missing")
+		assert(table.concat({"a", "b"}) == "ab", "table is missing")
+		assert(math.floor(1.5) == 1, "math is missing")
+	`
+	if err := L.DoString(script); err != nil {
+		t.Fatalf("sandbox check failed: %v", err)
+	}
+}
+
+func TestLuaSandboxAbortsOnInstructionLimit(t *testing.T) {
+	L, budget := newLuaSandbox(context.Background(), testLuaLimits())
+	defer L.Close()
+
+	err := L.DoString(`while true do end`)
+	if err == nil {
+		t.Fatal("expected infinite loop to be aborted")
+	}
+	if !errors.Is(budget.Violation(), errLuaInstructionLimit) {
+		t.Fatalf("expected instruction limit violation, got %v", budget.Violation())
+	}
+}
+
+func TestLuaSandboxAbortsOnMemoryLimit(t *testing.T) {
+	scripts := map[string]string{
+		"table growth":    `local t = {} local i = 0 while true do i = i + 1 t[i] = "entry " .. i end`,
+		"string doubling": `local s = "x" while true do s = s .. s end`,
+		"string.rep":      `local s = string.rep("x", 1024 * 1024 * 1024)`,
+	}
+
+	for name, script := range scripts {
+		t.Run(name, func(t *testing.T) {
+			limits := testLuaLimits()
+			limits.MaxInstructions = 0
+			L, budget := newLuaSandbox(context.Background(), limits)
+			defer L.Close()
+
+			if err := L.DoString(script); err == nil {
+				t.Fatal("expected script to be aborted")
+			}
+			if !errors.Is(budget.Violation(), errLuaMemoryLimit) {
+				t.Fatalf("expected memory limit violation, got %v", budget.Violation())
+			}
+		})
+	}
+}
+
+func TestLuaSandboxAbortsOnTimeout(t *testing.T) {
+	limits := testLuaLimits()
+	limits.MaxInstructions = 0
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	L, budget := newLuaSandbox(ctx, limits)
+	defer L.Close()
+
+	done := make(chan error, 1)
+	go func() { done <- L.DoString(`while true do end`) }()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected script to be aborted")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("script kept running after its context was done")
+	}
+	if budget.Violation() != nil {
+		t.Fatalf("timeout should not be reported as a limit violation, got %v", budget.Violation())
+	}
+}
+
+func TestResolveLuaLimits(t *testing.T) {
+	definition := &models.WorkflowDefinition{
+		LuaLimits: &models.WorkflowLuaLimits{
+			MaxInstructions: 1000,
+			MaxMemoryMB:     1024,
+			TimeoutSeconds:  5,
+		},
+	}
+
+	limits := resolveLuaLimits(definition, &models.WorkflowStep{Config: map[string]interface{}{}})
+	if limits.MaxInstructions != 1000 {
+		t.Errorf("expected workflow to lower instruction limit, got %d", limits.MaxInstructions)
+	}
+	if limits.MaxMemoryBytes != defaultLuaMaxMemoryMB*1024*1024 {
+		t.Errorf("expected workflow not to raise memory limit, got %d", limits.MaxMemoryBytes)
+	}
+	if limits.Timeout != 5*time.Second {
+		t.Errorf("expected workflow timeout, got %v", limits.Timeout)
+	}
+
+	step := &models.WorkflowStep{Config: map[string]interface{}{"timeout_seconds": float64(100000)}}
+	limits = resolveLuaLimits(definition, step)
+	if limits.Timeout != defaultLuaMaxTimeoutSeconds*time.Second {
+		t.Errorf("expected step timeout to be capped, got %v", limits.Timeout)
+	}
+
+	raised := &models.WorkflowDefinition{LuaLimits: &models.WorkflowLuaLimits{TimeoutSeconds: defaultLuaTimeoutSeconds + 60}}
+	limits = resolveLuaLimits(raised, nil)
+	if limits.Timeout != defaultLuaTimeoutSeconds*time.Second {
+		t.Errorf("expected workflow not to raise timeout, got %v", limits.Timeout)
+	}
+
+	limits = resolveLuaLimits(nil, nil)
+	if limits.MaxInstructions != defaultLuaMaxInstructions || limits.Timeout != defaultLuaTimeoutSeconds*time.Second {
+		t.Errorf("unexpected default limits: %+v", limits)
+	}
+}
@@ -0,0 +1,365 @@
+package workflow_manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+var (
+	bundleParamPattern     = regexp.MustCompile(`\{\{param:([a-z][a-z0-9_]*)\}\}`)
+	bundleParamNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	bundleParamNameCleaner = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// serverSpecificStepKeys are step config keys whose values only make sense
+// on the server they were configured on. Exports turn them into parameters.
+var serverSpecificStepKeys = map[string]string{
+	"rule_id":     "Server rule ID",
+	"webhook_url": "Webhook URL",
+	"channel_id":  "Discord channel ID",
+	"workflow_id": "Called workflow ID",
+}
+
+// ExportWorkflowBundle builds a portable bundle from a workflow. kvSeed may
+// be nil to leave the KV store out.
+func ExportWorkflowBundle(workflow *models.ServerWorkflow, kvSeed map[string]interface{}) (*models.WorkflowBundle, error) {
+	bundle := &models.WorkflowBundle{
+		Format:        models.WorkflowBundleFormat,
+		FormatVersion: models.WorkflowBundleFormatVersion,
+		ExportedAt:    time.Now().UTC(),
+		Name:          workflow.Name,
+		Description:   workflow.Description,
+		KVSeed:        kvSeed,
+	}
+
+	for _, variable := range workflow.Variables {
+		bundle.Variables = append(bundle.Variables, models.WorkflowBundleVariable{
+			Name:        variable.Name,
+			Value:       variable.Value,
+			Description: variable.Description,
+		})
+	}
+
+	var definition interface{}
+	if err := roundTripJSON(workflow.Definition, &definition); err != nil {
+		return nil, err
+	}
+
+	// The same value used in several steps becomes a single parameter
+	byValue := map[string]string{}
+	definition = parameterizeValue(definition, "", "", byValue, &bundle.Parameters)
+
+	if err := roundTripJSON(definition, &bundle.Definition); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// parameterizeValue replaces server-specific values below value with
+// placeholders. owner is the ID of the nearest enclosing step or trigger.
+func parameterizeValue(value interface{}, key, owner string, byValue map[string]string, params *[]models.WorkflowBundleParameter) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if id, ok := v["id"].(string); ok && id != "" {
+			owner = id
+		}
+		for k, child := range v {
+			v[k] = parameterizeValue(child, k, owner, byValue, params)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = parameterizeValue(child, key, owner, byValue, params)
+		}
+		return v
+	}
+
+	label, ok := serverSpecificStepKeys[key]
+	if !ok || value == nil || value == "" {
+		return value
+	}
+
+	paramType := models.WorkflowBundleParamString
+	if _, isNumber := value.(float64); isNumber {
+		paramType = models.WorkflowBundleParamNumber
+	}
+
+	valueKey := key + "\x00" + fmt.Sprint(value)
+	name, seen := byValue[valueKey]
+	if !seen {
+		name = uniqueParamName(bundleParamNameCleaner.ReplaceAllString(strings.ToLower(owner+"_"+key), "_"), *params)
+		byValue[valueKey] = name
+		*params = append(*params, models.WorkflowBundleParameter{
+			Name:     name,
+			Label:    label,
+			Type:     paramType,
+			Required: true,
+		})
+	}
+
+	return "{{param:" + name + "}}"
+}
+
+func uniqueParamName(base string, params []models.WorkflowBundleParameter) string {
+	base = strings.Trim(base, "_")
+	if base == "" || !bundleParamNamePattern.MatchString(base) {
+		base = "param_" + base
+	}
+
+	name := base
+	for i := 2; ; i++ {
+		taken := false
+		for _, param := range params {
+			if param.Name == name {
+				taken = true
+				break
+			}
+		}
+		if !taken {
+			return name
+		}
+		name = base + "_" + strconv.Itoa(i)
+	}
+}
+
+// ValidateWorkflowBundle checks a bundle's format and parameter declarations
+// and that every placeholder it uses is declared.
+func ValidateWorkflowBundle(bundle *models.WorkflowBundle) error {
+	if bundle.Format != models.WorkflowBundleFormat {
+		return fmt.Errorf("not a workflow bundle: format must be %q", models.WorkflowBundleFormat)
+	}
+	if bundle.FormatVersion < 1 || bundle.FormatVersion > models.WorkflowBundleFormatVersion {
+		return fmt.Errorf("unsupported bundle format version %d", bundle.FormatVersion)
+	}
+	if strings.TrimSpace(bundle.Name) == "" {
+		return fmt.Errorf("bundle has no workflow name")
+	}
+
+	declared := map[string]bool{}
+	for _, param := range bundle.Parameters {
+		if !bundleParamNamePattern.MatchString(param.Name) {
+			return fmt.Errorf("invalid parameter name %q", param.Name)
+		}
+		if declared[param.Name] {
+			return fmt.Errorf("parameter %q is declared more than once", param.Name)
+		}
+		switch param.Type {
+		case models.WorkflowBundleParamString, models.WorkflowBundleParamNumber, models.WorkflowBundleParamBoolean:
+		default:
+			return fmt.Errorf("parameter %q has unsupported type %q", param.Name, param.Type)
+		}
+		if param.Default != nil {
+			if err := checkBundleParamType(param, param.Default); err != nil {
+				return fmt.Errorf("default for %w", err)
+			}
+		}
+		declared[param.Name] = true
+	}
+
+	raw, err := json.Marshal([]interface{}{bundle.Definition, bundle.Variables, bundle.KVSeed})
+	if err != nil {
+		return err
+	}
+	for _, match := range bundleParamPattern.FindAllStringSubmatch(string(raw), -1) {
+		if !declared[match[1]] {
+			return fmt.Errorf("placeholder {{param:%s}} has no matching parameter", match[1])
+		}
+	}
+
+	return nil
+}
+
+// ResolveWorkflowBundle validates values against the bundle's parameters and
+// returns a copy of the bundle with every placeholder replaced. A placeholder
+// that makes up a whole string takes the parameter's type; one inside a
+// longer string is formatted into it.
+func ResolveWorkflowBundle(bundle *models.WorkflowBundle, values map[string]interface{}) (*models.WorkflowBundle, error) {
+	if err := ValidateWorkflowBundle(bundle); err != nil {
+		return nil, err
+	}
+
+	resolved := map[string]interface{}{}
+	for _, param := range bundle.Parameters {
+		value, ok := values[param.Name]
+		if !ok || value == nil || value == "" {
+			value = param.Default
+		}
+		if value == nil {
+			if param.Required {
+				return nil, fmt.Errorf("parameter %q is required", param.Name)
+			}
+			value = ""
+		} else if err := checkBundleParamType(param, value); err != nil {
+			return nil, err
+		}
+		resolved[param.Name] = value
+	}
+
+	var out models.WorkflowBundle
+	if err := roundTripJSON(bundle, &out); err != nil {
+		return nil, err
+	}
+
+	var definition interface{}
+	if err := roundTripJSON(out.Definition, &definition); err != nil {
+		return nil, err
+	}
+	if err := roundTripJSON(substituteBundleParams(definition, resolved), &out.Definition); err != nil {
+		return nil, fmt.Errorf("definition is invalid after filling in parameters: %w", err)
+	}
+
+	for i := range out.Variables {
+		out.Variables[i].Value, _ = substituteBundleParams(out.Variables[i].Value, resolved).(map[string]interface{})
+	}
+	if out.KVSeed != nil {
+		out.KVSeed, _ = substituteBundleParams(out.KVSeed, resolved).(map[string]interface{})
+	}
+
+	return &out, nil
+}
+
+func checkBundleParamType(param models.WorkflowBundleParameter, value interface{}) error {
+	ok := false
+	switch param.Type {
+	case models.WorkflowBundleParamString:
+		_, ok = value.(string)
+	case models.WorkflowBundleParamNumber:
+		_, ok = value.(float64)
+	case models.WorkflowBundleParamBoolean:
+		_, ok = value.(bool)
+	}
+	if !ok {
+		return fmt.Errorf("parameter %q must be a %s", param.Name, param.Type)
+	}
+	return nil
+}
+
+func substituteBundleParams(value interface{}, values map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			v[k] = substituteBundleParams(child, values)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = substituteBundleParams(child, values)
+		}
+		return v
+	case string:
+		if match := bundleParamPattern.FindStringSubmatch(v); match != nil && match[0] == v {
+			return values[match[1]]
+		}
+		return bundleParamPattern.ReplaceAllStringFunc(v, func(placeholder string) string {
+			return fmt.Sprint(values[bundleParamPattern.FindStringSubmatch(placeholder)[1]])
+		})
+	}
+	return value
+}
+
+// workflowStepTypes are the step types executeStep runs
+var workflowStepTypes = map[string]bool{
+	models.StepTypeAction:    true,
+	models.StepTypeCondition: true,
+	models.StepTypeVariable:  true,
+	models.StepTypeDelay:     true,
+	models.StepTypeWait:      true,
+}
+
+// workflowActionTypes are the action types executeActionStep runs
+var workflowActionTypes = map[string]bool{
+	models.ActionTypeRconCommand:           true,
+	models.ActionTypeAdminBroadcast:        true,
+	models.ActionTypeChatMessage:           true,
+	models.ActionTypeKickPlayer:            true,
+	models.ActionTypeBanPlayer:             true,
+	models.ActionTypeBanPlayerWithEvidence: true,
+	models.ActionTypeWarnPlayer:            true,
+	models.ActionTypeHTTPRequest:           true,
+	models.ActionTypeWebhook:               true,
+	models.ActionTypeDiscordMessage:        true,
+	models.ActionTypeLogMessage:            true,
+	models.ActionTypeSetVariable:           true,
+	models.ActionTypeLuaScript:             true,
+	models.ActionTypeCallWorkflow:          true,
+}
+
+// ValidateWorkflowDefinition checks that a definition only uses step and
+// action types this version can run and that step references resolve.
+func ValidateWorkflowDefinition(definition *models.WorkflowDefinition) error {
+	if strings.TrimSpace(definition.Version) == "" {
+		return fmt.Errorf("definition has no version")
+	}
+
+	triggerIDs := map[string]bool{}
+	for i, trigger := range definition.Triggers {
+		if trigger.ID == "" {
+			return fmt.Errorf("trigger %d has no id", i+1)
+		}
+		if triggerIDs[trigger.ID] {
+			return fmt.Errorf("trigger id %q is used more than once", trigger.ID)
+		}
+		if trigger.EventType == "" {
+			return fmt.Errorf("trigger %q has no event type", trigger.ID)
+		}
+		triggerIDs[trigger.ID] = true
+	}
+
+	stepIDs := map[string]bool{}
+	for i, step := range definition.Steps {
+		if step.ID == "" {
+			return fmt.Errorf("step %d has no id", i+1)
+		}
+		if stepIDs[step.ID] {
+			return fmt.Errorf("step id %q is used more than once", step.ID)
+		}
+		stepIDs[step.ID] = true
+	}
+
+	for _, step := range definition.Steps {
+		if !workflowStepTypes[step.Type] {
+			return fmt.Errorf("step %q has unsupported type %q", step.ID, step.Type)
+		}
+		if step.Type == models.StepTypeAction {
+			actionType, _ := step.Config["action_type"].(string)
+			if !workflowActionTypes[actionType] {
+				return fmt.Errorf("step %q has unsupported action type %q", step.ID, actionType)
+			}
+		}
+
+		var refs []string
+		refs = append(refs, step.NextSteps...)
+		if step.OnError != nil && step.OnError.GotoStep != "" {
+			refs = append(refs, step.OnError.GotoStep)
+		}
+		if gotoStep, _ := step.Config["timeout_goto_step"].(string); step.Type == models.StepTypeWait && gotoStep != "" {
+			refs = append(refs, gotoStep)
+		}
+		for _, ref := range refs {
+			if !stepIDs[ref] {
+				return fmt.Errorf("step %q refers to unknown step %q", step.ID, ref)
+			}
+		}
+	}
+
+	if err := ValidateWorkflowConcurrency(definition.Concurrency); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func roundTripJSON(in, out interface{}) error {
+	raw, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
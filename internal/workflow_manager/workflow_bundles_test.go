@@ -0,0 +1,150 @@
+package workflow_manager
+
+import (
+	"strings"
+	"testing"
+
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+func bundleTestWorkflow() *models.ServerWorkflow {
+	return &models.ServerWorkflow{
+		Name: "Teamkill bans",
+		Definition: models.WorkflowDefinition{
+			Version:  "1.0",
+			Triggers: []models.WorkflowTrigger{{ID: "tk", Name: "Teamkill", EventType: "LOG_PLAYER_DIED", Enabled: true}},
+			Steps: []models.WorkflowStep{
+				{ID: "ban", Name: "Ban", Type: "action", Enabled: true, Config: map[string]interface{}{
+					"action_type": "ban_player",
+					"rule_id":     "6c1f2d0e-8a8c-4d7e-9a55-3f0f8e1f2b11",
+				}},
+				{ID: "notify", Name: "Notify", Type: "action", Enabled: true, Config: map[string]interface{}{
+					"action_type": "discord_message",
+					"webhook_url": "https://discord.com/api/webhooks/1/abc",
+				}},
+				{ID: "notify_again", Name: "Notify again", Type: "action", Enabled: true, Config: map[string]interface{}{
+					"action_type": "discord_message",
+					"webhook_url": "https://discord.com/api/webhooks/1/abc",
+				}},
+			},
+		},
+		Variables: []models.ServerWorkflowVariable{
+			{Name: "limits", Value: map[string]interface{}{"max_teamkills": float64(3)}},
+		},
+	}
+}
+
+func TestExportWorkflowBundle(t *testing.T) {
+	bundle, err := ExportWorkflowBundle(bundleTestWorkflow(), map[string]interface{}{"count": float64(1)})
+	if err != nil {
+		t.Fatalf("ExportWorkflowBundle failed: %v", err)
+	}
+
+	if bundle.Format != models.WorkflowBundleFormat || bundle.FormatVersion != models.WorkflowBundleFormatVersion {
+		t.Fatalf("unexpected format %q v%d", bundle.Format, bundle.FormatVersion)
+	}
+	if len(bundle.Parameters) != 2 {
+		t.Fatalf("expected a rule and a shared webhook parameter, got %+v", bundle.Parameters)
+	}
+
+	steps := bundle.Definition.Steps
+	if steps[0].Config["rule_id"] != "{{param:ban_rule_id}}" {
+		t.Errorf("rule_id not parameterized: %v", steps[0].Config["rule_id"])
+	}
+	if steps[1].Config["webhook_url"] != steps[2].Config["webhook_url"] {
+		t.Errorf("identical webhook URLs should share a parameter: %v, %v", steps[1].Config["webhook_url"], steps[2].Config["webhook_url"])
+	}
+	if len(bundle.Variables) != 1 || bundle.KVSeed["count"] != float64(1) {
+		t.Errorf("variables or KV seed missing: %+v %+v", bundle.Variables, bundle.KVSeed)
+	}
+
+	if err := ValidateWorkflowBundle(bundle); err != nil {
+		t.Fatalf("exported bundle does not validate: %v", err)
+	}
+}
+
+func TestResolveWorkflowBundle(t *testing.T) {
+	bundle, err := ExportWorkflowBundle(bundleTestWorkflow(), nil)
+	if err != nil {
+		t.Fatalf("ExportWorkflowBundle failed: %v", err)
+	}
+	bundle.Parameters = append(bundle.Parameters, models.WorkflowBundleParameter{
+		Name: "max_teamkills", Type: models.WorkflowBundleParamNumber, Default: float64(5),
+	})
+	bundle.Variables[0].Value["max_teamkills"] = "{{param:max_teamkills}}"
+	bundle.Definition.Steps[0].Config["reason"] = "Teamkilling ({{param:max_teamkills}} max)"
+
+	if _, err := ResolveWorkflowBundle(bundle, map[string]interface{}{"ban_rule_id": "rule-2"}); err == nil || !strings.Contains(err.Error(), "required") {
+		t.Fatalf("expected a missing parameter error, got %v", err)
+	}
+	if _, err := ResolveWorkflowBundle(bundle, map[string]interface{}{"ban_rule_id": float64(2), "notify_webhook_url": "x"}); err == nil {
+		t.Fatal("expected a type error for a numeric rule ID")
+	}
+
+	resolved, err := ResolveWorkflowBundle(bundle, map[string]interface{}{
+		"ban_rule_id":        "rule-2",
+		"notify_webhook_url": "https://example.com/hook",
+	})
+	if err != nil {
+		t.Fatalf("ResolveWorkflowBundle failed: %v", err)
+	}
+
+	steps := resolved.Definition.Steps
+	if steps[0].Config["rule_id"] != "rule-2" || steps[2].Config["webhook_url"] != "https://example.com/hook" {
+		t.Errorf("parameters not substituted: %+v", steps)
+	}
+	if steps[0].Config["reason"] != "Teamkilling (5 max)" {
+		t.Errorf("embedded placeholder not formatted: %v", steps[0].Config["reason"])
+	}
+	if resolved.Variables[0].Value["max_teamkills"] != float64(5) {
+		t.Errorf("whole-string placeholder should keep the number type, got %#v", resolved.Variables[0].Value["max_teamkills"])
+	}
+	if bundle.Definition.Steps[0].Config["rule_id"] != "{{param:ban_rule_id}}" {
+		t.Error("resolving modified the original bundle")
+	}
+}
+
+func TestValidateWorkflowBundle(t *testing.T) {
+	bundle, _ := ExportWorkflowBundle(bundleTestWorkflow(), nil)
+
+	bundle.Definition.Steps[0].Config["message"] = "{{param:undeclared}}"
+	if err := ValidateWorkflowBundle(bundle); err == nil {
+		t.Error("expected an error for an undeclared placeholder")
+	}
+
+	bundle.Format = "something-else"
+	if err := ValidateWorkflowBundle(bundle); err == nil {
+		t.Error("expected an error for a foreign format")
+	}
+}
+
+func TestValidateWorkflowDefinition(t *testing.T) {
+	valid := bundleTestWorkflow().Definition
+	if err := ValidateWorkflowDefinition(&valid); err != nil {
+		t.Fatalf("valid definition rejected: %v", err)
+	}
+
+	tests := map[string]func(d *models.WorkflowDefinition){
+		"duplicate step id":   func(d *models.WorkflowDefinition) { d.Steps[1].ID = "ban" },
+		"unknown step type":   func(d *models.WorkflowDefinition) { d.Steps[0].Type = "teleport" },
+		"unknown action type": func(d *models.WorkflowDefinition) { d.Steps[0].Config["action_type"] = "teleport" },
+		"dangling next step":  func(d *models.WorkflowDefinition) { d.Steps[0].NextSteps = []string{"missing"} },
+		"dangling timeout goto": func(d *models.WorkflowDefinition) {
+			d.Steps = append(d.Steps, models.WorkflowStep{ID: "wait", Type: models.StepTypeWait, Config: map[string]interface{}{
+				"event_type":        "RCON_CHAT_MESSAGE",
+				"on_timeout":        "goto",
+				"timeout_goto_step": "missing",
+			}})
+		},
+		"trigger without event": func(d *models.WorkflowDefinition) {
+			d.Triggers[0].EventType = ""
+		},
+	}
+	for name, mutate := range tests {
+		definition := bundleTestWorkflow().Definition
+		mutate(&definition)
+		if err := ValidateWorkflowDefinition(&definition); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
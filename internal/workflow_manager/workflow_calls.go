@@ -0,0 +1,260 @@
+package workflow_manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+// maxWorkflowCallDepth bounds how deeply call_workflow steps can nest
+const maxWorkflowCallDepth = 5
+
+// workflowCall describes a call_workflow invocation
+type workflowCall struct {
+	depth             int
+	stack             []interface{} // IDs of the calling workflows, outermost first
+	parentExecutionID uuid.UUID
+	inputs            map[string]interface{}
+}
+
+// executeCallWorkflowAction runs another workflow on the same server. In sync
+// mode (the default) the called workflow's variables are returned as outputs
+// and its failure fails this step, unless it suspends at a wait_for_event
+// step; async mode only starts it.
+func (wm *WorkflowManager) executeCallWorkflowAction(context *models.WorkflowExecutionContext, step *models.WorkflowStep) error {
+	workflowIDStr, _ := step.Config["workflow_id"].(string)
+	workflowID, err := uuid.Parse(workflowIDStr)
+	if err != nil {
+		return fmt.Errorf("missing or invalid workflow_id in call_workflow config")
+	}
+
+	mode := "sync"
+	if m, ok := step.Config["mode"].(string); ok && m != "" {
+		mode = m
+	}
+	if mode != "sync" && mode != "async" {
+		return fmt.Errorf("invalid call_workflow mode %q, expected sync or async", mode)
+	}
+
+	depth := 0
+	switch d := context.Metadata["call_depth"].(type) {
+	case int:
+		depth = d
+	case float64:
+		depth = int(d)
+	}
+	if depth >= maxWorkflowCallDepth {
+		return fmt.Errorf("workflow calls are nested more than %d levels deep", maxWorkflowCallDepth)
+	}
+
+	callerStack, _ := context.Metadata["call_stack"].([]interface{})
+	stack := make([]interface{}, 0, len(callerStack)+1)
+	stack = append(stack, callerStack...)
+	stack = append(stack, context.WorkflowID.String())
+	for _, id := range stack {
+		if id == workflowID.String() {
+			return fmt.Errorf("workflow %s is already running further up this call chain", workflowID)
+		}
+	}
+
+	wm.mutex.RLock()
+	callee := wm.activeWorkflows[workflowID]
+	wm.mutex.RUnlock()
+	if callee == nil || callee.ServerID != context.ServerID {
+		return fmt.Errorf("workflow %s is not an enabled workflow on this server", workflowID)
+	}
+
+	// The editor stores inputs as JSON text
+	configured, ok := step.Config["inputs"].(map[string]interface{})
+	if raw, isString := step.Config["inputs"].(string); isString && strings.TrimSpace(raw) != "" {
+		if err := json.Unmarshal([]byte(raw), &configured); err != nil {
+			return fmt.Errorf("call_workflow inputs must be a JSON object: %w", err)
+		}
+		ok = true
+	}
+
+	inputs := map[string]interface{}{}
+	if ok {
+		for key, value := range configured {
+			if str, ok := value.(string); ok {
+				value = wm.replaceVariablesWithContext(str, context.Variables, context.TriggerEvent, context.Metadata)
+			}
+			inputs[key] = value
+		}
+	}
+
+	// The called workflow sees the caller's trigger event, so actions such as
+	// ban_player_with_evidence behave as they would in the caller.
+	triggerEvent := make(map[string]interface{}, len(context.TriggerEvent)+2)
+	for key, value := range context.TriggerEvent {
+		triggerEvent[key] = value
+	}
+	triggerEvent["caller_workflow_id"] = context.WorkflowID.String()
+	triggerEvent["caller_execution_id"] = context.ExecutionID.String()
+
+	call := &workflowCall{
+		depth:             depth + 1,
+		stack:             stack,
+		parentExecutionID: context.ExecutionID,
+		inputs:            inputs,
+	}
+	executionID := uuid.New()
+
+	result := map[string]interface{}{
+		"workflow_id":   workflowID.String(),
+		"workflow_name": callee.Name,
+		"execution_id":  executionID.String(),
+		"mode":          mode,
+	}
+
+	if mode == "async" {
+		go wm.runWorkflow(executionID, callee, triggerEvent, call)
+		result["status"] = "started"
+		context.StepResults[step.ID] = result
+		return nil
+	}
+
+	calleeContext, err := wm.runWorkflow(executionID, callee, triggerEvent, call)
+	if isWorkflowWaiting(err) {
+		// The called workflow resumes on its own once its event arrives
+		result["status"] = "waiting"
+		context.StepResults[step.ID] = result
+		return nil
+	}
+
+	result["outputs"] = selectWorkflowOutputs(calleeContext.Variables, step.Config["output_variables"])
+	if err != nil {
+		result["status"] = "failed"
+		result["error"] = err.Error()
+		context.StepResults[step.ID] = result
+		return fmt.Errorf("called workflow %s failed: %w", callee.Name, err)
+	}
+
+	result["status"] = "completed"
+	context.StepResults[step.ID] = result
+	return nil
+}
+
+// selectWorkflowOutputs returns the called workflow's variables, limited to
+// names when it is a non-empty list or comma-separated string.
+func selectWorkflowOutputs(variables map[string]interface{}, names interface{}) map[string]interface{} {
+	outputs := map[string]interface{}{}
+
+	list, _ := names.([]interface{})
+	if str, ok := names.(string); ok {
+		for _, name := range strings.Split(str, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				list = append(list, name)
+			}
+		}
+	}
+	if len(list) == 0 {
+		for key, value := range variables {
+			outputs[key] = value
+		}
+		return outputs
+	}
+
+	for _, name := range list {
+		if key, ok := name.(string); ok {
+			if value, exists := variables[key]; exists {
+				outputs[key] = value
+			}
+		}
+	}
+	return outputs
+}
+
+// WorkflowCallTargets lists the workflow IDs a definition calls through
+// call_workflow steps, including steps nested in condition branches and
+// failure handlers.
+func WorkflowCallTargets(definition *models.WorkflowDefinition) []string {
+	var raw interface{}
+	if err := roundTripJSON(definition, &raw); err != nil {
+		return nil
+	}
+
+	var targets []string
+	seen := map[string]bool{}
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if v["action_type"] == models.ActionTypeCallWorkflow {
+				if id, ok := v["workflow_id"].(string); ok && id != "" && !seen[id] {
+					seen[id] = true
+					targets = append(targets, id)
+				}
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(raw)
+
+	return targets
+}
+
+// CheckWorkflowCalls verifies that workflow only calls workflows on its own
+// server and that saving it would not create a call cycle. serverWorkflows
+// are the server's saved workflows; workflow replaces its saved version.
+func CheckWorkflowCalls(workflow *models.ServerWorkflow, serverWorkflows []models.ServerWorkflow) error {
+	names := map[string]string{workflow.ID.String(): workflow.Name}
+	calls := map[string][]string{workflow.ID.String(): WorkflowCallTargets(&workflow.Definition)}
+	for i := range serverWorkflows {
+		other := &serverWorkflows[i]
+		if other.ID == workflow.ID {
+			continue
+		}
+		names[other.ID.String()] = other.Name
+		calls[other.ID.String()] = WorkflowCallTargets(&other.Definition)
+	}
+
+	for _, target := range calls[workflow.ID.String()] {
+		if _, ok := names[target]; !ok {
+			return fmt.Errorf("call_workflow target %s is not a workflow on this server", target)
+		}
+	}
+
+	// Depth-first search for a path from workflow back to itself
+	start := workflow.ID.String()
+	visited := map[string]bool{}
+	var path []string
+	var visit func(id string) bool
+	visit = func(id string) bool {
+		path = append(path, id)
+		for _, target := range calls[id] {
+			if target == start {
+				path = append(path, target)
+				return true
+			}
+			if visited[target] {
+				continue
+			}
+			visited[target] = true
+			if visit(target) {
+				return true
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+
+	if visit(start) {
+		chain := make([]string, len(path))
+		for i, id := range path {
+			chain[i] = names[id]
+		}
+		return fmt.Errorf("workflow calls would form a cycle: %s", strings.Join(chain, " → "))
+	}
+
+	return nil
+}
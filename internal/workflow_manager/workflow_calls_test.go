@@ -0,0 +1,131 @@
+package workflow_manager
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+func callingWorkflow(name string, targets ...uuid.UUID) models.ServerWorkflow {
+	workflow := models.ServerWorkflow{
+		ID:   uuid.New(),
+		Name: name,
+		Definition: models.WorkflowDefinition{
+			Version: "1.0",
+		},
+	}
+	for i, target := range targets {
+		workflow.Definition.Steps = append(workflow.Definition.Steps, models.WorkflowStep{
+			ID:      "call_" + string(rune('a'+i)),
+			Type:    models.StepTypeAction,
+			Enabled: true,
+			Config: map[string]interface{}{
+				"action_type": models.ActionTypeCallWorkflow,
+				"workflow_id": target.String(),
+			},
+		})
+	}
+	return workflow
+}
+
+func TestWorkflowCallTargetsNested(t *testing.T) {
+	target := uuid.New()
+	definition := models.WorkflowDefinition{
+		Version: "1.0",
+		Steps: []models.WorkflowStep{{
+			ID:   "check",
+			Type: models.StepTypeCondition,
+			Config: map[string]interface{}{
+				"true_steps": []interface{}{
+					map[string]interface{}{"action_type": models.ActionTypeCallWorkflow, "workflow_id": target.String()},
+				},
+			},
+		}},
+	}
+
+	targets := WorkflowCallTargets(&definition)
+	if len(targets) != 1 || targets[0] != target.String() {
+		t.Fatalf("expected the nested call target, got %v", targets)
+	}
+}
+
+func TestCheckWorkflowCalls(t *testing.T) {
+	shared := callingWorkflow("Shared ban routine")
+	caller := callingWorkflow("Teamkill bans", shared.ID)
+	server := []models.ServerWorkflow{shared, caller}
+
+	if err := CheckWorkflowCalls(&caller, server); err != nil {
+		t.Fatalf("acyclic calls rejected: %v", err)
+	}
+
+	// Saving the shared routine with a call back to its caller closes a cycle
+	updated := callingWorkflow("Shared ban routine", caller.ID)
+	updated.ID = shared.ID
+	err := CheckWorkflowCalls(&updated, server)
+	if err == nil || !strings.Contains(err.Error(), "Shared ban routine → Teamkill bans → Shared ban routine") {
+		t.Fatalf("expected a cycle error naming both workflows, got %v", err)
+	}
+
+	self := callingWorkflow("Recursive")
+	self.Definition = callingWorkflow("Recursive", self.ID).Definition
+	if err := CheckWorkflowCalls(&self, nil); err == nil {
+		t.Error("expected a self-call to be rejected")
+	}
+
+	foreign := callingWorkflow("Foreign", uuid.New())
+	if err := CheckWorkflowCalls(&foreign, server); err == nil || !strings.Contains(err.Error(), "not a workflow on this server") {
+		t.Errorf("expected an unknown target error, got %v", err)
+	}
+}
+
+func TestExecuteCallWorkflowActionGuards(t *testing.T) {
+	wm := &WorkflowManager{activeWorkflows: map[uuid.UUID]*models.ServerWorkflow{}}
+	callee := uuid.New()
+	step := &models.WorkflowStep{ID: "call", Config: map[string]interface{}{
+		"action_type": models.ActionTypeCallWorkflow,
+		"workflow_id": callee.String(),
+	}}
+
+	newContext := func(metadata map[string]interface{}) *models.WorkflowExecutionContext {
+		return &models.WorkflowExecutionContext{
+			ExecutionID: uuid.New(),
+			WorkflowID:  uuid.New(),
+			Metadata:    metadata,
+			StepResults: map[string]interface{}{},
+		}
+	}
+
+	err := wm.executeCallWorkflowAction(newContext(map[string]interface{}{"call_depth": maxWorkflowCallDepth}), step)
+	if err == nil || !strings.Contains(err.Error(), "levels deep") {
+		t.Errorf("expected the depth limit to apply, got %v", err)
+	}
+
+	err = wm.executeCallWorkflowAction(newContext(map[string]interface{}{
+		"call_depth": 1,
+		"call_stack": []interface{}{callee.String()},
+	}), step)
+	if err == nil || !strings.Contains(err.Error(), "call chain") {
+		t.Errorf("expected a call back up the chain to be rejected, got %v", err)
+	}
+
+	err = wm.executeCallWorkflowAction(newContext(map[string]interface{}{}), step)
+	if err == nil || !strings.Contains(err.Error(), "not an enabled workflow") {
+		t.Errorf("expected an inactive callee to be rejected, got %v", err)
+	}
+}
+
+func TestSelectWorkflowOutputs(t *testing.T) {
+	variables := map[string]interface{}{"verdict": "ban", "score": float64(7), "scratch": true}
+
+	if outputs := selectWorkflowOutputs(variables, nil); len(outputs) != 3 {
+		t.Errorf("expected every variable without a filter, got %v", outputs)
+	}
+	if outputs := selectWorkflowOutputs(variables, []interface{}{"verdict", "missing"}); len(outputs) != 1 || outputs["verdict"] != "ban" {
+		t.Errorf("unexpected filtered outputs %v", outputs)
+	}
+	if outputs := selectWorkflowOutputs(variables, "verdict, score"); len(outputs) != 2 {
+		t.Errorf("expected a comma-separated filter to apply, got %v", outputs)
+	}
+}
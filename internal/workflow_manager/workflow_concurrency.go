@@ -0,0 +1,426 @@
+package workflow_manager
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+// Reasons a trigger did not start an execution of its own
+const (
+	triggerThrottled          = "throttled"
+	triggerDeduplicated       = "deduplicated"
+	triggerConcurrencyDropped = "concurrency_dropped"
+	triggerDebounced          = "debounced"
+)
+
+// triggerStatsFlushInterval is how often trigger counts are written out and
+// idle concurrency state is pruned
+const triggerStatsFlushInterval = 10 * time.Second
+
+// maxWorkflowDebounce bounds debounce_ms
+const maxWorkflowDebounce = time.Hour
+
+// concurrencyKeyPattern matches {{path}} placeholders in dedup keys
+var concurrencyKeyPattern = regexp.MustCompile(`\{\{\s*([\w.]+)\s*\}\}`)
+
+// workflowGate tracks a workflow's executions for its concurrency settings
+type workflowGate struct {
+	running  int
+	starts   []time.Time // Execution starts within the throttle window
+	keys     map[string]*gateKey
+	debounce map[string]*debouncedTrigger
+}
+
+// gatedExecution is an execution holding a slot in its workflow's gate. The
+// slot is held while the execution waits at a wait_for_event step and only
+// released when it finishes.
+type gatedExecution struct {
+	workflowID uuid.UUID
+	key        string
+}
+
+// gateKey tracks the executions started for one dedup key
+type gateKey struct {
+	running   int
+	lastStart time.Time
+}
+
+// debouncedTrigger is the latest trigger waiting out a debounce period
+type debouncedTrigger struct {
+	triggerEvent map[string]interface{}
+	timer        *time.Timer
+}
+
+func newWorkflowGate() *workflowGate {
+	return &workflowGate{
+		keys:     make(map[string]*gateKey),
+		debounce: make(map[string]*debouncedTrigger),
+	}
+}
+
+// admit returns why a trigger with key may not start an execution now, or ""
+// if it may.
+func (g *workflowGate) admit(settings *models.WorkflowConcurrency, key string, now time.Time) string {
+	if settings.DedupKey != "" {
+		if k := g.keys[key]; k != nil {
+			window := time.Duration(settings.DedupWindowSeconds) * time.Second
+			if k.running > 0 || now.Sub(k.lastStart) < window {
+				return triggerDeduplicated
+			}
+		}
+	}
+
+	if settings.MaxConcurrent > 0 && g.running >= settings.MaxConcurrent {
+		return triggerConcurrencyDropped
+	}
+
+	if settings.ThrottleCount > 0 {
+		g.pruneStarts(now, time.Duration(settings.ThrottleWindowSeconds)*time.Second)
+		if len(g.starts) >= settings.ThrottleCount {
+			return triggerThrottled
+		}
+	}
+
+	return ""
+}
+
+// begin records an admitted execution
+func (g *workflowGate) begin(settings *models.WorkflowConcurrency, key string, now time.Time) {
+	g.hold(settings, key)
+	if settings.ThrottleCount > 0 {
+		g.starts = append(g.starts, now)
+	}
+	if settings.DedupKey != "" {
+		g.keys[key].lastStart = now
+	}
+}
+
+// hold counts an execution as running without recording a start, for
+// waiting executions restored after a restart
+func (g *workflowGate) hold(settings *models.WorkflowConcurrency, key string) {
+	g.running++
+	if settings.DedupKey != "" {
+		k := g.keys[key]
+		if k == nil {
+			k = &gateKey{}
+			g.keys[key] = k
+		}
+		k.running++
+	}
+}
+
+// end records that an execution begun with key stopped running
+func (g *workflowGate) end(key string) {
+	if g.running > 0 {
+		g.running--
+	}
+	if k := g.keys[key]; k != nil && k.running > 0 {
+		k.running--
+	}
+}
+
+func (g *workflowGate) pruneStarts(now time.Time, window time.Duration) {
+	kept := g.starts[:0]
+	for _, start := range g.starts {
+		if now.Sub(start) < window {
+			kept = append(kept, start)
+		}
+	}
+	g.starts = kept
+}
+
+// prune drops state that can no longer affect a trigger and reports whether
+// the gate is idle
+func (g *workflowGate) prune(settings *models.WorkflowConcurrency, now time.Time) bool {
+	var throttleWindow, dedupWindow time.Duration
+	if settings != nil {
+		throttleWindow = time.Duration(settings.ThrottleWindowSeconds) * time.Second
+		dedupWindow = time.Duration(settings.DedupWindowSeconds) * time.Second
+	}
+
+	g.pruneStarts(now, throttleWindow)
+	for key, k := range g.keys {
+		if k.running == 0 && now.Sub(k.lastStart) >= dedupWindow {
+			delete(g.keys, key)
+		}
+	}
+
+	return g.running == 0 && len(g.starts) == 0 && len(g.keys) == 0 && len(g.debounce) == 0
+}
+
+// ValidateWorkflowConcurrency checks a workflow's concurrency settings
+func ValidateWorkflowConcurrency(settings *models.WorkflowConcurrency) error {
+	if settings == nil {
+		return nil
+	}
+
+	if settings.MaxConcurrent < 0 || settings.ThrottleCount < 0 || settings.ThrottleWindowSeconds < 0 ||
+		settings.DebounceMs < 0 || settings.DedupWindowSeconds < 0 {
+		return fmt.Errorf("concurrency settings cannot be negative")
+	}
+	if (settings.ThrottleCount > 0) != (settings.ThrottleWindowSeconds > 0) {
+		return fmt.Errorf("throttle_count and throttle_window_seconds must be set together")
+	}
+	if time.Duration(settings.DebounceMs)*time.Millisecond > maxWorkflowDebounce {
+		return fmt.Errorf("debounce_ms may be at most %d", maxWorkflowDebounce.Milliseconds())
+	}
+	if settings.DedupWindowSeconds > 0 && settings.DedupKey == "" {
+		return fmt.Errorf("dedup_window_seconds requires a dedup_key")
+	}
+
+	return nil
+}
+
+// concurrencyKey renders a dedup key template against a trigger event. Both
+// {{trigger_event.field}} and ${trigger_event.field} placeholders work.
+func (wm *WorkflowManager) concurrencyKey(template string, triggerEvent map[string]interface{}) string {
+	if template == "" {
+		return ""
+	}
+
+	data := map[string]interface{}{"trigger_event": triggerEvent}
+	key := concurrencyKeyPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+		path := concurrencyKeyPattern.FindStringSubmatch(placeholder)[1]
+		if value := wm.getValueByPath(path, data); value != nil {
+			return fmt.Sprintf("%v", value)
+		}
+		return ""
+	})
+
+	return wm.replaceVariablesWithContext(key, nil, triggerEvent, nil)
+}
+
+// dispatchWorkflow starts an execution for a trigger, subject to the
+// workflow's concurrency settings
+func (wm *WorkflowManager) dispatchWorkflow(workflow *models.ServerWorkflow, triggerEvent map[string]interface{}) {
+	settings := workflow.Definition.Concurrency
+	if settings == nil || *settings == (models.WorkflowConcurrency{}) {
+		go wm.executeWorkflow(workflow, triggerEvent)
+		return
+	}
+
+	key := wm.concurrencyKey(settings.DedupKey, triggerEvent)
+	if settings.DebounceMs > 0 {
+		wm.debounceTrigger(workflow.ID, key, time.Duration(settings.DebounceMs)*time.Millisecond, triggerEvent)
+		return
+	}
+
+	wm.startGated(workflow, key, triggerEvent)
+}
+
+// startGated starts an execution if the workflow's limits admit it
+func (wm *WorkflowManager) startGated(workflow *models.ServerWorkflow, key string, triggerEvent map[string]interface{}) {
+	settings := workflow.Definition.Concurrency
+	if settings == nil {
+		go wm.executeWorkflow(workflow, triggerEvent)
+		return
+	}
+
+	wm.gateMutex.Lock()
+	gate := wm.gates[workflow.ID]
+	if gate == nil {
+		gate = newWorkflowGate()
+		wm.gates[workflow.ID] = gate
+	}
+	now := time.Now()
+	executionID := uuid.New()
+	reason := gate.admit(settings, key, now)
+	if reason == "" {
+		gate.begin(settings, key, now)
+		wm.gatedExecutions[executionID] = gatedExecution{workflowID: workflow.ID, key: key}
+	} else {
+		wm.countTrigger(workflow.ID, reason)
+	}
+	wm.gateMutex.Unlock()
+
+	if reason != "" {
+		log.Debug().
+			Str("workflow_id", workflow.ID.String()).
+			Str("reason", reason).
+			Str("key", key).
+			Msg("Workflow trigger dropped by concurrency settings")
+		return
+	}
+
+	// The slot is released by finishExecution, which may be long after this
+	// goroutine returns if the execution waits for an event
+	go wm.runWorkflow(executionID, workflow, triggerEvent, nil)
+}
+
+// holdGate gives a restored waiting execution back its slot in the
+// workflow's gate
+func (wm *WorkflowManager) holdGate(wait *models.ServerWorkflowWait) {
+	settings := wait.Definition.Concurrency
+	if settings == nil || *settings == (models.WorkflowConcurrency{}) {
+		return
+	}
+	key := wm.concurrencyKey(settings.DedupKey, wait.Context.TriggerEvent)
+
+	wm.gateMutex.Lock()
+	defer wm.gateMutex.Unlock()
+
+	gate := wm.gates[wait.WorkflowID]
+	if gate == nil {
+		gate = newWorkflowGate()
+		wm.gates[wait.WorkflowID] = gate
+	}
+	gate.hold(settings, key)
+	wm.gatedExecutions[wait.ExecutionID] = gatedExecution{workflowID: wait.WorkflowID, key: key}
+}
+
+// releaseGate frees the gate slot held by an execution once it has finished.
+// Executions that were not gated hold nothing.
+func (wm *WorkflowManager) releaseGate(executionID uuid.UUID) {
+	wm.gateMutex.Lock()
+	defer wm.gateMutex.Unlock()
+
+	held, ok := wm.gatedExecutions[executionID]
+	if !ok {
+		return
+	}
+	delete(wm.gatedExecutions, executionID)
+	if gate := wm.gates[held.workflowID]; gate != nil {
+		gate.end(held.key)
+	}
+}
+
+// debounceTrigger holds a trigger until no other trigger with the same key
+// arrives for delay, then starts an execution with the latest one
+func (wm *WorkflowManager) debounceTrigger(workflowID uuid.UUID, key string, delay time.Duration, triggerEvent map[string]interface{}) {
+	wm.gateMutex.Lock()
+	defer wm.gateMutex.Unlock()
+
+	gate := wm.gates[workflowID]
+	if gate == nil {
+		gate = newWorkflowGate()
+		wm.gates[workflowID] = gate
+	}
+
+	if pending := gate.debounce[key]; pending != nil {
+		pending.triggerEvent = triggerEvent
+		pending.timer.Reset(delay)
+		wm.countTrigger(workflowID, triggerDebounced)
+		return
+	}
+
+	pending := &debouncedTrigger{triggerEvent: triggerEvent}
+	pending.timer = time.AfterFunc(delay, func() {
+		wm.fireDebouncedTrigger(workflowID, key, pending)
+	})
+	gate.debounce[key] = pending
+}
+
+func (wm *WorkflowManager) fireDebouncedTrigger(workflowID uuid.UUID, key string, pending *debouncedTrigger) {
+	wm.gateMutex.Lock()
+	gate := wm.gates[workflowID]
+	// A timer reset just as it fired runs again after it was handled
+	if gate == nil || gate.debounce[key] != pending {
+		wm.gateMutex.Unlock()
+		return
+	}
+	delete(gate.debounce, key)
+	triggerEvent := pending.triggerEvent
+	wm.gateMutex.Unlock()
+
+	if wm.ctx.Err() != nil {
+		return
+	}
+
+	wm.mutex.RLock()
+	workflow := wm.activeWorkflows[workflowID]
+	wm.mutex.RUnlock()
+	if workflow == nil {
+		return
+	}
+
+	wm.startGated(workflow, key, triggerEvent)
+}
+
+// countTrigger records a trigger that did not start its own execution.
+// Callers hold gateMutex.
+func (wm *WorkflowManager) countTrigger(workflowID uuid.UUID, reason string) {
+	counts := wm.triggerCounts[workflowID]
+	if counts == nil {
+		counts = &WorkflowTriggerStats{}
+		wm.triggerCounts[workflowID] = counts
+	}
+
+	switch reason {
+	case triggerThrottled:
+		counts.ThrottledCount++
+	case triggerDeduplicated:
+		counts.DeduplicatedCount++
+	case triggerConcurrencyDropped:
+		counts.ConcurrencyDroppedCount++
+	case triggerDebounced:
+		counts.DebouncedCount++
+	}
+}
+
+// triggerStatsLoop periodically writes trigger counts and prunes idle state
+func (wm *WorkflowManager) triggerStatsLoop() {
+	ticker := time.NewTicker(triggerStatsFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-wm.ctx.Done():
+			return
+		case <-ticker.C:
+			wm.flushTriggerStats()
+			wm.pruneGates()
+		}
+	}
+}
+
+// flushTriggerStats adds the counts gathered since the last flush to the
+// database
+func (wm *WorkflowManager) flushTriggerStats() {
+	wm.gateMutex.Lock()
+	counts := wm.triggerCounts
+	wm.triggerCounts = make(map[uuid.UUID]*WorkflowTriggerStats)
+	wm.gateMutex.Unlock()
+
+	for workflowID, stats := range counts {
+		if err := wm.workflowDB.AddWorkflowTriggerStats(workflowID, stats); err != nil {
+			log.Error().Err(err).Str("workflow_id", workflowID.String()).Msg("Failed to save workflow trigger stats")
+		}
+	}
+}
+
+func (wm *WorkflowManager) pruneGates() {
+	wm.mutex.RLock()
+	settings := make(map[uuid.UUID]*models.WorkflowConcurrency, len(wm.activeWorkflows))
+	for id, workflow := range wm.activeWorkflows {
+		settings[id] = workflow.Definition.Concurrency
+	}
+	wm.mutex.RUnlock()
+
+	wm.gateMutex.Lock()
+	defer wm.gateMutex.Unlock()
+
+	now := time.Now()
+	for id, gate := range wm.gates {
+		if gate.prune(settings[id], now) {
+			delete(wm.gates, id)
+		}
+	}
+}
+
+// stopGates cancels pending debounced triggers
+func (wm *WorkflowManager) stopGates() {
+	wm.gateMutex.Lock()
+	defer wm.gateMutex.Unlock()
+
+	for _, gate := range wm.gates {
+		for key, pending := range gate.debounce {
+			pending.timer.Stop()
+			delete(gate.debounce, key)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package workflow_manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+func TestConcurrencyKey(t *testing.T) {
+	wm := &WorkflowManager{}
+	event := map[string]interface{}{"attacker_eos": "0002abc", "victim": map[string]interface{}{"name": "Alpha"}}
+
+	if key := wm.concurrencyKey("{{trigger_event.attacker_eos}}", event); key != "0002abc" {
+		t.Errorf("unexpected key %q", key)
+	}
+	if key := wm.concurrencyKey("{{ trigger_event.attacker_eos }}:${trigger_event.victim.name}", event); key != "0002abc:Alpha" {
+		t.Errorf("unexpected mixed key %q", key)
+	}
+	if key := wm.concurrencyKey("", event); key != "" {
+		t.Errorf("expected no key without a template, got %q", key)
+	}
+}
+
+func TestWorkflowGateAdmit(t *testing.T) {
+	now := time.Now()
+
+	throttle := &models.WorkflowConcurrency{ThrottleCount: 2, ThrottleWindowSeconds: 60}
+	gate := newWorkflowGate()
+	for i := 0; i < 2; i++ {
+		if reason := gate.admit(throttle, "", now); reason != "" {
+			t.Fatalf("start %d unexpectedly %s", i+1, reason)
+		}
+		gate.begin(throttle, "", now)
+	}
+	if reason := gate.admit(throttle, "", now.Add(30*time.Second)); reason != triggerThrottled {
+		t.Errorf("expected the third start in the window to be throttled, got %q", reason)
+	}
+	if reason := gate.admit(throttle, "", now.Add(61*time.Second)); reason != "" {
+		t.Errorf("expected a start after the window to be admitted, got %q", reason)
+	}
+
+	limited := &models.WorkflowConcurrency{MaxConcurrent: 1}
+	gate = newWorkflowGate()
+	gate.begin(limited, "", now)
+	if reason := gate.admit(limited, "", now); reason != triggerConcurrencyDropped {
+		t.Errorf("expected a second concurrent execution to be dropped, got %q", reason)
+	}
+	gate.end("")
+	if reason := gate.admit(limited, "", now); reason != "" {
+		t.Errorf("expected a start after the first ended, got %q", reason)
+	}
+
+	dedup := &models.WorkflowConcurrency{DedupKey: "{{trigger_event.attacker_eos}}", DedupWindowSeconds: 30}
+	gate = newWorkflowGate()
+	gate.begin(dedup, "a", now)
+	if reason := gate.admit(dedup, "a", now); reason != triggerDeduplicated {
+		t.Errorf("expected a running key to be deduplicated, got %q", reason)
+	}
+	if reason := gate.admit(dedup, "b", now); reason != "" {
+		t.Errorf("expected another key to be admitted, got %q", reason)
+	}
+	gate.end("a")
+	if reason := gate.admit(dedup, "a", now.Add(10*time.Second)); reason != triggerDeduplicated {
+		t.Errorf("expected a key inside its window to be deduplicated, got %q", reason)
+	}
+	if reason := gate.admit(dedup, "a", now.Add(31*time.Second)); reason != "" {
+		t.Errorf("expected a key after its window to be admitted, got %q", reason)
+	}
+	if !gate.prune(dedup, now.Add(31*time.Second)) {
+		t.Error("expected the gate to be idle once the window passed")
+	}
+}
+
+func TestDebounceTriggerCoalesces(t *testing.T) {
+	wm := &WorkflowManager{
+		gates:         map[uuid.UUID]*workflowGate{},
+		triggerCounts: map[uuid.UUID]*WorkflowTriggerStats{},
+	}
+	workflowID := uuid.New()
+
+	for i := 0; i < 3; i++ {
+		wm.debounceTrigger(workflowID, "squad", time.Hour, map[string]interface{}{"n": i})
+	}
+	defer wm.stopGates()
+
+	pending := wm.gates[workflowID].debounce["squad"]
+	if pending == nil || pending.triggerEvent["n"] != 2 {
+		t.Fatalf("expected the latest trigger to be pending, got %+v", pending)
+	}
+	if got := wm.triggerCounts[workflowID].DebouncedCount; got != 2 {
+		t.Errorf("expected 2 coalesced triggers, got %d", got)
+	}
+}
+
+func TestWaitingExecutionHoldsGateSlot(t *testing.T) {
+	wm := &WorkflowManager{
+		gates:           map[uuid.UUID]*workflowGate{},
+		gatedExecutions: map[uuid.UUID]gatedExecution{},
+	}
+	limited := &models.WorkflowConcurrency{MaxConcurrent: 1}
+	wait := &models.ServerWorkflowWait{
+		ExecutionID: uuid.New(),
+		WorkflowID:  uuid.New(),
+		Definition:  models.WorkflowDefinition{Concurrency: limited},
+	}
+
+	// A waiting execution restored after a restart still counts as running
+	wm.holdGate(wait)
+	if reason := wm.gates[wait.WorkflowID].admit(limited, "", time.Now()); reason != triggerConcurrencyDropped {
+		t.Fatalf("expected a trigger to be dropped while an execution waits, got %q", reason)
+	}
+
+	wm.releaseGate(wait.ExecutionID)
+	if reason := wm.gates[wait.WorkflowID].admit(limited, "", time.Now()); reason != "" {
+		t.Fatalf("expected a start once the waiting execution finished, got %q", reason)
+	}
+
+	// Releasing twice, or an ungated execution, frees nothing
+	wm.holdGate(wait)
+	wm.releaseGate(wait.ExecutionID)
+	wm.releaseGate(wait.ExecutionID)
+	wm.releaseGate(uuid.New())
+	if running := wm.gates[wait.WorkflowID].running; running != 0 {
+		t.Fatalf("expected no running executions, got %d", running)
+	}
+}
+
+func TestValidateWorkflowConcurrency(t *testing.T) {
+	valid := &models.WorkflowConcurrency{MaxConcurrent: 2, ThrottleCount: 5, ThrottleWindowSeconds: 60, DedupKey: "{{trigger_event.steam_id}}", DedupWindowSeconds: 30}
+	if err := ValidateWorkflowConcurrency(valid); err != nil {
+		t.Fatalf("valid settings rejected: %v", err)
+	}
+
+	invalid := map[string]*models.WorkflowConcurrency{
+		"negative":                {MaxConcurrent: -1},
+		"throttle without window": {ThrottleCount: 5},
+		"window without throttle": {ThrottleWindowSeconds: 60},
+		"debounce too long":       {DebounceMs: int(2 * time.Hour / time.Millisecond)},
+		"dedup window no key":     {DedupWindowSeconds: 30},
+	}
+	for name, settings := range invalid {
+		if err := ValidateWorkflowConcurrency(settings); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -19,19 +19,26 @@ func NewWorkflowDatabase(db *sql.DB) *WorkflowDatabase {
 	return &WorkflowDatabase{db: db}
 }
 
-// CreateWorkflow creates a new workflow in the database
-func (wd *WorkflowDatabase) CreateWorkflow(workflow *models.ServerWorkflow) error {
+// CreateWorkflow creates a new workflow in the database along with its first
+// revision
+func (wd *WorkflowDatabase) CreateWorkflow(workflow *models.ServerWorkflow, changeNote *string) error {
 	definitionJSON, err := json.Marshal(workflow.Definition)
 	if err != nil {
 		return fmt.Errorf("failed to marshal workflow definition: %w", err)
 	}
 
+	tx, err := wd.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO server_workflows (id, server_id, name, description, enabled, definition, created_by, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
-	_, err = wd.db.Exec(query,
+	_, err = tx.Exec(query,
 		workflow.ID,
 		workflow.ServerID,
 		workflow.Name,
@@ -42,16 +49,25 @@ func (wd *WorkflowDatabase) CreateWorkflow(workflow *models.ServerWorkflow) erro
 		workflow.CreatedAt,
 		workflow.UpdatedAt,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	if _, err := insertWorkflowRevision(tx, workflow, definitionJSON, workflow.CreatedBy, changeNote, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // GetWorkflow retrieves a workflow by ID
 func (wd *WorkflowDatabase) GetWorkflow(workflowID uuid.UUID) (*models.ServerWorkflow, error) {
 	query := `
-		SELECT id, server_id, name, description, enabled, definition, created_by, created_at, updated_at
-		FROM server_workflows
-		WHERE id = $1
+		SELECT w.id, w.server_id, w.name, w.description, w.enabled, w.definition, w.created_by, w.created_at, w.updated_at,
+			w.current_revision_id, COALESCE(r.revision, 0)
+		FROM server_workflows w
+		LEFT JOIN server_workflow_revisions r ON r.id = w.current_revision_id
+		WHERE w.id = $1
 	`
 
 	var workflow models.ServerWorkflow
@@ -68,6 +84,8 @@ func (wd *WorkflowDatabase) GetWorkflow(workflowID uuid.UUID) (*models.ServerWor
 		&workflow.CreatedBy,
 		&workflow.CreatedAt,
 		&workflow.UpdatedAt,
+		&workflow.CurrentRevisionID,
+		&workflow.Revision,
 	)
 
 	if err != nil {
@@ -93,10 +111,12 @@ func (wd *WorkflowDatabase) GetWorkflow(workflowID uuid.UUID) (*models.ServerWor
 // GetWorkflowsByServerID retrieves all workflows for a server
 func (wd *WorkflowDatabase) GetWorkflowsByServerID(serverID uuid.UUID) ([]models.ServerWorkflow, error) {
 	query := `
-		SELECT id, server_id, name, description, enabled, definition, created_by, created_at, updated_at
-		FROM server_workflows
-		WHERE server_id = $1
-		ORDER BY created_at DESC
+		SELECT w.id, w.server_id, w.name, w.description, w.enabled, w.definition, w.created_by, w.created_at, w.updated_at,
+			w.current_revision_id, COALESCE(r.revision, 0)
+		FROM server_workflows w
+		LEFT JOIN server_workflow_revisions r ON r.id = w.current_revision_id
+		WHERE w.server_id = $1
+		ORDER BY w.created_at DESC
 	`
 
 	rows, err := wd.db.Query(query, serverID)
@@ -122,6 +142,8 @@ func (wd *WorkflowDatabase) GetWorkflowsByServerID(serverID uuid.UUID) ([]models
 			&workflow.CreatedBy,
 			&workflow.CreatedAt,
 			&workflow.UpdatedAt,
+			&workflow.CurrentRevisionID,
+			&workflow.Revision,
 		)
 
 		if err != nil {
@@ -318,7 +340,7 @@ func (wd *WorkflowDatabase) loadWorkflowVariables(workflow *models.ServerWorkflo
 // GetExecutionsByWorkflowID retrieves workflow executions for a workflow
 func (wd *WorkflowDatabase) GetExecutionsByWorkflowID(workflowID uuid.UUID, limit, offset int) ([]models.ServerWorkflowExecution, error) {
 	query := `
-		SELECT id, workflow_id, execution_id, status, trigger_data, started_at, completed_at, error_message
+		SELECT id, workflow_id, execution_id, status, trigger_data, started_at, completed_at, error_message, revision_id
 		FROM server_workflow_executions
 		WHERE workflow_id = $1
 		ORDER BY started_at DESC
@@ -348,6 +370,7 @@ func (wd *WorkflowDatabase) GetExecutionsByWorkflowID(workflowID uuid.UUID, limi
 			&execution.StartedAt,
 			&completedAt,
 			&errorMessage,
+			&execution.RevisionID,
 		)
 
 		if err != nil {
@@ -377,7 +400,7 @@ func (wd *WorkflowDatabase) GetExecutionsByWorkflowID(workflowID uuid.UUID, limi
 // GetExecutionsByServerID retrieves workflow executions for a server
 func (wd *WorkflowDatabase) GetExecutionsByServerID(serverID uuid.UUID, limit, offset int) ([]models.ServerWorkflowExecution, error) {
 	query := `
-		SELECT e.id, e.workflow_id, e.execution_id, e.status, e.trigger_data, e.started_at, e.completed_at, e.error_message
+		SELECT e.id, e.workflow_id, e.execution_id, e.status, e.trigger_data, e.started_at, e.completed_at, e.error_message, e.revision_id
 		FROM server_workflow_executions e
 		JOIN server_workflows w ON e.workflow_id = w.id
 		WHERE w.server_id = $1
@@ -408,6 +431,7 @@ func (wd *WorkflowDatabase) GetExecutionsByServerID(serverID uuid.UUID, limit, o
 			&execution.StartedAt,
 			&completedAt,
 			&errorMessage,
+			&execution.RevisionID,
 		)
 
 		if err != nil {
@@ -437,8 +461,8 @@ func (wd *WorkflowDatabase) GetExecutionsByServerID(serverID uuid.UUID, limit, o
 // CreateWorkflowExecution creates a new workflow execution record
 func (wd *WorkflowDatabase) CreateWorkflowExecution(execution *models.ServerWorkflowExecution) error {
 	query := `
-		INSERT INTO server_workflow_executions (id, workflow_id, execution_id, status, trigger_data, started_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO server_workflow_executions (id, workflow_id, execution_id, status, trigger_data, started_at, revision_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
 	triggerDataJSON, err := json.Marshal(execution.TriggerData)
@@ -453,6 +477,7 @@ func (wd *WorkflowDatabase) CreateWorkflowExecution(execution *models.ServerWork
 		execution.Status,
 		triggerDataJSON,
 		execution.StartedAt,
+		execution.RevisionID,
 	)
 
 	return err
@@ -479,7 +504,7 @@ func (wd *WorkflowDatabase) UpdateWorkflowExecution(execution *models.ServerWork
 // GetWorkflowExecution retrieves a workflow execution by execution ID
 func (wd *WorkflowDatabase) GetWorkflowExecution(executionID uuid.UUID) (*models.ServerWorkflowExecution, error) {
 	query := `
-		SELECT id, workflow_id, execution_id, status, trigger_data, started_at, completed_at, error_message
+		SELECT id, workflow_id, execution_id, status, trigger_data, started_at, completed_at, error_message, revision_id
 		FROM server_workflow_executions
 		WHERE execution_id = $1
 	`
@@ -498,6 +523,7 @@ func (wd *WorkflowDatabase) GetWorkflowExecution(executionID uuid.UUID) (*models
 		&execution.StartedAt,
 		&completedAt,
 		&errorMessage,
+		&execution.RevisionID,
 	)
 
 	if err != nil {
@@ -521,158 +547,46 @@ func (wd *WorkflowDatabase) GetWorkflowExecution(executionID uuid.UUID) (*models
 	return &execution, nil
 }
 
-// KV Store Operations
-
-// GetKVValue retrieves a value from the workflow KV store
-func (wd *WorkflowDatabase) GetKVValue(workflowID uuid.UUID, key string) (interface{}, error) {
-	query := `
-		SELECT value
-		FROM server_workflow_kv_store
-		WHERE workflow_id = $1 AND key = $2
-	`
-
-	var valueJSON []byte
-	err := wd.db.QueryRow(query, workflowID, key).Scan(&valueJSON)
-	if err != nil {
-		return nil, err
-	}
-
-	var value interface{}
-	if err := json.Unmarshal(valueJSON, &value); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal KV value: %w", err)
-	}
-
-	return value, nil
-}
-
-// SetKVValue sets a value in the workflow KV store (creates or updates)
-func (wd *WorkflowDatabase) SetKVValue(workflowID uuid.UUID, key string, value interface{}) error {
-	valueJSON, err := json.Marshal(value)
-	if err != nil {
-		return fmt.Errorf("failed to marshal KV value: %w", err)
-	}
-
-	query := `
-		INSERT INTO server_workflow_kv_store (id, workflow_id, key, value, created_at, updated_at)
-		VALUES (gen_random_uuid(), $1, $2, $3, NOW(), NOW())
-		ON CONFLICT (workflow_id, key)
-		DO UPDATE SET value = $3, updated_at = NOW()
-	`
-
-	_, err = wd.db.Exec(query, workflowID, key, valueJSON)
-	return err
-}
-
-// DeleteKVValue deletes a value from the workflow KV store
-func (wd *WorkflowDatabase) DeleteKVValue(workflowID uuid.UUID, key string) error {
-	query := `DELETE FROM server_workflow_kv_store WHERE workflow_id = $1 AND key = $2`
-	_, err := wd.db.Exec(query, workflowID, key)
-	return err
+// WorkflowExecutionStats holds computed statistics for a workflow
+type WorkflowExecutionStats struct {
+	TotalExecutions int64   `json:"total_executions"`
+	SuccessRate     float64 `json:"success_rate"`
+	AvgDurationMs   *int64  `json:"avg_duration_ms"`
+	RunningCount    int64   `json:"running_count"`
+	WorkflowTriggerStats
 }
 
-// KVExists checks if a key exists in the workflow KV store
-func (wd *WorkflowDatabase) KVExists(workflowID uuid.UUID, key string) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM server_workflow_kv_store WHERE workflow_id = $1 AND key = $2)`
-
-	var exists bool
-	err := wd.db.QueryRow(query, workflowID, key).Scan(&exists)
-	if err != nil {
-		return false, err
-	}
-
-	return exists, nil
+// WorkflowTriggerStats counts triggers that did not start an execution of
+// their own because of the workflow's concurrency settings
+type WorkflowTriggerStats struct {
+	ThrottledCount          int64 `json:"throttled_count"`
+	DeduplicatedCount       int64 `json:"deduplicated_count"`
+	ConcurrencyDroppedCount int64 `json:"concurrency_dropped_count"`
+	DebouncedCount          int64 `json:"debounced_count"`
 }
 
-// ListKVKeys returns all keys in the workflow KV store
-func (wd *WorkflowDatabase) ListKVKeys(workflowID uuid.UUID) ([]string, error) {
+// AddWorkflowTriggerStats adds to a workflow's trigger counts. Counts for a
+// workflow deleted in the meantime are discarded.
+func (wd *WorkflowDatabase) AddWorkflowTriggerStats(workflowID uuid.UUID, stats *WorkflowTriggerStats) error {
 	query := `
-		SELECT key
-		FROM server_workflow_kv_store
-		WHERE workflow_id = $1
-		ORDER BY key ASC
-	`
-
-	rows, err := wd.db.Query(query, workflowID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var keys []string
-	for rows.Next() {
-		var key string
-		if err := rows.Scan(&key); err != nil {
-			return nil, err
-		}
-		keys = append(keys, key)
-	}
-
-	return keys, nil
-}
-
-// GetAllKVPairs returns all key-value pairs for a workflow
-func (wd *WorkflowDatabase) GetAllKVPairs(workflowID uuid.UUID) (map[string]interface{}, error) {
-	query := `
-		SELECT key, value
-		FROM server_workflow_kv_store
-		WHERE workflow_id = $1
-		ORDER BY key ASC
+		INSERT INTO server_workflow_trigger_stats (
+			workflow_id, throttled_count, deduplicated_count, concurrency_dropped_count, debounced_count
+		)
+		SELECT $1, $2, $3, $4, $5
+		WHERE EXISTS (SELECT 1 FROM server_workflows WHERE id = $1)
+		ON CONFLICT (workflow_id) DO UPDATE SET
+			throttled_count = server_workflow_trigger_stats.throttled_count + EXCLUDED.throttled_count,
+			deduplicated_count = server_workflow_trigger_stats.deduplicated_count + EXCLUDED.deduplicated_count,
+			concurrency_dropped_count = server_workflow_trigger_stats.concurrency_dropped_count + EXCLUDED.concurrency_dropped_count,
+			debounced_count = server_workflow_trigger_stats.debounced_count + EXCLUDED.debounced_count,
+			updated_at = NOW()
 	`
 
-	rows, err := wd.db.Query(query, workflowID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	kvPairs := make(map[string]interface{})
-	for rows.Next() {
-		var key string
-		var valueJSON []byte
-
-		if err := rows.Scan(&key, &valueJSON); err != nil {
-			return nil, err
-		}
-
-		var value interface{}
-		if err := json.Unmarshal(valueJSON, &value); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal KV value for key %s: %w", key, err)
-		}
-
-		kvPairs[key] = value
-	}
-
-	return kvPairs, nil
-}
-
-// ClearKVStore removes all key-value pairs for a workflow
-func (wd *WorkflowDatabase) ClearKVStore(workflowID uuid.UUID) error {
-	query := `DELETE FROM server_workflow_kv_store WHERE workflow_id = $1`
-	_, err := wd.db.Exec(query, workflowID)
+	_, err := wd.db.Exec(query, workflowID, stats.ThrottledCount, stats.DeduplicatedCount,
+		stats.ConcurrencyDroppedCount, stats.DebouncedCount)
 	return err
 }
 
-// CountKVPairs returns the number of key-value pairs for a workflow
-func (wd *WorkflowDatabase) CountKVPairs(workflowID uuid.UUID) (int, error) {
-	query := `SELECT COUNT(*) FROM server_workflow_kv_store WHERE workflow_id = $1`
-
-	var count int
-	err := wd.db.QueryRow(query, workflowID).Scan(&count)
-	if err != nil {
-		return 0, err
-	}
-
-	return count, nil
-}
-
-// WorkflowExecutionStats holds computed statistics for a workflow
-type WorkflowExecutionStats struct {
-	TotalExecutions int64   `json:"total_executions"`
-	SuccessRate     float64 `json:"success_rate"`
-	AvgDurationMs   *int64  `json:"avg_duration_ms"`
-	RunningCount    int64   `json:"running_count"`
-}
-
 // GetWorkflowExecutionStats computes aggregate statistics for a workflow
 func (wd *WorkflowDatabase) GetWorkflowExecutionStats(workflowID uuid.UUID) (*WorkflowExecutionStats, error) {
 	query := `
@@ -687,7 +601,11 @@ func (wd *WorkflowDatabase) GetWorkflowExecutionStats(workflowID uuid.UUID) (*Wo
 				0
 			) as success_rate,
 			EXTRACT(EPOCH FROM AVG(completed_at - started_at) FILTER (WHERE completed_at IS NOT NULL)) * 1000 as avg_duration_ms,
-			COUNT(*) FILTER (WHERE status IN ('RUNNING', 'EXECUTING')) as running_count
+			COUNT(*) FILTER (WHERE status IN ('RUNNING', 'EXECUTING')) as running_count,
+			COALESCE((SELECT throttled_count FROM server_workflow_trigger_stats WHERE workflow_id = $1), 0) as throttled_count,
+			COALESCE((SELECT deduplicated_count FROM server_workflow_trigger_stats WHERE workflow_id = $1), 0) as deduplicated_count,
+			COALESCE((SELECT concurrency_dropped_count FROM server_workflow_trigger_stats WHERE workflow_id = $1), 0) as concurrency_dropped_count,
+			COALESCE((SELECT debounced_count FROM server_workflow_trigger_stats WHERE workflow_id = $1), 0) as debounced_count
 		FROM server_workflow_executions
 		WHERE workflow_id = $1
 	`
@@ -700,6 +618,10 @@ func (wd *WorkflowDatabase) GetWorkflowExecutionStats(workflowID uuid.UUID) (*Wo
 		&stats.SuccessRate,
 		&avgDurationMs,
 		&stats.RunningCount,
+		&stats.ThrottledCount,
+		&stats.DeduplicatedCount,
+		&stats.ConcurrencyDroppedCount,
+		&stats.DebouncedCount,
 	)
 
 	if err != nil {
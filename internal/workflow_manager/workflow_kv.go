@@ -0,0 +1,562 @@
+package workflow_manager
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+// kvExpiryInterval is how often expired KV entries are deleted. Reads hide
+// them as soon as they expire.
+const kvExpiryInterval = time.Minute
+
+// kvLive matches entries that have not expired
+const kvLive = `(expires_at IS NULL OR expires_at > NOW())`
+
+// kvNamespaceNamePattern matches valid shared namespace names
+var kvNamespaceNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,99}$`)
+
+var (
+	// ErrKVNotNumber is returned when incrementing a key that holds a non-number
+	ErrKVNotNumber = errors.New("existing value is not a number")
+	// ErrKVOutOfBounds is returned when an increment would pass its bounds
+	ErrKVOutOfBounds = errors.New("increment would pass its bounds")
+)
+
+// KVBounds limits the value an increment may produce
+type KVBounds struct {
+	Min *float64
+	Max *float64
+}
+
+// KVStore is one key-value store: a workflow's own store or a shared
+// namespace. Expired entries are never returned.
+type KVStore struct {
+	db     *sql.DB
+	table  string
+	column string
+	owner  uuid.UUID
+}
+
+// WorkflowKV returns a workflow's own KV store
+func (wd *WorkflowDatabase) WorkflowKV(workflowID uuid.UUID) *KVStore {
+	return &KVStore{db: wd.db, table: "server_workflow_kv_store", column: "workflow_id", owner: workflowID}
+}
+
+// NamespaceKV returns a shared namespace's KV store
+func (wd *WorkflowDatabase) NamespaceKV(namespaceID uuid.UUID) *KVStore {
+	return &KVStore{db: wd.db, table: "server_workflow_kv_namespace_entries", column: "namespace_id", owner: namespaceID}
+}
+
+// sql fills in the store's table and owner column
+func (s *KVStore) sql(query string) string {
+	return strings.NewReplacer("{table}", s.table, "{owner}", s.column).Replace(query)
+}
+
+// kvUpsert writes a value. $4 is the TTL in seconds, 0 for none; when $5 is
+// true an existing entry keeps its expiry.
+const kvUpsert = `
+	INSERT INTO {table} (id, {owner}, key, value, expires_at, created_at, updated_at)
+	VALUES (gen_random_uuid(), $1, $2, $3,
+		CASE WHEN $4::float8 > 0 THEN NOW() + make_interval(secs => $4::float8) END, NOW(), NOW())
+	ON CONFLICT ({owner}, key) DO UPDATE SET
+		value = EXCLUDED.value,
+		expires_at = CASE WHEN $5 THEN {table}.expires_at ELSE EXCLUDED.expires_at END,
+		updated_at = NOW()
+`
+
+// Get returns a key's value, or sql.ErrNoRows if it is missing or expired
+func (s *KVStore) Get(key string) (interface{}, error) {
+	var valueJSON []byte
+	err := s.db.QueryRow(s.sql(`SELECT value FROM {table} WHERE {owner} = $1 AND key = $2 AND `+kvLive), s.owner, key).Scan(&valueJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(valueJSON, &value); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal KV value: %w", err)
+	}
+	return value, nil
+}
+
+// Set creates or replaces a key. A ttl of 0 stores it without expiry.
+func (s *KVStore) Set(key string, value interface{}, ttl time.Duration) error {
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal KV value: %w", err)
+	}
+
+	_, err = s.db.Exec(s.sql(kvUpsert), s.owner, key, valueJSON, ttl.Seconds(), false)
+	return err
+}
+
+// Expire sets a key's TTL, or removes its expiry when ttl is 0, and reports
+// whether the key exists
+func (s *KVStore) Expire(key string, ttl time.Duration) (bool, error) {
+	result, err := s.db.Exec(s.sql(`
+		UPDATE {table}
+		SET expires_at = CASE WHEN $3::float8 > 0 THEN NOW() + make_interval(secs => $3::float8) END, updated_at = NOW()
+		WHERE {owner} = $1 AND key = $2 AND `+kvLive), s.owner, key, ttl.Seconds())
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// TTL returns how long a key has left, or nil if it never expires. Missing
+// keys return sql.ErrNoRows.
+func (s *KVStore) TTL(key string) (*time.Duration, error) {
+	var remaining sql.NullFloat64
+	err := s.db.QueryRow(s.sql(`
+		SELECT EXTRACT(EPOCH FROM expires_at - NOW())
+		FROM {table}
+		WHERE {owner} = $1 AND key = $2 AND `+kvLive), s.owner, key).Scan(&remaining)
+	if err != nil {
+		return nil, err
+	}
+	if !remaining.Valid {
+		return nil, nil
+	}
+
+	ttl := time.Duration(remaining.Float64 * float64(time.Second))
+	return &ttl, nil
+}
+
+// Delete removes a key
+func (s *KVStore) Delete(key string) error {
+	_, err := s.db.Exec(s.sql(`DELETE FROM {table} WHERE {owner} = $1 AND key = $2`), s.owner, key)
+	return err
+}
+
+// Exists reports whether a key is set
+func (s *KVStore) Exists(key string) (bool, error) {
+	var exists bool
+	err := s.db.QueryRow(s.sql(`SELECT EXISTS(SELECT 1 FROM {table} WHERE {owner} = $1 AND key = $2 AND `+kvLive+`)`), s.owner, key).Scan(&exists)
+	return exists, err
+}
+
+// Keys returns every key in the store
+func (s *KVStore) Keys() ([]string, error) {
+	rows, err := s.db.Query(s.sql(`SELECT key FROM {table} WHERE {owner} = $1 AND `+kvLive+` ORDER BY key ASC`), s.owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Entries returns every key in the store with its value and expiry
+func (s *KVStore) Entries() ([]models.WorkflowKVEntry, error) {
+	rows, err := s.db.Query(s.sql(`
+		SELECT key, value, expires_at, updated_at
+		FROM {table}
+		WHERE {owner} = $1 AND `+kvLive+`
+		ORDER BY key ASC`), s.owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.WorkflowKVEntry{}
+	for rows.Next() {
+		var entry models.WorkflowKVEntry
+		var valueJSON []byte
+		var expiresAt sql.NullTime
+
+		if err := rows.Scan(&entry.Key, &valueJSON, &expiresAt, &entry.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(valueJSON, &entry.Value); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal KV value for key %s: %w", entry.Key, err)
+		}
+		if expiresAt.Valid {
+			entry.ExpiresAt = &expiresAt.Time
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// All returns every key in the store with its value
+func (s *KVStore) All() (map[string]interface{}, error) {
+	entries, err := s.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := make(map[string]interface{}, len(entries))
+	for _, entry := range entries {
+		pairs[entry.Key] = entry.Value
+	}
+	return pairs, nil
+}
+
+// Clear removes every key in the store
+func (s *KVStore) Clear() error {
+	_, err := s.db.Exec(s.sql(`DELETE FROM {table} WHERE {owner} = $1`), s.owner)
+	return err
+}
+
+// Count returns the number of keys in the store
+func (s *KVStore) Count() (int, error) {
+	var count int
+	err := s.db.QueryRow(s.sql(`SELECT COUNT(*) FROM {table} WHERE {owner} = $1 AND `+kvLive), s.owner).Scan(&count)
+	return count, err
+}
+
+// Increment atomically adds delta to a numeric key, starting from 0 when it
+// is missing. An increment that would pass bounds is not applied and returns
+// ErrKVOutOfBounds. ttl applies only when the key is created, so a counter
+// expires a fixed time after its first increment.
+func (s *KVStore) Increment(key string, delta float64, bounds KVBounds, ttl time.Duration) (float64, error) {
+	var next float64
+	err := s.update(key, ttl, true, func(current interface{}, exists bool) (interface{}, bool, error) {
+		value, err := incrementKVValue(current, exists, delta, bounds)
+		if err != nil {
+			return nil, false, err
+		}
+		next = value
+		return value, true, nil
+	})
+	return next, err
+}
+
+// CompareAndSet atomically replaces a key's value if it equals expected. A
+// nil expected value requires the key to be missing. ttl works as in Set.
+func (s *KVStore) CompareAndSet(key string, expected, value interface{}, ttl time.Duration) (bool, error) {
+	swapped := false
+	err := s.update(key, ttl, false, func(current interface{}, exists bool) (interface{}, bool, error) {
+		if expected == nil {
+			swapped = !exists
+		} else {
+			swapped = exists && kvValuesEqual(current, expected)
+		}
+		return value, swapped, nil
+	})
+	return swapped, err
+}
+
+// update runs fn on a key's current value while holding the key, and writes
+// the value fn returns when it asks to. With keepExpiry an existing entry
+// keeps its expiry; otherwise ttl replaces it.
+func (s *KVStore) update(key string, ttl time.Duration, keepExpiry bool, fn func(current interface{}, exists bool) (interface{}, bool, error)) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Row locks cannot cover a key that does not exist yet, so a transaction
+	// lock on the key serialises concurrent first writes
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1))`, fmt.Sprintf("%s:%s:%s", s.table, s.owner, key)); err != nil {
+		return err
+	}
+
+	var valueJSON []byte
+	var current interface{}
+	exists := true
+	err = tx.QueryRow(s.sql(`
+		SELECT value FROM {table}
+		WHERE {owner} = $1 AND key = $2 AND `+kvLive+`
+		FOR UPDATE`), s.owner, key).Scan(&valueJSON)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		exists = false
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(valueJSON, &current); err != nil {
+			return fmt.Errorf("failed to unmarshal KV value: %w", err)
+		}
+	}
+
+	next, write, err := fn(current, exists)
+	if err != nil || !write {
+		return err
+	}
+
+	nextJSON, err := json.Marshal(next)
+	if err != nil {
+		return fmt.Errorf("failed to marshal KV value: %w", err)
+	}
+	if _, err := tx.Exec(s.sql(kvUpsert), s.owner, key, nextJSON, ttl.Seconds(), keepExpiry && exists); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// incrementKVValue returns current plus delta, checking bounds
+func incrementKVValue(current interface{}, exists bool, delta float64, bounds KVBounds) (float64, error) {
+	var value float64
+	if exists {
+		number, ok := current.(float64)
+		if !ok {
+			return 0, ErrKVNotNumber
+		}
+		value = number
+	}
+
+	value += delta
+	if bounds.Min != nil && value < *bounds.Min {
+		return 0, fmt.Errorf("%w: result %v is below the minimum %v", ErrKVOutOfBounds, value, *bounds.Min)
+	}
+	if bounds.Max != nil && value > *bounds.Max {
+		return 0, fmt.Errorf("%w: result %v is above the maximum %v", ErrKVOutOfBounds, value, *bounds.Max)
+	}
+	return value, nil
+}
+
+// kvValuesEqual compares two values as stored JSON
+func kvValuesEqual(a, b interface{}) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(aJSON, bJSON)
+}
+
+// DeleteExpiredKVEntries removes expired entries from workflow stores and
+// shared namespaces
+func (wd *WorkflowDatabase) DeleteExpiredKVEntries() (int64, error) {
+	var total int64
+	for _, table := range []string{"server_workflow_kv_store", "server_workflow_kv_namespace_entries"} {
+		result, err := wd.db.Exec(`DELETE FROM ` + table + ` WHERE expires_at <= NOW()`)
+		if err != nil {
+			return total, err
+		}
+		rows, _ := result.RowsAffected()
+		total += rows
+	}
+	return total, nil
+}
+
+// kvExpiryLoop periodically deletes expired KV entries
+func (wm *WorkflowManager) kvExpiryLoop() {
+	ticker := time.NewTicker(kvExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-wm.ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := wm.workflowDB.DeleteExpiredKVEntries()
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to delete expired workflow KV entries")
+			} else if deleted > 0 {
+				log.Debug().Int64("deleted", deleted).Msg("Deleted expired workflow KV entries")
+			}
+		}
+	}
+}
+
+// Shared KV namespace operations
+
+// ValidKVNamespaceName reports whether name can name a shared namespace:
+// lowercase letters, digits, dots, dashes and underscores, up to 100 long
+func ValidKVNamespaceName(name string) bool {
+	return kvNamespaceNamePattern.MatchString(name)
+}
+
+const kvNamespaceColumns = `id, server_id, name, description, created_at, updated_at`
+
+func scanKVNamespace(row interface{ Scan(...any) error }) (*models.WorkflowKVNamespace, error) {
+	var namespace models.WorkflowKVNamespace
+	var description sql.NullString
+
+	if err := row.Scan(
+		&namespace.ID,
+		&namespace.ServerID,
+		&namespace.Name,
+		&description,
+		&namespace.CreatedAt,
+		&namespace.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if description.Valid {
+		namespace.Description = &description.String
+	}
+	namespace.Grants = []models.WorkflowKVGrant{}
+	return &namespace, nil
+}
+
+// ListKVNamespaces returns a server's shared namespaces with their grants
+func (wd *WorkflowDatabase) ListKVNamespaces(serverID uuid.UUID) ([]models.WorkflowKVNamespace, error) {
+	rows, err := wd.db.Query(`SELECT `+kvNamespaceColumns+` FROM server_workflow_kv_namespaces WHERE server_id = $1 ORDER BY name`, serverID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	namespaces := []models.WorkflowKVNamespace{}
+	index := map[uuid.UUID]int{}
+	for rows.Next() {
+		namespace, err := scanKVNamespace(rows)
+		if err != nil {
+			return nil, err
+		}
+		index[namespace.ID] = len(namespaces)
+		namespaces = append(namespaces, *namespace)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	grantRows, err := wd.db.Query(`
+		SELECT g.namespace_id, g.workflow_id, g.access
+		FROM server_workflow_kv_namespace_grants g
+		JOIN server_workflow_kv_namespaces n ON n.id = g.namespace_id
+		WHERE n.server_id = $1
+		ORDER BY g.workflow_id
+	`, serverID)
+	if err != nil {
+		return nil, err
+	}
+	defer grantRows.Close()
+
+	for grantRows.Next() {
+		var namespaceID uuid.UUID
+		var grant models.WorkflowKVGrant
+		if err := grantRows.Scan(&namespaceID, &grant.WorkflowID, &grant.Access); err != nil {
+			return nil, err
+		}
+		if i, ok := index[namespaceID]; ok {
+			namespaces[i].Grants = append(namespaces[i].Grants, grant)
+		}
+	}
+
+	return namespaces, grantRows.Err()
+}
+
+// GetKVNamespace retrieves a shared namespace with its grants
+func (wd *WorkflowDatabase) GetKVNamespace(namespaceID uuid.UUID) (*models.WorkflowKVNamespace, error) {
+	namespace, err := scanKVNamespace(wd.db.QueryRow(`SELECT `+kvNamespaceColumns+` FROM server_workflow_kv_namespaces WHERE id = $1`, namespaceID))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := wd.db.Query(`SELECT workflow_id, access FROM server_workflow_kv_namespace_grants WHERE namespace_id = $1 ORDER BY workflow_id`, namespaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var grant models.WorkflowKVGrant
+		if err := rows.Scan(&grant.WorkflowID, &grant.Access); err != nil {
+			return nil, err
+		}
+		namespace.Grants = append(namespace.Grants, grant)
+	}
+
+	return namespace, rows.Err()
+}
+
+// CreateKVNamespace creates a shared namespace with its grants
+func (wd *WorkflowDatabase) CreateKVNamespace(namespace *models.WorkflowKVNamespace) error {
+	tx, err := wd.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO server_workflow_kv_namespaces (id, server_id, name, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, namespace.ID, namespace.ServerID, namespace.Name, namespace.Description, namespace.CreatedAt, namespace.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := insertKVNamespaceGrants(tx, namespace); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateKVNamespace saves a namespace's description and replaces its grants
+func (wd *WorkflowDatabase) UpdateKVNamespace(namespace *models.WorkflowKVNamespace) error {
+	tx, err := wd.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		UPDATE server_workflow_kv_namespaces
+		SET description = $2, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`, namespace.ID, namespace.Description).Scan(&namespace.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM server_workflow_kv_namespace_grants WHERE namespace_id = $1`, namespace.ID); err != nil {
+		return err
+	}
+	if err := insertKVNamespaceGrants(tx, namespace); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insertKVNamespaceGrants(tx *sql.Tx, namespace *models.WorkflowKVNamespace) error {
+	for _, grant := range namespace.Grants {
+		_, err := tx.Exec(`
+			INSERT INTO server_workflow_kv_namespace_grants (namespace_id, workflow_id, access)
+			VALUES ($1, $2, $3)
+		`, namespace.ID, grant.WorkflowID, grant.Access)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteKVNamespace deletes a shared namespace, its grants and its entries
+func (wd *WorkflowDatabase) DeleteKVNamespace(namespaceID uuid.UUID) error {
+	_, err := wd.db.Exec(`DELETE FROM server_workflow_kv_namespaces WHERE id = $1`, namespaceID)
+	return err
+}
+
+// KVNamespaceAccess returns the namespace called name on a server and the
+// access workflowID has to it. sql.ErrNoRows means the namespace does not
+// exist or the workflow was not granted access.
+func (wd *WorkflowDatabase) KVNamespaceAccess(serverID uuid.UUID, name string, workflowID uuid.UUID) (uuid.UUID, string, error) {
+	var namespaceID uuid.UUID
+	var access string
+	err := wd.db.QueryRow(`
+		SELECT n.id, g.access
+		FROM server_workflow_kv_namespaces n
+		JOIN server_workflow_kv_namespace_grants g ON g.namespace_id = n.id
+		WHERE n.server_id = $1 AND n.name = $2 AND g.workflow_id = $3
+	`, serverID, name, workflowID).Scan(&namespaceID, &access)
+	return namespaceID, access, err
+}
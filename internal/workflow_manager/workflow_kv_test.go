@@ -0,0 +1,93 @@
+package workflow_manager
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestIncrementKVValue(t *testing.T) {
+	max := 3.0
+	min := 0.0
+	bounds := KVBounds{Min: &min, Max: &max}
+
+	if value, err := incrementKVValue(nil, false, 1, bounds); err != nil || value != 1 {
+		t.Errorf("expected a missing key to start from 0, got %v, %v", value, err)
+	}
+	if value, err := incrementKVValue(float64(2), true, 1, bounds); err != nil || value != 3 {
+		t.Errorf("expected to reach the maximum, got %v, %v", value, err)
+	}
+	if _, err := incrementKVValue(float64(3), true, 1, bounds); !errors.Is(err, ErrKVOutOfBounds) {
+		t.Errorf("expected passing the maximum to fail, got %v", err)
+	}
+	if _, err := incrementKVValue(float64(0), true, -1, bounds); !errors.Is(err, ErrKVOutOfBounds) {
+		t.Errorf("expected passing the minimum to fail, got %v", err)
+	}
+	if _, err := incrementKVValue("five", true, 1, KVBounds{}); !errors.Is(err, ErrKVNotNumber) {
+		t.Errorf("expected a non-number to fail, got %v", err)
+	}
+}
+
+func TestKVValuesEqual(t *testing.T) {
+	stored := map[string]interface{}{"count": float64(2), "tags": []interface{}{"a", "b"}}
+
+	if !kvValuesEqual(stored, map[string]interface{}{"tags": []interface{}{"a", "b"}, "count": 2}) {
+		t.Error("expected equal JSON values to match regardless of key order and number type")
+	}
+	if kvValuesEqual(stored, map[string]interface{}{"count": float64(3), "tags": []interface{}{"a", "b"}}) {
+		t.Error("expected different values not to match")
+	}
+	if kvValuesEqual("1", float64(1)) {
+		t.Error("expected a string not to match a number")
+	}
+}
+
+func TestKVStoreSQL(t *testing.T) {
+	wd := &WorkflowDatabase{}
+	namespace := wd.NamespaceKV(uuid.New())
+
+	query := namespace.sql(`SELECT value FROM {table} WHERE {owner} = $1`)
+	if query != `SELECT value FROM server_workflow_kv_namespace_entries WHERE namespace_id = $1` {
+		t.Errorf("unexpected namespace query %q", query)
+	}
+	if query := wd.WorkflowKV(uuid.New()).sql(kvUpsert); !strings.Contains(query, "ON CONFLICT (workflow_id, key)") {
+		t.Errorf("unexpected workflow upsert %q", query)
+	}
+}
+
+func TestValidKVNamespaceName(t *testing.T) {
+	for _, name := range []string{"cooldowns", "team-1.votes", "shared_state"} {
+		if !ValidKVNamespaceName(name) {
+			t.Errorf("expected %q to be valid", name)
+		}
+	}
+	for _, name := range []string{"", "Cooldowns", "-leading", "has space", strings.Repeat("a", 101)} {
+		if ValidKVNamespaceName(name) {
+			t.Errorf("expected %q to be invalid", name)
+		}
+	}
+}
+
+func TestLuaModuleTestKVMocks(t *testing.T) {
+	wm := &WorkflowManager{}
+
+	testSource := `
+		function test_atomic_mocks()
+			assert_equal(workflow.kv.compare_and_set("lock", nil, "owner"), true)
+			assert_equal(workflow.kv.compare_and_set("lock", nil, "other"), false)
+			assert_equal(workflow.kv.increment("warns", 1, { max = 1 }), 1)
+			local value, err = workflow.kv.increment("warns", 1, { max = 1 })
+			assert_equal(value, nil)
+			assert(err ~= nil, "expected a bounds error")
+			assert_equal(workflow.kv.namespace("shared").get("lock"), "owner")
+		end
+	`
+
+	report := wm.RunLuaModuleTests(context.Background(), nil, "kvtest", `return {}`, testSource)
+	if report.Error != "" || !report.Passed {
+		t.Fatalf("expected the KV mocks to pass, got %+v", report)
+	}
+}
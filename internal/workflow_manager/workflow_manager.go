@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -41,6 +42,12 @@ type WorkflowManager struct {
 	isRunning        bool
 	subscriber       *event_manager.EventSubscriber
 	banSyncFunc      func(ctx context.Context, serverID uuid.UUID) error
+	waits            map[uuid.UUID]*pendingWait // Executions suspended at a wait_for_event step
+	waitMutex        sync.Mutex
+	gates            map[uuid.UUID]*workflowGate         // Concurrency state per workflow
+	gatedExecutions  map[uuid.UUID]gatedExecution        // Executions holding a gate slot, by execution ID
+	triggerCounts    map[uuid.UUID]*WorkflowTriggerStats // Dropped and coalesced triggers since the last flush
+	gateMutex        sync.Mutex
 }
 
 // NewWorkflowManager creates a new workflow manager
@@ -63,6 +70,10 @@ func NewWorkflowManager(
 		workflowDB:       NewWorkflowDatabase(db),
 		activeWorkflows:  make(map[uuid.UUID]*models.ServerWorkflow),
 		executionContext: make(map[uuid.UUID]*models.WorkflowExecutionContext),
+		waits:            make(map[uuid.UUID]*pendingWait),
+		gates:            make(map[uuid.UUID]*workflowGate),
+		gatedExecutions:  make(map[uuid.UUID]gatedExecution),
+		triggerCounts:    make(map[uuid.UUID]*WorkflowTriggerStats),
 	}
 }
 
@@ -120,6 +131,25 @@ func (wm *WorkflowManager) syncWorkflowBanConfig(serverID uuid.UUID, banID uuid.
 	return nil
 }
 
+// publishWorkflowBan announces a workflow-issued ban once it has been stored
+// and synced to Bans.cfg. steamIDVal and eosIDVal are the values returned by
+// resolveBanTargetIdentifiers.
+func (wm *WorkflowManager) publishWorkflowBan(serverID, banID uuid.UUID, steamIDVal, eosIDVal interface{}, reason string, expiresAt *time.Time) {
+	ban := event_manager.AegisBanData{
+		BanID:  banID.String(),
+		Reason: reason,
+		Action: "created",
+		Source: event_manager.AegisBanSourceWorkflow,
+	}
+	if steamID, ok := steamIDVal.(int64); ok {
+		ban.SteamID = strconv.FormatInt(steamID, 10)
+	}
+	if eosID, ok := eosIDVal.(string); ok {
+		ban.EosID = eosID
+	}
+	wm.eventManager.PublishAegisBan(serverID, ban, expiresAt)
+}
+
 // Start starts the workflow manager
 func (wm *WorkflowManager) Start() error {
 	wm.mutex.Lock()
@@ -136,6 +166,11 @@ func (wm *WorkflowManager) Start() error {
 		return fmt.Errorf("failed to load workflows: %w", err)
 	}
 
+	// Pick up executions that were waiting for an event when Aegis stopped
+	if err := wm.restoreWaits(); err != nil {
+		log.Error().Err(err).Msg("Failed to restore waiting workflow executions")
+	}
+
 	// Subscribe to events - subscribe to all event types by leaving Types empty
 	filter := event_manager.EventFilter{
 		Types: []event_manager.EventType{}, // Empty means all types
@@ -144,6 +179,8 @@ func (wm *WorkflowManager) Start() error {
 
 	// Start event handler goroutine
 	go wm.eventHandler()
+	go wm.triggerStatsLoop()
+	go wm.kvExpiryLoop()
 
 	log.Trace().Str("subscriber_id", wm.subscriber.ID.String()).Msg("Workflow manager subscribed to events")
 
@@ -329,6 +366,18 @@ func (wm *WorkflowManager) Stop() {
 	}
 	wm.executionMutex.Unlock()
 
+	// Waiting executions stay persisted and resume on the next start
+	wm.waitMutex.Lock()
+	for executionID, pending := range wm.waits {
+		pending.timer.Stop()
+		delete(wm.waits, executionID)
+	}
+	wm.waitMutex.Unlock()
+
+	// Debounced triggers are dropped; counts gathered so far are kept
+	wm.stopGates()
+	wm.flushTriggerStats()
+
 	// Unsubscribe from events
 	if wm.subscriber != nil {
 		wm.eventManager.Unsubscribe(wm.subscriber.ID)
@@ -437,6 +486,8 @@ func (wm *WorkflowManager) handleEvent(event event_manager.Event) {
 		return
 	}
 
+	wm.resumeMatchingWaits(event)
+
 	// Find workflows that should be triggered by this event
 	wm.mutex.RLock()
 	var triggeredWorkflows []*models.ServerWorkflow
@@ -466,18 +517,25 @@ func (wm *WorkflowManager) handleEvent(event event_manager.Event) {
 
 	// Execute triggered workflows
 	for _, workflow := range triggeredWorkflows {
-		eventDataMap := wm.convertEventDataToMap(event.Data)
-		eventDataMap["event_type"] = string(event.Type)
-		eventDataMap["event_id"] = event.ID.String()
-		eventDataMap["event_time"] = event.Timestamp.Format(time.RFC3339Nano)
+		eventDataMap := wm.eventDataWithEnvelope(event)
 		log.Debug().
 			Str("workflow_id", workflow.ID.String()).
 			Str("workflow_name", workflow.Name).
 			Msg("Starting workflow execution")
-		go wm.executeWorkflow(workflow, eventDataMap)
+		wm.dispatchWorkflow(workflow, eventDataMap)
 	}
 }
 
+// eventDataWithEnvelope converts an event to the map workflows see, including
+// its type, ID and time
+func (wm *WorkflowManager) eventDataWithEnvelope(event event_manager.Event) map[string]interface{} {
+	eventDataMap := wm.convertEventDataToMap(event.Data)
+	eventDataMap["event_type"] = string(event.Type)
+	eventDataMap["event_id"] = event.ID.String()
+	eventDataMap["event_time"] = event.Timestamp.Format(time.RFC3339Nano)
+	return eventDataMap
+}
+
 // evaluateConditions checks if an event matches workflow trigger conditions
 func (wm *WorkflowManager) evaluateConditions(conditions []models.WorkflowCondition, eventData map[string]interface{}) bool {
 	if len(conditions) == 0 {
@@ -710,8 +768,12 @@ func (wm *WorkflowManager) evaluateRegexOperator(fieldValue, conditionValue inte
 
 // executeWorkflow executes a workflow instance
 func (wm *WorkflowManager) executeWorkflow(workflow *models.ServerWorkflow, triggerEvent map[string]interface{}) {
-	executionID := uuid.New()
+	wm.runWorkflow(uuid.New(), workflow, triggerEvent, nil)
+}
 
+// runWorkflow executes a workflow instance and returns its final context.
+// call is set when another workflow invoked this one through call_workflow.
+func (wm *WorkflowManager) runWorkflow(executionID uuid.UUID, workflow *models.ServerWorkflow, triggerEvent map[string]interface{}, call *workflowCall) (*models.WorkflowExecutionContext, error) {
 	// Create execution context
 	context := &models.WorkflowExecutionContext{
 		ExecutionID:  executionID,
@@ -730,6 +792,15 @@ func (wm *WorkflowManager) executeWorkflow(workflow *models.ServerWorkflow, trig
 	context.Metadata["server_id"] = workflow.ServerID.String()
 	context.Metadata["execution_id"] = executionID.String()
 	context.Metadata["started_at"] = context.StartedAt.Format(time.RFC3339)
+	if workflow.CurrentRevisionID != nil {
+		context.Metadata["revision_id"] = workflow.CurrentRevisionID.String()
+		context.Metadata["revision"] = workflow.Revision
+	}
+	if call != nil {
+		context.Metadata["call_depth"] = call.depth
+		context.Metadata["call_stack"] = call.stack
+		context.Metadata["parent_execution_id"] = call.parentExecutionID.String()
+	}
 
 	// Initialize variables from workflow definition
 	for key, value := range workflow.Definition.Variables {
@@ -743,6 +814,13 @@ func (wm *WorkflowManager) executeWorkflow(workflow *models.ServerWorkflow, trig
 		}
 	}
 
+	// Inputs from a calling workflow override defaults
+	if call != nil {
+		for key, value := range call.inputs {
+			context.Variables[key] = value
+		}
+	}
+
 	// Store execution context
 	wm.executionMutex.Lock()
 	wm.executionContext[executionID] = context
@@ -762,6 +840,7 @@ func (wm *WorkflowManager) executeWorkflow(workflow *models.ServerWorkflow, trig
 		Status:      "RUNNING",
 		TriggerData: triggerEvent,
 		StartedAt:   context.StartedAt,
+		RevisionID:  workflow.CurrentRevisionID,
 	}
 
 	if err := wm.workflowDB.CreateWorkflowExecution(pgExecution); err != nil {
@@ -792,10 +871,27 @@ func (wm *WorkflowManager) executeWorkflow(workflow *models.ServerWorkflow, trig
 	// Execute workflow steps
 	err := wm.executeWorkflowSteps(context, workflow, summary)
 
+	wm.finishExecution(context, workflow, pgExecution, summary, err)
+
+	return context, err
+}
+
+// finishExecution records the outcome of running an execution's steps. An
+// execution suspended by a wait_for_event step is persisted and registered
+// instead of being completed.
+func (wm *WorkflowManager) finishExecution(context *models.WorkflowExecutionContext, workflow *models.ServerWorkflow, pgExecution *models.ServerWorkflowExecution, summary *models.WorkflowExecutionSummary, err error) {
+	executionID := context.ExecutionID
+
 	// Calculate total duration
 	totalDuration := time.Since(context.StartedAt)
 	summary.TotalDurationMs = uint32(totalDuration.Milliseconds())
 
+	var waitErr *workflowWaitError
+	if errors.As(err, &waitErr) {
+		wm.suspendExecution(context, workflow, pgExecution, summary, waitErr.wait)
+		return
+	}
+
 	// Update execution status
 	completedAt := time.Now()
 	if err != nil {
@@ -850,11 +946,19 @@ func (wm *WorkflowManager) executeWorkflow(workflow *models.ServerWorkflow, trig
 	wm.executionMutex.Lock()
 	delete(wm.executionContext, executionID)
 	wm.executionMutex.Unlock()
+
+	wm.releaseGate(executionID)
 }
 
 // executeWorkflowSteps executes the steps of a workflow
 func (wm *WorkflowManager) executeWorkflowSteps(context *models.WorkflowExecutionContext, workflow *models.ServerWorkflow, summary *models.WorkflowExecutionSummary) error {
-	for i, step := range workflow.Definition.Steps {
+	return wm.executeWorkflowStepsFrom(context, workflow, summary, 0)
+}
+
+// executeWorkflowStepsFrom executes the steps of a workflow from index start
+func (wm *WorkflowManager) executeWorkflowStepsFrom(context *models.WorkflowExecutionContext, workflow *models.ServerWorkflow, summary *models.WorkflowExecutionSummary, start int) error {
+	for i := start; i < len(workflow.Definition.Steps); i++ {
+		step := workflow.Definition.Steps[i]
 		if !step.Enabled {
 			summary.SkippedSteps++
 			continue
@@ -885,6 +989,13 @@ func (wm *WorkflowManager) executeWorkflowSteps(context *models.WorkflowExecutio
 		err := wm.executeStep(context, &step, workflow)
 		stepDuration := time.Since(stepStartTime)
 
+		if isWorkflowWaiting(err) {
+			stepOutput, _ := context.StepResults[step.ID].(map[string]interface{})
+			wm.logWorkflowStep(context, workflow, step.Name, strings.ToUpper(step.Type), uint32(i+1), "WAITING",
+				step.Config, stepOutput, nil, uint32(stepDuration.Milliseconds()))
+			return err
+		}
+
 		if err != nil {
 			summary.FailedSteps++
 			errorMsg := err.Error()
@@ -1160,6 +1271,13 @@ func (wm *WorkflowManager) executeWorkflowFromStep(context *models.WorkflowExecu
 		err := wm.executeStep(context, &step, workflow)
 		stepDuration := time.Since(stepStartTime)
 
+		if isWorkflowWaiting(err) {
+			stepOutput, _ := context.StepResults[step.ID].(map[string]interface{})
+			wm.logWorkflowStep(context, workflow, step.Name, strings.ToUpper(step.Type), uint32(i+1), "WAITING",
+				step.Config, stepOutput, nil, uint32(stepDuration.Milliseconds()))
+			return err
+		}
+
 		if err != nil {
 			summary.FailedSteps++
 			errorMsg := err.Error()
@@ -1228,6 +1346,12 @@ func (wm *WorkflowManager) executeSpecificStep(context *models.WorkflowExecution
 		return nil
 	}
 
+	// A suspended execution resumes in the main step list, so waits cannot
+	// run from a condition branch
+	if targetStep.Type == models.StepTypeWait {
+		return fmt.Errorf("wait_for_event step %s cannot be run from a condition branch", stepID)
+	}
+
 	log.Info().
 		Str("execution_id", context.ExecutionID.String()).
 		Str("step_id", stepID).
@@ -1292,6 +1416,8 @@ func (wm *WorkflowManager) executeStep(context *models.WorkflowExecutionContext,
 		return wm.executeVariableStep(context, step)
 	case models.StepTypeDelay:
 		return wm.executeDelayStep(context, step)
+	case models.StepTypeWait:
+		return wm.executeWaitForEventStep(context, step)
 	default:
 		return fmt.Errorf("unsupported step type: %s", step.Type)
 	}
@@ -1331,6 +1457,8 @@ func (wm *WorkflowManager) executeActionStep(context *models.WorkflowExecutionCo
 		return wm.executeSetVariableAction(context, step)
 	case models.ActionTypeLuaScript:
 		return wm.executeLuaAction(context, step)
+	case models.ActionTypeCallWorkflow:
+		return wm.executeCallWorkflowAction(context, step)
 	default:
 		return fmt.Errorf("unsupported action type: %s", actionType)
 	}
@@ -1587,6 +1715,7 @@ func (wm *WorkflowManager) executeBanPlayerAction(context *models.WorkflowExecut
 	if err := wm.syncWorkflowBanConfig(context.ServerID, banID, "workflow ban"); err != nil {
 		return err
 	}
+	wm.publishWorkflowBan(context.ServerID, banID, steamIDVal, eosIDVal, reason, expiresAt)
 
 	kickCommand := fmt.Sprintf("AdminKick \"%s\" %s", sanitizeRCONParam(playerId), sanitizeRCONParam(reason))
 	response, kickErr := wm.rconManager.ExecuteCommand(context.ServerID, kickCommand)
@@ -1780,6 +1909,7 @@ func (wm *WorkflowManager) executeBanPlayerWithEvidenceAction(context *models.Wo
 	if err := wm.syncWorkflowBanConfig(context.ServerID, banID, "workflow evidence ban"); err != nil {
 		return err
 	}
+	wm.publishWorkflowBan(context.ServerID, banID, steamIDVal, eosIDVal, reason, expiresAt)
 
 	// Kick player for immediate enforcement
 	kickCommand := fmt.Sprintf("AdminKick \"%s\" %s", sanitizeRCONParam(playerId), sanitizeRCONParam(reason))
@@ -3203,27 +3333,33 @@ func (wm *WorkflowManager) executeLuaAction(context *models.WorkflowExecutionCon
 	return wm.executeLuaScript(context, step, script)
 }
 
-// executeLuaScript executes a LUA script with access to workflow context
+// executeLuaScript executes a LUA script with access to workflow context.
+// Scripts run in a sandbox that aborts them when they exceed the instruction,
+// memory or time limits resolved for the workflow.
 func (wm *WorkflowManager) executeLuaScript(workflowContext *models.WorkflowExecutionContext, step *models.WorkflowStep, script string) error {
-	L := lua.NewState()
-	defer L.Close()
+	limits := resolveLuaLimits(wm.workflowDefinition(workflowContext.WorkflowID), step)
 
-	// Set timeout for script execution
-	timeout := 30 * time.Second
-	if timeoutConfig, ok := step.Config["timeout_seconds"].(float64); ok && timeoutConfig > 0 {
-		timeout = time.Duration(timeoutConfig) * time.Second
+	parentCtx := wm.ctx
+	if parentCtx == nil {
+		parentCtx = context.Background()
 	}
-
-	// Create a context with timeout
-	scriptCtx, cancel := context.WithTimeout(wm.ctx, timeout)
+	scriptCtx, cancel := context.WithTimeout(parentCtx, limits.Timeout)
 	defer cancel()
 
+	L, budget := newLuaSandbox(scriptCtx, limits)
+	if wm.workflowDB != nil {
+		installLuaRequire(L, wm.workflowDB.luaModuleLoader(&workflowContext.ServerID))
+	}
+
 	// Set up the LUA environment with workflow data
 	if err := wm.setupLuaEnvironment(L, workflowContext, step); err != nil {
+		L.Close()
 		return fmt.Errorf("failed to setup LUA environment: %w", err)
 	}
 
-	// Execute the script in a goroutine to handle timeout
+	// Execute the script in a goroutine so a Go function blocked past the
+	// timeout does not hold up the workflow. The VM itself stops at its next
+	// instruction once the context is done.
 	scriptError := make(chan error, 1)
 	go func() {
 		defer func() {
@@ -3242,17 +3378,41 @@ func (wm *WorkflowManager) executeLuaScript(workflowContext *models.WorkflowExec
 	// Wait for script completion or timeout
 	select {
 	case err := <-scriptError:
+		defer L.Close()
+		if violation := budget.Violation(); violation != nil {
+			message := luaViolationMessage(violation, limits)
+			wm.logWorkflowMessage(workflowContext, step, "ERROR", message)
+			return errors.New(message)
+		}
 		if err != nil {
 			return err
 		}
 	case <-scriptCtx.Done():
-		return fmt.Errorf("LUA script execution timed out after %v", timeout)
+		// The state is closed once the script goroutine has unwound.
+		go func() {
+			<-scriptError
+			L.Close()
+		}()
+		message := fmt.Sprintf("LUA script execution timed out after %v", limits.Timeout)
+		wm.logWorkflowMessage(workflowContext, step, "ERROR", message)
+		return errors.New(message)
 	}
 
 	// Extract results from LUA state
 	return wm.extractLuaResults(L, workflowContext, step)
 }
 
+// workflowDefinition returns the definition of an active workflow, or nil.
+func (wm *WorkflowManager) workflowDefinition(workflowID uuid.UUID) *models.WorkflowDefinition {
+	wm.mutex.RLock()
+	defer wm.mutex.RUnlock()
+
+	if workflow, ok := wm.activeWorkflows[workflowID]; ok {
+		return &workflow.Definition
+	}
+	return nil
+}
+
 // setupLuaEnvironment sets up the LUA environment with workflow context and utilities
 func (wm *WorkflowManager) setupLuaEnvironment(L *lua.LState, workflowContext *models.WorkflowExecutionContext, step *models.WorkflowStep) error {
 	// Create workflow context table
@@ -3424,59 +3584,7 @@ func (wm *WorkflowManager) addLuaUtilityFunctions(L *lua.LState, workflowTable *
 	}))
 	L.SetField(workflowTable, "variable", variableTable)
 
-	// Create workflow.json namespace
-	jsonTable := L.NewTable()
-	L.SetField(jsonTable, "encode", L.NewFunction(func(L *lua.LState) int {
-		value := L.Get(1)
-		goValue := wm.convertFromLuaValue(value)
-
-		if jsonBytes, err := json.Marshal(goValue); err == nil {
-			L.Push(lua.LString(string(jsonBytes)))
-		} else {
-			L.Push(lua.LNil)
-		}
-		return 1
-	}))
-	L.SetField(jsonTable, "decode", L.NewFunction(func(L *lua.LState) int {
-		jsonStr := L.CheckString(1)
-
-		var result interface{}
-		if err := json.Unmarshal([]byte(jsonStr), &result); err == nil {
-			L.Push(wm.convertToLuaValue(L, result))
-		} else {
-			L.Push(lua.LNil)
-		}
-		return 1
-	}))
-	L.SetField(workflowTable, "json", jsonTable)
-
-	// Create workflow.util namespace
-	utilTable := L.NewTable()
-	L.SetField(utilTable, "safe_get", L.NewFunction(func(L *lua.LState) int {
-		table := L.CheckTable(1)
-		key := L.CheckString(2)
-		defaultValue := L.Get(3) // Optional default value
-
-		value := L.GetField(table, key)
-		if value == lua.LNil && defaultValue != lua.LNil {
-			L.Push(defaultValue)
-		} else {
-			L.Push(value)
-		}
-		return 1
-	}))
-	L.SetField(utilTable, "to_string", L.NewFunction(func(L *lua.LState) int {
-		value := L.Get(1)
-		defaultStr := L.OptString(2, "")
-
-		if value == lua.LNil {
-			L.Push(lua.LString(defaultStr))
-		} else {
-			L.Push(lua.LString(value.String()))
-		}
-		return 1
-	}))
-	L.SetField(workflowTable, "util", utilTable)
+	wm.addLuaDataFunctions(L, workflowTable)
 
 	// Create workflow.rcon namespace
 	rconTable := L.NewTable()
@@ -3584,6 +3692,7 @@ func (wm *WorkflowManager) addLuaUtilityFunctions(L *lua.LState, workflowTable *
 			L.Push(lua.LString(err.Error()))
 			return 2
 		}
+		wm.publishWorkflowBan(workflowContext.ServerID, banID, luaBanSteamIDVal, luaBanEosIDVal, reason, luaBanExpiresAt)
 
 		kickCommand := fmt.Sprintf("AdminKick \"%s\" %s", sanitizeRCONParam(playerId), sanitizeRCONParam(reason))
 		response, err := wm.rconManager.ExecuteCommand(workflowContext.ServerID, kickCommand)
@@ -3760,6 +3869,7 @@ func (wm *WorkflowManager) addLuaUtilityFunctions(L *lua.LState, workflowTable *
 			L.Push(lua.LString(err.Error()))
 			return 2
 		}
+		wm.publishWorkflowBan(workflowContext.ServerID, banID, steamIDVal, eosIDVal, reason, luaExpiresAt)
 
 		// Kick player for immediate enforcement
 		kickCommand := fmt.Sprintf("AdminKick \"%s\" %s", sanitizeRCONParam(steamID), sanitizeRCONParam(reason))
@@ -3828,257 +3938,71 @@ func (wm *WorkflowManager) addLuaUtilityFunctions(L *lua.LState, workflowTable *
 	L.SetField(workflowTable, "rcon", rconTable)
 
 	// Create workflow.kv namespace - Persistent key-value storage for this workflow
-	kvTable := L.NewTable()
-	L.SetField(kvTable, "get", L.NewFunction(func(L *lua.LState) int {
-		key := L.CheckString(1)
-		defaultValue := L.Get(2) // Optional default value
-
-		value, err := wm.workflowDB.GetKVValue(workflowContext.WorkflowID, key)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				// Key doesn't exist, return default value
-				if defaultValue != lua.LNil {
-					L.Push(defaultValue)
-				} else {
-					L.Push(lua.LNil)
-				}
-				return 1
-			}
-			// Other error occurred
-			log.Error().
-				Err(err).
-				Str("execution_id", workflowContext.ExecutionID.String()).
-				Str("workflow_id", workflowContext.WorkflowID.String()).
-				Str("key", key).
-				Msg("LUA kv.get failed")
-			L.Push(lua.LNil)
-			return 1
-		}
-
-		L.Push(wm.convertToLuaValue(L, value))
-		return 1
+	kvTable := wm.newLuaKVTable(L, workflowContext, wm.workflowDB.WorkflowKV(workflowContext.WorkflowID), "", true)
+	L.SetField(kvTable, "namespace", L.NewFunction(func(L *lua.LState) int {
+		return wm.openLuaKVNamespace(L, workflowContext)
 	}))
-	L.SetField(kvTable, "set", L.NewFunction(func(L *lua.LState) int {
-		key := L.CheckString(1)
-		value := L.Get(2)
+	L.SetField(workflowTable, "kv", kvTable)
 
-		if value == lua.LNil {
-			L.Push(lua.LBool(false))
-			L.Push(lua.LString("cannot set nil value, use kv.delete to remove keys"))
-			return 2
-		}
+	return nil
+}
 
+// addLuaDataFunctions adds the workflow.json and workflow.util namespaces,
+// which have no side effects and are shared with module test runs.
+func (wm *WorkflowManager) addLuaDataFunctions(L *lua.LState, workflowTable *lua.LTable) {
+	// Create workflow.json namespace
+	jsonTable := L.NewTable()
+	L.SetField(jsonTable, "encode", L.NewFunction(func(L *lua.LState) int {
+		value := L.Get(1)
 		goValue := wm.convertFromLuaValue(value)
 
-		err := wm.workflowDB.SetKVValue(workflowContext.WorkflowID, key, goValue)
-		if err != nil {
-			log.Error().
-				Err(err).
-				Str("execution_id", workflowContext.ExecutionID.String()).
-				Str("workflow_id", workflowContext.WorkflowID.String()).
-				Str("key", key).
-				Msg("LUA kv.set failed")
-			L.Push(lua.LBool(false))
-			L.Push(lua.LString(err.Error()))
-			return 2
-		}
-
-		log.Debug().
-			Str("execution_id", workflowContext.ExecutionID.String()).
-			Str("workflow_id", workflowContext.WorkflowID.String()).
-			Str("key", key).
-			Interface("value", goValue).
-			Msg("LUA script set KV store value")
-
-		L.Push(lua.LBool(true))
-		L.Push(lua.LNil)
-		return 2 // Return success boolean and error
-	}))
-	L.SetField(kvTable, "delete", L.NewFunction(func(L *lua.LState) int {
-		key := L.CheckString(1)
-
-		err := wm.workflowDB.DeleteKVValue(workflowContext.WorkflowID, key)
-		if err != nil {
-			log.Error().
-				Err(err).
-				Str("execution_id", workflowContext.ExecutionID.String()).
-				Str("workflow_id", workflowContext.WorkflowID.String()).
-				Str("key", key).
-				Msg("LUA kv.delete failed")
-			L.Push(lua.LBool(false))
-			L.Push(lua.LString(err.Error()))
-			return 2
-		}
-
-		log.Debug().
-			Str("execution_id", workflowContext.ExecutionID.String()).
-			Str("workflow_id", workflowContext.WorkflowID.String()).
-			Str("key", key).
-			Msg("LUA script deleted KV store key")
-
-		L.Push(lua.LBool(true))
-		L.Push(lua.LNil)
-		return 2 // Return success boolean and error
-	}))
-	L.SetField(kvTable, "exists", L.NewFunction(func(L *lua.LState) int {
-		key := L.CheckString(1)
-
-		exists, err := wm.workflowDB.KVExists(workflowContext.WorkflowID, key)
-		if err != nil {
-			log.Error().
-				Err(err).
-				Str("execution_id", workflowContext.ExecutionID.String()).
-				Str("workflow_id", workflowContext.WorkflowID.String()).
-				Str("key", key).
-				Msg("LUA kv.exists failed")
-			L.Push(lua.LBool(false))
-			return 1
-		}
-
-		L.Push(lua.LBool(exists))
-		return 1
-	}))
-	L.SetField(kvTable, "keys", L.NewFunction(func(L *lua.LState) int {
-		keys, err := wm.workflowDB.ListKVKeys(workflowContext.WorkflowID)
-		if err != nil {
-			log.Error().
-				Err(err).
-				Str("execution_id", workflowContext.ExecutionID.String()).
-				Str("workflow_id", workflowContext.WorkflowID.String()).
-				Msg("LUA kv.keys failed")
+		if jsonBytes, err := json.Marshal(goValue); err == nil {
+			L.Push(lua.LString(string(jsonBytes)))
+		} else {
 			L.Push(lua.LNil)
-			return 1
 		}
-
-		// Create a Lua table with the keys
-		table := L.NewTable()
-		for i, key := range keys {
-			table.RawSetInt(i+1, lua.LString(key)) // Lua arrays are 1-indexed
-		}
-
-		L.Push(table)
 		return 1
 	}))
-	L.SetField(kvTable, "get_all", L.NewFunction(func(L *lua.LState) int {
-		kvPairs, err := wm.workflowDB.GetAllKVPairs(workflowContext.WorkflowID)
-		if err != nil {
-			log.Error().
-				Err(err).
-				Str("execution_id", workflowContext.ExecutionID.String()).
-				Str("workflow_id", workflowContext.WorkflowID.String()).
-				Msg("LUA kv.get_all failed")
-			L.Push(lua.LNil)
-			return 1
-		}
+	L.SetField(jsonTable, "decode", L.NewFunction(func(L *lua.LState) int {
+		jsonStr := L.CheckString(1)
 
-		// Create a Lua table with the key-value pairs
-		table := L.NewTable()
-		for key, value := range kvPairs {
-			L.SetField(table, key, wm.convertToLuaValue(L, value))
+		var result interface{}
+		if err := json.Unmarshal([]byte(jsonStr), &result); err == nil {
+			L.Push(wm.convertToLuaValue(L, result))
+		} else {
+			L.Push(lua.LNil)
 		}
-
-		L.Push(table)
 		return 1
 	}))
-	L.SetField(kvTable, "clear", L.NewFunction(func(L *lua.LState) int {
-		err := wm.workflowDB.ClearKVStore(workflowContext.WorkflowID)
-		if err != nil {
-			log.Error().
-				Err(err).
-				Str("execution_id", workflowContext.ExecutionID.String()).
-				Str("workflow_id", workflowContext.WorkflowID.String()).
-				Msg("LUA kv.clear failed")
-			L.Push(lua.LBool(false))
-			L.Push(lua.LString(err.Error()))
-			return 2
-		}
+	L.SetField(workflowTable, "json", jsonTable)
 
-		log.Info().
-			Str("execution_id", workflowContext.ExecutionID.String()).
-			Str("workflow_id", workflowContext.WorkflowID.String()).
-			Msg("LUA script cleared KV store")
+	// Create workflow.util namespace
+	utilTable := L.NewTable()
+	L.SetField(utilTable, "safe_get", L.NewFunction(func(L *lua.LState) int {
+		table := L.CheckTable(1)
+		key := L.CheckString(2)
+		defaultValue := L.Get(3) // Optional default value
 
-		L.Push(lua.LBool(true))
-		L.Push(lua.LNil)
-		return 2 // Return success boolean and error
-	}))
-	L.SetField(kvTable, "count", L.NewFunction(func(L *lua.LState) int {
-		count, err := wm.workflowDB.CountKVPairs(workflowContext.WorkflowID)
-		if err != nil {
-			log.Error().
-				Err(err).
-				Str("execution_id", workflowContext.ExecutionID.String()).
-				Str("workflow_id", workflowContext.WorkflowID.String()).
-				Msg("LUA kv.count failed")
-			L.Push(lua.LNumber(0))
-			return 1
+		value := L.GetField(table, key)
+		if value == lua.LNil && defaultValue != lua.LNil {
+			L.Push(defaultValue)
+		} else {
+			L.Push(value)
 		}
-
-		L.Push(lua.LNumber(count))
 		return 1
 	}))
-	L.SetField(kvTable, "increment", L.NewFunction(func(L *lua.LState) int {
-		key := L.CheckString(1)
-		delta := L.OptNumber(2, 1) // Default increment by 1
+	L.SetField(utilTable, "to_string", L.NewFunction(func(L *lua.LState) int {
+		value := L.Get(1)
+		defaultStr := L.OptString(2, "")
 
-		// Get current value
-		value, err := wm.workflowDB.GetKVValue(workflowContext.WorkflowID, key)
-		var currentNum float64
-		if err != nil {
-			if err == sql.ErrNoRows {
-				// Key doesn't exist, start from 0
-				currentNum = 0
-			} else {
-				log.Error().
-					Err(err).
-					Str("execution_id", workflowContext.ExecutionID.String()).
-					Str("workflow_id", workflowContext.WorkflowID.String()).
-					Str("key", key).
-					Msg("LUA kv.increment failed to get value")
-				L.Push(lua.LNil)
-				L.Push(lua.LString(err.Error()))
-				return 2
-			}
+		if value == lua.LNil {
+			L.Push(lua.LString(defaultStr))
 		} else {
-			// Try to convert to number
-			switch v := value.(type) {
-			case float64:
-				currentNum = v
-			case int:
-				currentNum = float64(v)
-			case int64:
-				currentNum = float64(v)
-			default:
-				L.Push(lua.LNil)
-				L.Push(lua.LString("existing value is not a number"))
-				return 2
-			}
-		}
-
-		// Increment
-		newNum := currentNum + float64(delta)
-
-		// Save back
-		err = wm.workflowDB.SetKVValue(workflowContext.WorkflowID, key, newNum)
-		if err != nil {
-			log.Error().
-				Err(err).
-				Str("execution_id", workflowContext.ExecutionID.String()).
-				Str("workflow_id", workflowContext.WorkflowID.String()).
-				Str("key", key).
-				Msg("LUA kv.increment failed to set value")
-			L.Push(lua.LNil)
-			L.Push(lua.LString(err.Error()))
-			return 2
+			L.Push(lua.LString(value.String()))
 		}
-
-		L.Push(lua.LNumber(newNum))
-		L.Push(lua.LNil)
-		return 2 // Return new value and error
+		return 1
 	}))
-	L.SetField(workflowTable, "kv", kvTable)
-
-	return nil
+	L.SetField(workflowTable, "util", utilTable)
 }
 
 // addLuaBackwardCompatibilityFunctions adds deprecated global functions for backward compatibility
@@ -4254,6 +4178,7 @@ func (wm *WorkflowManager) addLuaBackwardCompatibilityFunctions(L *lua.LState, w
 			L.Push(lua.LString(err.Error()))
 			return 2
 		}
+		wm.publishWorkflowBan(workflowContext.ServerID, banID, deprecatedSteamIDVal, deprecatedEosIDVal, reason, deprecatedExpiresAt)
 
 		kickCommand := fmt.Sprintf("AdminKick \"%s\" %s", sanitizeRCONParam(playerId), sanitizeRCONParam(reason))
 		response, err := wm.rconManager.ExecuteCommand(workflowContext.ServerID, kickCommand)
@@ -0,0 +1,308 @@
+package workflow_manager
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+// Workflow revision operations
+
+// insertWorkflowRevision appends the workflow's current name, description
+// and definition as its next revision and points the workflow at it.
+func insertWorkflowRevision(tx *sql.Tx, workflow *models.ServerWorkflow, definitionJSON []byte, authorID uuid.UUID, changeNote *string, restoredFrom *uuid.UUID) (*models.ServerWorkflowRevision, error) {
+	revision := &models.ServerWorkflowRevision{
+		ID:           uuid.New(),
+		WorkflowID:   workflow.ID,
+		Name:         workflow.Name,
+		Description:  workflow.Description,
+		Definition:   workflow.Definition,
+		ChangeNote:   changeNote,
+		RestoredFrom: restoredFrom,
+		CreatedBy:    &authorID,
+	}
+
+	err := tx.QueryRow(`
+		INSERT INTO server_workflow_revisions (id, workflow_id, revision, name, description, definition, change_note, restored_from, created_by, created_at)
+		SELECT $1, $2, COALESCE(MAX(revision), 0) + 1, $3, $4, $5, $6, $7, $8, NOW()
+		FROM server_workflow_revisions
+		WHERE workflow_id = $2
+		RETURNING revision, created_at
+	`,
+		revision.ID,
+		revision.WorkflowID,
+		revision.Name,
+		revision.Description,
+		definitionJSON,
+		revision.ChangeNote,
+		revision.RestoredFrom,
+		revision.CreatedBy,
+	).Scan(&revision.Revision, &revision.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workflow revision: %w", err)
+	}
+
+	if _, err := tx.Exec(`UPDATE server_workflows SET current_revision_id = $1 WHERE id = $2`, revision.ID, workflow.ID); err != nil {
+		return nil, err
+	}
+
+	workflow.CurrentRevisionID = &revision.ID
+	workflow.Revision = revision.Revision
+	return revision, nil
+}
+
+// UpdateWorkflowWithRevision saves a workflow and records the result as a new
+// revision. restoredFrom marks a rollback.
+func (wd *WorkflowDatabase) UpdateWorkflowWithRevision(workflow *models.ServerWorkflow, authorID uuid.UUID, changeNote *string, restoredFrom *uuid.UUID) (*models.ServerWorkflowRevision, error) {
+	definitionJSON, err := json.Marshal(workflow.Definition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal workflow definition: %w", err)
+	}
+
+	tx, err := wd.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Serialize concurrent saves so revision numbers stay sequential
+	if _, err := tx.Exec(`SELECT 1 FROM server_workflows WHERE id = $1 FOR UPDATE`, workflow.ID); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE server_workflows
+		SET name = $1, description = $2, enabled = $3, definition = $4, updated_at = $5
+		WHERE id = $6
+	`,
+		workflow.Name,
+		workflow.Description,
+		workflow.Enabled,
+		definitionJSON,
+		workflow.UpdatedAt,
+		workflow.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := insertWorkflowRevision(tx, workflow, definitionJSON, authorID, changeNote, restoredFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	return revision, tx.Commit()
+}
+
+const workflowRevisionColumns = `id, workflow_id, revision, name, description, definition, change_note, restored_from, created_by, created_at`
+
+func scanWorkflowRevision(row interface{ Scan(...any) error }) (*models.ServerWorkflowRevision, error) {
+	var revision models.ServerWorkflowRevision
+	var description, changeNote sql.NullString
+	var definitionJSON []byte
+
+	if err := row.Scan(
+		&revision.ID,
+		&revision.WorkflowID,
+		&revision.Revision,
+		&revision.Name,
+		&description,
+		&definitionJSON,
+		&changeNote,
+		&revision.RestoredFrom,
+		&revision.CreatedBy,
+		&revision.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if description.Valid {
+		revision.Description = &description.String
+	}
+	if changeNote.Valid {
+		revision.ChangeNote = &changeNote.String
+	}
+	if err := json.Unmarshal(definitionJSON, &revision.Definition); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal workflow definition for revision %s: %w", revision.ID, err)
+	}
+
+	return &revision, nil
+}
+
+// ListWorkflowRevisions lists a workflow's revisions, newest first
+func (wd *WorkflowDatabase) ListWorkflowRevisions(workflowID uuid.UUID, limit, offset int) ([]models.ServerWorkflowRevision, error) {
+	rows, err := wd.db.Query(`
+		SELECT `+workflowRevisionColumns+`
+		FROM server_workflow_revisions
+		WHERE workflow_id = $1
+		ORDER BY revision DESC
+		LIMIT $2 OFFSET $3
+	`, workflowID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []models.ServerWorkflowRevision{}
+	for rows.Next() {
+		revision, err := scanWorkflowRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *revision)
+	}
+
+	return revisions, rows.Err()
+}
+
+// GetWorkflowRevision retrieves a revision of a workflow by ID
+func (wd *WorkflowDatabase) GetWorkflowRevision(workflowID, revisionID uuid.UUID) (*models.ServerWorkflowRevision, error) {
+	return scanWorkflowRevision(wd.db.QueryRow(`
+		SELECT `+workflowRevisionColumns+`
+		FROM server_workflow_revisions
+		WHERE workflow_id = $1 AND id = $2
+	`, workflowID, revisionID))
+}
+
+// WorkflowRevisionContent returns the revisioned parts of a workflow (name,
+// description and definition) as a string that changes whenever they do.
+func WorkflowRevisionContent(workflow *models.ServerWorkflow) string {
+	content, _ := json.Marshal(map[string]interface{}{
+		"name":        workflow.Name,
+		"description": workflow.Description,
+		"definition":  workflow.Definition,
+	})
+	return string(content)
+}
+
+// DiffWorkflowRevisions lists the differences between two revisions' names,
+// descriptions and definitions, sorted by path.
+func DiffWorkflowRevisions(from, to *models.ServerWorkflowRevision) ([]models.WorkflowRevisionChange, error) {
+	fromDoc, err := revisionDocument(from)
+	if err != nil {
+		return nil, err
+	}
+	toDoc, err := revisionDocument(to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []models.WorkflowRevisionChange{}
+	diffValues("", fromDoc, toDoc, &changes)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// revisionDocument converts the compared parts of a revision to plain JSON
+// values.
+func revisionDocument(revision *models.ServerWorkflowRevision) (map[string]interface{}, error) {
+	raw, err := json.Marshal(map[string]interface{}{
+		"name":        revision.Name,
+		"description": revision.Description,
+		"definition":  revision.Definition,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func diffValues(path string, from, to interface{}, changes *[]models.WorkflowRevisionChange) {
+	fromMap, fromIsMap := from.(map[string]interface{})
+	toMap, toIsMap := to.(map[string]interface{})
+	if fromIsMap && toIsMap {
+		diffMaps(path, fromMap, toMap, changes)
+		return
+	}
+
+	fromList, fromIsList := from.([]interface{})
+	toList, toIsList := to.([]interface{})
+	if fromIsList && toIsList {
+		if fromKeyed, ok := keyListByID(fromList); ok {
+			if toKeyed, ok := keyListByID(toList); ok {
+				diffMaps(path, fromKeyed, toKeyed, changes)
+				return
+			}
+		}
+		diffLists(path, fromList, toList, changes)
+		return
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		*changes = append(*changes, models.WorkflowRevisionChange{Path: path, Type: "changed", From: from, To: to})
+	}
+}
+
+func diffMaps(path string, from, to map[string]interface{}, changes *[]models.WorkflowRevisionChange) {
+	for key, fromValue := range from {
+		toValue, ok := to[key]
+		if !ok {
+			*changes = append(*changes, models.WorkflowRevisionChange{Path: joinDiffPath(path, key), Type: "removed", From: fromValue})
+			continue
+		}
+		diffValues(joinDiffPath(path, key), fromValue, toValue, changes)
+	}
+	for key, toValue := range to {
+		if _, ok := from[key]; !ok {
+			*changes = append(*changes, models.WorkflowRevisionChange{Path: joinDiffPath(path, key), Type: "added", To: toValue})
+		}
+	}
+}
+
+func diffLists(path string, from, to []interface{}, changes *[]models.WorkflowRevisionChange) {
+	for i := 0; i < len(from) || i < len(to); i++ {
+		itemPath := path + "[" + strconv.Itoa(i) + "]"
+		switch {
+		case i >= len(to):
+			*changes = append(*changes, models.WorkflowRevisionChange{Path: itemPath, Type: "removed", From: from[i]})
+		case i >= len(from):
+			*changes = append(*changes, models.WorkflowRevisionChange{Path: itemPath, Type: "added", To: to[i]})
+		default:
+			diffValues(itemPath, from[i], to[i], changes)
+		}
+	}
+}
+
+// keyListByID keys a list of objects by their "id" field, so reordered or
+// inserted steps show up as such rather than as changes to every later index.
+// It reports false when any item lacks a unique string id.
+func keyListByID(list []interface{}) (map[string]interface{}, bool) {
+	keyed := make(map[string]interface{}, len(list))
+	for _, item := range list {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		id, ok := object["id"].(string)
+		if !ok || id == "" {
+			return nil, false
+		}
+		key := "[id=" + id + "]"
+		if _, duplicate := keyed[key]; duplicate {
+			return nil, false
+		}
+		keyed[key] = item
+	}
+	return keyed, true
+}
+
+func joinDiffPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	if len(key) > 0 && key[0] == '[' {
+		return path + key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,95 @@
+package workflow_manager
+
+import (
+	"testing"
+
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+func TestDiffWorkflowRevisions(t *testing.T) {
+	description := "Greets players"
+	from := &models.ServerWorkflowRevision{
+		Name: "Greeter",
+		Definition: models.WorkflowDefinition{
+			Version:   "1.0",
+			Variables: map[string]interface{}{"greeting": "hi"},
+			Steps: []models.WorkflowStep{
+				{ID: "greet", Name: "Greet", Type: "action", Enabled: true, Config: map[string]interface{}{"message": "hi"}},
+				{ID: "log", Name: "Log", Type: "action", Enabled: true, Config: map[string]interface{}{}},
+			},
+		},
+	}
+	to := &models.ServerWorkflowRevision{
+		Name:        "Greeter",
+		Description: &description,
+		Definition: models.WorkflowDefinition{
+			Version:   "1.0",
+			Variables: map[string]interface{}{"greeting": "hi"},
+			Steps: []models.WorkflowStep{
+				{ID: "wait", Name: "Wait", Type: "delay", Enabled: true, Config: map[string]interface{}{}},
+				{ID: "greet", Name: "Greet", Type: "action", Enabled: true, Config: map[string]interface{}{"message": "hello"}},
+			},
+		},
+	}
+
+	changes, err := DiffWorkflowRevisions(from, to)
+	if err != nil {
+		t.Fatalf("DiffWorkflowRevisions failed: %v", err)
+	}
+
+	want := []models.WorkflowRevisionChange{
+		{Path: "definition.steps[id=greet].config.message", Type: "changed", From: "hi", To: "hello"},
+		{Path: "definition.steps[id=log]", Type: "removed"},
+		{Path: "definition.steps[id=wait]", Type: "added"},
+		{Path: "description", Type: "changed", From: nil, To: description},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %d: %+v", len(want), len(changes), changes)
+	}
+	for i, change := range changes {
+		if change.Path != want[i].Path || change.Type != want[i].Type {
+			t.Errorf("change %d = %s %s, want %s %s", i, change.Type, change.Path, want[i].Type, want[i].Path)
+		}
+		if change.Type == "changed" && (change.From != want[i].From || change.To != want[i].To) {
+			t.Errorf("change %d = %v -> %v, want %v -> %v", i, change.From, change.To, want[i].From, want[i].To)
+		}
+	}
+
+	if changes, _ := DiffWorkflowRevisions(from, from); len(changes) != 0 {
+		t.Errorf("expected no changes between identical revisions, got %+v", changes)
+	}
+}
+
+func TestDiffWorkflowRevisionsFallsBackToIndexes(t *testing.T) {
+	from := &models.ServerWorkflowRevision{
+		Definition: models.WorkflowDefinition{Variables: map[string]interface{}{"ids": []interface{}{"a", "b"}}},
+	}
+	to := &models.ServerWorkflowRevision{
+		Definition: models.WorkflowDefinition{Variables: map[string]interface{}{"ids": []interface{}{"a", "c", "d"}}},
+	}
+
+	changes, err := DiffWorkflowRevisions(from, to)
+	if err != nil {
+		t.Fatalf("DiffWorkflowRevisions failed: %v", err)
+	}
+	if len(changes) != 2 ||
+		changes[0].Path != "definition.variables.ids[1]" || changes[0].Type != "changed" ||
+		changes[1].Path != "definition.variables.ids[2]" || changes[1].Type != "added" {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+}
+
+func TestWorkflowRevisionContentIgnoresEnabled(t *testing.T) {
+	workflow := &models.ServerWorkflow{Name: "Greeter", Enabled: true}
+	before := WorkflowRevisionContent(workflow)
+
+	workflow.Enabled = false
+	if WorkflowRevisionContent(workflow) != before {
+		t.Error("toggling enabled should not change revision content")
+	}
+
+	workflow.Definition.Steps = append(workflow.Definition.Steps, models.WorkflowStep{ID: "greet"})
+	if WorkflowRevisionContent(workflow) == before {
+		t.Error("changing the definition should change revision content")
+	}
+}
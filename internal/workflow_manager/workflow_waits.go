@@ -0,0 +1,498 @@
+package workflow_manager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+// maxWorkflowWait bounds how long a wait_for_event step can suspend an
+// execution
+const maxWorkflowWait = 7 * 24 * time.Hour
+
+// Actions a wait_for_event step can take when it times out
+const (
+	waitTimeoutContinue = "continue"
+	waitTimeoutFail     = "fail"
+	waitTimeoutGoto     = "goto"
+)
+
+// workflowWaitError suspends an execution. wait_for_event steps return it and
+// the step loops pass it up unchanged, so finishExecution can persist the
+// execution instead of completing it.
+type workflowWaitError struct {
+	wait *models.ServerWorkflowWait
+}
+
+func (e *workflowWaitError) Error() string {
+	return fmt.Sprintf("waiting for %s until %s", e.wait.EventType, e.wait.ExpiresAt.Format(time.RFC3339))
+}
+
+// isWorkflowWaiting reports whether err suspended the execution
+func isWorkflowWaiting(err error) bool {
+	var waitErr *workflowWaitError
+	return errors.As(err, &waitErr)
+}
+
+// pendingWait is a registered wait and the timer that times it out
+type pendingWait struct {
+	wait  *models.ServerWorkflowWait
+	timer *time.Timer
+}
+
+// waitTimeoutAction reads what a wait_for_event step does when it times out
+func waitTimeoutAction(step *models.WorkflowStep) (action, gotoStep string, err error) {
+	action, _ = step.Config["on_timeout"].(string)
+	switch action {
+	case "", waitTimeoutContinue:
+		return waitTimeoutContinue, "", nil
+	case waitTimeoutFail:
+		return action, "", nil
+	case waitTimeoutGoto:
+		gotoStep, _ = step.Config["timeout_goto_step"].(string)
+		if gotoStep == "" {
+			return "", "", fmt.Errorf("on_timeout goto requires timeout_goto_step")
+		}
+		return action, gotoStep, nil
+	default:
+		return "", "", fmt.Errorf("invalid on_timeout %q, expected continue, fail or goto", action)
+	}
+}
+
+// executeWaitForEventStep suspends the execution until an event matching the
+// step's type and conditions arrives or the timeout passes. Condition values
+// are resolved against the execution now, so they can refer to its variables
+// and trigger event.
+func (wm *WorkflowManager) executeWaitForEventStep(context *models.WorkflowExecutionContext, step *models.WorkflowStep) error {
+	// Inline condition branch steps run while CurrentStep is the condition
+	if context.CurrentStep != step.ID {
+		return fmt.Errorf("wait_for_event step %s cannot be run from a condition branch", step.ID)
+	}
+
+	eventType, _ := step.Config["event_type"].(string)
+	if eventType == "" {
+		return fmt.Errorf("missing event_type in wait_for_event step config")
+	}
+
+	timeoutSeconds, ok := step.Config["timeout_seconds"].(float64)
+	if !ok || timeoutSeconds <= 0 {
+		return fmt.Errorf("missing or invalid timeout_seconds in wait_for_event step config")
+	}
+	timeout := time.Duration(timeoutSeconds * float64(time.Second))
+	if timeout > maxWorkflowWait {
+		return fmt.Errorf("timeout_seconds may be at most %d", int(maxWorkflowWait.Seconds()))
+	}
+
+	if _, _, err := waitTimeoutAction(step); err != nil {
+		return err
+	}
+
+	var conditions []models.WorkflowCondition
+	if raw, exists := step.Config["conditions"]; exists && raw != nil {
+		if err := roundTripJSON(raw, &conditions); err != nil {
+			return fmt.Errorf("invalid conditions in wait_for_event step config: %w", err)
+		}
+	}
+	for i := range conditions {
+		if str, ok := conditions[i].Value.(string); ok {
+			conditions[i].Value = wm.replaceVariablesWithContext(str, context.Variables, context.TriggerEvent, context.Metadata)
+		}
+	}
+
+	now := time.Now()
+	wait := &models.ServerWorkflowWait{
+		ExecutionID: context.ExecutionID,
+		WorkflowID:  context.WorkflowID,
+		ServerID:    context.ServerID,
+		StepID:      step.ID,
+		EventType:   eventType,
+		Conditions:  conditions,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(timeout),
+	}
+
+	context.StepResults[step.ID] = map[string]interface{}{
+		"status":     "waiting",
+		"event_type": eventType,
+		"expires_at": wait.ExpiresAt.Format(time.RFC3339),
+	}
+
+	return &workflowWaitError{wait: wait}
+}
+
+// suspendExecution marks an execution as waiting, persists what it needs to
+// resume and registers the wait.
+func (wm *WorkflowManager) suspendExecution(context *models.WorkflowExecutionContext, workflow *models.ServerWorkflow, pgExecution *models.ServerWorkflowExecution, summary *models.WorkflowExecutionSummary, wait *models.ServerWorkflowWait) {
+	pgExecution.Status = "WAITING"
+	summary.Status = "WAITING"
+
+	wm.logWorkflowStep(context, workflow, "workflow_waiting", "WORKFLOW", uint32(len(workflow.Definition.Steps)+1), "WAITING",
+		map[string]interface{}{},
+		map[string]interface{}{"event_type": wait.EventType, "expires_at": wait.ExpiresAt.Format(time.RFC3339)}, nil, summary.TotalDurationMs)
+
+	// Mark the execution as waiting before the wait can resume it
+	if err := wm.workflowDB.UpdateWorkflowExecution(pgExecution); err != nil {
+		log.Error().Err(err).Str("execution_id", context.ExecutionID.String()).Msg("Failed to update execution record in PostgreSQL")
+	}
+	if wm.clickhouseClient != nil {
+		if err := wm.clickhouseClient.UpdateWorkflowExecutionSummary(wm.ctx, summary); err != nil {
+			log.Error().Err(err).Str("execution_id", context.ExecutionID.String()).Msg("Failed to update execution summary in ClickHouse")
+		}
+	}
+
+	wm.executionMutex.Lock()
+	delete(wm.executionContext, context.ExecutionID)
+	wm.executionMutex.Unlock()
+
+	wait.Definition = workflow.Definition
+	wait.Context = *context
+	wait.Summary = *summary
+
+	if err := wm.workflowDB.CreateWorkflowWait(wait); err != nil {
+		log.Error().Err(err).Str("execution_id", context.ExecutionID.String()).Msg("Failed to persist waiting workflow execution")
+
+		completedAt := time.Now()
+		errorMsg := fmt.Sprintf("failed to persist wait: %v", err)
+		pgExecution.Status = "FAILED"
+		pgExecution.CompletedAt = &completedAt
+		pgExecution.ErrorMessage = &errorMsg
+		if err := wm.workflowDB.UpdateWorkflowExecution(pgExecution); err != nil {
+			log.Error().Err(err).Str("execution_id", context.ExecutionID.String()).Msg("Failed to update execution record in PostgreSQL")
+		}
+		wm.releaseGate(context.ExecutionID)
+		return
+	}
+
+	log.Debug().
+		Str("execution_id", context.ExecutionID.String()).
+		Str("workflow_id", workflow.ID.String()).
+		Str("event_type", wait.EventType).
+		Time("expires_at", wait.ExpiresAt).
+		Msg("Workflow execution waiting for event")
+
+	wm.registerWait(wait)
+}
+
+// registerWait starts watching for a wait's event and timeout
+func (wm *WorkflowManager) registerWait(wait *models.ServerWorkflowWait) {
+	wm.waitMutex.Lock()
+	defer wm.waitMutex.Unlock()
+
+	// An expired wait times out straight away; the lock holds the timer
+	// back until the wait is registered
+	pending := &pendingWait{wait: wait}
+	pending.timer = time.AfterFunc(time.Until(wait.ExpiresAt), func() {
+		wm.resumeWait(wait.ExecutionID, nil)
+	})
+	wm.waits[wait.ExecutionID] = pending
+}
+
+// restoreWaits registers the waits persisted by earlier runs
+func (wm *WorkflowManager) restoreWaits() error {
+	waits, err := wm.workflowDB.ListWorkflowWaits()
+	if err != nil {
+		return err
+	}
+
+	for i := range waits {
+		wm.holdGate(&waits[i])
+		wm.registerWait(&waits[i])
+	}
+
+	if len(waits) > 0 {
+		log.Info().Msgf("Restored %d waiting workflow executions", len(waits))
+	}
+	return nil
+}
+
+// resumeMatchingWaits resumes the executions waiting for event
+func (wm *WorkflowManager) resumeMatchingWaits(event event_manager.Event) {
+	for _, executionID := range wm.matchingWaits(event) {
+		go wm.resumeWait(executionID, wm.eventDataWithEnvelope(event))
+	}
+}
+
+// matchingWaits lists the executions whose wait event matches
+func (wm *WorkflowManager) matchingWaits(event event_manager.Event) []uuid.UUID {
+	wm.waitMutex.Lock()
+	defer wm.waitMutex.Unlock()
+
+	var matched []uuid.UUID
+	var eventData map[string]interface{}
+	for executionID, pending := range wm.waits {
+		if pending.wait.ServerID != event.ServerID || pending.wait.EventType != string(event.Type) {
+			continue
+		}
+		if eventData == nil {
+			eventData = wm.eventDataWithEnvelope(event)
+		}
+		if wm.evaluateConditions(pending.wait.Conditions, eventData) {
+			matched = append(matched, executionID)
+		}
+	}
+
+	return matched
+}
+
+// resumeWait resumes a waiting execution with the event that matched, or nil
+// when it timed out. Deleting the persisted wait claims it, so each wait
+// resumes once even when an event and the timeout race.
+func (wm *WorkflowManager) resumeWait(executionID uuid.UUID, event map[string]interface{}) {
+	wm.waitMutex.Lock()
+	pending, ok := wm.waits[executionID]
+	if ok {
+		pending.timer.Stop()
+		delete(wm.waits, executionID)
+	}
+	wm.waitMutex.Unlock()
+
+	// When stopping, the persisted wait is left for the next start
+	if !ok || wm.ctx.Err() != nil {
+		return
+	}
+
+	claimed, err := wm.workflowDB.DeleteWorkflowWait(executionID)
+	if err != nil {
+		log.Error().Err(err).Str("execution_id", executionID.String()).Msg("Failed to claim waiting workflow execution")
+		return
+	}
+	if !claimed {
+		// The workflow was deleted while waiting
+		wm.releaseGate(executionID)
+		return
+	}
+
+	wm.continueExecution(pending.wait, event)
+}
+
+// continueExecution runs a resumed execution to completion or its next wait
+func (wm *WorkflowManager) continueExecution(wait *models.ServerWorkflowWait, event map[string]interface{}) {
+	context := &wait.Context
+	summary := &wait.Summary
+	summary.Status = "RUNNING"
+
+	// skipped_steps comes back from JSON as a generic map
+	if skipped, ok := context.Metadata["skipped_steps"].(map[string]interface{}); ok {
+		skippedSteps := make(map[string]bool, len(skipped))
+		for stepID, value := range skipped {
+			skippedSteps[stepID], _ = value.(bool)
+		}
+		context.Metadata["skipped_steps"] = skippedSteps
+	}
+
+	pgExecution := &models.ServerWorkflowExecution{
+		WorkflowID:  wait.WorkflowID,
+		ExecutionID: wait.ExecutionID,
+		Status:      "RUNNING",
+		TriggerData: context.TriggerEvent,
+		StartedAt:   context.StartedAt,
+	}
+
+	wm.mutex.RLock()
+	active := wm.activeWorkflows[wait.WorkflowID]
+	wm.mutex.RUnlock()
+
+	// Resume with the definition the execution started with, even if the
+	// workflow has been edited since
+	var workflow models.ServerWorkflow
+	if active != nil {
+		workflow = *active
+	} else {
+		workflow = models.ServerWorkflow{ID: wait.WorkflowID, ServerID: wait.ServerID, Name: summary.WorkflowName}
+	}
+	workflow.Definition = wait.Definition
+
+	wm.executionMutex.Lock()
+	wm.executionContext[context.ExecutionID] = context
+	wm.executionMutex.Unlock()
+
+	if err := wm.workflowDB.UpdateWorkflowExecution(pgExecution); err != nil {
+		log.Error().Err(err).Str("execution_id", context.ExecutionID.String()).Msg("Failed to update execution record in PostgreSQL")
+	}
+
+	var err error
+	if active == nil {
+		err = fmt.Errorf("workflow was disabled while waiting for %s", wait.EventType)
+	} else {
+		log.Debug().
+			Str("execution_id", context.ExecutionID.String()).
+			Str("workflow_id", workflow.ID.String()).
+			Bool("timed_out", event == nil).
+			Msg("Resuming waiting workflow execution")
+		err = wm.resumeAfterWait(context, &workflow, summary, wait, event)
+	}
+
+	wm.finishExecution(context, &workflow, pgExecution, summary, err)
+}
+
+// resumeAfterWait completes the wait step and runs the steps after it
+func (wm *WorkflowManager) resumeAfterWait(context *models.WorkflowExecutionContext, workflow *models.ServerWorkflow, summary *models.WorkflowExecutionSummary, wait *models.ServerWorkflowWait, event map[string]interface{}) error {
+	index := -1
+	for i, step := range workflow.Definition.Steps {
+		if step.ID == wait.StepID {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return fmt.Errorf("wait step %s not found in workflow", wait.StepID)
+	}
+	step := workflow.Definition.Steps[index]
+
+	waited := time.Since(wait.CreatedAt)
+	result := map[string]interface{}{
+		"event_type": wait.EventType,
+		"waited_ms":  waited.Milliseconds(),
+	}
+	context.CurrentStep = step.ID
+	context.StepResults[step.ID] = result
+
+	if event != nil {
+		result["status"] = "matched"
+		result["event"] = event
+		if storeAs, _ := step.Config["store_as"].(string); storeAs != "" {
+			context.Variables[storeAs] = event
+		}
+
+		summary.CompletedSteps++
+		wm.logWorkflowStep(context, workflow, step.Name, strings.ToUpper(step.Type), uint32(index+1), "COMPLETED",
+			step.Config, result, nil, uint32(waited.Milliseconds()))
+		return wm.executeWorkflowStepsFrom(context, workflow, summary, index+1)
+	}
+
+	result["status"] = "timeout"
+	action, gotoStep, _ := waitTimeoutAction(&step)
+
+	if action == waitTimeoutFail {
+		err := fmt.Errorf("timed out waiting for %s", wait.EventType)
+		errorMsg := err.Error()
+		summary.FailedSteps++
+		wm.logWorkflowStep(context, workflow, step.Name, strings.ToUpper(step.Type), uint32(index+1), "FAILED",
+			step.Config, result, &errorMsg, uint32(waited.Milliseconds()))
+		return err
+	}
+
+	summary.CompletedSteps++
+	wm.logWorkflowStep(context, workflow, step.Name, strings.ToUpper(step.Type), uint32(index+1), "COMPLETED",
+		step.Config, result, nil, uint32(waited.Milliseconds()))
+
+	if action == waitTimeoutGoto {
+		return wm.executeWorkflowFromStep(context, workflow, summary, gotoStep)
+	}
+	return wm.executeWorkflowStepsFrom(context, workflow, summary, index+1)
+}
+
+// Workflow wait operations
+
+// CreateWorkflowWait persists a waiting execution
+func (wd *WorkflowDatabase) CreateWorkflowWait(wait *models.ServerWorkflowWait) error {
+	conditionsJSON, err := json.Marshal(wait.Conditions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal conditions: %w", err)
+	}
+	definitionJSON, err := json.Marshal(wait.Definition)
+	if err != nil {
+		return fmt.Errorf("failed to marshal workflow definition: %w", err)
+	}
+	contextJSON, err := json.Marshal(wait.Context)
+	if err != nil {
+		return fmt.Errorf("failed to marshal execution context: %w", err)
+	}
+	summaryJSON, err := json.Marshal(wait.Summary)
+	if err != nil {
+		return fmt.Errorf("failed to marshal execution summary: %w", err)
+	}
+
+	_, err = wd.db.Exec(`
+		INSERT INTO server_workflow_waits (execution_id, workflow_id, server_id, step_id, event_type, conditions, definition, context, summary, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`,
+		wait.ExecutionID,
+		wait.WorkflowID,
+		wait.ServerID,
+		wait.StepID,
+		wait.EventType,
+		conditionsJSON,
+		definitionJSON,
+		contextJSON,
+		summaryJSON,
+		wait.CreatedAt,
+		wait.ExpiresAt,
+	)
+
+	return err
+}
+
+// DeleteWorkflowWait removes a persisted wait and reports whether it existed
+func (wd *WorkflowDatabase) DeleteWorkflowWait(executionID uuid.UUID) (bool, error) {
+	result, err := wd.db.Exec(`DELETE FROM server_workflow_waits WHERE execution_id = $1`, executionID)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// ListWorkflowWaits retrieves every persisted wait
+func (wd *WorkflowDatabase) ListWorkflowWaits() ([]models.ServerWorkflowWait, error) {
+	rows, err := wd.db.Query(`
+		SELECT execution_id, workflow_id, server_id, step_id, event_type, conditions, definition, context, summary, created_at, expires_at
+		FROM server_workflow_waits
+		ORDER BY expires_at
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	waits := []models.ServerWorkflowWait{}
+	for rows.Next() {
+		var wait models.ServerWorkflowWait
+		var conditionsJSON, definitionJSON, contextJSON, summaryJSON []byte
+
+		err := rows.Scan(
+			&wait.ExecutionID,
+			&wait.WorkflowID,
+			&wait.ServerID,
+			&wait.StepID,
+			&wait.EventType,
+			&conditionsJSON,
+			&definitionJSON,
+			&contextJSON,
+			&summaryJSON,
+			&wait.CreatedAt,
+			&wait.ExpiresAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(conditionsJSON, &wait.Conditions); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal conditions for wait %s: %w", wait.ExecutionID, err)
+		}
+		if err := json.Unmarshal(definitionJSON, &wait.Definition); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal workflow definition for wait %s: %w", wait.ExecutionID, err)
+		}
+		if err := json.Unmarshal(contextJSON, &wait.Context); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal execution context for wait %s: %w", wait.ExecutionID, err)
+		}
+		if err := json.Unmarshal(summaryJSON, &wait.Summary); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal execution summary for wait %s: %w", wait.ExecutionID, err)
+		}
+
+		waits = append(waits, wait)
+	}
+
+	return waits, rows.Err()
+}
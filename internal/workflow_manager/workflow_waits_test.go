@@ -0,0 +1,126 @@
+package workflow_manager
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
+	"go.codycody31.dev/squad-aegis/internal/models"
+)
+
+func waitTestStep(config map[string]interface{}) *models.WorkflowStep {
+	base := map[string]interface{}{
+		"event_type":      "RCON_CHAT_MESSAGE",
+		"timeout_seconds": float64(120),
+		"conditions": []interface{}{
+			map[string]interface{}{"field": "message", "operator": "equals", "value": "!confirm"},
+			map[string]interface{}{"field": "steam_id", "operator": "equals", "value": "${trigger_event.steam_id}"},
+		},
+	}
+	for key, value := range config {
+		base[key] = value
+	}
+	return &models.WorkflowStep{ID: "confirm", Type: models.StepTypeWait, Enabled: true, Config: base}
+}
+
+func waitTestContext() *models.WorkflowExecutionContext {
+	return &models.WorkflowExecutionContext{
+		ExecutionID:  uuid.New(),
+		WorkflowID:   uuid.New(),
+		ServerID:     uuid.New(),
+		TriggerEvent: map[string]interface{}{"steam_id": "76561198000000001"},
+		Variables:    map[string]interface{}{},
+		StepResults:  map[string]interface{}{},
+		CurrentStep:  "confirm",
+	}
+}
+
+func TestExecuteWaitForEventStep(t *testing.T) {
+	wm := &WorkflowManager{}
+	context := waitTestContext()
+
+	err := wm.executeWaitForEventStep(context, waitTestStep(nil))
+	var waitErr *workflowWaitError
+	if !errors.As(err, &waitErr) {
+		t.Fatalf("expected the execution to suspend, got %v", err)
+	}
+
+	wait := waitErr.wait
+	if wait.ExecutionID != context.ExecutionID || wait.StepID != "confirm" || wait.EventType != "RCON_CHAT_MESSAGE" {
+		t.Errorf("unexpected wait %+v", wait)
+	}
+	if wait.Conditions[1].Value != "76561198000000001" {
+		t.Errorf("condition value not resolved against the execution: %v", wait.Conditions[1].Value)
+	}
+	if got := wait.ExpiresAt.Sub(wait.CreatedAt); got != 2*time.Minute {
+		t.Errorf("expected a 2 minute timeout, got %s", got)
+	}
+	if result, _ := context.StepResults["confirm"].(map[string]interface{}); result["status"] != "waiting" {
+		t.Errorf("expected a waiting step result, got %v", context.StepResults["confirm"])
+	}
+}
+
+func TestExecuteWaitForEventStepInvalid(t *testing.T) {
+	wm := &WorkflowManager{}
+
+	tests := map[string]map[string]interface{}{
+		"no event type":      {"event_type": ""},
+		"no timeout":         {"timeout_seconds": nil},
+		"timeout too long":   {"timeout_seconds": float64(8 * 24 * 60 * 60)},
+		"unknown on_timeout": {"on_timeout": "explode"},
+		"goto without step":  {"on_timeout": "goto"},
+	}
+	for name, config := range tests {
+		err := wm.executeWaitForEventStep(waitTestContext(), waitTestStep(config))
+		if err == nil || isWorkflowWaiting(err) {
+			t.Errorf("%s: expected a config error, got %v", name, err)
+		}
+	}
+
+	// Inline branch steps run while the condition step is current
+	context := waitTestContext()
+	context.CurrentStep = "check"
+	if err := wm.executeWaitForEventStep(context, waitTestStep(nil)); err == nil || isWorkflowWaiting(err) {
+		t.Errorf("expected a wait in a condition branch to be rejected, got %v", err)
+	}
+}
+
+func TestMatchingWaits(t *testing.T) {
+	wm := &WorkflowManager{waits: map[uuid.UUID]*pendingWait{}}
+	serverID := uuid.New()
+
+	wait := &models.ServerWorkflowWait{
+		ExecutionID: uuid.New(),
+		ServerID:    serverID,
+		EventType:   string(event_manager.EventTypeRconChatMessage),
+		Conditions: []models.WorkflowCondition{
+			{Field: "message", Operator: models.OperatorEquals, Value: "!confirm"},
+			{Field: "steam_id", Operator: models.OperatorEquals, Value: "76561198000000001"},
+		},
+	}
+	wm.waits[wait.ExecutionID] = &pendingWait{wait: wait}
+
+	chat := func(steamID, message string) event_manager.Event {
+		return event_manager.Event{
+			ID:       uuid.New(),
+			ServerID: serverID,
+			Type:     event_manager.EventTypeRconChatMessage,
+			Data:     &event_manager.RconChatMessageData{SteamID: steamID, Message: message},
+		}
+	}
+
+	if matched := wm.matchingWaits(chat("76561198000000001", "!confirm")); len(matched) != 1 || matched[0] != wait.ExecutionID {
+		t.Errorf("expected the confirmation to match, got %v", matched)
+	}
+	if matched := wm.matchingWaits(chat("76561198000000002", "!confirm")); len(matched) != 0 {
+		t.Errorf("expected another player's confirmation not to match, got %v", matched)
+	}
+
+	other := chat("76561198000000001", "!confirm")
+	other.ServerID = uuid.New()
+	if matched := wm.matchingWaits(other); len(matched) != 0 {
+		t.Errorf("expected an event on another server not to match, got %v", matched)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"sync"
 	"time"
 
@@ -35,6 +36,7 @@ type connectorGRPCServer struct {
 	mu        sync.Mutex
 	runCtx    context.Context
 	runCancel context.CancelFunc
+	hostConn  io.Closer
 }
 
 // GetDefinition returns the static connector definition.
@@ -42,13 +44,33 @@ func (s *connectorGRPCServer) GetDefinition(_ context.Context, _ *connectorrpcpb
 	return connectorDefinitionToProto(s.impl.GetDefinition())
 }
 
-// Initialize runs the connector's Initialize method.
+// Initialize hands connectors that implement HostAPIReceiver a HostAPIs
+// proxy dialed via the broker ID, when the host serves one, and runs the
+// connector's Initialize method.
 func (s *connectorGRPCServer) Initialize(_ context.Context, req *connectorrpcpb.InitializeRequest) (*connectorrpcpb.Empty, error) {
 	cfg, err := decodeJSONMap(req.GetConfigJson())
 	if err != nil {
 		return nil, fmt.Errorf("decode config: %w", err)
 	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	receiver, wantsHost := s.impl.(HostAPIReceiver)
+	if brokerID := req.GetHostApiBrokerId(); brokerID != 0 && wantsHost && s.broker != nil {
+		conn, err := s.broker.Dial(brokerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial host api broker: %w", err)
+		}
+		s.hostConn = conn
+		receiver.SetHostAPIs(newHostAPIsFromConn(conn))
+	}
+
 	if err := s.impl.Initialize(cfg); err != nil {
+		if s.hostConn != nil {
+			_ = s.hostConn.Close()
+			s.hostConn = nil
+		}
 		return nil, err
 	}
 	return &connectorrpcpb.Empty{}, nil
@@ -98,18 +120,25 @@ func (s *connectorGRPCServer) Start(_ context.Context, _ *connectorrpcpb.Empty)
 // rationale; connectors share the same long-running pattern.
 const startReadyTimeout = 2 * time.Second
 
-// Stop cancels the run context and calls Stop.
+// Stop cancels the run context, calls Stop, and closes the host API
+// connection.
 func (s *connectorGRPCServer) Stop(_ context.Context, _ *connectorrpcpb.Empty) (*connectorrpcpb.Empty, error) {
 	s.mu.Lock()
 	cancel := s.runCancel
 	s.runCancel = nil
 	s.runCtx = nil
+	conn := s.hostConn
+	s.hostConn = nil
 	s.mu.Unlock()
 
 	if cancel != nil {
 		cancel()
 	}
-	if err := s.impl.Stop(); err != nil {
+	err := s.impl.Stop()
+	if conn != nil {
+		_ = conn.Close()
+	}
+	if err != nil {
 		return nil, err
 	}
 	return &connectorrpcpb.Empty{}, nil
@@ -188,6 +217,49 @@ type ConnectorGRPCClient struct {
 	conn   *grpc.ClientConn
 }
 
+// StartHostAPIBroker allocates a new broker ID and serves the supplied
+// gRPC server on it. The connector subprocess dials this broker ID from its
+// Initialize implementation. The returned stop function shuts down the
+// host-side listener and is safe to call more than once.
+func (c *ConnectorGRPCClient) StartHostAPIBroker(register func(*grpc.Server)) (uint32, func(), error) {
+	if c.broker == nil {
+		return 0, nil, fmt.Errorf("connector grpc client has no broker")
+	}
+	id := c.broker.NextId()
+
+	var (
+		mu      sync.Mutex
+		server  *grpc.Server
+		stopped bool
+	)
+	go c.broker.AcceptAndServe(id, func(opts []grpc.ServerOption) *grpc.Server {
+		s := grpc.NewServer(opts...)
+		register(s)
+		mu.Lock()
+		server = s
+		closed := stopped
+		mu.Unlock()
+		if closed {
+			s.Stop()
+		}
+		return s
+	})
+
+	var stopOnce sync.Once
+	stop := func() {
+		stopOnce.Do(func() {
+			mu.Lock()
+			stopped = true
+			s := server
+			mu.Unlock()
+			if s != nil {
+				s.Stop()
+			}
+		})
+	}
+	return id, stop, nil
+}
+
 // ctxOrBackground returns ctx if non-nil, else context.Background().
 func ctxOrBackground(ctx context.Context) context.Context {
 	if ctx == nil {
@@ -205,15 +277,17 @@ func (c *ConnectorGRPCClient) GetDefinition(ctx context.Context) (ConnectorDefin
 	return protoToConnectorDefinition(resp)
 }
 
-// Initialize runs the connector's Initialize.
+// Initialize runs the connector's Initialize, passing the broker ID of the
+// host API server when the host started one.
 func (c *ConnectorGRPCClient) Initialize(ctx context.Context, args InitializeArgs) error {
 	encoded, err := encodeJSONMap(args.Config)
 	if err != nil {
 		return fmt.Errorf("encode config: %w", err)
 	}
 	_, err = c.client.Initialize(ctxOrBackground(ctx), &connectorrpcpb.InitializeRequest{
-		ConfigJson: encoded,
-		InstanceId: args.InstanceID,
+		ConfigJson:      encoded,
+		InstanceId:      args.InstanceID,
+		HostApiBrokerId: args.HostAPIBrokerID,
 	})
 	return err
 }
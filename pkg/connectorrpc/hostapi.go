@@ -0,0 +1,134 @@
+package connectorrpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	connectorrpcpb "go.codycody31.dev/squad-aegis/pkg/connectorrpc/proto"
+)
+
+// HostAPIs is the connector-facing surface for calling back into the Aegis
+// host. It is built inside the connector process when Initialize carries a
+// host API broker ID, and handed to connectors that implement
+// HostAPIReceiver. Each API only works when the connector's manifest target
+// declares its capability; the host rejects the others.
+type HostAPIs struct {
+	EventAPI  *EventAPI
+	LogAPI    *LogAPI
+	ServerAPI *ServerAPI
+}
+
+// HostAPIReceiver is implemented by connectors that call the host.
+// SetHostAPIs runs before Initialize.
+type HostAPIReceiver interface {
+	SetHostAPIs(apis *HostAPIs)
+}
+
+// newHostAPIsFromConn builds a HostAPIs around a connection to the host's
+// ConnectorHostAPI service.
+func newHostAPIsFromConn(conn grpc.ClientConnInterface) *HostAPIs {
+	client := connectorrpcpb.NewConnectorHostAPIClient(conn)
+	return &HostAPIs{
+		EventAPI:  &EventAPI{client: client},
+		LogAPI:    &LogAPI{client: client},
+		ServerAPI: &ServerAPI{client: client},
+	}
+}
+
+// -- EventAPI ----------------------------------------------------------------
+
+// EventAPI publishes events into the host's event stream. Requires the
+// api.event capability.
+type EventAPI struct {
+	client connectorrpcpb.ConnectorHostAPIClient
+}
+
+// PublishEvent publishes an event for one server. The host namespaces the
+// type as CONNECTOR_<connector id>_<eventType>, and plugins on that server
+// receive it with the connector event source.
+func (e *EventAPI) PublishEvent(serverID, eventType string, data map[string]interface{}, raw string) error {
+	encoded, err := encodeJSONMap(data)
+	if err != nil {
+		return err
+	}
+	_, err = e.client.PublishEvent(context.Background(), &connectorrpcpb.PublishEventRequest{
+		ServerId:  serverID,
+		EventType: eventType,
+		DataJson:  encoded,
+		Raw:       raw,
+	})
+	return err
+}
+
+// -- LogAPI ------------------------------------------------------------------
+
+// LogAPI writes structured log entries into the host's logger. Requires the
+// api.log capability. Failures are dropped, as with the plugin LogAPI.
+type LogAPI struct {
+	client connectorrpcpb.ConnectorHostAPIClient
+}
+
+func (l *LogAPI) log(level, message string, err error, fields map[string]interface{}) {
+	encoded, encodeErr := encodeJSONMap(fields)
+	if encodeErr != nil {
+		return
+	}
+	req := &connectorrpcpb.LogRequest{
+		Level:      level,
+		Message:    message,
+		FieldsJson: encoded,
+	}
+	if err != nil {
+		req.Error = err.Error()
+	}
+	_, _ = l.client.Log(context.Background(), req)
+}
+
+// Debug writes a debug-level log entry.
+func (l *LogAPI) Debug(message string, fields map[string]interface{}) {
+	l.log("debug", message, nil, fields)
+}
+
+// Info writes an info-level log entry.
+func (l *LogAPI) Info(message string, fields map[string]interface{}) {
+	l.log("info", message, nil, fields)
+}
+
+// Warn writes a warn-level log entry.
+func (l *LogAPI) Warn(message string, fields map[string]interface{}) {
+	l.log("warn", message, nil, fields)
+}
+
+// Error writes an error-level log entry. The err.Error() string is forwarded
+// on the wire; the host re-attaches it to the log record.
+func (l *LogAPI) Error(message string, err error, fields map[string]interface{}) {
+	l.log("error", message, err, fields)
+}
+
+// -- ServerAPI ---------------------------------------------------------------
+
+// ServerSummary identifies a server registered with the host.
+type ServerSummary struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ServerAPI reads the host's server list. Requires the api.server
+// capability.
+type ServerAPI struct {
+	client connectorrpcpb.ConnectorHostAPIClient
+}
+
+// ListServers returns every server registered with the host.
+func (s *ServerAPI) ListServers() ([]ServerSummary, error) {
+	resp, err := s.client.ListServers(context.Background(), &connectorrpcpb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	servers := make([]ServerSummary, 0, len(resp.GetServers()))
+	for _, server := range resp.GetServers() {
+		servers = append(servers, ServerSummary{ID: server.GetId(), Name: server.GetName()})
+	}
+	return servers, nil
+}
@@ -262,10 +262,13 @@ func (x *ConnectorDefinition) GetConfigSchema() *ConfigSchema {
 type InitializeRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// JSON-encoded config map.
-	ConfigJson    []byte `protobuf:"bytes,1,opt,name=config_json,json=configJson,proto3" json:"config_json,omitempty"`
-	InstanceId    string `protobuf:"bytes,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	ConfigJson []byte `protobuf:"bytes,1,opt,name=config_json,json=configJson,proto3" json:"config_json,omitempty"`
+	InstanceId string `protobuf:"bytes,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	// Broker ID of the host's ConnectorHostAPI service. Zero when the host
+	// serves no host API to this connector.
+	HostApiBrokerId uint32 `protobuf:"varint,3,opt,name=host_api_broker_id,json=hostApiBrokerId,proto3" json:"host_api_broker_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *InitializeRequest) Reset() {
@@ -312,6 +315,13 @@ func (x *InitializeRequest) GetInstanceId() string {
 	return ""
 }
 
+func (x *InitializeRequest) GetHostApiBrokerId() uint32 {
+	if x != nil {
+		return x.HostApiBrokerId
+	}
+	return 0
+}
+
 type StatusResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
@@ -549,12 +559,13 @@ const file_pkg_connectorrpc_proto_connector_proto_rawDesc = "" +
 	"\x06fields\x18\x01 \x03(\v2'.squadaegis.connectorrpc.v1.ConfigFieldR\x06fields\"\x87\x01\n" +
 	"\x13ConnectorDefinition\x12!\n" +
 	"\fconnector_id\x18\x01 \x01(\tR\vconnectorId\x12M\n" +
-	"\rconfig_schema\x18\x02 \x01(\v2(.squadaegis.connectorrpc.v1.ConfigSchemaR\fconfigSchema\"U\n" +
+	"\rconfig_schema\x18\x02 \x01(\v2(.squadaegis.connectorrpc.v1.ConfigSchemaR\fconfigSchema\"\x82\x01\n" +
 	"\x11InitializeRequest\x12\x1f\n" +
 	"\vconfig_json\x18\x01 \x01(\fR\n" +
 	"configJson\x12\x1f\n" +
 	"\vinstance_id\x18\x02 \x01(\tR\n" +
-	"instanceId\"(\n" +
+	"instanceId\x12+\n" +
+	"\x12host_api_broker_id\x18\x03 \x01(\rR\x0fhostApiBrokerId\"(\n" +
 	"\x0eStatusResponse\x12\x16\n" +
 	"\x06status\x18\x01 \x01(\tR\x06status\"-\n" +
 	"\n" +
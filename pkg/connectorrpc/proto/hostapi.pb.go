@@ -0,0 +1,353 @@
+// ConnectorHostAPI is the connector-to-host service. The connector
+// subprocess dials the host on the broker ID supplied via
+// Connector.Initialize and then invokes methods on this service. Each
+// method is gated by a capability the connector's manifest target declares;
+// rate limiting and payload caps live in the host's implementation.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v7.34.1
+// source: pkg/connectorrpc/proto/hostapi.proto
+
+package connectorrpcpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PublishEventRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Server the event belongs to. Plugins receive it like any other event
+	// from that server.
+	ServerId  string `protobuf:"bytes,1,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	EventType string `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	// JSON-encoded data map.
+	DataJson      []byte `protobuf:"bytes,3,opt,name=data_json,json=dataJson,proto3" json:"data_json,omitempty"`
+	Raw           string `protobuf:"bytes,4,opt,name=raw,proto3" json:"raw,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishEventRequest) Reset() {
+	*x = PublishEventRequest{}
+	mi := &file_pkg_connectorrpc_proto_hostapi_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishEventRequest) ProtoMessage() {}
+
+func (x *PublishEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_connectorrpc_proto_hostapi_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishEventRequest.ProtoReflect.Descriptor instead.
+func (*PublishEventRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_connectorrpc_proto_hostapi_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PublishEventRequest) GetServerId() string {
+	if x != nil {
+		return x.ServerId
+	}
+	return ""
+}
+
+func (x *PublishEventRequest) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *PublishEventRequest) GetDataJson() []byte {
+	if x != nil {
+		return x.DataJson
+	}
+	return nil
+}
+
+func (x *PublishEventRequest) GetRaw() string {
+	if x != nil {
+		return x.Raw
+	}
+	return ""
+}
+
+type LogRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One of debug, info, warn, error.
+	Level   string `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// JSON-encoded fields map.
+	FieldsJson    []byte `protobuf:"bytes,3,opt,name=fields_json,json=fieldsJson,proto3" json:"fields_json,omitempty"`
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogRequest) Reset() {
+	*x = LogRequest{}
+	mi := &file_pkg_connectorrpc_proto_hostapi_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogRequest) ProtoMessage() {}
+
+func (x *LogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_connectorrpc_proto_hostapi_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogRequest.ProtoReflect.Descriptor instead.
+func (*LogRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_connectorrpc_proto_hostapi_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LogRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *LogRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *LogRequest) GetFieldsJson() []byte {
+	if x != nil {
+		return x.FieldsJson
+	}
+	return nil
+}
+
+func (x *LogRequest) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ServerSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerSummary) Reset() {
+	*x = ServerSummary{}
+	mi := &file_pkg_connectorrpc_proto_hostapi_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerSummary) ProtoMessage() {}
+
+func (x *ServerSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_connectorrpc_proto_hostapi_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerSummary.ProtoReflect.Descriptor instead.
+func (*ServerSummary) Descriptor() ([]byte, []int) {
+	return file_pkg_connectorrpc_proto_hostapi_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ServerSummary) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ServerSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ListServersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Servers       []*ServerSummary       `protobuf:"bytes,1,rep,name=servers,proto3" json:"servers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServersResponse) Reset() {
+	*x = ListServersResponse{}
+	mi := &file_pkg_connectorrpc_proto_hostapi_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServersResponse) ProtoMessage() {}
+
+func (x *ListServersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_connectorrpc_proto_hostapi_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServersResponse.ProtoReflect.Descriptor instead.
+func (*ListServersResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_connectorrpc_proto_hostapi_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListServersResponse) GetServers() []*ServerSummary {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+var File_pkg_connectorrpc_proto_hostapi_proto protoreflect.FileDescriptor
+
+const file_pkg_connectorrpc_proto_hostapi_proto_rawDesc = "" +
+	"\n" +
+	"$pkg/connectorrpc/proto/hostapi.proto\x12\x1asquadaegis.connectorrpc.v1\x1a&pkg/connectorrpc/proto/connector.proto\"\x80\x01\n" +
+	"\x13PublishEventRequest\x12\x1b\n" +
+	"\tserver_id\x18\x01 \x01(\tR\bserverId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\tR\teventType\x12\x1b\n" +
+	"\tdata_json\x18\x03 \x01(\fR\bdataJson\x12\x10\n" +
+	"\x03raw\x18\x04 \x01(\tR\x03raw\"s\n" +
+	"\n" +
+	"LogRequest\x12\x14\n" +
+	"\x05level\x18\x01 \x01(\tR\x05level\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1f\n" +
+	"\vfields_json\x18\x03 \x01(\fR\n" +
+	"fieldsJson\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"3\n" +
+	"\rServerSummary\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"Z\n" +
+	"\x13ListServersResponse\x12C\n" +
+	"\aservers\x18\x01 \x03(\v2).squadaegis.connectorrpc.v1.ServerSummaryR\aservers2\xab\x02\n" +
+	"\x10ConnectorHostAPI\x12b\n" +
+	"\fPublishEvent\x12/.squadaegis.connectorrpc.v1.PublishEventRequest\x1a!.squadaegis.connectorrpc.v1.Empty\x12P\n" +
+	"\x03Log\x12&.squadaegis.connectorrpc.v1.LogRequest\x1a!.squadaegis.connectorrpc.v1.Empty\x12a\n" +
+	"\vListServers\x12!.squadaegis.connectorrpc.v1.Empty\x1a/.squadaegis.connectorrpc.v1.ListServersResponseBEZCgo.codycody31.dev/squad-aegis/pkg/connectorrpc/proto;connectorrpcpbb\x06proto3"
+
+var (
+	file_pkg_connectorrpc_proto_hostapi_proto_rawDescOnce sync.Once
+	file_pkg_connectorrpc_proto_hostapi_proto_rawDescData []byte
+)
+
+func file_pkg_connectorrpc_proto_hostapi_proto_rawDescGZIP() []byte {
+	file_pkg_connectorrpc_proto_hostapi_proto_rawDescOnce.Do(func() {
+		file_pkg_connectorrpc_proto_hostapi_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pkg_connectorrpc_proto_hostapi_proto_rawDesc), len(file_pkg_connectorrpc_proto_hostapi_proto_rawDesc)))
+	})
+	return file_pkg_connectorrpc_proto_hostapi_proto_rawDescData
+}
+
+var file_pkg_connectorrpc_proto_hostapi_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_pkg_connectorrpc_proto_hostapi_proto_goTypes = []any{
+	(*PublishEventRequest)(nil), // 0: squadaegis.connectorrpc.v1.PublishEventRequest
+	(*LogRequest)(nil),          // 1: squadaegis.connectorrpc.v1.LogRequest
+	(*ServerSummary)(nil),       // 2: squadaegis.connectorrpc.v1.ServerSummary
+	(*ListServersResponse)(nil), // 3: squadaegis.connectorrpc.v1.ListServersResponse
+	(*Empty)(nil),               // 4: squadaegis.connectorrpc.v1.Empty
+}
+var file_pkg_connectorrpc_proto_hostapi_proto_depIdxs = []int32{
+	2, // 0: squadaegis.connectorrpc.v1.ListServersResponse.servers:type_name -> squadaegis.connectorrpc.v1.ServerSummary
+	0, // 1: squadaegis.connectorrpc.v1.ConnectorHostAPI.PublishEvent:input_type -> squadaegis.connectorrpc.v1.PublishEventRequest
+	1, // 2: squadaegis.connectorrpc.v1.ConnectorHostAPI.Log:input_type -> squadaegis.connectorrpc.v1.LogRequest
+	4, // 3: squadaegis.connectorrpc.v1.ConnectorHostAPI.ListServers:input_type -> squadaegis.connectorrpc.v1.Empty
+	4, // 4: squadaegis.connectorrpc.v1.ConnectorHostAPI.PublishEvent:output_type -> squadaegis.connectorrpc.v1.Empty
+	4, // 5: squadaegis.connectorrpc.v1.ConnectorHostAPI.Log:output_type -> squadaegis.connectorrpc.v1.Empty
+	3, // 6: squadaegis.connectorrpc.v1.ConnectorHostAPI.ListServers:output_type -> squadaegis.connectorrpc.v1.ListServersResponse
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_pkg_connectorrpc_proto_hostapi_proto_init() }
+func file_pkg_connectorrpc_proto_hostapi_proto_init() {
+	if File_pkg_connectorrpc_proto_hostapi_proto != nil {
+		return
+	}
+	file_pkg_connectorrpc_proto_connector_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_connectorrpc_proto_hostapi_proto_rawDesc), len(file_pkg_connectorrpc_proto_hostapi_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_connectorrpc_proto_hostapi_proto_goTypes,
+		DependencyIndexes: file_pkg_connectorrpc_proto_hostapi_proto_depIdxs,
+		MessageInfos:      file_pkg_connectorrpc_proto_hostapi_proto_msgTypes,
+	}.Build()
+	File_pkg_connectorrpc_proto_hostapi_proto = out.File
+	file_pkg_connectorrpc_proto_hostapi_proto_goTypes = nil
+	file_pkg_connectorrpc_proto_hostapi_proto_depIdxs = nil
+}
@@ -103,6 +103,9 @@ type ConnectorInvokeResponse struct {
 type InitializeArgs struct {
 	Config     map[string]interface{} `json:"config"`
 	InstanceID string                 `json:"instance_id,omitempty"`
+	// HostAPIBrokerID names the host's ConnectorHostAPI server. Zero means
+	// the host serves none.
+	HostAPIBrokerID uint32 `json:"host_api_broker_id,omitempty"`
 }
 
 // InvokeArgs is the RPC payload for Invoke calls.
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	pluginrpcpb "go.codycody31.dev/squad-aegis/pkg/pluginrpc/proto"
@@ -27,13 +28,59 @@ type HostAPIs struct {
 	RuleAPI      *RuleAPI
 	AdminAPI     *AdminAPI
 	EventAPI     *EventAPI
+	HistoryAPI   *HistoryAPI
 	DiscordAPI   *DiscordAPI
 	ConnectorAPI *ConnectorAPI
 }
 
+// ServerIDMetadataKey is the request metadata that names the server a
+// global plugin instance's Rcon* and Server* call targets.
+const ServerIDMetadataKey = "x-aegis-server-id"
+
+// ServerHandle holds RCON and server APIs qualified to one server in a
+// global plugin instance's scope.
+type ServerHandle struct {
+	RconAPI   *RconAPI
+	ServerAPI *ServerAPI
+}
+
+// ForServer returns handles that act on one server in a global instance's
+// scope. The host rejects servers outside the scope; per-server instances
+// keep using RconAPI and ServerAPI directly.
+func (h *HostAPIs) ForServer(serverID string) *ServerHandle {
+	return &ServerHandle{
+		RconAPI:   &RconAPI{client: h.client, serverID: serverID},
+		ServerAPI: &ServerAPI{client: h.client, serverID: serverID},
+	}
+}
+
+// ScopeServerIDs returns the servers a global instance receives events from.
+func (h *HostAPIs) ScopeServerIDs() ([]string, error) {
+	resp, err := h.client.ServerListScopeServers(context.Background(), &pluginrpcpb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	var serverIDs []string
+	if len(resp.GetDataJson()) > 0 {
+		if err := json.Unmarshal(resp.GetDataJson(), &serverIDs); err != nil {
+			return nil, fmt.Errorf("decode scope: %w", err)
+		}
+	}
+	return serverIDs, nil
+}
+
+// serverContext returns a request context that names serverID, or a plain
+// background context when the handle is not server-qualified.
+func serverContext(serverID string) context.Context {
+	if serverID == "" {
+		return context.Background()
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), ServerIDMetadataKey, serverID)
+}
+
 // newHostAPIsFromConn builds a HostAPIs around a gRPC client connection
 // pointing at the host's HostAPI gRPC server. Called once inside Initialize.
-func newHostAPIsFromConn(conn *grpc.ClientConn) *HostAPIs {
+func newHostAPIsFromConn(conn grpc.ClientConnInterface) *HostAPIs {
 	client := pluginrpcpb.NewHostAPIClient(conn)
 	apis := &HostAPIs{client: client}
 	apis.LogAPI = &LogAPI{client: client}
@@ -43,6 +90,7 @@ func newHostAPIsFromConn(conn *grpc.ClientConn) *HostAPIs {
 	apis.RuleAPI = &RuleAPI{client: client}
 	apis.AdminAPI = &AdminAPI{client: client}
 	apis.EventAPI = &EventAPI{client: client}
+	apis.HistoryAPI = &HistoryAPI{client: client}
 	apis.DiscordAPI = &DiscordAPI{client: client}
 	apis.ConnectorAPI = &ConnectorAPI{client: client}
 	return apis
@@ -109,7 +157,10 @@ func (l *LogAPI) Debug(message string, fields map[string]interface{}) {
 // -- RconAPI -----------------------------------------------------------------
 
 // RconAPI exposes the restricted RCON surface available to plugins.
-type RconAPI struct{ client pluginrpcpb.HostAPIClient }
+type RconAPI struct {
+	client   pluginrpcpb.HostAPIClient
+	serverID string
+}
 
 func newRconBanRequest(playerID, reason string, duration time.Duration, eventID, eventType string, ruleID *string, metadata map[string]interface{}) (*pluginrpcpb.RconBanRequest, error) {
 	encoded, err := encodeJSONMap(metadata)
@@ -133,7 +184,7 @@ func newRconBanRequest(playerID, reason string, duration time.Duration, eventID,
 
 // SendCommand runs an RCON command against the server the plugin is scoped to.
 func (r *RconAPI) SendCommand(command string) (string, error) {
-	resp, err := r.client.RconSendCommand(context.Background(), &pluginrpcpb.RconCommandRequest{Command: command})
+	resp, err := r.client.RconSendCommand(serverContext(r.serverID), &pluginrpcpb.RconCommandRequest{Command: command})
 	if err != nil {
 		return "", err
 	}
@@ -142,13 +193,13 @@ func (r *RconAPI) SendCommand(command string) (string, error) {
 
 // Broadcast sends a chat broadcast to every player on the server.
 func (r *RconAPI) Broadcast(message string) error {
-	_, err := r.client.RconBroadcast(context.Background(), &pluginrpcpb.RconBroadcastRequest{Message: message})
+	_, err := r.client.RconBroadcast(serverContext(r.serverID), &pluginrpcpb.RconBroadcastRequest{Message: message})
 	return err
 }
 
 // SendWarningToPlayer sends an in-game warning to a single player.
 func (r *RconAPI) SendWarningToPlayer(playerID, message string) error {
-	_, err := r.client.RconSendWarningToPlayer(context.Background(), &pluginrpcpb.RconWarnPlayerRequest{
+	_, err := r.client.RconSendWarningToPlayer(serverContext(r.serverID), &pluginrpcpb.RconWarnPlayerRequest{
 		PlayerId: playerID,
 		Message:  message,
 	})
@@ -157,7 +208,7 @@ func (r *RconAPI) SendWarningToPlayer(playerID, message string) error {
 
 // KickPlayer kicks a player from the server.
 func (r *RconAPI) KickPlayer(playerID, reason string) error {
-	_, err := r.client.RconKickPlayer(context.Background(), &pluginrpcpb.RconKickRequest{
+	_, err := r.client.RconKickPlayer(serverContext(r.serverID), &pluginrpcpb.RconKickRequest{
 		PlayerId: playerID,
 		Reason:   reason,
 	})
@@ -170,7 +221,7 @@ func (r *RconAPI) BanPlayer(playerID, reason string, duration time.Duration) err
 	if err != nil {
 		return err
 	}
-	_, err = r.client.RconBanPlayer(context.Background(), req)
+	_, err = r.client.RconBanPlayer(serverContext(r.serverID), req)
 	return err
 }
 
@@ -180,7 +231,7 @@ func (r *RconAPI) BanWithEvidence(playerID, reason string, duration time.Duratio
 	if err != nil {
 		return "", err
 	}
-	resp, err := r.client.RconBanWithEvidence(context.Background(), req)
+	resp, err := r.client.RconBanWithEvidence(serverContext(r.serverID), req)
 	if err != nil {
 		return "", err
 	}
@@ -193,7 +244,7 @@ func (r *RconAPI) WarnPlayerWithRule(playerID, message string, ruleID *string) e
 	if err != nil {
 		return err
 	}
-	_, err = r.client.RconWarnPlayerWithRule(context.Background(), req)
+	_, err = r.client.RconWarnPlayerWithRule(serverContext(r.serverID), req)
 	return err
 }
 
@@ -203,7 +254,7 @@ func (r *RconAPI) KickPlayerWithRule(playerID, reason string, ruleID *string) er
 	if err != nil {
 		return err
 	}
-	_, err = r.client.RconKickPlayerWithRule(context.Background(), req)
+	_, err = r.client.RconKickPlayerWithRule(serverContext(r.serverID), req)
 	return err
 }
 
@@ -213,7 +264,7 @@ func (r *RconAPI) BanPlayerWithRule(playerID, reason string, duration time.Durat
 	if err != nil {
 		return err
 	}
-	_, err = r.client.RconBanPlayerWithRule(context.Background(), req)
+	_, err = r.client.RconBanPlayerWithRule(serverContext(r.serverID), req)
 	return err
 }
 
@@ -223,7 +274,7 @@ func (r *RconAPI) BanWithEvidenceAndRule(playerID, reason string, duration time.
 	if err != nil {
 		return "", err
 	}
-	resp, err := r.client.RconBanWithEvidenceAndRule(context.Background(), req)
+	resp, err := r.client.RconBanWithEvidenceAndRule(serverContext(r.serverID), req)
 	if err != nil {
 		return "", err
 	}
@@ -236,7 +287,7 @@ func (r *RconAPI) BanWithEvidenceAndRuleAndMetadata(playerID, reason string, dur
 	if err != nil {
 		return "", err
 	}
-	resp, err := r.client.RconBanWithEvidenceAndRuleAndMetadata(context.Background(), req)
+	resp, err := r.client.RconBanWithEvidenceAndRuleAndMetadata(serverContext(r.serverID), req)
 	if err != nil {
 		return "", err
 	}
@@ -245,24 +296,27 @@ func (r *RconAPI) BanWithEvidenceAndRuleAndMetadata(playerID, reason string, dur
 
 // RemovePlayerFromSquad removes a player from their squad (by various IDs).
 func (r *RconAPI) RemovePlayerFromSquad(playerID string) error {
-	_, err := r.client.RconRemovePlayerFromSquad(context.Background(), &pluginrpcpb.RconRemoveSquadRequest{PlayerId: playerID})
+	_, err := r.client.RconRemovePlayerFromSquad(serverContext(r.serverID), &pluginrpcpb.RconRemoveSquadRequest{PlayerId: playerID})
 	return err
 }
 
 // RemovePlayerFromSquadById removes a player from their squad by player ID.
 func (r *RconAPI) RemovePlayerFromSquadById(playerID string) error {
-	_, err := r.client.RconRemovePlayerFromSquadById(context.Background(), &pluginrpcpb.RconRemoveSquadRequest{PlayerId: playerID})
+	_, err := r.client.RconRemovePlayerFromSquadById(serverContext(r.serverID), &pluginrpcpb.RconRemoveSquadRequest{PlayerId: playerID})
 	return err
 }
 
 // -- ServerAPI ---------------------------------------------------------------
 
 // ServerAPI exposes read-only server metadata.
-type ServerAPI struct{ client pluginrpcpb.HostAPIClient }
+type ServerAPI struct {
+	client   pluginrpcpb.HostAPIClient
+	serverID string
+}
 
 // GetServerID returns the UUID (as a string) of the server the plugin is scoped to.
 func (s *ServerAPI) GetServerID() (string, error) {
-	resp, err := s.client.ServerGetServerID(context.Background(), &pluginrpcpb.Empty{})
+	resp, err := s.client.ServerGetServerID(serverContext(s.serverID), &pluginrpcpb.Empty{})
 	if err != nil {
 		return "", err
 	}
@@ -270,7 +324,7 @@ func (s *ServerAPI) GetServerID() (string, error) {
 }
 
 func (s *ServerAPI) callJSONMap(fn func(context.Context, *pluginrpcpb.Empty, ...grpc.CallOption) (*pluginrpcpb.JSONResponse, error)) (map[string]interface{}, error) {
-	resp, err := fn(context.Background(), &pluginrpcpb.Empty{})
+	resp, err := fn(serverContext(s.serverID), &pluginrpcpb.Empty{})
 	if err != nil {
 		return nil, err
 	}
@@ -278,7 +332,7 @@ func (s *ServerAPI) callJSONMap(fn func(context.Context, *pluginrpcpb.Empty, ...
 }
 
 func (s *ServerAPI) callJSONList(fn func(context.Context, *pluginrpcpb.Empty, ...grpc.CallOption) (*pluginrpcpb.JSONResponse, error)) ([]map[string]interface{}, error) {
-	resp, err := fn(context.Background(), &pluginrpcpb.Empty{})
+	resp, err := fn(serverContext(s.serverID), &pluginrpcpb.Empty{})
 	if err != nil {
 		return nil, err
 	}
@@ -418,7 +472,8 @@ func (a *AdminAPI) ListTemporaryAdmins() ([]map[string]interface{}, error) {
 
 // -- EventAPI ----------------------------------------------------------------
 
-// EventAPI lets plugins publish custom events into the host event bus.
+// EventAPI lets plugins publish custom events into the host event bus and
+// change which events they receive.
 type EventAPI struct{ client pluginrpcpb.HostAPIClient }
 
 // PublishEvent publishes a custom plugin event. SubscribeToEvents is
@@ -437,6 +492,95 @@ func (e *EventAPI) PublishEvent(eventType string, data map[string]interface{}, r
 	return err
 }
 
+// Subscribe delivers the given event types to HandleEvent in addition to
+// the events in the plugin's definition, and returns the runtime
+// subscriptions now active. Subscriptions last until the plugin stops.
+// Native plugins need the matching events.* capability for each type.
+func (e *EventAPI) Subscribe(eventTypes ...string) ([]string, error) {
+	resp, err := e.client.EventSubscribe(context.Background(), &pluginrpcpb.EventSubscriptionRequest{EventTypes: eventTypes})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetEventTypes(), nil
+}
+
+// Unsubscribe removes runtime subscriptions added with Subscribe and
+// returns the ones still active.
+func (e *EventAPI) Unsubscribe(eventTypes ...string) ([]string, error) {
+	resp, err := e.client.EventUnsubscribe(context.Background(), &pluginrpcpb.EventSubscriptionRequest{EventTypes: eventTypes})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetEventTypes(), nil
+}
+
+// -- HistoryAPI --------------------------------------------------------------
+
+// HistoryAPI runs bounded, read-only queries over the events the host has
+// recorded for the plugin's server.
+type HistoryAPI struct{ client pluginrpcpb.HostAPIClient }
+
+// HistoryQuery bounds a history query. Since defaults to one hour ago and
+// Limit to 100; the window may not exceed seven days and Limit is capped at
+// 500.
+type HistoryQuery struct {
+	PlayerID      string // Steam or EOS ID
+	Since         time.Time
+	Until         time.Time
+	CurrentRound  bool // start at the current round instead of Since
+	TeamkillsOnly bool
+	Limit         int
+}
+
+func (q HistoryQuery) toWire() *pluginrpcpb.HistoryQueryRequest {
+	req := &pluginrpcpb.HistoryQueryRequest{
+		PlayerId:      q.PlayerID,
+		CurrentRound:  q.CurrentRound,
+		TeamkillsOnly: q.TeamkillsOnly,
+		Limit:         int32(q.Limit),
+	}
+	if !q.Since.IsZero() {
+		req.Since = timestamppb.New(q.Since)
+	}
+	if !q.Until.IsZero() {
+		req.Until = timestamppb.New(q.Until)
+	}
+	return req
+}
+
+// GetChatMessages returns chat messages, newest first.
+func (h *HistoryAPI) GetChatMessages(query HistoryQuery) ([]map[string]interface{}, error) {
+	resp, err := h.client.HistoryGetChatMessages(context.Background(), query.toWire())
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSONListOfMaps(resp.GetDataJson())
+}
+
+// GetPlayerDeaths returns player deaths, newest first. When a player is
+// given, deaths where they were the attacker or the victim match.
+func (h *HistoryAPI) GetPlayerDeaths(query HistoryQuery) ([]map[string]interface{}, error) {
+	resp, err := h.client.HistoryGetPlayerDeaths(context.Background(), query.toWire())
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSONListOfMaps(resp.GetDataJson())
+}
+
+// GetCurrentRoundStart returns when the current round started, or nil when
+// no round start has been recorded.
+func (h *HistoryAPI) GetCurrentRoundStart() (*time.Time, error) {
+	resp, err := h.client.HistoryGetCurrentRoundStart(context.Background(), &pluginrpcpb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	if resp.GetStartedAt() == nil {
+		return nil, nil
+	}
+	startedAt := resp.GetStartedAt().AsTime()
+	return &startedAt, nil
+}
+
 // -- DiscordAPI --------------------------------------------------------------
 
 // DiscordAPI sends messages through the host's configured Discord connector.
@@ -0,0 +1,28 @@
+package pluginrpc
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc"
+
+	pluginrpcpb "go.codycody31.dev/squad-aegis/pkg/pluginrpc/proto"
+)
+
+// NewInProcessPluginClient returns the host-side stub for impl running in
+// the host's own process. Calls are encoded exactly as they are for
+// subprocess and WASM plugins, so wire conversion and host-side checks
+// behave the same; only the transport is skipped. host serves the plugin's
+// HostAPI calls. It is meant for tests.
+func NewInProcessPluginClient(impl Plugin, host WasmInvoker) *PluginGRPCClient {
+	hostConn := NewWasmConn(host)
+	server := &pluginGRPCServer{
+		impl: impl,
+		dialHost: func(uint32) (grpc.ClientConnInterface, io.Closer, error) {
+			return hostConn, nil, nil
+		},
+	}
+	return NewWasmPluginClient(NewWasmConn(func(ctx context.Context, method, _ string, payload []byte) ([]byte, error) {
+		return DispatchWasmCall(ctx, &pluginrpcpb.Plugin_ServiceDesc, server, method, "", payload), nil
+	}))
+}
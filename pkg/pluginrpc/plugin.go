@@ -4,12 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"sync"
 	"time"
 
 	goplugin "github.com/hashicorp/go-plugin"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	pluginrpcpb "go.codycody31.dev/squad-aegis/pkg/pluginrpc/proto"
@@ -40,6 +43,22 @@ type Plugin interface {
 	GetCommandExecutionStatus(executionID string) (*CommandExecutionStatus, error)
 }
 
+// ConfigMigrator is implemented by plugins that can upgrade configs stored
+// for an older ConfigSchema.Version. The host calls MigrateConfig on a fresh
+// subprocess, before Initialize, with the stored config and the version it
+// was written for. The returned config must validate against the plugin's
+// current schema.
+type ConfigMigrator interface {
+	MigrateConfig(fromVersion int, config map[string]interface{}) (map[string]interface{}, error)
+}
+
+// HTTPHandler is implemented by plugins that declare HTTPRoutes. The host
+// only forwards requests matching a declared route, after checking the
+// caller's access, and fills in Route and Params from the match.
+type HTTPHandler interface {
+	HandleHTTP(ctx context.Context, req *HTTPRequest) (*HTTPResponse, error)
+}
+
 // -- gRPC server side (runs inside the plugin process) -----------------------
 
 // pluginGRPCServer is the gRPC server exposed to the host. It wraps the
@@ -49,17 +68,35 @@ type pluginGRPCServer struct {
 
 	impl   Plugin
 	broker *goplugin.GRPCBroker
+	// dialHost connects to the host's HostAPI service. Nil dials the
+	// go-plugin broker; WASM modules call the host through imports instead.
+	dialHost func(brokerID uint32) (grpc.ClientConnInterface, io.Closer, error)
 
 	mu        sync.Mutex
 	runCtx    context.Context
 	runCancel context.CancelFunc
 	hostAPIs  *HostAPIs
-	hostConn  *grpc.ClientConn
+	hostConn  io.Closer
+}
+
+// dial connects to the host's HostAPI service. The closer may be nil when
+// the connection holds no resources.
+func (s *pluginGRPCServer) dial(brokerID uint32) (grpc.ClientConnInterface, io.Closer, error) {
+	if s.dialHost != nil {
+		return s.dialHost(brokerID)
+	}
+	conn, err := s.broker.Dial(brokerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conn, conn, nil
 }
 
 // GetDefinition responds with the plugin definition.
 func (s *pluginGRPCServer) GetDefinition(_ context.Context, _ *pluginrpcpb.Empty) (*pluginrpcpb.PluginDefinition, error) {
-	return pluginDefinitionToProto(s.impl.GetDefinition())
+	def := s.impl.GetDefinition()
+	_, def.MigratesConfig = s.impl.(ConfigMigrator)
+	return pluginDefinitionToProto(def)
 }
 
 // Initialize wires up the HostAPIs proxy via the broker ID and calls the
@@ -68,23 +105,27 @@ func (s *pluginGRPCServer) Initialize(_ context.Context, req *pluginrpcpb.Initia
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	conn, err := s.broker.Dial(req.GetHostApiBrokerId())
+	conn, closer, err := s.dial(req.GetHostApiBrokerId())
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial host api broker: %w", err)
 	}
-	s.hostConn = conn
+	s.hostConn = closer
 	s.hostAPIs = newHostAPIsFromConn(conn)
 
 	cfg, err := decodeJSONMap(req.GetConfigJson())
 	if err != nil {
-		_ = conn.Close()
+		if closer != nil {
+			_ = closer.Close()
+		}
 		s.hostConn = nil
 		s.hostAPIs = nil
 		return nil, fmt.Errorf("decode config: %w", err)
 	}
 
 	if err := s.impl.Initialize(cfg, s.hostAPIs); err != nil {
-		_ = conn.Close()
+		if closer != nil {
+			_ = closer.Close()
+		}
 		s.hostConn = nil
 		s.hostAPIs = nil
 		return nil, err
@@ -228,6 +269,64 @@ func (s *pluginGRPCServer) UpdateConfig(_ context.Context, req *pluginrpcpb.Conf
 	return &pluginrpcpb.Empty{}, nil
 }
 
+// MigrateConfig rewrites a stored config for the plugin's current config
+// schema version. Plugins that do not implement ConfigMigrator report
+// Unimplemented.
+func (s *pluginGRPCServer) MigrateConfig(_ context.Context, req *pluginrpcpb.MigrateConfigRequest) (*pluginrpcpb.ConfigJSON, error) {
+	migrator, ok := s.impl.(ConfigMigrator)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "plugin does not implement config migrations")
+	}
+	cfg, err := decodeJSONMap(req.GetConfigJson())
+	if err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+	migrated, err := migrator.MigrateConfig(int(req.GetFromVersion()), cfg)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := encodeJSONMap(migrated)
+	if err != nil {
+		return nil, err
+	}
+	return &pluginrpcpb.ConfigJSON{ConfigJson: encoded}, nil
+}
+
+// HandleHTTP serves a request for one of the plugin's declared routes.
+// Plugins that do not implement HTTPHandler report Unimplemented.
+func (s *pluginGRPCServer) HandleHTTP(ctx context.Context, req *pluginrpcpb.HTTPRequest) (*pluginrpcpb.HTTPResponse, error) {
+	handler, ok := s.impl.(HTTPHandler)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "plugin does not serve HTTP routes")
+	}
+	var query map[string][]string
+	if len(req.GetQueryJson()) > 0 {
+		if err := json.Unmarshal(req.GetQueryJson(), &query); err != nil {
+			return nil, fmt.Errorf("decode query: %w", err)
+		}
+	}
+	resp, err := handler.HandleHTTP(ctx, &HTTPRequest{
+		Method: req.GetMethod(),
+		Path:   req.GetPath(),
+		Route:  req.GetRoute(),
+		Params: req.GetParams(),
+		Query:  query,
+		Body:   req.GetBody(),
+		UserID: req.GetUserId(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return &pluginrpcpb.HTTPResponse{}, nil
+	}
+	return &pluginrpcpb.HTTPResponse{
+		Status:      int32(resp.Status),
+		ContentType: resp.ContentType,
+		Body:        resp.Body,
+	}, nil
+}
+
 // GetCommands returns the list of commands the plugin exposes.
 func (s *pluginGRPCServer) GetCommands(_ context.Context, _ *pluginrpcpb.Empty) (*pluginrpcpb.CommandList, error) {
 	commands := s.impl.GetCommands()
@@ -430,6 +529,52 @@ func (c *PluginGRPCClient) UpdateConfig(ctx context.Context, config map[string]i
 	return err
 }
 
+// MigrateConfig asks the plugin to rewrite a config stored for fromVersion
+// of its config schema.
+func (c *PluginGRPCClient) MigrateConfig(ctx context.Context, fromVersion int, config map[string]interface{}) (map[string]interface{}, error) {
+	encoded, err := encodeJSONMap(config)
+	if err != nil {
+		return nil, fmt.Errorf("encode config: %w", err)
+	}
+	resp, err := c.client.MigrateConfig(ctxOrBackground(ctx), &pluginrpcpb.MigrateConfigRequest{
+		FromVersion: int32(fromVersion),
+		ConfigJson:  encoded,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSONMap(resp.GetConfigJson())
+}
+
+// HandleHTTP forwards a request for a declared route to the plugin.
+func (c *PluginGRPCClient) HandleHTTP(ctx context.Context, req *HTTPRequest) (*HTTPResponse, error) {
+	var query []byte
+	if len(req.Query) > 0 {
+		encoded, err := json.Marshal(req.Query)
+		if err != nil {
+			return nil, fmt.Errorf("encode query: %w", err)
+		}
+		query = encoded
+	}
+	resp, err := c.client.HandleHTTP(ctxOrBackground(ctx), &pluginrpcpb.HTTPRequest{
+		Method:    req.Method,
+		Path:      req.Path,
+		Route:     req.Route,
+		Params:    req.Params,
+		QueryJson: query,
+		Body:      req.Body,
+		UserId:    req.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &HTTPResponse{
+		Status:      int(resp.GetStatus()),
+		ContentType: resp.GetContentType(),
+		Body:        resp.GetBody(),
+	}, nil
+}
+
 // GetCommands fetches the plugin's command list.
 func (c *PluginGRPCClient) GetCommands(ctx context.Context) ([]PluginCommand, error) {
 	resp, err := c.client.GetCommands(ctxOrBackground(ctx), &pluginrpcpb.Empty{})
@@ -630,7 +775,7 @@ func configSchemaToProto(s ConfigSchema) (*pluginrpcpb.ConfigSchema, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &pluginrpcpb.ConfigSchema{Fields: fields}, nil
+	return &pluginrpcpb.ConfigSchema{Fields: fields, Version: int32(s.Version)}, nil
 }
 
 func protoToConfigSchema(s *pluginrpcpb.ConfigSchema) (ConfigSchema, error) {
@@ -641,7 +786,7 @@ func protoToConfigSchema(s *pluginrpcpb.ConfigSchema) (ConfigSchema, error) {
 	if err != nil {
 		return ConfigSchema{}, err
 	}
-	return ConfigSchema{Fields: fields}, nil
+	return ConfigSchema{Fields: fields, Version: int(s.GetVersion())}, nil
 }
 
 func pluginDefinitionToProto(def PluginDefinition) (*pluginrpcpb.PluginDefinition, error) {
@@ -649,14 +794,34 @@ func pluginDefinitionToProto(def PluginDefinition) (*pluginrpcpb.PluginDefinitio
 	if err != nil {
 		return nil, err
 	}
+	var panels []byte
+	if len(def.Panels) > 0 {
+		panels, err = json.Marshal(def.Panels)
+		if err != nil {
+			return nil, fmt.Errorf("encode panels: %w", err)
+		}
+	}
+	routes := make([]*pluginrpcpb.HTTPRoute, 0, len(def.HTTPRoutes))
+	for _, route := range def.HTTPRoutes {
+		routes = append(routes, &pluginrpcpb.HTTPRoute{
+			Method:      route.Method,
+			Path:        route.Path,
+			Access:      route.Access,
+			Description: route.Description,
+		})
+	}
 	return &pluginrpcpb.PluginDefinition{
 		PluginId:               def.PluginID,
 		AllowMultipleInstances: def.AllowMultipleInstances,
+		AllowGlobalInstances:   def.AllowGlobalInstances,
 		LongRunning:            def.LongRunning,
 		RequiredConnectors:     append([]string(nil), def.RequiredConnectors...),
 		OptionalConnectors:     append([]string(nil), def.OptionalConnectors...),
 		ConfigSchema:           schema,
 		Events:                 append([]string(nil), def.Events...),
+		MigratesConfig:         def.MigratesConfig,
+		HttpRoutes:             routes,
+		PanelsJson:             panels,
 	}, nil
 }
 
@@ -668,14 +833,33 @@ func protoToPluginDefinition(p *pluginrpcpb.PluginDefinition) (PluginDefinition,
 	if err != nil {
 		return PluginDefinition{}, err
 	}
+	var panels []Panel
+	if len(p.GetPanelsJson()) > 0 {
+		if err := json.Unmarshal(p.GetPanelsJson(), &panels); err != nil {
+			return PluginDefinition{}, fmt.Errorf("decode panels: %w", err)
+		}
+	}
+	var routes []HTTPRoute
+	for _, route := range p.GetHttpRoutes() {
+		routes = append(routes, HTTPRoute{
+			Method:      route.GetMethod(),
+			Path:        route.GetPath(),
+			Access:      route.GetAccess(),
+			Description: route.GetDescription(),
+		})
+	}
 	return PluginDefinition{
 		PluginID:               p.GetPluginId(),
 		AllowMultipleInstances: p.GetAllowMultipleInstances(),
+		AllowGlobalInstances:   p.GetAllowGlobalInstances(),
 		LongRunning:            p.GetLongRunning(),
 		RequiredConnectors:     append([]string(nil), p.GetRequiredConnectors()...),
 		OptionalConnectors:     append([]string(nil), p.GetOptionalConnectors()...),
 		ConfigSchema:           schema,
 		Events:                 append([]string(nil), p.GetEvents()...),
+		MigratesConfig:         p.GetMigratesConfig(),
+		HTTPRoutes:             routes,
+		Panels:                 panels,
 	}, nil
 }
 
@@ -1033,6 +1033,227 @@ func (x *PublishEventRequest) GetRaw() string {
 	return ""
 }
 
+// EventSubscriptionRequest adds or removes runtime event subscriptions.
+type EventSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventTypes    []string               `protobuf:"bytes,1,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventSubscriptionRequest) Reset() {
+	*x = EventSubscriptionRequest{}
+	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventSubscriptionRequest) ProtoMessage() {}
+
+func (x *EventSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*EventSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_pluginrpc_proto_hostapi_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *EventSubscriptionRequest) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+// EventSubscriptionResponse lists the runtime subscriptions now active.
+type EventSubscriptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventTypes    []string               `protobuf:"bytes,1,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventSubscriptionResponse) Reset() {
+	*x = EventSubscriptionResponse{}
+	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventSubscriptionResponse) ProtoMessage() {}
+
+func (x *EventSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*EventSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_pluginrpc_proto_hostapi_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *EventSubscriptionResponse) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+// HistoryQueryRequest bounds a history query. Unset times and limit use
+// the host defaults.
+type HistoryQueryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Since         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`
+	Until         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=until,proto3" json:"until,omitempty"`
+	CurrentRound  bool                   `protobuf:"varint,4,opt,name=current_round,json=currentRound,proto3" json:"current_round,omitempty"`
+	TeamkillsOnly bool                   `protobuf:"varint,5,opt,name=teamkills_only,json=teamkillsOnly,proto3" json:"teamkills_only,omitempty"`
+	Limit         int32                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryQueryRequest) Reset() {
+	*x = HistoryQueryRequest{}
+	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryQueryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryQueryRequest) ProtoMessage() {}
+
+func (x *HistoryQueryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryQueryRequest.ProtoReflect.Descriptor instead.
+func (*HistoryQueryRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_pluginrpc_proto_hostapi_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *HistoryQueryRequest) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *HistoryQueryRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *HistoryQueryRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *HistoryQueryRequest) GetCurrentRound() bool {
+	if x != nil {
+		return x.CurrentRound
+	}
+	return false
+}
+
+func (x *HistoryQueryRequest) GetTeamkillsOnly() bool {
+	if x != nil {
+		return x.TeamkillsOnly
+	}
+	return false
+}
+
+func (x *HistoryQueryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type RoundStartResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unset when no round start has been recorded.
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoundStartResponse) Reset() {
+	*x = RoundStartResponse{}
+	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoundStartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoundStartResponse) ProtoMessage() {}
+
+func (x *RoundStartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoundStartResponse.ProtoReflect.Descriptor instead.
+func (*RoundStartResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_pluginrpc_proto_hostapi_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RoundStartResponse) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
 type DiscordMessageRequest struct {
 	state     protoimpl.MessageState `protogen:"open.v1"`
 	ChannelId string                 `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
@@ -1045,7 +1266,7 @@ type DiscordMessageRequest struct {
 
 func (x *DiscordMessageRequest) Reset() {
 	*x = DiscordMessageRequest{}
-	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[19]
+	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1057,7 +1278,7 @@ func (x *DiscordMessageRequest) String() string {
 func (*DiscordMessageRequest) ProtoMessage() {}
 
 func (x *DiscordMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[19]
+	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1070,7 +1291,7 @@ func (x *DiscordMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DiscordMessageRequest.ProtoReflect.Descriptor instead.
 func (*DiscordMessageRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_pluginrpc_proto_hostapi_proto_rawDescGZIP(), []int{19}
+	return file_pkg_pluginrpc_proto_hostapi_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *DiscordMessageRequest) GetChannelId() string {
@@ -1103,7 +1324,7 @@ type DiscordMessageResponse struct {
 
 func (x *DiscordMessageResponse) Reset() {
 	*x = DiscordMessageResponse{}
-	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[20]
+	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1115,7 +1336,7 @@ func (x *DiscordMessageResponse) String() string {
 func (*DiscordMessageResponse) ProtoMessage() {}
 
 func (x *DiscordMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[20]
+	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1128,7 +1349,7 @@ func (x *DiscordMessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DiscordMessageResponse.ProtoReflect.Descriptor instead.
 func (*DiscordMessageResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_pluginrpc_proto_hostapi_proto_rawDescGZIP(), []int{20}
+	return file_pkg_pluginrpc_proto_hostapi_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *DiscordMessageResponse) GetMessageId() string {
@@ -1151,7 +1372,7 @@ type ConnectorCallRequest struct {
 
 func (x *ConnectorCallRequest) Reset() {
 	*x = ConnectorCallRequest{}
-	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[21]
+	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1163,7 +1384,7 @@ func (x *ConnectorCallRequest) String() string {
 func (*ConnectorCallRequest) ProtoMessage() {}
 
 func (x *ConnectorCallRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[21]
+	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1176,7 +1397,7 @@ func (x *ConnectorCallRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ConnectorCallRequest.ProtoReflect.Descriptor instead.
 func (*ConnectorCallRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_pluginrpc_proto_hostapi_proto_rawDescGZIP(), []int{21}
+	return file_pkg_pluginrpc_proto_hostapi_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *ConnectorCallRequest) GetConnectorId() string {
@@ -1220,7 +1441,7 @@ type ConnectorCallResponse struct {
 
 func (x *ConnectorCallResponse) Reset() {
 	*x = ConnectorCallResponse{}
-	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[22]
+	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1232,7 +1453,7 @@ func (x *ConnectorCallResponse) String() string {
 func (*ConnectorCallResponse) ProtoMessage() {}
 
 func (x *ConnectorCallResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[22]
+	mi := &file_pkg_pluginrpc_proto_hostapi_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1245,7 +1466,7 @@ func (x *ConnectorCallResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ConnectorCallResponse.ProtoReflect.Descriptor instead.
 func (*ConnectorCallResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_pluginrpc_proto_hostapi_proto_rawDescGZIP(), []int{22}
+	return file_pkg_pluginrpc_proto_hostapi_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *ConnectorCallResponse) GetV() string {
@@ -1344,7 +1565,23 @@ const file_pkg_pluginrpc_proto_hostapi_proto_rawDesc = "" +
 	"\n" +
 	"event_type\x18\x01 \x01(\tR\teventType\x12\x1b\n" +
 	"\tdata_json\x18\x02 \x01(\fR\bdataJson\x12\x10\n" +
-	"\x03raw\x18\x03 \x01(\tR\x03raw\"o\n" +
+	"\x03raw\x18\x03 \x01(\tR\x03raw\";\n" +
+	"\x18EventSubscriptionRequest\x12\x1f\n" +
+	"\vevent_types\x18\x01 \x03(\tR\n" +
+	"eventTypes\"<\n" +
+	"\x19EventSubscriptionResponse\x12\x1f\n" +
+	"\vevent_types\x18\x01 \x03(\tR\n" +
+	"eventTypes\"\xf8\x01\n" +
+	"\x13HistoryQueryRequest\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x120\n" +
+	"\x05since\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x120\n" +
+	"\x05until\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05until\x12#\n" +
+	"\rcurrent_round\x18\x04 \x01(\bR\fcurrentRound\x12%\n" +
+	"\x0eteamkills_only\x18\x05 \x01(\bR\rteamkillsOnly\x12\x14\n" +
+	"\x05limit\x18\x06 \x01(\x05R\x05limit\"O\n" +
+	"\x12RoundStartResponse\x129\n" +
+	"\n" +
+	"started_at\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\"o\n" +
 	"\x15DiscordMessageRequest\x12\x1d\n" +
 	"\n" +
 	"channel_id\x18\x01 \x01(\tR\tchannelId\x12\x18\n" +
@@ -1364,7 +1601,7 @@ const file_pkg_pluginrpc_proto_hostapi_proto_rawDesc = "" +
 	"\x01v\x18\x01 \x01(\tR\x01v\x12\x0e\n" +
 	"\x02ok\x18\x02 \x01(\bR\x02ok\x12\x1b\n" +
 	"\tdata_json\x18\x03 \x01(\fR\bdataJson\x12\x14\n" +
-	"\x05error\x18\x04 \x01(\tR\x05error2\xd7\x1c\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error2\xf6!\n" +
 	"\aHostAPI\x12N\n" +
 	"\aLogInfo\x12#.squadaegis.pluginrpc.v1.LogRequest\x1a\x1e.squadaegis.pluginrpc.v1.Empty\x12N\n" +
 	"\aLogWarn\x12#.squadaegis.pluginrpc.v1.LogRequest\x1a\x1e.squadaegis.pluginrpc.v1.Empty\x12O\n" +
@@ -1387,7 +1624,8 @@ const file_pkg_pluginrpc_proto_hostapi_proto_rawDesc = "" +
 	"\x13ServerGetServerInfo\x12\x1e.squadaegis.pluginrpc.v1.Empty\x1a%.squadaegis.pluginrpc.v1.JSONResponse\x12Y\n" +
 	"\x10ServerGetPlayers\x12\x1e.squadaegis.pluginrpc.v1.Empty\x1a%.squadaegis.pluginrpc.v1.JSONResponse\x12X\n" +
 	"\x0fServerGetAdmins\x12\x1e.squadaegis.pluginrpc.v1.Empty\x1a%.squadaegis.pluginrpc.v1.JSONResponse\x12X\n" +
-	"\x0fServerGetSquads\x12\x1e.squadaegis.pluginrpc.v1.Empty\x1a%.squadaegis.pluginrpc.v1.JSONResponse\x12l\n" +
+	"\x0fServerGetSquads\x12\x1e.squadaegis.pluginrpc.v1.Empty\x1a%.squadaegis.pluginrpc.v1.JSONResponse\x12_\n" +
+	"\x16ServerListScopeServers\x12\x1e.squadaegis.pluginrpc.v1.Empty\x1a%.squadaegis.pluginrpc.v1.JSONResponse\x12l\n" +
 	"\x15DatabaseGetPluginData\x12(.squadaegis.pluginrpc.v1.DatabaseRequest\x1a).squadaegis.pluginrpc.v1.DatabaseResponse\x12a\n" +
 	"\x15DatabaseSetPluginData\x12(.squadaegis.pluginrpc.v1.DatabaseRequest\x1a\x1e.squadaegis.pluginrpc.v1.Empty\x12d\n" +
 	"\x18DatabaseDeletePluginData\x12(.squadaegis.pluginrpc.v1.DatabaseRequest\x1a\x1e.squadaegis.pluginrpc.v1.Empty\x12g\n" +
@@ -1398,7 +1636,12 @@ const file_pkg_pluginrpc_proto_hostapi_proto_rawDesc = "" +
 	"\x1dAdminRemoveTemporaryAdminRole\x12/.squadaegis.pluginrpc.v1.RemoveTempAdminRequest\x1a\x1e.squadaegis.pluginrpc.v1.Empty\x12l\n" +
 	"\x19AdminGetPlayerAdminStatus\x12(.squadaegis.pluginrpc.v1.PlayerIDRequest\x1a%.squadaegis.pluginrpc.v1.JSONResponse\x12a\n" +
 	"\x18AdminListTemporaryAdmins\x12\x1e.squadaegis.pluginrpc.v1.Empty\x1a%.squadaegis.pluginrpc.v1.JSONResponse\x12a\n" +
-	"\x11EventPublishEvent\x12,.squadaegis.pluginrpc.v1.PublishEventRequest\x1a\x1e.squadaegis.pluginrpc.v1.Empty\x12u\n" +
+	"\x11EventPublishEvent\x12,.squadaegis.pluginrpc.v1.PublishEventRequest\x1a\x1e.squadaegis.pluginrpc.v1.Empty\x12w\n" +
+	"\x0eEventSubscribe\x121.squadaegis.pluginrpc.v1.EventSubscriptionRequest\x1a2.squadaegis.pluginrpc.v1.EventSubscriptionResponse\x12y\n" +
+	"\x10EventUnsubscribe\x121.squadaegis.pluginrpc.v1.EventSubscriptionRequest\x1a2.squadaegis.pluginrpc.v1.EventSubscriptionResponse\x12m\n" +
+	"\x16HistoryGetChatMessages\x12,.squadaegis.pluginrpc.v1.HistoryQueryRequest\x1a%.squadaegis.pluginrpc.v1.JSONResponse\x12m\n" +
+	"\x16HistoryGetPlayerDeaths\x12,.squadaegis.pluginrpc.v1.HistoryQueryRequest\x1a%.squadaegis.pluginrpc.v1.JSONResponse\x12j\n" +
+	"\x1bHistoryGetCurrentRoundStart\x12\x1e.squadaegis.pluginrpc.v1.Empty\x1a+.squadaegis.pluginrpc.v1.RoundStartResponse\x12u\n" +
 	"\x12DiscordSendMessage\x12..squadaegis.pluginrpc.v1.DiscordMessageRequest\x1a/.squadaegis.pluginrpc.v1.DiscordMessageResponse\x12s\n" +
 	"\x10DiscordSendEmbed\x12..squadaegis.pluginrpc.v1.DiscordMessageRequest\x1a/.squadaegis.pluginrpc.v1.DiscordMessageResponse\x12n\n" +
 	"\rConnectorCall\x12-.squadaegis.pluginrpc.v1.ConnectorCallRequest\x1a..squadaegis.pluginrpc.v1.ConnectorCallResponseB?Z=go.codycody31.dev/squad-aegis/pkg/pluginrpc/proto;pluginrpcpbb\x06proto3"
@@ -1415,113 +1658,132 @@ func file_pkg_pluginrpc_proto_hostapi_proto_rawDescGZIP() []byte {
 	return file_pkg_pluginrpc_proto_hostapi_proto_rawDescData
 }
 
-var file_pkg_pluginrpc_proto_hostapi_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_pkg_pluginrpc_proto_hostapi_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
 var file_pkg_pluginrpc_proto_hostapi_proto_goTypes = []any{
-	(*JSONResponse)(nil),           // 0: squadaegis.pluginrpc.v1.JSONResponse
-	(*StringResponse)(nil),         // 1: squadaegis.pluginrpc.v1.StringResponse
-	(*LogRequest)(nil),             // 2: squadaegis.pluginrpc.v1.LogRequest
-	(*RconCommandRequest)(nil),     // 3: squadaegis.pluginrpc.v1.RconCommandRequest
-	(*RconCommandResponse)(nil),    // 4: squadaegis.pluginrpc.v1.RconCommandResponse
-	(*RconBroadcastRequest)(nil),   // 5: squadaegis.pluginrpc.v1.RconBroadcastRequest
-	(*RconWarnPlayerRequest)(nil),  // 6: squadaegis.pluginrpc.v1.RconWarnPlayerRequest
-	(*RconKickRequest)(nil),        // 7: squadaegis.pluginrpc.v1.RconKickRequest
-	(*RconBanRequest)(nil),         // 8: squadaegis.pluginrpc.v1.RconBanRequest
-	(*BanResultResponse)(nil),      // 9: squadaegis.pluginrpc.v1.BanResultResponse
-	(*RconRemoveSquadRequest)(nil), // 10: squadaegis.pluginrpc.v1.RconRemoveSquadRequest
-	(*DatabaseRequest)(nil),        // 11: squadaegis.pluginrpc.v1.DatabaseRequest
-	(*DatabaseResponse)(nil),       // 12: squadaegis.pluginrpc.v1.DatabaseResponse
-	(*ListRulesRequest)(nil),       // 13: squadaegis.pluginrpc.v1.ListRulesRequest
-	(*ListRuleActionsRequest)(nil), // 14: squadaegis.pluginrpc.v1.ListRuleActionsRequest
-	(*AddTempAdminRequest)(nil),    // 15: squadaegis.pluginrpc.v1.AddTempAdminRequest
-	(*RemoveTempAdminRequest)(nil), // 16: squadaegis.pluginrpc.v1.RemoveTempAdminRequest
-	(*PlayerIDRequest)(nil),        // 17: squadaegis.pluginrpc.v1.PlayerIDRequest
-	(*PublishEventRequest)(nil),    // 18: squadaegis.pluginrpc.v1.PublishEventRequest
-	(*DiscordMessageRequest)(nil),  // 19: squadaegis.pluginrpc.v1.DiscordMessageRequest
-	(*DiscordMessageResponse)(nil), // 20: squadaegis.pluginrpc.v1.DiscordMessageResponse
-	(*ConnectorCallRequest)(nil),   // 21: squadaegis.pluginrpc.v1.ConnectorCallRequest
-	(*ConnectorCallResponse)(nil),  // 22: squadaegis.pluginrpc.v1.ConnectorCallResponse
-	(*timestamppb.Timestamp)(nil),  // 23: google.protobuf.Timestamp
-	(*Empty)(nil),                  // 24: squadaegis.pluginrpc.v1.Empty
+	(*JSONResponse)(nil),              // 0: squadaegis.pluginrpc.v1.JSONResponse
+	(*StringResponse)(nil),            // 1: squadaegis.pluginrpc.v1.StringResponse
+	(*LogRequest)(nil),                // 2: squadaegis.pluginrpc.v1.LogRequest
+	(*RconCommandRequest)(nil),        // 3: squadaegis.pluginrpc.v1.RconCommandRequest
+	(*RconCommandResponse)(nil),       // 4: squadaegis.pluginrpc.v1.RconCommandResponse
+	(*RconBroadcastRequest)(nil),      // 5: squadaegis.pluginrpc.v1.RconBroadcastRequest
+	(*RconWarnPlayerRequest)(nil),     // 6: squadaegis.pluginrpc.v1.RconWarnPlayerRequest
+	(*RconKickRequest)(nil),           // 7: squadaegis.pluginrpc.v1.RconKickRequest
+	(*RconBanRequest)(nil),            // 8: squadaegis.pluginrpc.v1.RconBanRequest
+	(*BanResultResponse)(nil),         // 9: squadaegis.pluginrpc.v1.BanResultResponse
+	(*RconRemoveSquadRequest)(nil),    // 10: squadaegis.pluginrpc.v1.RconRemoveSquadRequest
+	(*DatabaseRequest)(nil),           // 11: squadaegis.pluginrpc.v1.DatabaseRequest
+	(*DatabaseResponse)(nil),          // 12: squadaegis.pluginrpc.v1.DatabaseResponse
+	(*ListRulesRequest)(nil),          // 13: squadaegis.pluginrpc.v1.ListRulesRequest
+	(*ListRuleActionsRequest)(nil),    // 14: squadaegis.pluginrpc.v1.ListRuleActionsRequest
+	(*AddTempAdminRequest)(nil),       // 15: squadaegis.pluginrpc.v1.AddTempAdminRequest
+	(*RemoveTempAdminRequest)(nil),    // 16: squadaegis.pluginrpc.v1.RemoveTempAdminRequest
+	(*PlayerIDRequest)(nil),           // 17: squadaegis.pluginrpc.v1.PlayerIDRequest
+	(*PublishEventRequest)(nil),       // 18: squadaegis.pluginrpc.v1.PublishEventRequest
+	(*EventSubscriptionRequest)(nil),  // 19: squadaegis.pluginrpc.v1.EventSubscriptionRequest
+	(*EventSubscriptionResponse)(nil), // 20: squadaegis.pluginrpc.v1.EventSubscriptionResponse
+	(*HistoryQueryRequest)(nil),       // 21: squadaegis.pluginrpc.v1.HistoryQueryRequest
+	(*RoundStartResponse)(nil),        // 22: squadaegis.pluginrpc.v1.RoundStartResponse
+	(*DiscordMessageRequest)(nil),     // 23: squadaegis.pluginrpc.v1.DiscordMessageRequest
+	(*DiscordMessageResponse)(nil),    // 24: squadaegis.pluginrpc.v1.DiscordMessageResponse
+	(*ConnectorCallRequest)(nil),      // 25: squadaegis.pluginrpc.v1.ConnectorCallRequest
+	(*ConnectorCallResponse)(nil),     // 26: squadaegis.pluginrpc.v1.ConnectorCallResponse
+	(*timestamppb.Timestamp)(nil),     // 27: google.protobuf.Timestamp
+	(*Empty)(nil),                     // 28: squadaegis.pluginrpc.v1.Empty
 }
 var file_pkg_pluginrpc_proto_hostapi_proto_depIdxs = []int32{
-	23, // 0: squadaegis.pluginrpc.v1.AddTempAdminRequest.expires_at:type_name -> google.protobuf.Timestamp
-	2,  // 1: squadaegis.pluginrpc.v1.HostAPI.LogInfo:input_type -> squadaegis.pluginrpc.v1.LogRequest
-	2,  // 2: squadaegis.pluginrpc.v1.HostAPI.LogWarn:input_type -> squadaegis.pluginrpc.v1.LogRequest
-	2,  // 3: squadaegis.pluginrpc.v1.HostAPI.LogError:input_type -> squadaegis.pluginrpc.v1.LogRequest
-	2,  // 4: squadaegis.pluginrpc.v1.HostAPI.LogDebug:input_type -> squadaegis.pluginrpc.v1.LogRequest
-	3,  // 5: squadaegis.pluginrpc.v1.HostAPI.RconSendCommand:input_type -> squadaegis.pluginrpc.v1.RconCommandRequest
-	5,  // 6: squadaegis.pluginrpc.v1.HostAPI.RconBroadcast:input_type -> squadaegis.pluginrpc.v1.RconBroadcastRequest
-	6,  // 7: squadaegis.pluginrpc.v1.HostAPI.RconSendWarningToPlayer:input_type -> squadaegis.pluginrpc.v1.RconWarnPlayerRequest
-	7,  // 8: squadaegis.pluginrpc.v1.HostAPI.RconKickPlayer:input_type -> squadaegis.pluginrpc.v1.RconKickRequest
-	8,  // 9: squadaegis.pluginrpc.v1.HostAPI.RconBanPlayer:input_type -> squadaegis.pluginrpc.v1.RconBanRequest
-	8,  // 10: squadaegis.pluginrpc.v1.HostAPI.RconBanWithEvidence:input_type -> squadaegis.pluginrpc.v1.RconBanRequest
-	8,  // 11: squadaegis.pluginrpc.v1.HostAPI.RconWarnPlayerWithRule:input_type -> squadaegis.pluginrpc.v1.RconBanRequest
-	8,  // 12: squadaegis.pluginrpc.v1.HostAPI.RconKickPlayerWithRule:input_type -> squadaegis.pluginrpc.v1.RconBanRequest
-	8,  // 13: squadaegis.pluginrpc.v1.HostAPI.RconBanPlayerWithRule:input_type -> squadaegis.pluginrpc.v1.RconBanRequest
-	8,  // 14: squadaegis.pluginrpc.v1.HostAPI.RconBanWithEvidenceAndRule:input_type -> squadaegis.pluginrpc.v1.RconBanRequest
-	8,  // 15: squadaegis.pluginrpc.v1.HostAPI.RconBanWithEvidenceAndRuleAndMetadata:input_type -> squadaegis.pluginrpc.v1.RconBanRequest
-	10, // 16: squadaegis.pluginrpc.v1.HostAPI.RconRemovePlayerFromSquad:input_type -> squadaegis.pluginrpc.v1.RconRemoveSquadRequest
-	10, // 17: squadaegis.pluginrpc.v1.HostAPI.RconRemovePlayerFromSquadById:input_type -> squadaegis.pluginrpc.v1.RconRemoveSquadRequest
-	24, // 18: squadaegis.pluginrpc.v1.HostAPI.ServerGetServerID:input_type -> squadaegis.pluginrpc.v1.Empty
-	24, // 19: squadaegis.pluginrpc.v1.HostAPI.ServerGetServerInfo:input_type -> squadaegis.pluginrpc.v1.Empty
-	24, // 20: squadaegis.pluginrpc.v1.HostAPI.ServerGetPlayers:input_type -> squadaegis.pluginrpc.v1.Empty
-	24, // 21: squadaegis.pluginrpc.v1.HostAPI.ServerGetAdmins:input_type -> squadaegis.pluginrpc.v1.Empty
-	24, // 22: squadaegis.pluginrpc.v1.HostAPI.ServerGetSquads:input_type -> squadaegis.pluginrpc.v1.Empty
-	11, // 23: squadaegis.pluginrpc.v1.HostAPI.DatabaseGetPluginData:input_type -> squadaegis.pluginrpc.v1.DatabaseRequest
-	11, // 24: squadaegis.pluginrpc.v1.HostAPI.DatabaseSetPluginData:input_type -> squadaegis.pluginrpc.v1.DatabaseRequest
-	11, // 25: squadaegis.pluginrpc.v1.HostAPI.DatabaseDeletePluginData:input_type -> squadaegis.pluginrpc.v1.DatabaseRequest
-	13, // 26: squadaegis.pluginrpc.v1.HostAPI.RuleListServerRules:input_type -> squadaegis.pluginrpc.v1.ListRulesRequest
-	14, // 27: squadaegis.pluginrpc.v1.HostAPI.RuleListServerRuleActions:input_type -> squadaegis.pluginrpc.v1.ListRuleActionsRequest
-	15, // 28: squadaegis.pluginrpc.v1.HostAPI.AdminAddTemporaryAdmin:input_type -> squadaegis.pluginrpc.v1.AddTempAdminRequest
-	16, // 29: squadaegis.pluginrpc.v1.HostAPI.AdminRemoveTemporaryAdmin:input_type -> squadaegis.pluginrpc.v1.RemoveTempAdminRequest
-	16, // 30: squadaegis.pluginrpc.v1.HostAPI.AdminRemoveTemporaryAdminRole:input_type -> squadaegis.pluginrpc.v1.RemoveTempAdminRequest
-	17, // 31: squadaegis.pluginrpc.v1.HostAPI.AdminGetPlayerAdminStatus:input_type -> squadaegis.pluginrpc.v1.PlayerIDRequest
-	24, // 32: squadaegis.pluginrpc.v1.HostAPI.AdminListTemporaryAdmins:input_type -> squadaegis.pluginrpc.v1.Empty
-	18, // 33: squadaegis.pluginrpc.v1.HostAPI.EventPublishEvent:input_type -> squadaegis.pluginrpc.v1.PublishEventRequest
-	19, // 34: squadaegis.pluginrpc.v1.HostAPI.DiscordSendMessage:input_type -> squadaegis.pluginrpc.v1.DiscordMessageRequest
-	19, // 35: squadaegis.pluginrpc.v1.HostAPI.DiscordSendEmbed:input_type -> squadaegis.pluginrpc.v1.DiscordMessageRequest
-	21, // 36: squadaegis.pluginrpc.v1.HostAPI.ConnectorCall:input_type -> squadaegis.pluginrpc.v1.ConnectorCallRequest
-	24, // 37: squadaegis.pluginrpc.v1.HostAPI.LogInfo:output_type -> squadaegis.pluginrpc.v1.Empty
-	24, // 38: squadaegis.pluginrpc.v1.HostAPI.LogWarn:output_type -> squadaegis.pluginrpc.v1.Empty
-	24, // 39: squadaegis.pluginrpc.v1.HostAPI.LogError:output_type -> squadaegis.pluginrpc.v1.Empty
-	24, // 40: squadaegis.pluginrpc.v1.HostAPI.LogDebug:output_type -> squadaegis.pluginrpc.v1.Empty
-	4,  // 41: squadaegis.pluginrpc.v1.HostAPI.RconSendCommand:output_type -> squadaegis.pluginrpc.v1.RconCommandResponse
-	24, // 42: squadaegis.pluginrpc.v1.HostAPI.RconBroadcast:output_type -> squadaegis.pluginrpc.v1.Empty
-	24, // 43: squadaegis.pluginrpc.v1.HostAPI.RconSendWarningToPlayer:output_type -> squadaegis.pluginrpc.v1.Empty
-	24, // 44: squadaegis.pluginrpc.v1.HostAPI.RconKickPlayer:output_type -> squadaegis.pluginrpc.v1.Empty
-	24, // 45: squadaegis.pluginrpc.v1.HostAPI.RconBanPlayer:output_type -> squadaegis.pluginrpc.v1.Empty
-	9,  // 46: squadaegis.pluginrpc.v1.HostAPI.RconBanWithEvidence:output_type -> squadaegis.pluginrpc.v1.BanResultResponse
-	24, // 47: squadaegis.pluginrpc.v1.HostAPI.RconWarnPlayerWithRule:output_type -> squadaegis.pluginrpc.v1.Empty
-	24, // 48: squadaegis.pluginrpc.v1.HostAPI.RconKickPlayerWithRule:output_type -> squadaegis.pluginrpc.v1.Empty
-	24, // 49: squadaegis.pluginrpc.v1.HostAPI.RconBanPlayerWithRule:output_type -> squadaegis.pluginrpc.v1.Empty
-	9,  // 50: squadaegis.pluginrpc.v1.HostAPI.RconBanWithEvidenceAndRule:output_type -> squadaegis.pluginrpc.v1.BanResultResponse
-	9,  // 51: squadaegis.pluginrpc.v1.HostAPI.RconBanWithEvidenceAndRuleAndMetadata:output_type -> squadaegis.pluginrpc.v1.BanResultResponse
-	24, // 52: squadaegis.pluginrpc.v1.HostAPI.RconRemovePlayerFromSquad:output_type -> squadaegis.pluginrpc.v1.Empty
-	24, // 53: squadaegis.pluginrpc.v1.HostAPI.RconRemovePlayerFromSquadById:output_type -> squadaegis.pluginrpc.v1.Empty
-	1,  // 54: squadaegis.pluginrpc.v1.HostAPI.ServerGetServerID:output_type -> squadaegis.pluginrpc.v1.StringResponse
-	0,  // 55: squadaegis.pluginrpc.v1.HostAPI.ServerGetServerInfo:output_type -> squadaegis.pluginrpc.v1.JSONResponse
-	0,  // 56: squadaegis.pluginrpc.v1.HostAPI.ServerGetPlayers:output_type -> squadaegis.pluginrpc.v1.JSONResponse
-	0,  // 57: squadaegis.pluginrpc.v1.HostAPI.ServerGetAdmins:output_type -> squadaegis.pluginrpc.v1.JSONResponse
-	0,  // 58: squadaegis.pluginrpc.v1.HostAPI.ServerGetSquads:output_type -> squadaegis.pluginrpc.v1.JSONResponse
-	12, // 59: squadaegis.pluginrpc.v1.HostAPI.DatabaseGetPluginData:output_type -> squadaegis.pluginrpc.v1.DatabaseResponse
-	24, // 60: squadaegis.pluginrpc.v1.HostAPI.DatabaseSetPluginData:output_type -> squadaegis.pluginrpc.v1.Empty
-	24, // 61: squadaegis.pluginrpc.v1.HostAPI.DatabaseDeletePluginData:output_type -> squadaegis.pluginrpc.v1.Empty
-	0,  // 62: squadaegis.pluginrpc.v1.HostAPI.RuleListServerRules:output_type -> squadaegis.pluginrpc.v1.JSONResponse
-	0,  // 63: squadaegis.pluginrpc.v1.HostAPI.RuleListServerRuleActions:output_type -> squadaegis.pluginrpc.v1.JSONResponse
-	24, // 64: squadaegis.pluginrpc.v1.HostAPI.AdminAddTemporaryAdmin:output_type -> squadaegis.pluginrpc.v1.Empty
-	24, // 65: squadaegis.pluginrpc.v1.HostAPI.AdminRemoveTemporaryAdmin:output_type -> squadaegis.pluginrpc.v1.Empty
-	24, // 66: squadaegis.pluginrpc.v1.HostAPI.AdminRemoveTemporaryAdminRole:output_type -> squadaegis.pluginrpc.v1.Empty
-	0,  // 67: squadaegis.pluginrpc.v1.HostAPI.AdminGetPlayerAdminStatus:output_type -> squadaegis.pluginrpc.v1.JSONResponse
-	0,  // 68: squadaegis.pluginrpc.v1.HostAPI.AdminListTemporaryAdmins:output_type -> squadaegis.pluginrpc.v1.JSONResponse
-	24, // 69: squadaegis.pluginrpc.v1.HostAPI.EventPublishEvent:output_type -> squadaegis.pluginrpc.v1.Empty
-	20, // 70: squadaegis.pluginrpc.v1.HostAPI.DiscordSendMessage:output_type -> squadaegis.pluginrpc.v1.DiscordMessageResponse
-	20, // 71: squadaegis.pluginrpc.v1.HostAPI.DiscordSendEmbed:output_type -> squadaegis.pluginrpc.v1.DiscordMessageResponse
-	22, // 72: squadaegis.pluginrpc.v1.HostAPI.ConnectorCall:output_type -> squadaegis.pluginrpc.v1.ConnectorCallResponse
-	37, // [37:73] is the sub-list for method output_type
-	1,  // [1:37] is the sub-list for method input_type
-	1,  // [1:1] is the sub-list for extension type_name
-	1,  // [1:1] is the sub-list for extension extendee
-	0,  // [0:1] is the sub-list for field type_name
+	27, // 0: squadaegis.pluginrpc.v1.AddTempAdminRequest.expires_at:type_name -> google.protobuf.Timestamp
+	27, // 1: squadaegis.pluginrpc.v1.HistoryQueryRequest.since:type_name -> google.protobuf.Timestamp
+	27, // 2: squadaegis.pluginrpc.v1.HistoryQueryRequest.until:type_name -> google.protobuf.Timestamp
+	27, // 3: squadaegis.pluginrpc.v1.RoundStartResponse.started_at:type_name -> google.protobuf.Timestamp
+	2,  // 4: squadaegis.pluginrpc.v1.HostAPI.LogInfo:input_type -> squadaegis.pluginrpc.v1.LogRequest
+	2,  // 5: squadaegis.pluginrpc.v1.HostAPI.LogWarn:input_type -> squadaegis.pluginrpc.v1.LogRequest
+	2,  // 6: squadaegis.pluginrpc.v1.HostAPI.LogError:input_type -> squadaegis.pluginrpc.v1.LogRequest
+	2,  // 7: squadaegis.pluginrpc.v1.HostAPI.LogDebug:input_type -> squadaegis.pluginrpc.v1.LogRequest
+	3,  // 8: squadaegis.pluginrpc.v1.HostAPI.RconSendCommand:input_type -> squadaegis.pluginrpc.v1.RconCommandRequest
+	5,  // 9: squadaegis.pluginrpc.v1.HostAPI.RconBroadcast:input_type -> squadaegis.pluginrpc.v1.RconBroadcastRequest
+	6,  // 10: squadaegis.pluginrpc.v1.HostAPI.RconSendWarningToPlayer:input_type -> squadaegis.pluginrpc.v1.RconWarnPlayerRequest
+	7,  // 11: squadaegis.pluginrpc.v1.HostAPI.RconKickPlayer:input_type -> squadaegis.pluginrpc.v1.RconKickRequest
+	8,  // 12: squadaegis.pluginrpc.v1.HostAPI.RconBanPlayer:input_type -> squadaegis.pluginrpc.v1.RconBanRequest
+	8,  // 13: squadaegis.pluginrpc.v1.HostAPI.RconBanWithEvidence:input_type -> squadaegis.pluginrpc.v1.RconBanRequest
+	8,  // 14: squadaegis.pluginrpc.v1.HostAPI.RconWarnPlayerWithRule:input_type -> squadaegis.pluginrpc.v1.RconBanRequest
+	8,  // 15: squadaegis.pluginrpc.v1.HostAPI.RconKickPlayerWithRule:input_type -> squadaegis.pluginrpc.v1.RconBanRequest
+	8,  // 16: squadaegis.pluginrpc.v1.HostAPI.RconBanPlayerWithRule:input_type -> squadaegis.pluginrpc.v1.RconBanRequest
+	8,  // 17: squadaegis.pluginrpc.v1.HostAPI.RconBanWithEvidenceAndRule:input_type -> squadaegis.pluginrpc.v1.RconBanRequest
+	8,  // 18: squadaegis.pluginrpc.v1.HostAPI.RconBanWithEvidenceAndRuleAndMetadata:input_type -> squadaegis.pluginrpc.v1.RconBanRequest
+	10, // 19: squadaegis.pluginrpc.v1.HostAPI.RconRemovePlayerFromSquad:input_type -> squadaegis.pluginrpc.v1.RconRemoveSquadRequest
+	10, // 20: squadaegis.pluginrpc.v1.HostAPI.RconRemovePlayerFromSquadById:input_type -> squadaegis.pluginrpc.v1.RconRemoveSquadRequest
+	28, // 21: squadaegis.pluginrpc.v1.HostAPI.ServerGetServerID:input_type -> squadaegis.pluginrpc.v1.Empty
+	28, // 22: squadaegis.pluginrpc.v1.HostAPI.ServerGetServerInfo:input_type -> squadaegis.pluginrpc.v1.Empty
+	28, // 23: squadaegis.pluginrpc.v1.HostAPI.ServerGetPlayers:input_type -> squadaegis.pluginrpc.v1.Empty
+	28, // 24: squadaegis.pluginrpc.v1.HostAPI.ServerGetAdmins:input_type -> squadaegis.pluginrpc.v1.Empty
+	28, // 25: squadaegis.pluginrpc.v1.HostAPI.ServerGetSquads:input_type -> squadaegis.pluginrpc.v1.Empty
+	28, // 26: squadaegis.pluginrpc.v1.HostAPI.ServerListScopeServers:input_type -> squadaegis.pluginrpc.v1.Empty
+	11, // 27: squadaegis.pluginrpc.v1.HostAPI.DatabaseGetPluginData:input_type -> squadaegis.pluginrpc.v1.DatabaseRequest
+	11, // 28: squadaegis.pluginrpc.v1.HostAPI.DatabaseSetPluginData:input_type -> squadaegis.pluginrpc.v1.DatabaseRequest
+	11, // 29: squadaegis.pluginrpc.v1.HostAPI.DatabaseDeletePluginData:input_type -> squadaegis.pluginrpc.v1.DatabaseRequest
+	13, // 30: squadaegis.pluginrpc.v1.HostAPI.RuleListServerRules:input_type -> squadaegis.pluginrpc.v1.ListRulesRequest
+	14, // 31: squadaegis.pluginrpc.v1.HostAPI.RuleListServerRuleActions:input_type -> squadaegis.pluginrpc.v1.ListRuleActionsRequest
+	15, // 32: squadaegis.pluginrpc.v1.HostAPI.AdminAddTemporaryAdmin:input_type -> squadaegis.pluginrpc.v1.AddTempAdminRequest
+	16, // 33: squadaegis.pluginrpc.v1.HostAPI.AdminRemoveTemporaryAdmin:input_type -> squadaegis.pluginrpc.v1.RemoveTempAdminRequest
+	16, // 34: squadaegis.pluginrpc.v1.HostAPI.AdminRemoveTemporaryAdminRole:input_type -> squadaegis.pluginrpc.v1.RemoveTempAdminRequest
+	17, // 35: squadaegis.pluginrpc.v1.HostAPI.AdminGetPlayerAdminStatus:input_type -> squadaegis.pluginrpc.v1.PlayerIDRequest
+	28, // 36: squadaegis.pluginrpc.v1.HostAPI.AdminListTemporaryAdmins:input_type -> squadaegis.pluginrpc.v1.Empty
+	18, // 37: squadaegis.pluginrpc.v1.HostAPI.EventPublishEvent:input_type -> squadaegis.pluginrpc.v1.PublishEventRequest
+	19, // 38: squadaegis.pluginrpc.v1.HostAPI.EventSubscribe:input_type -> squadaegis.pluginrpc.v1.EventSubscriptionRequest
+	19, // 39: squadaegis.pluginrpc.v1.HostAPI.EventUnsubscribe:input_type -> squadaegis.pluginrpc.v1.EventSubscriptionRequest
+	21, // 40: squadaegis.pluginrpc.v1.HostAPI.HistoryGetChatMessages:input_type -> squadaegis.pluginrpc.v1.HistoryQueryRequest
+	21, // 41: squadaegis.pluginrpc.v1.HostAPI.HistoryGetPlayerDeaths:input_type -> squadaegis.pluginrpc.v1.HistoryQueryRequest
+	28, // 42: squadaegis.pluginrpc.v1.HostAPI.HistoryGetCurrentRoundStart:input_type -> squadaegis.pluginrpc.v1.Empty
+	23, // 43: squadaegis.pluginrpc.v1.HostAPI.DiscordSendMessage:input_type -> squadaegis.pluginrpc.v1.DiscordMessageRequest
+	23, // 44: squadaegis.pluginrpc.v1.HostAPI.DiscordSendEmbed:input_type -> squadaegis.pluginrpc.v1.DiscordMessageRequest
+	25, // 45: squadaegis.pluginrpc.v1.HostAPI.ConnectorCall:input_type -> squadaegis.pluginrpc.v1.ConnectorCallRequest
+	28, // 46: squadaegis.pluginrpc.v1.HostAPI.LogInfo:output_type -> squadaegis.pluginrpc.v1.Empty
+	28, // 47: squadaegis.pluginrpc.v1.HostAPI.LogWarn:output_type -> squadaegis.pluginrpc.v1.Empty
+	28, // 48: squadaegis.pluginrpc.v1.HostAPI.LogError:output_type -> squadaegis.pluginrpc.v1.Empty
+	28, // 49: squadaegis.pluginrpc.v1.HostAPI.LogDebug:output_type -> squadaegis.pluginrpc.v1.Empty
+	4,  // 50: squadaegis.pluginrpc.v1.HostAPI.RconSendCommand:output_type -> squadaegis.pluginrpc.v1.RconCommandResponse
+	28, // 51: squadaegis.pluginrpc.v1.HostAPI.RconBroadcast:output_type -> squadaegis.pluginrpc.v1.Empty
+	28, // 52: squadaegis.pluginrpc.v1.HostAPI.RconSendWarningToPlayer:output_type -> squadaegis.pluginrpc.v1.Empty
+	28, // 53: squadaegis.pluginrpc.v1.HostAPI.RconKickPlayer:output_type -> squadaegis.pluginrpc.v1.Empty
+	28, // 54: squadaegis.pluginrpc.v1.HostAPI.RconBanPlayer:output_type -> squadaegis.pluginrpc.v1.Empty
+	9,  // 55: squadaegis.pluginrpc.v1.HostAPI.RconBanWithEvidence:output_type -> squadaegis.pluginrpc.v1.BanResultResponse
+	28, // 56: squadaegis.pluginrpc.v1.HostAPI.RconWarnPlayerWithRule:output_type -> squadaegis.pluginrpc.v1.Empty
+	28, // 57: squadaegis.pluginrpc.v1.HostAPI.RconKickPlayerWithRule:output_type -> squadaegis.pluginrpc.v1.Empty
+	28, // 58: squadaegis.pluginrpc.v1.HostAPI.RconBanPlayerWithRule:output_type -> squadaegis.pluginrpc.v1.Empty
+	9,  // 59: squadaegis.pluginrpc.v1.HostAPI.RconBanWithEvidenceAndRule:output_type -> squadaegis.pluginrpc.v1.BanResultResponse
+	9,  // 60: squadaegis.pluginrpc.v1.HostAPI.RconBanWithEvidenceAndRuleAndMetadata:output_type -> squadaegis.pluginrpc.v1.BanResultResponse
+	28, // 61: squadaegis.pluginrpc.v1.HostAPI.RconRemovePlayerFromSquad:output_type -> squadaegis.pluginrpc.v1.Empty
+	28, // 62: squadaegis.pluginrpc.v1.HostAPI.RconRemovePlayerFromSquadById:output_type -> squadaegis.pluginrpc.v1.Empty
+	1,  // 63: squadaegis.pluginrpc.v1.HostAPI.ServerGetServerID:output_type -> squadaegis.pluginrpc.v1.StringResponse
+	0,  // 64: squadaegis.pluginrpc.v1.HostAPI.ServerGetServerInfo:output_type -> squadaegis.pluginrpc.v1.JSONResponse
+	0,  // 65: squadaegis.pluginrpc.v1.HostAPI.ServerGetPlayers:output_type -> squadaegis.pluginrpc.v1.JSONResponse
+	0,  // 66: squadaegis.pluginrpc.v1.HostAPI.ServerGetAdmins:output_type -> squadaegis.pluginrpc.v1.JSONResponse
+	0,  // 67: squadaegis.pluginrpc.v1.HostAPI.ServerGetSquads:output_type -> squadaegis.pluginrpc.v1.JSONResponse
+	0,  // 68: squadaegis.pluginrpc.v1.HostAPI.ServerListScopeServers:output_type -> squadaegis.pluginrpc.v1.JSONResponse
+	12, // 69: squadaegis.pluginrpc.v1.HostAPI.DatabaseGetPluginData:output_type -> squadaegis.pluginrpc.v1.DatabaseResponse
+	28, // 70: squadaegis.pluginrpc.v1.HostAPI.DatabaseSetPluginData:output_type -> squadaegis.pluginrpc.v1.Empty
+	28, // 71: squadaegis.pluginrpc.v1.HostAPI.DatabaseDeletePluginData:output_type -> squadaegis.pluginrpc.v1.Empty
+	0,  // 72: squadaegis.pluginrpc.v1.HostAPI.RuleListServerRules:output_type -> squadaegis.pluginrpc.v1.JSONResponse
+	0,  // 73: squadaegis.pluginrpc.v1.HostAPI.RuleListServerRuleActions:output_type -> squadaegis.pluginrpc.v1.JSONResponse
+	28, // 74: squadaegis.pluginrpc.v1.HostAPI.AdminAddTemporaryAdmin:output_type -> squadaegis.pluginrpc.v1.Empty
+	28, // 75: squadaegis.pluginrpc.v1.HostAPI.AdminRemoveTemporaryAdmin:output_type -> squadaegis.pluginrpc.v1.Empty
+	28, // 76: squadaegis.pluginrpc.v1.HostAPI.AdminRemoveTemporaryAdminRole:output_type -> squadaegis.pluginrpc.v1.Empty
+	0,  // 77: squadaegis.pluginrpc.v1.HostAPI.AdminGetPlayerAdminStatus:output_type -> squadaegis.pluginrpc.v1.JSONResponse
+	0,  // 78: squadaegis.pluginrpc.v1.HostAPI.AdminListTemporaryAdmins:output_type -> squadaegis.pluginrpc.v1.JSONResponse
+	28, // 79: squadaegis.pluginrpc.v1.HostAPI.EventPublishEvent:output_type -> squadaegis.pluginrpc.v1.Empty
+	20, // 80: squadaegis.pluginrpc.v1.HostAPI.EventSubscribe:output_type -> squadaegis.pluginrpc.v1.EventSubscriptionResponse
+	20, // 81: squadaegis.pluginrpc.v1.HostAPI.EventUnsubscribe:output_type -> squadaegis.pluginrpc.v1.EventSubscriptionResponse
+	0,  // 82: squadaegis.pluginrpc.v1.HostAPI.HistoryGetChatMessages:output_type -> squadaegis.pluginrpc.v1.JSONResponse
+	0,  // 83: squadaegis.pluginrpc.v1.HostAPI.HistoryGetPlayerDeaths:output_type -> squadaegis.pluginrpc.v1.JSONResponse
+	22, // 84: squadaegis.pluginrpc.v1.HostAPI.HistoryGetCurrentRoundStart:output_type -> squadaegis.pluginrpc.v1.RoundStartResponse
+	24, // 85: squadaegis.pluginrpc.v1.HostAPI.DiscordSendMessage:output_type -> squadaegis.pluginrpc.v1.DiscordMessageResponse
+	24, // 86: squadaegis.pluginrpc.v1.HostAPI.DiscordSendEmbed:output_type -> squadaegis.pluginrpc.v1.DiscordMessageResponse
+	26, // 87: squadaegis.pluginrpc.v1.HostAPI.ConnectorCall:output_type -> squadaegis.pluginrpc.v1.ConnectorCallResponse
+	46, // [46:88] is the sub-list for method output_type
+	4,  // [4:46] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_pkg_pluginrpc_proto_hostapi_proto_init() }
@@ -1536,7 +1798,7 @@ func file_pkg_pluginrpc_proto_hostapi_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_pluginrpc_proto_hostapi_proto_rawDesc), len(file_pkg_pluginrpc_proto_hostapi_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   23,
+			NumMessages:   27,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
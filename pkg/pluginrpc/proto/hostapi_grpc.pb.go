@@ -47,6 +47,7 @@ const (
 	HostAPI_ServerGetPlayers_FullMethodName                      = "/squadaegis.pluginrpc.v1.HostAPI/ServerGetPlayers"
 	HostAPI_ServerGetAdmins_FullMethodName                       = "/squadaegis.pluginrpc.v1.HostAPI/ServerGetAdmins"
 	HostAPI_ServerGetSquads_FullMethodName                       = "/squadaegis.pluginrpc.v1.HostAPI/ServerGetSquads"
+	HostAPI_ServerListScopeServers_FullMethodName                = "/squadaegis.pluginrpc.v1.HostAPI/ServerListScopeServers"
 	HostAPI_DatabaseGetPluginData_FullMethodName                 = "/squadaegis.pluginrpc.v1.HostAPI/DatabaseGetPluginData"
 	HostAPI_DatabaseSetPluginData_FullMethodName                 = "/squadaegis.pluginrpc.v1.HostAPI/DatabaseSetPluginData"
 	HostAPI_DatabaseDeletePluginData_FullMethodName              = "/squadaegis.pluginrpc.v1.HostAPI/DatabaseDeletePluginData"
@@ -58,6 +59,11 @@ const (
 	HostAPI_AdminGetPlayerAdminStatus_FullMethodName             = "/squadaegis.pluginrpc.v1.HostAPI/AdminGetPlayerAdminStatus"
 	HostAPI_AdminListTemporaryAdmins_FullMethodName              = "/squadaegis.pluginrpc.v1.HostAPI/AdminListTemporaryAdmins"
 	HostAPI_EventPublishEvent_FullMethodName                     = "/squadaegis.pluginrpc.v1.HostAPI/EventPublishEvent"
+	HostAPI_EventSubscribe_FullMethodName                        = "/squadaegis.pluginrpc.v1.HostAPI/EventSubscribe"
+	HostAPI_EventUnsubscribe_FullMethodName                      = "/squadaegis.pluginrpc.v1.HostAPI/EventUnsubscribe"
+	HostAPI_HistoryGetChatMessages_FullMethodName                = "/squadaegis.pluginrpc.v1.HostAPI/HistoryGetChatMessages"
+	HostAPI_HistoryGetPlayerDeaths_FullMethodName                = "/squadaegis.pluginrpc.v1.HostAPI/HistoryGetPlayerDeaths"
+	HostAPI_HistoryGetCurrentRoundStart_FullMethodName           = "/squadaegis.pluginrpc.v1.HostAPI/HistoryGetCurrentRoundStart"
 	HostAPI_DiscordSendMessage_FullMethodName                    = "/squadaegis.pluginrpc.v1.HostAPI/DiscordSendMessage"
 	HostAPI_DiscordSendEmbed_FullMethodName                      = "/squadaegis.pluginrpc.v1.HostAPI/DiscordSendEmbed"
 	HostAPI_ConnectorCall_FullMethodName                         = "/squadaegis.pluginrpc.v1.HostAPI/ConnectorCall"
@@ -92,6 +98,10 @@ type HostAPIClient interface {
 	ServerGetPlayers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*JSONResponse, error)
 	ServerGetAdmins(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*JSONResponse, error)
 	ServerGetSquads(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*JSONResponse, error)
+	// JSON-encoded list of the server IDs a global instance is scoped to.
+	// Rcon* and Server* calls from a global instance name their server in
+	// the x-aegis-server-id request metadata.
+	ServerListScopeServers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*JSONResponse, error)
 	// -- Database --------------------------------------------------------
 	DatabaseGetPluginData(ctx context.Context, in *DatabaseRequest, opts ...grpc.CallOption) (*DatabaseResponse, error)
 	DatabaseSetPluginData(ctx context.Context, in *DatabaseRequest, opts ...grpc.CallOption) (*Empty, error)
@@ -107,6 +117,12 @@ type HostAPIClient interface {
 	AdminListTemporaryAdmins(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*JSONResponse, error)
 	// -- Event -----------------------------------------------------------
 	EventPublishEvent(ctx context.Context, in *PublishEventRequest, opts ...grpc.CallOption) (*Empty, error)
+	EventSubscribe(ctx context.Context, in *EventSubscriptionRequest, opts ...grpc.CallOption) (*EventSubscriptionResponse, error)
+	EventUnsubscribe(ctx context.Context, in *EventSubscriptionRequest, opts ...grpc.CallOption) (*EventSubscriptionResponse, error)
+	// -- History ---------------------------------------------------------
+	HistoryGetChatMessages(ctx context.Context, in *HistoryQueryRequest, opts ...grpc.CallOption) (*JSONResponse, error)
+	HistoryGetPlayerDeaths(ctx context.Context, in *HistoryQueryRequest, opts ...grpc.CallOption) (*JSONResponse, error)
+	HistoryGetCurrentRoundStart(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RoundStartResponse, error)
 	// -- Discord ---------------------------------------------------------
 	DiscordSendMessage(ctx context.Context, in *DiscordMessageRequest, opts ...grpc.CallOption) (*DiscordMessageResponse, error)
 	DiscordSendEmbed(ctx context.Context, in *DiscordMessageRequest, opts ...grpc.CallOption) (*DiscordMessageResponse, error)
@@ -342,6 +358,16 @@ func (c *hostAPIClient) ServerGetSquads(ctx context.Context, in *Empty, opts ...
 	return out, nil
 }
 
+func (c *hostAPIClient) ServerListScopeServers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*JSONResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JSONResponse)
+	err := c.cc.Invoke(ctx, HostAPI_ServerListScopeServers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *hostAPIClient) DatabaseGetPluginData(ctx context.Context, in *DatabaseRequest, opts ...grpc.CallOption) (*DatabaseResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DatabaseResponse)
@@ -452,6 +478,56 @@ func (c *hostAPIClient) EventPublishEvent(ctx context.Context, in *PublishEventR
 	return out, nil
 }
 
+func (c *hostAPIClient) EventSubscribe(ctx context.Context, in *EventSubscriptionRequest, opts ...grpc.CallOption) (*EventSubscriptionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EventSubscriptionResponse)
+	err := c.cc.Invoke(ctx, HostAPI_EventSubscribe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hostAPIClient) EventUnsubscribe(ctx context.Context, in *EventSubscriptionRequest, opts ...grpc.CallOption) (*EventSubscriptionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EventSubscriptionResponse)
+	err := c.cc.Invoke(ctx, HostAPI_EventUnsubscribe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hostAPIClient) HistoryGetChatMessages(ctx context.Context, in *HistoryQueryRequest, opts ...grpc.CallOption) (*JSONResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JSONResponse)
+	err := c.cc.Invoke(ctx, HostAPI_HistoryGetChatMessages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hostAPIClient) HistoryGetPlayerDeaths(ctx context.Context, in *HistoryQueryRequest, opts ...grpc.CallOption) (*JSONResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JSONResponse)
+	err := c.cc.Invoke(ctx, HostAPI_HistoryGetPlayerDeaths_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hostAPIClient) HistoryGetCurrentRoundStart(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RoundStartResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoundStartResponse)
+	err := c.cc.Invoke(ctx, HostAPI_HistoryGetCurrentRoundStart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *hostAPIClient) DiscordSendMessage(ctx context.Context, in *DiscordMessageRequest, opts ...grpc.CallOption) (*DiscordMessageResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DiscordMessageResponse)
@@ -511,6 +587,10 @@ type HostAPIServer interface {
 	ServerGetPlayers(context.Context, *Empty) (*JSONResponse, error)
 	ServerGetAdmins(context.Context, *Empty) (*JSONResponse, error)
 	ServerGetSquads(context.Context, *Empty) (*JSONResponse, error)
+	// JSON-encoded list of the server IDs a global instance is scoped to.
+	// Rcon* and Server* calls from a global instance name their server in
+	// the x-aegis-server-id request metadata.
+	ServerListScopeServers(context.Context, *Empty) (*JSONResponse, error)
 	// -- Database --------------------------------------------------------
 	DatabaseGetPluginData(context.Context, *DatabaseRequest) (*DatabaseResponse, error)
 	DatabaseSetPluginData(context.Context, *DatabaseRequest) (*Empty, error)
@@ -526,6 +606,12 @@ type HostAPIServer interface {
 	AdminListTemporaryAdmins(context.Context, *Empty) (*JSONResponse, error)
 	// -- Event -----------------------------------------------------------
 	EventPublishEvent(context.Context, *PublishEventRequest) (*Empty, error)
+	EventSubscribe(context.Context, *EventSubscriptionRequest) (*EventSubscriptionResponse, error)
+	EventUnsubscribe(context.Context, *EventSubscriptionRequest) (*EventSubscriptionResponse, error)
+	// -- History ---------------------------------------------------------
+	HistoryGetChatMessages(context.Context, *HistoryQueryRequest) (*JSONResponse, error)
+	HistoryGetPlayerDeaths(context.Context, *HistoryQueryRequest) (*JSONResponse, error)
+	HistoryGetCurrentRoundStart(context.Context, *Empty) (*RoundStartResponse, error)
 	// -- Discord ---------------------------------------------------------
 	DiscordSendMessage(context.Context, *DiscordMessageRequest) (*DiscordMessageResponse, error)
 	DiscordSendEmbed(context.Context, *DiscordMessageRequest) (*DiscordMessageResponse, error)
@@ -607,6 +693,9 @@ func (UnimplementedHostAPIServer) ServerGetAdmins(context.Context, *Empty) (*JSO
 func (UnimplementedHostAPIServer) ServerGetSquads(context.Context, *Empty) (*JSONResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ServerGetSquads not implemented")
 }
+func (UnimplementedHostAPIServer) ServerListScopeServers(context.Context, *Empty) (*JSONResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ServerListScopeServers not implemented")
+}
 func (UnimplementedHostAPIServer) DatabaseGetPluginData(context.Context, *DatabaseRequest) (*DatabaseResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method DatabaseGetPluginData not implemented")
 }
@@ -640,6 +729,21 @@ func (UnimplementedHostAPIServer) AdminListTemporaryAdmins(context.Context, *Emp
 func (UnimplementedHostAPIServer) EventPublishEvent(context.Context, *PublishEventRequest) (*Empty, error) {
 	return nil, status.Error(codes.Unimplemented, "method EventPublishEvent not implemented")
 }
+func (UnimplementedHostAPIServer) EventSubscribe(context.Context, *EventSubscriptionRequest) (*EventSubscriptionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EventSubscribe not implemented")
+}
+func (UnimplementedHostAPIServer) EventUnsubscribe(context.Context, *EventSubscriptionRequest) (*EventSubscriptionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EventUnsubscribe not implemented")
+}
+func (UnimplementedHostAPIServer) HistoryGetChatMessages(context.Context, *HistoryQueryRequest) (*JSONResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method HistoryGetChatMessages not implemented")
+}
+func (UnimplementedHostAPIServer) HistoryGetPlayerDeaths(context.Context, *HistoryQueryRequest) (*JSONResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method HistoryGetPlayerDeaths not implemented")
+}
+func (UnimplementedHostAPIServer) HistoryGetCurrentRoundStart(context.Context, *Empty) (*RoundStartResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method HistoryGetCurrentRoundStart not implemented")
+}
 func (UnimplementedHostAPIServer) DiscordSendMessage(context.Context, *DiscordMessageRequest) (*DiscordMessageResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method DiscordSendMessage not implemented")
 }
@@ -1066,6 +1170,24 @@ func _HostAPI_ServerGetSquads_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HostAPI_ServerListScopeServers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HostAPIServer).ServerListScopeServers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HostAPI_ServerListScopeServers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HostAPIServer).ServerListScopeServers(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _HostAPI_DatabaseGetPluginData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DatabaseRequest)
 	if err := dec(in); err != nil {
@@ -1264,6 +1386,96 @@ func _HostAPI_EventPublishEvent_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HostAPI_EventSubscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EventSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HostAPIServer).EventSubscribe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HostAPI_EventSubscribe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HostAPIServer).EventSubscribe(ctx, req.(*EventSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HostAPI_EventUnsubscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EventSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HostAPIServer).EventUnsubscribe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HostAPI_EventUnsubscribe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HostAPIServer).EventUnsubscribe(ctx, req.(*EventSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HostAPI_HistoryGetChatMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoryQueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HostAPIServer).HistoryGetChatMessages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HostAPI_HistoryGetChatMessages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HostAPIServer).HistoryGetChatMessages(ctx, req.(*HistoryQueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HostAPI_HistoryGetPlayerDeaths_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoryQueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HostAPIServer).HistoryGetPlayerDeaths(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HostAPI_HistoryGetPlayerDeaths_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HostAPIServer).HistoryGetPlayerDeaths(ctx, req.(*HistoryQueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HostAPI_HistoryGetCurrentRoundStart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HostAPIServer).HistoryGetCurrentRoundStart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HostAPI_HistoryGetCurrentRoundStart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HostAPIServer).HistoryGetCurrentRoundStart(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _HostAPI_DiscordSendMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DiscordMessageRequest)
 	if err := dec(in); err != nil {
@@ -1413,6 +1625,10 @@ var HostAPI_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ServerGetSquads",
 			Handler:    _HostAPI_ServerGetSquads_Handler,
 		},
+		{
+			MethodName: "ServerListScopeServers",
+			Handler:    _HostAPI_ServerListScopeServers_Handler,
+		},
 		{
 			MethodName: "DatabaseGetPluginData",
 			Handler:    _HostAPI_DatabaseGetPluginData_Handler,
@@ -1457,6 +1673,26 @@ var HostAPI_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "EventPublishEvent",
 			Handler:    _HostAPI_EventPublishEvent_Handler,
 		},
+		{
+			MethodName: "EventSubscribe",
+			Handler:    _HostAPI_EventSubscribe_Handler,
+		},
+		{
+			MethodName: "EventUnsubscribe",
+			Handler:    _HostAPI_EventUnsubscribe_Handler,
+		},
+		{
+			MethodName: "HistoryGetChatMessages",
+			Handler:    _HostAPI_HistoryGetChatMessages_Handler,
+		},
+		{
+			MethodName: "HistoryGetPlayerDeaths",
+			Handler:    _HostAPI_HistoryGetPlayerDeaths_Handler,
+		},
+		{
+			MethodName: "HistoryGetCurrentRoundStart",
+			Handler:    _HostAPI_HistoryGetCurrentRoundStart_Handler,
+		},
 		{
 			MethodName: "DiscordSendMessage",
 			Handler:    _HostAPI_DiscordSendMessage_Handler,
@@ -168,8 +168,10 @@ func (x *ConfigField) GetNested() []*ConfigField {
 }
 
 type ConfigSchema struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Fields        []*ConfigField         `protobuf:"bytes,1,rep,name=fields,proto3" json:"fields,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Fields []*ConfigField         `protobuf:"bytes,1,rep,name=fields,proto3" json:"fields,omitempty"`
+	// Schema version; zero means unversioned.
+	Version       int32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -211,6 +213,13 @@ func (x *ConfigSchema) GetFields() []*ConfigField {
 	return nil
 }
 
+func (x *ConfigSchema) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 type PluginDefinition struct {
 	state                  protoimpl.MessageState `protogen:"open.v1"`
 	PluginId               string                 `protobuf:"bytes,1,opt,name=plugin_id,json=pluginId,proto3" json:"plugin_id,omitempty"`
@@ -220,8 +229,15 @@ type PluginDefinition struct {
 	OptionalConnectors     []string               `protobuf:"bytes,5,rep,name=optional_connectors,json=optionalConnectors,proto3" json:"optional_connectors,omitempty"`
 	ConfigSchema           *ConfigSchema          `protobuf:"bytes,6,opt,name=config_schema,json=configSchema,proto3" json:"config_schema,omitempty"`
 	Events                 []string               `protobuf:"bytes,7,rep,name=events,proto3" json:"events,omitempty"`
-	unknownFields          protoimpl.UnknownFields
-	sizeCache              protoimpl.SizeCache
+	// Set by the SDK when the plugin can migrate configs written for an
+	// older config schema version.
+	MigratesConfig bool         `protobuf:"varint,8,opt,name=migrates_config,json=migratesConfig,proto3" json:"migrates_config,omitempty"`
+	HttpRoutes     []*HTTPRoute `protobuf:"bytes,9,rep,name=http_routes,json=httpRoutes,proto3" json:"http_routes,omitempty"`
+	// JSON-encoded list of dashboard panels.
+	PanelsJson           []byte `protobuf:"bytes,10,opt,name=panels_json,json=panelsJson,proto3" json:"panels_json,omitempty"`
+	AllowGlobalInstances bool   `protobuf:"varint,11,opt,name=allow_global_instances,json=allowGlobalInstances,proto3" json:"allow_global_instances,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *PluginDefinition) Reset() {
@@ -303,6 +319,104 @@ func (x *PluginDefinition) GetEvents() []string {
 	return nil
 }
 
+func (x *PluginDefinition) GetMigratesConfig() bool {
+	if x != nil {
+		return x.MigratesConfig
+	}
+	return false
+}
+
+func (x *PluginDefinition) GetHttpRoutes() []*HTTPRoute {
+	if x != nil {
+		return x.HttpRoutes
+	}
+	return nil
+}
+
+func (x *PluginDefinition) GetPanelsJson() []byte {
+	if x != nil {
+		return x.PanelsJson
+	}
+	return nil
+}
+
+func (x *PluginDefinition) GetAllowGlobalInstances() bool {
+	if x != nil {
+		return x.AllowGlobalInstances
+	}
+	return false
+}
+
+// HTTPRoute declares an endpoint the plugin serves under the host's
+// plugin HTTP namespace.
+type HTTPRoute struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Method        string                 `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Access        string                 `protobuf:"bytes,3,opt,name=access,proto3" json:"access,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HTTPRoute) Reset() {
+	*x = HTTPRoute{}
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HTTPRoute) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HTTPRoute) ProtoMessage() {}
+
+func (x *HTTPRoute) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HTTPRoute.ProtoReflect.Descriptor instead.
+func (*HTTPRoute) Descriptor() ([]byte, []int) {
+	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *HTTPRoute) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *HTTPRoute) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *HTTPRoute) GetAccess() string {
+	if x != nil {
+		return x.Access
+	}
+	return ""
+}
+
+func (x *HTTPRoute) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
 type InitializeRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// JSON-encoded config map.
@@ -318,7 +432,7 @@ type InitializeRequest struct {
 
 func (x *InitializeRequest) Reset() {
 	*x = InitializeRequest{}
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[4]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -330,7 +444,7 @@ func (x *InitializeRequest) String() string {
 func (*InitializeRequest) ProtoMessage() {}
 
 func (x *InitializeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[4]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -343,7 +457,7 @@ func (x *InitializeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InitializeRequest.ProtoReflect.Descriptor instead.
 func (*InitializeRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{4}
+	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *InitializeRequest) GetConfigJson() []byte {
@@ -397,7 +511,7 @@ type PluginEvent struct {
 
 func (x *PluginEvent) Reset() {
 	*x = PluginEvent{}
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[5]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -409,7 +523,7 @@ func (x *PluginEvent) String() string {
 func (*PluginEvent) ProtoMessage() {}
 
 func (x *PluginEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[5]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -422,7 +536,7 @@ func (x *PluginEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginEvent.ProtoReflect.Descriptor instead.
 func (*PluginEvent) Descriptor() ([]byte, []int) {
-	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{5}
+	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *PluginEvent) GetId() string {
@@ -483,7 +597,7 @@ type StatusResponse struct {
 
 func (x *StatusResponse) Reset() {
 	*x = StatusResponse{}
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[6]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -495,7 +609,7 @@ func (x *StatusResponse) String() string {
 func (*StatusResponse) ProtoMessage() {}
 
 func (x *StatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[6]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -508,7 +622,7 @@ func (x *StatusResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
 func (*StatusResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{6}
+	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *StatusResponse) GetStatus() string {
@@ -529,7 +643,7 @@ type ConfigJSON struct {
 
 func (x *ConfigJSON) Reset() {
 	*x = ConfigJSON{}
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[7]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -541,7 +655,7 @@ func (x *ConfigJSON) String() string {
 func (*ConfigJSON) ProtoMessage() {}
 
 func (x *ConfigJSON) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[7]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -554,7 +668,7 @@ func (x *ConfigJSON) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ConfigJSON.ProtoReflect.Descriptor instead.
 func (*ConfigJSON) Descriptor() ([]byte, []int) {
-	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{7}
+	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ConfigJSON) GetConfigJson() []byte {
@@ -564,6 +678,60 @@ func (x *ConfigJSON) GetConfigJson() []byte {
 	return nil
 }
 
+// MigrateConfigRequest carries a stored config written for an older config
+// schema version. The plugin returns it rewritten for its current version.
+type MigrateConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromVersion   int32                  `protobuf:"varint,1,opt,name=from_version,json=fromVersion,proto3" json:"from_version,omitempty"`
+	ConfigJson    []byte                 `protobuf:"bytes,2,opt,name=config_json,json=configJson,proto3" json:"config_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MigrateConfigRequest) Reset() {
+	*x = MigrateConfigRequest{}
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MigrateConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigrateConfigRequest) ProtoMessage() {}
+
+func (x *MigrateConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigrateConfigRequest.ProtoReflect.Descriptor instead.
+func (*MigrateConfigRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *MigrateConfigRequest) GetFromVersion() int32 {
+	if x != nil {
+		return x.FromVersion
+	}
+	return 0
+}
+
+func (x *MigrateConfigRequest) GetConfigJson() []byte {
+	if x != nil {
+		return x.ConfigJson
+	}
+	return nil
+}
+
 type CommandList struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Commands      []*PluginCommand       `protobuf:"bytes,1,rep,name=commands,proto3" json:"commands,omitempty"`
@@ -573,7 +741,7 @@ type CommandList struct {
 
 func (x *CommandList) Reset() {
 	*x = CommandList{}
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[8]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -585,7 +753,7 @@ func (x *CommandList) String() string {
 func (*CommandList) ProtoMessage() {}
 
 func (x *CommandList) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[8]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -598,7 +766,7 @@ func (x *CommandList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CommandList.ProtoReflect.Descriptor instead.
 func (*CommandList) Descriptor() ([]byte, []int) {
-	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{8}
+	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *CommandList) GetCommands() []*PluginCommand {
@@ -624,7 +792,7 @@ type PluginCommand struct {
 
 func (x *PluginCommand) Reset() {
 	*x = PluginCommand{}
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[9]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -636,7 +804,7 @@ func (x *PluginCommand) String() string {
 func (*PluginCommand) ProtoMessage() {}
 
 func (x *PluginCommand) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[9]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -649,7 +817,7 @@ func (x *PluginCommand) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginCommand.ProtoReflect.Descriptor instead.
 func (*PluginCommand) Descriptor() ([]byte, []int) {
-	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{9}
+	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *PluginCommand) GetId() string {
@@ -719,7 +887,7 @@ type ExecuteCommandRequest struct {
 
 func (x *ExecuteCommandRequest) Reset() {
 	*x = ExecuteCommandRequest{}
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[10]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -731,7 +899,7 @@ func (x *ExecuteCommandRequest) String() string {
 func (*ExecuteCommandRequest) ProtoMessage() {}
 
 func (x *ExecuteCommandRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[10]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -744,7 +912,7 @@ func (x *ExecuteCommandRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExecuteCommandRequest.ProtoReflect.Descriptor instead.
 func (*ExecuteCommandRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{10}
+	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *ExecuteCommandRequest) GetCommandId() string {
@@ -775,7 +943,7 @@ type CommandResult struct {
 
 func (x *CommandResult) Reset() {
 	*x = CommandResult{}
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[11]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -787,7 +955,7 @@ func (x *CommandResult) String() string {
 func (*CommandResult) ProtoMessage() {}
 
 func (x *CommandResult) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[11]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -800,7 +968,7 @@ func (x *CommandResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CommandResult.ProtoReflect.Descriptor instead.
 func (*CommandResult) Descriptor() ([]byte, []int) {
-	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{11}
+	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *CommandResult) GetSuccess() bool {
@@ -847,7 +1015,7 @@ type ExecutionIDRequest struct {
 
 func (x *ExecutionIDRequest) Reset() {
 	*x = ExecutionIDRequest{}
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[12]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -859,7 +1027,7 @@ func (x *ExecutionIDRequest) String() string {
 func (*ExecutionIDRequest) ProtoMessage() {}
 
 func (x *ExecutionIDRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[12]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -872,7 +1040,7 @@ func (x *ExecutionIDRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExecutionIDRequest.ProtoReflect.Descriptor instead.
 func (*ExecutionIDRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{12}
+	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *ExecutionIDRequest) GetExecutionId() string {
@@ -898,7 +1066,7 @@ type CommandExecutionStatus struct {
 
 func (x *CommandExecutionStatus) Reset() {
 	*x = CommandExecutionStatus{}
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[13]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -910,7 +1078,7 @@ func (x *CommandExecutionStatus) String() string {
 func (*CommandExecutionStatus) ProtoMessage() {}
 
 func (x *CommandExecutionStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[13]
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -923,7 +1091,7 @@ func (x *CommandExecutionStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CommandExecutionStatus.ProtoReflect.Descriptor instead.
 func (*CommandExecutionStatus) Descriptor() ([]byte, []int) {
-	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{13}
+	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *CommandExecutionStatus) GetExecutionId() string {
@@ -982,6 +1150,161 @@ func (x *CommandExecutionStatus) GetCompletedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+// HTTPRequest is a request the host forwards to a declared HTTP route.
+type HTTPRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Method string                 `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	Path   string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// The declared route path the request matched.
+	Route  string            `protobuf:"bytes,3,opt,name=route,proto3" json:"route,omitempty"`
+	Params map[string]string `protobuf:"bytes,4,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// JSON-encoded map of query parameters to their values.
+	QueryJson     []byte `protobuf:"bytes,5,opt,name=query_json,json=queryJson,proto3" json:"query_json,omitempty"`
+	Body          []byte `protobuf:"bytes,6,opt,name=body,proto3" json:"body,omitempty"`
+	UserId        string `protobuf:"bytes,7,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HTTPRequest) Reset() {
+	*x = HTTPRequest{}
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HTTPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HTTPRequest) ProtoMessage() {}
+
+func (x *HTTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HTTPRequest.ProtoReflect.Descriptor instead.
+func (*HTTPRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *HTTPRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *HTTPRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *HTTPRequest) GetRoute() string {
+	if x != nil {
+		return x.Route
+	}
+	return ""
+}
+
+func (x *HTTPRequest) GetParams() map[string]string {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *HTTPRequest) GetQueryJson() []byte {
+	if x != nil {
+		return x.QueryJson
+	}
+	return nil
+}
+
+func (x *HTTPRequest) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+func (x *HTTPRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type HTTPResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Body          []byte                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HTTPResponse) Reset() {
+	*x = HTTPResponse{}
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HTTPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HTTPResponse) ProtoMessage() {}
+
+func (x *HTTPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pluginrpc_proto_plugin_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HTTPResponse.ProtoReflect.Descriptor instead.
+func (*HTTPResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *HTTPResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *HTTPResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *HTTPResponse) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
 var File_pkg_pluginrpc_proto_plugin_proto protoreflect.FileDescriptor
 
 const file_pkg_pluginrpc_proto_plugin_proto_rawDesc = "" +
@@ -996,9 +1319,10 @@ const file_pkg_pluginrpc_proto_plugin_proto_rawDesc = "" +
 	"\fdefault_json\x18\x05 \x01(\fR\vdefaultJson\x12\x1c\n" +
 	"\tsensitive\x18\x06 \x01(\bR\tsensitive\x12!\n" +
 	"\foptions_json\x18\a \x01(\fR\voptionsJson\x12<\n" +
-	"\x06nested\x18\b \x03(\v2$.squadaegis.pluginrpc.v1.ConfigFieldR\x06nested\"L\n" +
+	"\x06nested\x18\b \x03(\v2$.squadaegis.pluginrpc.v1.ConfigFieldR\x06nested\"f\n" +
 	"\fConfigSchema\x12<\n" +
-	"\x06fields\x18\x01 \x03(\v2$.squadaegis.pluginrpc.v1.ConfigFieldR\x06fields\"\xd2\x02\n" +
+	"\x06fields\x18\x01 \x03(\v2$.squadaegis.pluginrpc.v1.ConfigFieldR\x06fields\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\"\x97\x04\n" +
 	"\x10PluginDefinition\x12\x1b\n" +
 	"\tplugin_id\x18\x01 \x01(\tR\bpluginId\x128\n" +
 	"\x18allow_multiple_instances\x18\x02 \x01(\bR\x16allowMultipleInstances\x12!\n" +
@@ -1006,7 +1330,19 @@ const file_pkg_pluginrpc_proto_plugin_proto_rawDesc = "" +
 	"\x13required_connectors\x18\x04 \x03(\tR\x12requiredConnectors\x12/\n" +
 	"\x13optional_connectors\x18\x05 \x03(\tR\x12optionalConnectors\x12J\n" +
 	"\rconfig_schema\x18\x06 \x01(\v2%.squadaegis.pluginrpc.v1.ConfigSchemaR\fconfigSchema\x12\x16\n" +
-	"\x06events\x18\a \x03(\tR\x06events\"\xbc\x01\n" +
+	"\x06events\x18\a \x03(\tR\x06events\x12'\n" +
+	"\x0fmigrates_config\x18\b \x01(\bR\x0emigratesConfig\x12C\n" +
+	"\vhttp_routes\x18\t \x03(\v2\".squadaegis.pluginrpc.v1.HTTPRouteR\n" +
+	"httpRoutes\x12\x1f\n" +
+	"\vpanels_json\x18\n" +
+	" \x01(\fR\n" +
+	"panelsJson\x124\n" +
+	"\x16allow_global_instances\x18\v \x01(\bR\x14allowGlobalInstances\"q\n" +
+	"\tHTTPRoute\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x16\n" +
+	"\x06access\x18\x03 \x01(\tR\x06access\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\"\xbc\x01\n" +
 	"\x11InitializeRequest\x12\x1f\n" +
 	"\vconfig_json\x18\x01 \x01(\fR\n" +
 	"configJson\x12+\n" +
@@ -1028,6 +1364,10 @@ const file_pkg_pluginrpc_proto_plugin_proto_rawDesc = "" +
 	"\n" +
 	"ConfigJSON\x12\x1f\n" +
 	"\vconfig_json\x18\x01 \x01(\fR\n" +
+	"configJson\"Z\n" +
+	"\x14MigrateConfigRequest\x12!\n" +
+	"\ffrom_version\x18\x01 \x01(\x05R\vfromVersion\x12\x1f\n" +
+	"\vconfig_json\x18\x02 \x01(\fR\n" +
 	"configJson\"Q\n" +
 	"\vCommandList\x12B\n" +
 	"\bcommands\x18\x01 \x03(\v2&.squadaegis.pluginrpc.v1.PluginCommandR\bcommands\"\xbb\x02\n" +
@@ -1065,7 +1405,23 @@ const file_pkg_pluginrpc_proto_plugin_proto_rawDesc = "" +
 	"\x06result\x18\x06 \x01(\v2&.squadaegis.pluginrpc.v1.CommandResultR\x06result\x129\n" +
 	"\n" +
 	"started_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
-	"\fcompleted_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt2\xdb\a\n" +
+	"\fcompleted_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\"\xa0\x02\n" +
+	"\vHTTPRequest\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x14\n" +
+	"\x05route\x18\x03 \x01(\tR\x05route\x12H\n" +
+	"\x06params\x18\x04 \x03(\v20.squadaegis.pluginrpc.v1.HTTPRequest.ParamsEntryR\x06params\x12\x1d\n" +
+	"\n" +
+	"query_json\x18\x05 \x01(\fR\tqueryJson\x12\x12\n" +
+	"\x04body\x18\x06 \x01(\fR\x04body\x12\x17\n" +
+	"\auser_id\x18\a \x01(\tR\x06userId\x1a9\n" +
+	"\vParamsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"]\n" +
+	"\fHTTPResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\fR\x04body2\x9b\t\n" +
 	"\x06Plugin\x12Z\n" +
 	"\rGetDefinition\x12\x1e.squadaegis.pluginrpc.v1.Empty\x1a).squadaegis.pluginrpc.v1.PluginDefinition\x12X\n" +
 	"\n" +
@@ -1078,7 +1434,10 @@ const file_pkg_pluginrpc_proto_plugin_proto_rawDesc = "" +
 	"\fUpdateConfig\x12#.squadaegis.pluginrpc.v1.ConfigJSON\x1a\x1e.squadaegis.pluginrpc.v1.Empty\x12S\n" +
 	"\vGetCommands\x12\x1e.squadaegis.pluginrpc.v1.Empty\x1a$.squadaegis.pluginrpc.v1.CommandList\x12h\n" +
 	"\x0eExecuteCommand\x12..squadaegis.pluginrpc.v1.ExecuteCommandRequest\x1a&.squadaegis.pluginrpc.v1.CommandResult\x12y\n" +
-	"\x19GetCommandExecutionStatus\x12+.squadaegis.pluginrpc.v1.ExecutionIDRequest\x1a/.squadaegis.pluginrpc.v1.CommandExecutionStatusB?Z=go.codycody31.dev/squad-aegis/pkg/pluginrpc/proto;pluginrpcpbb\x06proto3"
+	"\x19GetCommandExecutionStatus\x12+.squadaegis.pluginrpc.v1.ExecutionIDRequest\x1a/.squadaegis.pluginrpc.v1.CommandExecutionStatus\x12c\n" +
+	"\rMigrateConfig\x12-.squadaegis.pluginrpc.v1.MigrateConfigRequest\x1a#.squadaegis.pluginrpc.v1.ConfigJSON\x12Y\n" +
+	"\n" +
+	"HandleHTTP\x12$.squadaegis.pluginrpc.v1.HTTPRequest\x1a%.squadaegis.pluginrpc.v1.HTTPResponseB?Z=go.codycody31.dev/squad-aegis/pkg/pluginrpc/proto;pluginrpcpbb\x06proto3"
 
 var (
 	file_pkg_pluginrpc_proto_plugin_proto_rawDescOnce sync.Once
@@ -1092,61 +1451,72 @@ func file_pkg_pluginrpc_proto_plugin_proto_rawDescGZIP() []byte {
 	return file_pkg_pluginrpc_proto_plugin_proto_rawDescData
 }
 
-var file_pkg_pluginrpc_proto_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_pkg_pluginrpc_proto_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
 var file_pkg_pluginrpc_proto_plugin_proto_goTypes = []any{
 	(*Empty)(nil),                  // 0: squadaegis.pluginrpc.v1.Empty
 	(*ConfigField)(nil),            // 1: squadaegis.pluginrpc.v1.ConfigField
 	(*ConfigSchema)(nil),           // 2: squadaegis.pluginrpc.v1.ConfigSchema
 	(*PluginDefinition)(nil),       // 3: squadaegis.pluginrpc.v1.PluginDefinition
-	(*InitializeRequest)(nil),      // 4: squadaegis.pluginrpc.v1.InitializeRequest
-	(*PluginEvent)(nil),            // 5: squadaegis.pluginrpc.v1.PluginEvent
-	(*StatusResponse)(nil),         // 6: squadaegis.pluginrpc.v1.StatusResponse
-	(*ConfigJSON)(nil),             // 7: squadaegis.pluginrpc.v1.ConfigJSON
-	(*CommandList)(nil),            // 8: squadaegis.pluginrpc.v1.CommandList
-	(*PluginCommand)(nil),          // 9: squadaegis.pluginrpc.v1.PluginCommand
-	(*ExecuteCommandRequest)(nil),  // 10: squadaegis.pluginrpc.v1.ExecuteCommandRequest
-	(*CommandResult)(nil),          // 11: squadaegis.pluginrpc.v1.CommandResult
-	(*ExecutionIDRequest)(nil),     // 12: squadaegis.pluginrpc.v1.ExecutionIDRequest
-	(*CommandExecutionStatus)(nil), // 13: squadaegis.pluginrpc.v1.CommandExecutionStatus
-	(*timestamppb.Timestamp)(nil),  // 14: google.protobuf.Timestamp
+	(*HTTPRoute)(nil),              // 4: squadaegis.pluginrpc.v1.HTTPRoute
+	(*InitializeRequest)(nil),      // 5: squadaegis.pluginrpc.v1.InitializeRequest
+	(*PluginEvent)(nil),            // 6: squadaegis.pluginrpc.v1.PluginEvent
+	(*StatusResponse)(nil),         // 7: squadaegis.pluginrpc.v1.StatusResponse
+	(*ConfigJSON)(nil),             // 8: squadaegis.pluginrpc.v1.ConfigJSON
+	(*MigrateConfigRequest)(nil),   // 9: squadaegis.pluginrpc.v1.MigrateConfigRequest
+	(*CommandList)(nil),            // 10: squadaegis.pluginrpc.v1.CommandList
+	(*PluginCommand)(nil),          // 11: squadaegis.pluginrpc.v1.PluginCommand
+	(*ExecuteCommandRequest)(nil),  // 12: squadaegis.pluginrpc.v1.ExecuteCommandRequest
+	(*CommandResult)(nil),          // 13: squadaegis.pluginrpc.v1.CommandResult
+	(*ExecutionIDRequest)(nil),     // 14: squadaegis.pluginrpc.v1.ExecutionIDRequest
+	(*CommandExecutionStatus)(nil), // 15: squadaegis.pluginrpc.v1.CommandExecutionStatus
+	(*HTTPRequest)(nil),            // 16: squadaegis.pluginrpc.v1.HTTPRequest
+	(*HTTPResponse)(nil),           // 17: squadaegis.pluginrpc.v1.HTTPResponse
+	nil,                            // 18: squadaegis.pluginrpc.v1.HTTPRequest.ParamsEntry
+	(*timestamppb.Timestamp)(nil),  // 19: google.protobuf.Timestamp
 }
 var file_pkg_pluginrpc_proto_plugin_proto_depIdxs = []int32{
 	1,  // 0: squadaegis.pluginrpc.v1.ConfigField.nested:type_name -> squadaegis.pluginrpc.v1.ConfigField
 	1,  // 1: squadaegis.pluginrpc.v1.ConfigSchema.fields:type_name -> squadaegis.pluginrpc.v1.ConfigField
 	2,  // 2: squadaegis.pluginrpc.v1.PluginDefinition.config_schema:type_name -> squadaegis.pluginrpc.v1.ConfigSchema
-	14, // 3: squadaegis.pluginrpc.v1.PluginEvent.timestamp:type_name -> google.protobuf.Timestamp
-	9,  // 4: squadaegis.pluginrpc.v1.CommandList.commands:type_name -> squadaegis.pluginrpc.v1.PluginCommand
-	2,  // 5: squadaegis.pluginrpc.v1.PluginCommand.parameters:type_name -> squadaegis.pluginrpc.v1.ConfigSchema
-	11, // 6: squadaegis.pluginrpc.v1.CommandExecutionStatus.result:type_name -> squadaegis.pluginrpc.v1.CommandResult
-	14, // 7: squadaegis.pluginrpc.v1.CommandExecutionStatus.started_at:type_name -> google.protobuf.Timestamp
-	14, // 8: squadaegis.pluginrpc.v1.CommandExecutionStatus.completed_at:type_name -> google.protobuf.Timestamp
-	0,  // 9: squadaegis.pluginrpc.v1.Plugin.GetDefinition:input_type -> squadaegis.pluginrpc.v1.Empty
-	4,  // 10: squadaegis.pluginrpc.v1.Plugin.Initialize:input_type -> squadaegis.pluginrpc.v1.InitializeRequest
-	0,  // 11: squadaegis.pluginrpc.v1.Plugin.Start:input_type -> squadaegis.pluginrpc.v1.Empty
-	0,  // 12: squadaegis.pluginrpc.v1.Plugin.Stop:input_type -> squadaegis.pluginrpc.v1.Empty
-	5,  // 13: squadaegis.pluginrpc.v1.Plugin.HandleEvent:input_type -> squadaegis.pluginrpc.v1.PluginEvent
-	0,  // 14: squadaegis.pluginrpc.v1.Plugin.GetStatus:input_type -> squadaegis.pluginrpc.v1.Empty
-	0,  // 15: squadaegis.pluginrpc.v1.Plugin.GetConfig:input_type -> squadaegis.pluginrpc.v1.Empty
-	7,  // 16: squadaegis.pluginrpc.v1.Plugin.UpdateConfig:input_type -> squadaegis.pluginrpc.v1.ConfigJSON
-	0,  // 17: squadaegis.pluginrpc.v1.Plugin.GetCommands:input_type -> squadaegis.pluginrpc.v1.Empty
-	10, // 18: squadaegis.pluginrpc.v1.Plugin.ExecuteCommand:input_type -> squadaegis.pluginrpc.v1.ExecuteCommandRequest
-	12, // 19: squadaegis.pluginrpc.v1.Plugin.GetCommandExecutionStatus:input_type -> squadaegis.pluginrpc.v1.ExecutionIDRequest
-	3,  // 20: squadaegis.pluginrpc.v1.Plugin.GetDefinition:output_type -> squadaegis.pluginrpc.v1.PluginDefinition
-	0,  // 21: squadaegis.pluginrpc.v1.Plugin.Initialize:output_type -> squadaegis.pluginrpc.v1.Empty
-	0,  // 22: squadaegis.pluginrpc.v1.Plugin.Start:output_type -> squadaegis.pluginrpc.v1.Empty
-	0,  // 23: squadaegis.pluginrpc.v1.Plugin.Stop:output_type -> squadaegis.pluginrpc.v1.Empty
-	0,  // 24: squadaegis.pluginrpc.v1.Plugin.HandleEvent:output_type -> squadaegis.pluginrpc.v1.Empty
-	6,  // 25: squadaegis.pluginrpc.v1.Plugin.GetStatus:output_type -> squadaegis.pluginrpc.v1.StatusResponse
-	7,  // 26: squadaegis.pluginrpc.v1.Plugin.GetConfig:output_type -> squadaegis.pluginrpc.v1.ConfigJSON
-	0,  // 27: squadaegis.pluginrpc.v1.Plugin.UpdateConfig:output_type -> squadaegis.pluginrpc.v1.Empty
-	8,  // 28: squadaegis.pluginrpc.v1.Plugin.GetCommands:output_type -> squadaegis.pluginrpc.v1.CommandList
-	11, // 29: squadaegis.pluginrpc.v1.Plugin.ExecuteCommand:output_type -> squadaegis.pluginrpc.v1.CommandResult
-	13, // 30: squadaegis.pluginrpc.v1.Plugin.GetCommandExecutionStatus:output_type -> squadaegis.pluginrpc.v1.CommandExecutionStatus
-	20, // [20:31] is the sub-list for method output_type
-	9,  // [9:20] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	4,  // 3: squadaegis.pluginrpc.v1.PluginDefinition.http_routes:type_name -> squadaegis.pluginrpc.v1.HTTPRoute
+	19, // 4: squadaegis.pluginrpc.v1.PluginEvent.timestamp:type_name -> google.protobuf.Timestamp
+	11, // 5: squadaegis.pluginrpc.v1.CommandList.commands:type_name -> squadaegis.pluginrpc.v1.PluginCommand
+	2,  // 6: squadaegis.pluginrpc.v1.PluginCommand.parameters:type_name -> squadaegis.pluginrpc.v1.ConfigSchema
+	13, // 7: squadaegis.pluginrpc.v1.CommandExecutionStatus.result:type_name -> squadaegis.pluginrpc.v1.CommandResult
+	19, // 8: squadaegis.pluginrpc.v1.CommandExecutionStatus.started_at:type_name -> google.protobuf.Timestamp
+	19, // 9: squadaegis.pluginrpc.v1.CommandExecutionStatus.completed_at:type_name -> google.protobuf.Timestamp
+	18, // 10: squadaegis.pluginrpc.v1.HTTPRequest.params:type_name -> squadaegis.pluginrpc.v1.HTTPRequest.ParamsEntry
+	0,  // 11: squadaegis.pluginrpc.v1.Plugin.GetDefinition:input_type -> squadaegis.pluginrpc.v1.Empty
+	5,  // 12: squadaegis.pluginrpc.v1.Plugin.Initialize:input_type -> squadaegis.pluginrpc.v1.InitializeRequest
+	0,  // 13: squadaegis.pluginrpc.v1.Plugin.Start:input_type -> squadaegis.pluginrpc.v1.Empty
+	0,  // 14: squadaegis.pluginrpc.v1.Plugin.Stop:input_type -> squadaegis.pluginrpc.v1.Empty
+	6,  // 15: squadaegis.pluginrpc.v1.Plugin.HandleEvent:input_type -> squadaegis.pluginrpc.v1.PluginEvent
+	0,  // 16: squadaegis.pluginrpc.v1.Plugin.GetStatus:input_type -> squadaegis.pluginrpc.v1.Empty
+	0,  // 17: squadaegis.pluginrpc.v1.Plugin.GetConfig:input_type -> squadaegis.pluginrpc.v1.Empty
+	8,  // 18: squadaegis.pluginrpc.v1.Plugin.UpdateConfig:input_type -> squadaegis.pluginrpc.v1.ConfigJSON
+	0,  // 19: squadaegis.pluginrpc.v1.Plugin.GetCommands:input_type -> squadaegis.pluginrpc.v1.Empty
+	12, // 20: squadaegis.pluginrpc.v1.Plugin.ExecuteCommand:input_type -> squadaegis.pluginrpc.v1.ExecuteCommandRequest
+	14, // 21: squadaegis.pluginrpc.v1.Plugin.GetCommandExecutionStatus:input_type -> squadaegis.pluginrpc.v1.ExecutionIDRequest
+	9,  // 22: squadaegis.pluginrpc.v1.Plugin.MigrateConfig:input_type -> squadaegis.pluginrpc.v1.MigrateConfigRequest
+	16, // 23: squadaegis.pluginrpc.v1.Plugin.HandleHTTP:input_type -> squadaegis.pluginrpc.v1.HTTPRequest
+	3,  // 24: squadaegis.pluginrpc.v1.Plugin.GetDefinition:output_type -> squadaegis.pluginrpc.v1.PluginDefinition
+	0,  // 25: squadaegis.pluginrpc.v1.Plugin.Initialize:output_type -> squadaegis.pluginrpc.v1.Empty
+	0,  // 26: squadaegis.pluginrpc.v1.Plugin.Start:output_type -> squadaegis.pluginrpc.v1.Empty
+	0,  // 27: squadaegis.pluginrpc.v1.Plugin.Stop:output_type -> squadaegis.pluginrpc.v1.Empty
+	0,  // 28: squadaegis.pluginrpc.v1.Plugin.HandleEvent:output_type -> squadaegis.pluginrpc.v1.Empty
+	7,  // 29: squadaegis.pluginrpc.v1.Plugin.GetStatus:output_type -> squadaegis.pluginrpc.v1.StatusResponse
+	8,  // 30: squadaegis.pluginrpc.v1.Plugin.GetConfig:output_type -> squadaegis.pluginrpc.v1.ConfigJSON
+	0,  // 31: squadaegis.pluginrpc.v1.Plugin.UpdateConfig:output_type -> squadaegis.pluginrpc.v1.Empty
+	10, // 32: squadaegis.pluginrpc.v1.Plugin.GetCommands:output_type -> squadaegis.pluginrpc.v1.CommandList
+	13, // 33: squadaegis.pluginrpc.v1.Plugin.ExecuteCommand:output_type -> squadaegis.pluginrpc.v1.CommandResult
+	15, // 34: squadaegis.pluginrpc.v1.Plugin.GetCommandExecutionStatus:output_type -> squadaegis.pluginrpc.v1.CommandExecutionStatus
+	8,  // 35: squadaegis.pluginrpc.v1.Plugin.MigrateConfig:output_type -> squadaegis.pluginrpc.v1.ConfigJSON
+	17, // 36: squadaegis.pluginrpc.v1.Plugin.HandleHTTP:output_type -> squadaegis.pluginrpc.v1.HTTPResponse
+	24, // [24:37] is the sub-list for method output_type
+	11, // [11:24] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_pkg_pluginrpc_proto_plugin_proto_init() }
@@ -1160,7 +1530,7 @@ func file_pkg_pluginrpc_proto_plugin_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_pluginrpc_proto_plugin_proto_rawDesc), len(file_pkg_pluginrpc_proto_plugin_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   14,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
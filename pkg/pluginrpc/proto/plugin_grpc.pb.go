@@ -37,6 +37,8 @@ const (
 	Plugin_GetCommands_FullMethodName               = "/squadaegis.pluginrpc.v1.Plugin/GetCommands"
 	Plugin_ExecuteCommand_FullMethodName            = "/squadaegis.pluginrpc.v1.Plugin/ExecuteCommand"
 	Plugin_GetCommandExecutionStatus_FullMethodName = "/squadaegis.pluginrpc.v1.Plugin/GetCommandExecutionStatus"
+	Plugin_MigrateConfig_FullMethodName             = "/squadaegis.pluginrpc.v1.Plugin/MigrateConfig"
+	Plugin_HandleHTTP_FullMethodName                = "/squadaegis.pluginrpc.v1.Plugin/HandleHTTP"
 )
 
 // PluginClient is the client API for Plugin service.
@@ -57,6 +59,8 @@ type PluginClient interface {
 	GetCommands(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*CommandList, error)
 	ExecuteCommand(ctx context.Context, in *ExecuteCommandRequest, opts ...grpc.CallOption) (*CommandResult, error)
 	GetCommandExecutionStatus(ctx context.Context, in *ExecutionIDRequest, opts ...grpc.CallOption) (*CommandExecutionStatus, error)
+	MigrateConfig(ctx context.Context, in *MigrateConfigRequest, opts ...grpc.CallOption) (*ConfigJSON, error)
+	HandleHTTP(ctx context.Context, in *HTTPRequest, opts ...grpc.CallOption) (*HTTPResponse, error)
 }
 
 type pluginClient struct {
@@ -177,6 +181,26 @@ func (c *pluginClient) GetCommandExecutionStatus(ctx context.Context, in *Execut
 	return out, nil
 }
 
+func (c *pluginClient) MigrateConfig(ctx context.Context, in *MigrateConfigRequest, opts ...grpc.CallOption) (*ConfigJSON, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfigJSON)
+	err := c.cc.Invoke(ctx, Plugin_MigrateConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginClient) HandleHTTP(ctx context.Context, in *HTTPRequest, opts ...grpc.CallOption) (*HTTPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HTTPResponse)
+	err := c.cc.Invoke(ctx, Plugin_HandleHTTP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PluginServer is the server API for Plugin service.
 // All implementations must embed UnimplementedPluginServer
 // for forward compatibility.
@@ -195,6 +219,8 @@ type PluginServer interface {
 	GetCommands(context.Context, *Empty) (*CommandList, error)
 	ExecuteCommand(context.Context, *ExecuteCommandRequest) (*CommandResult, error)
 	GetCommandExecutionStatus(context.Context, *ExecutionIDRequest) (*CommandExecutionStatus, error)
+	MigrateConfig(context.Context, *MigrateConfigRequest) (*ConfigJSON, error)
+	HandleHTTP(context.Context, *HTTPRequest) (*HTTPResponse, error)
 	mustEmbedUnimplementedPluginServer()
 }
 
@@ -238,6 +264,12 @@ func (UnimplementedPluginServer) ExecuteCommand(context.Context, *ExecuteCommand
 func (UnimplementedPluginServer) GetCommandExecutionStatus(context.Context, *ExecutionIDRequest) (*CommandExecutionStatus, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetCommandExecutionStatus not implemented")
 }
+func (UnimplementedPluginServer) MigrateConfig(context.Context, *MigrateConfigRequest) (*ConfigJSON, error) {
+	return nil, status.Error(codes.Unimplemented, "method MigrateConfig not implemented")
+}
+func (UnimplementedPluginServer) HandleHTTP(context.Context, *HTTPRequest) (*HTTPResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method HandleHTTP not implemented")
+}
 func (UnimplementedPluginServer) mustEmbedUnimplementedPluginServer() {}
 func (UnimplementedPluginServer) testEmbeddedByValue()                {}
 
@@ -457,6 +489,42 @@ func _Plugin_GetCommandExecutionStatus_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Plugin_MigrateConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MigrateConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServer).MigrateConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Plugin_MigrateConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServer).MigrateConfig(ctx, req.(*MigrateConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plugin_HandleHTTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HTTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServer).HandleHTTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Plugin_HandleHTTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServer).HandleHTTP(ctx, req.(*HTTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Plugin_ServiceDesc is the grpc.ServiceDesc for Plugin service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -508,6 +576,14 @@ var Plugin_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetCommandExecutionStatus",
 			Handler:    _Plugin_GetCommandExecutionStatus_Handler,
 		},
+		{
+			MethodName: "MigrateConfig",
+			Handler:    _Plugin_MigrateConfig_Handler,
+		},
+		{
+			MethodName: "HandleHTTP",
+			Handler:    _Plugin_HandleHTTP_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/pluginrpc/proto/plugin.proto",
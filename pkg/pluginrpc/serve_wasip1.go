@@ -0,0 +1,129 @@
+//go:build wasip1
+
+package pluginrpc
+
+import (
+	"context"
+	"io"
+	"sync"
+	"unsafe"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pluginrpcpb "go.codycody31.dev/squad-aegis/pkg/pluginrpc/proto"
+)
+
+// Serve registers impl as the plugin this WASM module serves. WASM plugins
+// are built as reactors (-buildmode=c-shared), which never run main, so
+// call Serve from an init function:
+//
+//	func init() {
+//	    pluginrpc.Serve(&MyPlugin{})
+//	}
+//
+// Goroutines only run while the host is calling into the module, so WASM
+// plugins cannot be long running.
+func Serve(impl Plugin) {
+	wasmGuest.mu.Lock()
+	defer wasmGuest.mu.Unlock()
+	wasmGuest.server = &pluginGRPCServer{impl: impl, dialHost: dialWasmHost}
+}
+
+var wasmGuest struct {
+	mu     sync.Mutex
+	server *pluginGRPCServer
+	// buffers pins the memory handed to the host until it calls aegis_free.
+	buffers map[uint32][]byte
+}
+
+// hostCallMu keeps host_call and its host_reply together when several
+// goroutines call host APIs.
+var hostCallMu sync.Mutex
+
+//go:wasmimport aegis host_call
+func wasmHostCall(method unsafe.Pointer, methodLen uint32, serverID unsafe.Pointer, serverIDLen uint32, payload unsafe.Pointer, payloadLen uint32) uint32
+
+//go:wasmimport aegis host_reply
+func wasmHostReply(buffer unsafe.Pointer)
+
+func dialWasmHost(uint32) (grpc.ClientConnInterface, io.Closer, error) {
+	return NewWasmConn(invokeWasmHost), nil, nil
+}
+
+func invokeWasmHost(_ context.Context, method, serverID string, payload []byte) ([]byte, error) {
+	hostCallMu.Lock()
+	defer hostCallMu.Unlock()
+
+	size := wasmHostCall(
+		unsafe.Pointer(unsafe.StringData(method)), uint32(len(method)),
+		unsafe.Pointer(unsafe.StringData(serverID)), uint32(len(serverID)),
+		unsafe.Pointer(unsafe.SliceData(payload)), uint32(len(payload)),
+	)
+	reply := make([]byte, size)
+	wasmHostReply(unsafe.Pointer(unsafe.SliceData(reply)))
+	return reply, nil
+}
+
+//go:wasmexport aegis_alloc
+func wasmAlloc(size uint32) uint32 {
+	if size == 0 {
+		return 0
+	}
+	buffer := make([]byte, size)
+	return pinWasmBuffer(buffer)
+}
+
+//go:wasmexport aegis_free
+func wasmFree(ptr uint32) {
+	wasmGuest.mu.Lock()
+	defer wasmGuest.mu.Unlock()
+	delete(wasmGuest.buffers, ptr)
+}
+
+//go:wasmexport aegis_call
+func wasmCall(methodPtr, methodLen, payloadPtr, payloadLen uint32) uint64 {
+	method := string(wasmBuffer(methodPtr, methodLen))
+	payload := wasmBuffer(payloadPtr, payloadLen)
+
+	wasmGuest.mu.Lock()
+	server := wasmGuest.server
+	wasmGuest.mu.Unlock()
+
+	var reply []byte
+	if server == nil {
+		reply = EncodeWasmReply(nil, status.Error(codes.FailedPrecondition, "module did not call pluginrpc.Serve"))
+	} else {
+		reply = DispatchWasmCall(context.Background(), &pluginrpcpb.Plugin_ServiceDesc, server, method, "", payload)
+	}
+	return uint64(pinWasmBuffer(reply))<<32 | uint64(len(reply))
+}
+
+func pinWasmBuffer(buffer []byte) uint32 {
+	if len(buffer) == 0 {
+		return 0
+	}
+	ptr := uint32(uintptr(unsafe.Pointer(unsafe.SliceData(buffer))))
+	wasmGuest.mu.Lock()
+	defer wasmGuest.mu.Unlock()
+	if wasmGuest.buffers == nil {
+		wasmGuest.buffers = make(map[uint32][]byte)
+	}
+	wasmGuest.buffers[ptr] = buffer
+	return ptr
+}
+
+// wasmBuffer returns the first size bytes of a buffer from aegis_alloc.
+func wasmBuffer(ptr, size uint32) []byte {
+	if size == 0 {
+		return nil
+	}
+	wasmGuest.mu.Lock()
+	defer wasmGuest.mu.Unlock()
+	buffer := wasmGuest.buffers[ptr]
+	if uint32(len(buffer)) < size {
+		return nil
+	}
+	return buffer[:size]
+}
@@ -0,0 +1,203 @@
+package pluginrpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pluginrpcpb "go.codycody31.dev/squad-aegis/pkg/pluginrpc/proto"
+)
+
+// WASM plugins speak the same Plugin and HostAPI gRPC services as subprocess
+// plugins, but each unary call crosses the module boundary as a function
+// call carrying the protobuf-encoded request instead of a network round
+// trip. The ABI is:
+//
+// The module exports, for the host to call:
+//
+//	aegis_alloc(size u32) u32
+//	aegis_free(ptr u32)
+//	aegis_call(method_ptr, method_len, payload_ptr, payload_len u32) u64
+//
+// aegis_call runs one Plugin service method and returns the reply envelope
+// as (ptr << 32 | len). The host frees request and reply buffers with
+// aegis_free once it has copied them.
+//
+// The host provides, in the "aegis" module:
+//
+//	host_call(method_ptr, method_len, server_id_ptr, server_id_len, payload_ptr, payload_len u32) u32
+//	host_reply(ptr u32)
+//
+// host_call runs one HostAPI method and returns the length of the reply
+// envelope, which host_reply then copies into a guest buffer of that size.
+// server_id carries the x-aegis-server-id metadata of server-qualified calls.
+//
+// A reply envelope is one status byte followed by the reply message, or by
+// a google.rpc.Status when the call failed.
+const (
+	// WasmTargetOS and WasmTargetArch mark a manifest target as a WASM
+	// module. They match Go's GOOS and GOARCH for WASI builds.
+	WasmTargetOS   = "wasip1"
+	WasmTargetArch = "wasm"
+
+	WasmHostModule    = "aegis"
+	WasmHostCallFunc  = "host_call"
+	WasmHostReplyFunc = "host_reply"
+	WasmAllocFunc     = "aegis_alloc"
+	WasmFreeFunc      = "aegis_free"
+	WasmCallFunc      = "aegis_call"
+)
+
+const (
+	wasmReplyOK    byte = 0
+	wasmReplyError byte = 1
+)
+
+// EncodeWasmReply encodes the result of a call as a reply envelope.
+func EncodeWasmReply(reply proto.Message, err error) []byte {
+	if err == nil {
+		payload, marshalErr := proto.Marshal(reply)
+		if marshalErr == nil {
+			return append([]byte{wasmReplyOK}, payload...)
+		}
+		err = status.Errorf(codes.Internal, "failed to encode reply: %v", marshalErr)
+	}
+	payload, marshalErr := proto.Marshal(status.Convert(err).Proto())
+	if marshalErr != nil {
+		payload, _ = proto.Marshal(&spb.Status{Code: int32(codes.Internal), Message: err.Error()})
+	}
+	return append([]byte{wasmReplyError}, payload...)
+}
+
+// DecodeWasmReply decodes a reply envelope into reply, or returns the error
+// it carries.
+func DecodeWasmReply(data []byte, reply proto.Message) error {
+	if len(data) == 0 {
+		return status.Error(codes.Internal, "empty wasm reply")
+	}
+	switch data[0] {
+	case wasmReplyOK:
+		if err := proto.Unmarshal(data[1:], reply); err != nil {
+			return status.Errorf(codes.Internal, "failed to decode wasm reply: %v", err)
+		}
+		return nil
+	case wasmReplyError:
+		var st spb.Status
+		if err := proto.Unmarshal(data[1:], &st); err != nil {
+			return status.Errorf(codes.Internal, "failed to decode wasm error: %v", err)
+		}
+		return status.ErrorProto(&st)
+	default:
+		return status.Errorf(codes.Internal, "unknown wasm reply status %d", data[0])
+	}
+}
+
+// WasmInvoker sends one encoded request across the module boundary and
+// returns the encoded reply envelope.
+type WasmInvoker func(ctx context.Context, method, serverID string, payload []byte) ([]byte, error)
+
+// wasmConn adapts a WasmInvoker onto grpc.ClientConnInterface so generated
+// gRPC clients work unchanged on both sides of the boundary.
+type wasmConn struct {
+	invoke WasmInvoker
+}
+
+// NewWasmConn returns a client connection that sends unary calls through
+// invoke. Streaming calls are not supported.
+func NewWasmConn(invoke WasmInvoker) grpc.ClientConnInterface {
+	return &wasmConn{invoke: invoke}
+}
+
+func (c *wasmConn) Invoke(ctx context.Context, method string, args, reply interface{}, _ ...grpc.CallOption) error {
+	request, ok := args.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "wasm call %s has a non-protobuf request %T", method, args)
+	}
+	response, ok := reply.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "wasm call %s has a non-protobuf reply %T", method, reply)
+	}
+	payload, err := proto.Marshal(request)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to encode wasm request: %v", err)
+	}
+
+	serverID := ""
+	if md, ok := metadata.FromOutgoingContext(ctx); ok {
+		if values := md.Get(ServerIDMetadataKey); len(values) > 0 {
+			serverID = values[0]
+		}
+	}
+
+	data, err := c.invoke(ctx, method, serverID, payload)
+	if err != nil {
+		return err
+	}
+	return DecodeWasmReply(data, response)
+}
+
+func (c *wasmConn) NewStream(context.Context, *grpc.StreamDesc, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, status.Error(codes.Unimplemented, "streaming calls are not supported by wasm plugins")
+}
+
+// DispatchWasmCall runs one unary method of service against srv and returns
+// the reply envelope. method is the full gRPC method name, such as
+// "/squadaegis.pluginrpc.v1.HostAPI/LogInfo". A non-empty serverID is
+// exposed to srv as incoming x-aegis-server-id metadata. Panics in srv
+// become errors.
+func DispatchWasmCall(ctx context.Context, service *grpc.ServiceDesc, srv interface{}, method, serverID string, payload []byte) (reply []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			reply = EncodeWasmReply(nil, status.Errorf(codes.Internal, "%s panicked: %v", method, r))
+		}
+	}()
+
+	name, ok := strings.CutPrefix(method, "/"+service.ServiceName+"/")
+	if !ok {
+		return EncodeWasmReply(nil, status.Errorf(codes.Unimplemented, "unknown service for method %s", method))
+	}
+	var handler grpc.MethodHandler
+	for _, desc := range service.Methods {
+		if desc.MethodName == name {
+			handler = desc.Handler
+			break
+		}
+	}
+	if handler == nil {
+		return EncodeWasmReply(nil, status.Errorf(codes.Unimplemented, "unknown method %s", method))
+	}
+
+	if serverID != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(ServerIDMetadataKey, serverID))
+	}
+	decode := func(v interface{}) error {
+		request, ok := v.(proto.Message)
+		if !ok {
+			return fmt.Errorf("non-protobuf request %T", v)
+		}
+		return proto.Unmarshal(payload, request)
+	}
+	result, err := handler(srv, ctx, decode, nil)
+	if err != nil {
+		return EncodeWasmReply(nil, err)
+	}
+	message, ok := result.(proto.Message)
+	if !ok {
+		return EncodeWasmReply(nil, status.Errorf(codes.Internal, "%s returned a non-protobuf reply %T", method, result))
+	}
+	return EncodeWasmReply(message, nil)
+}
+
+// NewWasmPluginClient returns the host-side stub for a WASM plugin whose
+// Plugin service is reached through conn. WASM plugins have no broker: their
+// host APIs are served through the module's imports.
+func NewWasmPluginClient(conn grpc.ClientConnInterface) *PluginGRPCClient {
+	return &PluginGRPCClient{client: pluginrpcpb.NewPluginClient(conn)}
+}
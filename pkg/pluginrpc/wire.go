@@ -125,6 +125,11 @@ type ConfigField struct {
 // re-marshal it via JSON.
 type ConfigSchema struct {
 	Fields []ConfigField `json:"fields"`
+
+	// Version identifies the shape of the config. Bump it whenever a
+	// change would make previously stored configs invalid, and implement
+	// ConfigMigrator to rewrite them. Zero means unversioned.
+	Version int `json:"version,omitempty"`
 }
 
 // PluginDefinition is what the subprocess returns from GetDefinition(). It
@@ -140,6 +145,11 @@ type PluginDefinition struct {
 	// plugin can be enabled on a single server.
 	AllowMultipleInstances bool `json:"allow_multiple_instances,omitempty"`
 
+	// AllowGlobalInstances lets operators create one instance that spans
+	// several servers. Such an instance receives events from every server
+	// in its scope and reaches them through HostAPIs.ForServer.
+	AllowGlobalInstances bool `json:"allow_global_instances,omitempty"`
+
 	// LongRunning is true for plugins that need a dedicated Start() call.
 	// Event-driven plugins can leave it false.
 	LongRunning bool `json:"long_running,omitempty"`
@@ -156,6 +166,94 @@ type PluginDefinition struct {
 	// Events is the list of event types the plugin wants to receive via
 	// HandleEvent RPC calls.
 	Events []string `json:"events,omitempty"`
+
+	// MigratesConfig reports whether the plugin implements ConfigMigrator.
+	// The SDK fills it in; plugin authors do not need to set it.
+	MigratesConfig bool `json:"migrates_config,omitempty"`
+
+	// HTTPRoutes are the endpoints the plugin serves through HTTPHandler.
+	// Panels are dashboard panels the web UI renders from those routes.
+	HTTPRoutes []HTTPRoute `json:"http_routes,omitempty"`
+	Panels     []Panel     `json:"panels,omitempty"`
+}
+
+// Access levels for HTTP routes.
+const (
+	HTTPAccessView   = "view"
+	HTTPAccessManage = "manage"
+)
+
+// HTTPRoute mirrors plugin_manager.PluginHTTPRoute. Path segments starting
+// with ":" are parameters.
+type HTTPRoute struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Access      string `json:"access"`
+	Description string `json:"description,omitempty"`
+}
+
+// Panel mirrors plugin_manager.PluginPanel. Type is table, chart, stats or
+// form, and Route names the declared route the panel reads from or, for
+// forms, posts to.
+type Panel struct {
+	ID             string        `json:"id"`
+	Title          string        `json:"title"`
+	Description    string        `json:"description,omitempty"`
+	Type           string        `json:"type"`
+	Route          string        `json:"route"`
+	Columns        []PanelColumn `json:"columns,omitempty"`
+	Chart          *PanelChart   `json:"chart,omitempty"`
+	Form           *ConfigSchema `json:"form,omitempty"`
+	SubmitLabel    string        `json:"submit_label,omitempty"`
+	RefreshSeconds int           `json:"refresh_seconds,omitempty"`
+}
+
+// PanelColumn is one column of a table panel.
+type PanelColumn struct {
+	Key    string `json:"key"`
+	Label  string `json:"label"`
+	Format string `json:"format,omitempty"`
+}
+
+// PanelChart configures a chart panel.
+type PanelChart struct {
+	Kind   string             `json:"kind"`
+	XKey   string             `json:"x_key"`
+	Series []PanelChartSeries `json:"series"`
+}
+
+// PanelChartSeries is one plotted value of a chart panel.
+type PanelChartSeries struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+}
+
+// HTTPRequest mirrors plugin_manager.PluginHTTPRequest on the wire.
+type HTTPRequest struct {
+	Method string              `json:"method"`
+	Path   string              `json:"path"`
+	Route  string              `json:"route"`
+	Params map[string]string   `json:"params,omitempty"`
+	Query  map[string][]string `json:"query,omitempty"`
+	Body   []byte              `json:"body,omitempty"`
+	UserID string              `json:"user_id,omitempty"`
+}
+
+// HTTPResponse mirrors plugin_manager.PluginHTTPResponse on the wire. The
+// host only serves JSON, plain text and CSV bodies.
+type HTTPResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// JSONResponse encodes v as an HTTPResponse.
+func JSONResponse(status int, v interface{}) (*HTTPResponse, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &HTTPResponse{Status: status, ContentType: "application/json", Body: body}, nil
 }
 
 // PluginEvent is the wire shape of plugin_manager.PluginEvent. Data is a raw
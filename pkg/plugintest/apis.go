@@ -0,0 +1,700 @@
+package plugintest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go.codycody31.dev/squad-aegis/internal/plugin_manager"
+	"go.codycody31.dev/squad-aegis/internal/shared/utils"
+)
+
+// Storage is the plugin's key/value store.
+type Storage struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newStorage() *Storage {
+	return &Storage{data: make(map[string]string)}
+}
+
+// Set stores a value, as if saved by an earlier run of the plugin.
+func (s *Storage) Set(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = value
+}
+
+// Get returns a stored value.
+func (s *Storage) Get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.data[key]
+	return value, ok
+}
+
+func (s *Storage) GetPluginData(key string) (string, error) {
+	value, ok := s.Get(key)
+	if !ok {
+		return "", fmt.Errorf("key not found")
+	}
+	return value, nil
+}
+
+func (s *Storage) SetPluginData(key string, value string) error {
+	s.Set(key, value)
+	return nil
+}
+
+func (s *Storage) DeletePluginData(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	return nil
+}
+
+// Rules holds the server's rules and their escalation actions.
+type Rules struct {
+	mu      sync.Mutex
+	rules   []*plugin_manager.RuleInfo
+	actions map[string][]*plugin_manager.RuleActionInfo
+}
+
+func newRules() *Rules {
+	return &Rules{actions: make(map[string][]*plugin_manager.RuleActionInfo)}
+}
+
+// Add adds a rule and its escalation actions.
+func (r *Rules) Add(rule plugin_manager.RuleInfo, actions ...plugin_manager.RuleActionInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rule.ID == "" {
+		rule.ID = uuid.NewString()
+	}
+	r.rules = append(r.rules, &rule)
+	for i := range actions {
+		action := actions[i]
+		action.RuleID = rule.ID
+		r.actions[rule.ID] = append(r.actions[rule.ID], &action)
+	}
+}
+
+func (r *Rules) ListServerRules(parentRuleID *string) ([]*plugin_manager.RuleInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	parentID := deref(parentRuleID)
+	rules := make([]*plugin_manager.RuleInfo, 0)
+	for _, rule := range r.rules {
+		if rule.ParentID == parentID {
+			copied := *rule
+			rules = append(rules, &copied)
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].DisplayOrder < rules[j].DisplayOrder })
+	return rules, nil
+}
+
+func (r *Rules) ListServerRuleActions(ruleID string) ([]*plugin_manager.RuleActionInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	actions := make([]*plugin_manager.RuleActionInfo, 0, len(r.actions[ruleID]))
+	for _, action := range r.actions[ruleID] {
+		copied := *action
+		actions = append(actions, &copied)
+	}
+	return actions, nil
+}
+
+// AdminCallKind identifies the AdminAPI call an AdminCall records.
+type AdminCallKind string
+
+const (
+	AdminCallAdd        AdminCallKind = "add"
+	AdminCallRemove     AdminCallKind = "remove"
+	AdminCallRemoveRole AdminCallKind = "remove_role"
+)
+
+// AdminCall is one change a plugin made to temporary admins.
+type AdminCall struct {
+	Kind      AdminCallKind
+	PlayerID  string
+	RoleName  string
+	Notes     string
+	ExpiresAt *time.Time
+}
+
+// Admins manages the temporary admins a plugin grants. Admin status
+// includes temporary admins and the server's admin list.
+type Admins struct {
+	server *Server
+
+	mu        sync.Mutex
+	temporary []*plugin_manager.TemporaryAdminInfo
+	calls     []AdminCall
+}
+
+func newAdmins(server *Server) *Admins {
+	return &Admins{server: server}
+}
+
+// Grant adds a temporary admin without recording a call, as if granted by
+// an earlier run of the plugin.
+func (a *Admins) Grant(admin plugin_manager.TemporaryAdminInfo) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if admin.ID == "" {
+		admin.ID = uuid.NewString()
+	}
+	if admin.CreatedAt.IsZero() {
+		admin.CreatedAt = time.Now()
+	}
+	a.temporary = append(a.temporary, &admin)
+}
+
+// Temporary returns the current temporary admins.
+func (a *Admins) Temporary() []plugin_manager.TemporaryAdminInfo {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	admins := make([]plugin_manager.TemporaryAdminInfo, 0, len(a.temporary))
+	for _, admin := range a.temporary {
+		admins = append(admins, *admin)
+	}
+	return admins
+}
+
+// Calls returns the changes the plugin made, in order.
+func (a *Admins) Calls() []AdminCall {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]AdminCall(nil), a.calls...)
+}
+
+// CallsOf returns the recorded calls of one kind.
+func (a *Admins) CallsOf(kind AdminCallKind) []AdminCall {
+	var matched []AdminCall
+	for _, call := range a.Calls() {
+		if call.Kind == kind {
+			matched = append(matched, call)
+		}
+	}
+	return matched
+}
+
+func (a *Admins) AddTemporaryAdmin(playerID string, roleName string, notes string, expiresAt *time.Time) error {
+	steamID, eosID, err := splitPlayerID(playerID)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.calls = append(a.calls, AdminCall{Kind: AdminCallAdd, PlayerID: playerID, RoleName: roleName, Notes: notes, ExpiresAt: expiresAt})
+	for _, admin := range a.temporary {
+		if admin.RoleName == roleName && admin.MatchesPlayerID(playerID) {
+			admin.Notes = notes
+			admin.ExpiresAt = expiresAt
+			return nil
+		}
+	}
+	a.temporary = append(a.temporary, &plugin_manager.TemporaryAdminInfo{
+		ID:        uuid.NewString(),
+		SteamID:   steamID,
+		EOSID:     eosID,
+		RoleName:  roleName,
+		Notes:     notes,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	})
+	return nil
+}
+
+func (a *Admins) RemoveTemporaryAdmin(playerID string, notes string) error {
+	return a.remove(AdminCall{Kind: AdminCallRemove, PlayerID: playerID, Notes: notes})
+}
+
+func (a *Admins) RemoveTemporaryAdminRole(playerID string, roleName string, notes string) error {
+	return a.remove(AdminCall{Kind: AdminCallRemoveRole, PlayerID: playerID, RoleName: roleName, Notes: notes})
+}
+
+func (a *Admins) remove(call AdminCall) error {
+	if _, _, err := splitPlayerID(call.PlayerID); err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.calls = append(a.calls, call)
+	kept := a.temporary[:0]
+	for _, admin := range a.temporary {
+		if admin.MatchesPlayerID(call.PlayerID) && (call.RoleName == "" || admin.RoleName == call.RoleName) {
+			continue
+		}
+		kept = append(kept, admin)
+	}
+	removed := len(a.temporary) - len(kept)
+	a.temporary = kept
+	if removed == 0 {
+		return fmt.Errorf("no plugin-managed admin records found for player ID %s", utils.NormalizePlayerID(call.PlayerID))
+	}
+	return nil
+}
+
+func (a *Admins) GetPlayerAdminStatus(playerID string) (*plugin_manager.PlayerAdminStatus, error) {
+	steamID, eosID, err := splitPlayerID(playerID)
+	if err != nil {
+		return nil, err
+	}
+	status := &plugin_manager.PlayerAdminStatus{SteamID: steamID, EOSID: eosID, Roles: []*plugin_manager.PlayerAdminRole{}}
+
+	a.mu.Lock()
+	for _, admin := range a.temporary {
+		if !admin.MatchesPlayerID(playerID) {
+			continue
+		}
+		role := &plugin_manager.PlayerAdminRole{ID: admin.ID, RoleName: admin.RoleName, Notes: admin.Notes, ExpiresAt: admin.ExpiresAt}
+		if admin.ExpiresAt != nil {
+			role.IsExpired = admin.ExpiresAt.Before(time.Now())
+			status.HasExpiring = status.HasExpiring || !role.IsExpired
+		}
+		status.Roles = append(status.Roles, role)
+	}
+	a.mu.Unlock()
+
+	admins, _ := a.server.GetAdmins()
+	for _, admin := range admins {
+		if admin.MatchesPlayerID(playerID) {
+			status.Roles = append(status.Roles, admin.Roles...)
+		}
+	}
+	status.IsAdmin = len(status.Roles) > 0
+	return status, nil
+}
+
+func (a *Admins) ListTemporaryAdmins() ([]*plugin_manager.TemporaryAdminInfo, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	admins := make([]*plugin_manager.TemporaryAdminInfo, 0, len(a.temporary))
+	for _, admin := range a.temporary {
+		copied := *admin
+		copied.IsExpired = copied.ExpiresAt != nil && copied.ExpiresAt.Before(time.Now())
+		admins = append(admins, &copied)
+	}
+	return admins, nil
+}
+
+// splitPlayerID sorts a player ID into a Steam or EOS ID, as the host does.
+func splitPlayerID(playerID string) (steamID, eosID string, err error) {
+	normalized := utils.NormalizePlayerID(playerID)
+	switch {
+	case normalized == "":
+		return "", "", fmt.Errorf("invalid player ID: player ID is required")
+	case utils.IsSteamID(normalized):
+		return normalized, "", nil
+	case utils.IsEOSID(normalized):
+		return "", normalized, nil
+	default:
+		return "", "", fmt.Errorf("invalid player ID: %s is not a Steam or EOS ID", playerID)
+	}
+}
+
+// PublishedEvent is an event a plugin published through EventAPI.
+type PublishedEvent struct {
+	Type string
+	Data map[string]interface{}
+	Raw  string
+}
+
+// Events records the events a plugin publishes and holds its runtime
+// subscriptions. Its context is the one passed to Start, cancelled when
+// the instance stops.
+type Events struct {
+	ctx        context.Context
+	cancelFunc context.CancelFunc
+
+	mu            sync.Mutex
+	published     []PublishedEvent
+	subscriptions map[string]bool
+	handlers      []eventHandler
+}
+
+type eventHandler struct {
+	types   map[string]bool
+	handler func(*plugin_manager.PluginEvent)
+}
+
+func newEvents() *Events {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Events{ctx: ctx, cancelFunc: cancel, subscriptions: make(map[string]bool)}
+}
+
+func (e *Events) context() context.Context {
+	return e.ctx
+}
+
+func (e *Events) cancel() {
+	e.cancelFunc()
+}
+
+// Published returns the events the plugin published, in order.
+func (e *Events) Published() []PublishedEvent {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]PublishedEvent(nil), e.published...)
+}
+
+// Subscriptions returns the plugin's runtime subscriptions, sorted.
+func (e *Events) Subscriptions() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.subscriptionListLocked()
+}
+
+// Subscribed reports whether the plugin subscribed to eventType at runtime.
+func (e *Events) Subscribed(eventType string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.subscriptions[eventType]
+}
+
+// deliver passes an event to the SubscribeToEvents handlers that want it.
+func (e *Events) deliver(event *plugin_manager.PluginEvent) {
+	e.mu.Lock()
+	handlers := append([]eventHandler(nil), e.handlers...)
+	e.mu.Unlock()
+	for _, h := range handlers {
+		if h.types[event.Type] {
+			h.handler(event)
+		}
+	}
+}
+
+func (e *Events) subscriptionListLocked() []string {
+	types := make([]string, 0, len(e.subscriptions))
+	for eventType := range e.subscriptions {
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func (e *Events) PublishEvent(eventType string, data map[string]interface{}, raw string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.published = append(e.published, PublishedEvent{Type: eventType, Data: data, Raw: raw})
+	return nil
+}
+
+func (e *Events) SubscribeToEvents(eventTypes []string, handler func(*plugin_manager.PluginEvent)) error {
+	if handler == nil {
+		return errors.New("handler is required")
+	}
+	types := make(map[string]bool, len(eventTypes))
+	for _, eventType := range eventTypes {
+		types[eventType] = true
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.handlers = append(e.handlers, eventHandler{types: types, handler: handler})
+	return nil
+}
+
+func (e *Events) AddEventSubscriptions(eventTypes []string) ([]string, error) {
+	if len(eventTypes) == 0 {
+		return nil, errors.New("at least one event type is required")
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, eventType := range eventTypes {
+		e.subscriptions[eventType] = true
+	}
+	return e.subscriptionListLocked(), nil
+}
+
+func (e *Events) RemoveEventSubscriptions(eventTypes []string) ([]string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, eventType := range eventTypes {
+		delete(e.subscriptions, eventType)
+	}
+	return e.subscriptionListLocked(), nil
+}
+
+// History holds the recorded chat messages and deaths HistoryAPI serves.
+// Queries apply the player, teamkill and limit filters and return newest
+// first; the time window is not enforced.
+type History struct {
+	mu         sync.Mutex
+	chat       []*plugin_manager.HistoryChatMessage
+	deaths     []*plugin_manager.HistoryPlayerDeath
+	roundStart *time.Time
+}
+
+func newHistory() *History {
+	return &History{}
+}
+
+// AddChatMessage records a chat message.
+func (h *History) AddChatMessage(message plugin_manager.HistoryChatMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.chat = append(h.chat, &message)
+}
+
+// AddPlayerDeath records a player death.
+func (h *History) AddPlayerDeath(death plugin_manager.HistoryPlayerDeath) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.deaths = append(h.deaths, &death)
+}
+
+// SetRoundStart sets when the current round started.
+func (h *History) SetRoundStart(start time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.roundStart = &start
+}
+
+func (h *History) GetChatMessages(query plugin_manager.HistoryQuery) ([]*plugin_manager.HistoryChatMessage, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	messages := make([]*plugin_manager.HistoryChatMessage, 0)
+	for i := len(h.chat) - 1; i >= 0; i-- {
+		message := h.chat[i]
+		if query.PlayerID != "" && !utils.MatchPlayerID(query.PlayerID, message.SteamID, message.EOSID) {
+			continue
+		}
+		copied := *message
+		messages = append(messages, &copied)
+	}
+	sort.SliceStable(messages, func(i, j int) bool { return messages[i].SentAt.After(messages[j].SentAt) })
+	return limitHistory(messages, query.Limit), nil
+}
+
+func (h *History) GetPlayerDeaths(query plugin_manager.HistoryQuery) ([]*plugin_manager.HistoryPlayerDeath, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	deaths := make([]*plugin_manager.HistoryPlayerDeath, 0)
+	for i := len(h.deaths) - 1; i >= 0; i-- {
+		death := h.deaths[i]
+		if query.TeamkillsOnly && !death.Teamkill {
+			continue
+		}
+		if query.PlayerID != "" &&
+			!utils.MatchPlayerID(query.PlayerID, death.AttackerSteamID, death.AttackerEOSID) &&
+			!utils.MatchPlayerID(query.PlayerID, death.VictimSteamID, death.VictimEOSID) {
+			continue
+		}
+		copied := *death
+		deaths = append(deaths, &copied)
+	}
+	sort.SliceStable(deaths, func(i, j int) bool { return deaths[i].EventTime.After(deaths[j].EventTime) })
+	return limitHistory(deaths, query.Limit), nil
+}
+
+func (h *History) GetCurrentRoundStart() (*time.Time, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.roundStart == nil {
+		return nil, nil
+	}
+	start := *h.roundStart
+	return &start, nil
+}
+
+func limitHistory[T any](items []T, limit int) []T {
+	if limit <= 0 {
+		limit = 100
+	}
+	if len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
+
+// DiscordMessage is a message a plugin sent to Discord. Exactly one of
+// Content and Embed is set.
+type DiscordMessage struct {
+	ID        string
+	ChannelID string
+	Content   string
+	Embed     *plugin_manager.DiscordEmbed
+}
+
+// Discord collects the messages a plugin sends.
+type Discord struct {
+	mu       sync.Mutex
+	messages []DiscordMessage
+}
+
+func newDiscord() *Discord {
+	return &Discord{}
+}
+
+// Messages returns the messages sent, in order.
+func (d *Discord) Messages() []DiscordMessage {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]DiscordMessage(nil), d.messages...)
+}
+
+// MessagesTo returns the messages sent to one channel.
+func (d *Discord) MessagesTo(channelID string) []DiscordMessage {
+	var matched []DiscordMessage
+	for _, message := range d.Messages() {
+		if message.ChannelID == channelID {
+			matched = append(matched, message)
+		}
+	}
+	return matched
+}
+
+func (d *Discord) SendMessage(channelID, content string) (string, error) {
+	return d.send(DiscordMessage{ChannelID: channelID, Content: content})
+}
+
+func (d *Discord) SendEmbed(channelID string, embed *plugin_manager.DiscordEmbed) (string, error) {
+	if embed == nil {
+		return "", errors.New("embed is required")
+	}
+	return d.send(DiscordMessage{ChannelID: channelID, Embed: embed})
+}
+
+func (d *Discord) send(message DiscordMessage) (string, error) {
+	if message.ChannelID == "" {
+		return "", errors.New("channel ID is required")
+	}
+	message.ID = uuid.NewString()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.messages = append(d.messages, message)
+	return message.ID, nil
+}
+
+// ConnectorCall is a ConnectorAPI call made by a plugin.
+type ConnectorCall struct {
+	ConnectorID string
+	Request     *plugin_manager.ConnectorInvokeRequest
+}
+
+// Connectors routes ConnectorAPI calls to handlers registered by the test.
+// Calls to connectors without a handler fail as they do when the connector
+// is not running.
+type Connectors struct {
+	mu       sync.Mutex
+	handlers map[string]func(*plugin_manager.ConnectorInvokeRequest) (*plugin_manager.ConnectorInvokeResponse, error)
+	calls    []ConnectorCall
+}
+
+func newConnectors() *Connectors {
+	return &Connectors{handlers: make(map[string]func(*plugin_manager.ConnectorInvokeRequest) (*plugin_manager.ConnectorInvokeResponse, error))}
+}
+
+// Handle registers the handler for a connector's calls.
+func (c *Connectors) Handle(connectorID string, handler func(*plugin_manager.ConnectorInvokeRequest) (*plugin_manager.ConnectorInvokeResponse, error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[connectorID] = handler
+}
+
+// Calls returns the calls made, in order.
+func (c *Connectors) Calls() []ConnectorCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]ConnectorCall(nil), c.calls...)
+}
+
+func (c *Connectors) Call(ctx context.Context, connectorID string, req *plugin_manager.ConnectorInvokeRequest) (*plugin_manager.ConnectorInvokeResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.calls = append(c.calls, ConnectorCall{ConnectorID: connectorID, Request: req})
+	handler := c.handlers[connectorID]
+	c.mu.Unlock()
+	if handler == nil {
+		return nil, fmt.Errorf("connector %s is not available", connectorID)
+	}
+	return handler(req)
+}
+
+// LogEntry is a message a plugin logged.
+type LogEntry struct {
+	Level   string
+	Message string
+	Err     error
+	Fields  map[string]interface{}
+}
+
+// Log collects the plugin's log output and echoes it to the test log.
+type Log struct {
+	t testing.TB
+
+	mu      sync.Mutex
+	entries []LogEntry
+	done    bool
+}
+
+// newLog returns a Log that stops echoing once the test's cleanups have
+// run, since plugin goroutines may still log after Stop returns.
+func newLog(t testing.TB) *Log {
+	l := &Log{t: t}
+	t.Cleanup(func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.done = true
+	})
+	return l
+}
+
+// Entries returns the logged messages, in order.
+func (l *Log) Entries() []LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]LogEntry(nil), l.entries...)
+}
+
+// Errors returns the messages logged at error level.
+func (l *Log) Errors() []LogEntry {
+	var matched []LogEntry
+	for _, entry := range l.Entries() {
+		if entry.Level == "error" {
+			matched = append(matched, entry)
+		}
+	}
+	return matched
+}
+
+func (l *Log) Info(message string, fields map[string]interface{}) {
+	l.add(LogEntry{Level: "info", Message: message, Fields: fields})
+}
+
+func (l *Log) Warn(message string, fields map[string]interface{}) {
+	l.add(LogEntry{Level: "warn", Message: message, Fields: fields})
+}
+
+func (l *Log) Error(message string, err error, fields map[string]interface{}) {
+	l.add(LogEntry{Level: "error", Message: message, Err: err, Fields: fields})
+}
+
+func (l *Log) Debug(message string, fields map[string]interface{}) {
+	l.add(LogEntry{Level: "debug", Message: message, Fields: fields})
+}
+
+func (l *Log) add(entry LogEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+	if l.done {
+		return
+	}
+	if entry.Err != nil {
+		l.t.Logf("plugin %s: %s: %v %v", entry.Level, entry.Message, entry.Err, entry.Fields)
+		return
+	}
+	l.t.Logf("plugin %s: %s %v", entry.Level, entry.Message, entry.Fields)
+}
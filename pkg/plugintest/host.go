@@ -0,0 +1,207 @@
+// Package plugintest runs Squad Aegis plugins against an in-memory host in
+// unit tests.
+//
+// A Host fakes every host API: RCON calls are recorded, server, player and
+// squad state is set by the test, plugin storage is a map, and Discord
+// messages land in a sink. The same Host runs bundled plugins, pluginrpc
+// plugins in-process, and packaged bundles as real subprocesses or WASM
+// modules:
+//
+//	host := plugintest.NewHost(t)
+//	instance := host.RunRPC(&MyPlugin{}, map[string]interface{}{"trigger": "!hello"})
+//	if err := instance.Send(&event_manager.RconChatMessageData{EosID: eosID, Message: "!hello"}); err != nil {
+//	    t.Fatal(err)
+//	}
+//	if got := host.Rcon.Warnings(eosID); len(got) != 1 {
+//	    t.Fatalf("warnings = %v, want one", got)
+//	}
+package plugintest
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"go.codycody31.dev/squad-aegis/internal/plugin_manager"
+	"go.codycody31.dev/squad-aegis/pkg/pluginrpc"
+)
+
+// Host is an in-memory Aegis host for one server. Tests set up state on its
+// fakes before or while running a plugin, and assert on what the plugin did
+// through them afterwards.
+type Host struct {
+	t testing.TB
+
+	Server     *Server
+	Rcon       *Rcon
+	Storage    *Storage
+	Rules      *Rules
+	Admins     *Admins
+	Events     *Events
+	History    *History
+	Discord    *Discord
+	Connectors *Connectors
+	Log        *Log
+}
+
+// NewHost returns a host for a new server with no players.
+func NewHost(t testing.TB) *Host {
+	server := newServer(uuid.New())
+	return &Host{
+		t:          t,
+		Server:     server,
+		Rcon:       newRcon(server),
+		Storage:    newStorage(),
+		Rules:      newRules(),
+		Admins:     newAdmins(server),
+		Events:     newEvents(),
+		History:    newHistory(),
+		Discord:    newDiscord(),
+		Connectors: newConnectors(),
+		Log:        newLog(t),
+	}
+}
+
+// ServerID returns the ID of the host's server.
+func (h *Host) ServerID() uuid.UUID {
+	return h.Server.GetServerID()
+}
+
+// APIs returns the host APIs backed by the host's fakes.
+func (h *Host) APIs() *plugin_manager.PluginAPIs {
+	return &plugin_manager.PluginAPIs{
+		ServerAPI:    h.Server,
+		DatabaseAPI:  h.Storage,
+		RuleAPI:      h.Rules,
+		RconAPI:      h.Rcon,
+		AdminAPI:     h.Admins,
+		EventAPI:     h.Events,
+		HistoryAPI:   h.History,
+		DiscordAPI:   h.Discord,
+		ConnectorAPI: h.Connectors,
+		LogAPI:       h.Log,
+	}
+}
+
+// apisFor returns the APIs an instance of definition gets once an operator
+// has granted every capability it requests. Native plugins only get the
+// APIs for their capabilities.
+func (h *Host) apisFor(definition plugin_manager.PluginDefinition) *plugin_manager.PluginAPIs {
+	apis := h.APIs()
+	if definition.Source != plugin_manager.PluginSourceNative {
+		return apis
+	}
+	granted := make(map[string]bool, len(definition.RequiredCapabilities))
+	for _, capability := range definition.RequiredCapabilities {
+		granted[capability] = true
+	}
+	if !granted[plugin_manager.NativePluginCapabilityAPIServer] {
+		apis.ServerAPI = nil
+	}
+	if !granted[plugin_manager.NativePluginCapabilityAPIDatabase] {
+		apis.DatabaseAPI = nil
+	}
+	if !granted[plugin_manager.NativePluginCapabilityAPIRule] {
+		apis.RuleAPI = nil
+	}
+	if !granted[plugin_manager.NativePluginCapabilityAPIRCON] {
+		apis.RconAPI = nil
+	}
+	if !granted[plugin_manager.NativePluginCapabilityAPIAdmin] {
+		apis.AdminAPI = nil
+	}
+	if !granted[plugin_manager.NativePluginCapabilityAPIEvent] {
+		apis.EventAPI = nil
+	}
+	if !granted[plugin_manager.NativePluginCapabilityAPIHistory] {
+		apis.HistoryAPI = nil
+	}
+	if !granted[plugin_manager.NativePluginCapabilityAPIDiscord] {
+		apis.DiscordAPI = nil
+	}
+	if !granted[plugin_manager.NativePluginCapabilityAPIConnector] {
+		apis.ConnectorAPI = nil
+	}
+	if !granted[plugin_manager.NativePluginCapabilityAPILog] {
+		apis.LogAPI = nil
+	}
+	return apis
+}
+
+// Run initializes a bundled plugin with config and starts it if it is long
+// running. The instance is stopped when the test ends.
+func (h *Host) Run(plugin plugin_manager.Plugin, config map[string]interface{}) *Instance {
+	h.t.Helper()
+	return h.start(plugin.GetDefinition(), plugin, config)
+}
+
+// RunRPC runs a pluginrpc plugin in the test's process. Calls between the
+// host and the plugin are encoded as they are for a packaged plugin, and
+// the plugin's host API calls pass the same capability checks. The plugin
+// is given the listed capabilities, or every host capability when none are
+// listed.
+func (h *Host) RunRPC(impl pluginrpc.Plugin, config map[string]interface{}, capabilities ...string) *Instance {
+	h.t.Helper()
+	if len(capabilities) == 0 {
+		capabilities = plugin_manager.NativePluginHostCapabilities()
+	}
+	pluginID := impl.GetDefinition().PluginID
+	manifest := plugin_manager.PluginPackageManifest{
+		PluginID: pluginID,
+		Name:     pluginID,
+		Version:  "0.0.0",
+	}
+	target := plugin_manager.PluginPackageTarget{
+		MinHostAPIVersion:    plugin_manager.NativePluginHostAPIVersion,
+		RequiredCapabilities: capabilities,
+		TargetOS:             runtime.GOOS,
+		TargetArch:           runtime.GOARCH,
+	}
+	definition, err := plugin_manager.LoadInProcessNativePlugin(func() pluginrpc.Plugin { return impl }, manifest, target)
+	if err != nil {
+		h.t.Fatalf("plugintest: failed to load plugin %s: %v", pluginID, err)
+	}
+	return h.start(definition, definition.CreateInstance(), config)
+}
+
+// RunBundle runs a packaged plugin bundle (.zip) the way an installed one
+// runs: as a subprocess, or as a WASM module for WASM targets. The plugin is
+// granted every capability its manifest requests. The bundle's signature is
+// not checked.
+func (h *Host) RunBundle(bundlePath string, config map[string]interface{}) *Instance {
+	h.t.Helper()
+	runtimeDir := filepath.Join(h.t.TempDir(), "runtime")
+	definition, err := plugin_manager.LoadNativePluginBundle(bundlePath, runtimeDir)
+	if err != nil {
+		h.t.Fatalf("plugintest: failed to load plugin bundle %s: %v", bundlePath, err)
+	}
+	return h.start(definition, definition.CreateInstance(), config)
+}
+
+// start validates config like the plugin manager does, then initializes
+// and starts the plugin.
+func (h *Host) start(definition plugin_manager.PluginDefinition, plugin plugin_manager.Plugin, config map[string]interface{}) *Instance {
+	h.t.Helper()
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+	if err := definition.ConfigSchema.ValidateForCreation(config); err != nil {
+		h.t.Fatalf("plugintest: config validation failed for %s: %v", definition.ID, err)
+	}
+	config = definition.ConfigSchema.FillDefaults(config)
+
+	if err := plugin.Initialize(config, h.apisFor(definition)); err != nil {
+		h.t.Fatalf("plugintest: failed to initialize %s: %v", definition.ID, err)
+	}
+	instance := &Instance{host: h, plugin: plugin, definition: definition}
+	h.t.Cleanup(func() { _ = instance.Stop() })
+
+	if definition.LongRunning {
+		if err := plugin.Start(h.Events.context()); err != nil {
+			h.t.Fatalf("plugintest: failed to start %s: %v", definition.ID, err)
+		}
+	}
+	return instance
+}
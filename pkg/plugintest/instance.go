@@ -0,0 +1,117 @@
+package plugintest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
+	"go.codycody31.dev/squad-aegis/internal/plugin_manager"
+)
+
+// ErrNotSubscribed is returned by Send for events the plugin neither
+// declares nor subscribed to at runtime. The host would not deliver them.
+var ErrNotSubscribed = errors.New("plugin is not subscribed to the event")
+
+// Instance is a running plugin instance.
+type Instance struct {
+	host       *Host
+	plugin     plugin_manager.Plugin
+	definition plugin_manager.PluginDefinition
+
+	stopOnce sync.Once
+	stopErr  error
+}
+
+// Plugin returns the running plugin.
+func (i *Instance) Plugin() plugin_manager.Plugin {
+	return i.plugin
+}
+
+// Definition returns the plugin's definition as the host sees it. For
+// packaged plugins the identity comes from the manifest.
+func (i *Instance) Definition() plugin_manager.PluginDefinition {
+	return i.definition
+}
+
+// Send delivers a typed event from the host's server, as the host does
+// when the event is published, and returns the plugin's HandleEvent error.
+// Handlers registered with EventAPI.SubscribeToEvents also receive it.
+func (i *Instance) Send(data event_manager.EventData) error {
+	eventType := data.GetEventType()
+	return i.SendEvent(&plugin_manager.PluginEvent{
+		ID:        uuid.New(),
+		ServerID:  i.host.ServerID(),
+		Source:    plugin_manager.EventSourceForType(eventType),
+		Type:      string(eventType),
+		Data:      data,
+		Timestamp: time.Now(),
+	})
+}
+
+// SendType delivers an event by type name, for plugins that cannot import
+// the host's event types. Data is passed to in-process Go plugins as is and
+// JSON-encoded for pluginrpc plugins, so a map with the event's JSON field
+// names works for the latter.
+func (i *Instance) SendType(eventType string, data interface{}) error {
+	return i.SendEvent(&plugin_manager.PluginEvent{
+		ID:        uuid.New(),
+		ServerID:  i.host.ServerID(),
+		Source:    plugin_manager.EventSourceForType(event_manager.EventType(eventType)),
+		Type:      eventType,
+		Data:      data,
+		Timestamp: time.Now(),
+	})
+}
+
+// SendEvent delivers a prepared event.
+func (i *Instance) SendEvent(event *plugin_manager.PluginEvent) error {
+	i.host.Events.deliver(event)
+	if !i.handles(event_manager.EventType(event.Type)) {
+		return fmt.Errorf("%w: %s", ErrNotSubscribed, event.Type)
+	}
+	return i.plugin.HandleEvent(event)
+}
+
+// handles mirrors the host's routing: events in the definition, plus
+// runtime subscriptions added through EventAPI.
+func (i *Instance) handles(eventType event_manager.EventType) bool {
+	for _, declared := range i.definition.Events {
+		if declared == eventType || declared == event_manager.EventTypeAll {
+			return true
+		}
+	}
+	return i.host.Events.Subscribed(string(eventType))
+}
+
+// Execute runs one of the plugin's commands after validating params
+// against the command's parameter schema, as the host does.
+func (i *Instance) Execute(commandID string, params map[string]interface{}) (*plugin_manager.CommandResult, error) {
+	for _, command := range i.plugin.GetCommands() {
+		if command.ID != commandID {
+			continue
+		}
+		if params == nil {
+			params = map[string]interface{}{}
+		}
+		if err := command.Parameters.Validate(params); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		return i.plugin.ExecuteCommand(commandID, params)
+	}
+	return nil, fmt.Errorf("command %s not found", commandID)
+}
+
+// Stop stops the plugin and cancels the context passed to Start. Tests
+// rarely need it: instances stop when the test ends. Safe to call more
+// than once.
+func (i *Instance) Stop() error {
+	i.stopOnce.Do(func() {
+		i.stopErr = i.plugin.Stop()
+		i.host.Events.cancel()
+	})
+	return i.stopErr
+}
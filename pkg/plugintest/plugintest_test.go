@@ -0,0 +1,290 @@
+package plugintest_test
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.codycody31.dev/squad-aegis/internal/event_manager"
+	"go.codycody31.dev/squad-aegis/internal/plugin_manager"
+	"go.codycody31.dev/squad-aegis/pkg/pluginrpc"
+	"go.codycody31.dev/squad-aegis/pkg/plugintest"
+)
+
+const counterPlayerID = "76561198000000001"
+
+// counterPlugin counts "!count" chat messages per player in plugin storage
+// and warns the player with their count.
+type counterPlugin struct {
+	mu   sync.Mutex
+	apis *pluginrpc.HostAPIs
+}
+
+func (p *counterPlugin) GetDefinition() pluginrpc.PluginDefinition {
+	return pluginrpc.PluginDefinition{
+		PluginID: "com.example.counter",
+		ConfigSchema: pluginrpc.ConfigSchema{
+			Fields: []pluginrpc.ConfigField{
+				{Name: "trigger", Type: pluginrpc.FieldTypeString, Default: "!count"},
+			},
+		},
+		Events: []string{string(event_manager.EventTypeRconChatMessage)},
+	}
+}
+
+func (p *counterPlugin) Initialize(config map[string]interface{}, apis *pluginrpc.HostAPIs) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.apis = apis
+	return nil
+}
+
+func (p *counterPlugin) Start(context.Context) error { return nil }
+func (p *counterPlugin) Stop() error                 { return nil }
+
+func (p *counterPlugin) HandleEvent(event *pluginrpc.PluginEvent) error {
+	var chat event_manager.RconChatMessageData
+	if err := json.Unmarshal(event.Data, &chat); err != nil {
+		return err
+	}
+	if chat.Message != "!count" {
+		return nil
+	}
+	count, err := p.increment(chat.SteamID)
+	if err != nil {
+		return err
+	}
+	return p.apis.RconAPI.SendWarningToPlayer(chat.SteamID, fmt.Sprintf("count: %d", count))
+}
+
+func (p *counterPlugin) increment(playerID string) (int, error) {
+	key := "count:" + playerID
+	count := 0
+	if value, err := p.apis.DatabaseAPI.GetPluginData(key); err == nil {
+		count, _ = strconv.Atoi(value)
+	}
+	count++
+	return count, p.apis.DatabaseAPI.SetPluginData(key, strconv.Itoa(count))
+}
+
+func (p *counterPlugin) GetStatus() pluginrpc.PluginStatus         { return pluginrpc.PluginStatusRunning }
+func (p *counterPlugin) GetConfig() map[string]interface{}         { return map[string]interface{}{} }
+func (p *counterPlugin) UpdateConfig(map[string]interface{}) error { return nil }
+
+func (p *counterPlugin) GetCommands() []pluginrpc.PluginCommand {
+	return []pluginrpc.PluginCommand{{
+		ID:   "reset",
+		Name: "Reset",
+		Parameters: pluginrpc.ConfigSchema{
+			Fields: []pluginrpc.ConfigField{
+				{Name: "player_id", Type: pluginrpc.FieldTypeString, Required: true},
+			},
+		},
+		ExecutionType: pluginrpc.CommandExecutionSync,
+	}}
+}
+
+func (p *counterPlugin) ExecuteCommand(commandID string, params map[string]interface{}) (*pluginrpc.CommandResult, error) {
+	if err := p.apis.DatabaseAPI.DeletePluginData("count:" + fmt.Sprint(params["player_id"])); err != nil {
+		return nil, err
+	}
+	return &pluginrpc.CommandResult{Success: true}, nil
+}
+
+func (p *counterPlugin) GetCommandExecutionStatus(string) (*pluginrpc.CommandExecutionStatus, error) {
+	return nil, errors.New("no async commands")
+}
+
+func TestRunRPCDeliversTypedEventsAndRecordsCommands(t *testing.T) {
+	host := plugintest.NewHost(t)
+	instance := host.RunRPC(&counterPlugin{}, nil)
+
+	chat := &event_manager.RconChatMessageData{SteamID: counterPlayerID, Message: "!count"}
+	for range 2 {
+		if err := instance.Send(chat); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	if got, want := host.Rcon.Warnings(counterPlayerID), []string{"count: 1", "count: 2"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("warnings = %q, want %q", got, want)
+	}
+	if value, _ := host.Storage.Get("count:" + counterPlayerID); value != "2" {
+		t.Fatalf("stored count = %q, want 2", value)
+	}
+
+	if _, err := instance.Execute("reset", map[string]interface{}{}); err == nil {
+		t.Fatal("Execute() without a required parameter error = nil, want error")
+	}
+	if _, err := instance.Execute("reset", map[string]interface{}{"player_id": counterPlayerID}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if _, ok := host.Storage.Get("count:" + counterPlayerID); ok {
+		t.Fatal("count still stored after reset")
+	}
+
+	err := instance.Send(&event_manager.LogPlayerConnectedData{SteamID: counterPlayerID})
+	if !errors.Is(err, plugintest.ErrNotSubscribed) {
+		t.Fatalf("Send() of an undeclared event error = %v, want ErrNotSubscribed", err)
+	}
+}
+
+func TestRunRPCEnforcesCapabilities(t *testing.T) {
+	host := plugintest.NewHost(t)
+	instance := host.RunRPC(&counterPlugin{}, nil,
+		plugin_manager.NativePluginCapabilityAPIDatabase,
+		plugin_manager.NativePluginCapabilityEventsRCON,
+	)
+
+	err := instance.Send(&event_manager.RconChatMessageData{SteamID: counterPlayerID, Message: "!count"})
+	if err == nil || !strings.Contains(err.Error(), "rcon api is unavailable") {
+		t.Fatalf("Send() error = %v, want rcon api unavailable", err)
+	}
+	if commands := host.Rcon.Commands(); len(commands) != 0 {
+		t.Fatalf("RCON commands = %v, want none", commands)
+	}
+}
+
+func TestRconKickAndSquadRemovalUpdateServerState(t *testing.T) {
+	host := plugintest.NewHost(t)
+	host.Server.AddSquad(plugin_manager.SquadInfo{ID: 1, TeamID: 1, Name: "Alpha"})
+	host.Server.AddPlayer(plugin_manager.PlayerInfo{SteamID: counterPlayerID, TeamID: 1, SquadID: 1, IsSquadLeader: true})
+	host.Server.AddPlayer(plugin_manager.PlayerInfo{SteamID: "76561198000000002", TeamID: 1, SquadID: 1})
+
+	if err := host.Rcon.RemovePlayerFromSquad(counterPlayerID); err != nil {
+		t.Fatalf("RemovePlayerFromSquad() error = %v", err)
+	}
+	squads, _ := host.Server.GetSquads()
+	if len(squads) != 1 || squads[0].Size != 1 || squads[0].Leader != nil {
+		t.Fatalf("squads after removal = %+v, want one member and no leader", squads[0])
+	}
+
+	if err := host.Rcon.KickPlayer(counterPlayerID, "afk"); err != nil {
+		t.Fatalf("KickPlayer() error = %v", err)
+	}
+	if _, ok := host.Server.Player(counterPlayerID); ok {
+		t.Fatal("kicked player is still on the server")
+	}
+	kicks := host.Rcon.CommandsOf(plugintest.CommandKick)
+	if len(kicks) != 1 || kicks[0].Message != "afk" {
+		t.Fatalf("kicks = %+v, want one with reason afk", kicks)
+	}
+}
+
+func TestRunBundleRunsSubprocess(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the example plugin")
+	}
+	if runtime.GOOS != "linux" {
+		t.Skip("subprocess plugins require linux")
+	}
+	bundle := buildHelloBundle(t)
+
+	host := plugintest.NewHost(t)
+	host.Connectors.Handle("com.squad-aegis.connectors.examples.hello", func(*plugin_manager.ConnectorInvokeRequest) (*plugin_manager.ConnectorInvokeResponse, error) {
+		return &plugin_manager.ConnectorInvokeResponse{V: "1", OK: true, Data: map[string]interface{}{"message": "pong"}}, nil
+	})
+	instance := host.RunBundle(bundle, map[string]interface{}{"response": "hi"})
+
+	err := instance.SendType("RCON_CHAT_MESSAGE", map[string]interface{}{"steam_id": counterPlayerID, "message": "!hello"})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got := host.Rcon.Warnings(counterPlayerID); len(got) != 1 || got[0] != "hi (pong)" {
+		t.Fatalf("warnings = %q, want [\"hi (pong)\"]", got)
+	}
+}
+
+// buildHelloBundle builds examples/native-plugin-hello and packages it as
+// an unsigned bundle.
+func buildHelloBundle(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "hello-plugin")
+	cmd := exec.Command("go", "build", "-o", binary, "./examples/native-plugin-hello")
+	cmd.Dir = filepath.Join("..", "..")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	library, err := os.ReadFile(binary)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := json.Marshal(plugin_manager.PluginPackageManifest{
+		PluginID: "com.squad-aegis.plugins.examples.hello",
+		Name:     "Hello Example",
+		Version:  "0.1.0",
+		Targets: []plugin_manager.PluginPackageTarget{{
+			MinHostAPIVersion: plugin_manager.NativePluginHostAPIVersion,
+			RequiredCapabilities: []string{
+				plugin_manager.NativePluginCapabilityAPIRCON,
+				plugin_manager.NativePluginCapabilityAPIConnector,
+				plugin_manager.NativePluginCapabilityEventsRCON,
+			},
+			TargetOS:    runtime.GOOS,
+			TargetArch:  runtime.GOARCH,
+			SHA256:      fmt.Sprintf("%x", sha256.Sum256(library)),
+			LibraryPath: "bin/hello-plugin",
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bundlePath := filepath.Join(dir, "hello.zip")
+	file, err := os.Create(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	archive := zip.NewWriter(file)
+	for name, data := range map[string][]byte{"manifest.json": manifest, "bin/hello-plugin": library} {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bundlePath
+}
+
+func TestWaitForTick(t *testing.T) {
+	var mu sync.Mutex
+	ctx, cancel := context.WithCancel(context.Background())
+	ticker := time.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+
+	if !plugintest.WaitForTick(&mu, &ctx, &ticker) {
+		t.Fatal("WaitForTick() = false, want true while the ticker runs")
+	}
+
+	cancel()
+	idle := time.NewTicker(time.Hour)
+	defer idle.Stop()
+	if plugintest.WaitForTick(&mu, &ctx, &idle) {
+		t.Fatal("WaitForTick() = true after the context was cancelled")
+	}
+
+	var cleared *time.Ticker
+	if plugintest.WaitForTick(&mu, &ctx, &cleared) {
+		t.Fatal("WaitForTick() = true for a cleared ticker")
+	}
+}
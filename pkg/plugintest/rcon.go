@@ -0,0 +1,218 @@
+package plugintest
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CommandKind identifies the RconAPI call a Command records.
+type CommandKind string
+
+const (
+	CommandRaw             CommandKind = "raw"
+	CommandBroadcast       CommandKind = "broadcast"
+	CommandWarn            CommandKind = "warn"
+	CommandKick            CommandKind = "kick"
+	CommandBan             CommandKind = "ban"
+	CommandRemoveFromSquad CommandKind = "remove_from_squad"
+)
+
+// Command is one RconAPI call made by a plugin. Calls that attach a rule or
+// evidence record them alongside the command.
+type Command struct {
+	Kind      CommandKind
+	PlayerID  string
+	Message   string // raw command, broadcast, warning, or kick/ban reason
+	Duration  time.Duration
+	RuleID    string
+	EventID   string
+	EventType string
+	Metadata  map[string]interface{}
+	BanID     string
+}
+
+// Rcon records the commands a plugin sends. Kicks take the player off the
+// fake server and squad removals clear their squad.
+type Rcon struct {
+	server *Server
+
+	mu        sync.Mutex
+	commands  []Command
+	responses map[string]string
+	err       error
+}
+
+func newRcon(server *Server) *Rcon {
+	return &Rcon{server: server, responses: make(map[string]string)}
+}
+
+// Respond sets the response SendCommand returns for a raw command.
+func (r *Rcon) Respond(command, response string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responses[command] = response
+}
+
+// FailWith makes every following call fail with err, or succeed again
+// when err is nil. Failed calls are not recorded.
+func (r *Rcon) FailWith(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = err
+}
+
+// Commands returns every recorded command in the order it was sent.
+func (r *Rcon) Commands() []Command {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Command(nil), r.commands...)
+}
+
+// CommandsOf returns the recorded commands of one kind.
+func (r *Rcon) CommandsOf(kind CommandKind) []Command {
+	var matched []Command
+	for _, command := range r.Commands() {
+		if command.Kind == kind {
+			matched = append(matched, command)
+		}
+	}
+	return matched
+}
+
+// Broadcasts returns the broadcast messages.
+func (r *Rcon) Broadcasts() []string {
+	var messages []string
+	for _, command := range r.CommandsOf(CommandBroadcast) {
+		messages = append(messages, command.Message)
+	}
+	return messages
+}
+
+// Warnings returns the warnings sent to a player.
+func (r *Rcon) Warnings(playerID string) []string {
+	var messages []string
+	for _, command := range r.CommandsOf(CommandWarn) {
+		if command.PlayerID == playerID {
+			messages = append(messages, command.Message)
+		}
+	}
+	return messages
+}
+
+// Reset forgets the recorded commands.
+func (r *Rcon) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commands = nil
+}
+
+func (r *Rcon) record(command Command) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.commands = append(r.commands, command)
+	return nil
+}
+
+func (r *Rcon) SendCommand(command string) (string, error) {
+	if err := r.record(Command{Kind: CommandRaw, Message: command}); err != nil {
+		return "", err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.responses[command], nil
+}
+
+func (r *Rcon) Broadcast(message string) error {
+	return r.record(Command{Kind: CommandBroadcast, Message: message})
+}
+
+func (r *Rcon) SendWarningToPlayer(playerID string, message string) error {
+	return r.record(Command{Kind: CommandWarn, PlayerID: playerID, Message: message})
+}
+
+func (r *Rcon) KickPlayer(playerID string, reason string) error {
+	return r.KickPlayerWithRule(playerID, reason, nil)
+}
+
+func (r *Rcon) BanPlayer(playerID string, reason string, duration time.Duration) error {
+	return r.BanPlayerWithRule(playerID, reason, duration, nil)
+}
+
+func (r *Rcon) BanWithEvidence(playerID string, reason string, duration time.Duration, eventID string, eventType string) (string, error) {
+	return r.BanWithEvidenceAndRuleAndMetadata(playerID, reason, duration, eventID, eventType, nil, nil)
+}
+
+func (r *Rcon) WarnPlayerWithRule(playerID string, message string, ruleID *string) error {
+	return r.record(Command{Kind: CommandWarn, PlayerID: playerID, Message: message, RuleID: deref(ruleID)})
+}
+
+func (r *Rcon) KickPlayerWithRule(playerID string, reason string, ruleID *string) error {
+	if err := r.record(Command{Kind: CommandKick, PlayerID: playerID, Message: reason, RuleID: deref(ruleID)}); err != nil {
+		return err
+	}
+	r.server.RemovePlayer(playerID)
+	return nil
+}
+
+func (r *Rcon) BanPlayerWithRule(playerID string, reason string, duration time.Duration, ruleID *string) error {
+	_, err := r.ban(Command{Kind: CommandBan, PlayerID: playerID, Message: reason, Duration: duration, RuleID: deref(ruleID)})
+	return err
+}
+
+func (r *Rcon) BanWithEvidenceAndRule(playerID string, reason string, duration time.Duration, eventID string, eventType string, ruleID *string) (string, error) {
+	return r.BanWithEvidenceAndRuleAndMetadata(playerID, reason, duration, eventID, eventType, ruleID, nil)
+}
+
+func (r *Rcon) BanWithEvidenceAndRuleAndMetadata(playerID string, reason string, duration time.Duration, eventID string, eventType string, ruleID *string, metadata map[string]interface{}) (string, error) {
+	return r.ban(Command{
+		Kind:      CommandBan,
+		PlayerID:  playerID,
+		Message:   reason,
+		Duration:  duration,
+		RuleID:    deref(ruleID),
+		EventID:   eventID,
+		EventType: eventType,
+		Metadata:  metadata,
+	})
+}
+
+func (r *Rcon) RemovePlayerFromSquad(playerID string) error {
+	return r.removeFromSquad(playerID)
+}
+
+func (r *Rcon) RemovePlayerFromSquadById(playerID string) error {
+	return r.removeFromSquad(playerID)
+}
+
+func (r *Rcon) removeFromSquad(playerID string) error {
+	if _, ok := r.server.Player(playerID); !ok {
+		return fmt.Errorf("player %s not found", playerID)
+	}
+	if err := r.record(Command{Kind: CommandRemoveFromSquad, PlayerID: playerID}); err != nil {
+		return err
+	}
+	return r.server.removeFromSquad(playerID)
+}
+
+// ban records a ban and takes the player off the server.
+func (r *Rcon) ban(command Command) (string, error) {
+	command.BanID = uuid.NewString()
+	if err := r.record(command); err != nil {
+		return "", err
+	}
+	r.server.RemovePlayer(command.PlayerID)
+	return command.BanID, nil
+}
+
+func deref(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,193 @@
+package plugintest
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"go.codycody31.dev/squad-aegis/internal/plugin_manager"
+)
+
+// Server is the fake game server state behind ServerAPI. Players, squads
+// and admins are set by the test; RCON kicks and squad removals update it
+// as the game would.
+type Server struct {
+	mu      sync.Mutex
+	info    plugin_manager.ServerInfo
+	players []*plugin_manager.PlayerInfo
+	squads  []*plugin_manager.SquadInfo
+	admins  []*plugin_manager.AdminInfo
+}
+
+func newServer(id uuid.UUID) *Server {
+	return &Server{
+		info: plugin_manager.ServerInfo{
+			ID:         id,
+			Name:       "Test Server",
+			Host:       "127.0.0.1",
+			Port:       7787,
+			MaxPlayers: 100,
+			Status:     "online",
+		},
+	}
+}
+
+// SetInfo replaces the server info. The ID is kept and the player count
+// always reflects the players on the server.
+func (s *Server) SetInfo(info plugin_manager.ServerInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info.ID = s.info.ID
+	s.info = info
+}
+
+// AddPlayer puts a player on the server, replacing any player with the
+// same ID.
+func (s *Server) AddPlayer(player plugin_manager.PlayerInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if player.ID == "" {
+		player.ID = player.PreferredID()
+	}
+	player.IsOnline = true
+	for i, existing := range s.players {
+		if existing.ID == player.ID {
+			s.players[i] = &player
+			return
+		}
+	}
+	s.players = append(s.players, &player)
+}
+
+// RemovePlayer takes a player off the server.
+func (s *Server) RemovePlayer(playerID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, player := range s.players {
+		if player.ID == playerID || player.MatchesPlayerID(playerID) {
+			s.players = append(s.players[:i], s.players[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Player returns a copy of a player on the server.
+func (s *Server) Player(playerID string) (plugin_manager.PlayerInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if player := s.findPlayerLocked(playerID); player != nil {
+		return *player, true
+	}
+	return plugin_manager.PlayerInfo{}, false
+}
+
+// AddSquad creates a squad. Its players, size and leader are filled in
+// from the players on the server.
+func (s *Server) AddSquad(squad plugin_manager.SquadInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	squad.Players = nil
+	squad.Leader = nil
+	for i, existing := range s.squads {
+		if existing.ID == squad.ID && existing.TeamID == squad.TeamID {
+			s.squads[i] = &squad
+			return
+		}
+	}
+	s.squads = append(s.squads, &squad)
+}
+
+// SetAdmins replaces the admins on the server's admin list.
+func (s *Server) SetAdmins(admins ...plugin_manager.AdminInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.admins = s.admins[:0]
+	for i := range admins {
+		admin := admins[i]
+		s.admins = append(s.admins, &admin)
+	}
+}
+
+// removeFromSquad clears a player's squad, as RemovePlayerFromSquad does.
+func (s *Server) removeFromSquad(playerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	player := s.findPlayerLocked(playerID)
+	if player == nil {
+		return fmt.Errorf("player %s not found", playerID)
+	}
+	player.SquadID = 0
+	player.IsSquadLeader = false
+	return nil
+}
+
+func (s *Server) findPlayerLocked(playerID string) *plugin_manager.PlayerInfo {
+	for _, player := range s.players {
+		if player.ID == playerID || player.MatchesPlayerID(playerID) {
+			return player
+		}
+	}
+	return nil
+}
+
+func (s *Server) GetServerID() uuid.UUID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.info.ID
+}
+
+func (s *Server) GetServerInfo() (*plugin_manager.ServerInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info := s.info
+	info.PlayerCount = len(s.players)
+	return &info, nil
+}
+
+func (s *Server) GetPlayers() ([]*plugin_manager.PlayerInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	players := make([]*plugin_manager.PlayerInfo, 0, len(s.players))
+	for _, player := range s.players {
+		copied := *player
+		players = append(players, &copied)
+	}
+	return players, nil
+}
+
+func (s *Server) GetAdmins() ([]*plugin_manager.AdminInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	admins := make([]*plugin_manager.AdminInfo, 0, len(s.admins))
+	for _, admin := range s.admins {
+		copied := *admin
+		copied.IsOnline = s.findPlayerLocked(admin.PreferredID()) != nil
+		admins = append(admins, &copied)
+	}
+	return admins, nil
+}
+
+func (s *Server) GetSquads() ([]*plugin_manager.SquadInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	squads := make([]*plugin_manager.SquadInfo, 0, len(s.squads))
+	for _, squad := range s.squads {
+		copied := *squad
+		copied.Players = nil
+		for _, player := range s.players {
+			if player.TeamID != squad.TeamID || player.SquadID != squad.ID {
+				continue
+			}
+			member := *player
+			copied.Players = append(copied.Players, &member)
+			if member.IsSquadLeader {
+				copied.Leader = &member
+			}
+		}
+		copied.Size = len(copied.Players)
+		squads = append(squads, &copied)
+	}
+	return squads, nil
+}
@@ -0,0 +1,28 @@
+package plugintest
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WaitForTick blocks until *ticker fires and reports false once *ctx is done
+// or the ticker has been cleared. Plugin loops call it so that tests can stop
+// them by cancelling the context or swapping the ticker; both are read under
+// mu because Stop and UpdateConfig replace them.
+func WaitForTick(mu sync.Locker, ctx *context.Context, ticker **time.Ticker) bool {
+	mu.Lock()
+	current := *ticker
+	done := (*ctx).Done()
+	mu.Unlock()
+	if current == nil {
+		return false
+	}
+
+	select {
+	case <-done:
+		return false
+	case <-current.C:
+		return true
+	}
+}
@@ -0,0 +1,23 @@
+package plugintest
+
+import "go.codycody31.dev/squad-aegis/internal/plugin_manager"
+
+// Aliases for the host types the fakes take and return, so plugins
+// outside this module can build them.
+type (
+	ServerInfo              = plugin_manager.ServerInfo
+	PlayerInfo              = plugin_manager.PlayerInfo
+	SquadInfo               = plugin_manager.SquadInfo
+	AdminInfo               = plugin_manager.AdminInfo
+	PlayerAdminRole         = plugin_manager.PlayerAdminRole
+	TemporaryAdminInfo      = plugin_manager.TemporaryAdminInfo
+	RuleInfo                = plugin_manager.RuleInfo
+	RuleActionInfo          = plugin_manager.RuleActionInfo
+	HistoryChatMessage      = plugin_manager.HistoryChatMessage
+	HistoryPlayerDeath      = plugin_manager.HistoryPlayerDeath
+	DiscordEmbed            = plugin_manager.DiscordEmbed
+	ConnectorInvokeRequest  = plugin_manager.ConnectorInvokeRequest
+	ConnectorInvokeResponse = plugin_manager.ConnectorInvokeResponse
+	PluginEvent             = plugin_manager.PluginEvent
+	CommandResult           = plugin_manager.CommandResult
+)
@@ -5,12 +5,13 @@ set -euo pipefail
 ROOT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)"
 CONNECTOR_ID="${CONNECTOR_ID:-com.squad-aegis.connectors.examples.hello}"
 CONNECTOR_NAME="${CONNECTOR_NAME:-Hello connector example}"
-CONNECTOR_DESCRIPTION="${CONNECTOR_DESCRIPTION:-Responds to JSON invoke action ping.}"
+CONNECTOR_DESCRIPTION="${CONNECTOR_DESCRIPTION:-Responds to JSON invoke action ping and publishes hello events.}"
 CONNECTOR_VERSION="${CONNECTOR_VERSION:-0.1.0}"
 CONNECTOR_AUTHOR="${CONNECTOR_AUTHOR:-Squad Aegis}"
 MIN_HOST_API_VERSION="${MIN_HOST_API_VERSION:-1}"
-# Native connectors may use an empty capability set per target if none are needed.
-REQUIRED_CAPABILITIES="${REQUIRED_CAPABILITIES:-}"
+# The example logs and publishes events through the connector host API.
+# Connectors that need no host APIs may use an empty capability set.
+REQUIRED_CAPABILITIES="${REQUIRED_CAPABILITIES:-api.log,api.event}"
 TARGETS="${TARGETS:-linux/$(go env GOARCH)}"
 OUTPUT_DIR="${OUTPUT_DIR:-$ROOT_DIR/dist/native-connector-hello}"
 LIBRARY_NAME="${LIBRARY_NAME:-hello-connector}"
@@ -0,0 +1,19 @@
+#!/usr/bin/env bash
+
+set -euo pipefail
+
+ROOT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)"
+CATALOG_DIR="${CATALOG_DIR:-$ROOT_DIR/dist/plugin-catalog}"
+PRIVATE_KEY_FILE="${PRIVATE_KEY_FILE:-$ROOT_DIR/dist/plugin-signing/private-key.b64}"
+KEY_ID="${KEY_ID:-}"
+
+if [[ -z "${KEY_ID}" ]]; then
+  echo "KEY_ID is required" >&2
+  exit 1
+fi
+
+cd "${ROOT_DIR}"
+env GOCACHE=/tmp/go-build-cache go run ./scripts/sign_plugin_catalog \
+  -catalog-dir "${CATALOG_DIR}" \
+  -private-key "${PRIVATE_KEY_FILE}" \
+  -key-id "${KEY_ID}"
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.codycody31.dev/squad-aegis/internal/plugin_signing"
+)
+
+func main() {
+	catalogDir := flag.String("catalog-dir", "", "directory containing the catalog index.json")
+	privateKeyPath := flag.String("private-key", "", "path to the base64-encoded ed25519 private key file")
+	keyID := flag.String("key-id", "", "operator-chosen identifier for the signing key (e.g. ops-key-2026-q1); recorded in the signed payload and matched against the host CRL")
+	validFor := flag.Duration("valid-for", 30*24*time.Hour, "how long the signature should be valid (Go duration format, e.g. 720h)")
+	flag.Parse()
+
+	if err := run(*catalogDir, *privateKeyPath, *keyID, *validFor); err != nil {
+		fmt.Fprintf(os.Stderr, "sign plugin catalog: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(catalogDir, privateKeyPath, keyID string, validFor time.Duration) error {
+	if strings.TrimSpace(catalogDir) == "" {
+		return fmt.Errorf("catalog-dir is required")
+	}
+	if strings.TrimSpace(privateKeyPath) == "" {
+		return fmt.Errorf("private-key is required")
+	}
+	if strings.TrimSpace(keyID) == "" {
+		return fmt.Errorf("key-id is required")
+	}
+	if validFor <= 0 {
+		return fmt.Errorf("valid-for must be positive")
+	}
+
+	indexBytes, err := os.ReadFile(filepath.Join(catalogDir, plugin_signing.CatalogIndexFile))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", plugin_signing.CatalogIndexFile, err)
+	}
+
+	privateKeyBytes, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return fmt.Errorf("failed to read private key: %w", err)
+	}
+	privateKeyRaw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(privateKeyBytes)))
+	if err != nil {
+		return fmt.Errorf("failed to decode private key: %w", err)
+	}
+	privateKey := ed25519.PrivateKey(privateKeyRaw)
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return fmt.Errorf("private key has %d bytes, want %d", len(privateKey), ed25519.PrivateKeySize)
+	}
+
+	signedAt := time.Now().UTC()
+	expiresAt := signedAt.Add(validFor)
+
+	signedPayload, err := plugin_signing.BuildSignedPayload(indexBytes, keyID, signedAt, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to build signed payload: %w", err)
+	}
+	signatureFile, publicKeyFile, err := plugin_signing.SignSignedPayload(signedPayload, privateKey)
+	if err != nil {
+		return err
+	}
+
+	for name, data := range map[string][]byte{
+		plugin_signing.CatalogSignedPayloadFile: signedPayload,
+		plugin_signing.CatalogSignatureFile:     signatureFile,
+		plugin_signing.CatalogPublicKeyFile:     publicKeyFile,
+	} {
+		if err := os.WriteFile(filepath.Join(catalogDir, name), data, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	fmt.Printf("Signed catalog index: %s\n", filepath.Join(catalogDir, plugin_signing.CatalogIndexFile))
+	fmt.Printf("Key ID: %s\n", keyID)
+	fmt.Printf("Signed at: %s\n", signedAt.Format(time.RFC3339))
+	fmt.Printf("Expires at: %s\n", expiresAt.Format(time.RFC3339))
+
+	return nil
+}
@@ -90,6 +90,14 @@ export default [
     },
     permissions: [UI_PERMISSIONS.MOTD_VIEW],
   },
+  {
+    title: "Config Files",
+    icon: "mdi:file-cog",
+    to: {
+      name: "servers-serverId-config-files",
+    },
+    permissions: [UI_PERMISSIONS.SETTINGS_MANAGE],
+  },
   {
     title: "Plugins",
     icon: "lucide:puzzle",
@@ -184,6 +184,7 @@ export interface SystemConfig {
     allow_unsafe_sideload?: boolean;
     max_upload_size?: number;
     trusted_signing_keys_set?: boolean;
+    catalogs_configured?: boolean;
   };
 }
 
@@ -289,7 +290,7 @@ export interface PluginPackage {
   description: string;
   version: string;
   source: "bundled" | "native";
-  distribution: "bundled" | "sideload";
+  distribution: "bundled" | "sideload" | "catalog";
   official: boolean;
   install_state: "ready" | "not_installed" | "pending_restart" | "error";
   runtime_path?: string;
@@ -309,6 +310,48 @@ export interface PluginPackage {
   updated_at?: string;
 }
 
+export interface PluginCatalogDownload {
+  target_os: string;
+  target_arch: string;
+  url: string;
+  sha256: string;
+  size?: number;
+}
+
+export interface PluginCatalogVersion {
+  version: string;
+  min_host_api_version?: number;
+  released_at?: string;
+  changelog?: string;
+  downloads: PluginCatalogDownload[];
+}
+
+export interface PluginCatalogListing {
+  plugin_id: string;
+  name: string;
+  description?: string;
+  author?: string;
+  homepage?: string;
+  catalog_url: string;
+  catalog_name?: string;
+  compatible: boolean;
+  latest_version?: string;
+  versions: PluginCatalogVersion[];
+  installed_version?: string;
+  install_state?: PluginPackage["install_state"];
+  update_available: boolean;
+}
+
+export interface PluginCatalogStatus {
+  url: string;
+  name?: string;
+  key_id?: string;
+  plugin_count: number;
+  fetched_at?: string;
+  expires_at?: string;
+  last_error?: string;
+}
+
 // Plugin & Connector Types
 
 export interface ConfigSchemaField {
@@ -328,6 +371,9 @@ export interface ConfigSchemaField {
 export interface PluginInstance {
   id: string;
   server_id: string;
+  // Set on global instances, which span these servers instead of server_id
+  server_ids?: string[];
+  global?: boolean;
   plugin_id: string;
   plugin_name: string;
   source?: string;
@@ -337,14 +383,33 @@ export interface PluginInstance {
   min_host_api_version?: number;
   notes: string;
   config: Record<string, any>;
+  config_schema_version?: number;
+  config_migration?: PluginConfigMigration;
   status: string;
   enabled: boolean;
   log_level: string;
+  rcon_policy?: PluginRconPolicy;
+  capability_grants?: Record<string, boolean>;
+  pending_capabilities?: string[];
   last_error?: string;
   created_at: string;
   updated_at: string;
 }
 
+export interface PluginConfigMigration {
+  status: "migrated" | "restored" | "failed";
+  from_version: number;
+  to_version: number;
+  error?: string;
+  backup_available: boolean;
+  at: string;
+}
+
+export interface PluginRconPolicy {
+  mode?: "" | "allow" | "deny";
+  commands?: string[];
+}
+
 export interface PluginDefinition {
   id: string;
   name: string;
@@ -357,9 +422,44 @@ export interface PluginDefinition {
   config_schema?: { fields?: ConfigSchemaField[] };
   event_handlers?: string[];
   allow_multiple_instances?: boolean;
+  allow_global_instances?: boolean;
   long_running?: boolean;
   required_connectors?: string[];
   optional_connectors?: string[];
+  required_capabilities?: string[];
+  http_routes?: PluginHTTPRoute[];
+  panels?: PluginPanel[];
+}
+
+export interface PluginHTTPRoute {
+  method: string;
+  path: string;
+  access: "view" | "manage";
+  description?: string;
+}
+
+export interface PluginPanelColumn {
+  key: string;
+  label: string;
+  format?: "text" | "number" | "datetime" | "duration";
+}
+
+export interface PluginPanel {
+  id: string;
+  title: string;
+  description?: string;
+  type: "table" | "chart" | "stats" | "form";
+  route: string;
+  columns?: PluginPanelColumn[];
+  chart?: {
+    kind: "line" | "bar";
+    x_key: string;
+    series: { key: string; label: string }[];
+  };
+  form?: { fields?: ConfigSchemaField[] };
+  submit_label?: string;
+  refresh_seconds?: number;
+  access?: "view" | "manage";
 }
 
 export interface ConnectorInstance {
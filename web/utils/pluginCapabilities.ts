@@ -0,0 +1,34 @@
+import type { PluginDefinition } from "~/types";
+
+// Needed to load any native plugin, so it is never offered for review
+const ENTRYPOINT_CAPABILITY = "entrypoint.get_aegis_plugin";
+
+export const PLUGIN_CAPABILITY_DESCRIPTIONS: Record<string, string> = {
+  "api.rcon": "Send RCON commands, warn, kick and ban players",
+  "api.server": "Read server info, players and squads",
+  "api.database": "Store its own plugin data",
+  "api.rule": "Read the server rules",
+  "api.admin": "Add and remove temporary admins",
+  "api.discord": "Send Discord messages through the Discord connector",
+  "api.connector": "Call the connectors it declares",
+  "api.event": "Publish and subscribe to events",
+  "api.log": "Write plugin logs",
+  "api.history": "Read recorded chat, kills and round history",
+  "events.rcon": "Receive RCON events such as chat messages",
+  "events.log": "Receive game log events",
+  "events.system": "Receive player, squad and system events",
+  "events.connector": "Receive connector events",
+  "events.plugin": "Receive events published by plugins",
+};
+
+// The capabilities of a native plugin an operator grants or denies
+export function requestedPluginCapabilities(
+  definition?: PluginDefinition | null,
+): string[] {
+  if (!definition || definition.source !== "native") {
+    return [];
+  }
+  return [...new Set(definition.required_capabilities || [])]
+    .filter((capability) => capability && capability !== ENTRYPOINT_CAPABILITY)
+    .sort();
+}